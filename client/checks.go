@@ -0,0 +1,42 @@
+package chclient
+
+import (
+	"context"
+
+	"github.com/cloudradar-monitoring/rport/client/monitoring/checks"
+	"github.com/cloudradar-monitoring/rport/client/system"
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+)
+
+// ExecuteCheck runs the command of a custom monitoring check the same way as remote scripts are run.
+func (c *Client) ExecuteCheck(ctx context.Context, check clientconfig.CheckConfig) (string, int, error) {
+	config := c.config()
+	interpreter := system.Interpreter{
+		InterpreterNameFromInput: check.Interpreter,
+		InterpreterAliases:       config.InterpreterAliases,
+	}
+
+	scriptPath, err := system.CreateScriptFile(config.GetScriptsDir(), check.Command, interpreter)
+	if err != nil {
+		return "", 0, err
+	}
+	defer c.rmScript(scriptPath)
+
+	cmd := c.cmdExec.New(ctx, &system.CmdExecutorContext{
+		Interpreter: interpreter,
+		Command:     scriptPath,
+		HasShebang:  system.HasShebangLine(check.Command),
+	})
+	stdOut := &CapacityBuffer{capacity: checks.MaxOutputLength * 4}
+	cmd.Stdout = stdOut
+
+	if err := c.cmdExec.Start(cmd); err != nil {
+		return "", 0, err
+	}
+	code, err := exitCode(c.cmdExec.Wait(cmd))
+	if err != nil {
+		return "", 0, err
+	}
+
+	return c.ToUTF8(stdOut.Bytes()), code, nil
+}
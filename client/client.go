@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cloudradar-monitoring/rport/share/random"
@@ -23,7 +24,9 @@ import (
 	"golang.org/x/net/proxy"
 	"golang.org/x/text/encoding"
 
+	"github.com/cloudradar-monitoring/rport/client/inventory"
 	"github.com/cloudradar-monitoring/rport/client/monitoring"
+	"github.com/cloudradar-monitoring/rport/client/selfupdate"
 	"github.com/cloudradar-monitoring/rport/client/system"
 	"github.com/cloudradar-monitoring/rport/client/updates"
 	chshare "github.com/cloudradar-monitoring/rport/share"
@@ -37,21 +40,28 @@ import (
 type Client struct {
 	*logger.Logger
 
-	SessionID          string
-	configHolder       *ClientConfigHolder
+	SessionID string
+	// configMu guards configHolder, it's replaced by the effective config when the server pushes configuration profiles
+	configMu     sync.RWMutex
+	configHolder *ClientConfigHolder
+	// localConfig is the config read from the config file, configuration profiles from the server are applied to it
+	localConfig        *ClientConfigHolder
 	sshConfig          *ssh.ClientConfig
 	sshConn            ssh.Conn
 	running            bool
 	runningc           chan error
 	connStats          chshare.ConnStats
 	cmdExec            system.CmdExecutor
+	runningJobs        runningJobs
 	systemInfo         system.SysInfo
 	updates            *updates.Updates
+	inventory          *inventory.Inventory
 	monitor            *monitoring.Monitor
 	serverCapabilities *models.Capabilities
 	consoleDecoder     *encoding.Decoder
 	filesAPI           files.FileAPI
 	watchdog           *Watchdog
+	selfUpdater        *selfupdate.Updater
 }
 
 // NewClient creates a new client instance
@@ -75,15 +85,18 @@ func NewClient(config *ClientConfigHolder, filesAPI files.FileAPI) (*Client, err
 		SessionID:    sessionID,
 		Logger:       logger,
 		configHolder: config,
+		localConfig:  &ClientConfigHolder{Config: config.Config.Clone()},
 		running:      true,
 		runningc:     make(chan error, 1),
 		cmdExec:      cmdExec,
 		systemInfo:   systemInfo,
 		updates:      updates.New(logger, config.Client.UpdatesInterval),
-		monitor:      monitoring.NewMonitor(logger, config.Monitoring, systemInfo),
+		inventory:    inventory.New(logger, config.Client.InventoryInterval),
 		filesAPI:     filesAPI,
 		watchdog:     watchdog,
+		selfUpdater:  selfupdate.New(logger, config.SelfUpdate, config.Client.DataDir, chshare.BuildVersion),
 	}
+	client.monitor = monitoring.NewMonitor(logger, config.Monitoring, systemInfo, client)
 
 	client.sshConfig = &ssh.ClientConfig{
 		User:            config.Client.AuthUser,
@@ -106,6 +119,11 @@ func NewClient(config *ClientConfigHolder, filesAPI files.FileAPI) (*Client, err
 	return client, nil
 }
 
+// SetRestart sets the func restarting the client, the client can only update itself if it's set
+func (c *Client) SetRestart(restart func() error) {
+	c.selfUpdater.SetRestart(restart)
+}
+
 // Run starts client and blocks while connected
 func (c *Client) Run() error {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -117,8 +135,9 @@ func (c *Client) Run() error {
 }
 
 func (c *Client) verifyServer(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	config := c.config()
 	got := chshare.FingerprintKey(key)
-	if c.configHolder.Client.Fingerprint != "" && !strings.HasPrefix(got, c.configHolder.Client.Fingerprint) {
+	if config.Client.Fingerprint != "" && !strings.HasPrefix(got, config.Client.Fingerprint) {
 		return fmt.Errorf("invalid fingerprint (%s)", got)
 	}
 	//overwrite with complete fingerprint
@@ -128,25 +147,27 @@ func (c *Client) verifyServer(hostname string, remote net.Addr, key ssh.PublicKe
 
 // Start client and do not block
 func (c *Client) Start(ctx context.Context) error {
+	c.selfUpdater.Start()
 
 	//optional keepalive loop
-	if c.configHolder.Connection.KeepAlive > 0 {
-		c.Infof("Keepalive job (client to server ping) started with interval %s", c.configHolder.Connection.KeepAlive)
+	if c.config().Connection.KeepAlive > 0 {
+		c.Infof("Keepalive job (client to server ping) started with interval %s", c.config().Connection.KeepAlive)
 		go c.keepAliveLoop()
 	}
 	//connection loop
 	go c.connectionLoop(ctx)
 
 	c.updates.Start(ctx)
+	c.inventory.Start(ctx)
 
 	return nil
 }
 
 func (c *Client) keepAliveLoop() {
 	for c.running {
-		time.Sleep(c.configHolder.Connection.KeepAlive)
+		time.Sleep(c.config().Connection.KeepAlive)
 		if c.sshConn != nil {
-			ok, _, rtt, err := comm.PingConnectionWithTimeout(c.sshConn, c.configHolder.Connection.KeepAliveTimeout)
+			ok, _, rtt, err := comm.PingConnectionWithTimeout(c.sshConn, c.config().Connection.KeepAliveTimeout)
 			if err != nil || !ok {
 				c.Errorf("Failed to keepalive (client to server ping): %s", err)
 				c.sshConn.Close()
@@ -163,7 +184,7 @@ func (c *Client) connectionLoop(ctx context.Context) {
 	//connection loop!
 	var connerr error
 	switchbackChan := make(chan *sshClientConn, 1)
-	b := &backoff.Backoff{Max: c.configHolder.Connection.MaxRetryInterval}
+	b := &backoff.Backoff{Max: c.config().Connection.MaxRetryInterval}
 loop:
 	for c.running {
 		if connerr != nil {
@@ -171,7 +192,7 @@ loop:
 			d := b.Duration()
 			c.showConnectionError(connerr, attempt)
 			//give up?
-			if c.configHolder.Connection.MaxRetryCount >= 0 && attempt >= c.configHolder.Connection.MaxRetryCount {
+			if c.config().Connection.MaxRetryCount >= 0 && attempt >= c.config().Connection.MaxRetryCount {
 				break
 			}
 			msg := fmt.Sprintf("Retrying in %s...", d)
@@ -209,8 +230,8 @@ loop:
 					select {
 					case <-switchbackCtx.Done():
 						return
-					case <-time.After(c.configHolder.Client.ServerSwitchbackInterval):
-						switchbackConn, err := c.connect(c.configHolder.Client.Server)
+					case <-time.After(c.config().Client.ServerSwitchbackInterval):
+						switchbackConn, err := c.connect(c.config().Client.Server)
 						if err != nil {
 							c.Errorf("Switchback failed: %v", err.Error())
 							continue
@@ -235,15 +256,18 @@ loop:
 		}
 
 		b.Reset()
+		c.selfUpdater.Confirm()
 
 		c.sshConn = sshConn.Connection
 		c.updates.SetConn(sshConn.Connection)
+		c.inventory.SetConn(sshConn.Connection)
 		c.monitor.SetConn(sshConn.Connection)
 
 		err = sshConn.Connection.Wait()
 		//disconnected
 		c.sshConn = nil
 		c.updates.SetConn(nil)
+		c.inventory.SetConn(nil)
 		c.monitor.SetConn(nil)
 		c.monitor.Stop()
 		cancelSwitchback()
@@ -268,7 +292,8 @@ type sshClientConn struct {
 }
 
 func (c *Client) connectToMainOrFallback() (conn *sshClientConn, isPrimary bool, err error) {
-	servers := append([]string{c.configHolder.Client.Server}, c.configHolder.Client.FallbackServers...)
+	config := c.config()
+	servers := append([]string{config.Client.Server}, config.Client.FallbackServers...)
 	for i, server := range servers {
 		conn, err = c.connect(server)
 		if err != nil {
@@ -284,9 +309,10 @@ func (c *Client) connectToMainOrFallback() (conn *sshClientConn, isPrimary bool,
 }
 
 func (c *Client) connect(server string) (*sshClientConn, error) {
+	config := c.config()
 	via := ""
-	if c.configHolder.Client.ProxyURL != nil {
-		via = " via " + c.configHolder.Client.ProxyURL.String()
+	if config.Client.ProxyURL != nil {
+		via = " via " + config.Client.ProxyURL.String()
 	}
 	c.Infof("Trying to connect to %s%s ...\n", server, via)
 
@@ -298,31 +324,31 @@ func (c *Client) connect(server string) (*sshClientConn, error) {
 		Subprotocols:     []string{chshare.ProtocolVersion},
 		NetDialContext:   netDialer.DialContext,
 	}
-	if c.configHolder.Client.BindInterface != "" {
-		laddr, err := c.localAddrForInterface(c.configHolder.Client.BindInterface)
+	if config.Client.BindInterface != "" {
+		laddr, err := c.localAddrForInterface(config.Client.BindInterface)
 		if err != nil {
 			return nil, err
 		}
 		netDialer.LocalAddr = laddr
 	}
 	//optionally proxy
-	if c.configHolder.Client.ProxyURL != nil {
-		if strings.HasPrefix(c.configHolder.Client.ProxyURL.Scheme, "socks") {
+	if config.Client.ProxyURL != nil {
+		if strings.HasPrefix(config.Client.ProxyURL.Scheme, "socks") {
 			// SOCKS5 proxy
-			if c.configHolder.Client.ProxyURL.Scheme != "socks" && c.configHolder.Client.ProxyURL.Scheme != "socks5h" {
+			if config.Client.ProxyURL.Scheme != "socks" && config.Client.ProxyURL.Scheme != "socks5h" {
 				return nil, fmt.Errorf(
 					"unsupported socks proxy type: %s:// (only socks5h:// or socks:// is supported)",
-					c.configHolder.Client.ProxyURL.Scheme)
+					config.Client.ProxyURL.Scheme)
 			}
 			var auth *proxy.Auth
-			if c.configHolder.Client.ProxyURL.User != nil {
-				pass, _ := c.configHolder.Client.ProxyURL.User.Password()
+			if config.Client.ProxyURL.User != nil {
+				pass, _ := config.Client.ProxyURL.User.Password()
 				auth = &proxy.Auth{
-					User:     c.configHolder.Client.ProxyURL.User.Username(),
+					User:     config.Client.ProxyURL.User.Username(),
 					Password: pass,
 				}
 			}
-			socksDialer, err := proxy.SOCKS5("tcp", c.configHolder.Client.ProxyURL.Host, auth, netDialer)
+			socksDialer, err := proxy.SOCKS5("tcp", config.Client.ProxyURL.Host, auth, netDialer)
 			if err != nil {
 				return nil, retryableError{err}
 			}
@@ -330,11 +356,11 @@ func (c *Client) connect(server string) (*sshClientConn, error) {
 		} else {
 			// CONNECT proxy
 			d.Proxy = func(*http.Request) (*url.URL, error) {
-				return c.configHolder.Client.ProxyURL, nil
+				return config.Client.ProxyURL, nil
 			}
 		}
 	}
-	wsConn, _, err := d.Dial(server, c.configHolder.Connection.HTTPHeaders)
+	wsConn, _, err := d.Dial(server, config.Connection.HTTPHeaders)
 	if err != nil {
 		return nil, retryableError{ConnectionErrorHints(server, c.Logger, err)}
 	}
@@ -436,6 +462,10 @@ func (c *Client) handleSSHRequests(ctx context.Context, sshConn *sshClientConn)
 			resp, err = checkPort(r.Payload)
 		case comm.RequestTypeRunCmd:
 			resp, err = c.HandleRunCmdRequest(ctx, r.Payload)
+		case comm.RequestTypeInstallUpdates:
+			resp, err = c.HandleInstallUpdatesRequest(ctx, r.Payload)
+		case comm.RequestTypeCancelJob:
+			err = c.HandleCancelJobRequest(r.Payload)
 		case comm.RequestTypeRefreshUpdatesStatus:
 			c.updates.Refresh()
 		case comm.RequestTypePutCapabilities:
@@ -444,12 +474,16 @@ func (c *Client) handleSSHRequests(ctx context.Context, sshConn *sshClientConn)
 			uploadManager := NewSSHUploadManager(
 				c.Logger,
 				c.filesAPI,
-				c.configHolder,
+				c.config(),
 				sshConn.Connection,
 				system.SysUserProvider{},
 			)
 
 			resp, err = uploadManager.HandleUploadRequest(r.Payload)
+		case comm.RequestTypeSelfUpdate:
+			err = c.selfUpdater.HandleRequest(r.Payload, &SSHFileProvider{sshConn: sshConn.Connection})
+		case comm.RequestTypePutManagedConfig:
+			resp, err = c.handlePutManagedConfigRequest(ctx, r.Payload)
 		case comm.RequestTypeCheckTunnelAllowed:
 			resp, err = c.checkTunnelAllowed(r.Payload)
 		case comm.RequestTypePing:
@@ -494,7 +528,14 @@ func (c *Client) checkTunnelAllowed(payload []byte) (*comm.CheckTunnelAllowedRes
 		return nil, err
 	}
 
-	allowed, err := TunnelIsAllowed(c.configHolder.Client.TunnelAllowed, req.Remote)
+	if err := c.config().CheckUserPolicy(req.User, req.UserGroups); err != nil {
+		c.Infof("Tunnel to %s rejected: %v", req.Remote, err)
+		return &comm.CheckTunnelAllowedResponse{
+			IsAllowed: false,
+		}, nil
+	}
+
+	allowed, err := TunnelIsAllowed(c.config().Client.TunnelAllowed, req.Remote)
 	if err != nil {
 		return nil, err
 	}
@@ -505,7 +546,7 @@ func (c *Client) checkTunnelAllowed(payload []byte) (*comm.CheckTunnelAllowedRes
 }
 
 func (c *Client) showConnectionError(connerr error, attempt int) {
-	maxAttempt := c.configHolder.Connection.MaxRetryCount
+	maxAttempt := c.config().Connection.MaxRetryCount
 	//show error and attempt counts
 	msg := fmt.Sprintf("Connection error: %s", connerr)
 	if attempt > 0 {
@@ -539,6 +580,15 @@ func (c *Client) Close() error {
 
 func (c *Client) connectStreams(chans <-chan ssh.NewChannel) {
 	for ch := range chans {
+		if ch.ChannelType() == comm.ChannelTypeTerminal {
+			go c.handleTerminalChannel(ch)
+			continue
+		}
+		if ch.ChannelType() == comm.ChannelTypeSFTP {
+			go c.handleSFTPChannel(ch)
+			continue
+		}
+
 		remote := string(ch.ExtraData())
 		protocol := models.ProtocolTCP
 		parts := strings.SplitN(remote, "/", 2)
@@ -547,7 +597,7 @@ func (c *Client) connectStreams(chans <-chan ssh.NewChannel) {
 			protocol = parts[1]
 		}
 
-		allowed, err := TunnelIsAllowed(c.configHolder.Client.TunnelAllowed, remote)
+		allowed, err := TunnelIsAllowed(c.config().Client.TunnelAllowed, remote)
 		if err != nil {
 			c.Errorf("Could not check if remote is allowed: %v", err)
 		}
@@ -617,13 +667,14 @@ func (c *Client) localIPAddresses() ([]string, []string, error) {
 func (c *Client) connectionRequest(ctx context.Context) (*chshare.ConnectionRequest, error) {
 	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
 	defer cancel()
+	config := c.config()
 
 	connReq := &chshare.ConnectionRequest{
-		ID:                     c.configHolder.Client.ID,
-		Name:                   c.configHolder.Client.Name,
+		ID:                     config.Client.ID,
+		Name:                   config.Client.Name,
 		SessionID:              c.SessionID,
-		Tags:                   c.configHolder.Client.Tags,
-		Remotes:                c.configHolder.Client.Tunnels,
+		Tags:                   config.Client.Tags,
+		Remotes:                config.Client.Tunnels,
 		OS:                     system.UnknownValue,
 		OSArch:                 c.systemInfo.GoArch(),
 		OSKernel:               system.UnknownValue,
@@ -637,18 +688,18 @@ func (c *Client) connectionRequest(ctx context.Context) (*chshare.ConnectionRequ
 		CPUModel:               system.UnknownValue,
 		CPUModelName:           system.UnknownValue,
 		CPUVendor:              system.UnknownValue,
-		ClientConfiguration:    c.configHolder.Config,
+		ClientConfiguration:    config.Config,
 	}
 
 	var err error
-	if connReq.ID == "" && c.configHolder.Client.UseSystemID {
+	if connReq.ID == "" && config.Client.UseSystemID {
 		connReq.ID, err = machineid.ID()
 		if err != nil {
 			return nil, fmt.Errorf("could not use system id as client id: try to set client.id manually or disable client.use_system_id. Error: %w", err)
 		}
 	}
 
-	if connReq.Name == "" && c.configHolder.Client.UseHostname {
+	if connReq.Name == "" && config.Client.UseHostname {
 		connReq.Name, err = c.systemInfo.Hostname()
 		if err != nil {
 			return nil, fmt.Errorf("could not use system hostname as client name: try to set client.name manually or disable client.use_hostname. Error: %w", err)
@@ -31,7 +31,8 @@ var (
 var now = time.Now
 
 func (c *Client) HandleRunCmdRequest(ctx context.Context, reqPayload []byte) (*comm.RunCmdResponse, error) {
-	if !c.configHolder.RemoteCommands.Enabled {
+	config := c.config()
+	if !config.RemoteCommands.Enabled {
 		return nil, errors.New("remote commands execution is disabled")
 	}
 
@@ -41,7 +42,11 @@ func (c *Client) HandleRunCmdRequest(ctx context.Context, reqPayload []byte) (*c
 		return nil, fmt.Errorf("failed to decode requested job: %s", err)
 	}
 
-	if job.IsScript && !c.configHolder.RemoteScripts.Enabled {
+	if err := config.CheckUserPolicy(job.CreatedBy, job.CreatedByGroups); err != nil {
+		return nil, err
+	}
+
+	if job.IsScript && !config.RemoteScripts.Enabled {
 		return nil, errors.New("remote scripts are disabled")
 	}
 
@@ -51,10 +56,24 @@ func (c *Client) HandleRunCmdRequest(ctx context.Context, reqPayload []byte) (*c
 
 	interpreter := system.Interpreter{
 		InterpreterNameFromInput: job.Interpreter,
-		InterpreterAliases:       c.configHolder.InterpreterAliases,
+		InterpreterAliases:       config.InterpreterAliases,
 	}
 
-	scriptPath, err := system.CreateScriptFile(c.configHolder.GetScriptsDir(), job.Command, interpreter)
+	return c.runJob(ctx, reqPayload, job, job.Command, interpreter, nil)
+}
+
+// runJob executes the script of a job in background and sends the job with the result to the server when it's finished.
+// onFinished is optional, it's called after the result is sent.
+func (c *Client) runJob(
+	ctx context.Context,
+	reqPayload []byte,
+	job models.Job,
+	script string,
+	interpreter system.Interpreter,
+	onFinished func(),
+) (*comm.RunCmdResponse, error) {
+	config := c.config()
+	scriptPath, err := system.CreateScriptFile(config.GetScriptsDir(), script, interpreter)
 	if err != nil {
 		return nil, err
 	}
@@ -70,7 +89,7 @@ func (c *Client) HandleRunCmdRequest(ctx context.Context, reqPayload []byte) (*c
 		}
 		limitedStdOutCh = &LimitedWriter{
 			Writer: stdOutCh,
-			Limit:  c.configHolder.RemoteCommands.SendBackLimit,
+			Limit:  config.RemoteCommands.SendBackLimit,
 		}
 
 		stdErrCh, reqs, err := c.sshConn.OpenChannel(models.ChannelStderr, reqPayload)
@@ -80,7 +99,7 @@ func (c *Client) HandleRunCmdRequest(ctx context.Context, reqPayload []byte) (*c
 		}
 		limitedStdErrCh = &LimitedWriter{
 			Writer: stdErrCh,
-			Limit:  c.configHolder.RemoteCommands.SendBackLimit,
+			Limit:  config.RemoteCommands.SendBackLimit,
 		}
 
 		closeStreamChannels = func() {
@@ -94,12 +113,12 @@ func (c *Client) HandleRunCmdRequest(ctx context.Context, reqPayload []byte) (*c
 		Command:     scriptPath,
 		WorkingDir:  job.Cwd,
 		IsSudo:      job.IsSudo,
-		HasShebang:  system.HasShebangLine(job.Command),
+		HasShebang:  system.HasShebangLine(script),
 	}
 	cmd := c.cmdExec.New(ctx, execCtx)
 	summary := NewSummaryBuffer()
-	stdOut := &CapacityBuffer{capacity: c.configHolder.RemoteCommands.SendBackLimit}
-	stdErr := &CapacityBuffer{capacity: c.configHolder.RemoteCommands.SendBackLimit}
+	stdOut := &CapacityBuffer{capacity: config.RemoteCommands.SendBackLimit}
+	stdErr := &CapacityBuffer{capacity: config.RemoteCommands.SendBackLimit}
 	cmd.Stdout = io.MultiWriter(summary, stdOut, limitedStdOutCh)
 	cmd.Stderr = io.MultiWriter(stdErr, limitedStdErrCh)
 
@@ -111,11 +130,16 @@ func (c *Client) HandleRunCmdRequest(ctx context.Context, reqPayload []byte) (*c
 		c.rmScript(scriptPath)
 		return nil, fmt.Errorf("failed to start a command: %s", err)
 	}
+	running := c.runningJobs.add(job.JID, cmd)
 
 	// observe the cmd execution in background
 	go func() {
+		if onFinished != nil {
+			defer onFinished()
+		}
 		defer c.rmScript(scriptPath)
 		defer closeStreamChannels()
+		defer c.runningJobs.remove(job.JID)
 
 		c.Debugf("started to observe cmd [jid=%q,pid=%d]", job.JID, cmd.Process.Pid)
 
@@ -127,7 +151,11 @@ func (c *Client) HandleRunCmdRequest(ctx context.Context, reqPayload []byte) (*c
 		var execErr error
 		select {
 		case execErr = <-done:
-			if execErr != nil {
+			close(running.exited)
+			if running.isCancelled() {
+				status = models.JobStatusCancelled
+				c.Infof("command[jid=%q,pid=%d] cancelled", job.JID, cmd.Process.Pid)
+			} else if execErr != nil {
 				status = models.JobStatusFailed
 				c.Errorf("failed to run command[jid=%q,pid=%d]:\ncmd:\n%s\nerr: %s", job.JID, cmd.Process.Pid, job.Command, execErr)
 			} else {
@@ -206,9 +234,10 @@ func (c *Client) rmScript(scriptPath string) {
 
 // isAllowed returns true if a given command passes configured restrictions.
 func (c *Client) isAllowed(cmd string) bool {
-	allowMatch := matchRegexp(cmd, c.configHolder.RemoteCommands.AllowRegexp)
-	denyMatch := matchRegexp(cmd, c.configHolder.RemoteCommands.DenyRegexp)
-	switch c.configHolder.RemoteCommands.Order {
+	config := c.config()
+	allowMatch := matchRegexp(cmd, config.RemoteCommands.AllowRegexp)
+	denyMatch := matchRegexp(cmd, config.RemoteCommands.DenyRegexp)
+	switch config.RemoteCommands.Order {
 	case allowDenyOrder:
 		if !allowMatch {
 			return false
@@ -0,0 +1,147 @@
+package chclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/client/system"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+)
+
+// runningJob is a job observed by the client, it can be cancelled until the observation ends
+type runningJob struct {
+	cmd *exec.Cmd
+	// exited is closed when the process of the job has exited
+	exited chan struct{}
+
+	mu        sync.Mutex
+	cancelled bool
+}
+
+// cancel marks the job as cancelled, returns false if it's already cancelled
+func (j *runningJob) cancel() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.cancelled {
+		return false
+	}
+	j.cancelled = true
+	return true
+}
+
+// resetCancel allows to cancel the job again, it's used if the cancellation failed
+func (j *runningJob) resetCancel() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.cancelled = false
+}
+
+func (j *runningJob) isCancelled() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.cancelled
+}
+
+// runningJobs is a thread safe map of observed jobs by their JID
+type runningJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*runningJob
+}
+
+func (r *runningJobs) add(jid string, cmd *exec.Cmd) *runningJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.jobs == nil {
+		r.jobs = make(map[string]*runningJob)
+	}
+	job := &runningJob{
+		cmd:    cmd,
+		exited: make(chan struct{}),
+	}
+	r.jobs[jid] = job
+	return job
+}
+
+func (r *runningJobs) get(jid string) *runningJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.jobs[jid]
+}
+
+func (r *runningJobs) remove(jid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.jobs, jid)
+}
+
+// HandleCancelJobRequest terminates the process of a running job, the job result is sent by the observer of the job.
+func (c *Client) HandleCancelJobRequest(reqPayload []byte) error {
+	req := comm.CancelJobRequest{}
+	if err := json.Unmarshal(reqPayload, &req); err != nil {
+		return fmt.Errorf("failed to decode %T: %v", req, err)
+	}
+
+	job := c.runningJobs.get(req.JID)
+	if job == nil {
+		return fmt.Errorf("job %q is not running", req.JID)
+	}
+	if !job.cancel() {
+		c.Debugf("job %q is already being cancelled", req.JID)
+		return nil
+	}
+
+	signals := c.config().RemoteCommands.CancelSignals
+	if len(signals) == 0 {
+		signals = []string{"SIGKILL"}
+	}
+
+	// the first signal is sent right away, so the server is told if the job can't be cancelled
+	if err := c.sendCancelSignal(req.JID, job, signals[0]); err != nil {
+		job.resetCancel()
+		return err
+	}
+
+	go c.terminateJob(req.JID, job, signals[1:])
+
+	return nil
+}
+
+func (c *Client) sendCancelSignal(jid string, job *runningJob, name string) error {
+	sig, err := system.ParseSignal(name)
+	if err != nil {
+		return fmt.Errorf("failed to cancel job %q: %v", jid, err)
+	}
+
+	c.Debugf("sending %s to cancel job [jid=%q,pid=%d]", name, jid, job.cmd.Process.Pid)
+	err = c.cmdExec.Terminate(job.cmd, sig)
+	if err != nil && err != syscall.ESRCH {
+		return fmt.Errorf("failed to send %s to job %q: %v", name, jid, err)
+	}
+
+	return nil
+}
+
+// terminateJob sends the remaining signals one by one until the process of the job exits
+func (c *Client) terminateJob(jid string, job *runningJob, signals []string) {
+	for {
+		select {
+		case <-job.exited:
+			return
+		case <-time.After(c.config().RemoteCommands.CancelSignalsInterval):
+		}
+
+		if len(signals) == 0 {
+			break
+		}
+		if err := c.sendCancelSignal(jid, job, signals[0]); err != nil {
+			c.Errorf("%v", err)
+		}
+		signals = signals[1:]
+	}
+
+	c.Errorf("job [jid=%q,pid=%d] has not exited after all cancel signals were sent", jid, job.cmd.Process.Pid)
+}
@@ -0,0 +1,70 @@
+//go:build !windows
+// +build !windows
+
+package chclient
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+func TestHandleCancelJobRequest(t *testing.T) {
+	now = nowMockF
+
+	execMock := NewCmdExecutorMock()
+	execMock.ReturnPID = 123
+	execMock.ExitOnSignal = syscall.SIGKILL
+	connMock := test.NewConnMock()
+	done := make(chan bool)
+	connMock.DoneChannel = done
+	configCopy := getDefaultValidMinConfig()
+	configCopy.RemoteCommands.CancelSignals = []string{"SIGTERM", "SIGKILL"}
+	configCopy.RemoteCommands.CancelSignalsInterval = 10 * time.Millisecond
+	c := Client{
+		cmdExec:      execMock,
+		sshConn:      connMock,
+		Logger:       testLog,
+		configHolder: &configCopy,
+	}
+
+	configCopy.Client.DataDir = filepath.Join(configCopy.Client.DataDir, "TestHandleCancelJobRequest")
+	defer func() {
+		os.RemoveAll(configCopy.Client.DataDir)
+	}()
+	require.NoError(t, PrepareDirs(&configCopy))
+
+	_, err := c.HandleRunCmdRequest(context.Background(), []byte(jobToRunJSON))
+	require.NoError(t, err)
+
+	execMock.ReturnTerminateErr = syscall.EPERM
+	err = c.HandleCancelJobRequest([]byte(`{"JID": "5f02b216-3f8a-42be-b66c-f4c1d0ea3809"}`))
+	assert.EqualError(t, err, `failed to send SIGTERM to job "5f02b216-3f8a-42be-b66c-f4c1d0ea3809": operation not permitted`)
+
+	// the cancellation can be retried
+	execMock.ReturnTerminateErr = nil
+	err = c.HandleCancelJobRequest([]byte(`{"JID": "5f02b216-3f8a-42be-b66c-f4c1d0ea3809"}`))
+	require.NoError(t, err)
+	<-done
+
+	inputRequestName, _, inputPayload := connMock.InputSendRequest()
+	assert.Equal(t, comm.RequestTypeCmdResult, inputRequestName)
+	job := models.Job{}
+	require.NoError(t, json.Unmarshal(inputPayload, &job))
+	assert.Equal(t, models.JobStatusCancelled, job.Status)
+	assert.Equal(t, []syscall.Signal{syscall.SIGTERM, syscall.SIGKILL}, execMock.Signals())
+
+	err = c.HandleCancelJobRequest([]byte(`{"JID": "5f02b216-3f8a-42be-b66c-f4c1d0ea3809"}`))
+	assert.EqualError(t, err, `job "5f02b216-3f8a-42be-b66c-f4c1d0ea3809" is not running`)
+}
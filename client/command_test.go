@@ -11,6 +11,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"syscall"
 	"testing"
 	"time"
 
@@ -32,8 +33,15 @@ type CmdExecutorMock struct {
 	ReturnWaitErr  error
 	ReturnStdOut   []string
 	ReturnStdErr   []string
-
-	wg sync.WaitGroup
+	// ReturnTerminateErr is returned by Terminate, the signal is not recorded then
+	ReturnTerminateErr error
+	// if ExitOnSignal is set, Wait blocks until the signal is sent by Terminate
+	ExitOnSignal syscall.Signal
+
+	wg          sync.WaitGroup
+	mu          sync.Mutex
+	signals     []syscall.Signal
+	exitChannel chan struct{}
 }
 
 func NewCmdExecutorMock() *CmdExecutorMock {
@@ -61,6 +69,7 @@ func (e *CmdExecutorMock) Start(cmd *exec.Cmd) error {
 	if e.ReturnPID != 0 {
 		cmd.Process = &os.Process{Pid: e.ReturnPID}
 	}
+	e.exitChannel = make(chan struct{})
 
 	// mock output to stdout and stderr
 	e.wg.Add(1)
@@ -100,6 +109,9 @@ func (e *CmdExecutorMock) Wait(cmd *exec.Cmd) error {
 		return e.ReturnWaitErr
 	}
 	e.wg.Wait()
+	if e.ExitOnSignal != 0 {
+		<-e.exitChannel
+	}
 	// wait if needed
 	if e.DoneChannel != nil {
 		e.DoneChannel <- true
@@ -107,6 +119,25 @@ func (e *CmdExecutorMock) Wait(cmd *exec.Cmd) error {
 	return nil
 }
 
+func (e *CmdExecutorMock) Terminate(cmd *exec.Cmd, sig syscall.Signal) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.ReturnTerminateErr != nil {
+		return e.ReturnTerminateErr
+	}
+	e.signals = append(e.signals, sig)
+	if sig == e.ExitOnSignal {
+		close(e.exitChannel)
+	}
+	return nil
+}
+
+func (e *CmdExecutorMock) Signals() []syscall.Signal {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.signals
+}
+
 // nowMock is used to override time now.
 var nowMockF = func() time.Time {
 	n, _ := time.Parse(time.RFC3339, "2020-08-19T12:00:00+03:00")
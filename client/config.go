@@ -1,6 +1,9 @@
 package chclient
 
 import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"log"
@@ -21,7 +24,16 @@ import (
 	"github.com/cloudradar-monitoring/rport/share/models"
 )
 
-const DefaultMonitoringInterval = 60 * time.Second
+const (
+	DefaultMonitoringInterval = 60 * time.Second
+	DefaultCheckInterval      = 60 * time.Second
+	DefaultCheckTimeout       = 10 * time.Second
+	MinCheckInterval          = 10 * time.Second
+	DefaultRollbackTimeout    = 5 * time.Minute
+	MinRollbackTimeout        = 30 * time.Second
+)
+
+var checkNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 
 var (
 	allowDenyOrder = [2]string{"allow", "deny"}
@@ -75,10 +87,26 @@ func (c *ClientConfigHolder) ParseAndValidate(skipScriptsDirValidation bool) err
 		return err
 	}
 
+	if err := c.ParseAndValidateFileBrowserConfig(); err != nil {
+		return fmt.Errorf("file browser: %v", err)
+	}
+
 	if err := c.ParseAndValidateConnection(); err != nil {
 		return err
 	}
 
+	if err := c.ParseAndValidateSelfUpdate(); err != nil {
+		return fmt.Errorf("self-update: %v", err)
+	}
+
+	if err := c.ParseAndValidateManagedConfig(); err != nil {
+		return fmt.Errorf("managed-config: %v", err)
+	}
+
+	if err := c.ParseAndValidateUserPolicy(); err != nil {
+		return fmt.Errorf("user-policy: %v", err)
+	}
+
 	return nil
 }
 
@@ -102,6 +130,50 @@ func (c *ClientConfigHolder) ParseAndValidateMonitoring() error {
 		}
 		c.Monitoring.WanCard = wanCard
 	}
+
+	if err := c.parseChecks(); err != nil {
+		return fmt.Errorf("monitoring checks: %v", err)
+	}
+	return nil
+}
+
+func (c *ClientConfigHolder) parseChecks() error {
+	names := make(map[string]bool, len(c.Monitoring.Checks))
+	for i := range c.Monitoring.Checks {
+		check := &c.Monitoring.Checks[i]
+		if !checkNameRegex.MatchString(check.Name) {
+			return fmt.Errorf("invalid name %q, only letters, digits, '_' and '-' are allowed", check.Name)
+		}
+		if names[check.Name] {
+			return fmt.Errorf("duplicate name %q", check.Name)
+		}
+		names[check.Name] = true
+
+		if strings.TrimSpace(check.Command) == "" {
+			return fmt.Errorf("%s: 'command' cannot be empty", check.Name)
+		}
+
+		switch check.Type {
+		case "":
+			check.Type = clientconfig.CheckTypeValue
+		case clientconfig.CheckTypeValue, clientconfig.CheckTypeNagios:
+		default:
+			return fmt.Errorf("%s: invalid type %q, expected %q or %q", check.Name, check.Type, clientconfig.CheckTypeValue, clientconfig.CheckTypeNagios)
+		}
+
+		if check.Interval == 0 {
+			check.Interval = DefaultCheckInterval
+		}
+		if check.Interval < MinCheckInterval {
+			return fmt.Errorf("%s: 'interval' must be at least %s", check.Name, MinCheckInterval)
+		}
+		if check.Timeout == 0 {
+			check.Timeout = DefaultCheckTimeout
+		}
+		if check.Timeout < 0 || check.Timeout > check.Interval {
+			return fmt.Errorf("%s: 'timeout' must be positive and not greater than 'interval'", check.Name)
+		}
+	}
 	return nil
 }
 
@@ -118,6 +190,91 @@ func (c *ClientConfigHolder) ParseAndValidateConnection() error {
 	return nil
 }
 
+func (c *ClientConfigHolder) ParseAndValidateSelfUpdate() error {
+	if !c.SelfUpdate.Enabled {
+		return nil
+	}
+	if c.SelfUpdate.PublicKeyFile == "" {
+		return errors.New("'public_key_file' is required")
+	}
+	if c.SelfUpdate.RollbackTimeout < MinRollbackTimeout {
+		return fmt.Errorf("'rollback_timeout' must be at least %s", MinRollbackTimeout)
+	}
+
+	data, err := os.ReadFile(c.SelfUpdate.PublicKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read public key: %v", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("no PEM encoded public key found in %s", c.SelfUpdate.PublicKeyFile)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("invalid public key: %v", err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return fmt.Errorf("invalid public key: expected ed25519, got %T", key)
+	}
+	c.SelfUpdate.PublicKey = publicKey
+
+	return nil
+}
+
+func (c *ClientConfigHolder) ParseAndValidateManagedConfig() error {
+	for _, key := range c.ManagedConfig.Locked {
+		if !clientconfig.IsManagedOptionKey(key) {
+			return fmt.Errorf("'locked': %q is not an option or section that can be managed", key)
+		}
+	}
+
+	return nil
+}
+
+func (c *ClientConfigHolder) ParseAndValidateUserPolicy() error {
+	lists := map[string][]string{
+		"allow_users":  c.UserPolicy.AllowUsers,
+		"deny_users":   c.UserPolicy.DenyUsers,
+		"allow_groups": c.UserPolicy.AllowGroups,
+		"deny_groups":  c.UserPolicy.DenyGroups,
+	}
+	for option, values := range lists {
+		for _, value := range values {
+			if strings.TrimSpace(value) == "" {
+				return fmt.Errorf("'%s': empty values are not allowed", option)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ApplyManagedOptions returns a copy of the config with the given options applied, except the locked ones.
+// The options set by the server are validated the same way as the local ones.
+func (c *ClientConfigHolder) ApplyManagedOptions(profiles []string, options clientconfig.ManagedOptions) (*ClientConfigHolder, error) {
+	cfg := c.Config.Clone()
+	applied, err := options.ApplyTo(cfg, c.ManagedConfig.Locked)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ManagedConfig.Profiles = profiles
+	cfg.ManagedConfig.Applied = applied
+
+	effective := &ClientConfigHolder{Config: cfg}
+	if err := effective.parseRemoteCommands(); err != nil {
+		return nil, fmt.Errorf("remote commands: %v", err)
+	}
+	if err := effective.ParseAndValidateMonitoring(); err != nil {
+		return nil, err
+	}
+	if err := effective.ParseAndValidateFilePushConfig(); err != nil {
+		return nil, err
+	}
+
+	return effective, nil
+}
+
 func (c *ClientConfigHolder) ParseAndValidateFilePushConfig() error {
 	for _, globPattern := range c.FileReceptionConfig.Protected {
 		_, err := filepath.Match(globPattern, "/test")
@@ -129,6 +286,21 @@ func (c *ClientConfigHolder) ParseAndValidateFilePushConfig() error {
 	return nil
 }
 
+func (c *ClientConfigHolder) ParseAndValidateFileBrowserConfig() error {
+	for _, patterns := range [][]string{c.FileBrowser.Allow, c.FileBrowser.Deny} {
+		for i, globPattern := range patterns {
+			_, err := filepath.Match(globPattern, "/test")
+			if err != nil {
+				return fmt.Errorf("invalid glob pattern %s: %v", globPattern, err)
+			}
+			// a trailing separator would never match, paths are compared without it
+			patterns[i] = filepath.Clean(globPattern)
+		}
+	}
+
+	return nil
+}
+
 func (c *ClientConfigHolder) parseHeaders() error {
 	c.Connection.HTTPHeaders = http.Header{}
 	for _, h := range c.Connection.HeadersRaw {
@@ -290,6 +462,15 @@ func (c *ClientConfigHolder) parseRemoteCommands() error {
 		return fmt.Errorf("invalid order: %v", c.RemoteCommands.Order)
 	}
 
+	for _, name := range c.RemoteCommands.CancelSignals {
+		if _, err := system.ParseSignal(name); err != nil {
+			return fmt.Errorf("cancel signals: %v", err)
+		}
+	}
+	if c.RemoteCommands.CancelSignalsInterval < 0 {
+		return fmt.Errorf("cancel signals interval can not be negative: %v", c.RemoteCommands.CancelSignalsInterval)
+	}
+
 	return nil
 }
 
@@ -1,10 +1,16 @@
 package chclient
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"testing"
 	"time"
@@ -716,3 +722,234 @@ func TestConfigParseAndValidateFilePushConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigParseAndValidateMonitoringChecks(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Checks        []clientconfig.CheckConfig
+		Expected      []clientconfig.CheckConfig
+		ExpectedError string
+	}{
+		{
+			Name: "defaults",
+			Checks: []clientconfig.CheckConfig{
+				{Name: "queue_size", Command: "cat /tmp/queue | wc -l"},
+			},
+			Expected: []clientconfig.CheckConfig{
+				{Name: "queue_size", Command: "cat /tmp/queue | wc -l", Type: clientconfig.CheckTypeValue, Interval: DefaultCheckInterval, Timeout: DefaultCheckTimeout},
+			},
+		},
+		{
+			Name: "nagios",
+			Checks: []clientconfig.CheckConfig{
+				{Name: "load", Command: "check_load", Type: clientconfig.CheckTypeNagios, Interval: 5 * time.Minute, Timeout: 30 * time.Second},
+			},
+			Expected: []clientconfig.CheckConfig{
+				{Name: "load", Command: "check_load", Type: clientconfig.CheckTypeNagios, Interval: 5 * time.Minute, Timeout: 30 * time.Second},
+			},
+		},
+		{
+			Name: "invalid name",
+			Checks: []clientconfig.CheckConfig{
+				{Name: "queue size", Command: "echo 1"},
+			},
+			ExpectedError: `monitoring checks: invalid name "queue size", only letters, digits, '_' and '-' are allowed`,
+		},
+		{
+			Name: "duplicate name",
+			Checks: []clientconfig.CheckConfig{
+				{Name: "queue", Command: "echo 1"},
+				{Name: "queue", Command: "echo 2"},
+			},
+			ExpectedError: `monitoring checks: duplicate name "queue"`,
+		},
+		{
+			Name: "empty command",
+			Checks: []clientconfig.CheckConfig{
+				{Name: "queue", Command: " "},
+			},
+			ExpectedError: `monitoring checks: queue: 'command' cannot be empty`,
+		},
+		{
+			Name: "invalid type",
+			Checks: []clientconfig.CheckConfig{
+				{Name: "queue", Command: "echo 1", Type: "icinga"},
+			},
+			ExpectedError: `monitoring checks: queue: invalid type "icinga", expected "value" or "nagios"`,
+		},
+		{
+			Name: "interval too short",
+			Checks: []clientconfig.CheckConfig{
+				{Name: "queue", Command: "echo 1", Interval: time.Second},
+			},
+			ExpectedError: `monitoring checks: queue: 'interval' must be at least 10s`,
+		},
+		{
+			Name: "timeout greater than interval",
+			Checks: []clientconfig.CheckConfig{
+				{Name: "queue", Command: "echo 1", Timeout: 2 * time.Minute},
+			},
+			ExpectedError: `monitoring checks: queue: 'timeout' must be positive and not greater than 'interval'`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			config := getDefaultValidMinConfig()
+			config.Monitoring.Checks = tc.Checks
+
+			err := config.ParseAndValidate(true)
+
+			if tc.ExpectedError == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.Expected, config.Monitoring.Checks)
+			} else {
+				require.EqualError(t, err, tc.ExpectedError)
+			}
+		})
+	}
+}
+
+func TestConfigParseAndValidateSelfUpdate(t *testing.T) {
+	dir := t.TempDir()
+	publicKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "ed25519.pub")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	rsaKeyFile := filepath.Join(dir, "rsa.pub")
+	require.NoError(t, os.WriteFile(rsaKeyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+	invalidKeyFile := filepath.Join(dir, "invalid.pub")
+	require.NoError(t, os.WriteFile(invalidKeyFile, []byte("invalid"), 0644))
+
+	testCases := []struct {
+		Name          string
+		SelfUpdate    clientconfig.SelfUpdateConfig
+		ExpectedError string
+	}{
+		{
+			Name: "disabled",
+			SelfUpdate: clientconfig.SelfUpdateConfig{
+				Enabled: false,
+			},
+		},
+		{
+			Name: "valid",
+			SelfUpdate: clientconfig.SelfUpdateConfig{
+				Enabled:         true,
+				PublicKeyFile:   keyFile,
+				RollbackTimeout: 5 * time.Minute,
+			},
+		},
+		{
+			Name: "missing public key",
+			SelfUpdate: clientconfig.SelfUpdateConfig{
+				Enabled:         true,
+				RollbackTimeout: 5 * time.Minute,
+			},
+			ExpectedError: "self-update: 'public_key_file' is required",
+		},
+		{
+			Name: "rollback timeout too short",
+			SelfUpdate: clientconfig.SelfUpdateConfig{
+				Enabled:         true,
+				PublicKeyFile:   keyFile,
+				RollbackTimeout: time.Second,
+			},
+			ExpectedError: "self-update: 'rollback_timeout' must be at least 30s",
+		},
+		{
+			Name: "not PEM encoded",
+			SelfUpdate: clientconfig.SelfUpdateConfig{
+				Enabled:         true,
+				PublicKeyFile:   invalidKeyFile,
+				RollbackTimeout: 5 * time.Minute,
+			},
+			ExpectedError: "self-update: no PEM encoded public key found in " + invalidKeyFile,
+		},
+		{
+			Name: "not ed25519",
+			SelfUpdate: clientconfig.SelfUpdateConfig{
+				Enabled:         true,
+				PublicKeyFile:   rsaKeyFile,
+				RollbackTimeout: 5 * time.Minute,
+			},
+			ExpectedError: "self-update: invalid public key: expected ed25519, got *rsa.PublicKey",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			config := getDefaultValidMinConfig()
+			config.SelfUpdate = tc.SelfUpdate
+
+			err := config.ParseAndValidate(true)
+
+			if tc.ExpectedError == "" {
+				require.NoError(t, err)
+				if tc.SelfUpdate.Enabled {
+					assert.Equal(t, publicKey, config.SelfUpdate.PublicKey)
+				}
+			} else {
+				require.EqualError(t, err, tc.ExpectedError)
+			}
+		})
+	}
+}
+
+func TestConfigApplyManagedOptions(t *testing.T) {
+	local := getDefaultValidMinConfig()
+	local.Client.Tags = []string{"local"}
+	local.RemoteCommands.Allow = []string{"^/usr/bin/.*"}
+	local.ManagedConfig = clientconfig.ManagedConfig{
+		Enabled: true,
+		Locked:  []string{"remote-commands.allow", "monitoring"},
+	}
+	require.NoError(t, local.ParseAndValidate(true))
+
+	effective, err := local.ApplyManagedOptions([]string{"linux"}, clientconfig.ManagedOptions{
+		"client.tags":           []interface{}{"managed"},
+		"remote-commands.allow": []interface{}{".*"},
+		"remote-commands.deny":  []interface{}{"^rm .*"},
+		"monitoring.enabled":    true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"managed"}, effective.Client.Tags)
+	assert.Equal(t, []string{"^/usr/bin/.*"}, effective.RemoteCommands.Allow)
+	assert.Equal(t, []*regexp.Regexp{regexp.MustCompile("^rm .*")}, effective.RemoteCommands.DenyRegexp)
+	assert.False(t, effective.Monitoring.Enabled)
+	assert.Equal(t, []string{"linux"}, effective.ManagedConfig.Profiles)
+	assert.Equal(t, []string{"client.tags", "remote-commands.deny"}, effective.ManagedConfig.Applied)
+	// the local config is not changed
+	assert.Equal(t, []string{"local"}, local.Client.Tags)
+	assert.Empty(t, local.RemoteCommands.Deny)
+	assert.Empty(t, local.ManagedConfig.Applied)
+
+	_, err = local.ApplyManagedOptions(nil, clientconfig.ManagedOptions{"remote-commands.deny": []interface{}{"("}})
+	assert.EqualError(t, err, "remote commands: deny regexp: invalid regular expression \"(\": error parsing regexp: missing closing ): `(`")
+
+	local.ManagedConfig.Locked = []string{"remote-command"}
+	assert.EqualError(t, local.ParseAndValidate(true), `managed-config: 'locked': "remote-command" is not an option or section that can be managed`)
+}
+
+func TestConfigParseAndValidateUserPolicy(t *testing.T) {
+	config := getDefaultValidMinConfig()
+	config.UserPolicy.AllowGroups = []string{"ops"}
+	config.UserPolicy.DenyUsers = []string{"guest"}
+	assert.NoError(t, config.ParseAndValidate(true))
+
+	config.UserPolicy.DenyUsers = []string{"guest", " "}
+	assert.EqualError(t, config.ParseAndValidate(true), `user-policy: 'deny_users': empty values are not allowed`)
+}
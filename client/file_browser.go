@@ -0,0 +1,215 @@
+package chclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+func (c *Client) handleSFTPChannel(ch ssh.NewChannel) {
+	config := c.config()
+	if !config.FileBrowser.Enabled {
+		c.Infof("Rejecting file browser session, file browser is disabled.")
+		c.rejectChannel(ch, ssh.Prohibited, "file browser is disabled")
+		return
+	}
+
+	// older servers don't send the request, the user policy rejects them if it's set
+	req := comm.SFTPRequest{}
+	if len(ch.ExtraData()) > 0 {
+		if err := json.Unmarshal(ch.ExtraData(), &req); err != nil {
+			c.rejectChannel(ch, ssh.ConnectionFailed, fmt.Sprintf("failed to decode sftp request: %v", err))
+			return
+		}
+	}
+	if err := config.CheckUserPolicy(req.User, req.UserGroups); err != nil {
+		c.Infof("Rejecting file browser session: %v", err)
+		c.rejectChannel(ch, ssh.Prohibited, err.Error())
+		return
+	}
+
+	stream, reqs, err := ch.Accept()
+	if err != nil {
+		c.Errorf("Failed to accept sftp channel: %v", err)
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	c.Debugf("File browser session started.")
+	server := sftp.NewRequestServer(stream, newFileBrowser(config.FileBrowser, c.Logger).handlers())
+	if err := server.Serve(); err != nil && err != io.EOF {
+		c.Errorf("File browser session failed: %v", err)
+	}
+	_ = server.Close()
+	c.Debugf("File browser session ended.")
+}
+
+// fileBrowser is a read-only sftp handler, it serves only files and directories that are accessible by the config
+type fileBrowser struct {
+	allow  []string
+	deny   []string
+	logger *logger.Logger
+}
+
+func newFileBrowser(config clientconfig.FileBrowserConfig, log *logger.Logger) *fileBrowser {
+	return &fileBrowser{
+		allow:  config.Allow,
+		deny:   config.Deny,
+		logger: log,
+	}
+}
+
+func (fb *fileBrowser) handlers() sftp.Handlers {
+	return sftp.Handlers{
+		FileGet:  fb,
+		FilePut:  fb,
+		FileCmd:  fb,
+		FileList: fb,
+	}
+}
+
+func (fb *fileBrowser) Fileread(r *sftp.Request) (io.ReaderAt, error) {
+	path, err := fb.resolve(r.Filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		_ = file.Close()
+		return nil, fmt.Errorf("%s is a directory", r.Filepath)
+	}
+
+	fb.logger.Infof("File %s is downloaded by the server.", path)
+	return file, nil
+}
+
+func (fb *fileBrowser) Filewrite(r *sftp.Request) (io.WriterAt, error) {
+	return nil, sftp.ErrSSHFxPermissionDenied
+}
+
+func (fb *fileBrowser) Filecmd(r *sftp.Request) error {
+	return sftp.ErrSSHFxPermissionDenied
+}
+
+func (fb *fileBrowser) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	path, err := fb.resolve(r.Filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	switch r.Method {
+	case "List":
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		infos := make([]os.FileInfo, 0, len(entries))
+		for _, entry := range entries {
+			if !fb.isAccessible(filepath.Join(path, entry.Name())) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				// the entry was removed in the meantime
+				continue
+			}
+			infos = append(infos, info)
+		}
+		return fileInfoLister(infos), nil
+	case "Stat", "Lstat":
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		return fileInfoLister{info}, nil
+	}
+
+	return nil, sftp.ErrSSHFxOpUnsupported
+}
+
+// resolve converts the sftp path to a local one and checks that both, the path and the target of it
+// when it's a symlink, are accessible.
+func (fb *fileBrowser) resolve(sftpPath string) (string, error) {
+	path := localPath(sftpPath)
+	if !fb.isAccessible(path) {
+		fb.logger.Infof("Rejecting access to %s based on file browser config.", path)
+		return "", sftp.ErrSSHFxPermissionDenied
+	}
+
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	if resolved != path && !fb.isAccessible(resolved) {
+		fb.logger.Infof("Rejecting access to %s linked to %s based on file browser config.", path, resolved)
+		return "", sftp.ErrSSHFxPermissionDenied
+	}
+
+	return resolved, nil
+}
+
+// isAccessible returns false if the path or one of its parents matches a deny pattern. If allow patterns are given,
+// the path or one of its parents must match one of them.
+func (fb *fileBrowser) isAccessible(path string) bool {
+	allowed := len(fb.allow) == 0
+	for {
+		if fb.matchAny(fb.deny, path) {
+			return false
+		}
+		if !allowed && fb.matchAny(fb.allow, path) {
+			allowed = true
+		}
+
+		parent := filepath.Dir(path)
+		if parent == path {
+			return allowed
+		}
+		path = parent
+	}
+}
+
+func (fb *fileBrowser) matchAny(globPatterns []string, path string) bool {
+	for _, p := range globPatterns {
+		matched, err := filepath.Match(p, path)
+		if err != nil {
+			fb.logger.Errorf("failed to match glob pattern %s against path %s: %v", p, path, err)
+			continue
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+type fileInfoLister []os.FileInfo
+
+func (l fileInfoLister) ListAt(ls []os.FileInfo, offset int64) (int, error) {
+	if offset >= int64(len(l)) {
+		return 0, io.EOF
+	}
+
+	n := copy(ls, l[offset:])
+	if n < len(ls) {
+		return n, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+// +build !windows
+
+package chclient
+
+var FileBrowserDenyGlobs = []string{
+	"/etc/shadow", "/etc/gshadow", "/etc/sudoers*", "/root/.ssh", "/home/*/.ssh", "/proc", "/sys", "/dev",
+}
+
+// localPath returns the path as it is, sftp paths are unix paths already
+func localPath(sftpPath string) string {
+	return sftpPath
+}
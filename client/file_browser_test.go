@@ -0,0 +1,166 @@
+package chclient
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+)
+
+func TestFileBrowserIsAccessible(t *testing.T) {
+	testCases := []struct {
+		name     string
+		allow    []string
+		deny     []string
+		path     string
+		expected bool
+	}{
+		{
+			name:     "no patterns",
+			path:     "/var/log/syslog",
+			expected: true,
+		},
+		{
+			name:     "denied",
+			deny:     []string{"/etc/shadow"},
+			path:     "/etc/shadow",
+			expected: false,
+		},
+		{
+			name:     "parent denied",
+			deny:     []string{"/home/*/.ssh"},
+			path:     "/home/user/.ssh/id_rsa",
+			expected: false,
+		},
+		{
+			name:     "not denied",
+			deny:     []string{"/home/*/.ssh"},
+			path:     "/home/user/.bashrc",
+			expected: true,
+		},
+		{
+			name:     "parent allowed",
+			allow:    []string{"/var/log"},
+			path:     "/var/log/nginx/access.log",
+			expected: true,
+		},
+		{
+			name:     "not allowed",
+			allow:    []string{"/var/log"},
+			path:     "/var/lib/rport",
+			expected: false,
+		},
+		{
+			name:     "allowed but denied",
+			allow:    []string{"/var/log"},
+			deny:     []string{"/var/log/secure*"},
+			path:     "/var/log/secure.1",
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fb := newFileBrowser(clientconfig.FileBrowserConfig{Allow: fromSlash(tc.allow), Deny: fromSlash(tc.deny)}, testLog)
+
+			assert.Equal(t, tc.expected, fb.isAccessible(filepath.FromSlash(tc.path)))
+		})
+	}
+}
+
+func fromSlash(paths []string) []string {
+	var result []string
+	for _, p := range paths {
+		result = append(result, filepath.FromSlash(p))
+	}
+	return result
+}
+
+func newTestFileBrowserClient(t *testing.T, config clientconfig.FileBrowserConfig) *sftp.Client {
+	serverConn, clientConn := net.Pipe()
+	server := sftp.NewRequestServer(serverConn, newFileBrowser(config, testLog).handlers())
+	go func() {
+		_ = server.Serve()
+	}()
+
+	client, err := sftp.NewClientPipe(clientConn, clientConn)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	return client
+}
+
+func TestFileBrowser(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "logs"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logs", "app.log"), []byte("started"), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "secrets"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "secrets", "key"), []byte("secret"), 0600))
+
+	client := newTestFileBrowserClient(t, clientconfig.FileBrowserConfig{
+		Deny: []string{filepath.Join(dir, "secrets")},
+	})
+	sftpDir := filepath.ToSlash(dir)
+
+	t.Run("list", func(t *testing.T) {
+		entries, err := client.ReadDir(sftpDir)
+		require.NoError(t, err)
+
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		sort.Strings(names)
+		assert.Equal(t, []string{"logs"}, names)
+	})
+
+	t.Run("stat", func(t *testing.T) {
+		info, err := client.Stat(sftpDir + "/logs/app.log")
+		require.NoError(t, err)
+		assert.EqualValues(t, 7, info.Size())
+		assert.False(t, info.IsDir())
+	})
+
+	t.Run("download", func(t *testing.T) {
+		file, err := client.Open(sftpDir + "/logs/app.log")
+		require.NoError(t, err)
+		defer file.Close()
+
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		assert.Equal(t, "started", string(content))
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		_, err := client.Open(sftpDir + "/secrets/key")
+		assert.ErrorIs(t, err, os.ErrPermission)
+
+		_, err = client.ReadDir(sftpDir + "/secrets")
+		assert.ErrorIs(t, err, os.ErrPermission)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := client.Stat(sftpDir + "/missing")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("write rejected", func(t *testing.T) {
+		_, err := client.Create(sftpDir + "/logs/new.log")
+		assert.ErrorIs(t, err, os.ErrPermission)
+
+		err = client.Remove(sftpDir + "/logs/app.log")
+		assert.ErrorIs(t, err, os.ErrPermission)
+	})
+}
@@ -0,0 +1,18 @@
+//go:build windows
+// +build windows
+
+package chclient
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+var FileBrowserDenyGlobs = []string{
+	`C:\Windows\System32\config`, `C:\Users\*\NTUSER.DAT`, `C:\pagefile.sys`, `C:\hiberfil.sys`,
+}
+
+// localPath converts a sftp path like /C:/Users to C:\Users
+func localPath(sftpPath string) string {
+	return filepath.FromSlash(strings.TrimPrefix(sftpPath, "/"))
+}
@@ -0,0 +1,105 @@
+package inventory
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/host"
+
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+func getDisks(ctx context.Context) ([]models.InventoryDisk, error) {
+	partitions, err := disk.PartitionsWithContext(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	disks := make([]models.InventoryDisk, 0, len(partitions))
+	for _, p := range partitions {
+		d := models.InventoryDisk{
+			Device:     p.Device,
+			Mountpoint: p.Mountpoint,
+			FSType:     p.Fstype,
+		}
+		if usage, err := disk.UsageWithContext(ctx, p.Mountpoint); err == nil {
+			d.TotalBytes = usage.Total
+		}
+		disks = append(disks, d)
+	}
+
+	return disks, nil
+}
+
+// getNICs returns the network interfaces having a hardware address
+func getNICs() ([]models.InventoryNIC, error) {
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	nics := make([]models.InventoryNIC, 0, len(interfaces))
+	for _, iface := range interfaces {
+		if len(iface.HardwareAddr) == 0 {
+			continue
+		}
+		nic := models.InventoryNIC{
+			Name:      iface.Name,
+			MAC:       iface.HardwareAddr.String(),
+			Addresses: []string{},
+		}
+		if addrs, err := iface.Addrs(); err == nil {
+			for _, addr := range addrs {
+				nic.Addresses = append(nic.Addresses, addr.String())
+			}
+		}
+		nics = append(nics, nic)
+	}
+
+	return nics, nil
+}
+
+func getUsers(ctx context.Context) ([]models.InventoryUser, error) {
+	stats, err := host.UsersWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]models.InventoryUser, 0, len(stats))
+	for _, s := range stats {
+		users = append(users, models.InventoryUser{
+			Name:     s.User,
+			Terminal: s.Terminal,
+			Host:     s.Host,
+		})
+	}
+
+	return users, nil
+}
+
+// parseTabSeparated parses lines of two values separated by a tab, lines without a tab are skipped
+func parseTabSeparated(output string) [][2]string {
+	var res [][2]string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "\t", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			continue
+		}
+		res = append(res, [2]string{strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])})
+	}
+	return res
+}
+
+func sortPackages(packages []models.InventoryPackage) {
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name != packages[j].Name {
+			return packages[i].Name < packages[j].Name
+		}
+		return packages[i].Version < packages[j].Version
+	})
+}
@@ -0,0 +1,35 @@
+//go:build linux
+// +build linux
+
+package inventory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudradar-monitoring/rport/client/updates"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+var dmiDir = "/sys/class/dmi/id"
+
+// getHardware reads the DMI data exposed by the kernel, the serial number is only readable by root
+func getHardware(ctx context.Context, runner updates.Runner) (models.InventoryHardware, error) {
+	read := func(name string) string {
+		data, err := os.ReadFile(filepath.Join(dmiDir, name))
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(data))
+	}
+
+	return models.InventoryHardware{
+		Manufacturer: read("sys_vendor"),
+		Model:        read("product_name"),
+		SerialNumber: read("product_serial"),
+		BIOSVendor:   read("bios_vendor"),
+		BIOSVersion:  read("bios_version"),
+	}, nil
+}
@@ -0,0 +1,16 @@
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package inventory
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudradar-monitoring/rport/client/updates"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+func getHardware(ctx context.Context, runner updates.Runner) (models.InventoryHardware, error) {
+	return models.InventoryHardware{}, errors.New("not supported on this platform")
+}
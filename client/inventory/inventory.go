@@ -0,0 +1,134 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/cloudradar-monitoring/rport/client/updates"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+const collectTimeout = 5 * time.Minute
+
+type Inventory struct {
+	// mtx protects both conn and current
+	mtx     sync.RWMutex
+	conn    ssh.Conn
+	current *models.Inventory
+
+	interval time.Duration
+	runner   updates.Runner
+	logger   *logger.Logger
+}
+
+func New(logger *logger.Logger, interval time.Duration) *Inventory {
+	return &Inventory{
+		interval: interval,
+		runner:   &updates.RunnerImpl{},
+		logger:   logger,
+	}
+}
+
+func (i *Inventory) Start(ctx context.Context) {
+	if i.interval <= 0 {
+		return
+	}
+
+	go i.refreshLoop(ctx)
+}
+
+func (i *Inventory) refreshLoop(ctx context.Context) {
+	for {
+		i.refresh(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(i.interval):
+		}
+	}
+}
+
+func (i *Inventory) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, collectTimeout)
+	defer cancel()
+
+	inventory := i.collect(ctx)
+	if len(inventory.Errors) > 0 {
+		i.logger.Infof("Inventory collected with errors: %v", inventory.Errors)
+	} else {
+		i.logger.Infof("Inventory collected, %d packages, %d services", len(inventory.Packages), len(inventory.Services))
+	}
+
+	i.mtx.Lock()
+	i.current = inventory
+	i.mtx.Unlock()
+
+	go i.sendInventory()
+}
+
+// collect runs all collectors, failing ones are reported in the errors of the inventory
+func (i *Inventory) collect(ctx context.Context) *models.Inventory {
+	inventory := &models.Inventory{}
+	addErr := func(category string, err error) {
+		inventory.Errors = append(inventory.Errors, fmt.Sprintf("%s: %v", category, err))
+	}
+
+	var err error
+	if inventory.Packages, err = getPackages(ctx, i.runner); err != nil {
+		addErr(models.InventoryCategoryPackage, err)
+	}
+	if inventory.Services, err = getServices(ctx, i.runner); err != nil {
+		addErr(models.InventoryCategoryService, err)
+	}
+	if inventory.Disks, err = getDisks(ctx); err != nil {
+		addErr(models.InventoryCategoryDisk, err)
+	}
+	if inventory.NICs, err = getNICs(); err != nil {
+		addErr(models.InventoryCategoryNIC, err)
+	}
+	if inventory.Hardware, err = getHardware(ctx, i.runner); err != nil {
+		addErr(models.InventoryCategoryHardware, err)
+	}
+	if inventory.Users, err = getUsers(ctx); err != nil {
+		addErr(models.InventoryCategoryUser, err)
+	}
+	inventory.Refreshed = time.Now()
+
+	return inventory
+}
+
+// sendInventory sends the inventory in background, it's called both after the inventory is collected or conn set
+func (i *Inventory) sendInventory() {
+	i.mtx.RLock()
+	defer i.mtx.RUnlock()
+
+	if i.conn != nil && i.current != nil {
+		data, err := json.Marshal(i.current)
+		if err != nil {
+			i.logger.Errorf("Could not marshal json for inventory: %v", err)
+			return
+		}
+
+		_, _, err = i.conn.SendRequest(comm.RequestTypeInventory, false, data)
+		if err != nil {
+			i.logger.Errorf("Could not send inventory: %v", err)
+			return
+		}
+	}
+}
+
+func (i *Inventory) SetConn(c ssh.Conn) {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+
+	i.conn = c
+	go i.sendInventory()
+}
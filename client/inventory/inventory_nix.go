@@ -0,0 +1,66 @@
+//go:build !windows
+// +build !windows
+
+package inventory
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/cloudradar-monitoring/rport/client/updates"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+var (
+	dpkgCmd      = []string{"dpkg-query", "-W", "-f", "${Package}\t${Version}\n"}
+	rpmCmd       = []string{"rpm", "-qa", "--qf", "%{NAME}\t%{VERSION}-%{RELEASE}\n"}
+	systemctlCmd = []string{"systemctl", "list-units", "--type=service", "--state=running", "--no-legend", "--plain"}
+)
+
+// getPackages lists the packages installed with dpkg or rpm, whichever is available first
+func getPackages(ctx context.Context, runner updates.Runner) ([]models.InventoryPackage, error) {
+	for _, cmd := range [][]string{dpkgCmd, rpmCmd} {
+		output, err := runner.Run(ctx, cmd...)
+		if err != nil {
+			continue
+		}
+		packages := make([]models.InventoryPackage, 0)
+		for _, p := range parseTabSeparated(output) {
+			packages = append(packages, models.InventoryPackage{
+				Name:    p[0],
+				Version: p[1],
+			})
+		}
+		sortPackages(packages)
+		return packages, nil
+	}
+
+	return nil, errors.New("no supported package manager found")
+}
+
+// getServices lists the running systemd services
+func getServices(ctx context.Context, runner updates.Runner) ([]models.InventoryService, error) {
+	output, err := runner.Run(ctx, systemctlCmd...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseSystemctlUnits(output), nil
+}
+
+// parseSystemctlUnits parses lines like "cron.service loaded active running Regular background program processing daemon"
+func parseSystemctlUnits(output string) []models.InventoryService {
+	services := make([]models.InventoryService, 0)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		services = append(services, models.InventoryService{
+			Name:  strings.TrimSuffix(fields[0], ".service"),
+			State: fields[3],
+		})
+	}
+	return services
+}
@@ -0,0 +1,91 @@
+//go:build !windows
+// +build !windows
+
+package inventory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+type mockRunner struct {
+	outputs map[string]string
+}
+
+func (r *mockRunner) Run(ctx context.Context, args ...string) (string, error) {
+	output, ok := r.outputs[strings.Join(args, " ")]
+	if !ok {
+		return "", errors.New("command not found")
+	}
+	return output, nil
+}
+
+func TestGetPackages(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Outputs       map[string]string
+		Expected      []models.InventoryPackage
+		ExpectedError string
+	}{
+		{
+			Name: "dpkg",
+			Outputs: map[string]string{
+				strings.Join(dpkgCmd, " "): "openssl\t3.0.2-0ubuntu1.10\nbash\t5.1-6ubuntu1\n\ninvalid line\n",
+			},
+			Expected: []models.InventoryPackage{
+				{Name: "bash", Version: "5.1-6ubuntu1"},
+				{Name: "openssl", Version: "3.0.2-0ubuntu1.10"},
+			},
+		},
+		{
+			Name: "rpm",
+			Outputs: map[string]string{
+				strings.Join(rpmCmd, " "): "openssl\t1.1.1k-9.el8\n",
+			},
+			Expected: []models.InventoryPackage{
+				{Name: "openssl", Version: "1.1.1k-9.el8"},
+			},
+		},
+		{
+			Name:          "no package manager",
+			ExpectedError: "no supported package manager found",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			packages, err := getPackages(context.Background(), &mockRunner{outputs: tc.Outputs})
+
+			if tc.ExpectedError != "" {
+				assert.EqualError(t, err, tc.ExpectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.Expected, packages)
+		})
+	}
+}
+
+func TestGetServices(t *testing.T) {
+	runner := &mockRunner{outputs: map[string]string{
+		strings.Join(systemctlCmd, " "): `cron.service    loaded active running Regular background program processing daemon
+ssh.service     loaded active running OpenBSD Secure Shell server
+`,
+	}}
+
+	services, err := getServices(context.Background(), runner)
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.InventoryService{
+		{Name: "cron", State: "running"},
+		{Name: "ssh", State: "running"},
+	}, services)
+}
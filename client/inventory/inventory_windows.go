@@ -0,0 +1,126 @@
+//go:build windows
+// +build windows
+
+package inventory
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+
+	"github.com/cloudradar-monitoring/rport/client/updates"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+var uninstallKeys = []string{
+	`SOFTWARE\Microsoft\Windows\CurrentVersion\Uninstall`,
+	`SOFTWARE\WOW6432Node\Microsoft\Windows\CurrentVersion\Uninstall`,
+}
+
+var serialNumberCmd = []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", "(Get-CimInstance -ClassName Win32_BIOS).SerialNumber"}
+
+// getPackages lists the programs registered for uninstallation, like the "Programs and Features" control panel
+func getPackages(ctx context.Context, runner updates.Runner) ([]models.InventoryPackage, error) {
+	packages := make([]models.InventoryPackage, 0)
+	seen := make(map[models.InventoryPackage]bool)
+	for _, path := range uninstallKeys {
+		key, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.ENUMERATE_SUB_KEYS)
+		if err != nil {
+			continue
+		}
+		names, err := key.ReadSubKeyNames(-1)
+		key.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			p, ok := readPackage(path + `\` + name)
+			if ok && !seen[p] {
+				seen[p] = true
+				packages = append(packages, p)
+			}
+		}
+	}
+	sortPackages(packages)
+
+	return packages, nil
+}
+
+func readPackage(path string) (models.InventoryPackage, bool) {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE)
+	if err != nil {
+		return models.InventoryPackage{}, false
+	}
+	defer key.Close()
+
+	name, _, err := key.GetStringValue("DisplayName")
+	if err != nil || name == "" {
+		return models.InventoryPackage{}, false
+	}
+	version, _, _ := key.GetStringValue("DisplayVersion")
+
+	return models.InventoryPackage{
+		Name:    name,
+		Version: version,
+	}, true
+}
+
+// getServices lists the running windows services
+func getServices(ctx context.Context, runner updates.Runner) ([]models.InventoryService, error) {
+	m, err := mgr.Connect()
+	if err != nil {
+		return nil, err
+	}
+	defer m.Disconnect()
+
+	names, err := m.ListServices()
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]models.InventoryService, 0)
+	for _, name := range names {
+		s, err := m.OpenService(name)
+		if err != nil {
+			continue
+		}
+		status, err := s.Query()
+		s.Close()
+		if err != nil || status.State != svc.Running {
+			continue
+		}
+		services = append(services, models.InventoryService{
+			Name:  name,
+			State: "running",
+		})
+	}
+
+	return services, nil
+}
+
+func getHardware(ctx context.Context, runner updates.Runner) (models.InventoryHardware, error) {
+	hardware := models.InventoryHardware{}
+
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `HARDWARE\DESCRIPTION\System\BIOS`, registry.QUERY_VALUE)
+	if err != nil {
+		return hardware, err
+	}
+	defer key.Close()
+
+	hardware.Manufacturer, _, _ = key.GetStringValue("SystemManufacturer")
+	hardware.Model, _, _ = key.GetStringValue("SystemProductName")
+	hardware.BIOSVendor, _, _ = key.GetStringValue("BIOSVendor")
+	hardware.BIOSVersion, _, _ = key.GetStringValue("BIOSVersion")
+
+	serial, err := runner.Run(ctx, serialNumberCmd...)
+	if err != nil {
+		return hardware, err
+	}
+	hardware.SerialNumber = strings.TrimSpace(serial)
+
+	return hardware, nil
+}
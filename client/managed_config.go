@@ -0,0 +1,60 @@
+package chclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+)
+
+// handlePutManagedConfigRequest applies the configuration profiles pushed by the server to the local config and
+// replies with the effective config. Profiles are always applied to the local config, so options removed from
+// the profiles fall back to their local value.
+func (c *Client) handlePutManagedConfigRequest(ctx context.Context, payload []byte) (*clientconfig.Config, error) {
+	if !c.localConfig.ManagedConfig.Enabled {
+		return nil, errors.New("managed config is disabled")
+	}
+
+	req := &comm.ManagedConfigRequest{}
+	if err := json.Unmarshal(payload, req); err != nil {
+		return nil, fmt.Errorf("failed to decode %T: %v", req, err)
+	}
+
+	effective, err := c.localConfig.ApplyManagedOptions(req.Profiles, req.Options)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration profiles %s: %v", strings.Join(req.Profiles, ", "), err)
+	}
+
+	monitoringChanged := !reflect.DeepEqual(c.config().Monitoring, effective.Monitoring)
+	c.setConfig(effective)
+	c.Infof("Configuration profiles %v applied, managed options: %v", req.Profiles, effective.ManagedConfig.Applied)
+
+	if monitoringChanged {
+		c.monitor.Stop()
+		c.monitor.SetConfig(effective.Monitoring)
+		if c.serverCapabilities != nil {
+			c.afterPutCapabilities(ctx)
+		}
+	}
+
+	return effective.Config, nil
+}
+
+// config returns the effective config. It's replaced as a whole when configuration profiles are applied, so callers
+// should read it once and use the returned config for the rest of a request.
+func (c *Client) config() *ClientConfigHolder {
+	c.configMu.RLock()
+	defer c.configMu.RUnlock()
+	return c.configHolder
+}
+
+func (c *Client) setConfig(config *ClientConfigHolder) {
+	c.configMu.Lock()
+	defer c.configMu.Unlock()
+	c.configHolder = config
+}
@@ -0,0 +1,72 @@
+package chclient
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+)
+
+func TestHandlePutManagedConfigRequest(t *testing.T) {
+	config := getDefaultValidMinConfig()
+	config.Client.Tags = []string{"local"}
+	config.FileBrowser.Enabled = true
+	config.ManagedConfig.Enabled = true
+	require.NoError(t, config.ParseAndValidate(true))
+	c := &Client{
+		Logger:       testLog,
+		configHolder: &config,
+		localConfig:  &ClientConfigHolder{Config: config.Config.Clone()},
+	}
+
+	// the config is read by the handlers of other requests while the profiles are applied
+	started, stop := make(chan struct{}), make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			if i == 1 {
+				close(started)
+			}
+			select {
+			case <-stop:
+				return
+			default:
+				_ = c.config().FileBrowser.Enabled
+				_ = c.isAllowed("/usr/bin/ls")
+			}
+		}
+	}()
+
+	payload, err := json.Marshal(comm.ManagedConfigRequest{
+		Profiles: []string{"linux"},
+		Options: clientconfig.ManagedOptions{
+			"client.tags":          []interface{}{"managed"},
+			"file-browser.enabled": false,
+			"remote-commands.deny": []interface{}{"^/usr/bin/.*"},
+		},
+	})
+	require.NoError(t, err)
+	<-started
+	effective, err := c.handlePutManagedConfigRequest(context.Background(), payload)
+	close(stop)
+	wg.Wait()
+	require.NoError(t, err)
+
+	assert.Same(t, effective, c.config().Config)
+	assert.Equal(t, []string{"managed"}, c.config().Client.Tags)
+	assert.False(t, c.config().FileBrowser.Enabled)
+	assert.False(t, c.isAllowed("/usr/bin/ls"))
+	// neither the local config nor the config the client was started with are changed
+	assert.Equal(t, []string{"local"}, c.localConfig.Client.Tags)
+	assert.True(t, c.localConfig.FileBrowser.Enabled)
+	assert.Equal(t, []string{"local"}, config.Client.Tags)
+	assert.True(t, config.FileBrowser.Enabled)
+}
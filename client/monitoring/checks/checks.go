@@ -0,0 +1,204 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+const (
+	// MaxOutputLength limits the output stored for a single check result
+	MaxOutputLength = 1024
+	// maxPendingResults limits the results kept while they can't be sent, the oldest ones are dropped
+	maxPendingResults = 1000
+)
+
+// Executor runs the command of a check and returns its stdout and exit code
+type Executor interface {
+	ExecuteCheck(ctx context.Context, check clientconfig.CheckConfig) (output string, exitCode int, err error)
+}
+
+// Runner executes the configured checks in their intervals and collects the results until they are taken
+type Runner struct {
+	logger   *logger.Logger
+	checks   []clientconfig.CheckConfig
+	executor Executor
+
+	mtx     sync.Mutex
+	pending []*models.CheckResult
+}
+
+func NewRunner(logger *logger.Logger, checks []clientconfig.CheckConfig, executor Executor) *Runner {
+	return &Runner{
+		logger:   logger,
+		checks:   checks,
+		executor: executor,
+	}
+}
+
+// Start runs each check in a separate goroutine until ctx is done
+func (r *Runner) Start(ctx context.Context) {
+	for _, check := range r.checks {
+		go r.loop(ctx, check)
+	}
+}
+
+// Results returns the results collected since the previous call
+func (r *Runner) Results() []*models.CheckResult {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	results := r.pending
+	r.pending = nil
+	return results
+}
+
+func (r *Runner) loop(ctx context.Context, check clientconfig.CheckConfig) {
+	for {
+		r.add(r.Run(ctx, check))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(check.Interval):
+		}
+	}
+}
+
+func (r *Runner) add(result *models.CheckResult) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	if len(r.pending) >= maxPendingResults {
+		r.pending = r.pending[1:]
+	}
+	r.pending = append(r.pending, result)
+}
+
+// Run executes the check once and converts the output to a result
+func (r *Runner) Run(ctx context.Context, check clientconfig.CheckConfig) *models.CheckResult {
+	timestamp := time.Now().UTC()
+
+	execCtx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+
+	output, exitCode, err := r.executor.ExecuteCheck(execCtx, check)
+	if execCtx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timeout after %s", check.Timeout)
+	}
+	if err != nil {
+		r.logger.Errorf("Check %s failed: %v", check.Name, err)
+		return &models.CheckResult{
+			Name:      check.Name,
+			Timestamp: timestamp,
+			Status:    models.CheckStatusUnknown,
+			Output:    truncate(err.Error()),
+		}
+	}
+
+	var result *models.CheckResult
+	if check.Type == clientconfig.CheckTypeNagios {
+		result = ParseNagiosOutput(output, exitCode)
+	} else {
+		result = ParseValueOutput(output, exitCode)
+	}
+	result.Name = check.Name
+	result.Timestamp = timestamp
+	return result
+}
+
+// ParseValueOutput expects a number as the first word of the output and a zero exit code
+func ParseValueOutput(output string, exitCode int) *models.CheckResult {
+	text := strings.TrimSpace(output)
+	if exitCode != 0 {
+		return &models.CheckResult{
+			Status: models.CheckStatusUnknown,
+			Output: truncate(fmt.Sprintf("exit code %d: %s", exitCode, firstLine(text))),
+		}
+	}
+
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return &models.CheckResult{
+			Status: models.CheckStatusUnknown,
+			Output: "empty output",
+		}
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return &models.CheckResult{
+			Status: models.CheckStatusUnknown,
+			Output: truncate(fmt.Sprintf("invalid value: %s", firstLine(text))),
+		}
+	}
+
+	return &models.CheckResult{
+		Value:  &value,
+		Status: models.CheckStatusOK,
+		Output: truncate(firstLine(text)),
+	}
+}
+
+// ParseNagiosOutput converts the output of a nagios plugin, see https://nagios-plugins.org/doc/guidelines.html#PLUGOUTPUT.
+// The status is taken from the exit code, the value from the first performance data item of the first line.
+func ParseNagiosOutput(output string, exitCode int) *models.CheckResult {
+	status := exitCode
+	if status < models.CheckStatusOK || status > models.CheckStatusUnknown {
+		status = models.CheckStatusUnknown
+	}
+
+	text, perfData, _ := strings.Cut(firstLine(output), "|")
+
+	return &models.CheckResult{
+		Value:  parsePerfDataValue(perfData),
+		Status: status,
+		Output: truncate(strings.TrimSpace(text)),
+	}
+}
+
+// parsePerfDataValue returns the value of the first item formatted as 'label'=value[UOM];[warn];[crit];[min];[max]
+func parsePerfDataValue(perfData string) *float64 {
+	perfData = strings.TrimSpace(perfData)
+	if strings.HasPrefix(perfData, "'") {
+		// quoted labels might contain spaces and '='
+		end := strings.Index(perfData[1:], "'")
+		if end < 0 {
+			return nil
+		}
+		perfData = perfData[end+2:]
+	}
+
+	_, rest, ok := strings.Cut(perfData, "=")
+	if !ok {
+		return nil
+	}
+	rest, _, _ = strings.Cut(rest, " ")
+	rest, _, _ = strings.Cut(rest, ";")
+	rest = strings.TrimRight(rest, "%abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+
+	value, err := strconv.ParseFloat(rest, 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return strings.TrimRight(line, "\r")
+}
+
+func truncate(text string) string {
+	if len(text) > MaxOutputLength {
+		// don't leave a partial multi-byte character at the end
+		return strings.ToValidUTF8(text[:MaxOutputLength], "")
+	}
+	return text
+}
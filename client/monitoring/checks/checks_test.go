@@ -0,0 +1,183 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+var testLog = logger.NewLogger("checks", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug)
+
+type executorMock struct {
+	output   string
+	exitCode int
+	err      error
+	delay    time.Duration
+}
+
+func (e *executorMock) ExecuteCheck(ctx context.Context, check clientconfig.CheckConfig) (string, int, error) {
+	select {
+	case <-ctx.Done():
+		return "", -1, nil
+	case <-time.After(e.delay):
+	}
+	return e.output, e.exitCode, e.err
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestParseValueOutput(t *testing.T) {
+	testCases := []struct {
+		name     string
+		output   string
+		exitCode int
+		expected *models.CheckResult
+	}{
+		{
+			name:     "integer",
+			output:   "42\n",
+			expected: &models.CheckResult{Value: floatPtr(42), Status: models.CheckStatusOK, Output: "42"},
+		},
+		{
+			name:     "float with unit",
+			output:   " 0.25 seconds\nsecond line",
+			expected: &models.CheckResult{Value: floatPtr(0.25), Status: models.CheckStatusOK, Output: "0.25 seconds"},
+		},
+		{
+			name:     "no number",
+			output:   "queue not found",
+			expected: &models.CheckResult{Status: models.CheckStatusUnknown, Output: "invalid value: queue not found"},
+		},
+		{
+			name:     "empty",
+			output:   "",
+			expected: &models.CheckResult{Status: models.CheckStatusUnknown, Output: "empty output"},
+		},
+		{
+			name:     "failed",
+			output:   "12",
+			exitCode: 1,
+			expected: &models.CheckResult{Status: models.CheckStatusUnknown, Output: "exit code 1: 12"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ParseValueOutput(tc.output, tc.exitCode))
+		})
+	}
+}
+
+func TestParseNagiosOutput(t *testing.T) {
+	testCases := []struct {
+		name     string
+		output   string
+		exitCode int
+		expected *models.CheckResult
+	}{
+		{
+			name:     "ok with perfdata",
+			output:   "OK - load average: 0.15, 0.10, 0.05|load1=0.150;5.000;10.000;0; load5=0.100;4.000;6.000;0;\n",
+			expected: &models.CheckResult{Value: floatPtr(0.15), Status: models.CheckStatusOK, Output: "OK - load average: 0.15, 0.10, 0.05"},
+		},
+		{
+			name:     "warning with unit and quoted label",
+			output:   "DISK WARNING - free space: / 1024 MB | 'used space /'=85%;80;90;0;100",
+			exitCode: 1,
+			expected: &models.CheckResult{Value: floatPtr(85), Status: models.CheckStatusWarning, Output: "DISK WARNING - free space: / 1024 MB"},
+		},
+		{
+			name:     "critical without perfdata",
+			output:   "PROCS CRITICAL: 0 processes\nlong output",
+			exitCode: 2,
+			expected: &models.CheckResult{Status: models.CheckStatusCritical, Output: "PROCS CRITICAL: 0 processes"},
+		},
+		{
+			name:     "invalid exit code",
+			output:   "command not found",
+			exitCode: 127,
+			expected: &models.CheckResult{Status: models.CheckStatusUnknown, Output: "command not found"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ParseNagiosOutput(tc.output, tc.exitCode))
+		})
+	}
+}
+
+func TestRunnerRun(t *testing.T) {
+	check := clientconfig.CheckConfig{
+		Name:    "queue",
+		Type:    clientconfig.CheckTypeValue,
+		Timeout: 50 * time.Millisecond,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		runner := NewRunner(testLog, nil, &executorMock{output: "7"})
+
+		result := runner.Run(context.Background(), check)
+
+		assert.Equal(t, "queue", result.Name)
+		assert.False(t, result.Timestamp.IsZero())
+		assert.Equal(t, floatPtr(7), result.Value)
+		assert.Equal(t, models.CheckStatusOK, result.Status)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		runner := NewRunner(testLog, nil, &executorMock{err: errors.New("scripts dir not found")})
+
+		result := runner.Run(context.Background(), check)
+
+		assert.Nil(t, result.Value)
+		assert.Equal(t, models.CheckStatusUnknown, result.Status)
+		assert.Equal(t, "scripts dir not found", result.Output)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		runner := NewRunner(testLog, nil, &executorMock{output: "7", delay: time.Second})
+
+		result := runner.Run(context.Background(), check)
+
+		assert.Nil(t, result.Value)
+		assert.Equal(t, models.CheckStatusUnknown, result.Status)
+		assert.Equal(t, "timeout after 50ms", result.Output)
+	})
+}
+
+func TestRunnerResults(t *testing.T) {
+	check := clientconfig.CheckConfig{
+		Name:     "queue",
+		Type:     clientconfig.CheckTypeValue,
+		Interval: time.Hour,
+		Timeout:  time.Second,
+	}
+	runner := NewRunner(testLog, []clientconfig.CheckConfig{check}, &executorMock{output: "7"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runner.Start(ctx)
+
+	var results []*models.CheckResult
+	require.Eventually(t, func() bool {
+		results = runner.Results()
+		return len(results) > 0
+	}, time.Second, 10*time.Millisecond)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "queue", results[0].Name)
+	assert.Empty(t, runner.Results())
+}
@@ -8,6 +8,7 @@ import (
 
 	"golang.org/x/crypto/ssh"
 
+	"github.com/cloudradar-monitoring/rport/client/monitoring/checks"
 	"github.com/cloudradar-monitoring/rport/client/monitoring/fs"
 	"github.com/cloudradar-monitoring/rport/client/monitoring/networking"
 	"github.com/cloudradar-monitoring/rport/client/monitoring/processes"
@@ -29,19 +30,36 @@ type Monitor struct {
 	fileSystemWatcher *fs.FileSystemWatcher
 	processHandler    *processes.ProcessHandler
 	netHandler        *networking.NetHandler
+	checkRunner       *checks.Runner
+	checkExecutor     checks.Executor
 }
 
-func NewMonitor(logger *logger.Logger, config clientconfig.MonitoringConfig, systemInfo system.SysInfo) *Monitor {
+func NewMonitor(logger *logger.Logger, config clientconfig.MonitoringConfig, systemInfo system.SysInfo, checkExecutor checks.Executor) *Monitor {
+	m := &Monitor{logger: logger, systemInfo: systemInfo, checkExecutor: checkExecutor}
+	m.SetConfig(config)
+	return m
+}
+
+// SetConfig replaces the monitoring config, it's applied on the next start
+func (m *Monitor) SetConfig(config clientconfig.MonitoringConfig) {
 	fsWatcher := fs.NewWatcher(fs.FileSystemWatcherConfig{
 		TypeInclude:                 config.FSTypeInclude,
 		PathExclude:                 config.FSPathExclude,
 		PathExcludeRecurse:          config.FSPathExcludeRecurse,
 		Metrics:                     fs.DefaultMetrics(),
 		IdentifyMountpointsByDevice: config.FSIdentifyMountpointsByDevice,
-	}, logger)
-	processHandler := processes.NewProcessHandler(config, logger)
+	}, m.logger)
+	processHandler := processes.NewProcessHandler(config, m.logger)
 	netHandler := networking.NewNetHandler(&config)
-	return &Monitor{logger: logger, config: config, systemInfo: systemInfo, fileSystemWatcher: fsWatcher, processHandler: processHandler, netHandler: netHandler}
+	checkRunner := checks.NewRunner(m.logger, config.Checks, m.checkExecutor)
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.config = config
+	m.fileSystemWatcher = fsWatcher
+	m.processHandler = processHandler
+	m.netHandler = netHandler
+	m.checkRunner = checkRunner
 }
 
 func (m *Monitor) Start(ctx context.Context) {
@@ -51,7 +69,8 @@ func (m *Monitor) Start(ctx context.Context) {
 
 	ctx, m.stopFn = context.WithCancel(ctx)
 
-	go m.refreshLoop(ctx)
+	m.checkRunner.Start(ctx)
+	go m.refreshLoop(ctx, m.config.Interval)
 	m.logger.Debugf("Monitor started")
 }
 
@@ -61,10 +80,11 @@ func (m *Monitor) Stop() {
 	}
 
 	m.stopFn()
+	m.stopFn = nil
 	m.logger.Debugf("Monitor stopped")
 }
 
-func (m *Monitor) refreshLoop(ctx context.Context) {
+func (m *Monitor) refreshLoop(ctx context.Context, interval time.Duration) {
 	for {
 		m.refreshMeasurement(ctx)
 
@@ -72,7 +92,7 @@ func (m *Monitor) refreshLoop(ctx context.Context) {
 		case <-ctx.Done():
 			m.logger.Errorf("Monitoring ended by context.Done")
 			return
-		case <-time.After(m.config.Interval):
+		case <-time.After(interval):
 		}
 	}
 }
@@ -130,6 +150,11 @@ func (m *Monitor) createMeasurement(ctx context.Context) *models.Measurement {
 	} else {
 		m.logger.Debugf("Cannot measure network bandwidth:" + err.Error())
 	}
+
+	// keep the check results for the next measurement while disconnected
+	if m.conn != nil {
+		newMeasurement.Checks = m.checkRunner.Results()
+	}
 	return newMeasurement
 }
 
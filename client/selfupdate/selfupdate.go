@@ -0,0 +1,364 @@
+package selfupdate
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+const (
+	stateFileName = "self-update.json"
+	newSuffix     = ".new"
+	backupSuffix  = ".old"
+)
+
+// SourceFileProvider opens files on the server
+type SourceFileProvider interface {
+	Open(path string) (io.ReadCloser, error)
+}
+
+// state is persisted while an update is pending, so the new binary can roll back if it doesn't connect
+type state struct {
+	Version         string    `json:"version"`
+	PreviousVersion string    `json:"previous_version"`
+	Executable      string    `json:"executable"`
+	Backup          string    `json:"backup"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Updater replaces the client binary by a signed one from the server and restarts the client. If the new binary
+// doesn't connect to the server within the rollback timeout, the previous binary is restored.
+type Updater struct {
+	logger    *logger.Logger
+	config    clientconfig.SelfUpdateConfig
+	stateFile string
+	version   string
+	// executable is the path of the running binary, symlinks resolved
+	executable    string
+	executableErr error
+	now           func() time.Time
+
+	mu            sync.Mutex
+	restart       func() error
+	updating      bool
+	pending       *state
+	rollbackTimer *time.Timer
+}
+
+func New(logger *logger.Logger, config clientconfig.SelfUpdateConfig, dataDir, version string) *Updater {
+	u := &Updater{
+		logger:    logger,
+		config:    config,
+		stateFile: filepath.Join(dataDir, stateFileName),
+		version:   version,
+		now:       time.Now,
+	}
+	u.executable, u.executableErr = os.Executable()
+	if u.executableErr == nil {
+		u.executable, u.executableErr = filepath.EvalSymlinks(u.executable)
+	}
+
+	return u
+}
+
+// SetRestart sets the func restarting the client, updates are only possible if it's set
+func (u *Updater) SetRestart(restart func() error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.restart = restart
+}
+
+// Start checks if the client was just updated and starts the rollback timer if so
+func (u *Updater) Start() {
+	st, err := u.loadState()
+	if err != nil {
+		u.logger.Errorf("Failed to read self-update state: %v", err)
+		return
+	}
+	if st == nil {
+		return
+	}
+
+	if st.Version != u.version {
+		// the update was rolled back or the restart didn't happen
+		u.logger.Errorf("Update from version %s to %s was not completed, running version %s", st.PreviousVersion, st.Version, u.version)
+		u.removeState()
+		return
+	}
+
+	deadline := st.UpdatedAt.Add(u.config.RollbackTimeout)
+	u.logger.Infof("Updated from version %s to %s, waiting for a connection to the server until %s", st.PreviousVersion, st.Version, deadline)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.pending = st
+	u.rollbackTimer = time.AfterFunc(deadline.Sub(u.now()), u.rollback)
+}
+
+// Confirm completes a pending update, it's called once the client is connected to the server
+func (u *Updater) Confirm() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.pending == nil {
+		return
+	}
+	if !u.rollbackTimer.Stop() {
+		// rollback is already in progress
+		return
+	}
+
+	if err := os.Remove(u.pending.Backup); err != nil && !os.IsNotExist(err) {
+		u.logger.Errorf("Failed to remove backup of previous version: %v", err)
+	}
+	u.removeState()
+	u.logger.Infof("Update from version %s to %s completed", u.pending.PreviousVersion, u.pending.Version)
+	u.pending = nil
+}
+
+// HandleRequest starts an update requested by the server, the binary is downloaded in the background
+func (u *Updater) HandleRequest(payload []byte, files SourceFileProvider) error {
+	if !u.config.Enabled {
+		return errors.New("client self-update is disabled")
+	}
+	if u.executableErr != nil {
+		return fmt.Errorf("failed to find the client executable: %v", u.executableErr)
+	}
+
+	req := &comm.SelfUpdateRequest{}
+	if err := json.Unmarshal(payload, req); err != nil {
+		return fmt.Errorf("failed to decode %T: %v", req, err)
+	}
+	if req.Version == u.version {
+		return fmt.Errorf("version %s is already running", req.Version)
+	}
+	older, err := isOlder(req.Version, u.version)
+	if err != nil {
+		return err
+	}
+	if older {
+		return fmt.Errorf("downgrade from version %s to %s is not allowed", u.version, req.Version)
+	}
+	if req.OS != runtime.GOOS || req.Arch != runtime.GOARCH {
+		return fmt.Errorf("binary for %s/%s can't run on %s/%s", req.OS, req.Arch, runtime.GOOS, runtime.GOARCH)
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.restart == nil {
+		return errors.New("client self-update requires rport to run as a service")
+	}
+	if u.updating || u.pending != nil {
+		return errors.New("another update is in progress")
+	}
+	u.updating = true
+
+	go u.update(req, files)
+
+	return nil
+}
+
+func (u *Updater) update(req *comm.SelfUpdateRequest, files SourceFileProvider) {
+	defer func() {
+		u.mu.Lock()
+		u.updating = false
+		u.mu.Unlock()
+	}()
+
+	u.logger.Infof("Updating from version %s to %s", u.version, req.Version)
+	st, err := u.install(req, files)
+	if err != nil {
+		u.logger.Errorf("Failed to update to version %s: %v", req.Version, err)
+		return
+	}
+
+	u.logger.Infof("Version %s installed, restarting", req.Version)
+	if err := u.restart(); err != nil {
+		u.logger.Errorf("Failed to restart: %v", err)
+		// the running process is still the previous version, restore its binary
+		if err := u.restore(st); err != nil {
+			u.logger.Errorf("Failed to restore previous version: %v", err)
+		}
+	}
+}
+
+// install verifies the binary and replaces the running one by it, the running one is kept as backup
+func (u *Updater) install(req *comm.SelfUpdateRequest, files SourceFileProvider) (*state, error) {
+	src, err := files.Open(req.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %v", req.Path, err)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, req.Size+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %v", req.Path, err)
+	}
+	if int64(len(data)) != req.Size {
+		return nil, fmt.Errorf("size mismatch: expected %d bytes, got %d", req.Size, len(data))
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != req.SHA256 {
+		return nil, errors.New("checksum mismatch")
+	}
+	// the signature covers the checksum together with the version and platform, not the binary alone
+	if !ed25519.Verify(u.config.PublicKey, req.Manifest(), req.Signature) {
+		return nil, errors.New("invalid signature")
+	}
+
+	info, err := os.Stat(u.executable)
+	if err != nil {
+		return nil, err
+	}
+	newPath := u.executable + newSuffix
+	if err := writeFile(newPath, data, info.Mode()); err != nil {
+		return nil, err
+	}
+
+	st := &state{
+		Version:         req.Version,
+		PreviousVersion: u.version,
+		Executable:      u.executable,
+		Backup:          u.executable + backupSuffix,
+		UpdatedAt:       u.now(),
+	}
+	if err := u.saveState(st); err != nil {
+		_ = os.Remove(newPath)
+		return nil, err
+	}
+
+	// renaming the running binary is possible on all platforms, unlike overwriting it
+	if err := os.Rename(u.executable, st.Backup); err != nil {
+		_ = os.Remove(newPath)
+		u.removeState()
+		return nil, err
+	}
+	if err := os.Rename(newPath, u.executable); err != nil {
+		if restoreErr := u.restore(st); restoreErr != nil {
+			u.logger.Errorf("Failed to restore previous version: %v", restoreErr)
+		}
+		return nil, err
+	}
+
+	return st, nil
+}
+
+func (u *Updater) rollback() {
+	u.mu.Lock()
+	st := u.pending
+	u.pending = nil
+	restart := u.restart
+	u.mu.Unlock()
+
+	u.logger.Errorf("Not connected within %s after update to version %s, rolling back to %s", u.config.RollbackTimeout, st.Version, st.PreviousVersion)
+	if err := u.restore(st); err != nil {
+		u.logger.Errorf("Failed to roll back: %v", err)
+		return
+	}
+
+	if restart == nil {
+		u.logger.Errorf("Version %s restored, restart rport to run it", st.PreviousVersion)
+		return
+	}
+	if err := restart(); err != nil {
+		u.logger.Errorf("Failed to restart: %v", err)
+	}
+}
+
+// restore puts the backup of the previous binary back in place
+func (u *Updater) restore(st *state) error {
+	// the binary in place might be running, on windows it can be renamed but not overwritten
+	replaced := st.Executable + newSuffix
+	if err := os.Rename(st.Executable, replaced); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Rename(st.Backup, st.Executable); err != nil {
+		return err
+	}
+	if err := os.Remove(replaced); err != nil && !os.IsNotExist(err) {
+		u.logger.Debugf("Failed to remove %s: %v", replaced, err)
+	}
+	u.removeState()
+	return nil
+}
+
+func (u *Updater) loadState() (*state, error) {
+	data, err := os.ReadFile(u.stateFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	st := &state{}
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, err
+	}
+
+	return st, nil
+}
+
+func (u *Updater) saveState(st *state) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	return writeFile(u.stateFile, data, 0600)
+}
+
+func (u *Updater) removeState() {
+	if err := os.Remove(u.stateFile); err != nil && !os.IsNotExist(err) {
+		u.logger.Errorf("Failed to remove self-update state: %v", err)
+	}
+}
+
+// isOlder tells if version a is older than b, it fails if either version can't be parsed, so an update can't
+// bypass the downgrade check with an invalid version
+func isOlder(a, b string) (bool, error) {
+	va, err := version.NewVersion(a)
+	if err != nil {
+		return false, fmt.Errorf("invalid version %q: %v", a, err)
+	}
+	vb, err := version.NewVersion(b)
+	if err != nil {
+		return false, fmt.Errorf("invalid version %q of the running client: %v", b, err)
+	}
+
+	return va.LessThan(vb), nil
+}
+
+// writeFile writes and syncs a file, so it's complete on disk before it's renamed
+func writeFile(path string, data []byte, mode os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
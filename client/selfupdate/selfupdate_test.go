@@ -0,0 +1,301 @@
+package selfupdate
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+var testLog = logger.NewLogger("self-update", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug)
+
+type fileProviderMock map[string][]byte
+
+func (m fileProviderMock) Open(path string) (io.ReadCloser, error) {
+	data, ok := m[path]
+	if !ok {
+		return nil, errors.New("file does not exist")
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+type testEnv struct {
+	dataDir    string
+	executable string
+	publicKey  ed25519.PublicKey
+	privateKey ed25519.PrivateKey
+	restarts   chan struct{}
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	dir := t.TempDir()
+	env := &testEnv{
+		dataDir:    filepath.Join(dir, "data"),
+		executable: filepath.Join(dir, "rport"),
+		restarts:   make(chan struct{}, 1),
+	}
+	require.NoError(t, os.Mkdir(env.dataDir, 0700))
+	require.NoError(t, os.WriteFile(env.executable, []byte("0.9.0"), 0755))
+	var err error
+	env.publicKey, env.privateKey, err = ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	return env
+}
+
+func (env *testEnv) newUpdater(version string, rollbackTimeout time.Duration) *Updater {
+	u := New(testLog, clientconfig.SelfUpdateConfig{
+		Enabled:         true,
+		RollbackTimeout: rollbackTimeout,
+		PublicKey:       env.publicKey,
+	}, env.dataDir, version)
+	u.executable = env.executable
+	u.executableErr = nil
+	u.SetRestart(func() error {
+		env.restarts <- struct{}{}
+		return nil
+	})
+
+	return u
+}
+
+func (env *testEnv) request(t *testing.T, version string, data []byte) ([]byte, fileProviderMock) {
+	sum := sha256.Sum256(data)
+	req := &comm.SelfUpdateRequest{
+		Version: version,
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+		Path:    "/var/lib/rport/client-binaries/" + version + "/rport",
+		Size:    int64(len(data)),
+		SHA256:  hex.EncodeToString(sum[:]),
+	}
+	req.Signature = ed25519.Sign(env.privateKey, req.Manifest())
+	payload, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	return payload, fileProviderMock{req.Path: data}
+}
+
+func (env *testEnv) waitForRestart(t *testing.T) {
+	select {
+	case <-env.restarts:
+	case <-time.After(5 * time.Second):
+		t.Fatal("client was not restarted")
+	}
+}
+
+func assertFileContent(t *testing.T, path, expected string) {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(data))
+}
+
+func TestUpdateAndConfirm(t *testing.T) {
+	env := newTestEnv(t)
+	u := env.newUpdater("0.9.0", time.Minute)
+
+	payload, files := env.request(t, "0.9.1", []byte("0.9.1"))
+	require.NoError(t, u.HandleRequest(payload, files))
+	env.waitForRestart(t)
+
+	assertFileContent(t, env.executable, "0.9.1")
+	assertFileContent(t, env.executable+backupSuffix, "0.9.0")
+
+	// the new version is started
+	updated := env.newUpdater("0.9.1", time.Minute)
+	updated.Start()
+	require.NotNil(t, updated.pending)
+
+	updated.Confirm()
+
+	assert.Nil(t, updated.pending)
+	assert.NoFileExists(t, env.executable+backupSuffix)
+	assert.NoFileExists(t, filepath.Join(env.dataDir, stateFileName))
+	assertFileContent(t, env.executable, "0.9.1")
+}
+
+func TestUpdateRollback(t *testing.T) {
+	env := newTestEnv(t)
+	u := env.newUpdater("0.9.0", time.Minute)
+
+	payload, files := env.request(t, "0.9.1", []byte("0.9.1"))
+	require.NoError(t, u.HandleRequest(payload, files))
+	env.waitForRestart(t)
+
+	// the new version is started but doesn't connect
+	updated := env.newUpdater("0.9.1", 100*time.Millisecond)
+	updated.Start()
+	env.waitForRestart(t)
+
+	assertFileContent(t, env.executable, "0.9.0")
+	assert.NoFileExists(t, env.executable+backupSuffix)
+	assert.NoFileExists(t, filepath.Join(env.dataDir, stateFileName))
+
+	// confirming after the rollback does nothing
+	updated.Confirm()
+	assertFileContent(t, env.executable, "0.9.0")
+}
+
+func TestUpdateRejected(t *testing.T) {
+	env := newTestEnv(t)
+	validPayload, files := env.request(t, "0.9.1", []byte("0.9.1"))
+
+	t.Run("disabled", func(t *testing.T) {
+		u := env.newUpdater("0.9.0", time.Minute)
+		u.config.Enabled = false
+
+		err := u.HandleRequest(validPayload, files)
+
+		assert.EqualError(t, err, "client self-update is disabled")
+	})
+
+	t.Run("not a service", func(t *testing.T) {
+		u := env.newUpdater("0.9.0", time.Minute)
+		u.SetRestart(nil)
+
+		err := u.HandleRequest(validPayload, files)
+
+		assert.EqualError(t, err, "client self-update requires rport to run as a service")
+	})
+
+	t.Run("same version", func(t *testing.T) {
+		u := env.newUpdater("0.9.1", time.Minute)
+
+		err := u.HandleRequest(validPayload, files)
+
+		assert.EqualError(t, err, "version 0.9.1 is already running")
+	})
+
+	t.Run("downgrade", func(t *testing.T) {
+		u := env.newUpdater("0.10.0", time.Minute)
+
+		err := u.HandleRequest(validPayload, files)
+
+		assert.EqualError(t, err, "downgrade from version 0.10.0 to 0.9.1 is not allowed")
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		u := env.newUpdater("0.10.0", time.Minute)
+		payload, files := env.request(t, "latest", []byte("latest"))
+
+		err := u.HandleRequest(payload, files)
+
+		assert.EqualError(t, err, `invalid version "latest": Malformed version: latest`)
+	})
+
+	t.Run("invalid running version", func(t *testing.T) {
+		u := env.newUpdater("unknown", time.Minute)
+
+		err := u.HandleRequest(validPayload, files)
+
+		assert.EqualError(t, err, `invalid version "unknown" of the running client: Malformed version: unknown`)
+	})
+
+	t.Run("other platform", func(t *testing.T) {
+		u := env.newUpdater("0.9.0", time.Minute)
+		req := &comm.SelfUpdateRequest{}
+		require.NoError(t, json.Unmarshal(validPayload, req))
+		req.OS = "plan9"
+		payload, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		err = u.HandleRequest(payload, files)
+
+		assert.EqualError(t, err, "binary for plan9/"+runtime.GOARCH+" can't run on "+runtime.GOOS+"/"+runtime.GOARCH)
+	})
+}
+
+func TestInstallVerification(t *testing.T) {
+	env := newTestEnv(t)
+	u := env.newUpdater("0.9.0", time.Minute)
+	data := []byte("0.9.1")
+	sum := sha256.Sum256(data)
+	valid := comm.SelfUpdateRequest{
+		Version: "0.9.1",
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+		Path:    "/rport",
+		Size:    int64(len(data)),
+		SHA256:  hex.EncodeToString(sum[:]),
+	}
+	valid.Signature = ed25519.Sign(env.privateKey, valid.Manifest())
+	files := fileProviderMock{"/rport": data}
+
+	testCases := []struct {
+		name        string
+		modify      func(req *comm.SelfUpdateRequest)
+		expectedErr string
+	}{
+		{
+			name:        "size mismatch",
+			modify:      func(req *comm.SelfUpdateRequest) { req.Size = 4 },
+			expectedErr: "size mismatch: expected 4 bytes, got 5",
+		},
+		{
+			name:        "checksum mismatch",
+			modify:      func(req *comm.SelfUpdateRequest) { req.SHA256 = hex.EncodeToString(make([]byte, 32)) },
+			expectedErr: "checksum mismatch",
+		},
+		{
+			name:        "invalid signature",
+			modify:      func(req *comm.SelfUpdateRequest) { req.Signature = ed25519.Sign(env.privateKey, []byte("other")) },
+			expectedErr: "invalid signature",
+		},
+		{
+			name:        "binary signed instead of the manifest",
+			modify:      func(req *comm.SelfUpdateRequest) { req.Signature = ed25519.Sign(env.privateKey, data) },
+			expectedErr: "invalid signature",
+		},
+		{
+			name: "signed as another version",
+			modify: func(req *comm.SelfUpdateRequest) {
+				signed := *req
+				signed.Version = "0.8.0"
+				req.Signature = ed25519.Sign(env.privateKey, signed.Manifest())
+			},
+			expectedErr: "invalid signature",
+		},
+		{
+			name: "signed for another platform",
+			modify: func(req *comm.SelfUpdateRequest) {
+				signed := *req
+				signed.Arch = "other"
+				req.Signature = ed25519.Sign(env.privateKey, signed.Manifest())
+			},
+			expectedErr: "invalid signature",
+		},
+		{
+			name:        "download failed",
+			modify:      func(req *comm.SelfUpdateRequest) { req.Path = "/missing" },
+			expectedErr: "failed to download /missing: file does not exist",
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req := valid
+			tc.modify(&req)
+
+			_, err := u.install(&req, files)
+
+			assert.EqualError(t, err, tc.expectedErr)
+			assertFileContent(t, env.executable, "0.9.0")
+			assert.NoFileExists(t, filepath.Join(env.dataDir, stateFileName))
+		})
+	}
+}
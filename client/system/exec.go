@@ -3,6 +3,7 @@ package system
 import (
 	"context"
 	"os/exec"
+	"syscall"
 
 	"github.com/cloudradar-monitoring/rport/share/logger"
 )
@@ -19,6 +20,8 @@ type CmdExecutor interface {
 	New(ctx context.Context, execCtx *CmdExecutorContext) *exec.Cmd
 	Start(cmd *exec.Cmd) error
 	Wait(cmd *exec.Cmd) error
+	// Terminate sends the signal to the process of a started cmd and all its child processes
+	Terminate(cmd *exec.Cmd, sig syscall.Signal) error
 }
 
 type CmdExecutorImpl struct {
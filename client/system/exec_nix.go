@@ -5,8 +5,13 @@ package system
 
 import (
 	"context"
+	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
 
 	chshare "github.com/cloudradar-monitoring/rport/share"
 )
@@ -37,6 +42,41 @@ func (e *CmdExecutorImpl) New(ctx context.Context, execCtx *CmdExecutorContext)
 
 	cmd := exec.CommandContext(ctx, args[0], args[1:]...) //nolint:gosec
 	cmd.Dir = execCtx.WorkingDir
+	// run in a new process group to be able to terminate the cmd along with its children
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
 	return cmd
 }
+
+func (e *CmdExecutorImpl) Terminate(cmd *exec.Cmd, sig syscall.Signal) error {
+	// negative pid sends the signal to the process group
+	err := syscall.Kill(-cmd.Process.Pid, sig)
+	if err != syscall.EPERM || !isSudo(cmd) {
+		return err
+	}
+
+	// the processes of a sudo job run as root, they can only be signalled via sudo as well
+	out, err := exec.Command("sudo", sudoKillArgs(cmd.Process.Pid, sig)...).CombinedOutput() //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("failed to send signal via sudo, cancelling sudo jobs requires rport to be allowed to run kill with sudo: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func isSudo(cmd *exec.Cmd) bool {
+	return len(cmd.Args) > 0 && cmd.Args[0] == "sudo"
+}
+
+// sudoKillArgs returns the args of sudo to send the signal to the process group of the given pid
+func sudoKillArgs(pid int, sig syscall.Signal) []string {
+	return []string{"-n", "kill", "-" + strconv.Itoa(int(sig)), "--", "-" + strconv.Itoa(pid)}
+}
+
+// ParseSignal returns a signal by its name, e.g. SIGTERM
+func ParseSignal(name string) (syscall.Signal, error) {
+	sig := unix.SignalNum(strings.ToUpper(name))
+	if sig == 0 {
+		return 0, fmt.Errorf("unknown signal %q", name)
+	}
+	return sig, nil
+}
@@ -4,6 +4,12 @@
 package system
 
 import (
+	"os/exec"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
 	chshare "github.com/cloudradar-monitoring/rport/share"
 )
 
@@ -56,3 +62,10 @@ func getCmdBuildTestcases() []cmdBuildTestCase {
 		},
 	}
 }
+
+func TestSudoKill(t *testing.T) {
+	assert.True(t, isSudo(exec.Command("sudo", "-n", "/bin/sh", "/script.sh")))
+	assert.False(t, isSudo(exec.Command("/bin/sh", "/script.sh")))
+
+	assert.Equal(t, []string{"-n", "kill", "-15", "--", "-123"}, sudoKillArgs(123, syscall.SIGTERM))
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -80,3 +81,13 @@ func buildDefaultCmd(ctx context.Context, execCtx *CmdExecutorContext, interpret
 
 	return cmd
 }
+
+func (e *CmdExecutorImpl) Terminate(cmd *exec.Cmd, sig syscall.Signal) error {
+	// there are no signals on windows, the whole process tree is killed
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run() //nolint:gosec
+}
+
+// ParseSignal accepts any signal name on windows, a terminated process is always killed
+func ParseSignal(name string) (syscall.Signal, error) {
+	return syscall.SIGKILL, nil
+}
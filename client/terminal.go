@@ -0,0 +1,128 @@
+package chclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/cloudradar-monitoring/rport/share/comm"
+)
+
+// terminal is a shell process attached to a pseudo terminal, reads return the output and writes are sent as input.
+type terminal interface {
+	io.ReadWriteCloser
+	Resize(cols, rows uint16) error
+	// Wait waits for the shell to exit and returns its exit code
+	Wait() (int, error)
+}
+
+func (c *Client) handleTerminalChannel(ch ssh.NewChannel) {
+	config := c.config()
+	if !config.RemoteTerminal.Enabled {
+		c.Infof("Rejecting terminal session, remote terminal is disabled.")
+		c.rejectChannel(ch, ssh.Prohibited, "remote terminal is disabled")
+		return
+	}
+
+	req := comm.TerminalRequest{}
+	if err := json.Unmarshal(ch.ExtraData(), &req); err != nil {
+		c.rejectChannel(ch, ssh.ConnectionFailed, fmt.Sprintf("failed to decode terminal request: %v", err))
+		return
+	}
+	if err := config.CheckUserPolicy(req.User, req.UserGroups); err != nil {
+		c.Infof("Rejecting terminal session: %v", err)
+		c.rejectChannel(ch, ssh.Prohibited, err.Error())
+		return
+	}
+
+	shell := config.RemoteTerminal.Shell
+	if shell == "" {
+		shell = defaultTerminalShell()
+	}
+
+	term, err := startTerminal(shell, req)
+	if err != nil {
+		c.Errorf("Failed to start terminal with %s: %v", shell, err)
+		c.rejectChannel(ch, ssh.ConnectionFailed, fmt.Sprintf("failed to start terminal: %v", err))
+		return
+	}
+
+	stream, reqs, err := ch.Accept()
+	if err != nil {
+		c.Errorf("Failed to accept terminal channel: %v", err)
+		_ = term.Close()
+		return
+	}
+	c.Infof("Terminal session started with %s.", shell)
+
+	go c.handleTerminalRequests(term, reqs)
+	go func() {
+		// the server closes the channel once the session ended on its side
+		_, _ = io.Copy(term, stream)
+		_ = term.Close()
+	}()
+
+	// reading fails as soon as the shell exited
+	_, _ = io.Copy(stream, term)
+
+	exitCode, err := term.Wait()
+	exitReq := comm.TerminalExitRequest{
+		ExitCode: exitCode,
+	}
+	if err != nil {
+		exitReq.ErrMsg = err.Error()
+	}
+	c.Infof("Terminal session ended with exit code %d.", exitCode)
+
+	payload, err := json.Marshal(exitReq)
+	if err == nil {
+		_, _ = stream.SendRequest(comm.RequestTypeTerminalExit, false, payload)
+	}
+	_ = term.Close()
+	_ = stream.Close()
+}
+
+func (c *Client) handleTerminalRequests(term terminal, reqs <-chan *ssh.Request) {
+	for r := range reqs {
+		if r.Type != comm.RequestTypeTerminalResize {
+			if r.WantReply {
+				_ = r.Reply(false, nil)
+			}
+			continue
+		}
+
+		resize := comm.TerminalResizeRequest{}
+		err := json.Unmarshal(r.Payload, &resize)
+		if err == nil {
+			err = term.Resize(resize.Cols, resize.Rows)
+		}
+		if err != nil {
+			c.Debugf("Failed to resize terminal: %v", err)
+		}
+		if r.WantReply {
+			_ = r.Reply(err == nil, nil)
+		}
+	}
+}
+
+func (c *Client) rejectChannel(ch ssh.NewChannel, reason ssh.RejectionReason, msg string) {
+	if err := ch.Reject(reason, msg); err != nil {
+		c.Errorf("Failed to reject channel: %v", err)
+	}
+}
+
+// exitCode returns the exit code of a process that finished with a given error
+func exitCode(err error) (int, error) {
+	if err == nil {
+		return 0, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	return -1, err
+}
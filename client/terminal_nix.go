@@ -0,0 +1,70 @@
+//go:build !windows
+// +build !windows
+
+package chclient
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/creack/pty"
+
+	"github.com/cloudradar-monitoring/rport/share/comm"
+)
+
+const defaultTerm = "xterm"
+
+type ptyTerminal struct {
+	*os.File
+	cmd       *exec.Cmd
+	closeOnce sync.Once
+}
+
+func defaultTerminalShell() string {
+	if shell := os.Getenv("SHELL"); shell != "" {
+		return shell
+	}
+	return "/bin/sh"
+}
+
+func startTerminal(shell string, req comm.TerminalRequest) (terminal, error) {
+	term := req.Term
+	if term == "" {
+		term = defaultTerm
+	}
+
+	cmd := exec.Command(shell)
+	cmd.Env = append(os.Environ(), "TERM="+term)
+	if home, err := os.UserHomeDir(); err == nil {
+		cmd.Dir = home
+	}
+
+	f, err := pty.StartWithSize(cmd, &pty.Winsize{Cols: req.Cols, Rows: req.Rows})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ptyTerminal{
+		File: f,
+		cmd:  cmd,
+	}, nil
+}
+
+func (t *ptyTerminal) Resize(cols, rows uint16) error {
+	return pty.Setsize(t.File, &pty.Winsize{Cols: cols, Rows: rows})
+}
+
+func (t *ptyTerminal) Wait() (int, error) {
+	return exitCode(t.cmd.Wait())
+}
+
+// Close kills the shell if it's still running and closes the pty
+func (t *ptyTerminal) Close() error {
+	var err error
+	t.closeOnce.Do(func() {
+		_ = t.cmd.Process.Kill()
+		err = t.File.Close()
+	})
+	return err
+}
@@ -0,0 +1,34 @@
+//go:build !windows
+// +build !windows
+
+package chclient
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/share/comm"
+)
+
+func TestStartTerminal(t *testing.T) {
+	term, err := startTerminal("/bin/sh", comm.TerminalRequest{Cols: 100, Rows: 30})
+	require.NoError(t, err)
+	defer term.Close()
+
+	_, err = term.Write([]byte("stty size; echo $TERM; exit 3\n"))
+	require.NoError(t, err)
+
+	output := &bytes.Buffer{}
+	// reading the pty fails once the shell exited
+	_, _ = io.Copy(output, term)
+
+	exitCode, err := term.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, 3, exitCode)
+	assert.Contains(t, output.String(), "30 100")
+	assert.Contains(t, output.String(), "xterm")
+}
@@ -0,0 +1,87 @@
+//go:build windows
+// +build windows
+
+package chclient
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/cloudradar-monitoring/rport/share/comm"
+)
+
+// pipeTerminal runs the shell with plain pipes. It's used until a ConPTY based pseudo console is available,
+// so the session works for line based shells like cmd.exe or powershell, but resizing has no effect.
+type pipeTerminal struct {
+	stdout    *os.File
+	stdin     io.WriteCloser
+	cmd       *exec.Cmd
+	closeOnce sync.Once
+}
+
+func defaultTerminalShell() string {
+	if comspec := os.Getenv("COMSPEC"); comspec != "" {
+		return comspec
+	}
+	return "cmd.exe"
+}
+
+func startTerminal(shell string, req comm.TerminalRequest) (terminal, error) {
+	cmd := exec.Command(shell)
+	if home, err := os.UserHomeDir(); err == nil {
+		cmd.Dir = home
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	if err := cmd.Start(); err != nil {
+		r.Close()
+		w.Close()
+		return nil, err
+	}
+	// the shell holds its own handle, the output reader gets EOF once the shell exited
+	w.Close()
+
+	return &pipeTerminal{
+		stdout: r,
+		stdin:  stdin,
+		cmd:    cmd,
+	}, nil
+}
+
+func (t *pipeTerminal) Read(p []byte) (int, error) {
+	return t.stdout.Read(p)
+}
+
+func (t *pipeTerminal) Write(p []byte) (int, error) {
+	return t.stdin.Write(p)
+}
+
+func (t *pipeTerminal) Resize(cols, rows uint16) error {
+	return nil
+}
+
+func (t *pipeTerminal) Wait() (int, error) {
+	return exitCode(t.cmd.Wait())
+}
+
+// Close kills the shell if it's still running and closes the pipes
+func (t *pipeTerminal) Close() error {
+	t.closeOnce.Do(func() {
+		_ = t.cmd.Process.Kill()
+		_ = t.stdin.Close()
+		_ = t.stdout.Close()
+	})
+	return nil
+}
@@ -15,6 +15,10 @@ func TunnelIsAllowed(tunnelAllowed []string, remote string) (bool, error) {
 	if len(tunnelAllowed) == 0 {
 		return true, nil
 	}
+	// socks5 tunnels have no fixed remote, the destination of each connection is checked separately
+	if remote == "" {
+		return true, nil
+	}
 
 	host, remotePort, err := net.SplitHostPort(remote)
 	if err != nil {
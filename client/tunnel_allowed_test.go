@@ -33,6 +33,12 @@ func TestIsAllowed(t *testing.T) {
 			TunnelAllowed: nil,
 			Expected:      true,
 		},
+		{
+			Name:          "socks5 tunnel without remote",
+			Remote:        "",
+			TunnelAllowed: []string{"192.0.2.1"},
+			Expected:      true,
+		},
 		{
 			Name:          "ip allowed",
 			Remote:        "192.0.2.1:3000",
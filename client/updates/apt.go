@@ -2,6 +2,7 @@ package updates
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
@@ -20,6 +21,8 @@ type AptPackageManager struct {
 	updateCacheCmd         []string
 	getSummariesCmd        []string
 	getCountsCmd           []string
+	upgradeCmd             []string
+	installCmd             []string
 }
 
 type getCountsCmdError error
@@ -33,6 +36,9 @@ func NewAptPackageManager() *AptPackageManager {
 		updateCacheCmd:         []string{"sudo", "-n", "apt-get", "update", "-o", "Debug::NoLocking=true"},
 		getSummariesCmd:        []string{"apt-get", "-s", "-o", "Debug::NoLocking=true", "upgrade"},
 		getCountsCmd:           []string{"/usr/lib/update-notifier/apt-check"},
+		// keep changed config files, don't ask for it
+		upgradeCmd: []string{"sudo", "-n", "apt-get", "-y", "-o", "Dpkg::Options::=--force-confdef", "-o", "Dpkg::Options::=--force-confold", "upgrade"},
+		installCmd: []string{"sudo", "-n", "apt-get", "-y", "-o", "Dpkg::Options::=--force-confdef", "-o", "Dpkg::Options::=--force-confold", "install", "--only-upgrade"},
 	}
 }
 
@@ -78,6 +84,30 @@ func (p *AptPackageManager) GetUpdatesStatus(ctx context.Context, logger *logger
 	}, nil
 }
 
+func (p *AptPackageManager) GetInstallScript(installation *models.UpdatesInstallation, status *models.UpdatesStatus) (string, error) {
+	switch installation.Mode {
+	case models.UpdatesInstallationModeSecurity:
+		// apt has no option to install only security updates, they are taken from the last summary
+		if status == nil || status.Error != "" {
+			return "", errors.New("pending security updates are unknown, the updates status is not available")
+		}
+		var packages []string
+		for _, s := range status.UpdateSummaries {
+			if s.IsSecurityUpdate {
+				packages = append(packages, s.Title)
+			}
+		}
+		if len(packages) == 0 {
+			return noUpdatesScript, nil
+		}
+		return nixInstallScript(append(p.installCmd, packages...), installation.Reboot), nil
+	case models.UpdatesInstallationModePackages:
+		return nixInstallScript(append(p.installCmd, installation.Packages...), installation.Reboot), nil
+	default:
+		return nixInstallScript(p.upgradeCmd, installation.Reboot), nil
+	}
+}
+
 func (p *AptPackageManager) getCounts(ctx context.Context) (availableUpdates int, securityUpdates int, err error) {
 	output, err := p.runner.Run(ctx, p.getCountsCmd...)
 	if err != nil {
@@ -267,3 +267,67 @@ Conf libc6-dev (2.31-0ubuntu9.2 Ubuntu:20.04/focal-updates [amd64])
 		})
 	}
 }
+
+func TestAptPackageManagerGetInstallScript(t *testing.T) {
+	const options = "sudo -n apt-get -y -o Dpkg::Options::=--force-confdef -o Dpkg::Options::=--force-confold"
+	status := &models.UpdatesStatus{
+		UpdateSummaries: []models.UpdateSummary{
+			{Title: "openssl", IsSecurityUpdate: true},
+			{Title: "vim"},
+			{Title: "libssl3", IsSecurityUpdate: true},
+		},
+	}
+
+	testCases := []struct {
+		Name           string
+		Installation   *models.UpdatesInstallation
+		Status         *models.UpdatesStatus
+		ExpectedScript string
+		ExpectedError  string
+	}{
+		{
+			Name:           "all with reboot",
+			Installation:   &models.UpdatesInstallation{Mode: models.UpdatesInstallationModeAll, Reboot: true},
+			ExpectedScript: "set -e\n" + options + " upgrade\nsudo -n shutdown -r +1\n",
+		},
+		{
+			Name:           "security",
+			Installation:   &models.UpdatesInstallation{Mode: models.UpdatesInstallationModeSecurity},
+			Status:         status,
+			ExpectedScript: "set -e\n" + options + " install --only-upgrade openssl libssl3\n",
+		},
+		{
+			Name:           "no security updates",
+			Installation:   &models.UpdatesInstallation{Mode: models.UpdatesInstallationModeSecurity, Reboot: true},
+			Status:         &models.UpdatesStatus{},
+			ExpectedScript: noUpdatesScript,
+		},
+		{
+			Name:          "security without status",
+			Installation:  &models.UpdatesInstallation{Mode: models.UpdatesInstallationModeSecurity},
+			ExpectedError: "pending security updates are unknown, the updates status is not available",
+		},
+		{
+			Name:           "packages",
+			Installation:   &models.UpdatesInstallation{Mode: models.UpdatesInstallationModePackages, Packages: []string{"vim", "curl"}},
+			Status:         status,
+			ExpectedScript: "set -e\n" + options + " install --only-upgrade vim curl\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			script, err := NewAptPackageManager().GetInstallScript(tc.Installation, tc.Status)
+
+			if tc.ExpectedError != "" {
+				assert.EqualError(t, err, tc.ExpectedError)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.ExpectedScript, script)
+			}
+		})
+	}
+}
@@ -3,7 +3,11 @@ package updates
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,8 +21,15 @@ import (
 type PackageManager interface {
 	IsAvailable(context.Context) bool
 	GetUpdatesStatus(context.Context, *logger.Logger) (*models.UpdatesStatus, error)
+	// GetInstallScript returns a script installing the requested updates, status is the last known, it might be nil
+	GetInstallScript(*models.UpdatesInstallation, *models.UpdatesStatus) (string, error)
 }
 
+const (
+	nixRebootCmd    = "sudo -n shutdown -r +1"
+	noUpdatesScript = "echo No matching updates available\n"
+)
+
 type Updates struct {
 	// mtx protects both conn and status
 	mtx    sync.RWMutex
@@ -28,8 +39,10 @@ type Updates struct {
 	interval    time.Duration
 	refreshChan chan struct{}
 
-	pkgMgr PackageManager
-	logger *logger.Logger
+	// pkgMgrMtx protects the detection of pkgMgr, it's also used when installing updates
+	pkgMgrMtx sync.Mutex
+	pkgMgr    PackageManager
+	logger    *logger.Logger
 }
 
 func New(logger *logger.Logger, interval time.Duration) *Updates {
@@ -49,6 +62,9 @@ func (u *Updates) Start(ctx context.Context) {
 }
 
 func (u *Updates) getPackageManager(ctx context.Context) PackageManager {
+	u.pkgMgrMtx.Lock()
+	defer u.pkgMgrMtx.Unlock()
+
 	if u.pkgMgr != nil {
 		return u.pkgMgr
 	}
@@ -61,6 +77,45 @@ func (u *Updates) getPackageManager(ctx context.Context) PackageManager {
 	return nil
 }
 
+// GetInstallScript returns the script installing the requested updates with the detected package manager
+// and the interpreter to run it with
+func (u *Updates) GetInstallScript(ctx context.Context, installation *models.UpdatesInstallation) (script string, interpreter string, err error) {
+	pkgMgr := u.getPackageManager(ctx)
+	if pkgMgr == nil {
+		return "", "", errors.New("no supported package manager found")
+	}
+
+	u.mtx.RLock()
+	status := u.status
+	u.mtx.RUnlock()
+
+	script, err = pkgMgr.GetInstallScript(installation, status)
+	if err != nil {
+		return "", "", err
+	}
+	return script, installInterpreter, nil
+}
+
+// nixInstallScript stops on the first failing command and reboots only after a successful installation.
+// The reboot is delayed to let the client send the result of the job.
+// Additional exit codes of the install command can be treated as success.
+func nixInstallScript(installCmd []string, reboot bool, successExitCodes ...int) string {
+	cmd := strings.Join(installCmd, " ")
+	if len(successExitCodes) > 0 {
+		codes := make([]string, len(successExitCodes))
+		for i, c := range successExitCodes {
+			codes[i] = strconv.Itoa(c)
+		}
+		cmd = fmt.Sprintf("%s || { rc=$?; case $rc in %s) ;; *) exit $rc ;; esac; }", cmd, strings.Join(codes, "|"))
+	}
+
+	script := "set -e\n" + cmd + "\n"
+	if reboot {
+		script += nixRebootCmd + "\n"
+	}
+	return script
+}
+
 func (u *Updates) Refresh() {
 	select {
 	case u.refreshChan <- struct{}{}:
@@ -3,6 +3,9 @@
 
 package updates
 
+// installInterpreter is empty to run install scripts with the default shell
+const installInterpreter = ""
+
 var packageManagers = []PackageManager{
 	NewZypperPackageManager(),
 	NewYumPackageManager(),
@@ -35,6 +35,10 @@ func (pm *mockPackageManager) GetUpdatesStatus(context.Context, *logger.Logger)
 	return newStatus, pm.err
 }
 
+func (pm *mockPackageManager) GetInstallScript(installation *models.UpdatesInstallation, _ *models.UpdatesStatus) (string, error) {
+	return installation.String(), pm.err
+}
+
 type mockSSHRequest struct {
 	Name string
 	Data []byte
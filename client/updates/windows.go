@@ -5,12 +5,14 @@ package updates
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	"github.com/go-ole/go-ole"
 	"github.com/go-ole/go-ole/oleutil"
 	"github.com/scjalliance/comshim"
 
+	chshare "github.com/cloudradar-monitoring/rport/share"
 	"github.com/cloudradar-monitoring/rport/share/logger"
 	"github.com/cloudradar-monitoring/rport/share/models"
 )
@@ -19,6 +21,49 @@ var packageManagers = []PackageManager{
 	NewWindowsPackageManager(),
 }
 
+const installInterpreter = chshare.PowerShell
+
+// windowsInstallScript selects the updates by mode and KB article IDs, downloads and installs them.
+// Result code 2 means succeeded, see https://learn.microsoft.com/en-us/windows/win32/api/wuapi/ne-wuapi-operationresultcode
+const windowsInstallScript = `$ErrorActionPreference = 'Stop'
+$mode = '%s'
+$packages = @(%s)
+$reboot = $%t
+$session = New-Object -ComObject Microsoft.Update.Session
+$result = $session.CreateUpdateSearcher().Search('IsHidden=0 and IsInstalled=0')
+$updates = New-Object -ComObject Microsoft.Update.UpdateColl
+foreach ($update in $result.Updates) {
+    $selected = $true
+    if ($mode -eq 'security') {
+        $selected = @($update.Categories | Where-Object { $_.Name -like '*Security Updates*' }).Count -gt 0
+    } elseif ($mode -eq 'packages') {
+        $selected = @($update.KBArticleIDs | Where-Object { $packages -contains "KB$_" }).Count -gt 0
+    }
+    if ($selected) {
+        if (-not $update.EulaAccepted) { $update.AcceptEula() }
+        [void]$updates.Add($update)
+        Write-Output "Selected $($update.Title)"
+    }
+}
+if ($updates.Count -eq 0) {
+    Write-Output 'No matching updates available'
+    exit 0
+}
+$downloader = $session.CreateUpdateDownloader()
+$downloader.Updates = $updates
+[void]$downloader.Download()
+$installer = $session.CreateUpdateInstaller()
+$installer.Updates = $updates
+$installResult = $installer.Install()
+Write-Output "Installation finished with result code $($installResult.ResultCode), reboot required: $($installResult.RebootRequired)"
+if ($installResult.ResultCode -ne 2) {
+    exit 1
+}
+if ($reboot) {
+    shutdown /r /t 60
+}
+`
+
 type WindowsPackageManager struct {
 }
 
@@ -110,6 +155,15 @@ func (p *WindowsPackageManager) GetUpdatesStatus(ctx context.Context, logger *lo
 	}, nil
 }
 
+func (p *WindowsPackageManager) GetInstallScript(installation *models.UpdatesInstallation, _ *models.UpdatesStatus) (string, error) {
+	// package names are validated, they can't contain quotes
+	packages := make([]string, len(installation.Packages))
+	for i, pkg := range installation.Packages {
+		packages[i] = "'" + pkg + "'"
+	}
+	return fmt.Sprintf(windowsInstallScript, installation.Mode, strings.Join(packages, ","), installation.Reboot), nil
+}
+
 func (p *WindowsPackageManager) checkRebootPending() (bool, error) {
 	sysInfo, err := p.newCOMObject("Microsoft.Update.SystemInfo")
 	if err != nil {
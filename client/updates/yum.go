@@ -76,6 +76,17 @@ func (p *YumPackageManager) GetUpdatesStatus(ctx context.Context, logger *logger
 	}, nil
 }
 
+func (p *YumPackageManager) GetInstallScript(installation *models.UpdatesInstallation, _ *models.UpdatesStatus) (string, error) {
+	cmd := []string{"sudo", "-n", p.cmd, "-y", "update"}
+	switch installation.Mode {
+	case models.UpdatesInstallationModeSecurity:
+		cmd = append(cmd, "--security")
+	case models.UpdatesInstallationModePackages:
+		cmd = append(cmd, installation.Packages...)
+	}
+	return nixInstallScript(cmd, installation.Reboot), nil
+}
+
 type yumUpdate struct {
 	name       string
 	arch       string
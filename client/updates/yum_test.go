@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/cloudradar-monitoring/rport/share/models"
 )
@@ -206,3 +207,42 @@ It could be a DNF plugin command, try: "dnf install 'dnf-command(needs-restartin
 		}
 	}
 }
+
+func TestYumPackageManagerGetInstallScript(t *testing.T) {
+	testCases := []struct {
+		Name           string
+		Installation   *models.UpdatesInstallation
+		ExpectedScript string
+	}{
+		{
+			Name:           "all",
+			Installation:   &models.UpdatesInstallation{Mode: models.UpdatesInstallationModeAll},
+			ExpectedScript: "set -e\nsudo -n dnf -y update\n",
+		},
+		{
+			Name:           "security with reboot",
+			Installation:   &models.UpdatesInstallation{Mode: models.UpdatesInstallationModeSecurity, Reboot: true},
+			ExpectedScript: "set -e\nsudo -n dnf -y update --security\nsudo -n shutdown -r +1\n",
+		},
+		{
+			Name:           "packages",
+			Installation:   &models.UpdatesInstallation{Mode: models.UpdatesInstallationModePackages, Packages: []string{"kernel", "openssl-libs"}},
+			ExpectedScript: "set -e\nsudo -n dnf -y update kernel openssl-libs\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			pm := NewYumPackageManager()
+			pm.cmd = "dnf"
+
+			script, err := pm.GetInstallScript(tc.Installation, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.ExpectedScript, script)
+		})
+	}
+}
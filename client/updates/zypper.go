@@ -9,6 +9,9 @@ import (
 	"github.com/cloudradar-monitoring/rport/share/models"
 )
 
+// zypperExitRebootNeeded is returned after a successful installation of updates requiring a reboot
+const zypperExitRebootNeeded = 102
+
 type ZypperPackageManager struct {
 	runner Runner
 
@@ -18,6 +21,8 @@ type ZypperPackageManager struct {
 	needsRebootCmd []string
 	listPatchesCmd []string
 	patchInfoCmd   []string
+	updateCmd      []string
+	patchCmd       []string
 }
 
 func NewZypperPackageManager() *ZypperPackageManager {
@@ -30,6 +35,8 @@ func NewZypperPackageManager() *ZypperPackageManager {
 		needsRebootCmd: []string{"zypper", "needs-rebooting"},
 		listPatchesCmd: []string{"zypper", "--terse", "--quiet", "list-patches"},
 		patchInfoCmd:   []string{"zypper", "--terse", "--quiet", "patch-info"},
+		updateCmd:      []string{"sudo", "-n", "zypper", "--non-interactive", "update"},
+		patchCmd:       []string{"sudo", "-n", "zypper", "--non-interactive", "patch", "--category", "security"},
 	}
 }
 
@@ -86,6 +93,17 @@ func (p *ZypperPackageManager) GetUpdatesStatus(ctx context.Context, logger *log
 	}, nil
 }
 
+func (p *ZypperPackageManager) GetInstallScript(installation *models.UpdatesInstallation, _ *models.UpdatesStatus) (string, error) {
+	cmd := p.updateCmd
+	switch installation.Mode {
+	case models.UpdatesInstallationModeSecurity:
+		cmd = p.patchCmd
+	case models.UpdatesInstallationModePackages:
+		cmd = append(cmd, installation.Packages...)
+	}
+	return nixInstallScript(cmd, installation.Reboot, zypperExitRebootNeeded), nil
+}
+
 func (p *ZypperPackageManager) listUpdates(ctx context.Context) ([]zypperUpdate, error) {
 	output, err := p.runner.Run(ctx, p.listUpdatesCmd...)
 	if err != nil {
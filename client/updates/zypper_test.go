@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/cloudradar-monitoring/rport/share/models"
 )
@@ -257,3 +258,40 @@ Reboot is probably not necessary.
 		})
 	}
 }
+
+func TestZypperPackageManagerGetInstallScript(t *testing.T) {
+	const rebootNeededOK = " || { rc=$?; case $rc in 102) ;; *) exit $rc ;; esac; }\n"
+	testCases := []struct {
+		Name           string
+		Installation   *models.UpdatesInstallation
+		ExpectedScript string
+	}{
+		{
+			Name:           "all",
+			Installation:   &models.UpdatesInstallation{Mode: models.UpdatesInstallationModeAll},
+			ExpectedScript: "set -e\nsudo -n zypper --non-interactive update" + rebootNeededOK,
+		},
+		{
+			Name:           "security with reboot",
+			Installation:   &models.UpdatesInstallation{Mode: models.UpdatesInstallationModeSecurity, Reboot: true},
+			ExpectedScript: "set -e\nsudo -n zypper --non-interactive patch --category security" + rebootNeededOK + "sudo -n shutdown -r +1\n",
+		},
+		{
+			Name:           "packages",
+			Installation:   &models.UpdatesInstallation{Mode: models.UpdatesInstallationModePackages, Packages: []string{"vim"}},
+			ExpectedScript: "set -e\nsudo -n zypper --non-interactive update vim" + rebootNeededOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			script, err := NewZypperPackageManager().GetInstallScript(tc.Installation, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.ExpectedScript, script)
+		})
+	}
+}
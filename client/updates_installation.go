@@ -0,0 +1,54 @@
+package chclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cloudradar-monitoring/rport/client/system"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+// HandleInstallUpdatesRequest starts a job installing pending updates with the detected package manager.
+// The installation is executed as a script, so the result is reported the same way as for remote commands.
+func (c *Client) HandleInstallUpdatesRequest(ctx context.Context, reqPayload []byte) (*comm.RunCmdResponse, error) {
+	config := c.config()
+	if !config.RemoteCommands.Enabled {
+		return nil, errors.New("remote commands execution is disabled")
+	}
+	if !config.Client.AllowUpdatesInstallation {
+		return nil, errors.New("updates installation is not allowed")
+	}
+
+	job := models.Job{}
+	err := json.Unmarshal(reqPayload, &job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode requested job: %s", err)
+	}
+	if err := config.CheckUserPolicy(job.CreatedBy, job.CreatedByGroups); err != nil {
+		return nil, err
+	}
+	if job.UpdatesInstallation == nil {
+		return nil, errors.New("missing updates installation")
+	}
+	if err := job.UpdatesInstallation.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid updates installation: %v", err)
+	}
+
+	script, interpreterName, err := c.updates.GetInstallScript(ctx, job.UpdatesInstallation)
+	if err != nil {
+		return nil, err
+	}
+
+	interpreter := system.Interpreter{
+		InterpreterNameFromInput: interpreterName,
+		InterpreterAliases:       config.InterpreterAliases,
+	}
+
+	c.Infof("Starting to %s [jid=%q]", job.UpdatesInstallation, job.JID)
+
+	// report the new patch level as soon as the installation is finished
+	return c.runJob(ctx, reqPayload, job, script, interpreter, c.updates.Refresh)
+}
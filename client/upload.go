@@ -31,6 +31,7 @@ type OptionsProvider interface {
 	GetUploadDir() string
 	GetProtectedUploadDirs() []string
 	IsFileReceptionEnabled() bool
+	CheckUserPolicy(username string, groups []string) error
 }
 
 type UploadManager struct {
@@ -126,6 +127,10 @@ func (um *UploadManager) HandleUploadRequest(reqPayload []byte) (*models.UploadR
 		return nil, err
 	}
 
+	if err := um.OptionsProvider.CheckUserPolicy(uploadedFile.CreatedBy, uploadedFile.CreatedByGroups); err != nil {
+		return nil, err
+	}
+
 	destinationFileExists, err := um.FilesAPI.Exist(uploadedFile.DestinationPath)
 	if err != nil {
 		return nil, err
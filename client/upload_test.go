@@ -42,6 +42,7 @@ func (sfpm *SourceFileProviderMock) Open(path string) (io.ReadCloser, error) {
 
 type UploadOptionsProviderMock struct {
 	mock.Mock
+	userPolicyErr error
 }
 
 func (uopm *UploadOptionsProviderMock) GetUploadDir() string {
@@ -67,6 +68,10 @@ func (uopm *UploadOptionsProviderMock) IsFileReceptionEnabled() bool {
 	return args.Bool(0)
 }
 
+func (uopm *UploadOptionsProviderMock) CheckUserPolicy(username string, groups []string) error {
+	return uopm.userPolicyErr
+}
+
 func TestHandleUploadRequest(t *testing.T) {
 	testCases := []struct {
 		name                  string
@@ -372,6 +377,16 @@ func TestHandleUploadRequest(t *testing.T) {
 			},
 			wantError: errors.ErrUploadsDisabled.Error(),
 		},
+		{
+			name:             "denied by user policy",
+			wantUploadedFile: getValidUploadFile(""),
+			optionsCallback: func(opts *UploadOptionsProviderMock) {
+				opts.On("GetProtectedUploadDirs").Return([]string{})
+				opts.On("IsFileReceptionEnabled").Return(true)
+				opts.userPolicyErr = fmt.Errorf(`user policy: user "admin" is not allowed`)
+			},
+			wantError: `user policy: user "admin" is not allowed`,
+		},
 	}
 
 	for _, tc := range testCases {
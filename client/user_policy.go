@@ -0,0 +1,51 @@
+package chclient
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CheckUserPolicy returns an error if the user policy doesn't allow the given user of the server API to act on the client
+func (c *ClientConfigHolder) CheckUserPolicy(username string, groups []string) error {
+	policy := c.UserPolicy
+	if len(policy.AllowUsers) == 0 && len(policy.DenyUsers) == 0 && len(policy.AllowGroups) == 0 && len(policy.DenyGroups) == 0 {
+		return nil
+	}
+
+	// the policy can't be applied to servers not sending the user
+	if username == "" {
+		return errors.New("user policy: requesting user is unknown")
+	}
+
+	if contains(policy.DenyUsers, username) {
+		return fmt.Errorf("user policy: user %q is denied", username)
+	}
+	for _, group := range groups {
+		if contains(policy.DenyGroups, group) {
+			return fmt.Errorf("user policy: user group %q of user %q is denied", group, username)
+		}
+	}
+
+	if len(policy.AllowUsers) == 0 && len(policy.AllowGroups) == 0 {
+		return nil
+	}
+	if contains(policy.AllowUsers, username) {
+		return nil
+	}
+	for _, group := range groups {
+		if contains(policy.AllowGroups, group) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("user policy: user %q is not allowed", username)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+package chclient
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+)
+
+func TestCheckUserPolicy(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Policy        clientconfig.UserPolicyConfig
+		Username      string
+		Groups        []string
+		ExpectedError string
+	}{
+		{
+			Name:     "no policy",
+			Username: "admin",
+		},
+		{
+			Name:     "no policy unknown user",
+			Username: "",
+		},
+		{
+			Name: "unknown user",
+			Policy: clientconfig.UserPolicyConfig{
+				DenyUsers: []string{"guest"},
+			},
+			Username:      "",
+			ExpectedError: "user policy: requesting user is unknown",
+		},
+		{
+			Name: "user denied",
+			Policy: clientconfig.UserPolicyConfig{
+				DenyUsers: []string{"guest"},
+			},
+			Username:      "guest",
+			ExpectedError: `user policy: user "guest" is denied`,
+		},
+		{
+			Name: "user not denied",
+			Policy: clientconfig.UserPolicyConfig{
+				DenyUsers: []string{"guest"},
+			},
+			Username: "admin",
+		},
+		{
+			Name: "group denied",
+			Policy: clientconfig.UserPolicyConfig{
+				DenyGroups: []string{"interns"},
+			},
+			Username:      "admin",
+			Groups:        []string{"ops", "interns"},
+			ExpectedError: `user policy: user group "interns" of user "admin" is denied`,
+		},
+		{
+			Name: "user allowed",
+			Policy: clientconfig.UserPolicyConfig{
+				AllowUsers: []string{"admin"},
+			},
+			Username: "admin",
+		},
+		{
+			Name: "user not allowed",
+			Policy: clientconfig.UserPolicyConfig{
+				AllowUsers: []string{"admin"},
+			},
+			Username:      "guest",
+			ExpectedError: `user policy: user "guest" is not allowed`,
+		},
+		{
+			Name: "group allowed",
+			Policy: clientconfig.UserPolicyConfig{
+				AllowUsers:  []string{"admin"},
+				AllowGroups: []string{"ops"},
+			},
+			Username: "guest",
+			Groups:   []string{"ops"},
+		},
+		{
+			Name: "group not allowed",
+			Policy: clientconfig.UserPolicyConfig{
+				AllowGroups: []string{"ops"},
+			},
+			Username:      "guest",
+			Groups:        []string{"devs"},
+			ExpectedError: `user policy: user "guest" is not allowed`,
+		},
+		{
+			Name: "deny takes precedence",
+			Policy: clientconfig.UserPolicyConfig{
+				AllowUsers: []string{"admin"},
+				DenyGroups: []string{"ops"},
+			},
+			Username:      "admin",
+			Groups:        []string{"ops"},
+			ExpectedError: `user policy: user group "ops" of user "admin" is denied`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			config := &ClientConfigHolder{Config: &clientconfig.Config{
+				UserPolicy: tc.Policy,
+			}}
+
+			err := config.CheckUserPolicy(tc.Username, tc.Groups)
+
+			if tc.ExpectedError != "" {
+				assert.EqualError(t, err, tc.ExpectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// newChannelMock records the rejection of a channel, accepting it fails
+type newChannelMock struct {
+	channelType string
+	extraData   []byte
+
+	accepted     bool
+	rejectReason ssh.RejectionReason
+	rejectMsg    string
+}
+
+func (ch *newChannelMock) Accept() (ssh.Channel, <-chan *ssh.Request, error) {
+	ch.accepted = true
+	return nil, nil, errors.New("accept not supported")
+}
+
+func (ch *newChannelMock) Reject(reason ssh.RejectionReason, message string) error {
+	ch.rejectReason = reason
+	ch.rejectMsg = message
+	return nil
+}
+
+func (ch *newChannelMock) ChannelType() string {
+	return ch.channelType
+}
+
+func (ch *newChannelMock) ExtraData() []byte {
+	return ch.extraData
+}
+
+func TestUserPolicyOfChannels(t *testing.T) {
+	testCases := []struct {
+		Name              string
+		ChannelType       string
+		ExtraData         string
+		ExpectedAccepted  bool
+		ExpectedRejectMsg string
+	}{
+		{
+			Name:              "terminal of denied user",
+			ChannelType:       comm.ChannelTypeTerminal,
+			ExtraData:         `{"Cols":80,"Rows":24,"User":"guest"}`,
+			ExpectedRejectMsg: `user policy: user "guest" is denied`,
+		},
+		{
+			Name:              "terminal of denied group",
+			ChannelType:       comm.ChannelTypeTerminal,
+			ExtraData:         `{"Cols":80,"Rows":24,"User":"admin","UserGroups":["interns"]}`,
+			ExpectedRejectMsg: `user policy: user group "interns" of user "admin" is denied`,
+		},
+		{
+			Name:              "terminal without user",
+			ChannelType:       comm.ChannelTypeTerminal,
+			ExtraData:         `{"Cols":80,"Rows":24}`,
+			ExpectedRejectMsg: "user policy: requesting user is unknown",
+		},
+		{
+			Name:              "sftp of denied user",
+			ChannelType:       comm.ChannelTypeSFTP,
+			ExtraData:         `{"User":"guest"}`,
+			ExpectedRejectMsg: `user policy: user "guest" is denied`,
+		},
+		{
+			Name:              "sftp of older server",
+			ChannelType:       comm.ChannelTypeSFTP,
+			ExpectedRejectMsg: "user policy: requesting user is unknown",
+		},
+		{
+			Name:             "sftp of allowed user",
+			ChannelType:      comm.ChannelTypeSFTP,
+			ExtraData:        `{"User":"admin","UserGroups":["ops"]}`,
+			ExpectedAccepted: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			c := &Client{
+				Logger: testLog,
+				configHolder: &ClientConfigHolder{Config: &clientconfig.Config{
+					RemoteTerminal: clientconfig.TerminalConfig{Enabled: true},
+					FileBrowser:    clientconfig.FileBrowserConfig{Enabled: true},
+					UserPolicy: clientconfig.UserPolicyConfig{
+						DenyUsers:  []string{"guest"},
+						DenyGroups: []string{"interns"},
+					},
+				}},
+			}
+			ch := &newChannelMock{channelType: tc.ChannelType}
+			if tc.ExtraData != "" {
+				ch.extraData = []byte(tc.ExtraData)
+			}
+
+			if tc.ChannelType == comm.ChannelTypeTerminal {
+				c.handleTerminalChannel(ch)
+			} else {
+				c.handleSFTPChannel(ch)
+			}
+
+			assert.Equal(t, tc.ExpectedAccepted, ch.accepted)
+			if tc.ExpectedRejectMsg != "" {
+				assert.Equal(t, ssh.Prohibited, ch.rejectReason)
+				assert.Equal(t, tc.ExpectedRejectMsg, ch.rejectMsg)
+			}
+		})
+	}
+}
+
+func TestUserPolicyOfTunnels(t *testing.T) {
+	c := &Client{
+		Logger: testLog,
+		configHolder: &ClientConfigHolder{Config: &clientconfig.Config{
+			Client: clientconfig.ClientConfig{TunnelAllowed: []string{"127.0.0.1:22"}},
+		}},
+	}
+	// a tunnel restored on reconnect is checked with the owner and the groups stored when it was created
+	payload := []byte(`{"Remote":"127.0.0.1:22","User":"admin","UserGroups":["ops"]}`)
+
+	resp, err := c.checkTunnelAllowed(payload)
+	require.NoError(t, err)
+	assert.True(t, resp.IsAllowed)
+
+	policyChanged := c.config().Config.Clone()
+	policyChanged.UserPolicy.DenyGroups = []string{"ops"}
+	c.setConfig(&ClientConfigHolder{Config: policyChanged})
+
+	resp, err = c.checkTunnelAllowed(payload)
+	require.NoError(t, err)
+	assert.False(t, resp.IsAllowed)
+}
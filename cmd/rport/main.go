@@ -125,7 +125,8 @@ var clientHelp = `
     --allow-root, An optional arg to allow running rport as root. There is no technical requirement to run the rport
     client under the root user. Running it as root is an unnecessary security risk.
 
-    --service, Manages rport running as a service. Possible commands are "install", "uninstall", "start" and "stop".
+    --service, Manages rport running as a service. Possible commands are "install", "uninstall", "start", "stop"
+    and "restart".
     The only arguments compatible with --service are --service-user and --config, others will be ignored.
 
     --service-user, An optional arg specifying user to run rport service under. Only on linux. Defaults to rport.
@@ -140,6 +141,12 @@ var clientHelp = `
     --remote-scripts-enabled, Enable or disable remote scripts.
     Defaults: false
 
+    --remote-terminal-enabled, Enable or disable interactive terminal sessions opened from the server.
+    Defaults: false
+
+    --file-browser-enabled, Enable or disable listing and downloading files by the server.
+    Defaults: false
+
     --data-dir, Temporary directory to store temp client data.
     Defaults: /var/lib/rport (unix) or C:\Program Files\rport (windows)
 
@@ -150,6 +157,14 @@ var clientHelp = `
     --updates-interval, How often after the rport client has started pending updates are summarized.
     Defaults: 4h
 
+    --allow-updates-installation, Allow the server to install pending updates and reboot the system afterwards if requested.
+    Requires remote commands to be enabled.
+    Defaults: false
+
+    --inventory-interval, How often after the rport client has started the software and hardware inventory is collected.
+    Set 0 to disable.
+    Defaults: 4h
+
     --fallback-server, Set fallback server(s) to which the client tries to connect if the main server is not reachable.
 
     --server-switchback-interval, If connected to fallback server, try every interval to switch back to the main server.
@@ -243,9 +258,12 @@ func init() {
 	pFlags.Bool("allow-root", false, "")
 	pFlags.Bool("remote-commands-enabled", false, "")
 	pFlags.Bool("remote-scripts-enabled", false, "")
+	pFlags.Bool("remote-terminal-enabled", false, "")
 	pFlags.String("data-dir", chclient.DefaultDataDir, "")
 	pFlags.Int("remote-commands-send-back-limit", 0, "")
 	pFlags.Duration("updates-interval", 0, "")
+	pFlags.Bool("allow-updates-installation", false, "")
+	pFlags.Duration("inventory-interval", 0, "")
 	pFlags.StringArray("fallback-server", []string{}, "")
 	pFlags.Duration("server-switchback-interval", 0, "")
 	pFlags.Bool("monitoring-enabled", false, "")
@@ -261,6 +279,7 @@ func init() {
 	pFlags.StringArray("monitoring-net-wan", []string{}, "")
 	pFlags.StringArray("file-reception-protected", []string{}, "")
 	pFlags.Bool("file-reception-enabled", true, "")
+	pFlags.Bool("file-browser-enabled", false, "")
 	pFlags.String("bind-interface", "", "")
 	tunnelsScheme = pFlags.String("scheme", "", "")
 	tunnelsReverseProxy = pFlags.Bool("enable-reverse-proxy", false, "")
@@ -291,8 +310,12 @@ func init() {
 	viperCfg.SetDefault("remote-commands.order", []string{"allow", "deny"})
 	viperCfg.SetDefault("remote-commands.send_back_limit", 4194304)
 	viperCfg.SetDefault("remote-commands.enabled", true)
+	viperCfg.SetDefault("remote-commands.cancel_signals", []string{"SIGTERM", "SIGKILL"})
+	viperCfg.SetDefault("remote-commands.cancel_signals_interval", "10s")
 	viperCfg.SetDefault("remote-scripts.enabled", false)
+	viperCfg.SetDefault("remote-terminal.enabled", false)
 	viperCfg.SetDefault("client.updates_interval", 4*time.Hour)
+	viperCfg.SetDefault("client.inventory_interval", 4*time.Hour)
 	viperCfg.SetDefault("client.data_dir", chclient.DefaultDataDir)
 	viperCfg.SetDefault("monitoring.enabled", true)
 	viperCfg.SetDefault("monitoring.interval", chclient.DefaultMonitoringInterval)
@@ -303,6 +326,11 @@ func init() {
 	viperCfg.SetDefault("monitoring.pm_max_number_processes", 500)
 	viperCfg.SetDefault("file-reception.protected", chclient.FileReceptionGlobs)
 	viperCfg.SetDefault("file-reception.enabled", true)
+	viperCfg.SetDefault("file-browser.enabled", false)
+	viperCfg.SetDefault("file-browser.deny", chclient.FileBrowserDenyGlobs)
+	viperCfg.SetDefault("self-update.enabled", false)
+	viperCfg.SetDefault("self-update.rollback_timeout", chclient.DefaultRollbackTimeout)
+	viperCfg.SetDefault("managed-config.enabled", false)
 }
 
 func bindPFlags() {
@@ -318,6 +346,8 @@ func bindPFlags() {
 	_ = viperCfg.BindPFlag("client.tags", pFlags.Lookup("tag"))
 	_ = viperCfg.BindPFlag("client.allow_root", pFlags.Lookup("allow-root"))
 	_ = viperCfg.BindPFlag("client.updates_interval", pFlags.Lookup("updates-interval"))
+	_ = viperCfg.BindPFlag("client.allow_updates_installation", pFlags.Lookup("allow-updates-installation"))
+	_ = viperCfg.BindPFlag("client.inventory_interval", pFlags.Lookup("inventory-interval"))
 	_ = viperCfg.BindPFlag("client.fallback_servers", pFlags.Lookup("fallback-server"))
 	_ = viperCfg.BindPFlag("client.server_switchback_interval", pFlags.Lookup("server-switchback-interval"))
 	_ = viperCfg.BindPFlag("client.data_dir", pFlags.Lookup("data-dir"))
@@ -334,6 +364,7 @@ func bindPFlags() {
 
 	_ = viperCfg.BindPFlag("remote-commands.enabled", pFlags.Lookup("remote-commands-enabled"))
 	_ = viperCfg.BindPFlag("remote-scripts.enabled", pFlags.Lookup("remote-scripts-enabled"))
+	_ = viperCfg.BindPFlag("remote-terminal.enabled", pFlags.Lookup("remote-terminal-enabled"))
 	_ = viperCfg.BindPFlag("remote-commands.send_back_limit", pFlags.Lookup("remote-commands-send-back-limit"))
 
 	_ = viperCfg.BindPFlag("monitoring.enabled", pFlags.Lookup("monitoring-enabled"))
@@ -349,6 +380,7 @@ func bindPFlags() {
 	_ = viperCfg.BindPFlag("monitoring.net_wan", pFlags.Lookup("monitoring-net-wan"))
 	_ = viperCfg.BindPFlag("file-reception.protected", pFlags.Lookup("file-reception-protected"))
 	_ = viperCfg.BindPFlag("file-reception.enabled", pFlags.Lookup("file-reception-enabled"))
+	_ = viperCfg.BindPFlag("file-browser.enabled", pFlags.Lookup("file-browser-enabled"))
 }
 
 func main() {
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"os/exec"
 	"path/filepath"
 
 	"github.com/kardianos/service"
@@ -30,10 +33,33 @@ func runAsService(c *chclient.Client, configPath string) error {
 	if err != nil {
 		return err
 	}
+	c.SetRestart(func() error {
+		return restartService(configPath)
+	})
 
 	return svc.Run()
 }
 
+// restartService restarts the service from a separate process, the running one is stopped by the service manager
+func restartService(configPath string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	absConfigPath, err := filepath.Abs(configPath)
+	if err != nil {
+		return err
+	}
+
+	out, err := exec.Command(exe, "--service", "restart", "-c", absConfigPath).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, out)
+	}
+
+	return nil
+}
+
 func getService(c *chclient.Client, configPath string, user *string) (service.Service, error) {
 	absConfigPath, err := filepath.Abs(configPath)
 	if err != nil {
@@ -37,6 +37,22 @@ const (
 	DefaultLogLevel                         = "info"
 	DefaultRunRemoteCmdTimeoutSec           = 60
 	DefaultMonitoringDataStorageDays        = 30
+	DefaultInventoryHistoryStorageDays      = 90
+	DefaultAlertsCheckInterval              = time.Minute
+	DefaultAlertsDataStorageDays            = 30
+	DefaultRecordingsDataStorageDays        = 90
+	DefaultApprovalsExpiration              = 24 * time.Hour
+	DefaultWebhooksMaxAttempts              = 5
+	DefaultWebhooksRetryInterval            = 10 * time.Second
+	DefaultWebhooksTimeout                  = 10 * time.Second
+	DefaultWebhooksDataStorageDays          = 30
+	DefaultTunnelHistoryDataStorageDays     = 30
+	DefaultTunnelSharesMaxLifetime          = 7 * 24 * time.Hour
+	DefaultTunnelSharesOTPLifetime          = 10 * time.Minute
+	DefaultLDAPTimeout                      = 10 * time.Second
+	DefaultLDAPCacheTTL                     = 5 * time.Minute
+	DefaultClusterHeartbeatInterval         = 5 * time.Second
+	DefaultClusterNodeTimeout               = 30 * time.Second
 	DefaultPairingURL                       = "https://pairing.rport.io"
 )
 
@@ -149,15 +165,18 @@ var serverHelp = `
 
     --api-access-log-file, An optional arg to specify file for writing api access logs.
 
-    --db-type, An optional arg to specify database type. Values 'mysql' or 'sqlite'.
+    --db-type, An optional arg to specify database type. Values 'mysql', 'postgres' or 'sqlite'.
+    With 'postgres' all internal data of the server is stored in the database as well.
 
-    --db-host, An optional arg to specify host for mysql database.
+    --db-host, An optional arg to specify host for mysql or postgres database.
 
-    --db-user, An optional arg to specify user for mysql database.
+    --db-user, An optional arg to specify user for mysql or postgres database.
 
-    --db-password, An optional arg to specify password for mysql database.
+    --db-password, An optional arg to specify password for mysql or postgres database.
 
-    --db-name, An optional arg to specify name for mysql database or file for sqlite.
+    --db-name, An optional arg to specify name for mysql or postgres database or file for sqlite.
+
+    --db-ssl-mode, An optional arg to specify sslmode of the postgres connection.
 
     --data-dir, An optional arg to define a local directory path to store internal data.
     By default, "/var/lib/rportd" is used on Linux, 'C:\ProgramData\rportd' is used on Windows.
@@ -191,7 +210,8 @@ var serverHelp = `
     --allow-root, An optional arg to allow running rportd as root. There is no technical requirement to run the rport
     server under the root user. Running it as root is an unnecessary security risk.
 
-    --service, Manages rportd running as a service. Possible commands are "install", "uninstall", "start" and "stop".
+    --service, Manages rportd running as a service. Possible commands are "install", "uninstall", "start", "stop"
+    and "restart".
     The only arguments compatible with --service are --service-user and --config, others will be ignored.
 
     --service-user, An optional arg specifying user to run rportd service under. Only on linux. Defaults to rport.
@@ -268,6 +288,7 @@ func init() {
 	pFlags.String("db-host", "", "")
 	pFlags.String("db-user", "", "")
 	pFlags.String("db-password", "", "")
+	pFlags.String("db-ssl-mode", "", "")
 	pFlags.StringP("log-file", "l", "", "")
 	pFlags.String("log-level", "", "")
 	pFlags.StringSlice("use-ports", nil, "")
@@ -339,6 +360,32 @@ func init() {
 	viperCfg.SetDefault("api.totp_enabled", false)
 	viperCfg.SetDefault("api.audit_log_rotation", auditlog.RotationMonthly)
 	viperCfg.SetDefault("monitoring.data_storage_days", DefaultMonitoringDataStorageDays)
+	viperCfg.SetDefault("inventory.history_storage_days", DefaultInventoryHistoryStorageDays)
+	viperCfg.SetDefault("alerts.enabled", false)
+	viperCfg.SetDefault("alerts.check_interval", DefaultAlertsCheckInterval)
+	viperCfg.SetDefault("alerts.data_storage_days", DefaultAlertsDataStorageDays)
+	viperCfg.SetDefault("recordings.enabled", false)
+	viperCfg.SetDefault("recordings.data_storage_days", DefaultRecordingsDataStorageDays)
+	viperCfg.SetDefault("approvals.enabled", false)
+	viperCfg.SetDefault("approvals.expiration", DefaultApprovalsExpiration)
+	viperCfg.SetDefault("webhooks.enabled", false)
+	viperCfg.SetDefault("webhooks.max_attempts", DefaultWebhooksMaxAttempts)
+	viperCfg.SetDefault("webhooks.retry_interval", DefaultWebhooksRetryInterval)
+	viperCfg.SetDefault("webhooks.timeout", DefaultWebhooksTimeout)
+	viperCfg.SetDefault("webhooks.data_storage_days", DefaultWebhooksDataStorageDays)
+	viperCfg.SetDefault("tunnel_history.enabled", false)
+	viperCfg.SetDefault("tunnel_history.data_storage_days", DefaultTunnelHistoryDataStorageDays)
+	viperCfg.SetDefault("tunnel_shares.enabled", false)
+	viperCfg.SetDefault("tunnel_shares.max_lifetime", DefaultTunnelSharesMaxLifetime)
+	viperCfg.SetDefault("tunnel_shares.otp_lifetime", DefaultTunnelSharesOTPLifetime)
+	viperCfg.SetDefault("ldap.enabled", false)
+	viperCfg.SetDefault("ldap.timeout", DefaultLDAPTimeout)
+	viperCfg.SetDefault("ldap.username_attribute", "uid")
+	viperCfg.SetDefault("ldap.group_attribute", "memberOf")
+	viperCfg.SetDefault("ldap.cache_ttl", DefaultLDAPCacheTTL)
+	viperCfg.SetDefault("cluster.enabled", false)
+	viperCfg.SetDefault("cluster.heartbeat_interval", DefaultClusterHeartbeatInterval)
+	viperCfg.SetDefault("cluster.node_timeout", DefaultClusterNodeTimeout)
 	viperCfg.SetDefault("api.totp_login_session_ttl", time.Minute*10)
 	viperCfg.SetDefault("api.totp_account_name", "RPort")
 	viperCfg.SetDefault("api.password_min_length", 14)
@@ -391,6 +438,7 @@ func bindPFlags() {
 	_ = viperCfg.BindPFlag("database.db_host", pFlags.Lookup("db-host"))
 	_ = viperCfg.BindPFlag("database.db_user", pFlags.Lookup("db-user"))
 	_ = viperCfg.BindPFlag("database.db_password", pFlags.Lookup("db-password"))
+	_ = viperCfg.BindPFlag("database.db_ssl_mode", pFlags.Lookup("db-ssl-mode"))
 
 	_ = viperCfg.BindPFlag("monitoring.data_storage_days", pFlags.Lookup("monitoring-data-storage-days"))
 }
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/cloudradar-monitoring/rport/db/postgres"
+	"github.com/cloudradar-monitoring/rport/db/sqlite"
+)
+
+// Options defines where the server stores its data.
+type Options struct {
+	// PostgresDSN is the connection string of a PostgreSQL database used by all stores.
+	// If empty each store is kept in its own sqlite file in the data directory.
+	PostgresDSN string
+	SQLite      sqlite.DataSourceOptions
+}
+
+// Open returns a DB instance of the given store with migrated DB scheme to the latest version.
+// The store name must match the directory of its PostgreSQL migrations, sqlitePath and the assets are used for sqlite only.
+func Open(store, sqlitePath string, sqliteAssetNames []string, sqliteAsset func(name string) ([]byte, error), opts Options) (*sqlx.DB, error) {
+	if opts.PostgresDSN != "" {
+		return postgres.New(opts.PostgresDSN, store)
+	}
+	return sqlite.New(sqlitePath, sqliteAssetNames, sqliteAsset, opts.SQLite)
+}
+
+// InsertReturningID executes an insert and returns the generated id of the new row.
+// PostgreSQL doesn't support LastInsertId, the id is returned by the statement instead.
+func InsertReturningID(ctx context.Context, db *sqlx.DB, q, idColumn string, args ...interface{}) (int64, error) {
+	if db.DriverName() == postgres.DriverName {
+		var id int64
+		err := db.QueryRowxContext(ctx, db.Rebind(q+" RETURNING "+idColumn), args...).Scan(&id)
+		return id, err
+	}
+
+	res, err := db.ExecContext(ctx, q, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
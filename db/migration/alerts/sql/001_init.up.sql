@@ -0,0 +1,29 @@
+CREATE TABLE `rules` (
+    `id` TEXT PRIMARY KEY NOT NULL,
+    `name` TEXT NOT NULL,
+    `metric` TEXT NOT NULL,
+    `target` TEXT NOT NULL DEFAULT '',
+    `operator` TEXT NOT NULL DEFAULT '',
+    `threshold` REAL NOT NULL DEFAULT 0,
+    `for_minutes` INTEGER NOT NULL DEFAULT 0,
+    `client_ids` TEXT NOT NULL DEFAULT '[]',
+    `created_at` DATETIME NOT NULL,
+    `created_by` TEXT NOT NULL
+);
+
+CREATE TABLE `alerts` (
+    `id` TEXT PRIMARY KEY NOT NULL,
+    `rule_id` TEXT NOT NULL,
+    `rule_name` TEXT NOT NULL,
+    `client_id` TEXT NOT NULL,
+    `state` TEXT NOT NULL,
+    `value` REAL NOT NULL DEFAULT 0,
+    `summary` TEXT NOT NULL DEFAULT '',
+    `started_at` DATETIME NOT NULL,
+    `fired_at` DATETIME,
+    `resolved_at` DATETIME
+);
+
+CREATE INDEX `alerts_rule_id_client_id` ON `alerts` (`rule_id`, `client_id`);
+CREATE INDEX `alerts_state` ON `alerts` (`state`);
+CREATE INDEX `alerts_started_at` ON `alerts` (`started_at` DESC);
@@ -0,0 +1 @@
+alter table "client_groups" add require_approval BOOLEAN NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1,6 @@
+CREATE TABLE `config_profiles` (
+    `id` TEXT PRIMARY KEY NOT NULL,
+    `description` TEXT NOT NULL DEFAULT '',
+    `client_group_ids` TEXT NOT NULL DEFAULT '[]',
+    `options` TEXT NOT NULL DEFAULT '{}'
+) WITHOUT ROWID;
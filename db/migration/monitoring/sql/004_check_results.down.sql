@@ -0,0 +1,4 @@
+-- ----------------------------
+-- drop table check_results
+-- ----------------------------
+DROP TABLE "check_results";
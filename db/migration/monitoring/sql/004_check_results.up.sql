@@ -0,0 +1,16 @@
+-- ----------------------------
+-- Table structure for check_results
+-- ----------------------------
+CREATE TABLE "check_results" (
+  "client_id" TEXT NOT NULL,
+  "name" TEXT NOT NULL,
+  "timestamp" DATETIME NOT NULL,
+  "value" REAL,
+  "status" INTEGER NOT NULL,
+  "output" TEXT NOT NULL DEFAULT '',
+  PRIMARY KEY ("client_id", "name", "timestamp")
+);
+
+CREATE INDEX "check_results_timestamp" ON `check_results` (
+    "timestamp" ASC
+);
@@ -0,0 +1,29 @@
+CREATE TABLE rules (
+    id TEXT PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL,
+    metric TEXT NOT NULL,
+    target TEXT NOT NULL DEFAULT '',
+    operator TEXT NOT NULL DEFAULT '',
+    threshold DOUBLE PRECISION NOT NULL DEFAULT 0,
+    for_minutes INTEGER NOT NULL DEFAULT 0,
+    client_ids TEXT NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by TEXT NOT NULL
+);
+
+CREATE TABLE alerts (
+    id TEXT PRIMARY KEY NOT NULL,
+    rule_id TEXT NOT NULL,
+    rule_name TEXT NOT NULL,
+    client_id TEXT NOT NULL,
+    state TEXT NOT NULL,
+    value DOUBLE PRECISION NOT NULL DEFAULT 0,
+    summary TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    fired_at TIMESTAMP WITH TIME ZONE,
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX alerts_rule_id_client_id ON alerts (rule_id, client_id);
+CREATE INDEX alerts_state ON alerts (state);
+CREATE INDEX alerts_started_at ON alerts (started_at DESC);
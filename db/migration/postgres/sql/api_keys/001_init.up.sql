@@ -0,0 +1,14 @@
+CREATE TABLE api_keys (
+    id TEXT PRIMARY KEY NOT NULL,
+    username TEXT NOT NULL,
+    name TEXT NOT NULL,
+    key_hash TEXT NOT NULL,
+    permissions TEXT NOT NULL DEFAULT '{}',
+    client_group_ids TEXT NOT NULL DEFAULT '[]',
+    allowed_ips TEXT NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX api_keys_username ON api_keys (username);
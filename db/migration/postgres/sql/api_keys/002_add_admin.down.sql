@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS api_keys_key_hash;
+ALTER TABLE api_keys DROP COLUMN admin;
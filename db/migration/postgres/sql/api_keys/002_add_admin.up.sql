@@ -0,0 +1,2 @@
+ALTER TABLE api_keys ADD admin BOOLEAN NOT NULL DEFAULT false;
+CREATE UNIQUE INDEX api_keys_key_hash ON api_keys (key_hash);
@@ -0,0 +1,13 @@
+CREATE TABLE api_sessions (
+    session_id BIGSERIAL PRIMARY KEY NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    username TEXT NOT NULL,
+    last_access_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    user_agent TEXT,
+    ip_address TEXT
+);
+
+CREATE INDEX api_sessions_expires_at ON api_sessions (expires_at DESC);
+
+-- username may not be unique as the user may create new tokens before old ones are deleted/expired
+CREATE INDEX api_sessions_username ON api_sessions (username);
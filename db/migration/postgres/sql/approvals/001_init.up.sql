@@ -0,0 +1,16 @@
+CREATE TABLE approvals (
+    id TEXT PRIMARY KEY NOT NULL,
+    status TEXT NOT NULL,
+    request TEXT NOT NULL,
+    client_group_ids TEXT NOT NULL DEFAULT '[]',
+    requested_by TEXT NOT NULL,
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    decided_by TEXT,
+    decided_at TIMESTAMP WITH TIME ZONE,
+    comment TEXT NOT NULL DEFAULT '',
+    job_id TEXT,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX approvals_status ON approvals (status);
@@ -0,0 +1,23 @@
+CREATE TABLE clients (
+    id TEXT PRIMARY KEY NOT NULL,
+    client_auth_id TEXT NOT NULL,
+    disconnected_at TIMESTAMP WITH TIME ZONE,
+    details TEXT NOT NULL
+);
+
+CREATE INDEX clients_disconnected_at_client_auth_id ON clients (disconnected_at DESC, client_auth_id);
+
+CREATE TABLE stored_tunnels (
+    id TEXT PRIMARY KEY NOT NULL,
+    client_id TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE,
+    name TEXT,
+    scheme TEXT,
+    remote_ip TEXT,
+    remote_port INTEGER,
+    acl TEXT,
+    public_port INTEGER,
+    further_options TEXT
+);
+
+CREATE INDEX stored_tunnels_client_id ON stored_tunnels (client_id);
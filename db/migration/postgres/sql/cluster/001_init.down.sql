@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS cluster_clients;
+DROP TABLE IF EXISTS cluster_nodes;
@@ -0,0 +1,13 @@
+CREATE TABLE cluster_nodes (
+    id TEXT PRIMARY KEY NOT NULL,
+    url TEXT NOT NULL,
+    heartbeat_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE cluster_clients (
+    client_id TEXT PRIMARY KEY NOT NULL,
+    node_id TEXT NOT NULL,
+    connected_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX cluster_clients_node_id ON cluster_clients (node_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS inventory_changes;
+DROP TABLE IF EXISTS inventory_items;
+DROP TABLE IF EXISTS inventories;
@@ -0,0 +1,28 @@
+CREATE TABLE inventories (
+    client_id TEXT PRIMARY KEY NOT NULL,
+    refreshed TIMESTAMP WITH TIME ZONE NOT NULL,
+    inventory TEXT NOT NULL
+);
+
+CREATE TABLE inventory_items (
+    client_id TEXT NOT NULL,
+    category TEXT NOT NULL,
+    name TEXT NOT NULL,
+    value TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX inventory_items_client_id ON inventory_items (client_id);
+CREATE INDEX inventory_items_category_name ON inventory_items (category, name);
+
+CREATE TABLE inventory_changes (
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    client_id TEXT NOT NULL,
+    category TEXT NOT NULL,
+    name TEXT NOT NULL,
+    action TEXT NOT NULL,
+    old_value TEXT NOT NULL DEFAULT '',
+    new_value TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX inventory_changes_client_id_timestamp ON inventory_changes (client_id, timestamp);
+CREATE INDEX inventory_changes_category_name ON inventory_changes (category, name);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS schedules;
+DROP TABLE IF EXISTS jobs;
+DROP TABLE IF EXISTS multi_jobs;
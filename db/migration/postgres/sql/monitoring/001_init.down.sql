@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS check_results;
+DROP TABLE IF EXISTS measurements;
@@ -0,0 +1,28 @@
+CREATE TABLE measurements (
+    client_id TEXT NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    cpu_usage_percent NUMERIC NOT NULL,
+    memory_usage_percent NUMERIC NOT NULL,
+    io_usage_percent NUMERIC NOT NULL,
+    processes TEXT,
+    mountpoints TEXT,
+    net_lan_in BIGINT,
+    net_lan_out BIGINT,
+    net_wan_in BIGINT,
+    net_wan_out BIGINT,
+    PRIMARY KEY (client_id, timestamp)
+);
+
+CREATE INDEX measurements_timestamp ON measurements (timestamp);
+
+CREATE TABLE check_results (
+    client_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL,
+    value NUMERIC,
+    status INTEGER NOT NULL,
+    output TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (client_id, name, timestamp)
+);
+
+CREATE INDEX check_results_timestamp ON check_results (timestamp);
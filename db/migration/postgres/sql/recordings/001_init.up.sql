@@ -0,0 +1,15 @@
+CREATE TABLE recordings (
+    id TEXT PRIMARY KEY NOT NULL,
+    type TEXT NOT NULL,
+    format TEXT NOT NULL,
+    client_id TEXT NOT NULL,
+    tunnel_id TEXT NOT NULL DEFAULT '',
+    username TEXT NOT NULL DEFAULT '',
+    filename TEXT NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX recordings_client_id ON recordings (client_id);
+CREATE INDEX recordings_started_at ON recordings (started_at DESC);
@@ -0,0 +1,21 @@
+CREATE TABLE tunnel_shares (
+    id TEXT PRIMARY KEY NOT NULL,
+    client_id TEXT NOT NULL,
+    tunnel_id TEXT NOT NULL,
+    tunnel_session_id TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    one_time BOOLEAN NOT NULL DEFAULT false,
+    bound_ip TEXT NOT NULL DEFAULT '',
+    email TEXT NOT NULL DEFAULT '',
+    uses INTEGER NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    last_used_ip TEXT NOT NULL DEFAULT '',
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT NULL,
+    revoked_by TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX tunnel_shares_client_id_tunnel_id ON tunnel_shares (client_id, tunnel_id);
+CREATE INDEX tunnel_shares_expires_at ON tunnel_shares (expires_at);
@@ -0,0 +1,23 @@
+-- "values" is a reserved word in PostgreSQL, the table name must always be quoted
+CREATE TABLE "values" (
+    id SERIAL PRIMARY KEY NOT NULL,
+    client_id TEXT NOT NULL DEFAULT '',
+    required_group TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_by TEXT,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    type TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX values_unique_client_id_key ON "values" (client_id, key);
+CREATE INDEX values_key ON "values" (key);
+
+CREATE TABLE status (
+    id SERIAL PRIMARY KEY NOT NULL,
+    db_status TEXT NOT NULL,
+    enc_check TEXT,
+    dec_check TEXT
+);
@@ -0,0 +1,16 @@
+package postgres
+
+import "fmt"
+
+// StoreAssets returns the migrations of a single store. The names are relative to the directory of the store
+// as the migration source expects them to start with the version.
+func StoreAssets(store string) (assetNames []string, asset func(name string) ([]byte, error), err error) {
+	assetNames, err = AssetDir(store)
+	if err != nil {
+		return nil, nil, fmt.Errorf("no PostgreSQL migrations found for %q", store)
+	}
+
+	return assetNames, func(name string) ([]byte, error) {
+		return Asset(store + "/" + name)
+	}, nil
+}
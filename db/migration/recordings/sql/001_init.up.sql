@@ -0,0 +1,15 @@
+CREATE TABLE `recordings` (
+    `id` TEXT PRIMARY KEY NOT NULL,
+    `type` TEXT NOT NULL,
+    `format` TEXT NOT NULL,
+    `client_id` TEXT NOT NULL,
+    `tunnel_id` TEXT NOT NULL DEFAULT '',
+    `username` TEXT NOT NULL DEFAULT '',
+    `filename` TEXT NOT NULL,
+    `size` INTEGER NOT NULL DEFAULT 0,
+    `started_at` DATETIME NOT NULL,
+    `finished_at` DATETIME
+);
+
+CREATE INDEX `recordings_client_id` ON `recordings` (`client_id`);
+CREATE INDEX `recordings_started_at` ON `recordings` (`started_at` DESC);
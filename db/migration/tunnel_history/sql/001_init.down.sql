@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `tunnel_connections`;
+DROP TABLE IF EXISTS `tunnel_sessions`;
@@ -0,0 +1,34 @@
+CREATE TABLE `tunnel_sessions` (
+    `id` TEXT PRIMARY KEY NOT NULL,
+    `client_id` TEXT NOT NULL,
+    `tunnel_id` TEXT NOT NULL,
+    `name` TEXT NOT NULL DEFAULT '',
+    `protocol` TEXT NOT NULL,
+    `local` TEXT NOT NULL,
+    `remote` TEXT NOT NULL DEFAULT '',
+    `scheme` TEXT NOT NULL DEFAULT '',
+    `http_proxy` BOOLEAN NOT NULL DEFAULT 0,
+    `created_by` TEXT NOT NULL DEFAULT '',
+    `started_at` DATETIME NOT NULL,
+    `closed_at` DATETIME DEFAULT NULL,
+    `close_reason` TEXT NOT NULL DEFAULT '',
+    `connections` INTEGER NOT NULL DEFAULT 0,
+    `bytes_sent` INTEGER NOT NULL DEFAULT 0,
+    `bytes_received` INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX `tunnel_sessions_client_id_started_at` ON `tunnel_sessions` (`client_id`, `started_at`);
+CREATE INDEX `tunnel_sessions_started_at` ON `tunnel_sessions` (`started_at`);
+CREATE INDEX `tunnel_sessions_closed_at` ON `tunnel_sessions` (`closed_at`);
+
+CREATE TABLE `tunnel_connections` (
+    `session_id` TEXT NOT NULL,
+    `protocol` TEXT NOT NULL,
+    `source_ip` TEXT NOT NULL DEFAULT '',
+    `started_at` DATETIME NOT NULL,
+    `ended_at` DATETIME NOT NULL,
+    `bytes_sent` INTEGER NOT NULL DEFAULT 0,
+    `bytes_received` INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX `tunnel_connections_session_id_started_at` ON `tunnel_connections` (`session_id`, `started_at`);
@@ -0,0 +1,26 @@
+CREATE TABLE `webhooks` (
+    `id` TEXT PRIMARY KEY NOT NULL,
+    `url` TEXT NOT NULL,
+    `secret` TEXT NOT NULL DEFAULT '',
+    `events` TEXT NOT NULL DEFAULT '[]',
+    `description` TEXT NOT NULL DEFAULT '',
+    `enabled` BOOLEAN NOT NULL DEFAULT 1,
+    `created_by` TEXT NOT NULL,
+    `created_at` DATETIME NOT NULL
+);
+
+CREATE TABLE `deliveries` (
+    `id` TEXT PRIMARY KEY NOT NULL,
+    `webhook_id` TEXT NOT NULL,
+    `event` TEXT NOT NULL,
+    `payload` TEXT NOT NULL,
+    `status` TEXT NOT NULL,
+    `attempts` INTEGER NOT NULL DEFAULT 0,
+    `status_code` INTEGER NOT NULL DEFAULT 0,
+    `error` TEXT NOT NULL DEFAULT '',
+    `created_at` DATETIME NOT NULL,
+    `updated_at` DATETIME NOT NULL
+);
+
+CREATE INDEX `deliveries_webhook_id_created_at` ON `deliveries` (`webhook_id`, `created_at`);
+CREATE INDEX `deliveries_created_at` ON `deliveries` (`created_at`);
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	bindata "github.com/golang-migrate/migrate/v4/source/go_bindata"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	migration "github.com/cloudradar-monitoring/rport/db/migration/postgres"
+)
+
+const (
+	DriverName = "postgres"
+
+	uniqueViolationErrorCode = "23505"
+)
+
+// New returns a new PostgreSQL DB instance with migrated DB scheme of the given store to the latest version.
+// All stores share the same database, each of them keeps its migration version in a separate table.
+func New(dsn, store string) (*sqlx.DB, error) {
+	db, err := sqlx.Connect(DriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to DB: %v", err)
+	}
+
+	m, err := newMigrate(db, store)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
+		return nil, fmt.Errorf("failed to migrate %s DB to the latest version: %v", store, err)
+	}
+
+	return db, nil
+}
+
+func newMigrate(db *sqlx.DB, store string) (*migrate.Migrate, error) {
+	assetNames, asset, err := migration.StoreAssets(store)
+	if err != nil {
+		return nil, err
+	}
+
+	s := bindata.Resource(assetNames,
+		func(name string) ([]byte, error) {
+			return asset(name)
+		})
+	sourceDriver, err := bindata.WithInstance(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init DB source driver: %v", err)
+	}
+
+	dbDriver, err := postgres.WithInstance(db.DB, &postgres.Config{
+		MigrationsTable: store + "_schema_migrations",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to init DB migration driver: %v", err)
+	}
+
+	m, err := migrate.NewWithInstance("go-bindata", sourceDriver, DriverName, dbDriver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init DB migration instance: %v", err)
+	}
+
+	return m, nil
+}
+
+// IsUniqueViolation returns true if the error is caused by a violated unique or primary key constraint.
+func IsUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == uniqueViolationErrorCode
+}
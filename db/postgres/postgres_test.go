@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	migration "github.com/cloudradar-monitoring/rport/db/migration/postgres"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+func TestMigrateUpAndDown(t *testing.T) {
+	dsn := test.PostgresDSN(t)
+	stores, err := migration.AssetDir("")
+	require.NoError(t, err)
+	require.NotEmpty(t, stores)
+
+	listTables := func(t *testing.T, db *sqlx.DB) []string {
+		var tables []string
+		require.NoError(t, db.Select(&tables, "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema()"))
+		return tables
+	}
+
+	for _, store := range stores {
+		store := store
+		t.Run(store, func(t *testing.T) {
+			// all stores share the schema, only the tables added by the store are checked
+			conn, err := sqlx.Connect(DriverName, dsn)
+			require.NoError(t, err)
+			defer conn.Close()
+			before := listTables(t, conn)
+
+			db, err := New(dsn, store)
+			require.NoError(t, err)
+			defer db.Close()
+
+			var created []string
+			for _, table := range listTables(t, db) {
+				if !contains(before, table) {
+					created = append(created, table)
+				}
+			}
+			assert.Contains(t, created, store+"_schema_migrations")
+			assert.Greater(t, len(created), 1, "no tables created by %s migrations", store)
+
+			m, err := newMigrate(db, store)
+			require.NoError(t, err)
+			require.NoError(t, m.Down())
+
+			after := listTables(t, db)
+			for _, table := range created {
+				if table != store+"_schema_migrations" {
+					assert.NotContains(t, after, table, "table not dropped by %s migrations", store)
+				}
+			}
+
+			// the store can be migrated again, e.g. after a downgrade
+			require.NoError(t, m.Up())
+			// New is a no-op for migrated stores
+			db2, err := New(dsn, store)
+			require.NoError(t, err)
+			require.NoError(t, db2.Close())
+		})
+	}
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	db, err := New(test.PostgresDSN(t), "client_groups")
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec("INSERT INTO client_groups (id, description, params) VALUES ('group-1', '', '{}')")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO client_groups (id, description, params) VALUES ('group-1', '', '{}')")
+
+	assert.True(t, IsUniqueViolation(err))
+	assert.False(t, IsUniqueViolation(nil))
+}
@@ -78,7 +78,7 @@ func WithRetryWhenBusy[R any](retryAble func() (result R, err error), label stri
 				continue
 			}
 			// non retryable err
-			return result, sqlErr
+			return result, err
 		}
 		// success
 		return result, nil
@@ -69,13 +69,19 @@ require (
 )
 
 require (
+	github.com/creack/pty v1.1.18
+	github.com/go-asn1-ber/asn1-ber v1.5.1
+	github.com/go-ldap/ldap/v3 v3.4.1
 	github.com/gobeam/stringy v0.0.5
 	github.com/hashicorp/go-version v1.5.0
+	github.com/lib/pq v1.10.9
 	github.com/oleiade/reflections v1.0.1
 	github.com/pkg/sftp v1.13.4
+	github.com/trustelem/zxcvbn v1.0.1
 )
 
 require (
+	github.com/Azure/go-ntlmssp v0.0.0-20200615164410-66371956d46c // indirect
 	github.com/andrew-d/go-termutil v0.0.0-20150726205930-009166a695a2 // indirect
 	github.com/dlclark/regexp2 v1.7.0 // indirect
 	github.com/fsnotify/fsnotify v1.5.1 // indirect
@@ -83,5 +89,4 @@ require (
 	github.com/lxn/win v0.0.0-20210218163916-a377121e959e // indirect
 	github.com/tklauser/go-sysconf v0.3.6 // indirect
 	github.com/tomasen/realip v0.0.0-20180522021738-f0c99a92ddce // indirect
-	github.com/trustelem/zxcvbn v1.0.1 // indirect
 )
@@ -0,0 +1,54 @@
+package chserver
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/cloudradar-monitoring/rport/server/alerts"
+	"github.com/cloudradar-monitoring/rport/server/api/message"
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+func initAlertsService(ctx context.Context, config *chconfig.Config, log *logger.Logger) (*alerts.Service, error) {
+	provider, err := alerts.NewSqliteProvider(
+		path.Join(config.Server.DataDir, "alerts.db"),
+		config.GetDatabaseOptions(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	msgSrv, err := newNotificationMessageService(config, config.Alerts.NotificationDelivery)
+	if err != nil {
+		return nil, err
+	}
+
+	return alerts.NewService(ctx, provider, msgSrv, config.Alerts.NotificationRecipients, log)
+}
+
+// newNotificationMessageService returns the service sending notifications via a given delivery method, it returns nil
+// if the delivery method is empty to disable notifications.
+func newNotificationMessageService(config *chconfig.Config, delivery string) (message.Service, error) {
+	switch delivery {
+	case "":
+		return nil, nil
+	case "pushover":
+		return message.NewPushoverService(config.Pushover.APIToken), nil
+	case "smtp":
+		msgSrv, err := message.NewSMTPService(
+			config.SMTP.Server,
+			config.SMTP.AuthUsername,
+			config.SMTP.AuthPassword,
+			config.SMTP.SenderEmail,
+			config.SMTP.Secure,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init smtp service: %v", err)
+		}
+		return msgSrv, nil
+	default:
+		return message.NewScriptService(delivery, message.ValidationNone, nil), nil
+	}
+}
@@ -0,0 +1,93 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/share/types"
+)
+
+type State string
+
+const (
+	// StatePending means the rule condition is met, but not long enough to fire.
+	StatePending  State = "pending"
+	StateFiring   State = "firing"
+	StateResolved State = "resolved"
+)
+
+const (
+	MetricCPUUsagePercent        = "cpu_usage_percent"
+	MetricMemoryUsagePercent     = "memory_usage_percent"
+	MetricIOUsagePercent         = "io_usage_percent"
+	MetricMountpointUsagePercent = "mountpoint_usage_percent"
+	MetricProcessMissing         = "process_missing"
+	MetricClientDisconnected     = "client_disconnected"
+)
+
+const (
+	OperatorGT = "gt"
+	OperatorLT = "lt"
+)
+
+// Rule describes a condition that is evaluated for each matching client.
+type Rule struct {
+	ID   string `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+	// Metric is one of the Metric* constants.
+	Metric string `json:"metric" db:"metric"`
+	// Target is a mountpoint for mountpoint_usage_percent (empty matches any mountpoint)
+	// or a process name for process_missing.
+	Target    string  `json:"target" db:"target"`
+	Operator  string  `json:"operator" db:"operator"`
+	Threshold float64 `json:"threshold" db:"threshold"`
+	// ForMinutes is how long the condition has to be met before the alert fires.
+	ForMinutes int `json:"for_minutes" db:"for_minutes"`
+	// ClientIDs limits the rule to the given clients, empty means all clients.
+	ClientIDs types.StringSlice `json:"client_ids" db:"client_ids"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+	CreatedBy string            `json:"created_by" db:"created_by"`
+}
+
+func (r *Rule) appliesTo(clientID string) bool {
+	if len(r.ClientIDs) == 0 {
+		return true
+	}
+	for _, id := range r.ClientIDs {
+		if id == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) isMeasurementRule() bool {
+	return r.Metric != MetricClientDisconnected
+}
+
+func (r *Rule) forDuration() time.Duration {
+	return time.Duration(r.ForMinutes) * time.Minute
+}
+
+type Alert struct {
+	ID         string     `json:"id" db:"id"`
+	RuleID     string     `json:"rule_id" db:"rule_id"`
+	RuleName   string     `json:"rule_name" db:"rule_name"`
+	ClientID   string     `json:"client_id" db:"client_id"`
+	State      State      `json:"state" db:"state"`
+	Value      float64    `json:"value" db:"value"`
+	Summary    string     `json:"summary" db:"summary"`
+	StartedAt  time.Time  `json:"started_at" db:"started_at"`
+	FiredAt    *time.Time `json:"fired_at" db:"fired_at"`
+	ResolvedAt *time.Time `json:"resolved_at" db:"resolved_at"`
+}
+
+// Text returns a human readable description of an alert used in notifications.
+func (a *Alert) Text() string {
+	text := fmt.Sprintf("Alert %q is %s for client %s.\n%s\nStarted at: %s",
+		a.RuleName, a.State, a.ClientID, a.Summary, a.StartedAt.UTC().Format(time.RFC3339))
+	if a.ResolvedAt != nil {
+		text += fmt.Sprintf("\nResolved at: %s", a.ResolvedAt.UTC().Format(time.RFC3339))
+	}
+	return text
+}
@@ -0,0 +1,185 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+const (
+	mountpointUsedPercentPrefix = "used_percent."
+	mountpointFreePrefix        = "free_b."
+	mountpointTotalPrefix       = "total_b."
+)
+
+type evaluation struct {
+	breached bool
+	value    float64
+	summary  string
+}
+
+type process struct {
+	Name string `json:"name"`
+}
+
+// Validate checks that a given rule can be evaluated.
+func Validate(rule *Rule) error {
+	if rule.Name == "" {
+		return errors2.APIError{Message: "name is required", HTTPStatus: http.StatusBadRequest}
+	}
+
+	switch rule.Metric {
+	case MetricCPUUsagePercent, MetricMemoryUsagePercent, MetricIOUsagePercent, MetricMountpointUsagePercent:
+		if rule.Operator != OperatorGT && rule.Operator != OperatorLT {
+			return errors2.APIError{
+				Message:    fmt.Sprintf("invalid operator %q, expected one of: %s, %s", rule.Operator, OperatorGT, OperatorLT),
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+	case MetricProcessMissing:
+		if rule.Target == "" {
+			return errors2.APIError{Message: "target is required: process name", HTTPStatus: http.StatusBadRequest}
+		}
+	case MetricClientDisconnected:
+	default:
+		return errors2.APIError{
+			Message: fmt.Sprintf("invalid metric %q, expected one of: %s", rule.Metric, strings.Join([]string{
+				MetricCPUUsagePercent,
+				MetricMemoryUsagePercent,
+				MetricIOUsagePercent,
+				MetricMountpointUsagePercent,
+				MetricProcessMissing,
+				MetricClientDisconnected,
+			}, ", ")),
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	if rule.ForMinutes < 0 {
+		return errors2.APIError{Message: "for_minutes must not be negative", HTTPStatus: http.StatusBadRequest}
+	}
+
+	return nil
+}
+
+// evaluateMeasurement returns nil if the measurement doesn't contain the data required by the rule.
+func evaluateMeasurement(rule *Rule, m *models.Measurement) (*evaluation, error) {
+	switch rule.Metric {
+	case MetricCPUUsagePercent:
+		return compare(rule, m.CPUUsagePercent, "cpu usage"), nil
+	case MetricMemoryUsagePercent:
+		return compare(rule, m.MemoryUsagePercent, "memory usage"), nil
+	case MetricIOUsagePercent:
+		return compare(rule, m.IoUsagePercent, "io usage"), nil
+	case MetricMountpointUsagePercent:
+		return evaluateMountpoints(rule, m.Mountpoints)
+	case MetricProcessMissing:
+		return evaluateProcesses(rule, m.Processes)
+	}
+
+	return nil, fmt.Errorf("unsupported metric %q", rule.Metric)
+}
+
+func compare(rule *Rule, value float64, name string) *evaluation {
+	res := &evaluation{value: value}
+	switch rule.Operator {
+	case OperatorGT:
+		res.breached = value > rule.Threshold
+		res.summary = fmt.Sprintf("%s %.2f%% is above %.2f%%", name, value, rule.Threshold)
+	case OperatorLT:
+		res.breached = value < rule.Threshold
+		res.summary = fmt.Sprintf("%s %.2f%% is below %.2f%%", name, value, rule.Threshold)
+	}
+	return res
+}
+
+func evaluateMountpoints(rule *Rule, mountpoints string) (*evaluation, error) {
+	if mountpoints == "" {
+		return nil, nil
+	}
+
+	values := map[string]float64{}
+	if err := json.Unmarshal([]byte(mountpoints), &values); err != nil {
+		return nil, fmt.Errorf("invalid mountpoints: %v", err)
+	}
+
+	usage := mountpointUsage(values)
+	if rule.Target != "" {
+		value, ok := usage[rule.Target]
+		if !ok {
+			return nil, nil
+		}
+		return compare(rule, value, fmt.Sprintf("mountpoint %s usage", rule.Target)), nil
+	}
+
+	// without a target the rule applies to the most critical mountpoint
+	var res *evaluation
+	for mountpoint, value := range usage {
+		current := compare(rule, value, fmt.Sprintf("mountpoint %s usage", mountpoint))
+		if res == nil ||
+			rule.Operator == OperatorGT && current.value > res.value ||
+			rule.Operator == OperatorLT && current.value < res.value {
+			res = current
+		}
+	}
+
+	return res, nil
+}
+
+// mountpointUsage returns used percent by mountpoint, it's calculated from free and total bytes if not reported directly.
+func mountpointUsage(values map[string]float64) map[string]float64 {
+	usage := make(map[string]float64)
+	for key, value := range values {
+		if strings.HasPrefix(key, mountpointUsedPercentPrefix) {
+			usage[strings.TrimPrefix(key, mountpointUsedPercentPrefix)] = value
+		}
+	}
+
+	for key, total := range values {
+		if !strings.HasPrefix(key, mountpointTotalPrefix) || total <= 0 {
+			continue
+		}
+		mountpoint := strings.TrimPrefix(key, mountpointTotalPrefix)
+		if _, ok := usage[mountpoint]; ok {
+			continue
+		}
+		free, ok := values[mountpointFreePrefix+mountpoint]
+		if !ok {
+			continue
+		}
+		usage[mountpoint] = (total - free) / total * 100
+	}
+
+	return usage
+}
+
+func evaluateProcesses(rule *Rule, processes string) (*evaluation, error) {
+	if processes == "" {
+		return nil, nil
+	}
+
+	var procs []process
+	if err := json.Unmarshal([]byte(processes), &procs); err != nil {
+		return nil, fmt.Errorf("invalid processes: %v", err)
+	}
+	// empty list means process monitoring is disabled on the client
+	if len(procs) == 0 {
+		return nil, nil
+	}
+
+	for _, p := range procs {
+		if p.Name == rule.Target {
+			return &evaluation{}, nil
+		}
+	}
+
+	return &evaluation{
+		breached: true,
+		value:    1,
+		summary:  fmt.Sprintf("process %q is not running", rule.Target),
+	}, nil
+}
@@ -0,0 +1,352 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/server/api/message"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/random"
+)
+
+const notificationTimeout = 30 * time.Second
+
+var (
+	supportedFilters = map[string]bool{
+		"id":        true,
+		"rule_id":   true,
+		"rule_name": true,
+		"client_id": true,
+		"state":     true,
+	}
+	supportedSorts = map[string]bool{
+		"started_at":  true,
+		"fired_at":    true,
+		"resolved_at": true,
+		"rule_name":   true,
+		"client_id":   true,
+		"state":       true,
+	}
+	defaultSort = []query.SortOption{{Column: "started_at", IsASC: false}}
+)
+
+// Service evaluates alert rules against incoming measurements and client states
+// and keeps track of the resulting alerts.
+type Service struct {
+	provider   Provider
+	msgSrv     message.Service
+	recipients []string
+	logger     *logger.Logger
+	now        func() time.Time
+	// shouldRun tells if the tasks checking clients and cleaning up alerts run on this server
+	shouldRun func() bool
+
+	rulesMu sync.RWMutex
+	rules   []*Rule
+}
+
+// NewService returns a new alerts service. msgSrv is optional, if nil - no notifications are sent.
+func NewService(ctx context.Context, provider Provider, msgSrv message.Service, recipients []string, logger *logger.Logger) (*Service, error) {
+	s := &Service{
+		provider:   provider,
+		msgSrv:     msgSrv,
+		recipients: recipients,
+		logger:     logger,
+		now:        time.Now,
+		shouldRun:  func() bool { return true },
+	}
+
+	if err := s.ReloadRules(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load alert rules: %v", err)
+	}
+
+	return s, nil
+}
+
+// SetRunCondition sets a condition checked each time the check or cleanup task runs, the task is skipped if it
+// returns false. It's used to run the tasks only on one node of a cluster.
+func (s *Service) SetRunCondition(shouldRun func() bool) {
+	s.shouldRun = shouldRun
+}
+
+// ReloadRules reads the rules from the database. Rules are cached, in a cluster they are reloaded to apply
+// the changes made on other nodes.
+func (s *Service) ReloadRules(ctx context.Context) error {
+	rules, err := s.provider.ListRules(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.rulesMu.Lock()
+	defer s.rulesMu.Unlock()
+	s.rules = rules
+
+	return nil
+}
+
+func (s *Service) getRules() []*Rule {
+	s.rulesMu.RLock()
+	defer s.rulesMu.RUnlock()
+	return s.rules
+}
+
+func (s *Service) ListRules() []*Rule {
+	return s.getRules()
+}
+
+func (s *Service) GetRule(id string) (*Rule, error) {
+	for _, rule := range s.getRules() {
+		if rule.ID == id {
+			return rule, nil
+		}
+	}
+
+	return nil, errors2.APIError{
+		Message:    fmt.Sprintf("alert rule with id %q not found", id),
+		HTTPStatus: http.StatusNotFound,
+	}
+}
+
+func (s *Service) CreateRule(ctx context.Context, rule *Rule, username string) (*Rule, error) {
+	if err := Validate(rule); err != nil {
+		return nil, err
+	}
+
+	id, err := random.UUID4()
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = id
+	rule.CreatedAt = s.now()
+	rule.CreatedBy = username
+
+	if err := s.provider.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return rule, s.ReloadRules(ctx)
+}
+
+func (s *Service) UpdateRule(ctx context.Context, id string, rule *Rule) (*Rule, error) {
+	existing, err := s.GetRule(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Validate(rule); err != nil {
+		return nil, err
+	}
+
+	rule.ID = existing.ID
+	rule.CreatedAt = existing.CreatedAt
+	rule.CreatedBy = existing.CreatedBy
+
+	if err := s.provider.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return rule, s.ReloadRules(ctx)
+}
+
+// DeleteRule deletes a rule together with all its alerts.
+func (s *Service) DeleteRule(ctx context.Context, id string) error {
+	if _, err := s.GetRule(id); err != nil {
+		return err
+	}
+
+	if err := s.provider.DeleteRule(ctx, id); err != nil {
+		return err
+	}
+
+	return s.ReloadRules(ctx)
+}
+
+func (s *Service) ListAlerts(ctx context.Context, options *query.ListOptions) (*api.SuccessPayload, error) {
+	err := query.ValidateListOptions(options, supportedSorts, supportedFilters, nil, &query.PaginationConfig{
+		DefaultLimit: 10,
+		MaxLimit:     100,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(options.Sorts) == 0 {
+		options.Sorts = defaultSort
+	}
+
+	entries, err := s.provider.ListAlerts(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.provider.CountAlerts(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.SuccessPayload{
+		Data: entries,
+		Meta: api.NewMeta(count),
+	}, nil
+}
+
+// EvaluateMeasurement evaluates all measurement based rules that apply to the client of a given measurement.
+func (s *Service) EvaluateMeasurement(ctx context.Context, measurement *models.Measurement) error {
+	now := measurement.Timestamp
+	if now.IsZero() {
+		now = s.now()
+	}
+
+	for _, rule := range s.getRules() {
+		if !rule.isMeasurementRule() || !rule.appliesTo(measurement.ClientID) {
+			continue
+		}
+
+		res, err := evaluateMeasurement(rule, measurement)
+		if err != nil {
+			s.logger.Errorf("Failed to evaluate alert rule %q for client %s: %v", rule.Name, measurement.ClientID, err)
+			continue
+		}
+		if res == nil {
+			// measurement doesn't contain data for the rule
+			continue
+		}
+
+		if err := s.updateAlert(ctx, rule, measurement.ClientID, res, now, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckClients evaluates the connection state based rules for given clients.
+func (s *Service) CheckClients(ctx context.Context, allClients []*clients.Client) error {
+	now := s.now()
+	for _, rule := range s.getRules() {
+		if rule.Metric != MetricClientDisconnected {
+			continue
+		}
+
+		for _, client := range allClients {
+			if !rule.appliesTo(client.ID) {
+				continue
+			}
+
+			since := now
+			res := &evaluation{}
+			if client.DisconnectedAt != nil {
+				since = *client.DisconnectedAt
+				res.breached = true
+				res.value = now.Sub(since).Minutes()
+				res.summary = fmt.Sprintf("client %q is disconnected since %s", client.Name, since.UTC().Format(time.RFC3339))
+			}
+
+			if err := s.updateAlert(ctx, rule, client.ID, res, since, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// updateAlert moves the alert for a given rule and client to the next state based on the evaluation result.
+// An alert starts as pending and fires once the rule condition is met longer than the rule duration.
+// Pending alerts are dropped if the condition is no longer met, firing alerts become resolved.
+func (s *Service) updateAlert(ctx context.Context, rule *Rule, clientID string, res *evaluation, since, now time.Time) error {
+	alert, err := s.provider.GetActiveAlert(ctx, rule.ID, clientID)
+	if err != nil {
+		return fmt.Errorf("failed to get active alert: %v", err)
+	}
+
+	if !res.breached {
+		if alert == nil {
+			return nil
+		}
+		if alert.State == StatePending {
+			return s.provider.DeleteAlert(ctx, alert.ID)
+		}
+
+		alert.State = StateResolved
+		alert.ResolvedAt = &now
+		if err := s.provider.SaveAlert(ctx, alert); err != nil {
+			return fmt.Errorf("failed to save alert: %v", err)
+		}
+		s.notify(alert)
+		return nil
+	}
+
+	if alert == nil {
+		id, err := random.UUID4()
+		if err != nil {
+			return err
+		}
+		alert = &Alert{
+			ID:        id,
+			RuleID:    rule.ID,
+			RuleName:  rule.Name,
+			ClientID:  clientID,
+			State:     StatePending,
+			StartedAt: since,
+		}
+	}
+	alert.Value = res.value
+	alert.Summary = res.summary
+
+	fire := alert.State == StatePending && now.Sub(alert.StartedAt) >= rule.forDuration()
+	if fire {
+		alert.State = StateFiring
+		alert.FiredAt = &now
+	}
+
+	if err := s.provider.SaveAlert(ctx, alert); err != nil {
+		return fmt.Errorf("failed to save alert: %v", err)
+	}
+
+	if fire {
+		s.notify(alert)
+	}
+
+	return nil
+}
+
+// notify sends a notification about a given alert to all recipients in the background.
+func (s *Service) notify(alert *Alert) {
+	if s.msgSrv == nil {
+		return
+	}
+
+	data := message.Data{
+		Title:   fmt.Sprintf("[RPort alert %s] %s", strings.ToUpper(string(alert.State)), alert.RuleName),
+		Message: alert.Text(),
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+		defer cancel()
+
+		for _, recipient := range s.recipients {
+			data.SendTo = recipient
+			if err := s.msgSrv.Send(ctx, data); err != nil {
+				s.logger.Errorf("Failed to send alert %s notification to %s via %s: %v", alert.ID, recipient, s.msgSrv.DeliveryMethod(), err)
+			}
+		}
+	}()
+}
+
+func (s *Service) DeleteResolvedOlderThan(ctx context.Context, period time.Duration) (int64, error) {
+	return s.provider.DeleteResolvedBefore(ctx, s.now().Add(-period))
+}
+
+func (s *Service) Close() error {
+	return s.provider.Close()
+}
@@ -0,0 +1,399 @@
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/server/api/message"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+var testLog = logger.NewLogger("alerts", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug)
+
+type msgServiceMock struct {
+	message.ServiceMock
+	sent chan message.Data
+}
+
+func (s *msgServiceMock) Send(ctx context.Context, data message.Data) error {
+	s.sent <- data
+	return nil
+}
+
+// newAlertsService returns a service notifying admin@example.com, no notifications are sent if msgSrv is nil
+func newAlertsService(t *testing.T, msgSrv message.Service) *Service {
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { dbProvider.Close() })
+
+	service, err := NewService(context.Background(), dbProvider, msgSrv, []string{"admin@example.com"}, testLog)
+	require.NoError(t, err)
+
+	return service
+}
+
+// alertStates returns the states of all alerts, the latest alert first
+func alertStates(t *testing.T, service *Service) []State {
+	result, err := service.ListAlerts(context.Background(), &query.ListOptions{})
+	require.NoError(t, err)
+
+	var states []State
+	for _, alert := range result.Data.([]*Alert) {
+		states = append(states, alert.State)
+	}
+	return states
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		rule        *Rule
+		expectedErr string
+	}{
+		{
+			name: "valid cpu rule",
+			rule: &Rule{Name: "cpu", Metric: MetricCPUUsagePercent, Operator: OperatorGT, Threshold: 90, ForMinutes: 10},
+		},
+		{
+			name: "valid process rule",
+			rule: &Rule{Name: "nginx", Metric: MetricProcessMissing, Target: "nginx"},
+		},
+		{
+			name: "valid disconnected rule",
+			rule: &Rule{Name: "offline", Metric: MetricClientDisconnected, ForMinutes: 5},
+		},
+		{
+			name:        "missing name",
+			rule:        &Rule{Metric: MetricCPUUsagePercent, Operator: OperatorGT},
+			expectedErr: "name is required",
+		},
+		{
+			name:        "unknown metric",
+			rule:        &Rule{Name: "test", Metric: "unknown"},
+			expectedErr: `invalid metric "unknown", expected one of: cpu_usage_percent, memory_usage_percent, io_usage_percent, mountpoint_usage_percent, process_missing, client_disconnected`,
+		},
+		{
+			name:        "invalid operator",
+			rule:        &Rule{Name: "test", Metric: MetricMountpointUsagePercent, Operator: "eq"},
+			expectedErr: `invalid operator "eq", expected one of: gt, lt`,
+		},
+		{
+			name:        "process without target",
+			rule:        &Rule{Name: "test", Metric: MetricProcessMissing},
+			expectedErr: "target is required: process name",
+		},
+		{
+			name:        "negative duration",
+			rule:        &Rule{Name: "test", Metric: MetricClientDisconnected, ForMinutes: -1},
+			expectedErr: "for_minutes must not be negative",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.rule)
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestEvaluateMeasurement(t *testing.T) {
+	testCases := []struct {
+		name             string
+		rule             *Rule
+		measurement      *models.Measurement
+		expectedBreached *bool
+		expectedValue    float64
+	}{
+		{
+			name:             "cpu above threshold",
+			rule:             &Rule{Metric: MetricCPUUsagePercent, Operator: OperatorGT, Threshold: 90},
+			measurement:      &models.Measurement{CPUUsagePercent: 95},
+			expectedBreached: boolPtr(true),
+			expectedValue:    95,
+		},
+		{
+			name:             "memory not below threshold",
+			rule:             &Rule{Metric: MetricMemoryUsagePercent, Operator: OperatorLT, Threshold: 10},
+			measurement:      &models.Measurement{MemoryUsagePercent: 50},
+			expectedBreached: boolPtr(false),
+			expectedValue:    50,
+		},
+		{
+			name:             "mountpoint used percent",
+			rule:             &Rule{Metric: MetricMountpointUsagePercent, Operator: OperatorGT, Threshold: 95, Target: "/"},
+			measurement:      &models.Measurement{Mountpoints: `{"used_percent./":96.5,"used_percent./home":20}`},
+			expectedBreached: boolPtr(true),
+			expectedValue:    96.5,
+		},
+		{
+			name:             "mountpoint usage from free and total bytes",
+			rule:             &Rule{Metric: MetricMountpointUsagePercent, Operator: OperatorGT, Threshold: 95, Target: "/home"},
+			measurement:      &models.Measurement{Mountpoints: `{"free_b./":10,"total_b./":100,"free_b./home":25,"total_b./home":100}`},
+			expectedBreached: boolPtr(false),
+			expectedValue:    75,
+		},
+		{
+			name:             "any mountpoint",
+			rule:             &Rule{Metric: MetricMountpointUsagePercent, Operator: OperatorGT, Threshold: 85},
+			measurement:      &models.Measurement{Mountpoints: `{"free_b./":10,"total_b./":100,"free_b./home":25,"total_b./home":100}`},
+			expectedBreached: boolPtr(true),
+			expectedValue:    90,
+		},
+		{
+			name:        "unknown mountpoint",
+			rule:        &Rule{Metric: MetricMountpointUsagePercent, Operator: OperatorGT, Threshold: 85, Target: "/data"},
+			measurement: &models.Measurement{Mountpoints: `{"free_b./":10,"total_b./":100}`},
+		},
+		{
+			name:             "process running",
+			rule:             &Rule{Metric: MetricProcessMissing, Target: "nginx"},
+			measurement:      &models.Measurement{Processes: `[{"pid":1,"name":"systemd"},{"pid":30,"name":"nginx"}]`},
+			expectedBreached: boolPtr(false),
+		},
+		{
+			name:             "process missing",
+			rule:             &Rule{Metric: MetricProcessMissing, Target: "nginx"},
+			measurement:      &models.Measurement{Processes: `[{"pid":1,"name":"systemd"}]`},
+			expectedBreached: boolPtr(true),
+			expectedValue:    1,
+		},
+		{
+			name:        "process monitoring disabled",
+			rule:        &Rule{Metric: MetricProcessMissing, Target: "nginx"},
+			measurement: &models.Measurement{Processes: `[]`},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := evaluateMeasurement(tc.rule, tc.measurement)
+			require.NoError(t, err)
+
+			if tc.expectedBreached == nil {
+				assert.Nil(t, res)
+				return
+			}
+			require.NotNil(t, res)
+			assert.Equal(t, *tc.expectedBreached, res.breached)
+			assert.InDelta(t, tc.expectedValue, res.value, 0.001)
+		})
+	}
+}
+
+func TestAlertLifecycle(t *testing.T) {
+	ctx := context.Background()
+	msgSrv := &msgServiceMock{sent: make(chan message.Data, 10)}
+	service := newAlertsService(t, msgSrv)
+
+	rule, err := service.CreateRule(ctx, &Rule{
+		Name:       "high cpu",
+		Metric:     MetricCPUUsagePercent,
+		Operator:   OperatorGT,
+		Threshold:  90,
+		ForMinutes: 10,
+	}, "admin")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", rule.CreatedBy)
+
+	start := time.Date(2022, time.May, 1, 10, 0, 0, 0, time.UTC)
+	measure := func(minutes int, cpu float64) {
+		err := service.EvaluateMeasurement(ctx, &models.Measurement{
+			ClientID:        "client-1",
+			Timestamp:       start.Add(time.Duration(minutes) * time.Minute),
+			CPUUsagePercent: cpu,
+		})
+		require.NoError(t, err)
+	}
+
+	measure(0, 50)
+	assert.Empty(t, alertStates(t, service))
+
+	measure(1, 95)
+	assert.Equal(t, []State{StatePending}, alertStates(t, service))
+
+	// condition not met long enough, pending alert is dropped
+	measure(2, 60)
+	assert.Empty(t, alertStates(t, service))
+
+	measure(3, 95)
+	measure(13, 96)
+	assert.Equal(t, []State{StateFiring}, alertStates(t, service))
+	firing, err := service.provider.GetActiveAlert(ctx, rule.ID, "client-1")
+	require.NoError(t, err)
+	assert.Equal(t, 96.0, firing.Value)
+	require.NotNil(t, firing.FiredAt)
+	assert.Equal(t, start.Add(13*time.Minute), firing.FiredAt.UTC())
+
+	sent := <-msgSrv.sent
+	assert.Equal(t, "[RPort alert FIRING] high cpu", sent.Title)
+	assert.Equal(t, "admin@example.com", sent.SendTo)
+	assert.Contains(t, sent.Message, "cpu usage 96.00% is above 90.00%")
+
+	measure(14, 97)
+	assert.Equal(t, []State{StateFiring}, alertStates(t, service))
+
+	measure(15, 40)
+	resolved, err := service.provider.ListAlerts(ctx, &query.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, resolved, 1)
+	assert.Equal(t, StateResolved, resolved[0].State)
+	require.NotNil(t, resolved[0].ResolvedAt)
+
+	sent = <-msgSrv.sent
+	assert.Equal(t, "[RPort alert RESOLVED] high cpu", sent.Title)
+
+	// a new breach starts a new alert
+	measure(16, 99)
+	assert.Equal(t, []State{StatePending, StateResolved}, alertStates(t, service))
+}
+
+func TestRuleClientIDs(t *testing.T) {
+	ctx := context.Background()
+	service := newAlertsService(t, nil)
+
+	rule, err := service.CreateRule(ctx, &Rule{
+		Name:      "high memory",
+		Metric:    MetricMemoryUsagePercent,
+		Operator:  OperatorGT,
+		Threshold: 80,
+		ClientIDs: []string{"client-2"},
+	}, "admin")
+	require.NoError(t, err)
+
+	for _, clientID := range []string{"client-1", "client-2"} {
+		err = service.EvaluateMeasurement(ctx, &models.Measurement{ClientID: clientID, MemoryUsagePercent: 90})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []State{StateFiring}, alertStates(t, service))
+	firing, err := service.provider.GetActiveAlert(ctx, rule.ID, "client-2")
+	require.NoError(t, err)
+	assert.NotNil(t, firing)
+}
+
+func TestCheckClients(t *testing.T) {
+	ctx := context.Background()
+	service := newAlertsService(t, nil)
+	now := time.Date(2022, time.May, 1, 10, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	rule, err := service.CreateRule(ctx, &Rule{
+		Name:       "offline",
+		Metric:     MetricClientDisconnected,
+		ForMinutes: 5,
+	}, "admin")
+	require.NoError(t, err)
+
+	disconnectedAt := now.Add(-2 * time.Minute)
+	client := &clients.Client{ID: "client-1", Name: "test", DisconnectedAt: &disconnectedAt}
+
+	require.NoError(t, service.CheckClients(ctx, []*clients.Client{client}))
+	assert.Equal(t, []State{StatePending}, alertStates(t, service))
+	pending, err := service.provider.GetActiveAlert(ctx, rule.ID, client.ID)
+	require.NoError(t, err)
+	assert.Equal(t, disconnectedAt, pending.StartedAt.UTC())
+
+	now = now.Add(5 * time.Minute)
+	require.NoError(t, service.CheckClients(ctx, []*clients.Client{client}))
+	assert.Equal(t, []State{StateFiring}, alertStates(t, service))
+
+	client.DisconnectedAt = nil
+	require.NoError(t, service.CheckClients(ctx, []*clients.Client{client}))
+	assert.Equal(t, []State{StateResolved}, alertStates(t, service))
+
+	now = now.Add(time.Hour)
+	deleted, err := service.DeleteResolvedOlderThan(ctx, 30*time.Minute)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, deleted)
+	assert.Empty(t, alertStates(t, service))
+}
+
+func TestRuleCRUD(t *testing.T) {
+	ctx := context.Background()
+	service := newAlertsService(t, nil)
+
+	rule, err := service.CreateRule(ctx, &Rule{Name: "nginx", Metric: MetricProcessMissing, Target: "nginx"}, "admin")
+	require.NoError(t, err)
+
+	err = service.EvaluateMeasurement(ctx, &models.Measurement{ClientID: "client-1", Processes: `[{"name":"apache"}]`})
+	require.NoError(t, err)
+	assert.Equal(t, []State{StateFiring}, alertStates(t, service))
+
+	updated, err := service.UpdateRule(ctx, rule.ID, &Rule{Name: "nginx running", Metric: MetricProcessMissing, Target: "nginx"})
+	require.NoError(t, err)
+	assert.Equal(t, rule.ID, updated.ID)
+	assert.Equal(t, "admin", updated.CreatedBy)
+	assert.Equal(t, []*Rule{updated}, service.ListRules())
+
+	_, err = service.UpdateRule(ctx, "unknown", &Rule{Name: "test", Metric: MetricClientDisconnected})
+	assert.Equal(t, errors2.APIError{Message: `alert rule with id "unknown" not found`, HTTPStatus: http.StatusNotFound}, err)
+
+	require.NoError(t, service.DeleteRule(ctx, rule.ID))
+	assert.Empty(t, service.ListRules())
+	assert.Empty(t, alertStates(t, service))
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+type clientsProviderMock []*clients.Client
+
+func (m clientsProviderMock) GetAll() ([]*clients.Client, error) {
+	return m, nil
+}
+
+func TestCheckTaskRunCondition(t *testing.T) {
+	ctx := context.Background()
+	service := newAlertsService(t, nil)
+	_, err := service.CreateRule(ctx, &Rule{Name: "offline", Metric: MetricClientDisconnected}, "admin")
+	require.NoError(t, err)
+	disconnectedAt := time.Now().Add(-time.Minute)
+	task := NewCheckTask(testLog, service, clientsProviderMock{{ID: "client-1", DisconnectedAt: &disconnectedAt}})
+
+	isLeader := false
+	service.SetRunCondition(func() bool { return isLeader })
+	require.NoError(t, task.Run(ctx))
+	assert.Empty(t, alertStates(t, service), "task must be skipped on other nodes than the leader")
+
+	isLeader = true
+	require.NoError(t, task.Run(ctx))
+	assert.Equal(t, []State{StateFiring}, alertStates(t, service))
+}
+
+func TestReloadRules(t *testing.T) {
+	ctx := context.Background()
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	defer dbProvider.Close()
+	node1, err := NewService(ctx, dbProvider, nil, nil, testLog)
+	require.NoError(t, err)
+	node2, err := NewService(ctx, dbProvider, nil, nil, testLog)
+	require.NoError(t, err)
+
+	rule, err := node1.CreateRule(ctx, &Rule{Name: "nginx", Metric: MetricProcessMissing, Target: "nginx"}, "admin")
+	require.NoError(t, err)
+	assert.Empty(t, node2.ListRules())
+
+	require.NoError(t, node2.ReloadRules(ctx))
+	require.Len(t, node2.ListRules(), 1)
+	assert.Equal(t, rule.ID, node2.ListRules()[0].ID)
+}
@@ -0,0 +1,198 @@
+package alerts
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	alertsmigration "github.com/cloudradar-monitoring/rport/db/migration/alerts"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+type Provider interface {
+	ListRules(ctx context.Context) ([]*Rule, error)
+	SaveRule(ctx context.Context, rule *Rule) error
+	DeleteRule(ctx context.Context, id string) error
+	GetActiveAlert(ctx context.Context, ruleID, clientID string) (*Alert, error)
+	SaveAlert(ctx context.Context, alert *Alert) error
+	DeleteAlert(ctx context.Context, id string) error
+	ListAlerts(ctx context.Context, options *query.ListOptions) ([]*Alert, error)
+	CountAlerts(ctx context.Context, options *query.ListOptions) (int, error)
+	DeleteResolvedBefore(ctx context.Context, before time.Time) (int64, error)
+	Close() error
+}
+
+type SqliteProvider struct {
+	db        *sqlx.DB
+	converter *query.SQLConverter
+}
+
+func NewSqliteProvider(dbPath string, dbOptions database.Options) (*SqliteProvider, error) {
+	db, err := database.Open("alerts", dbPath, alertsmigration.AssetNames(), alertsmigration.Asset, dbOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create alerts DB instance: %v", err)
+	}
+
+	return &SqliteProvider{
+		db:        db,
+		converter: query.NewSQLConverter(db.DriverName()),
+	}, nil
+}
+
+func (p *SqliteProvider) ListRules(ctx context.Context) ([]*Rule, error) {
+	rules := []*Rule{}
+	err := p.db.SelectContext(ctx, &rules, "SELECT * FROM rules ORDER BY created_at")
+	return rules, err
+}
+
+func (p *SqliteProvider) SaveRule(ctx context.Context, rule *Rule) error {
+	_, err := p.db.NamedExecContext(ctx,
+		`INSERT INTO rules (
+			id,
+			name,
+			metric,
+			target,
+			operator,
+			threshold,
+			for_minutes,
+			client_ids,
+			created_at,
+			created_by
+		) VALUES (
+			:id,
+			:name,
+			:metric,
+			:target,
+			:operator,
+			:threshold,
+			:for_minutes,
+			:client_ids,
+			:created_at,
+			:created_by
+		) ON CONFLICT(id) DO UPDATE SET
+			name = :name,
+			metric = :metric,
+			target = :target,
+			operator = :operator,
+			threshold = :threshold,
+			for_minutes = :for_minutes,
+			client_ids = :client_ids`,
+		rule,
+	)
+	return err
+}
+
+func (p *SqliteProvider) DeleteRule(ctx context.Context, id string) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM alerts WHERE rule_id = ?"), id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM rules WHERE id = ?"), id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (p *SqliteProvider) GetActiveAlert(ctx context.Context, ruleID, clientID string) (*Alert, error) {
+	alert := &Alert{}
+	err := p.db.GetContext(ctx, alert,
+		p.db.Rebind("SELECT * FROM alerts WHERE rule_id = ? AND client_id = ? AND state != ? LIMIT 1"),
+		ruleID, clientID, StateResolved,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+func (p *SqliteProvider) SaveAlert(ctx context.Context, alert *Alert) error {
+	_, err := p.db.NamedExecContext(ctx,
+		`INSERT INTO alerts (
+			id,
+			rule_id,
+			rule_name,
+			client_id,
+			state,
+			value,
+			summary,
+			started_at,
+			fired_at,
+			resolved_at
+		) VALUES (
+			:id,
+			:rule_id,
+			:rule_name,
+			:client_id,
+			:state,
+			:value,
+			:summary,
+			:started_at,
+			:fired_at,
+			:resolved_at
+		) ON CONFLICT(id) DO UPDATE SET
+			state = :state,
+			value = :value,
+			summary = :summary,
+			fired_at = :fired_at,
+			resolved_at = :resolved_at`,
+		alert,
+	)
+	return err
+}
+
+func (p *SqliteProvider) DeleteAlert(ctx context.Context, id string) error {
+	_, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM alerts WHERE id = ?"), id)
+	return err
+}
+
+func (p *SqliteProvider) ListAlerts(ctx context.Context, options *query.ListOptions) ([]*Alert, error) {
+	values := []*Alert{}
+
+	q, params := p.converter.ConvertListOptionsToQuery(options, "SELECT * FROM alerts")
+
+	err := p.db.SelectContext(ctx, &values, p.db.Rebind(q), params...)
+	return values, err
+}
+
+func (p *SqliteProvider) CountAlerts(ctx context.Context, options *query.ListOptions) (int, error) {
+	var result int
+
+	countOptions := *options
+	countOptions.Pagination = nil
+	countOptions.Sorts = nil
+	q, params := p.converter.ConvertListOptionsToQuery(&countOptions, "SELECT COUNT(*) FROM alerts")
+
+	err := p.db.GetContext(ctx, &result, p.db.Rebind(q), params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (p *SqliteProvider) DeleteResolvedBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM alerts WHERE state = ? AND resolved_at < ?"), StateResolved, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (p *SqliteProvider) Close() error {
+	return p.db.Close()
+}
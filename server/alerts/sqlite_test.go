@@ -0,0 +1,91 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+func TestProvider(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		p, err := NewSqliteProvider(":memory:", database.Options{PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		defer p.Close()
+		start := time.Date(2022, 10, 16, 12, 0, 0, 0, time.UTC)
+
+		rule := &Rule{ID: "rule-1", Name: "cpu", Metric: MetricCPUUsagePercent, Operator: OperatorGT, Threshold: 90, ClientIDs: []string{"client-1"}, CreatedAt: start, CreatedBy: "admin"}
+		require.NoError(t, p.SaveRule(ctx, rule))
+		// saving it again updates the rule, but keeps the creation info
+		require.NoError(t, p.SaveRule(ctx, &Rule{ID: "rule-1", Name: "cpu high", Metric: MetricCPUUsagePercent, Operator: OperatorGT, Threshold: 95, CreatedAt: start.Add(time.Hour), CreatedBy: "other"}))
+
+		rules, err := p.ListRules(ctx)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		assert.Equal(t, "cpu high", rules[0].Name)
+		assert.Equal(t, 95.0, rules[0].Threshold)
+		assert.Empty(t, rules[0].ClientIDs)
+		assert.Equal(t, "admin", rules[0].CreatedBy)
+		assert.True(t, start.Equal(rules[0].CreatedAt))
+
+		pending := &Alert{ID: "alert-1", RuleID: "rule-1", RuleName: "cpu", ClientID: "client-1", State: StatePending, Value: 96, StartedAt: start}
+		require.NoError(t, p.SaveAlert(ctx, pending))
+		resolvedAt := start.Add(time.Minute)
+		resolved := &Alert{ID: "alert-2", RuleID: "rule-1", RuleName: "cpu", ClientID: "client-2", State: StateResolved, StartedAt: start, ResolvedAt: &resolvedAt}
+		require.NoError(t, p.SaveAlert(ctx, resolved))
+
+		active, err := p.GetActiveAlert(ctx, "rule-1", "client-1")
+		require.NoError(t, err)
+		require.NotNil(t, active)
+		assert.Equal(t, "alert-1", active.ID)
+		assert.Equal(t, 96.0, active.Value)
+		assert.Nil(t, active.FiredAt)
+
+		firedAt := start.Add(10 * time.Minute)
+		pending.State = StateFiring
+		pending.FiredAt = &firedAt
+		require.NoError(t, p.SaveAlert(ctx, pending))
+		active, err = p.GetActiveAlert(ctx, "rule-1", "client-1")
+		require.NoError(t, err)
+		require.NotNil(t, active)
+		assert.Equal(t, StateFiring, active.State)
+		require.NotNil(t, active.FiredAt)
+		assert.True(t, firedAt.Equal(*active.FiredAt))
+
+		active, err = p.GetActiveAlert(ctx, "rule-1", "client-2")
+		require.NoError(t, err)
+		assert.Nil(t, active)
+
+		options := &query.ListOptions{
+			Filters: []query.FilterOption{{Column: []string{"rule_id"}, Values: []string{"rule-1"}}},
+			Sorts:   []query.SortOption{{Column: "client_id", IsASC: false}},
+		}
+		alerts, err := p.ListAlerts(ctx, options)
+		require.NoError(t, err)
+		require.Len(t, alerts, 2)
+		assert.Equal(t, "alert-2", alerts[0].ID)
+		assert.Equal(t, "alert-1", alerts[1].ID)
+		count, err := p.CountAlerts(ctx, options)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		deleted, err := p.DeleteResolvedBefore(ctx, start.Add(time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+
+		require.NoError(t, p.DeleteRule(ctx, "rule-1"))
+		rules, err = p.ListRules(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, rules)
+		alerts, err = p.ListAlerts(ctx, &query.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, alerts)
+	})
+}
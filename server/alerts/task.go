@@ -0,0 +1,73 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+type ClientsProvider interface {
+	GetAll() ([]*clients.Client, error)
+}
+
+type CheckTask struct {
+	log             *logger.Logger
+	service         *Service
+	clientsProvider ClientsProvider
+}
+
+// NewCheckTask returns a task to evaluate alert rules that don't depend on measurements, like disconnected clients
+func NewCheckTask(log *logger.Logger, service *Service, clientsProvider ClientsProvider) *CheckTask {
+	return &CheckTask{
+		log:             log,
+		service:         service,
+		clientsProvider: clientsProvider,
+	}
+}
+
+func (t *CheckTask) Run(ctx context.Context) error {
+	if !t.service.shouldRun() {
+		return nil
+	}
+
+	allClients, err := t.clientsProvider.GetAll()
+	if err != nil {
+		return fmt.Errorf("failed to get clients: %v", err)
+	}
+
+	if err := t.service.CheckClients(ctx, allClients); err != nil {
+		return fmt.Errorf("failed to check alert rules: %v", err)
+	}
+	return nil
+}
+
+type CleanupTask struct {
+	log     *logger.Logger
+	service *Service
+	period  time.Duration
+}
+
+// NewCleanupTask returns a task to cleanup resolved alerts after configured period
+func NewCleanupTask(log *logger.Logger, service *Service, period time.Duration) *CleanupTask {
+	return &CleanupTask{
+		log:     log,
+		service: service,
+		period:  period,
+	}
+}
+
+func (t *CleanupTask) Run(ctx context.Context) error {
+	if !t.service.shouldRun() {
+		return nil
+	}
+
+	deletedRecords, err := t.service.DeleteResolvedOlderThan(ctx, t.period)
+	if err != nil {
+		return fmt.Errorf("failed to cleanup alerts: %v", err)
+	}
+	t.log.Debugf("alerts.CleanupTask: %d resolved alerts deleted", deletedRecords)
+	return nil
+}
@@ -0,0 +1,122 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/share/security"
+	"github.com/cloudradar-monitoring/rport/share/types"
+)
+
+const (
+	// TokenPrefix distinguishes API keys from the JWT tokens of login sessions
+	TokenPrefix = "rpk_"
+	tokenLength = 48
+)
+
+// APIKey is a long-lived bearer token of a user, its access is limited to the given permissions, client groups and
+// source IPs. Only the hash of the token is stored.
+type APIKey struct {
+	ID          string            `json:"id" db:"id"`
+	Username    string            `json:"-" db:"username"`
+	Name        string            `json:"name" db:"name"`
+	KeyHash     string            `json:"-" db:"key_hash"`
+	Permissions users.Permissions `json:"permissions" db:"permissions"`
+	// Admin allows the key to access the resources reserved to the Administrators group, the owner must be an admin
+	Admin          bool              `json:"admin" db:"admin"`
+	ClientGroupIDs types.StringSlice `json:"client_group_ids" db:"client_group_ids"`
+	AllowedIPs     types.StringSlice `json:"allowed_ips" db:"allowed_ips"`
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+	ExpiresAt      *time.Time        `json:"expires_at" db:"expires_at"`
+	LastUsedAt     *time.Time        `json:"last_used_at" db:"last_used_at"`
+}
+
+// NewToken returns a new random token for an API key. It's opaque, the key is found by the hash of the token.
+func NewToken() (string, error) {
+	token, err := security.NewRandomToken(tokenLength)
+	if err != nil {
+		return "", err
+	}
+	return TokenPrefix + token, nil
+}
+
+// IsToken returns true if a bearer token is an API key
+func IsToken(token string) bool {
+	return strings.HasPrefix(token, TokenPrefix)
+}
+
+// Hash returns the hash of the token of an API key as it's stored
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsExpired returns true if the key has an expiry date that has passed
+func (k *APIKey) IsExpired(now time.Time) bool {
+	return k.ExpiresAt != nil && !k.ExpiresAt.After(now)
+}
+
+// AllowsIP returns true if the key has no IP allow-list or the given IP matches one of its entries
+func (k *APIKey) AllowsIP(ipStr string) bool {
+	if len(k.AllowedIPs) == 0 {
+		return true
+	}
+
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return false
+	}
+	for _, allowed := range k.AllowedIPs {
+		ipNet, err := ParseIPNet(allowed)
+		if err != nil {
+			continue
+		}
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseIPNet parses an entry of the IP allow-list, either a single IP or a CIDR.
+func ParseIPNet(s string) (*net.IPNet, error) {
+	if strings.ContainsRune(s, '/') {
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+		return ipNet, nil
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address: %s", s)
+	}
+	bits := 8 * net.IPv6len
+	if ip.To4() != nil {
+		ip = ip.To4()
+		bits = 8 * net.IPv4len
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+type ctxKeyType string
+
+const ctxKey ctxKeyType = "api-key"
+
+// WithAPIKey returns a copy of a given context that contains the API key the request is authenticated with.
+func WithAPIKey(ctx context.Context, key *APIKey) context.Context {
+	return context.WithValue(ctx, ctxKey, key)
+}
+
+// FromContext returns the API key the request is authenticated with, nil if it's not authenticated by an API key.
+func FromContext(ctx context.Context) *APIKey {
+	key, _ := ctx.Value(ctxKey).(*APIKey)
+	return key
+}
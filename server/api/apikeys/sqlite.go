@@ -0,0 +1,146 @@
+package apikeys
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	apikeysmigration "github.com/cloudradar-monitoring/rport/db/migration/api_keys"
+)
+
+type Provider interface {
+	Create(ctx context.Context, key *APIKey) error
+	Get(ctx context.Context, id string) (*APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	ListByUser(ctx context.Context, username string) ([]*APIKey, error)
+	UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error
+	Delete(ctx context.Context, username, id string) (bool, error)
+	DeleteAllByUser(ctx context.Context, username string) error
+	Close() error
+}
+
+type SqliteProvider struct {
+	db *sqlx.DB
+}
+
+func NewSqliteProvider(dbPath string, dbOptions database.Options) (*SqliteProvider, error) {
+	db, err := database.Open("api_keys", dbPath, apikeysmigration.AssetNames(), apikeysmigration.Asset, dbOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api keys DB instance: %v", err)
+	}
+
+	return &SqliteProvider{
+		db: db,
+	}, nil
+}
+
+func (p *SqliteProvider) Create(ctx context.Context, key *APIKey) error {
+	_, err := p.db.NamedExecContext(ctx,
+		`INSERT INTO api_keys (
+			id,
+			username,
+			name,
+			key_hash,
+			permissions,
+			admin,
+			client_group_ids,
+			allowed_ips,
+			created_at,
+			expires_at
+		) VALUES (
+			:id,
+			:username,
+			:name,
+			:key_hash,
+			:permissions,
+			:admin,
+			:client_group_ids,
+			:allowed_ips,
+			:created_at,
+			:expires_at
+		)`,
+		key,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create api key: %w", err)
+	}
+
+	return nil
+}
+
+func (p *SqliteProvider) Get(ctx context.Context, id string) (*APIKey, error) {
+	key := &APIKey{}
+	err := p.db.GetContext(ctx, key, p.db.Rebind("SELECT * FROM api_keys WHERE id = ?"), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to get api key: %w", err)
+	}
+
+	return key, nil
+}
+
+func (p *SqliteProvider) GetByHash(ctx context.Context, keyHash string) (*APIKey, error) {
+	key := &APIKey{}
+	err := p.db.GetContext(ctx, key, p.db.Rebind("SELECT * FROM api_keys WHERE key_hash = ?"), keyHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to get api key: %w", err)
+	}
+
+	return key, nil
+}
+
+func (p *SqliteProvider) ListByUser(ctx context.Context, username string) ([]*APIKey, error) {
+	keys := []*APIKey{}
+	err := p.db.SelectContext(ctx, &keys, p.db.Rebind("SELECT * FROM api_keys WHERE username = ? ORDER BY created_at"), username)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (p *SqliteProvider) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	_, err := p.db.ExecContext(ctx, p.db.Rebind("UPDATE api_keys SET last_used_at = ? WHERE id = ?"), lastUsedAt, id)
+	if err != nil {
+		return fmt.Errorf("unable to update api key: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a key of a given user, returns false if the user has no such key.
+func (p *SqliteProvider) Delete(ctx context.Context, username, id string) (bool, error) {
+	res, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM api_keys WHERE username = ? AND id = ?"), username, id)
+	if err != nil {
+		return false, fmt.Errorf("unable to delete api key: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (p *SqliteProvider) DeleteAllByUser(ctx context.Context, username string) error {
+	_, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM api_keys WHERE username = ?"), username)
+	if err != nil {
+		return fmt.Errorf("unable to delete api keys: %w", err)
+	}
+
+	return nil
+}
+
+func (p *SqliteProvider) Close() error {
+	return p.db.Close()
+}
@@ -0,0 +1,121 @@
+package apikeys
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+func TestSqliteProvider(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		p, err := NewSqliteProvider(":memory:", database.Options{PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		defer p.Close()
+
+		now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+		expires := now.Add(time.Hour)
+		k1 := &APIKey{
+			ID:             "key-1",
+			Username:       "user1",
+			Name:           "ci",
+			KeyHash:        Hash("token-1"),
+			Permissions:    users.NewPermissions(users.PermissionCommands),
+			Admin:          true,
+			ClientGroupIDs: []string{"group-1"},
+			AllowedIPs:     []string{"192.0.2.0/24"},
+			CreatedAt:      now,
+			ExpiresAt:      &expires,
+		}
+		k2 := &APIKey{
+			ID:        "key-2",
+			Username:  "user1",
+			Name:      "monitoring",
+			KeyHash:   Hash("token-2"),
+			CreatedAt: now.Add(time.Minute),
+		}
+		k3 := &APIKey{
+			ID:        "key-3",
+			Username:  "user2",
+			Name:      "backup",
+			KeyHash:   Hash("token-3"),
+			CreatedAt: now,
+		}
+		for _, k := range []*APIKey{k1, k2, k3} {
+			require.NoError(t, p.Create(ctx, k))
+		}
+
+		got, err := p.Get(ctx, "key-1")
+		require.NoError(t, err)
+		assert.Equal(t, k1.Name, got.Name)
+		assert.Equal(t, k1.KeyHash, got.KeyHash)
+		assert.True(t, got.Permissions.Has(users.PermissionCommands))
+		assert.False(t, got.Permissions.Has(users.PermissionVault))
+		assert.EqualValues(t, []string{"group-1"}, got.ClientGroupIDs)
+		assert.EqualValues(t, []string{"192.0.2.0/24"}, got.AllowedIPs)
+		assert.True(t, expires.Equal(*got.ExpiresAt))
+		assert.Nil(t, got.LastUsedAt)
+
+		assert.True(t, got.Admin)
+
+		got, err = p.GetByHash(ctx, Hash("token-2"))
+		require.NoError(t, err)
+		assert.Equal(t, "key-2", got.ID)
+		assert.False(t, got.Admin)
+
+		got, err = p.GetByHash(ctx, Hash("unknown"))
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		got, err = p.Get(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		lastUsed := now.Add(2 * time.Minute)
+		require.NoError(t, p.UpdateLastUsed(ctx, "key-2", lastUsed))
+
+		list, err := p.ListByUser(ctx, "user1")
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, "key-1", list[0].ID)
+		assert.Equal(t, "key-2", list[1].ID)
+		assert.True(t, lastUsed.Equal(*list[1].LastUsedAt))
+
+		deleted, err := p.Delete(ctx, "user2", "key-1")
+		require.NoError(t, err)
+		assert.False(t, deleted, "keys of other users must not be deleted")
+
+		deleted, err = p.Delete(ctx, "user1", "key-1")
+		require.NoError(t, err)
+		assert.True(t, deleted)
+
+		require.NoError(t, p.DeleteAllByUser(ctx, "user1"))
+		list, err = p.ListByUser(ctx, "user1")
+		require.NoError(t, err)
+		assert.Len(t, list, 0)
+
+		list, err = p.ListByUser(ctx, "user2")
+		require.NoError(t, err)
+		assert.Len(t, list, 1)
+	})
+}
+
+func TestAllowsIP(t *testing.T) {
+	k := &APIKey{}
+	assert.True(t, k.AllowsIP("203.0.113.5"), "no allow-list")
+
+	k.AllowedIPs = []string{"192.0.2.0/24", "203.0.113.5", "2001:db8::/32"}
+	assert.True(t, k.AllowsIP("192.0.2.10"))
+	assert.True(t, k.AllowsIP("203.0.113.5"))
+	assert.True(t, k.AllowsIP("2001:db8::1"))
+	assert.False(t, k.AllowsIP("203.0.113.6"))
+	assert.False(t, k.AllowsIP("198.51.100.1"))
+	assert.False(t, k.AllowsIP("invalid"))
+}
@@ -36,11 +36,11 @@ func (p *SqliteProvider) Close() error {
 }
 
 func (p *SqliteProvider) GetByID(ctx context.Context, id string, ro *query.RetrieveOptions) (val *Command, found bool, err error) {
-	q := "SELECT * FROM `commands` WHERE `id` = ? LIMIT 1"
+	q := "SELECT * FROM commands WHERE id = ? LIMIT 1"
 	q = p.converter.ConvertRetrieveOptionsToQuery(ro, q)
 
 	val = new(Command)
-	err = p.db.GetContext(ctx, val, q, id)
+	err = p.db.GetContext(ctx, val, p.db.Rebind(q), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return val, false, nil
@@ -55,11 +55,11 @@ func (p *SqliteProvider) GetByID(ctx context.Context, id string, ro *query.Retri
 func (p *SqliteProvider) List(ctx context.Context, lo *query.ListOptions) ([]Command, error) {
 	values := []Command{}
 
-	q := "SELECT * FROM `commands`"
+	q := "SELECT * FROM commands"
 
 	q, params := p.converter.ConvertListOptionsToQuery(lo, q)
 
-	err := p.db.SelectContext(ctx, &values, q, params...)
+	err := p.db.SelectContext(ctx, &values, p.db.Rebind(q), params...)
 	if err != nil {
 		return values, err
 	}
@@ -77,8 +77,8 @@ func (p *SqliteProvider) Save(ctx context.Context, s *Command) (string, error) {
 
 		_, err = p.db.NamedExecContext(
 			ctx,
-			"INSERT INTO `commands` "+
-				"(`id`, `name`, `created_at`, `created_by`, `updated_at`, `updated_by`, `cmd`, `tags`, `timeout_sec`)"+
+			"INSERT INTO commands "+
+				"(id, name, created_at, created_by, updated_at, updated_by, cmd, tags, timeout_sec)"+
 				" VALUES "+
 				"(:id, :name, :created_at, :created_by, :updated_at, :updated_by, :cmd, :tags, :timeout_sec)",
 			s,
@@ -87,13 +87,13 @@ func (p *SqliteProvider) Save(ctx context.Context, s *Command) (string, error) {
 		return commandID, err
 	}
 
-	q := "UPDATE `commands` SET " +
-		"`name` = :name, " +
-		"`updated_at` = :updated_at, " +
-		"`updated_by` =  :updated_by, " +
-		"`cmd` = :cmd, " +
-		"`tags` = :tags, " +
-		"`timeout_sec` = :timeout_sec " +
+	q := "UPDATE commands SET " +
+		"name = :name, " +
+		"updated_at = :updated_at, " +
+		"updated_by =  :updated_by, " +
+		"cmd = :cmd, " +
+		"tags = :tags, " +
+		"timeout_sec = :timeout_sec " +
 		"WHERE id = :id"
 	_, err := p.db.NamedExecContext(ctx, q, s)
 
@@ -101,7 +101,7 @@ func (p *SqliteProvider) Save(ctx context.Context, s *Command) (string, error) {
 }
 
 func (p *SqliteProvider) Delete(ctx context.Context, id string) error {
-	res, err := p.db.ExecContext(ctx, "DELETE FROM `commands` WHERE `id` = ?", id)
+	res, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM commands WHERE id = ?"), id)
 
 	if err != nil {
 		return err
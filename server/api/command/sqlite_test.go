@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/migration/library"
 	"github.com/cloudradar-monitoring/rport/db/sqlite"
 	"github.com/cloudradar-monitoring/rport/share/ptr"
@@ -328,3 +329,47 @@ func addDemoData(db *sqlx.DB) error {
 
 	return nil
 }
+
+func TestProviderDatabases(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		db, err := database.Open("library", ":memory:", library.AssetNames(), library.Asset, database.Options{SQLite: DataSourceOptions, PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		dbProv := NewSqliteProvider(db)
+		defer dbProv.Close()
+		ctx := context.Background()
+
+		for i := range demoData {
+			item := demoData[i]
+			item.ID = ""
+			_, err := dbProv.Save(ctx, &item)
+			require.NoError(t, err)
+		}
+		commands, err := dbProv.List(ctx, &query.ListOptions{Sorts: []query.SortOption{{Column: "created_at", IsASC: true}}})
+		require.NoError(t, err)
+		require.Len(t, commands, 2)
+		id := commands[0].ID
+		assert.Equal(t, "some name", commands[0].Name)
+
+		item := demoData[0]
+		item.ID = id
+		item.Cmd = "ls"
+		item.Tags = ptr.StringSlice()
+		_, err = dbProv.Save(ctx, &item)
+		require.NoError(t, err)
+
+		val, found, err := dbProv.GetByID(ctx, id, &query.RetrieveOptions{})
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, "ls", val.Cmd)
+		assert.Equal(t, ptr.StringSlice(), val.Tags)
+		assert.True(t, demoData[0].CreatedAt.Equal(*val.CreatedAt))
+		assert.True(t, demoData[0].UpdatedAt.Equal(*val.UpdatedAt))
+
+		commands, err = dbProv.List(ctx, &query.ListOptions{Filters: []query.FilterOption{{Column: []string{"created_by"}, Values: []string{"user1"}}}})
+		require.NoError(t, err)
+		assert.Len(t, commands, 2)
+
+		require.NoError(t, dbProv.Delete(ctx, id))
+		assert.EqualError(t, dbProv.Delete(ctx, id), "cannot find entry by id "+id)
+	})
+}
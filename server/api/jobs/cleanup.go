@@ -2,6 +2,7 @@ package jobs
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
 )
@@ -27,18 +28,19 @@ func (t *CleanupTask) Run(ctx context.Context) error {
 }
 
 func (p *SqliteProvider) CleanupJobsMultiJobs(ctx context.Context, maxJobs int) error {
+	offset := p.converter.Offset()
 	// Delete all multi jobs that have jobs after max jobs
-	_, err := p.db.ExecContext(ctx, "DELETE FROM multi_jobs WHERE jid IN (SELECT multi_job_id FROM jobs ORDER BY started_at DESC LIMIT -1 OFFSET ?)", maxJobs)
+	_, err := p.db.ExecContext(ctx, p.db.Rebind(fmt.Sprintf("DELETE FROM multi_jobs WHERE jid IN (SELECT multi_job_id FROM jobs ORDER BY started_at DESC %s)", offset)), maxJobs)
 	if err != nil {
 		return errors.Wrap(err, "deleting multi jobs")
 	}
 	// Delete all jobs associated with multi jobs
-	_, err = p.db.ExecContext(ctx, "DELETE FROM jobs WHERE multi_job_id IN (SELECT multi_job_id FROM jobs ORDER BY started_at DESC LIMIT -1 OFFSET ?) AND multi_job_id IS NOT NULL", maxJobs)
+	_, err = p.db.ExecContext(ctx, p.db.Rebind(fmt.Sprintf("DELETE FROM jobs WHERE multi_job_id IN (SELECT multi_job_id FROM jobs ORDER BY started_at DESC %s) AND multi_job_id IS NOT NULL", offset)), maxJobs)
 	if err != nil {
 		return errors.Wrap(err, "deleting multi jobs' jobs")
 	}
 	// Delete any jobs left not from multi jobs
-	_, err = p.db.ExecContext(ctx, "DELETE FROM jobs WHERE jid IN (SELECT jid FROM jobs ORDER BY started_at DESC LIMIT -1 OFFSET ?) AND multi_job_id IS NULL", maxJobs)
+	_, err = p.db.ExecContext(ctx, p.db.Rebind(fmt.Sprintf("DELETE FROM jobs WHERE jid IN (SELECT jid FROM jobs ORDER BY started_at DESC %s) AND multi_job_id IS NULL", offset)), maxJobs)
 	if err != nil {
 		return errors.Wrap(err, "deleting jobs")
 	}
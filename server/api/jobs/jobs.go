@@ -9,7 +9,6 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	"github.com/mattn/go-sqlite3"
 	"github.com/pkg/errors"
 
 	"github.com/cloudradar-monitoring/rport/db/sqlite"
@@ -21,6 +20,10 @@ import (
 const (
 	DefaultLimit = 100
 	MaxLimit     = 1000
+
+	// DefaultUpdatesInstallationTimeoutSec is used for updates installation jobs without a timeout,
+	// the regular command timeout is usually too short for it
+	DefaultUpdatesInstallationTimeoutSec = 3600
 )
 
 var JobSupportedFilters = map[string]bool{
@@ -66,6 +69,8 @@ var jobFields = map[string]bool{
 	"error":        true,
 	"is_sudo":      true,
 	"is_script":    true,
+
+	"updates_installation": true,
 }
 var JobSupportedFields = map[string]map[string]bool{
 	"jobs":     jobFields,
@@ -110,7 +115,7 @@ func (p *SqliteProvider) GetDB() (db *sqlx.DB) {
 
 func (p *SqliteProvider) GetByJID(clientID, jid string) (*models.Job, error) {
 	res := &jobSqlite{}
-	err := p.db.Get(res, "SELECT jobs.*, schedule_id FROM jobs LEFT JOIN multi_jobs ON jobs.multi_job_id = multi_jobs.jid WHERE jobs.jid=?", jid)
+	err := p.db.Get(res, p.db.Rebind("SELECT jobs.*, schedule_id FROM jobs LEFT JOIN multi_jobs ON jobs.multi_job_id = multi_jobs.jid WHERE jobs.jid=?"), jid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -138,7 +143,7 @@ func (p *SqliteProvider) List(ctx context.Context, options *query.ListOptions) (
 	q, params := p.converter.AppendOptionsToQuery(options, q, nil)
 
 	var res []*jobSqlite
-	err := p.db.SelectContext(ctx, &res, q, params...)
+	err := p.db.SelectContext(ctx, &res, p.db.Rebind(q), params...)
 	if err != nil {
 		return nil, err
 	}
@@ -148,23 +153,44 @@ func (p *SqliteProvider) List(ctx context.Context, options *query.ListOptions) (
 func (p *SqliteProvider) Count(ctx context.Context, options *query.ListOptions) (int, error) {
 	countOptions := *options
 	countOptions.Pagination = nil
+	countOptions.Sorts = nil
 
-	q := "SELECT count(*) FROM (SELECT jobs.*, schedule_id FROM jobs LEFT JOIN multi_jobs ON jobs.multi_job_id = multi_jobs.jid)"
+	q := "SELECT count(*) FROM (SELECT jobs.*, schedule_id FROM jobs LEFT JOIN multi_jobs ON jobs.multi_job_id = multi_jobs.jid) AS jobs"
 	q, params := p.converter.AppendOptionsToQuery(&countOptions, q, nil)
 
 	var result int
-	err := p.db.GetContext(ctx, &result, q, params...)
+	err := p.db.GetContext(ctx, &result, p.db.Rebind(q), params...)
 	if err != nil {
 		return 0, err
 	}
 	return result, nil
 }
 
+// CountByStatus returns the number of jobs by job status.
+func (p *SqliteProvider) CountByStatus(ctx context.Context) (map[string]int, error) {
+	var rows []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+	err := p.db.SelectContext(ctx, &rows, "SELECT status, count(*) AS count FROM jobs GROUP BY status")
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int, len(rows))
+	for _, row := range rows {
+		result[row.Status] = row.Count
+	}
+	return result, nil
+}
+
 // SaveJob creates a new or updates an existing job.
 func (p *SqliteProvider) SaveJob(job *models.Job) error {
 	_, err := sqlite.WithRetryWhenBusy(func() (result sql.Result, err error) {
-		result, err = p.db.NamedExec(`INSERT OR REPLACE INTO jobs (jid, status, started_at, finished_at, created_by, client_id, multi_job_id, details)
-		VALUES (:jid, :status, :started_at, :finished_at, :created_by, :client_id, :multi_job_id, :details)`,
+		result, err = p.db.NamedExec(`INSERT INTO jobs (jid, status, started_at, finished_at, created_by, client_id, multi_job_id, details)
+		VALUES (:jid, :status, :started_at, :finished_at, :created_by, :client_id, :multi_job_id, :details)
+		ON CONFLICT(jid) DO UPDATE SET status = excluded.status, started_at = excluded.started_at, finished_at = excluded.finished_at,
+		created_by = excluded.created_by, client_id = excluded.client_id, multi_job_id = excluded.multi_job_id, details = excluded.details`,
 			convertToSqlite(job))
 		return result, err
 	}, "savejob", p.log)
@@ -177,23 +203,27 @@ func (p *SqliteProvider) SaveJob(job *models.Job) error {
 
 // CreateJob creates a new job. If already exists with the same ID - does nothing and returns nil.
 func (p *SqliteProvider) CreateJob(job *models.Job) error {
-	_, err := sqlite.WithRetryWhenBusy(func() (result sql.Result, err error) {
+	res, err := sqlite.WithRetryWhenBusy(func() (result sql.Result, err error) {
 		result, err = p.db.NamedExec(`INSERT INTO jobs (jid, status, started_at, finished_at, created_by, client_id, multi_job_id, details)
-		VALUES (:jid, :status, :started_at, :finished_at, :created_by, :client_id, :multi_job_id, :details)`,
+		VALUES (:jid, :status, :started_at, :finished_at, :created_by, :client_id, :multi_job_id, :details)
+		ON CONFLICT(jid) DO NOTHING`,
 			convertToSqlite(job))
 		return result, err
 	}, "createjob", p.log)
 	if err != nil {
-		// check if it's "already exist" err
-		typeErr, ok := err.(sqlite3.Error)
-		if ok && typeErr.Code == sqlite3.ErrConstraint {
-			p.log.Debugf("Job already exist with ID: %s", job.JID)
-			return nil
-		}
+		return err
+	}
+
+	created, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if created == 0 {
+		p.log.Debugf("Job already exist with ID: %s", job.JID)
 	} else {
 		p.log.Debugf("Job saved successfully: %v", *job)
 	}
-	return err
+	return nil
 }
 
 func (p *SqliteProvider) Close() error {
@@ -223,6 +253,8 @@ type JobDetails struct {
 	Error       string            `json:"error"`
 	Result      *models.JobResult `json:"result"`
 	ClientName  string            `json:"client_name"`
+
+	UpdatesInstallation *models.UpdatesInstallation `json:"updates_installation,omitempty"`
 }
 
 func (d *JobDetails) Scan(value interface{}) error {
@@ -279,6 +311,7 @@ func (j *jobSqlite) convert() *models.Job {
 		res.Cwd = j.Details.Cwd
 		res.IsSudo = j.Details.IsSudo
 		res.IsScript = j.Details.IsScript
+		res.UpdatesInstallation = j.Details.UpdatesInstallation
 	}
 	if j.FinishedAt.Valid {
 		res.FinishedAt = &j.FinishedAt.Time
@@ -307,6 +340,8 @@ func convertToSqlite(job *models.Job) *jobSqlite {
 			Cwd:         job.Cwd,
 			IsSudo:      job.IsSudo,
 			IsScript:    job.IsScript,
+
+			UpdatesInstallation: job.UpdatesInstallation,
 		},
 	}
 	if job.MultiJobID != nil {
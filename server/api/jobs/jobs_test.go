@@ -10,12 +10,14 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/migration/jobs"
 	"github.com/cloudradar-monitoring/rport/db/sqlite"
 	"github.com/cloudradar-monitoring/rport/server/test/jb"
 	"github.com/cloudradar-monitoring/rport/share/logger"
 	"github.com/cloudradar-monitoring/rport/share/models"
 	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/test"
 )
 
 var DataSourceOptions = sqlite.DataSourceOptions{WALEnabled: false}
@@ -89,6 +91,19 @@ func TestJobsSqliteProvider(t *testing.T) {
 	gotJSc1, err = p.List(ctx, &query.ListOptions{Filters: []query.FilterOption{{Column: []string{"client_id"}, Values: []string{job1.ClientID}}}})
 	require.NoError(t, err)
 	assert.ElementsMatch(t, []*models.Job{job1, job2}, gotJSc1)
+
+	// verify count by status
+	gotCounts, err := p.CountByStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, countsOf(job1, job2, job3), gotCounts)
+}
+
+func countsOf(jobs ...*models.Job) map[string]int {
+	result := make(map[string]int)
+	for _, job := range jobs {
+		result[job.Status]++
+	}
+	return result
 }
 
 func TestCreateJob(t *testing.T) {
@@ -111,3 +126,87 @@ func TestCreateJob(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, job, gotJob)
 }
+
+func TestJobsProviderDatabases(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		jobsDB, err := database.Open("jobs", ":memory:", jobs.AssetNames(), jobs.Asset, database.Options{SQLite: DataSourceOptions, PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		p := NewSqliteProvider(jobsDB, testLog)
+		defer p.Close()
+
+		multiJob := jb.NewMulti(t).JID("multi-1").StartedAt(time.Date(2020, 10, 10, 10, 0, 0, 0, time.UTC)).WithSudo().Build()
+		require.NoError(t, p.SaveMultiJob(multiJob))
+		multiJob.Concurrent = true
+		require.NoError(t, p.SaveMultiJob(multiJob))
+
+		job1 := jb.New(t).JID("job-1").MultiJobID(multiJob.JID).Status(models.JobStatusRunning).Result(nil).Build()
+		job2 := jb.New(t).JID("job-2").ClientID(job1.ClientID).Build()
+		require.NoError(t, p.SaveJob(job1))
+		require.NoError(t, p.CreateJob(job2))
+
+		// the existing job is kept
+		changed := *job2
+		changed.Status = models.JobStatusFailed
+		require.NoError(t, p.CreateJob(&changed))
+
+		job1.Status = models.JobStatusSuccessful
+		job1.Result = &models.JobResult{StdOut: "some std out"}
+		finishedAt := time.Date(2020, 10, 10, 10, 11, 0, 0, time.UTC)
+		job1.FinishedAt = &finishedAt
+		require.NoError(t, p.SaveJob(job1))
+
+		gotJob1, err := p.GetByJID(job1.ClientID, job1.JID)
+		require.NoError(t, err)
+		assertJob(t, job1, gotJob1)
+		gotJob2, err := p.GetByJID(job2.ClientID, job2.JID)
+		require.NoError(t, err)
+		assertJob(t, job2, gotJob2)
+
+		options := &query.ListOptions{Filters: []query.FilterOption{{Column: []string{"multi_job_id"}, Values: []string{multiJob.JID}}}}
+		list, err := p.List(ctx, options)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, job1.JID, list[0].JID)
+		count, err := p.Count(ctx, options)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		counts, err := p.CountByStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{models.JobStatusSuccessful: 2}, counts)
+
+		gotMultiJob, err := p.GetMultiJob(ctx, multiJob.JID)
+		require.NoError(t, err)
+		require.NotNil(t, gotMultiJob)
+		assert.True(t, multiJob.StartedAt.Equal(gotMultiJob.StartedAt))
+		assert.True(t, gotMultiJob.Concurrent)
+		assert.True(t, gotMultiJob.IsSudo)
+		assert.Equal(t, multiJob.ClientIDs, gotMultiJob.ClientIDs)
+		require.Len(t, gotMultiJob.Jobs, 1)
+		assert.Equal(t, job1.JID, gotMultiJob.Jobs[0].JID)
+
+		summaries, err := p.GetMultiJobSummaries(ctx, &query.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, summaries, 1)
+		assert.Equal(t, multiJob.JID, summaries[0].JID)
+		count, err = p.CountMultiJobs(ctx, &query.ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+}
+
+// assertJob compares the jobs, the times are compared by the instant only as PostgreSQL doesn't keep the location
+func assertJob(t *testing.T, expected, actual *models.Job) {
+	t.Helper()
+	require.NotNil(t, actual)
+
+	assert.True(t, expected.StartedAt.Equal(actual.StartedAt))
+	actual.StartedAt = expected.StartedAt
+	if expected.FinishedAt != nil {
+		require.NotNil(t, actual.FinishedAt)
+		assert.True(t, expected.FinishedAt.Equal(*actual.FinishedAt))
+		actual.FinishedAt = expected.FinishedAt
+	}
+	assert.Equal(t, expected, actual)
+}
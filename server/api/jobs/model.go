@@ -22,6 +22,8 @@ type MultiJobRequest struct {
 	IsScript       bool              `json:"-"`
 	OrderedClients []*clients.Client `json:"-"`
 	ScheduleID     *string           `json:"-"`
+	// UpdatesInstallation is set to install pending updates instead of running the command
+	UpdatesInstallation *models.UpdatesInstallation `json:"-"`
 }
 
 func (req *MultiJobRequest) GetClientIDs() (ids []string) {
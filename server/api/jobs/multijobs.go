@@ -32,7 +32,7 @@ var MultiJobSupportedSorts = map[string]bool{
 // GetMultiJob returns a multi-client job with fetched all clients' jobs.
 func (p *SqliteProvider) GetMultiJob(ctx context.Context, jid string) (*models.MultiJob, error) {
 	res := &multiJobSqlite{}
-	err := p.db.Get(res, "SELECT * FROM multi_jobs WHERE jid=?", jid)
+	err := p.db.Get(res, p.db.Rebind("SELECT * FROM multi_jobs WHERE jid=?"), jid)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -67,7 +67,7 @@ func (p *SqliteProvider) GetMultiJobSummaries(ctx context.Context, options *quer
 	if len(options.Sorts) == 0 {
 		options.Sorts = []query.SortOption{
 			{
-				Column: "DATETIME(started_at)",
+				Column: p.converter.DateTime("started_at"),
 				IsASC:  false,
 			},
 			{
@@ -79,7 +79,7 @@ func (p *SqliteProvider) GetMultiJobSummaries(ctx context.Context, options *quer
 	q := "SELECT jid, started_at, created_by, schedule_id FROM multi_jobs"
 	q, params := p.converter.ConvertListOptionsToQuery(options, q)
 
-	err := p.db.SelectContext(ctx, &res, q, params...)
+	err := p.db.SelectContext(ctx, &res, p.db.Rebind(q), params...)
 	if err != nil {
 		return nil, err
 	}
@@ -92,10 +92,11 @@ func (p *SqliteProvider) CountMultiJobs(ctx context.Context, options *query.List
 
 	countOptions := *options
 	countOptions.Pagination = nil
+	countOptions.Sorts = nil
 	q := "SELECT count(*) FROM multi_jobs"
 	q, params := p.converter.ConvertListOptionsToQuery(&countOptions, q)
 
-	err := p.db.GetContext(ctx, &result, q, params...)
+	err := p.db.GetContext(ctx, &result, p.db.Rebind(q), params...)
 	if err != nil {
 		return 0, err
 	}
@@ -105,11 +106,12 @@ func (p *SqliteProvider) CountMultiJobs(ctx context.Context, options *query.List
 // SaveMultiJob creates a new or updates an existing multi-client job (without child jobs).
 func (p *SqliteProvider) SaveMultiJob(job *models.MultiJob) error {
 	_, err := p.db.NamedExec(`
-INSERT OR REPLACE INTO multi_jobs (
+INSERT INTO multi_jobs (
 	jid, started_at, created_by, schedule_id, details
 ) VALUES (
 	:jid, :started_at, :created_by, :schedule_id, :details
-)`,
+) ON CONFLICT(jid) DO UPDATE SET
+	started_at = excluded.started_at, created_by = excluded.created_by, schedule_id = excluded.schedule_id, details = excluded.details`,
 		convertMultiJobToSqlite(job))
 	if err == nil {
 		p.log.Debugf("Multi-client Job saved successfully: %v", *job)
@@ -140,6 +142,8 @@ type multiJobDetailSqlite struct {
 	TimeoutSec  int                   `json:"timeout_sec"`
 	Concurrent  bool                  `json:"concurrent"`
 	AbortOnErr  bool                  `json:"abort_on_err"`
+
+	UpdatesInstallation *models.UpdatesInstallation `json:"updates_installation,omitempty"`
 }
 
 func (d *multiJobDetailSqlite) Scan(value interface{}) error {
@@ -200,6 +204,8 @@ func (j *multiJobSqlite) convert() *models.MultiJob {
 		TimeoutSec:      d.TimeoutSec,
 		Concurrent:      d.Concurrent,
 		AbortOnErr:      d.AbortOnErr,
+
+		UpdatesInstallation: d.UpdatesInstallation,
 	}
 }
 
@@ -222,6 +228,8 @@ func convertMultiJobToSqlite(job *models.MultiJob) *multiJobSqlite {
 			TimeoutSec:  job.TimeoutSec,
 			Concurrent:  job.Concurrent,
 			AbortOnErr:  job.AbortOnErr,
+
+			UpdatesInstallation: job.UpdatesInstallation,
 		},
 	}
 }
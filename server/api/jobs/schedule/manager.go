@@ -5,6 +5,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -68,6 +69,14 @@ type Manager struct {
 	cron      Cron
 
 	runRemoteCmdTimeoutSec int
+
+	cronsMu sync.Mutex
+	// crons are the schedule expressions of the schedules added to cron by schedule id
+	crons map[string]string
+	// shouldRun is checked before running a schedule, nil means always
+	shouldRun func() bool
+	// onRun is called after a schedule was run, nil means no listener
+	onRun func(s *Schedule, multiJob *models.MultiJob, err error)
 }
 
 func New(ctx context.Context, logger *logger.Logger, db *sqlx.DB, jobRunner JobRunner, runRemoteCmdTimeoutSec int) (*Manager, error) {
@@ -96,10 +105,58 @@ func NewManager(jobRunner JobRunner, db *sqlx.DB, logger *logger.Logger, runRemo
 		cron:      newCron(),
 
 		runRemoteCmdTimeoutSec: runRemoteCmdTimeoutSec,
+		crons:                  make(map[string]string),
 	}
 	return m
 }
 
+// SetRunCondition sets a condition checked each time a schedule is due, the schedule is skipped if it returns false.
+// It's used to run schedules only on one node of a cluster.
+func (m *Manager) SetRunCondition(shouldRun func() bool) {
+	m.shouldRun = shouldRun
+}
+
+// SetRunListener sets a func called each time a schedule was run with the started job or the error starting it.
+func (m *Manager) SetRunListener(onRun func(s *Schedule, multiJob *models.MultiJob, err error)) {
+	m.onRun = onRun
+}
+
+// Reload syncs the cron with the schedules in the database, which are changed by other nodes of a cluster.
+func (m *Manager) Reload(ctx context.Context) error {
+	existing, err := m.provider.List(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	m.cronsMu.Lock()
+	current := make(map[string]string, len(m.crons))
+	for id, spec := range m.crons {
+		current[id] = spec
+	}
+	m.cronsMu.Unlock()
+
+	for _, s := range existing {
+		spec, ok := current[s.ID]
+		delete(current, s.ID)
+		if ok && spec == s.Schedule {
+			continue
+		}
+		if ok {
+			m.removeCron(s.ID)
+		}
+		err := m.addCron(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	for id := range current {
+		m.removeCron(id)
+	}
+
+	return nil
+}
+
 func (m *Manager) List(ctx context.Context, r *http.Request) (*api.SuccessPayload, error) {
 	listOptions := query.GetListOptions(r)
 
@@ -156,7 +213,7 @@ func (m *Manager) Create(ctx context.Context, s *Schedule, user string) (*Schedu
 	s.CreatedAt = time.Now()
 	s.CreatedBy = user
 
-	err = m.validate(s)
+	err = m.Validate(s)
 	if err != nil {
 		return nil, err
 	}
@@ -177,7 +234,7 @@ func (m *Manager) Create(ctx context.Context, s *Schedule, user string) (*Schedu
 func (m *Manager) Update(ctx context.Context, id string, s *Schedule) (*Schedule, error) {
 	s.ID = id
 
-	err := m.validate(s)
+	err := m.Validate(s)
 	if err != nil {
 		return nil, err
 	}
@@ -187,7 +244,7 @@ func (m *Manager) Update(ctx context.Context, id string, s *Schedule) (*Schedule
 		return nil, err
 	}
 
-	m.cron.Remove(s.ID)
+	m.removeCron(s.ID)
 	err = m.addCron(s)
 	if err != nil {
 		return nil, err
@@ -207,15 +264,16 @@ func (m *Manager) Delete(ctx context.Context, id string) error {
 		return err
 	}
 
-	m.cron.Remove(id)
+	m.removeCron(id)
 	return nil
 }
 
-func (m *Manager) validate(s *Schedule) error {
-	if s.Type != TypeCommand && s.Type != TypeScript {
+// Validate returns an error if a schedule can't be saved
+func (m *Manager) Validate(s *Schedule) error {
+	if s.Type != TypeCommand && s.Type != TypeScript && s.Type != TypeUpdates {
 		return &errors.APIError{
 			Message:    "Invalid type.",
-			Err:        fmt.Errorf("type must be 'command' or 'script'"),
+			Err:        fmt.Errorf("type must be 'command', 'script' or 'updates'"),
 			HTTPStatus: http.StatusBadRequest,
 		}
 	}
@@ -263,16 +321,53 @@ func (m *Manager) validate(s *Schedule) error {
 				HTTPStatus: http.StatusBadRequest,
 			}
 		}
+	case TypeUpdates:
+		if s.Details.UpdatesInstallation == nil {
+			return &errors.APIError{
+				Message:    "Empty updates installation.",
+				Err:        fmt.Errorf("updates_installation cannot be empty"),
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+		err := s.Details.UpdatesInstallation.Validate()
+		if err != nil {
+			return &errors.APIError{
+				Message:    "Invalid updates installation.",
+				Err:        err,
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
 	}
 
 	return nil
 }
 
 func (m *Manager) addCron(s *Schedule) error {
-	return m.cron.Add(s.ID, s.Schedule, m.run)
+	err := m.cron.Add(s.ID, s.Schedule, m.run)
+	if err != nil {
+		return err
+	}
+
+	m.cronsMu.Lock()
+	defer m.cronsMu.Unlock()
+	m.crons[s.ID] = s.Schedule
+	return nil
+}
+
+func (m *Manager) removeCron(id string) {
+	m.cron.Remove(id)
+
+	m.cronsMu.Lock()
+	defer m.cronsMu.Unlock()
+	delete(m.crons, id)
 }
 
 func (m *Manager) run(ctx context.Context, id string) {
+	if m.shouldRun != nil && !m.shouldRun() {
+		m.Debugf("Skipping schedule %s, it runs on another node.", id)
+		return
+	}
+
 	schedule, err := m.provider.Get(ctx, id)
 	if err != nil {
 		m.Errorf("Could not get schedule %s: %v", id, err)
@@ -287,6 +382,9 @@ func (m *Manager) run(ctx context.Context, id string) {
 		timeoutSec := schedule.Details.TimeoutSec
 		if timeoutSec <= 0 {
 			timeoutSec = m.runRemoteCmdTimeoutSec
+			if schedule.Type == TypeUpdates {
+				timeoutSec = jobs.DefaultUpdatesInstallationTimeoutSec
+			}
 		}
 		cnt, err := m.provider.CountJobsInProgress(ctx, id, timeoutSec)
 		if err != nil {
@@ -301,7 +399,12 @@ func (m *Manager) run(ctx context.Context, id string) {
 
 	m.Infof("Running schedule: %s", id)
 
-	_, err = m.jobRunner.StartMultiClientJob(ctx, &jobs.MultiJobRequest{
+	var updatesInstallation *models.UpdatesInstallation
+	if schedule.Type == TypeUpdates {
+		updatesInstallation = schedule.Details.UpdatesInstallation
+	}
+
+	multiJob, err := m.jobRunner.StartMultiClientJob(ctx, &jobs.MultiJobRequest{
 		ScheduleID:          &schedule.ID,
 		Username:            schedule.CreatedBy,
 		ClientIDs:           schedule.Details.ClientIDs,
@@ -315,7 +418,11 @@ func (m *Manager) run(ctx context.Context, id string) {
 		ExecuteConcurrently: schedule.Details.ExecuteConcurrently,
 		AbortOnError:        schedule.Details.AbortOnError,
 		IsScript:            schedule.Type == TypeScript,
+		UpdatesInstallation: updatesInstallation,
 	})
+	if m.onRun != nil {
+		m.onRun(schedule, multiJob, err)
+	}
 	if err != nil {
 		m.Errorf("Error running schedule %s: %v", id, err)
 		return
@@ -1,9 +1,17 @@
 package schedule
 
 import (
+	"context"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	jobsmigration "github.com/cloudradar-monitoring/rport/db/migration/jobs"
+	"github.com/cloudradar-monitoring/rport/db/sqlite"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/models"
 )
 
 func TestValidate(t *testing.T) {
@@ -23,7 +31,7 @@ func TestValidate(t *testing.T) {
 					Type: "invalid",
 				},
 			},
-			ExpectedError: "type must be 'command' or 'script'",
+			ExpectedError: "type must be 'command', 'script' or 'updates'",
 		},
 		{
 			Name: "invalid schedule",
@@ -103,6 +111,53 @@ func TestValidate(t *testing.T) {
 			},
 			ExpectedError: "",
 		},
+		{
+			Name: "empty updates installation",
+			Schedule: &Schedule{
+				Base: Base{
+					Type:     TypeUpdates,
+					Schedule: "* * * * *",
+				},
+				Details: Details{
+					ClientIDs: []string{"id-1"},
+				},
+			},
+			ExpectedError: "updates_installation cannot be empty",
+		},
+		{
+			Name: "invalid updates installation",
+			Schedule: &Schedule{
+				Base: Base{
+					Type:     TypeUpdates,
+					Schedule: "* * * * *",
+				},
+				Details: Details{
+					ClientIDs: []string{"id-1"},
+					UpdatesInstallation: &models.UpdatesInstallation{
+						Mode:     models.UpdatesInstallationModePackages,
+						Packages: []string{"--allow-downgrades"},
+					},
+				},
+			},
+			ExpectedError: `invalid package name "--allow-downgrades"`,
+		},
+		{
+			Name: "ok updates",
+			Schedule: &Schedule{
+				Base: Base{
+					Type:     TypeUpdates,
+					Schedule: "0 3 * * 0",
+				},
+				Details: Details{
+					GroupIDs: []string{"id-1"},
+					UpdatesInstallation: &models.UpdatesInstallation{
+						Mode:   models.UpdatesInstallationModeSecurity,
+						Reboot: true,
+					},
+				},
+			},
+			ExpectedError: "",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -110,7 +165,7 @@ func TestValidate(t *testing.T) {
 		t.Run(tc.Name, func(t *testing.T) {
 			t.Parallel()
 
-			err := manager.validate(tc.Schedule)
+			err := manager.Validate(tc.Schedule)
 
 			if tc.ExpectedError == "" {
 				assert.NoError(t, err)
@@ -120,3 +175,27 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestReload(t *testing.T) {
+	db, err := sqlite.New(":memory:", jobsmigration.AssetNames(), jobsmigration.Asset, DataSourceOptions)
+	require.NoError(t, err)
+	defer db.Close()
+	ctx := context.Background()
+	testLog := logger.NewLogger("test", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug)
+	manager := NewManager(nil, db, testLog, 60)
+
+	require.NoError(t, addTestData(db))
+
+	err = manager.Reload(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"1": "* * * * *", "2": "*/5 * * * *"}, manager.crons)
+
+	changed := *testData[0]
+	changed.Schedule = "0 * * * *"
+	require.NoError(t, manager.provider.Update(ctx, &changed))
+	require.NoError(t, manager.provider.Delete(ctx, "2"))
+
+	err = manager.Reload(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"1": "0 * * * *"}, manager.crons)
+}
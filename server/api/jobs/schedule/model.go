@@ -14,6 +14,7 @@ import (
 const (
 	TypeCommand = "command"
 	TypeScript  = "script"
+	TypeUpdates = "updates"
 )
 
 type Schedule struct {
@@ -78,6 +79,8 @@ type Details struct {
 	ExecuteConcurrently bool                  `json:"execute_concurrently" db:"-"`
 	AbortOnError        *bool                 `json:"abort_on_error" db:"-"`
 	Overlaps            bool                  `json:"overlaps" db:"-"`
+
+	UpdatesInstallation *models.UpdatesInstallation `json:"updates_installation,omitempty" db:"-"`
 }
 
 func (d *Details) Scan(value interface{}) error {
@@ -92,7 +92,7 @@ func (p *SQLiteProvider) List(ctx context.Context, options *query.ListOptions) (
 
 	q, params := p.converter.ConvertListOptionsToQuery(options, schedulesQuery)
 
-	err := p.db.SelectContext(ctx, &values, q, params...)
+	err := p.db.SelectContext(ctx, &values, p.db.Rebind(q), params...)
 	if err != nil {
 		return nil, err
 	}
@@ -110,10 +110,10 @@ func (p *SQLiteProvider) Close() error {
 }
 
 func (p *SQLiteProvider) Get(ctx context.Context, id string) (*Schedule, error) {
-	q := schedulesQuery + " WHERE `id` = ? LIMIT 1"
+	q := schedulesQuery + " WHERE id = ? LIMIT 1"
 
 	s := &DBSchedule{}
-	err := p.db.GetContext(ctx, s, q, id)
+	err := p.db.GetContext(ctx, s, p.db.Rebind(q), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -125,7 +125,7 @@ func (p *SQLiteProvider) Get(ctx context.Context, id string) (*Schedule, error)
 }
 
 func (p *SQLiteProvider) Delete(ctx context.Context, id string) error {
-	res, err := p.db.ExecContext(ctx, "DELETE FROM `schedules` WHERE `id` = ?", id)
+	res, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM schedules WHERE id = ?"), id)
 
 	if err != nil {
 		return err
@@ -141,13 +141,13 @@ func (p *SQLiteProvider) Delete(ctx context.Context, id string) error {
 	}
 
 	// Delete associated jobs
-	_, err = p.db.ExecContext(ctx, "DELETE FROM jobs WHERE multi_job_id IN (SELECT jid FROM multi_jobs WHERE schedule_id = ?)", id)
+	_, err = p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM jobs WHERE multi_job_id IN (SELECT jid FROM multi_jobs WHERE schedule_id = ?)"), id)
 	if err != nil {
 		return err
 	}
 
 	// Delete associated multi jobs
-	_, err = p.db.ExecContext(ctx, "DELETE FROM multi_jobs WHERE schedule_id = ?", id)
+	_, err = p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM multi_jobs WHERE schedule_id = ?"), id)
 	if err != nil {
 		return err
 	}
@@ -159,7 +159,7 @@ func (p *SQLiteProvider) Delete(ctx context.Context, id string) error {
 func (p *SQLiteProvider) CountJobsInProgress(ctx context.Context, scheduleID string, timeoutSec int) (int, error) {
 	var result int
 
-	err := p.db.GetContext(ctx, &result, `
+	err := p.db.GetContext(ctx, &result, p.db.Rebind(fmt.Sprintf(`
 SELECT count(*)
 FROM jobs
 JOIN multi_jobs ON jobs.multi_job_id = multi_jobs.jid
@@ -168,8 +168,8 @@ WHERE
 AND
 	finished_at IS NULL
 AND
-	strftime('%s', 'now') - strftime('%s', jobs.started_at) <= ?
-`, scheduleID, timeoutSec)
+	%s - %s <= ?
+`, p.converter.UnixTime("CURRENT_TIMESTAMP"), p.converter.UnixTime("jobs.started_at"))), scheduleID, timeoutSec)
 	if err != nil {
 		return 0, err
 	}
@@ -6,12 +6,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	jobsmigration "github.com/cloudradar-monitoring/rport/db/migration/jobs"
 	"github.com/cloudradar-monitoring/rport/db/sqlite"
 	"github.com/cloudradar-monitoring/rport/server/api/jobs"
 	"github.com/cloudradar-monitoring/rport/server/test/jb"
 	"github.com/cloudradar-monitoring/rport/share/logger"
 	"github.com/cloudradar-monitoring/rport/share/ptr"
+	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/test"
 
 	"github.com/jmoiron/sqlx"
 
@@ -228,3 +231,49 @@ func assertCount(t *testing.T, db *sqlx.DB, expected int, query string) {
 
 	assert.Equal(t, expected, result)
 }
+
+func TestProviderDatabases(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		db, err := database.Open("jobs", ":memory:", jobsmigration.AssetNames(), jobsmigration.Asset, database.Options{SQLite: DataSourceOptions, PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		dbProv := newSQLiteProvider(db)
+		defer dbProv.Close()
+		ctx := context.Background()
+
+		for _, s := range testData {
+			require.NoError(t, dbProv.Insert(ctx, s))
+		}
+		updated := *testData[0]
+		updated.Schedule = "1 5 * * *"
+		require.NoError(t, dbProv.Update(ctx, &updated))
+
+		val, err := dbProv.Get(ctx, "1")
+		require.NoError(t, err)
+		require.NotNil(t, val)
+		assert.True(t, updated.CreatedAt.Equal(val.CreatedAt))
+		assert.Equal(t, "1 5 * * *", val.Schedule)
+		assert.Equal(t, updated.Details, val.Details)
+		assert.Nil(t, val.LastExecution)
+
+		addJobs(t, db)
+
+		result, err := dbProv.List(ctx, &query.ListOptions{Sorts: []query.SortOption{{Column: "id", IsASC: true}}})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "1", result[0].ID)
+		require.NotNil(t, result[0].LastExecution)
+		assert.Equal(t, 3, *result[0].LastExecution.ClientCount)
+		assert.Equal(t, 3, *result[0].LastExecution.SuccessCount)
+
+		count, err := dbProv.CountJobsInProgress(ctx, "1", 60)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		require.NoError(t, dbProv.Delete(ctx, "2"))
+		val, err = dbProv.Get(ctx, "2")
+		require.NoError(t, err)
+		assert.Nil(t, val)
+		assertCount(t, db, 3, "SELECT count(*) FROM jobs")
+		assertCount(t, db, 0, "SELECT count(*) FROM multi_jobs WHERE schedule_id = '2'")
+	})
+}
@@ -30,10 +30,15 @@ func (s *PushoverService) Send(ctx context.Context, data Data) error {
 requested from %s
 <i>with %s</i>
 <i>valid for %.0f seconds</i>.`, data.Token, data.RemoteAddress, data.UserAgent, data.TTL.Seconds())
+	html := true
+	if data.Message != "" {
+		body = data.Message
+		html = false
+	}
 	pMsg := &pushover.Message{
 		Title:     data.Title,
 		Message:   body,
-		HTML:      true,
+		HTML:      html,
 		Timestamp: time.Now().Unix(),
 		Retry:     5 * time.Second,
 	}
@@ -54,6 +54,14 @@ func (s *ScriptService) Send(ctx context.Context, data Data) error {
 }
 
 func (s *ScriptService) DataToEnv(data Data) []string {
+	if data.Message != "" {
+		return []string{
+			fmt.Sprintf("RPORT_MESSAGE_SENDTO=%v", data.SendTo),
+			fmt.Sprintf("RPORT_MESSAGE_TITLE=%v", data.Title),
+			fmt.Sprintf("RPORT_MESSAGE_BODY=%v", data.Message),
+		}
+	}
+
 	return []string{
 		fmt.Sprintf("RPORT_2FA_TOKEN=%v", data.Token),
 		fmt.Sprintf("RPORT_2FA_SENDTO=%v", data.SendTo),
@@ -89,3 +89,21 @@ func TestDataToEnv(t *testing.T) {
 	}
 	assert.ElementsMatch(t, expected, env)
 }
+
+func TestDataToEnvWithMessage(t *testing.T) {
+	ss := message.NewScriptService("", message.ValidationNone, nil)
+
+	data := message.Data{
+		SendTo:  "whatever@example.com",
+		Title:   "Alert",
+		Message: "cpu usage is above 90%",
+	}
+	env := ss.DataToEnv(data)
+
+	expected := []string{
+		"RPORT_MESSAGE_SENDTO=whatever@example.com",
+		"RPORT_MESSAGE_TITLE=Alert",
+		"RPORT_MESSAGE_BODY=cpu usage is above 90%",
+	}
+	assert.ElementsMatch(t, expected, env)
+}
@@ -12,6 +12,8 @@ type Data struct {
 	UserAgent     string
 	RemoteAddress string
 	TTL           time.Duration
+	// Message is a custom message body, if set it's sent instead of the 2fa token message.
+	Message string
 }
 
 type Service interface {
@@ -48,6 +48,9 @@ The token is: %s
 The token has been requested from %s
 with user agent %s.
 Token is valid for %.0f seconds.`, data.Token, data.RemoteAddress, data.UserAgent, data.TTL.Seconds())
+	if data.Message != "" {
+		message = data.Message
+	}
 	e := &email.Email{
 		From:    s.From,
 		To:      []string{data.SendTo},
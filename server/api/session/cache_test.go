@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
 )
 
 func SetupTestAPISessionCache(t *testing.T) (ctx context.Context, cache *Cache) {
@@ -17,7 +19,7 @@ func SetupTestAPISessionCache(t *testing.T) (ctx context.Context, cache *Cache)
 	defaultExpiration := time.Duration(100)
 	cleanupInterval := time.Duration(200)
 
-	storage, err := NewSqliteProvider(":memory:", DataSourceOptions)
+	storage, err := NewSqliteProvider(":memory:", database.Options{SQLite: DataSourceOptions})
 	require.NoError(t, err)
 
 	cache, err = NewCache(ctx, defaultExpiration, cleanupInterval, storage, nil)
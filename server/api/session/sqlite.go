@@ -8,28 +8,33 @@ import (
 
 	"github.com/jmoiron/sqlx"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/migration/api_sessions"
-	"github.com/cloudradar-monitoring/rport/db/sqlite"
+	"github.com/cloudradar-monitoring/rport/share/query"
 )
 
 type SqliteProvider struct {
-	db *sqlx.DB
+	db        *sqlx.DB
+	converter *query.SQLConverter
 }
 
-func NewSqliteProvider(dbPath string, dataSourceOptions sqlite.DataSourceOptions) (*SqliteProvider, error) {
-	db, err := sqlite.New(dbPath, api_sessions.AssetNames(), api_sessions.Asset, dataSourceOptions)
+func NewSqliteProvider(dbPath string, dbOptions database.Options) (*SqliteProvider, error) {
+	db, err := database.Open("api_sessions", dbPath, api_sessions.AssetNames(), api_sessions.Asset, dbOptions)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create api session DB instance: %w", err)
 	}
 
-	return &SqliteProvider{db: db}, nil
+	return &SqliteProvider{
+		db:        db,
+		converter: query.NewSQLConverter(db.DriverName()),
+	}, nil
 }
 
 func (p *SqliteProvider) GetAll(ctx context.Context) ([]*APISession, error) {
 	var result []*APISession
 	err := p.db.SelectContext(
 		ctx, &result,
-		"SELECT * FROM api_sessions WHERE DATETIME(expires_at) >= DATETIME(?)",
+		p.db.Rebind(fmt.Sprintf("SELECT * FROM api_sessions WHERE %s >= %s", p.converter.DateTime("expires_at"), p.converter.DateTime("?"))),
 		time.Now(),
 	)
 	if err != nil {
@@ -44,7 +49,7 @@ func (p *SqliteProvider) Get(ctx context.Context, sessionID int64) (*APISession,
 
 	err := p.db.GetContext(ctx,
 		res,
-		"SELECT * FROM api_sessions WHERE session_id = ?",
+		p.db.Rebind("SELECT * FROM api_sessions WHERE session_id = ?"),
 		sessionID,
 	)
 	if err != nil {
@@ -73,22 +78,19 @@ func (p *SqliteProvider) Save(ctx context.Context, session *APISession) (session
 }
 
 func (p *SqliteProvider) add(ctx context.Context, session *APISession) (sessionID int64, err error) {
-	result, err := p.db.NamedExecContext(
+	sessionID, err = database.InsertReturningID(
 		ctx,
+		p.db,
 		"INSERT INTO"+
 			" api_sessions (expires_at, username, last_access_at, user_agent, ip_address)"+
-			" VALUES (:expires_at, :username, :last_access_at, :user_agent, :ip_address)",
-		session,
+			" VALUES (?, ?, ?, ?, ?)",
+		"session_id",
+		session.ExpiresAt, session.Username, session.LastAccessAt, session.UserAgent, session.IPAddress,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("unable to create api session: %w", err)
 	}
 
-	sessionID, err = result.LastInsertId()
-	if err != nil {
-		return 0, fmt.Errorf("unable to get api session ID for save: %w", err)
-	}
-
 	return sessionID, nil
 }
 
@@ -112,7 +114,7 @@ func (p *SqliteProvider) update(ctx context.Context, session *APISession) (sessi
 func (p *SqliteProvider) Delete(ctx context.Context, sessionID int64) error {
 	_, err := p.db.ExecContext(
 		ctx,
-		"DELETE FROM api_sessions WHERE session_id = ?",
+		p.db.Rebind("DELETE FROM api_sessions WHERE session_id = ?"),
 		sessionID,
 	)
 	if err != nil {
@@ -125,7 +127,7 @@ func (p *SqliteProvider) Delete(ctx context.Context, sessionID int64) error {
 func (p *SqliteProvider) DeleteExpired(ctx context.Context) error {
 	_, err := p.db.ExecContext(
 		ctx,
-		"DELETE FROM api_sessions WHERE DATETIME(expires_at) <= DATETIME(?)",
+		p.db.Rebind(fmt.Sprintf("DELETE FROM api_sessions WHERE %s <= %s", p.converter.DateTime("expires_at"), p.converter.DateTime("?"))),
 		time.Now(),
 	)
 	if err != nil {
@@ -142,7 +144,7 @@ func (p *SqliteProvider) Close() error {
 func (p *SqliteProvider) DeleteAllByUser(ctx context.Context, username string) (err error) {
 	_, err = p.db.ExecContext(
 		ctx,
-		"DELETE FROM api_sessions WHERE username = ?",
+		p.db.Rebind("DELETE FROM api_sessions WHERE username = ?"),
 		username,
 	)
 	if err != nil {
@@ -155,7 +157,7 @@ func (p *SqliteProvider) DeleteAllByUser(ctx context.Context, username string) (
 func (p *SqliteProvider) DeleteByID(ctx context.Context, username string, sessionID int64) (err error) {
 	_, err = p.db.ExecContext(
 		ctx,
-		"DELETE FROM api_sessions WHERE username = ? AND session_id = ?",
+		p.db.Rebind("DELETE FROM api_sessions WHERE username = ? AND session_id = ?"),
 		username,
 		sessionID,
 	)
@@ -5,7 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/sqlite"
+	"github.com/cloudradar-monitoring/rport/share/test"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/stretchr/testify/assert"
@@ -101,7 +103,7 @@ func TestAPISessionSqlite(t *testing.T) {
 }
 
 func newInmemoryDB(t *testing.T, sessions ...*APISession) *SqliteProvider {
-	p, err := NewSqliteProvider(":memory:", DataSourceOptions)
+	p, err := NewSqliteProvider(":memory:", database.Options{SQLite: DataSourceOptions})
 	require.NoError(t, err)
 
 	for _, cur := range sessions {
@@ -133,3 +135,65 @@ func generateAPISession(t *testing.T, username string, expiresAt time.Time, last
 
 	return apiSession
 }
+
+func TestAPISessionProviderDatabases(t *testing.T) {
+	sessionIDs := func(sessions []*APISession) []int64 {
+		ids := make([]int64, 0, len(sessions))
+		for _, s := range sessions {
+			ids = append(ids, s.SessionID)
+		}
+		return ids
+	}
+
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		p, err := NewSqliteProvider(":memory:", database.Options{PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		defer p.Close()
+
+		now := time.Now().UTC().Truncate(time.Second)
+		active, err := p.Save(ctx, generateAPISession(t, "user1", now.Add(time.Hour), now))
+		require.NoError(t, err)
+		expired, err := p.Save(ctx, generateAPISession(t, "user1", now.Add(-time.Minute), now))
+		require.NoError(t, err)
+		other, err := p.Save(ctx, generateAPISession(t, "user2", now.Add(time.Hour), now))
+		require.NoError(t, err)
+		assert.NotEqual(t, active, expired, "generated ids must be unique")
+
+		all, err := p.GetAll(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int64{active, other}, sessionIDs(all))
+
+		updated := generateAPISession(t, "user1", now.Add(2*time.Hour), now.Add(time.Minute))
+		updated.SessionID = active
+		updated.UserAgent = "Firefox"
+		_, err = p.Save(ctx, updated)
+		require.NoError(t, err)
+		got, err := p.Get(ctx, active)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, "Firefox", got.UserAgent)
+		assert.True(t, now.Add(2*time.Hour).Equal(got.ExpiresAt))
+		assert.True(t, now.Add(time.Minute).Equal(got.LastAccessAt))
+
+		require.NoError(t, p.DeleteExpired(ctx))
+		got, err = p.Get(ctx, expired)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		require.NoError(t, p.DeleteByID(ctx, "user2", active))
+		got, err = p.Get(ctx, active)
+		require.NoError(t, err)
+		assert.NotNil(t, got, "sessions of other users must not be deleted")
+
+		require.NoError(t, p.DeleteAllByUser(ctx, "user1"))
+		all, err = p.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{other}, sessionIDs(all))
+
+		require.NoError(t, p.Delete(ctx, other))
+		all, err = p.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+}
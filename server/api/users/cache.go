@@ -32,6 +32,20 @@ func (r *UserCache) GetByUsername(username string) (*User, error) {
 	return r.byUsername[username], nil
 }
 
+// Set adds the given user to cache or replaces the cached user with the same username
+func (r *UserCache) Set(user *User) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.byUsername[user.Username] = user
+}
+
+// Remove removes the user with the given username from cache
+func (r *UserCache) Remove(username string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.byUsername, username)
+}
+
 func (r *UserCache) GetAll() ([]*User, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -47,4 +47,11 @@ func TestUserCache(t *testing.T) {
 	users, err = c.GetAll()
 	require.NoError(t, err)
 	assert.ElementsMatch(t, []*User{u2, u3}, users)
+
+	c.Set(u1)
+	c.Remove(u2.Username)
+
+	users, err = c.GetAll()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []*User{u1, u3}, users)
 }
@@ -10,6 +10,7 @@ import (
 	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
 	"github.com/cloudradar-monitoring/rport/share/enums"
 	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/query"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -21,10 +22,11 @@ type UserDatabase struct {
 	groupsTableName       string
 	groupDetailsTableName string
 
-	twoFAOn        bool
-	hasTokenColumn bool
-	totPOn         bool
-	logger         *logger.Logger
+	twoFAOn                         bool
+	hasTokenColumn                  bool
+	hasClientGroupPermissionsColumn bool
+	totPOn                          bool
+	logger                          *logger.Logger
 }
 
 func NewUserDatabase(
@@ -50,6 +52,14 @@ func NewUserDatabase(
 	return d, nil
 }
 
+// prepare adapts a query written with MySQL identifier quotes and ? placeholders to the database in use
+func (d *UserDatabase) prepare(q string) string {
+	if d.db.DriverName() == query.DriverPostgres {
+		q = strings.ReplaceAll(q, "`", `"`)
+	}
+	return d.db.Rebind(q)
+}
+
 func (d *UserDatabase) getSelectClause() string {
 	s := "username, password, password_expired"
 	if d.twoFAOn {
@@ -64,27 +74,39 @@ func (d *UserDatabase) getSelectClause() string {
 	return s
 }
 
+func (d *UserDatabase) getGroupSelectClause() string {
+	s := "name, permissions"
+	if d.hasClientGroupPermissionsColumn {
+		s += ", client_group_permissions"
+	}
+	return s
+}
+
 // checkDatabaseTables @todo use context for all db operations
 func (d *UserDatabase) checkDatabaseTables() error {
-	_, err := d.db.Exec(fmt.Sprintf("SELECT token FROM `%s` LIMIT 0", d.usersTableName))
+	_, err := d.db.Exec(d.prepare(fmt.Sprintf("SELECT token FROM `%s` LIMIT 0", d.usersTableName)))
 	if err == nil {
 		d.hasTokenColumn = true
 	}
 
-	_, err = d.db.Exec(fmt.Sprintf("SELECT %s FROM `%s` LIMIT 0", d.getSelectClause(), d.usersTableName))
+	_, err = d.db.Exec(d.prepare(fmt.Sprintf("SELECT %s FROM `%s` LIMIT 0", d.getSelectClause(), d.usersTableName)))
 	if err != nil {
 		err = fmt.Errorf("%v, if you have 2fa enabled please check additional column requirements at https://oss.rport.io/docs/no02-api-auth.html#database", err)
 		return err
 	}
-	_, err = d.db.Exec(fmt.Sprintf("SELECT username, `group` FROM `%s` LIMIT 0", d.groupsTableName))
+	_, err = d.db.Exec(d.prepare(fmt.Sprintf("SELECT username, `group` FROM `%s` LIMIT 0", d.groupsTableName)))
 	if err != nil {
 		return err
 	}
 	if d.groupDetailsTableName != "" {
-		_, err = d.db.Exec(fmt.Sprintf("SELECT name, permissions FROM `%s` LIMIT 0", d.groupDetailsTableName))
+		_, err = d.db.Exec(d.prepare(fmt.Sprintf("SELECT name, permissions FROM `%s` LIMIT 0", d.groupDetailsTableName)))
 		if err != nil {
 			return err
 		}
+		_, err = d.db.Exec(d.prepare(fmt.Sprintf("SELECT client_group_permissions FROM `%s` LIMIT 0", d.groupDetailsTableName)))
+		if err == nil {
+			d.hasClientGroupPermissionsColumn = true
+		}
 	}
 
 	return nil
@@ -93,14 +115,14 @@ func (d *UserDatabase) checkDatabaseTables() error {
 // GetByUsername @todo use context for all db operations
 func (d *UserDatabase) GetByUsername(username string) (*User, error) {
 	user := &User{}
-	err := d.db.Get(user, fmt.Sprintf("SELECT %s FROM `%s` WHERE username = ? LIMIT 1", d.getSelectClause(), d.usersTableName), username)
+	err := d.db.Get(user, d.prepare(fmt.Sprintf("SELECT %s FROM `%s` WHERE username = ? LIMIT 1", d.getSelectClause(), d.usersTableName)), username)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
 	}
-	err = d.db.Select(&user.Groups, fmt.Sprintf("SELECT DISTINCT(`group`) FROM `%s` WHERE username = ?", d.groupsTableName), username)
+	err = d.db.Select(&user.Groups, d.prepare(fmt.Sprintf("SELECT DISTINCT(`group`) FROM `%s` WHERE username = ?", d.groupsTableName)), username)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
@@ -111,7 +133,7 @@ func (d *UserDatabase) GetByUsername(username string) (*User, error) {
 // GetAll @todo use context for all db operations
 func (d *UserDatabase) GetAll() ([]*User, error) {
 	var usrs []*User
-	err := d.db.Select(&usrs, fmt.Sprintf("SELECT %s FROM `%s` ORDER BY username", d.getSelectClause(), d.usersTableName))
+	err := d.db.Select(&usrs, d.prepare(fmt.Sprintf("SELECT %s FROM `%s` ORDER BY username", d.getSelectClause(), d.usersTableName)))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -123,7 +145,7 @@ func (d *UserDatabase) GetAll() ([]*User, error) {
 		Username string `db:"username"`
 		Group    string `db:"group"`
 	}
-	err = d.db.Select(&groups, fmt.Sprintf("SELECT `username`, `group` FROM `%s` ORDER BY `group`", d.groupsTableName))
+	err = d.db.Select(&groups, d.prepare(fmt.Sprintf("SELECT `username`, `group` FROM `%s` ORDER BY `group`", d.groupsTableName)))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return usrs, nil
@@ -145,14 +167,14 @@ func (d *UserDatabase) ListGroups() ([]Group, error) {
 	var groups []Group
 
 	if d.groupDetailsTableName != "" {
-		err := d.db.Select(&groups, fmt.Sprintf("SELECT name, permissions FROM `%s` ORDER BY `name`", d.groupDetailsTableName))
+		err := d.db.Select(&groups, d.prepare(fmt.Sprintf("SELECT %s FROM `%s` ORDER BY `name`", d.getGroupSelectClause(), d.groupDetailsTableName)))
 		if err != nil && err != sql.ErrNoRows {
 			return nil, err
 		}
 	}
 
 	var userGroups []string
-	err := d.db.Select(&userGroups, fmt.Sprintf("SELECT DISTINCT `group` FROM `%s` ORDER BY `group`", d.groupsTableName))
+	err := d.db.Select(&userGroups, d.prepare(fmt.Sprintf("SELECT DISTINCT `group` FROM `%s` ORDER BY `group`", d.groupsTableName)))
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
@@ -179,7 +201,7 @@ func (d *UserDatabase) GetGroup(name string) (Group, error) {
 	}
 
 	group := Group{}
-	err := d.db.Get(&group, fmt.Sprintf("SELECT name, permissions FROM `%s` WHERE name = ? LIMIT 1", d.groupDetailsTableName), name)
+	err := d.db.Get(&group, d.prepare(fmt.Sprintf("SELECT %s FROM `%s` WHERE name = ? LIMIT 1", d.getGroupSelectClause(), d.groupDetailsTableName)), name)
 	if err == sql.ErrNoRows {
 		return NewGroup(name), nil
 	} else if err != nil {
@@ -196,12 +218,36 @@ func (d *UserDatabase) UpdateGroup(name string, group Group) error {
 			HTTPStatus: http.StatusBadRequest,
 		}
 	}
+	if len(group.ClientGroupPermissions) > 0 && !d.hasClientGroupPermissionsColumn {
+		return errors2.APIError{
+			Message: "Column 'client_group_permissions' must be added to the user group details table for this operation, " +
+				"see https://oss.rport.io/docs/no02-api-auth.html#database",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
 	group.Name = name
-	_, err := d.db.NamedExec(
-		// We rely on a unique index. Let the database decide, if INSERT or UPDATE is needed.
-		fmt.Sprintf("REPLACE INTO `%s` (name, permissions) VALUES (:name, :permissions)", d.groupDetailsTableName),
-		group,
-	)
+	// We rely on a unique index. Let the database decide, if INSERT or UPDATE is needed.
+	q := fmt.Sprintf("REPLACE INTO `%s` (name, permissions) VALUES (:name, :permissions)", d.groupDetailsTableName)
+	if d.db.DriverName() == query.DriverPostgres {
+		q = fmt.Sprintf(
+			"INSERT INTO `%s` (name, permissions) VALUES (:name, :permissions) ON CONFLICT (name) DO UPDATE SET permissions = excluded.permissions",
+			d.groupDetailsTableName,
+		)
+	}
+	if d.hasClientGroupPermissionsColumn {
+		q = fmt.Sprintf(
+			"REPLACE INTO `%s` (name, permissions, client_group_permissions) VALUES (:name, :permissions, :client_group_permissions)",
+			d.groupDetailsTableName,
+		)
+		if d.db.DriverName() == query.DriverPostgres {
+			q = fmt.Sprintf(
+				"INSERT INTO `%s` (name, permissions, client_group_permissions) VALUES (:name, :permissions, :client_group_permissions) "+
+					"ON CONFLICT (name) DO UPDATE SET permissions = excluded.permissions, client_group_permissions = excluded.client_group_permissions",
+				d.groupDetailsTableName,
+			)
+		}
+	}
+	_, err := d.db.NamedExec(d.prepare(q), group)
 	if err != nil {
 		return err
 	}
@@ -222,13 +268,13 @@ func (d *UserDatabase) DeleteGroup(name string) error {
 		return err
 	}
 
-	_, err = tx.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE `group` = ?", d.groupsTableName), name)
+	_, err = tx.Exec(d.prepare(fmt.Sprintf("DELETE FROM `%s` WHERE `group` = ?", d.groupsTableName)), name)
 	if err != nil {
 		d.handleRollback(tx)
 		return err
 	}
 
-	_, err = tx.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE `name` = ?", d.groupDetailsTableName), name)
+	_, err = tx.Exec(d.prepare(fmt.Sprintf("DELETE FROM `%s` WHERE `name` = ?", d.groupDetailsTableName)), name)
 	if err != nil {
 		d.handleRollback(tx)
 		return err
@@ -271,12 +317,12 @@ func (d *UserDatabase) Add(usr *User) error {
 	}
 
 	_, err = tx.Exec(
-		fmt.Sprintf(
+		d.prepare(fmt.Sprintf(
 			"INSERT INTO `%s` (%s) VALUES (%s)",
 			d.usersTableName,
 			strings.Join(columns, ", "),
 			strings.TrimRight(strings.Repeat("?,", len(params)), ","),
-		),
+		)),
 		params...,
 	)
 
@@ -287,7 +333,7 @@ func (d *UserDatabase) Add(usr *User) error {
 
 	for i := range usr.Groups {
 		_, err := tx.Exec(
-			fmt.Sprintf("INSERT INTO `%s` (`username`, `group`) VALUES (?, ?)", d.groupsTableName),
+			d.prepare(fmt.Sprintf("INSERT INTO `%s` (`username`, `group`) VALUES (?, ?)", d.groupsTableName)),
 			usr.Username,
 			usr.Groups[i],
 		)
@@ -355,7 +401,7 @@ func (d *UserDatabase) Update(usr *User, usernameToUpdate string) error {
 			strings.Join(statements, ", "),
 		)
 		params = append(params, usernameToUpdate)
-		_, err := tx.Exec(q, params...)
+		_, err := tx.Exec(d.prepare(q), params...)
 		if err != nil {
 			d.handleRollback(tx)
 			return err
@@ -364,7 +410,7 @@ func (d *UserDatabase) Update(usr *User, usernameToUpdate string) error {
 
 	if usr.Username != "" && usernameToUpdate != usr.Username {
 		_, err := tx.Exec(
-			fmt.Sprintf("UPDATE `%s` SET `username` = ? WHERE `username` = ?", d.groupsTableName),
+			d.prepare(fmt.Sprintf("UPDATE `%s` SET `username` = ? WHERE `username` = ?", d.groupsTableName)),
 			usr.Username,
 			usernameToUpdate,
 		)
@@ -380,7 +426,7 @@ func (d *UserDatabase) Update(usr *User, usernameToUpdate string) error {
 	}
 
 	if usr.Groups != nil {
-		_, err := tx.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE `username` = ?", d.groupsTableName), groupUserName)
+		_, err := tx.Exec(d.prepare(fmt.Sprintf("DELETE FROM `%s` WHERE `username` = ?", d.groupsTableName)), groupUserName)
 		if err != nil {
 			d.handleRollback(tx)
 			return err
@@ -390,7 +436,7 @@ func (d *UserDatabase) Update(usr *User, usernameToUpdate string) error {
 	for i := range usr.Groups {
 		group := usr.Groups[i]
 		_, err := tx.Exec(
-			fmt.Sprintf("INSERT INTO `%s` (`username`, `group`) VALUES (?, ?)", d.groupsTableName),
+			d.prepare(fmt.Sprintf("INSERT INTO `%s` (`username`, `group`) VALUES (?, ?)", d.groupsTableName)),
 			groupUserName,
 			group,
 		)
@@ -415,13 +461,13 @@ func (d *UserDatabase) Delete(usernameToDelete string) error {
 		return err
 	}
 
-	_, err = tx.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE `username` = ?", d.usersTableName), usernameToDelete)
+	_, err = tx.Exec(d.prepare(fmt.Sprintf("DELETE FROM `%s` WHERE `username` = ?", d.usersTableName)), usernameToDelete)
 	if err != nil {
 		d.handleRollback(tx)
 		return err
 	}
 
-	_, err = tx.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE `username` = ?", d.groupsTableName), usernameToDelete)
+	_, err = tx.Exec(d.prepare(fmt.Sprintf("DELETE FROM `%s` WHERE `username` = ?", d.groupsTableName)), usernameToDelete)
 	if err != nil {
 		d.handleRollback(tx)
 		return err
@@ -370,6 +370,48 @@ func TestUpdateGroup(t *testing.T) {
 	}
 }
 
+func TestUpdateGroupClientGroupPermissions(t *testing.T) {
+	db, err := sqlx.Connect("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	err = prepareTables(db, false, false)
+	require.NoError(t, err)
+
+	group := NewGroup("group1", PermissionVault)
+	group.ClientGroupPermissions = ClientGroupPermissions{
+		"web": NewPermissions(PermissionCommands, PermissionTunnels),
+		"db":  NewPermissions(PermissionTunnels),
+	}
+
+	d, err := NewUserDatabase(db, "users", "groups", "group_details", false, false, testLog)
+	require.NoError(t, err)
+	err = d.UpdateGroup(group.Name, group)
+	assert.EqualError(t, err, "Column 'client_group_permissions' must be added to the user group details table for this operation, "+
+		"see https://oss.rport.io/docs/no02-api-auth.html#database")
+
+	_, err = db.Exec("ALTER TABLE `group_details` ADD `client_group_permissions` TEXT")
+	require.NoError(t, err)
+
+	d, err = NewUserDatabase(db, "users", "groups", "group_details", false, false, testLog)
+	require.NoError(t, err)
+	err = d.UpdateGroup(group.Name, group)
+	require.NoError(t, err)
+
+	actual, err := d.GetGroup(group.Name)
+	require.NoError(t, err)
+	assert.True(t, actual.Permissions.Has(PermissionVault))
+	assert.True(t, actual.ClientGroupPermissions["web"].Has(PermissionCommands))
+	assert.True(t, actual.ClientGroupPermissions["web"].Has(PermissionTunnels))
+	assert.False(t, actual.ClientGroupPermissions["db"].Has(PermissionCommands))
+	assert.True(t, actual.ClientGroupPermissions["db"].Has(PermissionTunnels))
+
+	groups, err := d.ListGroups()
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Len(t, groups[0].ClientGroupPermissions, 2)
+}
+
 func TestDeleteGroup(t *testing.T) {
 	db, err := sqlx.Connect("sqlite3", ":memory:")
 	require.NoError(t, err)
@@ -780,3 +822,79 @@ func assertGroupTableEquals(t *testing.T, db *sqlx.DB, groupTableName string, ex
 	query := fmt.Sprintf("SELECT `username`, `group` FROM `%s` order by `username`, `group`", groupTableName)
 	test.AssertRowsEqual(t, db, expectedRows, query, []interface{}{})
 }
+
+func TestUserDatabasePostgres(t *testing.T) {
+	db, err := sqlx.Connect("postgres", test.PostgresDSN(t))
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE users (
+		username VARCHAR(150) NOT NULL UNIQUE,
+		password VARCHAR(255) NOT NULL,
+		password_expired BOOLEAN NOT NULL DEFAULT false,
+		two_fa_send_to VARCHAR(150),
+		token CHAR(36) DEFAULT NULL,
+		totp_secret TEXT
+	);
+	CREATE TABLE groups (
+		username VARCHAR(150) NOT NULL,
+		"group" VARCHAR(150) NOT NULL,
+		UNIQUE (username, "group")
+	);
+	CREATE TABLE group_details (
+		name VARCHAR(150) NOT NULL UNIQUE,
+		permissions TEXT DEFAULT '{}',
+		client_group_permissions TEXT
+	)`)
+	require.NoError(t, err)
+
+	d, err := NewUserDatabase(db, "users", "groups", "group_details", true, true, testLog)
+	require.NoError(t, err)
+
+	require.NoError(t, d.Add(&User{Username: "user1", Password: "pass1", TwoFASendTo: "user1@example.com", Groups: []string{"group1", "group2"}}))
+	require.NoError(t, d.Add(&User{Username: "user2", Password: "pass2", Groups: []string{"group1"}}))
+
+	user, err := d.GetByUsername("user1")
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	assert.Equal(t, "pass1", user.Password)
+	assert.Equal(t, "user1@example.com", user.TwoFASendTo)
+	assert.ElementsMatch(t, []string{"group1", "group2"}, user.Groups)
+
+	require.NoError(t, d.Update(&User{Username: "user3", Password: "pass3", Groups: []string{"group3"}}, "user1"))
+	user, err = d.GetByUsername("user1")
+	require.NoError(t, err)
+	assert.Nil(t, user)
+	user, err = d.GetByUsername("user3")
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	assert.Equal(t, "pass3", user.Password)
+	assert.Equal(t, []string{"group3"}, user.Groups)
+
+	all, err := d.GetAll()
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "user2", all[0].Username)
+	assert.Equal(t, "user3", all[1].Username)
+
+	require.NoError(t, d.UpdateGroup("group1", NewGroup("group1", PermissionCommands)))
+	require.NoError(t, d.UpdateGroup("group1", NewGroup("group1", PermissionScripts)))
+	group, err := d.GetGroup("group1")
+	require.NoError(t, err)
+	assert.Equal(t, NewGroup("group1", PermissionScripts), group)
+
+	groups, err := d.ListGroups()
+	require.NoError(t, err)
+	assert.Len(t, groups, 2)
+
+	require.NoError(t, d.DeleteGroup("group1"))
+	user, err = d.GetByUsername("user2")
+	require.NoError(t, err)
+	assert.Empty(t, user.Groups)
+
+	require.NoError(t, d.Delete("user2"))
+	require.NoError(t, d.Delete("user3"))
+	all, err = d.GetAll()
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
@@ -12,6 +12,8 @@ var AdministratorsGroup = Group{
 type Group struct {
 	Name        string      `json:"name" db:"name"`
 	Permissions Permissions `json:"permissions" db:"permissions"`
+	// ClientGroupPermissions are granted in addition to Permissions, but only for the clients of the client groups
+	ClientGroupPermissions ClientGroupPermissions `json:"client_group_permissions" db:"client_group_permissions"`
 }
 
 func NewGroup(name string, perms ...string) Group {
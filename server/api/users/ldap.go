@@ -0,0 +1,443 @@
+package users
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/share/enums"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+type LDAPConfig struct {
+	Enabled            bool               `mapstructure:"enabled"`
+	URL                string             `mapstructure:"url"`
+	StartTLS           bool               `mapstructure:"start_tls"`
+	InsecureSkipVerify bool               `mapstructure:"insecure_skip_verify"`
+	CACertFile         string             `mapstructure:"ca_cert_file"`
+	Timeout            time.Duration      `mapstructure:"timeout"`
+	BindDN             string             `mapstructure:"bind_dn"`
+	BindPassword       string             `mapstructure:"bind_password"`
+	BaseDN             string             `mapstructure:"base_dn"`
+	UserFilter         string             `mapstructure:"user_filter"`
+	UsernameAttribute  string             `mapstructure:"username_attribute"`
+	GroupAttribute     string             `mapstructure:"group_attribute"`
+	CacheTTL           time.Duration      `mapstructure:"cache_ttl"`
+	GroupMapping       []LDAPGroupMapping `mapstructure:"group_mapping"`
+}
+
+// LDAPGroupMapping grants the given user groups to the members of a directory group
+type LDAPGroupMapping struct {
+	LDAPGroup  string   `mapstructure:"ldap_group"`
+	UserGroups []string `mapstructure:"user_groups"`
+}
+
+func (c *LDAPConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	u, err := url.Parse(c.URL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid ldap.url: %q", c.URL)
+	}
+	switch u.Scheme {
+	case "ldap":
+	case "ldaps":
+		if c.StartTLS {
+			return errors.New("ldap.start_tls can't be used with an ldaps:// url")
+		}
+	default:
+		return fmt.Errorf("invalid ldap.url: unsupported scheme %q, expected ldap or ldaps", u.Scheme)
+	}
+
+	if c.BaseDN == "" {
+		return errors.New("ldap.base_dn is required")
+	}
+	if c.UsernameAttribute == "" {
+		return errors.New("ldap.username_attribute is required")
+	}
+	if c.GroupAttribute == "" {
+		return errors.New("ldap.group_attribute is required")
+	}
+	if c.UserFilter != "" {
+		if _, err := ldap.CompileFilter(c.UserFilter); err != nil {
+			return fmt.Errorf("invalid ldap.user_filter: %v", err)
+		}
+	}
+
+	if len(c.GroupMapping) == 0 {
+		return errors.New("ldap.group_mapping is required, only members of the mapped groups have access")
+	}
+	for _, m := range c.GroupMapping {
+		if m.LDAPGroup == "" {
+			return errors.New("invalid ldap.group_mapping: ldap_group is required")
+		}
+		if len(m.UserGroups) == 0 {
+			return fmt.Errorf("invalid ldap.group_mapping: user_groups of %q are empty", m.LDAPGroup)
+		}
+	}
+
+	return nil
+}
+
+// LDAPProvider reads users from an LDAP directory, e.g. Active Directory. Users are looked up with the service account
+// given by bind_dn and their passwords are verified by binding as the user. Only members of the mapped directory
+// groups are users, their user groups are given by the mapping. Found users are kept in cache for cache_ttl.
+// The directory matches usernames case-insensitively, but a user is known only by the name stored in the directory.
+// Other spellings are rejected, otherwise they would be separate identities for bans, approvals and ownership.
+type LDAPProvider struct {
+	*logger.Logger
+
+	config    LDAPConfig
+	tlsConfig *tls.Config
+	cache     *UserCache
+
+	mu sync.Mutex
+	// cached maps the lower case usernames to the name stored in the directory and the time the user was cached
+	cached map[string]cachedLDAPUser
+	now    func() time.Time
+}
+
+type cachedLDAPUser struct {
+	username string
+	cachedAt time.Time
+}
+
+// ldapCacheKey returns the key of a user in the cache, any spelling of a username finds the cached user
+func ldapCacheKey(username string) string {
+	return strings.ToLower(username)
+}
+
+func NewLDAPProvider(logger *logger.Logger, config LDAPConfig) (*LDAPProvider, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: config.InsecureSkipVerify, //nolint:gosec
+		MinVersion:         tls.VersionTLS12,
+	}
+	if config.CACertFile != "" {
+		pem, err := os.ReadFile(config.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ldap.ca_cert_file: %v", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in ldap.ca_cert_file %q", config.CACertFile)
+		}
+	}
+
+	return &LDAPProvider{
+		Logger:    logger,
+		config:    config,
+		tlsConfig: tlsConfig,
+		cache:     NewUserCache(nil),
+		cached:    make(map[string]cachedLDAPUser),
+		now:       time.Now,
+	}, nil
+}
+
+// Check verifies that the directory is reachable with the configured service account
+func (p *LDAPProvider) Check() error {
+	conn, err := p.connect()
+	if err != nil {
+		return err
+	}
+	conn.Close()
+	return nil
+}
+
+func (p *LDAPProvider) Type() enums.ProviderSource {
+	return enums.ProviderSourceLDAP
+}
+
+func (p *LDAPProvider) SupportsGroupPermissions() bool {
+	return false
+}
+
+// GetByUsername returns the cached user if it's not older than cache_ttl, otherwise the user is looked up in the
+// directory. It returns nil if the username isn't spelled as in the directory.
+func (p *LDAPProvider) GetByUsername(username string) (*User, error) {
+	p.mu.Lock()
+	cached, ok := p.cached[ldapCacheKey(username)]
+	p.mu.Unlock()
+	if ok && p.now().Sub(cached.cachedAt) < p.config.CacheTTL {
+		if cached.username != username {
+			return nil, nil
+		}
+		return p.cache.GetByUsername(cached.username)
+	}
+
+	conn, err := p.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	user, _, err := p.searchUser(conn, username)
+	return user, err
+}
+
+// GetAll returns all members of the mapped groups, it always reads from the directory and refreshes the cache
+func (p *LDAPProvider) GetAll() ([]*User, error) {
+	conn, err := p.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	groupFilters := make([]string, 0, len(p.config.GroupMapping))
+	for _, m := range p.config.GroupMapping {
+		groupFilters = append(groupFilters, fmt.Sprintf("(%s=%s)", p.config.GroupAttribute, ldap.EscapeFilter(m.LDAPGroup)))
+	}
+	entries, err := p.search(conn, fmt.Sprintf("(|%s)", strings.Join(groupFilters, "")))
+	if err != nil {
+		return nil, err
+	}
+
+	now := p.now()
+	cached := make(map[string]cachedLDAPUser, len(entries))
+	users := make([]*User, 0, len(entries))
+	for _, entry := range entries {
+		user := p.toUser(entry)
+		if user == nil {
+			continue
+		}
+		users = append(users, user)
+		cached[ldapCacheKey(user.Username)] = cachedLDAPUser{username: user.Username, cachedAt: now}
+	}
+
+	p.Debugf("Loaded %d users from ldap.", len(users))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cache.Load(users)
+	p.cached = cached
+
+	return users, nil
+}
+
+// VerifyPassword checks the password by binding as the given user, the user is looked up in the directory every time
+// to get its current DN and group membership
+func (p *LDAPProvider) VerifyPassword(username, password string) (bool, error) {
+	// an empty password would be an unauthenticated bind that succeeds for any user
+	if password == "" {
+		return false, nil
+	}
+
+	conn, err := p.connect()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	user, dn, err := p.searchUser(conn, username)
+	if err != nil || user == nil {
+		return false, err
+	}
+
+	err = conn.Bind(dn, password)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to bind as %q: %v", dn, err)
+	}
+
+	return true, nil
+}
+
+func (p *LDAPProvider) connect() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(
+		p.config.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: p.config.Timeout}),
+		ldap.DialWithTLSConfig(p.tlsConfig),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ldap server: %v", err)
+	}
+	conn.SetTimeout(p.config.Timeout)
+
+	if p.config.StartTLS {
+		if err := conn.StartTLS(p.tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start tls with ldap server: %v", err)
+		}
+	}
+
+	if p.config.BindDN != "" {
+		if err := conn.Bind(p.config.BindDN, p.config.BindPassword); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to bind to ldap server as %q: %v", p.config.BindDN, err)
+		}
+	}
+
+	return conn, nil
+}
+
+// searchUser looks up the user in the directory, updates the cache and returns the user with its DN. It returns a nil
+// user if there is no such user, the user is not a member of a mapped group or the username is spelled differently.
+func (p *LDAPProvider) searchUser(conn *ldap.Conn, username string) (*User, string, error) {
+	entries, err := p.search(conn, fmt.Sprintf("(%s=%s)", p.config.UsernameAttribute, ldap.EscapeFilter(username)))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(entries) > 1 {
+		return nil, "", fmt.Errorf("found %d ldap entries for user %q, expected one", len(entries), username)
+	}
+
+	var user *User
+	var dn string
+	if len(entries) == 1 {
+		user = p.toUser(entries[0])
+		dn = entries[0].DN
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := ldapCacheKey(username)
+	if user == nil {
+		if cached, ok := p.cached[key]; ok {
+			p.cache.Remove(cached.username)
+			delete(p.cached, key)
+		}
+		return nil, "", nil
+	}
+	p.cache.Set(user)
+	p.cached[ldapCacheKey(user.Username)] = cachedLDAPUser{username: user.Username, cachedAt: p.now()}
+
+	if user.Username != username {
+		p.Debugf("Rejecting ldap user %q, the username is %q in the directory.", username, user.Username)
+		return nil, "", nil
+	}
+
+	return user, dn, nil
+}
+
+func (p *LDAPProvider) search(conn *ldap.Conn, filter string) ([]*ldap.Entry, error) {
+	if p.config.UserFilter != "" {
+		filter = fmt.Sprintf("(&%s%s)", p.config.UserFilter, filter)
+	}
+
+	req := ldap.NewSearchRequest(
+		p.config.BaseDN,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0,
+		int(p.config.Timeout.Seconds()),
+		false,
+		filter,
+		[]string{p.config.UsernameAttribute, p.config.GroupAttribute},
+		nil,
+	)
+	res, err := conn.Search(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search ldap users: %v", err)
+	}
+
+	return res.Entries, nil
+}
+
+// toUser returns the user of the given entry with the user groups of its mapped directory groups, nil if it's not a
+// member of any mapped group
+func (p *LDAPProvider) toUser(entry *ldap.Entry) *User {
+	username := entry.GetAttributeValue(p.config.UsernameAttribute)
+	if username == "" {
+		return nil
+	}
+
+	var groups []string
+	for _, ldapGroup := range entry.GetAttributeValues(p.config.GroupAttribute) {
+		for _, m := range p.config.GroupMapping {
+			if !strings.EqualFold(m.LDAPGroup, ldapGroup) {
+				continue
+			}
+			for _, g := range m.UserGroups {
+				if !containsString(groups, g) {
+					groups = append(groups, g)
+				}
+			}
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	return &User{
+		Username: username,
+		Groups:   groups,
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *LDAPProvider) ListGroups() ([]Group, error) {
+	return nil, errors2.APIError{
+		Message:    "The LDAP authentication backend doesn't support this feature.",
+		HTTPStatus: http.StatusBadRequest,
+	}
+}
+
+func (p *LDAPProvider) GetGroup(string) (Group, error) {
+	return Group{}, errors2.APIError{
+		Message:    "The LDAP authentication backend doesn't support this feature.",
+		HTTPStatus: http.StatusBadRequest,
+	}
+}
+
+func (p *LDAPProvider) UpdateGroup(string, Group) error {
+	return errors2.APIError{
+		Message:    "The LDAP authentication backend doesn't support this feature.",
+		HTTPStatus: http.StatusBadRequest,
+	}
+}
+
+func (p *LDAPProvider) DeleteGroup(string) error {
+	return errors2.APIError{
+		Message:    "The LDAP authentication backend doesn't support this feature.",
+		HTTPStatus: http.StatusBadRequest,
+	}
+}
+
+func (p *LDAPProvider) Add(*User) error {
+	return errors2.APIError{
+		Message:    "The LDAP authentication backend doesn't support this operation, users are managed in the directory.",
+		HTTPStatus: http.StatusBadRequest,
+	}
+}
+
+func (p *LDAPProvider) Update(*User, string) error {
+	return errors2.APIError{
+		Message:    "The LDAP authentication backend doesn't support this operation, users are managed in the directory.",
+		HTTPStatus: http.StatusBadRequest,
+	}
+}
+
+func (p *LDAPProvider) Delete(string) error {
+	return errors2.APIError{
+		Message:    "The LDAP authentication backend doesn't support this operation, users are managed in the directory.",
+		HTTPStatus: http.StatusBadRequest,
+	}
+}
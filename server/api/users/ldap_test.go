@@ -0,0 +1,448 @@
+package users
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ldapStubEntry struct {
+	password string
+	attrs    map[string][]string
+}
+
+// ldapStub is a minimal in-process LDAP server, it supports simple binds, StartTLS and searches with and, or,
+// equality and presence filters
+type ldapStub struct {
+	t         *testing.T
+	listener  net.Listener
+	tlsConfig *tls.Config
+
+	mu      sync.Mutex
+	entries map[string]ldapStubEntry
+}
+
+func newLDAPStub(t *testing.T, tlsConfig *tls.Config) *ldapStub {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &ldapStub{
+		t:         t,
+		listener:  listener,
+		tlsConfig: tlsConfig,
+		entries:   make(map[string]ldapStubEntry),
+	}
+	go s.serve()
+	t.Cleanup(func() {
+		listener.Close()
+	})
+	return s
+}
+
+func (s *ldapStub) URL() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *ldapStub) Set(dn string, entry ldapStubEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[dn] = entry
+}
+
+func (s *ldapStub) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *ldapStub) handle(conn net.Conn) {
+	defer func() {
+		conn.Close()
+	}()
+
+	bound := false
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		msgID := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			dn := op.Children[1].Data.String()
+			password := op.Children[2].Data.String()
+			s.mu.Lock()
+			entry, ok := s.entries[dn]
+			s.mu.Unlock()
+			bound = ok && password != "" && entry.password == password
+			code := ldap.LDAPResultSuccess
+			if !bound {
+				code = ldap.LDAPResultInvalidCredentials
+			}
+			s.write(conn, msgID, ldap.ApplicationBindResponse, code)
+		case ldap.ApplicationExtendedRequest:
+			// the only supported extended operation is StartTLS
+			s.write(conn, msgID, ldap.ApplicationExtendedResponse, ldap.LDAPResultSuccess)
+			conn = tls.Server(conn, s.tlsConfig)
+		case ldap.ApplicationSearchRequest:
+			if !bound {
+				s.write(conn, msgID, ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights)
+				continue
+			}
+			baseDN := strings.ToLower(op.Children[0].Data.String())
+			filter := op.Children[6]
+			s.mu.Lock()
+			for dn, entry := range s.entries {
+				if strings.HasSuffix(strings.ToLower(dn), baseDN) && s.matches(filter, entry.attrs) {
+					s.writeEntry(conn, msgID, dn, entry.attrs)
+				}
+			}
+			s.mu.Unlock()
+			s.write(conn, msgID, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess)
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			s.t.Errorf("unexpected ldap request: %d", op.Tag)
+			return
+		}
+	}
+}
+
+func (s *ldapStub) matches(filter *ber.Packet, attrs map[string][]string) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !s.matches(child, attrs) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if s.matches(child, attrs) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterEqualityMatch:
+		want := filter.Children[1].Data.String()
+		for _, v := range ldapStubAttr(attrs, filter.Children[0].Data.String()) {
+			if strings.EqualFold(v, want) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterPresent:
+		return len(ldapStubAttr(attrs, filter.Data.String())) > 0
+	default:
+		s.t.Errorf("unexpected ldap filter: %d", filter.Tag)
+		return false
+	}
+}
+
+func ldapStubAttr(attrs map[string][]string, name string) []string {
+	for k, v := range attrs {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+func (s *ldapStub) write(conn net.Conn, msgID int64, tag ber.Tag, code int) {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	s.send(conn, msgID, op)
+}
+
+func (s *ldapStub) writeEntry(conn net.Conn, msgID int64, dn string, attrs map[string][]string) {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, "Object Name"))
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for name, values := range attrs {
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		vals := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, v := range values {
+			vals.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "Value"))
+		}
+		attr.AppendChild(vals)
+		attributes.AppendChild(attr)
+	}
+	op.AppendChild(attributes)
+	s.send(conn, msgID, op)
+}
+
+func (s *ldapStub) send(conn net.Conn, msgID int64, op *ber.Packet) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, msgID, "Message ID"))
+	packet.AppendChild(op)
+	_, err := conn.Write(packet.Bytes())
+	if err != nil {
+		s.t.Errorf("failed to write ldap response: %v", err)
+	}
+}
+
+const (
+	ldapTestAdmins    = "CN=RPort Admins,OU=Groups,DC=example,DC=com"
+	ldapTestOperators = "CN=RPort Operators,OU=Groups,DC=example,DC=com"
+)
+
+func newTestLDAP(t *testing.T, tlsConfig *tls.Config) (*ldapStub, LDAPConfig) {
+	stub := newLDAPStub(t, tlsConfig)
+	stub.Set("CN=rport,OU=Service,DC=example,DC=com", ldapStubEntry{password: "service-pwd"})
+	stub.Set("CN=Alice,OU=Users,DC=example,DC=com", ldapStubEntry{
+		password: "alice-pwd",
+		attrs: map[string][]string{
+			"objectClass":    {"user"},
+			"sAMAccountName": {"alice"},
+			"memberOf":       {"cn=rport admins,ou=groups,dc=example,dc=com", ldapTestOperators, "CN=Other,DC=example,DC=com"},
+		},
+	})
+	stub.Set("CN=Bob,OU=Users,DC=example,DC=com", ldapStubEntry{
+		password: "bob-pwd",
+		attrs: map[string][]string{
+			"objectClass":    {"user"},
+			"sAMAccountName": {"bob"},
+			"memberOf":       {ldapTestOperators},
+		},
+	})
+	stub.Set("CN=Carol,OU=Users,DC=example,DC=com", ldapStubEntry{
+		password: "carol-pwd",
+		attrs: map[string][]string{
+			"objectClass":    {"user"},
+			"sAMAccountName": {"carol"},
+			"memberOf":       {"CN=Other,DC=example,DC=com"},
+		},
+	})
+
+	return stub, LDAPConfig{
+		Enabled:           true,
+		URL:               stub.URL(),
+		Timeout:           5 * time.Second,
+		BindDN:            "CN=rport,OU=Service,DC=example,DC=com",
+		BindPassword:      "service-pwd",
+		BaseDN:            "OU=Users,DC=example,DC=com",
+		UserFilter:        "(objectClass=user)",
+		UsernameAttribute: "sAMAccountName",
+		GroupAttribute:    "memberOf",
+		CacheTTL:          time.Minute,
+		GroupMapping: []LDAPGroupMapping{
+			{LDAPGroup: ldapTestAdmins, UserGroups: []string{Administrators, "Operators"}},
+			{LDAPGroup: ldapTestOperators, UserGroups: []string{"Operators"}},
+		},
+	}
+}
+
+func TestLDAPProvider(t *testing.T) {
+	_, config := newTestLDAP(t, nil)
+	p, err := NewLDAPProvider(testLog, config)
+	require.NoError(t, err)
+	require.NoError(t, p.Check())
+
+	t.Run("get by username", func(t *testing.T) {
+		user, err := p.GetByUsername("alice")
+		require.NoError(t, err)
+		assert.Equal(t, &User{Username: "alice", Groups: []string{Administrators, "Operators"}}, user)
+
+		user, err = p.GetByUsername("bob")
+		require.NoError(t, err)
+		assert.Equal(t, &User{Username: "bob", Groups: []string{"Operators"}}, user)
+
+		user, err = p.GetByUsername("carol")
+		require.NoError(t, err)
+		assert.Nil(t, user, "members of unmapped groups only must not be users")
+
+		user, err = p.GetByUsername("unknown")
+		require.NoError(t, err)
+		assert.Nil(t, user)
+
+		user, err = p.GetByUsername("*")
+		require.NoError(t, err)
+		assert.Nil(t, user, "username must be escaped")
+	})
+
+	t.Run("get all", func(t *testing.T) {
+		all, err := p.GetAll()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []*User{
+			{Username: "alice", Groups: []string{Administrators, "Operators"}},
+			{Username: "bob", Groups: []string{"Operators"}},
+		}, all)
+	})
+
+	t.Run("verify password", func(t *testing.T) {
+		ok, err := p.VerifyPassword("alice", "alice-pwd")
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = p.VerifyPassword("alice", "bob-pwd")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = p.VerifyPassword("alice", "")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = p.VerifyPassword("carol", "carol-pwd")
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = p.VerifyPassword("unknown", "alice-pwd")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("read only", func(t *testing.T) {
+		assert.Error(t, p.Add(&User{Username: "dave"}))
+		assert.Error(t, p.Update(&User{Password: "new-pwd"}, "alice"))
+		assert.Error(t, p.Delete("alice"))
+		_, err := p.ListGroups()
+		assert.Error(t, err)
+	})
+}
+
+func TestLDAPProviderCache(t *testing.T) {
+	stub, config := newTestLDAP(t, nil)
+	p, err := NewLDAPProvider(testLog, config)
+	require.NoError(t, err)
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	p.now = func() time.Time {
+		return now
+	}
+
+	user, err := p.GetByUsername("bob")
+	require.NoError(t, err)
+	require.NotNil(t, user)
+
+	// bob is removed from the mapped group
+	stub.Set("CN=Bob,OU=Users,DC=example,DC=com", ldapStubEntry{
+		password: "bob-pwd",
+		attrs: map[string][]string{
+			"objectClass":    {"user"},
+			"sAMAccountName": {"bob"},
+		},
+	})
+
+	now = now.Add(config.CacheTTL - time.Second)
+	user, err = p.GetByUsername("bob")
+	require.NoError(t, err)
+	assert.NotNil(t, user, "user is expected to be cached")
+
+	now = now.Add(2 * time.Second)
+	user, err = p.GetByUsername("bob")
+	require.NoError(t, err)
+	assert.Nil(t, user, "cache is expected to be expired")
+
+	all, err := p.cache.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, all, 0)
+}
+
+func TestLDAPProviderCanonicalUsername(t *testing.T) {
+	stub, config := newTestLDAP(t, nil)
+	p, err := NewLDAPProvider(testLog, config)
+	require.NoError(t, err)
+
+	user, err := p.GetByUsername("Alice")
+	require.NoError(t, err)
+	assert.Nil(t, user, "usernames spelled differently than in the directory must be rejected")
+
+	ok, err := p.VerifyPassword("Alice", "alice-pwd")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	user, err = p.GetByUsername("alice")
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	assert.Equal(t, "alice", user.Username)
+
+	// other spellings are answered from the cache without querying the directory
+	stub.listener.Close()
+	user, err = p.GetByUsername("ALICE")
+	require.NoError(t, err)
+	assert.Nil(t, user)
+	user, err = p.GetByUsername("alice")
+	require.NoError(t, err)
+	assert.NotNil(t, user)
+}
+
+func TestLDAPProviderStartTLS(t *testing.T) {
+	cert, caFile := newLDAPTestCert(t)
+	_, config := newTestLDAP(t, &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12})
+	config.StartTLS = true
+	config.CACertFile = caFile
+
+	p, err := NewLDAPProvider(testLog, config)
+	require.NoError(t, err)
+
+	ok, err := p.VerifyPassword("alice", "alice-pwd")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	config.CACertFile = ""
+	p, err = NewLDAPProvider(testLog, config)
+	require.NoError(t, err)
+
+	_, err = p.VerifyPassword("alice", "alice-pwd")
+	assert.ErrorContains(t, err, "failed to start tls")
+}
+
+func TestLDAPProviderInvalidServiceAccount(t *testing.T) {
+	_, config := newTestLDAP(t, nil)
+	config.BindPassword = "wrong"
+	p, err := NewLDAPProvider(testLog, config)
+	require.NoError(t, err)
+
+	assert.ErrorContains(t, p.Check(), "failed to bind to ldap server")
+
+	_, err = p.GetByUsername("alice")
+	assert.Error(t, err)
+}
+
+// newLDAPTestCert returns a self-signed certificate for 127.0.0.1 and the path of its PEM file
+func newLDAPTestCert(t *testing.T) (tls.Certificate, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ldap-stub"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, caFile
+}
@@ -16,6 +16,7 @@ const (
 	PermissionMonitoring = "monitoring"
 	PermissionUploads    = "uploads"
 	PermissionsAuditLog  = "auditlog"
+	PermissionTerminal   = "terminal"
 )
 
 var AllPermissions = []string{
@@ -27,6 +28,27 @@ var AllPermissions = []string{
 	PermissionMonitoring,
 	PermissionUploads,
 	PermissionsAuditLog,
+	PermissionTerminal,
+}
+
+// ClientPermissions are the permissions that can be granted for the clients of a client group only, the others are
+// always granted for the whole server
+var ClientPermissions = []string{
+	PermissionTunnels,
+	PermissionScripts,
+	PermissionCommands,
+	PermissionUploads,
+	PermissionMonitoring,
+	PermissionTerminal,
+}
+
+func IsClientPermission(p string) bool {
+	for _, permission := range ClientPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
 }
 
 type Permissions struct {
@@ -122,3 +144,47 @@ func (permissions *Permissions) UnmarshalJSON(data []byte) error {
 	permissions.data = result
 	return nil
 }
+
+// ClientGroupPermissions are permissions granted for the clients of a client group only, keyed by client group id
+type ClientGroupPermissions map[string]Permissions
+
+func (cgp *ClientGroupPermissions) Scan(value interface{}) error {
+	if cgp == nil {
+		return errors.New("'client_group_permissions' cannot be nil")
+	}
+
+	var data []byte
+	switch d := value.(type) {
+	case nil:
+		return nil
+	case string:
+		data = []byte(d)
+	case []uint8:
+		data = d
+	default:
+		return fmt.Errorf("failed to decode json column: unknown comlumn type %T", value)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(data, (*map[string]Permissions)(cgp)); err != nil {
+		return fmt.Errorf("failed to decode 'client_group_permissions' field: %v", err)
+	}
+	return nil
+}
+
+func (cgp ClientGroupPermissions) Value() (driver.Value, error) {
+	b, err := json.Marshal(cgp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode 'client_group_permissions' field: %v", err)
+	}
+	return string(b), nil
+}
+
+func (cgp ClientGroupPermissions) MarshalJSON() ([]byte, error) {
+	if cgp == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]Permissions(cgp))
+}
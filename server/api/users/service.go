@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
 	zxcvbn "github.com/trustelem/zxcvbn"
@@ -28,6 +29,11 @@ type Provider interface {
 	Delete(usernameToDelete string) error
 }
 
+// PasswordVerifier is implemented by providers that verify passwords themselves instead of returning password hashes
+type PasswordVerifier interface {
+	VerifyPassword(username, password string) (bool, error)
+}
+
 type APIService struct {
 	DeliverySrv            message.Service
 	Provider               Provider
@@ -54,6 +60,12 @@ func (as APIService) GetProviderType() enums.ProviderSource {
 	return as.Provider.Type()
 }
 
+// PasswordVerifier returns the provider if it verifies passwords itself, nil otherwise
+func (as APIService) PasswordVerifier() PasswordVerifier {
+	verifier, _ := as.Provider.(PasswordVerifier)
+	return verifier
+}
+
 func (as *APIService) GetAll() ([]*User, error) {
 	return as.Provider.GetAll()
 }
@@ -71,6 +83,17 @@ func (as *APIService) GetGroup(name string) (Group, error) {
 }
 
 func (as *APIService) UpdateGroup(name string, g Group) (Group, error) {
+	for clientGroupID, permissions := range g.ClientGroupPermissions {
+		for _, permission := range AllPermissions {
+			if permissions.Has(permission) && !IsClientPermission(permission) {
+				return Group{}, errors2.APIError{
+					Message:    fmt.Sprintf("Permission %q can't be granted for client group %q, it applies to the whole server.", permission, clientGroupID),
+					HTTPStatus: http.StatusBadRequest,
+				}
+			}
+		}
+	}
+
 	err := as.Provider.UpdateGroup(name, g)
 	if err != nil {
 		return Group{}, err
@@ -82,6 +105,7 @@ func (as *APIService) DeleteGroup(name string) error {
 	return as.Provider.DeleteGroup(name)
 }
 
+// CheckPermission returns nil if the user has the permission, either for all clients or for some client groups
 func (as *APIService) CheckPermission(user *User, permission string) error {
 	for _, groupName := range user.Groups {
 		group, err := as.Provider.GetGroup(groupName)
@@ -91,6 +115,11 @@ func (as *APIService) CheckPermission(user *User, permission string) error {
 		if group.Permissions.Has(permission) {
 			return nil
 		}
+		for _, permissions := range group.ClientGroupPermissions {
+			if permissions.Has(permission) && IsClientPermission(permission) {
+				return nil
+			}
+		}
 	}
 	return errors2.APIError{
 		Message:    fmt.Sprintf("user does not have %q permission", permission),
@@ -114,10 +143,58 @@ func (as *APIService) GetEffectiveUserPermissions(user *User) (map[string]bool,
 				permissions[permission] = true
 			}
 		}
+		for _, clientGroupPermissions := range group.ClientGroupPermissions {
+			for _, permission := range ClientPermissions {
+				if clientGroupPermissions.Has(permission) {
+					permissions[permission] = true
+				}
+			}
+		}
 	}
 	return permissions, nil
 }
 
+// GetPermissionScopes returns the ids of the client groups the client permissions of the user are granted for, the
+// list is empty if the user doesn't have the permission at all. Permissions granted for all clients are omitted.
+func (as *APIService) GetPermissionScopes(user *User) (map[string][]string, error) {
+	if !as.SupportsGroupPermissions() {
+		return nil, nil
+	}
+
+	granted := make(map[string]bool)
+	scopes := make(map[string][]string)
+	for _, permission := range ClientPermissions {
+		scopes[permission] = []string{}
+	}
+	for _, groupName := range user.Groups {
+		group, err := as.Provider.GetGroup(groupName)
+		if err != nil {
+			return nil, err
+		}
+		for _, permission := range ClientPermissions {
+			if group.Permissions.Has(permission) {
+				granted[permission] = true
+			}
+		}
+		for clientGroupID, permissions := range group.ClientGroupPermissions {
+			for _, permission := range ClientPermissions {
+				if permissions.Has(permission) && !containsString(scopes[permission], clientGroupID) {
+					scopes[permission] = append(scopes[permission], clientGroupID)
+				}
+			}
+		}
+	}
+
+	for permission, clientGroupIDs := range scopes {
+		if granted[permission] {
+			delete(scopes, permission)
+			continue
+		}
+		sort.Strings(clientGroupIDs)
+	}
+	return scopes, nil
+}
+
 func (as *APIService) ExistGroups(groups []string) error {
 	existingGroups, err := as.ListGroups()
 	if err != nil {
@@ -24,6 +24,8 @@ type ProviderMock struct {
 	ErrorToGiveOnDelete error
 	UsernameToUpdate    string
 	UsernameToDelete    string
+
+	GroupPermissionsSupported bool
 }
 
 func (dpm *ProviderMock) GetAll() ([]*User, error) {
@@ -89,7 +91,7 @@ func (dpm *ProviderMock) Delete(usernameToDelete string) error {
 }
 
 func (dpm *ProviderMock) SupportsGroupPermissions() bool {
-	return false
+	return dpm.GroupPermissionsSupported
 }
 
 func (dpm ProviderMock) Type() enums.ProviderSource {
@@ -522,6 +524,14 @@ func TestCheckPermission(t *testing.T) {
 			},
 			Expected: false,
 		},
+		{
+			Name:       "has permission for client group",
+			Permission: PermissionTunnels,
+			User: &User{
+				Groups: []string{"group-client-group"},
+			},
+			Expected: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -529,7 +539,9 @@ func TestCheckPermission(t *testing.T) {
 		t.Run(tc.Name, func(t *testing.T) {
 			t.Parallel()
 
-			givenGroups := []Group{NewGroup("group-no-permissions"), NewGroup("group-commands", PermissionCommands), AdministratorsGroup}
+			clientGroupGroup := NewGroup("group-client-group")
+			clientGroupGroup.ClientGroupPermissions = ClientGroupPermissions{"db": NewPermissions(PermissionTunnels)}
+			givenGroups := []Group{NewGroup("group-no-permissions"), NewGroup("group-commands", PermissionCommands), AdministratorsGroup, clientGroupGroup}
 			db := &ProviderMock{
 				GroupsToGive: givenGroups,
 			}
@@ -546,3 +558,59 @@ func TestCheckPermission(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPermissionScopes(t *testing.T) {
+	webTeam := NewGroup("web-team", PermissionVault)
+	webTeam.ClientGroupPermissions = ClientGroupPermissions{
+		"web": NewPermissions(PermissionCommands, PermissionTunnels),
+		"db":  NewPermissions(PermissionTunnels),
+	}
+	dbTeam := NewGroup("db-team", PermissionTunnels)
+	dbTeam.ClientGroupPermissions = ClientGroupPermissions{
+		"db": NewPermissions(PermissionCommands),
+	}
+	service := APIService{
+		Provider: &ProviderMock{
+			GroupsToGive:              []Group{webTeam, dbTeam, AdministratorsGroup},
+			GroupPermissionsSupported: true,
+		},
+	}
+
+	scopes, err := service.GetPermissionScopes(&User{Groups: []string{"web-team"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		PermissionTunnels:    {"db", "web"},
+		PermissionScripts:    {},
+		PermissionCommands:   {"web"},
+		PermissionUploads:    {},
+		PermissionMonitoring: {},
+		PermissionTerminal:   {},
+	}, scopes)
+
+	scopes, err = service.GetPermissionScopes(&User{Groups: []string{"web-team", "db-team"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db", "web"}, scopes[PermissionCommands])
+	_, limited := scopes[PermissionTunnels]
+	assert.False(t, limited, "tunnels are granted for all clients by db-team")
+
+	scopes, err = service.GetPermissionScopes(&User{Groups: []string{Administrators}})
+	require.NoError(t, err)
+	assert.Empty(t, scopes)
+
+	effective, err := service.GetEffectiveUserPermissions(&User{Groups: []string{"web-team"}})
+	require.NoError(t, err)
+	assert.True(t, effective[PermissionCommands])
+	assert.True(t, effective[PermissionVault])
+	assert.False(t, effective[PermissionScripts])
+}
+
+func TestUpdateGroupWithServerPermissionForClientGroup(t *testing.T) {
+	service := APIService{
+		Provider: &ProviderMock{GroupPermissionsSupported: true},
+	}
+	group := NewGroup("web-team")
+	group.ClientGroupPermissions = ClientGroupPermissions{"web": NewPermissions(PermissionVault)}
+
+	_, err := service.UpdateGroup("web-team", group)
+	assert.EqualError(t, err, `Permission "vault" can't be granted for client group "web", it applies to the whole server.`)
+}
@@ -9,12 +9,29 @@ type User struct {
 	TwoFASendTo     string   `json:"two_fa_send_to" db:"two_fa_send_to"`
 	Token           *string  `json:"token,omitempty" db:"token"`
 	TotP            string   `json:"totp_secret,omitempty" db:"totp_secret"`
+	// ClientGroupIDs limits the access to the clients of the given client groups if not empty. It's not stored but
+	// taken from the API key the current request is authenticated with.
+	ClientGroupIDs []string `json:"-" db:"-"`
+	// PermissionScopes holds the ids of the client groups a permission of the user is granted for, if it's not granted
+	// for all clients. It's not stored but taken from the client group permissions of the user groups.
+	PermissionScopes map[string][]string `json:"-" db:"-"`
 }
 
 func (u User) GetGroups() []string {
 	return u.Groups
 }
 
+func (u User) GetClientGroupIDs() []string {
+	return u.ClientGroupIDs
+}
+
+// GetPermissionScope returns the ids of the client groups the given permission is limited to, limited is false if
+// the permission is not limited to some client groups
+func (u User) GetPermissionScope(permission string) (clientGroupIDs []string, limited bool) {
+	clientGroupIDs, limited = u.PermissionScopes[permission]
+	return clientGroupIDs, limited
+}
+
 func (u User) GetUsername() string {
 	return u.Username
 }
@@ -28,6 +45,11 @@ func (u User) IsAdmin() bool {
 	return false
 }
 
+// HasAccessToAllClients returns true for admins whose access is not limited to some client groups
+func (u User) HasAccessToAllClients() bool {
+	return u.IsAdmin() && len(u.ClientGroupIDs) == 0
+}
+
 func PasswordExpired(f bool) *bool {
 	return &f
 }
@@ -0,0 +1,134 @@
+package chserver
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cloudradar-monitoring/rport/server/alerts"
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/auditlog"
+	"github.com/cloudradar-monitoring/rport/server/routes"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+// handleListAlerts handles GET /alerts
+func (al *APIListener) handleListAlerts(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	options := query.GetListOptions(req)
+
+	// non-admin users see only alerts of the clients they have access to
+	hasClients, err := al.filterByUserClients(ctx, curUser, users.PermissionMonitoring, options)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if !hasClients {
+		al.writeJSONResponse(w, http.StatusOK, &api.SuccessPayload{
+			Data: []*alerts.Alert{},
+			Meta: api.NewMeta(0),
+		})
+		return
+	}
+
+	result, err := al.alertsService.ListAlerts(ctx, options)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// handleListAlertRules handles GET /alerts/rules
+func (al *APIListener) handleListAlertRules(w http.ResponseWriter, req *http.Request) {
+	rules := al.alertsService.ListRules()
+
+	al.writeJSONResponse(w, http.StatusOK, &api.SuccessPayload{
+		Data: rules,
+		Meta: api.NewMeta(len(rules)),
+	})
+}
+
+// handlePostAlertRule handles POST /alerts/rules
+func (al *APIListener) handlePostAlertRule(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	rule := &alerts.Rule{}
+	err = parseRequestBody(req.Body, rule)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	result, err := al.alertsService.CreateRule(ctx, rule, curUser.Username)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationAlertRule, auditlog.ActionCreate).
+		WithHTTPRequest(req).
+		WithRequest(rule).
+		WithID(result.ID).
+		Save()
+
+	al.writeJSONResponse(w, http.StatusCreated, api.NewSuccessPayload(result))
+}
+
+// handlePutAlertRule handles PUT /alerts/rules/{rule_id}
+func (al *APIListener) handlePutAlertRule(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	ruleID := mux.Vars(req)[routes.ParamAlertRuleID]
+
+	rule := &alerts.Rule{}
+	err := parseRequestBody(req.Body, rule)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	result, err := al.alertsService.UpdateRule(ctx, ruleID, rule)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationAlertRule, auditlog.ActionUpdate).
+		WithHTTPRequest(req).
+		WithRequest(rule).
+		WithID(ruleID).
+		Save()
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(result))
+}
+
+// handleDeleteAlertRule handles DELETE /alerts/rules/{rule_id}
+func (al *APIListener) handleDeleteAlertRule(w http.ResponseWriter, req *http.Request) {
+	ruleID := mux.Vars(req)[routes.ParamAlertRuleID]
+
+	err := al.alertsService.DeleteRule(req.Context(), ruleID)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationAlertRule, auditlog.ActionDelete).
+		WithHTTPRequest(req).
+		WithID(ruleID).
+		Save()
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,205 @@
+package chserver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/apikeys"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/auditlog"
+	"github.com/cloudradar-monitoring/rport/server/routes"
+	"github.com/cloudradar-monitoring/rport/share/random"
+)
+
+type postAPIKeyRequest struct {
+	Name           string            `json:"name"`
+	ExpiresAt      *time.Time        `json:"expires_at"`
+	Permissions    users.Permissions `json:"permissions"`
+	Admin          bool              `json:"admin"`
+	ClientGroupIDs []string          `json:"client_group_ids"`
+	AllowedIPs     []string          `json:"allowed_ips"`
+}
+
+type postAPIKeyResponse struct {
+	*apikeys.APIKey
+	// Key is the token to use as bearer auth, it's returned only once
+	Key string `json:"key"`
+}
+
+// handleGetAPIKeys handles GET /me/api-keys
+func (al *APIListener) handleGetAPIKeys(w http.ResponseWriter, req *http.Request) {
+	curUser, err := al.getUserModelForAuth(req.Context())
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	keys, err := al.apiKeys.ListByUser(req.Context(), curUser.Username)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(keys))
+}
+
+// handlePostAPIKey handles POST /me/api-keys
+func (al *APIListener) handlePostAPIKey(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	var reqBody postAPIKeyRequest
+	err = parseRequestBody(req.Body, &reqBody)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	err = al.validateAPIKeyRequest(req, curUser, &reqBody)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	id, err := random.UUID4()
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	token, err := apikeys.NewToken()
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	apiKey := &apikeys.APIKey{
+		ID:             id,
+		Username:       curUser.Username,
+		Name:           reqBody.Name,
+		KeyHash:        apikeys.Hash(token),
+		Permissions:    reqBody.Permissions,
+		Admin:          reqBody.Admin,
+		ClientGroupIDs: reqBody.ClientGroupIDs,
+		AllowedIPs:     reqBody.AllowedIPs,
+		CreatedAt:      time.Now().UTC(),
+		ExpiresAt:      reqBody.ExpiresAt,
+	}
+	err = al.apiKeys.Create(ctx, apiKey)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationAuthUserMeAPIKey, auditlog.ActionCreate).
+		WithHTTPRequest(req).
+		WithID(apiKey.ID).
+		WithRequest(reqBody).
+		Save()
+
+	al.writeJSONResponse(w, http.StatusCreated, api.NewSuccessPayload(postAPIKeyResponse{
+		APIKey: apiKey,
+		Key:    token,
+	}))
+}
+
+func (al *APIListener) validateAPIKeyRequest(req *http.Request, curUser *users.User, reqBody *postAPIKeyRequest) error {
+	reqBody.Name = strings.TrimSpace(reqBody.Name)
+	if reqBody.Name == "" {
+		return errors2.APIError{
+			Message:    "Missing name.",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	if reqBody.ExpiresAt != nil && !reqBody.ExpiresAt.After(time.Now()) {
+		return errors2.APIError{
+			Message:    "Expiry date must be in the future.",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	if reqBody.Admin && !curUser.IsAdmin() {
+		return errors2.APIError{
+			Message:    fmt.Sprintf("Admin access can't be granted, the current user doesn't belong to %s group.", users.Administrators),
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	if al.userService.SupportsGroupPermissions() {
+		userPermissions, err := al.userService.GetEffectiveUserPermissions(curUser)
+		if err != nil {
+			return err
+		}
+		for _, permission := range users.AllPermissions {
+			if reqBody.Permissions.Has(permission) && !userPermissions[permission] {
+				return errors2.APIError{
+					Message:    fmt.Sprintf("Permission %q can't be granted, the current user doesn't have it.", permission),
+					HTTPStatus: http.StatusBadRequest,
+				}
+			}
+		}
+	}
+
+	for _, groupID := range reqBody.ClientGroupIDs {
+		group, err := al.clientGroupProvider.Get(req.Context(), groupID)
+		if err != nil {
+			return err
+		}
+		if group == nil {
+			return errors2.APIError{
+				Message:    fmt.Sprintf("Client group %q not found.", groupID),
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+	}
+
+	for _, ip := range reqBody.AllowedIPs {
+		if _, err := apikeys.ParseIPNet(ip); err != nil {
+			return errors2.APIError{
+				Message:    fmt.Sprintf("Invalid allowed IP %q.", ip),
+				Err:        err,
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+	}
+
+	return nil
+}
+
+// handleDeleteAPIKey handles DELETE /me/api-keys/{api_key_id}
+func (al *APIListener) handleDeleteAPIKey(w http.ResponseWriter, req *http.Request) {
+	curUser, err := al.getUserModelForAuth(req.Context())
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	id := mux.Vars(req)[routes.ParamAPIKeyID]
+	deleted, err := al.apiKeys.Delete(req.Context(), curUser.Username, id)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if !deleted {
+		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("API key with id %q not found.", id))
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationAuthUserMeAPIKey, auditlog.ActionDelete).
+		WithHTTPRequest(req).
+		WithID(id).
+		Save()
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,255 @@
+package chserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/server/api/apikeys"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/cgroups"
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/share/security"
+)
+
+func TestAPIKeys(t *testing.T) {
+	ctx := context.Background()
+	admin := &users.User{
+		Username: "admin",
+		Password: "$2y$05$ep2DdPDeLDDhwRrED9q/vuVEzRpZtB5WHCFT7YbcmH9r9oNmlsZOm",
+		Groups:   []string{users.Administrators},
+	}
+	c1 := clients.New(t).ID("client-1").Build()
+	c2 := clients.New(t).ID("client-2").Build()
+
+	apiKeysProvider, err := apikeys.NewSqliteProvider(":memory:", database.Options{SQLite: DataSourceOptions})
+	require.NoError(t, err)
+	defer apiKeysProvider.Close()
+
+	gp := makeGroupsProvider(t, DataSourceOptions)
+	defer gp.Close()
+	require.NoError(t, gp.Create(ctx, &cgroups.ClientGroup{
+		ID:     "group-1",
+		Params: &cgroups.ClientParams{ClientID: &cgroups.ParamValues{"client-1"}},
+	}))
+
+	al := APIListener{
+		Logger:      testLog,
+		apiSessions: newEmptyAPISessionCache(t),
+		apiKeys:     apiKeysProvider,
+		bannedUsers: security.NewBanList(0),
+		userService: users.NewAPIService(users.NewStaticProvider([]*users.User{admin}), false, 0, -1),
+		Server: &Server{
+			clientService:       NewClientService(nil, nil, clients.NewClientRepository([]*clients.Client{c1, c2}, &hour, testLog), testLog),
+			clientGroupProvider: gp,
+			config: &chconfig.Config{
+				API:    chconfig.APIConfig{JWTSecret: "secret"},
+				Server: chconfig.ServerConfig{MaxRequestBytes: 1024 * 1024},
+			},
+		},
+	}
+	al.initRouter()
+
+	basicAuth := func(req *http.Request) {
+		req.SetBasicAuth("admin", "pwd")
+	}
+	bearerAuth := func(token string) func(req *http.Request) {
+		return func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	do := func(method, url, body string, auth func(req *http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		auth(req)
+		w := httptest.NewRecorder()
+		al.router.ServeHTTP(w, req)
+		return w
+	}
+	createKey := func(body string) (id, key string) {
+		w := do(http.MethodPost, "/api/v1/me/api-keys", body, basicAuth)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+		var resp struct {
+			Data struct {
+				ID  string `json:"id"`
+				Key string `json:"key"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data.ID, resp.Data.Key
+	}
+
+	t.Run("invalid requests", func(t *testing.T) {
+		testCases := []struct {
+			name      string
+			body      string
+			wantError string
+		}{
+			{
+				name:      "missing name",
+				body:      `{"name": " "}`,
+				wantError: "Missing name.",
+			},
+			{
+				name:      "expired",
+				body:      `{"name": "key", "expires_at": "2020-01-01T00:00:00Z"}`,
+				wantError: "Expiry date must be in the future.",
+			},
+			{
+				name:      "unknown permission",
+				body:      `{"name": "key", "permissions": {"unknown": true}}`,
+				wantError: "Invalid JSON data.",
+			},
+			{
+				name:      "unknown client group",
+				body:      `{"name": "key", "client_group_ids": ["unknown"]}`,
+				wantError: `Client group \"unknown\" not found.`,
+			},
+			{
+				name:      "invalid ip",
+				body:      `{"name": "key", "allowed_ips": ["192.0.2.300"]}`,
+				wantError: `Invalid allowed IP \"192.0.2.300\".`,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				w := do(http.MethodPost, "/api/v1/me/api-keys", tc.body, basicAuth)
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Contains(t, w.Body.String(), tc.wantError)
+			})
+		}
+	})
+
+	id, key := createKey(`{"name": "ci", "permissions": {"commands": true}, "client_group_ids": ["group-1"], "allowed_ips": ["192.0.2.0/24"]}`)
+
+	t.Run("independent of jwt secret", func(t *testing.T) {
+		assert.True(t, strings.HasPrefix(key, apikeys.TokenPrefix))
+
+		al.config.API.JWTSecret = "new-secret"
+		defer func() { al.config.API.JWTSecret = "secret" }()
+		w := do(http.MethodGet, "/api/v1/clients", "", bearerAuth(key))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("restricted to client group", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/v1/clients?fields[clients]=id", "", bearerAuth(key))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"data": [{"id": "client-1"}], "meta": {"count": 1}}`, w.Body.String())
+
+		w = do(http.MethodGet, "/api/v1/clients/client-2", "", bearerAuth(key))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("restricted to permissions", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/v1/tunnels", "", bearerAuth(key))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), `API key \"ci\" has no tunnels permission`)
+
+		w = do(http.MethodGet, "/api/v1/tunnels", "", basicAuth)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("not allowed to manage credentials", func(t *testing.T) {
+		testCases := []struct {
+			method string
+			url    string
+			body   string
+		}{
+			{method: http.MethodGet, url: "/api/v1/me/api-keys"},
+			{method: http.MethodPost, url: "/api/v1/me/api-keys", body: `{"name": "other"}`},
+			{method: http.MethodDelete, url: "/api/v1/me/api-keys/" + id},
+			{method: http.MethodPost, url: "/api/v1/me/token"},
+			{method: http.MethodDelete, url: "/api/v1/me/token"},
+			{method: http.MethodPut, url: "/api/v1/me", body: `{"password": "new-password"}`},
+		}
+		for _, tc := range testCases {
+			w := do(tc.method, tc.url, tc.body, bearerAuth(key))
+			assert.Equal(t, http.StatusForbidden, w.Code, tc.method+" "+tc.url)
+			assert.Contains(t, w.Body.String(), `API key \"ci\" can't be used to manage credentials`)
+		}
+	})
+
+	t.Run("restricted admin access", func(t *testing.T) {
+		_, adminKey := createKey(`{"name": "admin", "admin": true, "permissions": {"commands": true}}`)
+
+		for _, url := range []string{"/api/v1/users", "/api/v1/user-groups", "/api/v1/clients-auth"} {
+			w := do(http.MethodGet, url, "", bearerAuth(key))
+			assert.Equal(t, http.StatusForbidden, w.Code, url)
+			assert.Contains(t, w.Body.String(), `API key \"ci\" has no admin access`)
+		}
+
+		// the static user provider doesn't support managing users, but the admin check passed
+		w := do(http.MethodGet, "/api/v1/users", "", bearerAuth(adminKey))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "server runs on a static user-password pair")
+	})
+
+	t.Run("client groups require a client permission", func(t *testing.T) {
+		_, auditKey := createKey(`{"name": "audit", "permissions": {"auditlog": true}}`)
+
+		w := do(http.MethodGet, "/api/v1/client-groups", "", bearerAuth(auditKey))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		w = do(http.MethodGet, "/api/v1/client-groups", "", bearerAuth(key))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("restricted to ips", func(t *testing.T) {
+		_, otherKey := createKey(`{"name": "other", "allowed_ips": ["198.51.100.1"]}`)
+
+		w := do(http.MethodGet, "/api/v1/clients", "", bearerAuth(otherKey))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		w := do(http.MethodGet, "/api/v1/me/api-keys", "", basicAuth)
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Data []*apikeys.APIKey `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Len(t, resp.Data, 4)
+		assert.Equal(t, id, resp.Data[0].ID)
+		assert.Equal(t, "ci", resp.Data[0].Name)
+		assert.True(t, resp.Data[0].Permissions.Has(users.PermissionCommands))
+		assert.NotNil(t, resp.Data[0].LastUsedAt)
+		assert.NotContains(t, w.Body.String(), key)
+		assert.NotContains(t, w.Body.String(), apikeys.Hash(key))
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		w := do(http.MethodDelete, "/api/v1/me/api-keys/"+id, "", basicAuth)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = do(http.MethodGet, "/api/v1/clients", "", bearerAuth(key))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w = do(http.MethodDelete, "/api/v1/me/api-keys/"+id, "", basicAuth)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expiredKey, err := apikeys.NewToken()
+		require.NoError(t, err)
+		expiredAt := time.Now().Add(-time.Minute)
+		require.NoError(t, apiKeysProvider.Create(ctx, &apikeys.APIKey{
+			ID:        "expired",
+			Username:  "admin",
+			Name:      "expired",
+			KeyHash:   apikeys.Hash(expiredKey),
+			CreatedAt: time.Now().Add(-time.Hour),
+			ExpiresAt: &expiredAt,
+		}))
+
+		w := do(http.MethodGet, "/api/v1/clients", "", bearerAuth(expiredKey))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,317 @@
+package chserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/apikeys"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/server/api/jobs/schedule"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/approvals"
+	"github.com/cloudradar-monitoring/rport/server/auditlog"
+	"github.com/cloudradar-monitoring/rport/server/cgroups"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/routes"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+type approvalDecisionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// requestApprovalIfRequired stores a job or schedule request as pending if one of the clients belongs to a client
+// group that requires approval or such a group is targeted. It returns nil if approvals are disabled or not required,
+// the job can be started or the schedule saved right away then.
+func (al *APIListener) requestApprovalIfRequired(
+	req *http.Request,
+	request *approvals.Request,
+	orderedClients []*clients.Client,
+	username string,
+) (*approvals.Approval, error) {
+	if al.approvalsService == nil {
+		return nil, nil
+	}
+
+	clientGroups, err := al.clientGroupProvider.GetAll(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	targetedGroupIDs := request.GroupIDs
+	if request.Schedule != nil {
+		targetedGroupIDs = request.Schedule.GroupIDs
+	}
+	clientGroupIDs := clientGroupsRequiringApproval(orderedClients, targetedGroupIDs, clientGroups)
+	if len(clientGroupIDs) == 0 {
+		return nil, nil
+	}
+
+	if request.Schedule == nil {
+		// the job must not run on other clients than the ones the approver agreed to
+		request.ClientIDs = make([]string, 0, len(orderedClients))
+		for _, client := range orderedClients {
+			request.ClientIDs = append(request.ClientIDs, client.ID)
+		}
+	}
+
+	approval, err := al.approvalsService.Create(req.Context(), request, clientGroupIDs, username)
+	if err != nil {
+		return nil, err
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationApproval, auditlog.ActionCreate).
+		WithHTTPRequest(req).
+		WithID(approval.ID).
+		WithRequest(request).
+		SaveForMultipleClients(orderedClients)
+
+	al.Debugf("Approval[id=%q] created for a job on clients of client groups %s.", approval.ID, clientGroupIDs)
+
+	return approval, nil
+}
+
+// clientGroupsRequiringApproval returns the ids of the client groups requiring approval one of the given clients
+// belongs to or that are targeted by id
+func clientGroupsRequiringApproval(orderedClients []*clients.Client, groupIDs []string, clientGroups []*cgroups.ClientGroup) []string {
+	var result []string
+	for _, group := range clientGroups {
+		if group.RequireApproval && isGroupTargeted(group, orderedClients, groupIDs) {
+			result = append(result, group.ID)
+		}
+	}
+	return result
+}
+
+// isGroupTargeted returns true if a client group is targeted by id or one of the given clients belongs to it
+func isGroupTargeted(group *cgroups.ClientGroup, orderedClients []*clients.Client, groupIDs []string) bool {
+	for _, id := range groupIDs {
+		if id == group.ID {
+			return true
+		}
+	}
+	for _, client := range orderedClients {
+		if client.BelongsTo(group) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkApprover returns nil if a given user is allowed to decide about the requests of other users.
+func (al *APIListener) checkApprover(ctx context.Context, user *users.User) error {
+	permission := al.config.Approvals.ApproverPermission
+	if permission == "" {
+		return al.checkAdminAccess(ctx, user)
+	}
+
+	if apiKey := apikeys.FromContext(ctx); apiKey != nil && !apiKey.Permissions.Has(permission) {
+		return errors2.APIError{
+			Message:    fmt.Sprintf("API key %q has no %s permission", apiKey.Name, permission),
+			HTTPStatus: http.StatusForbidden,
+		}
+	}
+
+	if user.IsAdmin() || !al.userService.SupportsGroupPermissions() {
+		return nil
+	}
+
+	return al.userService.CheckPermission(user, permission)
+}
+
+// approvalsPermissions returns the permissions that allow to access approvals, the permissions needed to request
+// a job that requires approval and the permission to approve
+func (al *APIListener) approvalsPermissions() []string {
+	permissions := []string{users.PermissionCommands, users.PermissionScripts}
+	if p := al.config.Approvals.ApproverPermission; p != "" && p != users.PermissionCommands && p != users.PermissionScripts {
+		permissions = append(permissions, p)
+	}
+	return permissions
+}
+
+// runApproval starts the job or saves the schedule of an approved request on behalf of the requesting user. The access
+// of the user is checked again, it might have been revoked while the request was pending.
+func (al *APIListener) runApproval(ctx context.Context, approval *approvals.Approval) (string, error) {
+	requester, err := al.getUserModelByUsername(approval.RequestedBy, nil)
+	if err != nil {
+		return "", err
+	}
+	if requester == nil {
+		return "", fmt.Errorf("user %q not found", approval.RequestedBy)
+	}
+
+	if approval.Request.Schedule != nil {
+		return al.runScheduleApproval(ctx, approval, requester)
+	}
+
+	orderedClients, _, err := al.getOrderedClients(ctx, approval.Request.ClientIDs, nil, true /* allowDisconnected */)
+	if err != nil {
+		return "", err
+	}
+	permission := users.PermissionCommands
+	if approval.Request.IsScript {
+		permission = users.PermissionScripts
+	}
+	if err := al.checkRequesterAccess(ctx, requester, orderedClients, permission); err != nil {
+		return "", err
+	}
+
+	multiJobRequest := approval.Request.MultiJobRequest(approval.RequestedBy)
+	multiJobRequest.OrderedClients = orderedClients
+	multiJob, err := al.StartMultiClientJob(ctx, multiJobRequest)
+	if err != nil {
+		return "", err
+	}
+
+	al.Debugf("Multi-client Job[id=%q] created for Approval[id=%q].", multiJob.JID, approval.ID)
+
+	return multiJob.JID, nil
+}
+
+func (al *APIListener) runScheduleApproval(ctx context.Context, approval *approvals.Approval, requester *users.User) (string, error) {
+	s := approval.Request.Schedule
+	orderedClients, _, err := al.getOrderedClientsWithValidation(ctx, s)
+	if err != nil {
+		return "", err
+	}
+	if err := al.checkRequesterAccess(ctx, requester, orderedClients, schedulePermission(s)); err != nil {
+		return "", err
+	}
+
+	var saved *schedule.Schedule
+	if s.ID == "" {
+		saved, err = al.scheduleManager.Create(ctx, s, approval.RequestedBy)
+	} else {
+		saved, err = al.scheduleManager.Update(ctx, s.ID, s)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	al.Debugf("Schedule[id=%q] saved for Approval[id=%q].", saved.ID, approval.ID)
+
+	return saved.ID, nil
+}
+
+// checkRequesterAccess returns an error if the user who requested an approval is not allowed to access the given
+// clients with the given permission anymore
+func (al *APIListener) checkRequesterAccess(ctx context.Context, requester *users.User, orderedClients []*clients.Client, permission string) error {
+	if al.userService.SupportsGroupPermissions() {
+		if err := al.userService.CheckPermission(requester, permission); err != nil {
+			return err
+		}
+	}
+
+	clientGroups, err := al.clientGroupProvider.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	return al.clientService.CheckClientsAccess(orderedClients, requester, clientGroups, permission)
+}
+
+// handleListApprovals handles GET /approvals
+func (al *APIListener) handleListApprovals(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	options := query.GetListOptions(req)
+
+	// users that can't decide about approvals see only their own requests
+	if al.checkApprover(ctx, curUser) != nil {
+		options.Filters = append(options.Filters, query.FilterOption{
+			Column: []string{"requested_by"},
+			Values: []string{curUser.Username},
+		})
+	}
+
+	result, err := al.approvalsService.List(ctx, options)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// handleGetApproval handles GET /approvals/{approval_id}
+func (al *APIListener) handleGetApproval(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	id := mux.Vars(req)[routes.ParamApprovalID]
+	approval, err := al.approvalsService.Get(ctx, id)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if approval == nil || approval.RequestedBy != curUser.Username && al.checkApprover(ctx, curUser) != nil {
+		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("Approval with id %q not found.", id))
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(approval))
+}
+
+// handleApproveApproval handles POST /approvals/{approval_id}/approve
+func (al *APIListener) handleApproveApproval(w http.ResponseWriter, req *http.Request) {
+	al.handleApprovalDecision(w, req, auditlog.ActionApprove)
+}
+
+// handleRejectApproval handles POST /approvals/{approval_id}/reject
+func (al *APIListener) handleRejectApproval(w http.ResponseWriter, req *http.Request) {
+	al.handleApprovalDecision(w, req, auditlog.ActionReject)
+}
+
+func (al *APIListener) handleApprovalDecision(w http.ResponseWriter, req *http.Request, action string) {
+	ctx := req.Context()
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	err = al.checkApprover(ctx, curUser)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	var reqBody approvalDecisionRequest
+	err = parseRequestBody(req.Body, &reqBody)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	id := mux.Vars(req)[routes.ParamApprovalID]
+	var approval *approvals.Approval
+	if action == auditlog.ActionApprove {
+		approval, err = al.approvalsService.Approve(ctx, id, curUser.Username, reqBody.Comment, al.runApproval)
+	} else {
+		approval, err = al.approvalsService.Reject(ctx, id, curUser.Username, reqBody.Comment)
+	}
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationApproval, action).
+		WithHTTPRequest(req).
+		WithID(approval.ID).
+		WithRequest(reqBody).
+		WithResponse(approval).
+		Save()
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(approval))
+}
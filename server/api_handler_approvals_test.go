@@ -0,0 +1,390 @@
+package chserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/jobs"
+	"github.com/cloudradar-monitoring/rport/server/api/jobs/schedule"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/approvals"
+	"github.com/cloudradar-monitoring/rport/server/cgroups"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+func TestHandlePostMultiClientCommandWithApproval(t *testing.T) {
+	requester := makeTestUser("requester")
+	approver := makeTestUser("approver")
+
+	connMock1 := makeConnMock(t, 1, time.Date(2020, 10, 10, 10, 10, 1, 0, time.UTC))
+	connMock2 := makeConnMock(t, 2, time.Date(2020, 10, 10, 10, 10, 2, 0, time.UTC))
+	c1 := clients.New(t).ID("client-1").Connection(connMock1).Build()
+	c2 := clients.New(t).ID("client-2").Connection(connMock2).Build()
+
+	al := makeAPIListener(requester,
+		clients.NewClientRepository([]*clients.Client{c1, c2}, &hour, testLog),
+		60,
+		testLog)
+	al.userService = users.NewAPIService(users.NewStaticProvider([]*users.User{requester, approver}), false, 0, -1)
+
+	jp := makeJobsProvider(t, DataSourceOptions, testLog)
+	defer jp.Close()
+	gp := makeGroupsProvider(t, DataSourceOptions)
+	defer gp.Close()
+	approvalsProvider, err := approvals.NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	al.approvalsService = approvals.NewService(approvalsProvider, time.Hour, nil, nil, testLog)
+	defer al.approvalsService.Close()
+
+	al.jobProvider = jp
+	al.clientGroupProvider = gp
+	al.initRouter()
+
+	ctx := api.WithUser(context.Background(), requester.Username)
+	g1 := makeClientGroup("production", &cgroups.ClientParams{
+		ClientID: &cgroups.ParamValues{"client-2"},
+	})
+	g1.RequireApproval = true
+	require.NoError(t, gp.Create(ctx, g1))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/commands", strings.NewReader(`{
+		"command": "/bin/date",
+		"client_ids": ["client-1", "client-2"]
+	}`))
+	req = req.WithContext(ctx)
+	w := httptest.NewRecorder()
+	al.router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var pending struct {
+		Data *approvals.Approval `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &pending))
+	assert.Equal(t, approvals.StatusPending, pending.Data.Status)
+	assert.Equal(t, "requester", pending.Data.RequestedBy)
+	assert.EqualValues(t, []string{"production"}, pending.Data.ClientGroupIDs)
+	assert.Equal(t, []string{"client-1", "client-2"}, pending.Data.Request.ClientIDs)
+
+	multiJobs, err := jp.GetMultiJobSummaries(ctx, &query.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, multiJobs, "job must not be started before approval")
+
+	approveURL := "/api/v1/approvals/" + pending.Data.ID + "/approve"
+
+	req = httptest.NewRequest(http.MethodPost, approveURL, strings.NewReader(`{}`))
+	req = req.WithContext(ctx)
+	w = httptest.NewRecorder()
+	al.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	done := make(chan bool)
+	al.testDone = done
+
+	req = httptest.NewRequest(http.MethodPost, approveURL, strings.NewReader(`{"comment": "ok"}`))
+	req = req.WithContext(api.WithUser(context.Background(), approver.Username))
+	w = httptest.NewRecorder()
+	al.router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	<-done
+	var approved struct {
+		Data *approvals.Approval `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &approved))
+	assert.Equal(t, approvals.StatusApproved, approved.Data.Status)
+	assert.Equal(t, "approver", *approved.Data.DecidedBy)
+	require.NotNil(t, approved.Data.JobID)
+
+	gotMultiJob, err := jp.GetMultiJob(ctx, *approved.Data.JobID)
+	require.NoError(t, err)
+	require.NotNil(t, gotMultiJob)
+	assert.Equal(t, "requester", gotMultiJob.CreatedBy)
+	assert.Len(t, gotMultiJob.Jobs, 2)
+}
+
+func TestHandlePostMultiClientCommandWithoutApproval(t *testing.T) {
+	curUser := makeTestUser("test-user")
+	connMock1 := makeConnMock(t, 1, time.Date(2020, 10, 10, 10, 10, 1, 0, time.UTC))
+	c1 := clients.New(t).ID("client-1").Connection(connMock1).Build()
+
+	al := makeAPIListener(curUser,
+		clients.NewClientRepository([]*clients.Client{c1}, &hour, testLog),
+		60,
+		testLog)
+
+	jp := makeJobsProvider(t, DataSourceOptions, testLog)
+	defer jp.Close()
+	gp := makeGroupsProvider(t, DataSourceOptions)
+	defer gp.Close()
+	approvalsProvider, err := approvals.NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	al.approvalsService = approvals.NewService(approvalsProvider, time.Hour, nil, nil, testLog)
+	defer al.approvalsService.Close()
+
+	al.jobProvider = jp
+	al.clientGroupProvider = gp
+	al.initRouter()
+
+	ctx := api.WithUser(context.Background(), curUser.Username)
+	g1 := makeClientGroup("production", &cgroups.ClientParams{
+		ClientID: &cgroups.ParamValues{"client-2"},
+	})
+	g1.RequireApproval = true
+	require.NoError(t, gp.Create(ctx, g1))
+
+	done := make(chan bool)
+	al.testDone = done
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/commands", strings.NewReader(`{
+		"command": "/bin/date",
+		"client_ids": ["client-1"]
+	}`))
+	req = req.WithContext(ctx)
+	w := httptest.NewRecorder()
+	al.router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	<-done
+	assert.Contains(t, w.Body.String(), `{"data":{"jid":`)
+}
+
+// makeApprovalsTestListener returns a listener with approvals enabled for a client group "production" of all clients
+// with ids starting with "prod-". The first user is the requester, the second one the approver.
+func makeApprovalsTestListener(t *testing.T, requester, approver *users.User, clientList ...*clients.Client) (*APIListener, *jobs.SqliteProvider) {
+	t.Helper()
+	al := makeAPIListener(requester, clients.NewClientRepository(clientList, &hour, testLog), 60, testLog)
+	al.userService = users.NewAPIService(users.NewStaticProvider([]*users.User{requester, approver}), false, 0, -1)
+
+	jp := makeJobsProvider(t, DataSourceOptions, testLog)
+	t.Cleanup(func() { jp.Close() })
+	gp := makeGroupsProvider(t, DataSourceOptions)
+	t.Cleanup(func() { gp.Close() })
+	approvalsProvider, err := approvals.NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	al.approvalsService = approvals.NewService(approvalsProvider, time.Hour, nil, nil, testLog)
+	t.Cleanup(func() { al.approvalsService.Close() })
+
+	al.jobProvider = jp
+	al.clientGroupProvider = gp
+	al.scheduleManager = makeScheduleManager(t, jp, al, testLog)
+	al.initRouter()
+
+	production := makeClientGroup("production", &cgroups.ClientParams{
+		ClientID: &cgroups.ParamValues{"prod-*"},
+	})
+	production.RequireApproval = true
+	require.NoError(t, gp.Create(context.Background(), production))
+
+	return al, jp
+}
+
+func sendAs(al *APIListener, username, method, url, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, strings.NewReader(body))
+	req = req.WithContext(api.WithUser(context.Background(), username))
+	w := httptest.NewRecorder()
+	al.router.ServeHTTP(w, req)
+	return w
+}
+
+func decodeApproval(t *testing.T, w *httptest.ResponseRecorder) *approvals.Approval {
+	t.Helper()
+	var resp struct {
+		Data *approvals.Approval `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+func TestApprovedCommandRunsOnRequestedClientsOnly(t *testing.T) {
+	requester := makeTestUser("requester")
+	approver := makeTestUser("approver")
+	c1 := clients.New(t).ID("prod-1").Connection(makeConnMock(t, 1, time.Now())).Build()
+	c2 := clients.New(t).ID("prod-2").Connection(makeConnMock(t, 2, time.Now())).Build()
+	al, jp := makeApprovalsTestListener(t, requester, approver, c1, c2)
+
+	w := sendAs(al, requester.Username, http.MethodPost, "/api/v1/commands", `{
+		"command": "/bin/date",
+		"group_ids": ["production"]
+	}`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	pending := decodeApproval(t, w)
+	assert.ElementsMatch(t, []string{"prod-1", "prod-2"}, pending.Request.ClientIDs)
+	assert.Equal(t, []string{"production"}, pending.Request.GroupIDs)
+
+	// a client joining the group while the request is pending must not be targeted
+	c3 := clients.New(t).ID("prod-3").Connection(makeConnMock(t, 3, time.Now())).Build()
+	require.NoError(t, al.clientService.GetRepo().Save(c3))
+
+	done := make(chan bool)
+	al.testDone = done
+	w = sendAs(al, approver.Username, http.MethodPost, "/api/v1/approvals/"+pending.ID+"/approve", `{}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	<-done
+	approved := decodeApproval(t, w)
+	require.NotNil(t, approved.JobID)
+
+	multiJob, err := jp.GetMultiJob(context.Background(), *approved.JobID)
+	require.NoError(t, err)
+	require.NotNil(t, multiJob)
+	assert.ElementsMatch(t, []string{"prod-1", "prod-2"}, multiJob.ClientIDs)
+	assert.Len(t, multiJob.Jobs, 2)
+}
+
+func TestApprovedCommandWithRevokedAccess(t *testing.T) {
+	requester := makeTestUser("requester")
+	approver := makeTestUser("approver")
+	c1 := clients.New(t).ID("prod-1").Connection(makeConnMock(t, 1, time.Now())).Build()
+	al, jp := makeApprovalsTestListener(t, requester, approver, c1)
+
+	w := sendAs(al, requester.Username, http.MethodPost, "/api/v1/commands", `{
+		"command": "/bin/date",
+		"client_ids": ["prod-1"]
+	}`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	pending := decodeApproval(t, w)
+
+	// the requester is removed from the Administrators group while the request is pending
+	al.userService = users.NewAPIService(users.NewStaticProvider([]*users.User{
+		{Username: requester.Username, Groups: []string{"guests"}},
+		approver,
+	}), false, 0, -1)
+
+	w = sendAs(al, approver.Username, http.MethodPost, "/api/v1/approvals/"+pending.ID+"/approve", `{}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	approved := decodeApproval(t, w)
+	assert.Equal(t, approvals.StatusApproved, approved.Status)
+	assert.Nil(t, approved.JobID)
+	assert.Contains(t, approved.Error, "Access denied to client(s) with ID(s): prod-1")
+
+	multiJobs, err := jp.GetMultiJobSummaries(context.Background(), &query.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, multiJobs)
+}
+
+func TestHandleSchedulesWithApproval(t *testing.T) {
+	requester := makeTestUser("requester")
+	approver := makeTestUser("approver")
+	c1 := clients.New(t).ID("prod-1").Connection(makeConnMock(t, 1, time.Now())).Build()
+	c2 := clients.New(t).ID("test-1").Connection(makeConnMock(t, 2, time.Now())).Build()
+	al, _ := makeApprovalsTestListener(t, requester, approver, c1, c2)
+	ctx := context.Background()
+
+	w := sendAs(al, requester.Username, http.MethodPost, "/api/v1/schedules", `{
+		"name": "invalid",
+		"type": "command",
+		"schedule": "invalid",
+		"command": "/bin/date",
+		"client_ids": ["prod-1"]
+	}`)
+	assert.NotEqual(t, http.StatusAccepted, w.Code, "invalid schedules must not wait for approval")
+	assert.Contains(t, w.Body.String(), "expected exactly 5 fields")
+
+	w = sendAs(al, requester.Username, http.MethodPost, "/api/v1/schedules", `{
+		"name": "date",
+		"type": "command",
+		"schedule": "0 * * * *",
+		"command": "/bin/date",
+		"client_ids": ["prod-1"]
+	}`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	pending := decodeApproval(t, w)
+	require.NotNil(t, pending.Request.Schedule)
+	assert.Equal(t, "date", pending.Request.Schedule.Name)
+
+	list, err := al.scheduleManager.List(ctx, httptest.NewRequest(http.MethodGet, "/api/v1/schedules", nil))
+	require.NoError(t, err)
+	assert.Empty(t, list.Data, "schedule must not be created before approval")
+
+	w = sendAs(al, approver.Username, http.MethodPost, "/api/v1/approvals/"+pending.ID+"/approve", `{}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	approved := decodeApproval(t, w)
+	require.NotNil(t, approved.JobID)
+	created, err := al.scheduleManager.Get(ctx, *approved.JobID)
+	require.NoError(t, err)
+	require.NotNil(t, created)
+	assert.Equal(t, "requester", created.CreatedBy)
+	assert.Equal(t, "0 * * * *", created.Schedule)
+
+	// changing a schedule to target a client of the group requires approval, too
+	w = sendAs(al, requester.Username, http.MethodPost, "/api/v1/schedules", `{
+		"name": "uptime",
+		"type": "command",
+		"schedule": "0 * * * *",
+		"command": "/bin/uptime",
+		"client_ids": ["test-1"]
+	}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var resp struct {
+		Data *schedule.Schedule `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	w = sendAs(al, requester.Username, http.MethodPut, "/api/v1/schedules/"+resp.Data.ID, `{
+		"name": "uptime",
+		"type": "command",
+		"schedule": "0 * * * *",
+		"command": "/bin/uptime",
+		"group_ids": ["production"]
+	}`)
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	pending = decodeApproval(t, w)
+	assert.Equal(t, resp.Data.ID, pending.Request.Schedule.ID)
+
+	unchanged, err := al.scheduleManager.Get(ctx, resp.Data.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-1"}, unchanged.ClientIDs)
+
+	w = sendAs(al, approver.Username, http.MethodPost, "/api/v1/approvals/"+pending.ID+"/approve", `{}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	updated, err := al.scheduleManager.Get(ctx, resp.Data.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"production"}, updated.GroupIDs)
+}
+
+func TestHandlePostUpdatesInstallationWithApproval(t *testing.T) {
+	requester := makeTestUser("requester")
+	approver := makeTestUser("approver")
+	connMock := makeConnMock(t, 1, time.Now())
+	c1 := clients.New(t).ID("prod-1").Connection(connMock).Build()
+	al, jp := makeApprovalsTestListener(t, requester, approver, c1)
+
+	for url, body := range map[string]string{
+		"/api/v1/updates-installation":                `{"mode": "security", "client_ids": ["prod-1"]}`,
+		"/api/v1/clients/prod-1/updates-installation": `{"mode": "security"}`,
+	} {
+		t.Run(url, func(t *testing.T) {
+			w := sendAs(al, requester.Username, http.MethodPost, url, body)
+			require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+			pending := decodeApproval(t, w)
+			require.NotNil(t, pending.Request.UpdatesInstallation)
+			assert.Equal(t, models.UpdatesInstallationModeSecurity, pending.Request.UpdatesInstallation.Mode)
+			assert.Contains(t, pending.Text(), "updates installation")
+
+			done := make(chan bool)
+			al.testDone = done
+			w = sendAs(al, approver.Username, http.MethodPost, "/api/v1/approvals/"+pending.ID+"/approve", `{}`)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			<-done
+			approved := decodeApproval(t, w)
+			require.NotNil(t, approved.JobID)
+
+			multiJob, err := jp.GetMultiJob(context.Background(), *approved.JobID)
+			require.NoError(t, err)
+			require.NotNil(t, multiJob)
+			assert.Equal(t, &models.UpdatesInstallation{Mode: models.UpdatesInstallationModeSecurity}, multiJob.UpdatesInstallation)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package chserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/server/auditlog"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/routes"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+)
+
+const queryParamPath = "path"
+
+// clientFileInfo describes a file or directory on a client, entries are set for directories only
+type clientFileInfo struct {
+	Name       string            `json:"name"`
+	Path       string            `json:"path"`
+	Size       int64             `json:"size"`
+	Mode       string            `json:"mode"`
+	IsDir      bool              `json:"is_dir"`
+	ModifiedAt time.Time         `json:"modified_at"`
+	Entries    []*clientFileInfo `json:"entries"`
+}
+
+type clientFilesAuditRequest struct {
+	Path string `json:"path"`
+}
+
+func newClientFileInfo(path string, info os.FileInfo) *clientFileInfo {
+	return &clientFileInfo{
+		Name:       info.Name(),
+		Path:       path,
+		Size:       info.Size(),
+		Mode:       info.Mode().String(),
+		IsDir:      info.IsDir(),
+		ModifiedAt: info.ModTime(),
+	}
+}
+
+// handleGetClientFiles handles GET /clients/{client_id}/files?path=
+func (al *APIListener) handleGetClientFiles(w http.ResponseWriter, req *http.Request) {
+	client, path, ok := al.getClientFilesTarget(w, req)
+	if !ok {
+		return
+	}
+
+	sftpClient, err := al.openClientSFTP(client, api.GetUser(req.Context(), al.Logger))
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	defer sftpClient.Close()
+
+	info, err := sftpClient.Stat(path)
+	if err != nil {
+		al.jsonError(w, convertClientFilesError(path, err))
+		return
+	}
+
+	result := newClientFileInfo(path, info)
+	if info.IsDir() {
+		entries, err := sftpClient.ReadDir(path)
+		if err != nil {
+			al.jsonError(w, convertClientFilesError(path, err))
+			return
+		}
+		result.Entries = make([]*clientFileInfo, 0, len(entries))
+		for _, entry := range entries {
+			result.Entries = append(result.Entries, newClientFileInfo(joinClientPath(client, path, entry.Name()), entry))
+		}
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationClientFiles, auditlog.ActionList).
+		WithHTTPRequest(req).
+		WithClient(client).
+		WithRequest(clientFilesAuditRequest{Path: path}).
+		Save()
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(result))
+}
+
+// handleGetClientFileContent handles GET /clients/{client_id}/files/content?path=
+func (al *APIListener) handleGetClientFileContent(w http.ResponseWriter, req *http.Request) {
+	client, path, ok := al.getClientFilesTarget(w, req)
+	if !ok {
+		return
+	}
+
+	sftpClient, err := al.openClientSFTP(client, api.GetUser(req.Context(), al.Logger))
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	defer sftpClient.Close()
+
+	info, err := sftpClient.Stat(path)
+	if err != nil {
+		al.jsonError(w, convertClientFilesError(path, err))
+		return
+	}
+	if info.IsDir() {
+		al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, fmt.Sprintf("%s is a directory.", path))
+		return
+	}
+
+	file, err := sftpClient.Open(path)
+	if err != nil {
+		al.jsonError(w, convertClientFilesError(path, err))
+		return
+	}
+	defer file.Close()
+
+	al.auditLog.Entry(auditlog.ApplicationClientFiles, auditlog.ActionDownload).
+		WithHTTPRequest(req).
+		WithClient(client).
+		WithRequest(clientFilesAuditRequest{Path: path}).
+		Save()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", info.Name()))
+	http.ServeContent(w, req, info.Name(), info.ModTime(), file)
+}
+
+// getClientFilesTarget writes an error response and returns false if the path is missing or
+// the client is not connected or has the file browser disabled.
+func (al *APIListener) getClientFilesTarget(w http.ResponseWriter, req *http.Request) (*clients.Client, string, bool) {
+	path := req.URL.Query().Get(queryParamPath)
+	if path == "" {
+		al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, fmt.Sprintf("Missing %q query parameter.", queryParamPath))
+		return nil, "", false
+	}
+
+	clientID := mux.Vars(req)[routes.ParamClientID]
+	client, err := al.clientService.GetActiveByID(clientID)
+	if err != nil {
+		al.jsonError(w, err)
+		return nil, "", false
+	}
+	if client == nil {
+		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("Active client with id=%q not found.", clientID))
+		return nil, "", false
+	}
+	if client.ClientConfiguration == nil || !client.ClientConfiguration.FileBrowser.Enabled {
+		al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "File browser is disabled on the client, check [file-browser] enabled option.")
+		return nil, "", false
+	}
+
+	return client, path, true
+}
+
+func (al *APIListener) openClientSFTP(client *clients.Client, username string) (*sftp.Client, error) {
+	payload, err := json.Marshal(comm.SFTPRequest{
+		User:       username,
+		UserGroups: al.getUserGroups(username),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ch, reqs, err := client.Connection.OpenChannel(comm.ChannelTypeSFTP, payload)
+	if err != nil {
+		var openErr *ssh.OpenChannelError
+		if errors.As(err, &openErr) && openErr.Reason == ssh.Prohibited {
+			return nil, errors2.APIError{
+				Message:    fmt.Sprintf("File browser session rejected by the client: %s.", openErr.Message),
+				HTTPStatus: http.StatusForbidden,
+			}
+		}
+		return nil, fmt.Errorf("failed to open sftp session on client %s: %w", client.ID, err)
+	}
+	go ssh.DiscardRequests(reqs)
+
+	sftpClient, err := sftp.NewClientPipe(ch, ch)
+	if err != nil {
+		_ = ch.Close()
+		return nil, fmt.Errorf("failed to start sftp session on client %s: %w", client.ID, err)
+	}
+
+	return sftpClient, nil
+}
+
+func convertClientFilesError(path string, err error) error {
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return errors2.APIError{
+			Message:    fmt.Sprintf("%s not found.", path),
+			HTTPStatus: http.StatusNotFound,
+		}
+	case errors.Is(err, os.ErrPermission):
+		return errors2.APIError{
+			Message:    fmt.Sprintf("Access to %s is denied by the client.", path),
+			HTTPStatus: http.StatusForbidden,
+		}
+	}
+	return err
+}
+
+// joinClientPath returns the path of a directory entry using the path separator of the client OS
+func joinClientPath(client *clients.Client, dir, name string) string {
+	sep := "/"
+	if client.OSKernel == "windows" {
+		sep = `\`
+	}
+	return strings.TrimRight(dir, sep) + sep + name
+}
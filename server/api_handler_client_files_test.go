@@ -0,0 +1,212 @@
+package chserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+// sftpConnMock serves each opened sftp channel with the given handlers, it rejects channels of deniedUser like the
+// user policy of the client does
+type sftpConnMock struct {
+	*test.ConnMock
+	handlers   sftp.Handlers
+	deniedUser string
+	request    comm.SFTPRequest
+}
+
+func (c *sftpConnMock) OpenChannel(name string, data []byte) (ssh.Channel, <-chan *ssh.Request, error) {
+	if name != comm.ChannelTypeSFTP {
+		return nil, nil, &ssh.OpenChannelError{Reason: ssh.UnknownChannelType}
+	}
+	c.request = comm.SFTPRequest{}
+	if err := json.Unmarshal(data, &c.request); err != nil {
+		return nil, nil, err
+	}
+	if c.deniedUser != "" && c.request.User == c.deniedUser {
+		return nil, nil, &ssh.OpenChannelError{Reason: ssh.Prohibited, Message: fmt.Sprintf("user policy: user %q is denied", c.deniedUser)}
+	}
+
+	serverConn, clientConn := net.Pipe()
+	server := sftp.NewRequestServer(clientConn, c.handlers)
+	go func() {
+		_ = server.Serve()
+		server.Close()
+	}()
+
+	return &sftpChannelMock{Conn: serverConn}, make(chan *ssh.Request), nil
+}
+
+type sftpChannelMock struct {
+	ssh.Channel
+	net.Conn
+}
+
+func (c *sftpChannelMock) Read(data []byte) (int, error) {
+	return c.Conn.Read(data)
+}
+
+func (c *sftpChannelMock) Write(data []byte) (int, error) {
+	return c.Conn.Write(data)
+}
+
+func (c *sftpChannelMock) Close() error {
+	return c.Conn.Close()
+}
+
+// deniedLister rejects listing of a single path like the client does based on its file browser config
+type deniedLister struct {
+	sftp.FileLister
+	denied string
+}
+
+func (l deniedLister) Filelist(r *sftp.Request) (sftp.ListerAt, error) {
+	if r.Filepath == l.denied {
+		return nil, sftp.ErrSSHFxPermissionDenied
+	}
+	return l.FileLister.Filelist(r)
+}
+
+func TestHandleClientFiles(t *testing.T) {
+	handlers := sftp.InMemHandler()
+	handlers.FileList = deniedLister{FileLister: handlers.FileList, denied: "/secret"}
+	connMock := &sftpConnMock{ConnMock: test.NewConnMock(), handlers: handlers}
+
+	c1 := clients.New(t).Connection(connMock).Build()
+	c1.ClientConfiguration = &clientconfig.Config{FileBrowser: clientconfig.FileBrowserConfig{Enabled: true}}
+	c2 := clients.New(t).Connection(connMock).Build()
+	c2.ClientConfiguration = &clientconfig.Config{}
+
+	al := APIListener{
+		Server: &Server{
+			config:        &chconfig.Config{},
+			clientService: NewClientService(nil, nil, clients.NewClientRepository([]*clients.Client{c1, c2}, &hour, testLog), testLog),
+		},
+		userService: users.NewAPIService(users.NewStaticProvider([]*users.User{{Username: "admin", Groups: []string{users.Administrators}}}), false, 0, -1),
+		Logger:      testLog,
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/clients/{client_id}/files", al.handleGetClientFiles)
+	router.HandleFunc("/clients/{client_id}/files/content", al.handleGetClientFileContent)
+
+	// prepare the client files
+	sftpClient, err := al.openClientSFTP(c1, "")
+	require.NoError(t, err)
+	require.NoError(t, sftpClient.Mkdir("/logs"))
+	file, err := sftpClient.Create("/logs/app.log")
+	require.NoError(t, err)
+	_, err = file.Write([]byte("started"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	require.NoError(t, sftpClient.Close())
+
+	doRequest := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req = req.WithContext(api.WithUser(context.Background(), "admin"))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("list directory", func(t *testing.T) {
+		w := doRequest("/clients/" + c1.ID + "/files?path=/logs")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var result struct {
+			Data clientFileInfo `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, "logs", result.Data.Name)
+		assert.Equal(t, "/logs", result.Data.Path)
+		assert.True(t, result.Data.IsDir)
+		require.Len(t, result.Data.Entries, 1)
+		assert.Equal(t, "app.log", result.Data.Entries[0].Name)
+		assert.Equal(t, "/logs/app.log", result.Data.Entries[0].Path)
+		assert.EqualValues(t, 7, result.Data.Entries[0].Size)
+		assert.False(t, result.Data.Entries[0].IsDir)
+	})
+
+	t.Run("user sent to client", func(t *testing.T) {
+		w := doRequest("/clients/" + c1.ID + "/files?path=/logs")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, comm.SFTPRequest{User: "admin", UserGroups: []string{users.Administrators}}, connMock.request)
+	})
+
+	t.Run("rejected by user policy", func(t *testing.T) {
+		connMock.deniedUser = "admin"
+		defer func() { connMock.deniedUser = "" }()
+
+		w := doRequest("/clients/" + c1.ID + "/files?path=/logs")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), `user policy: user \"admin\" is denied`)
+	})
+
+	t.Run("stat file", func(t *testing.T) {
+		w := doRequest("/clients/" + c1.ID + "/files?path=/logs/app.log")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var result struct {
+			Data clientFileInfo `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, "app.log", result.Data.Name)
+		assert.False(t, result.Data.IsDir)
+		assert.Nil(t, result.Data.Entries)
+	})
+
+	t.Run("download", func(t *testing.T) {
+		w := doRequest("/clients/" + c1.ID + "/files/content?path=/logs/app.log")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "started", w.Body.String())
+		assert.Equal(t, `attachment; filename="app.log"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "7", w.Header().Get("Content-Length"))
+	})
+
+	t.Run("download directory", func(t *testing.T) {
+		w := doRequest("/clients/" + c1.ID + "/files/content?path=/logs")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		w := doRequest("/clients/" + c1.ID + "/files?path=/missing")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		w := doRequest("/clients/" + c1.ID + "/files?path=/secret")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("missing path", func(t *testing.T) {
+		w := doRequest("/clients/" + c1.ID + "/files")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("disabled on client", func(t *testing.T) {
+		w := doRequest("/clients/" + c2.ID + "/files?path=/logs")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("inactive client", func(t *testing.T) {
+		w := doRequest("/clients/unknown/files?path=/logs")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
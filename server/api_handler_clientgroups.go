@@ -1,6 +1,7 @@
 package chserver
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -39,6 +40,9 @@ func (al *APIListener) handlePostClientGroups(w http.ResponseWriter, req *http.R
 		WithID(group.ID).
 		Save()
 
+	// config profiles are assigned to client groups, their clients might have changed
+	go al.pushConfigProfilesToAll(context.Background())
+
 	w.WriteHeader(http.StatusCreated)
 	al.Debugf("Client Group [id=%q] created.", group.ID)
 }
@@ -79,6 +83,8 @@ func (al *APIListener) handlePutClientGroup(w http.ResponseWriter, req *http.Req
 		WithID(id).
 		Save()
 
+	go al.pushConfigProfilesToAll(context.Background())
+
 	w.WriteHeader(http.StatusNoContent)
 	al.Debugf("Client Group [id=%q] updated.", group.ID)
 }
@@ -125,7 +131,13 @@ func (al *APIListener) handleGetClientGroup(w http.ResponseWriter, req *http.Req
 		return
 	}
 
-	al.clientService.PopulateGroupsWithUserClients([]*cgroups.ClientGroup{group}, curUser)
+	allGroups, err := al.clientGroupProvider.GetAll(req.Context())
+	if err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, "Failed to get client groups.", err)
+		return
+	}
+
+	al.clientService.PopulateGroupsWithUserClients([]*cgroups.ClientGroup{group}, allGroups, curUser, "")
 	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(group))
 }
 
@@ -142,7 +154,7 @@ func (al *APIListener) handleGetClientGroups(w http.ResponseWriter, req *http.Re
 		return
 	}
 
-	al.clientService.PopulateGroupsWithUserClients(res, curUser)
+	al.clientService.PopulateGroupsWithUserClients(res, res, curUser, "")
 
 	// for non-admins filter out groups with no clients
 	if !curUser.IsAdmin() {
@@ -181,6 +193,8 @@ func (al *APIListener) handleDeleteClientGroup(w http.ResponseWriter, req *http.
 		WithID(id).
 		Save()
 
+	go al.pushConfigProfilesToAll(context.Background())
+
 	w.WriteHeader(http.StatusNoContent)
 	al.Debugf("Client Group [id=%q] deleted.", id)
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/cloudradar-monitoring/rport/server/ports"
 	"github.com/cloudradar-monitoring/rport/server/routes"
 	"github.com/cloudradar-monitoring/rport/server/validation"
+	"github.com/cloudradar-monitoring/rport/server/webhooks"
 	"github.com/cloudradar-monitoring/rport/share/clientconfig"
 	"github.com/cloudradar-monitoring/rport/share/comm"
 	"github.com/cloudradar-monitoring/rport/share/models"
@@ -53,6 +54,7 @@ type ClientPayload struct {
 	Tags                   *[]string               `json:"tags,omitempty"`
 	AllowedUserGroups      *[]string               `json:"allowed_user_groups,omitempty"`
 	Tunnels                *[]*clienttunnel.Tunnel `json:"tunnels,omitempty"`
+	TunnelTraffic          *clients.TunnelTraffic  `json:"tunnel_traffic,omitempty"`
 	UpdatesStatus          **models.UpdatesStatus  `json:"updates_status,omitempty"`
 	ClientConfiguration    **clientconfig.Config   `json:"client_configuration,omitempty"`
 	Groups                 *[]string               `json:"groups,omitempty"`
@@ -100,6 +102,8 @@ func convertToClientPayload(client *clients.CalculatedClient, fields []query.Fie
 			p.Address = &client.Address
 		case "tunnels":
 			p.Tunnels = &client.Tunnels
+		case "tunnel_traffic":
+			p.TunnelTraffic = &client.TunnelTraffic
 		case "disconnected_at":
 			p.DisconnectedAt = &client.DisconnectedAt
 		case "last_heartbeat_at":
@@ -314,6 +318,8 @@ const (
 	autoCloseQueryParam          = "auto-close"
 	idleTimeoutMinutesQueryParam = "idle-timeout-minutes"
 	skipIdleTimeoutQueryParam    = "skip-idle-timeout"
+	rateLimitUpQueryParam        = "rate-limit-up"
+	rateLimitDownQueryParam      = "rate-limit-down"
 
 	ErrCodeLocalPortInUse        = "ERR_CODE_LOCAL_PORT_IN_USE"
 	ErrCodeRemotePortNotOpen     = "ERR_CODE_REMOTE_PORT_NOT_OPEN"
@@ -360,6 +366,14 @@ func (al *APIListener) handlePutClientTunnel(w http.ResponseWriter, req *http.Re
 		remoteStr = remoteAddr
 	}
 	protocol := req.URL.Query().Get("protocol")
+	if protocol == models.ProtocolSOCKS5 {
+		// the destinations of socks5 tunnels are requested by the SOCKS5 clients
+		if remoteAddr != "" {
+			al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "remote is not supported with protocol socks5")
+			return
+		}
+		remoteStr = localAddr
+	}
 	if protocol != "" {
 		remoteStr += "/" + protocol
 	}
@@ -370,13 +384,19 @@ func (al *APIListener) handlePutClientTunnel(w http.ResponseWriter, req *http.Re
 		return
 	}
 
-	allowed, err := clienttunnel.IsAllowed(remote.Remote(), client.Connection)
+	username := api.GetUser(req.Context(), al.Logger)
+	userGroups := al.getUserGroups(username)
+	allowed, err := clienttunnel.IsAllowed(&comm.CheckTunnelAllowedRequest{
+		Remote:     remote.Remote(),
+		User:       username,
+		UserGroups: userGroups,
+	}, client.Connection)
 	if err != nil {
 		al.jsonError(w, err)
 		return
 	}
 	if !allowed {
-		al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "Tunnel destination is not allowed by client configuration.")
+		al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "Tunnel destination or user is not allowed by client configuration.")
 		return
 	}
 
@@ -400,6 +420,18 @@ func (al *APIListener) handlePutClientTunnel(w http.ResponseWriter, req *http.Re
 		return
 	}
 
+	remote.RateLimitUp, err = validation.ResolveTunnelRateLimitValue(rateLimitUpQueryParam, req.URL.Query().Get(rateLimitUpQueryParam))
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	remote.RateLimitDown, err = validation.ResolveTunnelRateLimitValue(rateLimitDownQueryParam, req.URL.Query().Get(rateLimitDownQueryParam))
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
 	aclStr := req.URL.Query().Get("acl")
 	if _, err = clienttunnel.ParseTunnelACL(aclStr); err != nil {
 		al.jsonErrorResponseWithErrCode(w, http.StatusBadRequest, ErrCodeInvalidACL, fmt.Sprintf("Invalid ACL: %s", err))
@@ -429,7 +461,7 @@ func (al *APIListener) handlePutClientTunnel(w http.ResponseWriter, req *http.Re
 	}
 
 	for _, t := range client.Tunnels {
-		if t.Remote.Remote() == remote.Remote() && t.Remote.IsProtocol(remote.Protocol) && t.EqualACL(remote.ACL) {
+		if remote.Protocol != models.ProtocolSOCKS5 && t.Remote.Remote() == remote.Remote() && t.Remote.IsProtocol(remote.Protocol) && t.EqualACL(remote.ACL) {
 			al.jsonErrorResponseWithErrCode(w, http.StatusBadRequest, ErrCodeTunnelToPortExist, fmt.Sprintf("Tunnel to port %s already exist.", remote.RemotePort))
 			return
 		}
@@ -443,6 +475,9 @@ func (al *APIListener) handlePutClientTunnel(w http.ResponseWriter, req *http.Re
 
 	al.getTunnelProxyOptions(w, req, remote)
 
+	remote.Owner = username
+	remote.OwnerGroups = userGroups
+
 	// make next steps thread-safe
 	client.Lock()
 	defer client.Unlock()
@@ -465,6 +500,7 @@ func (al *APIListener) handlePutClientTunnel(w http.ResponseWriter, req *http.Re
 		WithResponse(tunnels[0]).
 		WithID(tunnels[0].ID).
 		Save()
+	al.webhooksService.Dispatch(webhooks.EventTunnelCreated, tunnelEvent(client.ID, username, tunnels[0], ""))
 
 	al.writeJSONResponse(w, http.StatusOK, response)
 }
@@ -496,7 +532,8 @@ func (al *APIListener) getTunnelProxyOptions(w http.ResponseWriter, req *http.Re
 	authUser := req.URL.Query().Get("auth_user")
 	authPassword := req.URL.Query().Get("auth_password")
 	if authUser != "" || authPassword != "" {
-		if !isHTTPProxy {
+		// socks5 tunnels use the credentials for the SOCKS5 username/password authentication
+		if !isHTTPProxy && remote.Protocol != models.ProtocolSOCKS5 {
 			al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "http basic authentication requires http_proxy to be activated on the requested tunnel")
 			return
 		}
@@ -636,6 +673,7 @@ func (al *APIListener) handleDeleteClientTunnel(w http.ResponseWriter, req *http
 			"force": force,
 		}).
 		Save()
+	al.webhooksService.Dispatch(webhooks.EventTunnelDeleted, tunnelEvent(client.ID, api.GetUser(req.Context(), al.Logger), tunnel, clienttunnel.CloseReasonDeleted))
 
 	w.WriteHeader(http.StatusNoContent)
 }
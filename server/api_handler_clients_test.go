@@ -102,6 +102,7 @@ func TestHandleGetClient(t *testing.T) {
         "version":"0.1.12",
         "address":"88.198.189.161:50078",
         "timezone":"UTC-0",
+        "tunnel_traffic":{"bytes_sent":0,"bytes_received":0},
         "tunnels":[
             {
                 "name": "",
@@ -116,6 +117,12 @@ func TestHandleGetClient(t *testing.T) {
                 "host_header":"",
                 "auth_user":"",
                 "auth_password":"",
+                "rate_limit_up":0,
+                "rate_limit_down":0,
+                "bytes_sent":0,
+                "bytes_received":0,
+                "owner":"",
+                "owner_groups":null,
                 "http_proxy":false,
                 "idle_timeout_minutes": 0,
                 "auto_close": 0,
@@ -135,6 +142,12 @@ func TestHandleGetClient(t *testing.T) {
                 "host_header":"",
                 "auth_user":"",
                 "auth_password":"",
+                "rate_limit_up":0,
+                "rate_limit_down":0,
+                "bytes_sent":0,
+                "bytes_received":0,
+                "owner":"",
+                "owner_groups":null,
                 "http_proxy":false,
                 "idle_timeout_minutes": 0,
                 "auto_close": 0,
@@ -398,7 +411,7 @@ func TestHandlePutTunnelWithName(t *testing.T) {
 	}{
 		{
 			Name: "With Name",
-			URL:  "/api/v1/clients/client-1/tunnels?scheme=ssh&acl=127.0.0.1&local=0.0.0.0%3A3390&remote=0.0.0.0%3A22&name=TUNNELNAME&check_port=0",
+			URL:  "/api/v1/clients/client-1/tunnels?scheme=ssh&acl=127.0.0.1&local=0.0.0.0%3A3390&remote=0.0.0.0%3A22&name=TUNNELNAME&check_port=0&rate-limit-up=1024&rate-limit-down=2048",
 			ExpectedJSON: `{
 			"data": {
 				"id": "10",
@@ -417,6 +430,12 @@ func TestHandlePutTunnelWithName(t *testing.T) {
 				"host_header": "",
 				"auth_user":"",
 				"auth_password":"",
+				"rate_limit_up":1024,
+				"rate_limit_down":2048,
+				"bytes_sent":0,
+				"bytes_received":0,
+				"owner":"admin",
+				"owner_groups":["Administrators"],
 				"created_at": "0001-01-01T00:00:00Z"
 			}
 		}`,
@@ -442,6 +461,12 @@ func TestHandlePutTunnelWithName(t *testing.T) {
 				"host_header": "",
 				"auth_user":"",
 				"auth_password":"",
+				"rate_limit_up":0,
+				"rate_limit_down":0,
+				"bytes_sent":0,
+				"bytes_received":0,
+				"owner":"admin",
+				"owner_groups":["Administrators"],
 				"created_at": "0001-01-01T00:00:00Z"
 			}
 		}`,
@@ -467,6 +492,12 @@ func TestHandlePutTunnelWithName(t *testing.T) {
 				"host_header": "",
 				"auth_user":"admin",
 				"auth_password":"foo",
+				"rate_limit_up":0,
+				"rate_limit_down":0,
+				"bytes_sent":0,
+				"bytes_received":0,
+				"owner":"admin",
+				"owner_groups":["Administrators"],
 				"created_at": "0001-01-01T00:00:00Z"
 			}
 		}`,
@@ -476,6 +507,47 @@ func TestHandlePutTunnelWithName(t *testing.T) {
 			URL:           "/api/v1/clients/client-1/tunnels?scheme=http&acl=127.0.0.1&local=0.0.0.0%3A3390&remote=0.0.0.0%3A22&check_port=0&auth_user=admin&http_proxy=1",
 			ExpectedError: "auth_user requires auth_password",
 		},
+		{
+			Name: "SOCKS5 With User and Password",
+			URL:  "/api/v1/clients/client-1/tunnels?protocol=socks5&acl=127.0.0.1&local=0.0.0.0%3A3390&auth_user=admin&auth_password=foo",
+			ExpectedJSON: `{
+			"data": {
+				"id": "10",
+				"name": "",
+				"protocol": "socks5",
+				"lhost": "0.0.0.0",
+				"lport": "3390",
+				"rhost": "",
+				"rport": "",
+				"lport_random": false,
+				"scheme": null,
+				"acl": "127.0.0.1",
+				"idle_timeout_minutes": 5,
+				"auto_close": 0,
+				"http_proxy": false,
+				"host_header": "",
+				"auth_user":"admin",
+				"auth_password":"foo",
+				"rate_limit_up":0,
+				"rate_limit_down":0,
+				"bytes_sent":0,
+				"bytes_received":0,
+				"owner":"admin",
+				"owner_groups":["Administrators"],
+				"created_at": "0001-01-01T00:00:00Z"
+			}
+		}`,
+		},
+		{
+			Name:          "SOCKS5 with remote",
+			URL:           "/api/v1/clients/client-1/tunnels?protocol=socks5&local=0.0.0.0%3A3390&remote=0.0.0.0%3A22",
+			ExpectedError: "remote is not supported with protocol socks5",
+		},
+		{
+			Name:          "Negative rate limit",
+			URL:           "/api/v1/clients/client-1/tunnels?local=0.0.0.0%3A3390&remote=0.0.0.0%3A22&check_port=0&rate-limit-down=-1",
+			ExpectedError: "rate-limit-down param should not be negative",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -507,11 +579,13 @@ func TestHandlePutTunnelWithName(t *testing.T) {
 					},
 					clientGroupProvider: mockClientGroupProvider{},
 				},
+				userService: users.NewAPIService(users.NewStaticProvider([]*users.User{{Username: "admin", Groups: []string{users.Administrators}}}), false, 0, -1),
 			}
 			al.initRouter()
 
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest("PUT", tc.URL, nil)
+			req = req.WithContext(api.WithUser(req.Context(), "admin"))
 
 			al.router.ServeHTTP(w, req)
 			if tc.ExpectedError == "" {
@@ -0,0 +1,75 @@
+package chserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/cluster"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+// handlePostClusterClientRequest handles POST /cluster/client-requests, it relays a request of another node of the
+// cluster to a client connected to this node
+func (al *APIListener) handlePostClusterClientRequest(w http.ResponseWriter, req *http.Request) {
+	var clientReq cluster.ClientRequest
+	err := parseRequestBody(req.Body, &clientReq)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	client, err := al.clientService.GetActiveByID(clientReq.ClientID)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if client == nil || client.Node != "" {
+		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("Active client with id=%q not found.", clientReq.ClientID))
+		return
+	}
+
+	// the client sends the job result to this node, it's passed on to the node that started the job
+	if clientReq.Type == comm.RequestTypeRunCmd || clientReq.Type == comm.RequestTypeInstallUpdates {
+		job := models.Job{}
+		if err := json.Unmarshal(clientReq.Payload, &job); err == nil && job.JID != "" {
+			al.clusterService.TrackRelayedJob(job.JID, req.Header.Get(cluster.HeaderNode))
+		}
+	}
+
+	ok, payload, err := client.Connection.SendRequest(clientReq.Type, clientReq.WantReply, clientReq.Payload)
+	if err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, "Failed to send request to client.", err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(&cluster.ClientResponse{
+		OK:      ok,
+		Payload: payload,
+	}))
+}
+
+// handlePostClusterJobResult handles POST /cluster/job-results, it receives the result of a job started by this node
+// on a client connected to another node of the cluster
+func (al *APIListener) handlePostClusterJobResult(w http.ResponseWriter, req *http.Request) {
+	result, err := io.ReadAll(req.Body)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	job := models.Job{}
+	err = json.Unmarshal(result, &job)
+	if err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusBadRequest, "Invalid job result.", err)
+		return
+	}
+
+	al.writeJobResultToUI(&job, result)
+	al.signalJobDone(&job)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,185 @@
+package chserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/apikeys"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/cluster"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+const testClusterSecret = "0123456789abcdef0123456789abcdef"
+
+type testClusterNode struct {
+	al      *APIListener
+	service *cluster.Service
+	server  *httptest.Server
+}
+
+func makeTestClusterNode(t *testing.T, nodeID string, provider cluster.Provider, clientList []*clients.Client) *testClusterNode {
+	curUser := makeTestUser("admin")
+	node := &testClusterNode{}
+	node.al = makeAPIListener(curUser, clients.NewClientRepository(clientList, &hour, testLog), 60, testLog)
+	gp := makeGroupsProvider(t, DataSourceOptions)
+	t.Cleanup(func() { gp.Close() })
+	node.al.clientGroupProvider = gp
+	node.al.Server.Logger = testLog
+
+	node.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		node.al.router.ServeHTTP(w, r.WithContext(api.WithUser(r.Context(), curUser.Username)))
+	}))
+	t.Cleanup(node.server.Close)
+
+	node.service = cluster.NewService(cluster.Config{
+		Enabled:           true,
+		NodeID:            nodeID,
+		NodeURL:           node.server.URL,
+		Secret:            testClusterSecret,
+		HeartbeatInterval: 5 * time.Second,
+		NodeTimeout:       30 * time.Second,
+	}, provider, testLog)
+	node.al.clusterService = node.service
+	node.al.initRouter()
+
+	return node
+}
+
+func TestClusterForwardAndRelay(t *testing.T) {
+	ctx := context.Background()
+	provider, err := cluster.NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	defer provider.Close()
+
+	connMock := makeConnMock(t, 1, time.Date(2020, 10, 10, 10, 10, 1, 0, time.UTC))
+	localClient := clients.New(t).ID("client-1").Connection(connMock).Build()
+	localClient.Name = "connected-to-node-2"
+	node2 := makeTestClusterNode(t, "node-2", provider, []*clients.Client{localClient})
+	require.NoError(t, node2.service.ClaimClient(ctx, "client-1"))
+	require.NoError(t, node2.service.Heartbeat(ctx))
+
+	node1 := makeTestClusterNode(t, "node-1", provider, nil)
+	require.NoError(t, node1.service.Heartbeat(ctx))
+	require.NoError(t, node2.service.Heartbeat(ctx))
+	remoteNode := node1.service.RemoteClients()["client-1"]
+	require.NotNil(t, remoteNode)
+	remoteConn := node1.service.NewRemoteConn(remoteNode, "client-1")
+	remoteClient := clients.New(t).ID("client-1").Connection(remoteConn).Build()
+	remoteClient.Name = "stale"
+	remoteClient.Node = "node-2"
+	require.NoError(t, node1.al.clientService.GetRepo().Save(remoteClient))
+
+	t.Run("forward", func(t *testing.T) {
+		resp, err := http.Get(node1.server.URL + "/api/v1/clients/client-1?fields[clients]=id,name")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var got struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+		assert.Equal(t, "connected-to-node-2", got.Data["name"])
+	})
+
+	t.Run("relay request and job result", func(t *testing.T) {
+		multiJobID := "multi-job-1"
+		job := models.Job{JID: "job-1", ClientID: "client-1", MultiJobID: &multiJobID}
+		resp := &comm.RunCmdResponse{}
+		err := comm.SendRequestAndGetResponse(remoteConn, comm.RequestTypeRunCmd, job, resp)
+		require.NoError(t, err)
+		assert.Equal(t, 1, resp.Pid)
+
+		done := make(chan *models.Job)
+		node1.al.jobsDoneChannel.Set(multiJobID, done)
+		defer node1.al.jobsDoneChannel.Del(multiJobID)
+
+		job.Status = models.JobStatusSuccessful
+		result, err := json.Marshal(job)
+		require.NoError(t, err)
+		node2.al.relayJobResult(&job, result)
+
+		select {
+		case got := <-done:
+			assert.Equal(t, "job-1", got.JID)
+			assert.Equal(t, models.JobStatusSuccessful, got.Status)
+		case <-time.After(5 * time.Second):
+			t.Fatal("job result was not relayed")
+		}
+	})
+
+	t.Run("unsigned request", func(t *testing.T) {
+		resp, err := http.Post(node2.server.URL+"/api/v1/cluster/job-results", "application/json", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestClusterForwardWithAllowListedAPIKey(t *testing.T) {
+	ctx := context.Background()
+	provider, err := cluster.NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	defer provider.Close()
+	apiKeysProvider, err := apikeys.NewSqliteProvider(":memory:", database.Options{SQLite: DataSourceOptions})
+	require.NoError(t, err)
+	defer apiKeysProvider.Close()
+
+	// the key is allowed for the caller only, the nodes connect to each other from localhost
+	key, err := apikeys.NewToken()
+	require.NoError(t, err)
+	require.NoError(t, apiKeysProvider.Create(ctx, &apikeys.APIKey{
+		ID:         "key-1",
+		Username:   "admin",
+		Name:       "ci",
+		KeyHash:    apikeys.Hash(key),
+		CreatedAt:  time.Now(),
+		AllowedIPs: []string{"192.0.2.10"},
+	}))
+
+	connMock := makeConnMock(t, 1, time.Date(2020, 10, 10, 10, 10, 1, 0, time.UTC))
+	localClient := clients.New(t).ID("client-1").Connection(connMock).Build()
+	localClient.Name = "connected-to-node-2"
+	node2 := makeTestClusterNode(t, "node-2", provider, []*clients.Client{localClient})
+	require.NoError(t, node2.service.ClaimClient(ctx, "client-1"))
+	require.NoError(t, node2.service.Heartbeat(ctx))
+	node1 := makeTestClusterNode(t, "node-1", provider, nil)
+	require.NoError(t, node1.service.Heartbeat(ctx))
+	remoteClient := clients.New(t).ID("client-1").Build()
+	remoteClient.Node = "node-2"
+	require.NoError(t, node1.al.clientService.GetRepo().Save(remoteClient))
+
+	for _, node := range []*testClusterNode{node1, node2} {
+		node.al.insecureForTests = false
+		node.al.apiKeys = apiKeysProvider
+		node.al.initRouter()
+	}
+
+	send := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/clients/client-1?fields[clients]=id,name", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Authorization", "Bearer "+key)
+		w := httptest.NewRecorder()
+		node1.al.router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("192.0.2.10:40000")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "connected-to-node-2")
+
+	w = send("198.51.100.1:40000")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -1,17 +1,21 @@
 package chserver
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/cloudradar-monitoring/rport/server/api"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
 	"github.com/cloudradar-monitoring/rport/server/api/jobs"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/approvals"
 	"github.com/cloudradar-monitoring/rport/server/auditlog"
+	"github.com/cloudradar-monitoring/rport/server/clients"
 	"github.com/cloudradar-monitoring/rport/server/routes"
 	"github.com/cloudradar-monitoring/rport/server/validation"
 	"github.com/cloudradar-monitoring/rport/share/comm"
@@ -39,6 +43,8 @@ type jobPayload struct {
 	Result      **jobResult `json:"result,omitempty"`
 	IsSudo      *bool       `json:"is_sudo,omitempty"`
 	IsScript    *bool       `json:"is_script,omitempty"`
+
+	UpdatesInstallation **models.UpdatesInstallation `json:"updates_installation,omitempty"`
 }
 
 type jobResult struct {
@@ -110,6 +116,9 @@ func convertToJobsPayload(jobs []*models.Job, fields []query.FieldsOption) []job
 		if requestedFields["is_script"] {
 			result[i].IsScript = &job.IsScript
 		}
+		if requestedFields["updates_installation"] {
+			result[i].UpdatesInstallation = &job.UpdatesInstallation
+		}
 		if len(requestedResultFields) > 0 {
 			result[i].Result = new(*jobResult)
 			if job.Result != nil {
@@ -152,7 +161,7 @@ func (al *APIListener) handlePostCommand(w http.ResponseWriter, req *http.Reques
 	execCmdInput.ClientID = cid
 	execCmdInput.IsScript = false
 
-	resp := al.handleExecuteCommand(req.Context(), w, execCmdInput)
+	resp := al.handleExecuteCommand(req, w, execCmdInput)
 
 	if resp != nil {
 		al.auditLog.Entry(auditlog.ApplicationClientCommand, auditlog.ActionExecuteStart).
@@ -264,7 +273,7 @@ func (al *APIListener) handleGetCommand(w http.ResponseWriter, req *http.Request
 		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to find a job[id=%q].", jid), err)
 		return
 	}
-	if job == nil {
+	if job == nil || job.ClientID != cid {
 		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("Job[id=%q] not found.", jid))
 		return
 	}
@@ -272,6 +281,150 @@ func (al *APIListener) handleGetCommand(w http.ResponseWriter, req *http.Request
 	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(job))
 }
 
+// handleCancelCommand handles DELETE /clients/{client_id}/commands/{job_id}
+func (al *APIListener) handleCancelCommand(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	cid := vars[routes.ParamClientID]
+	jid := vars[routes.ParamJobID]
+
+	job, err := al.jobProvider.GetByJID(cid, jid)
+	if err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to find a job[id=%q].", jid), err)
+		return
+	}
+	// the job is looked up by its id only, the client access was checked for the client in the path
+	if job == nil || job.ClientID != cid {
+		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("Job[id=%q] not found.", jid))
+		return
+	}
+
+	curUser, err := al.getUserModelForAuth(req.Context())
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if !curUser.IsAdmin() && job.CreatedBy != curUser.Username {
+		al.jsonErrorResponseWithError(w, http.StatusForbidden, "forbidden", fmt.Errorf("you are not allowed to access items created by another user"))
+		return
+	}
+
+	if job.Status != models.JobStatusRunning {
+		al.jsonErrorResponseWithTitle(w, http.StatusConflict, fmt.Sprintf("Job[id=%q] is not running, status: %s.", jid, job.Status))
+		return
+	}
+
+	if err := al.cancelJob(job); err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationClientCommand, auditlog.ActionExecuteCancel).
+		WithHTTPRequest(req).
+		WithClientID(cid).
+		WithID(jid).
+		Save()
+
+	w.WriteHeader(http.StatusNoContent)
+
+	al.Debugf("Job[id=%q] cancellation requested on client with id=%q.", jid, cid)
+}
+
+// handleCancelMultiClientCommand handles DELETE /commands/{job_id}
+func (al *APIListener) handleCancelMultiClientCommand(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	jid := mux.Vars(req)[routes.ParamJobID]
+
+	multiJob, err := al.jobProvider.GetMultiJob(ctx, jid)
+	if err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to find a multi-client job[id=%q].", jid), err)
+		return
+	}
+	if multiJob == nil {
+		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("Multi-client Job[id=%q] not found.", jid))
+		return
+	}
+
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if !curUser.IsAdmin() && multiJob.CreatedBy != curUser.Username {
+		al.jsonErrorResponseWithError(w, http.StatusForbidden, "forbidden", fmt.Errorf("you are not allowed to access items created by another user"))
+		return
+	}
+
+	// stop starting new jobs first, otherwise a job could be started after the running ones are cancelled
+	stopped := al.stopMultiJob(jid)
+
+	var cancelled int
+	var errs []string
+	for _, job := range multiJob.Jobs {
+		if job.Status != models.JobStatusRunning {
+			continue
+		}
+		if err := al.cancelJob(job); err != nil {
+			errs = append(errs, fmt.Sprintf("client_id=%q: %v", job.ClientID, err))
+			continue
+		}
+		cancelled++
+	}
+
+	if !stopped && cancelled == 0 && len(errs) == 0 {
+		al.jsonErrorResponseWithTitle(w, http.StatusConflict, fmt.Sprintf("Multi-client Job[id=%q] is not running.", jid))
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationClientCommand, auditlog.ActionExecuteCancel).
+		WithHTTPRequest(req).
+		WithID(jid).
+		Save()
+
+	if len(errs) > 0 {
+		al.jsonErrorResponseWithDetail(w, http.StatusConflict, "", "Failed to cancel some jobs.", strings.Join(errs, ", "))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+	al.Debugf("Multi-client Job[id=%q] cancelled, cancellation of %d running jobs requested.", jid, cancelled)
+}
+
+// cancelJob asks the client of a running job to terminate it, the client sends the result of the cancelled job when it has exited
+func (al *APIListener) cancelJob(job *models.Job) error {
+	client, err := al.clientService.GetActiveByID(job.ClientID)
+	if err != nil {
+		return errors2.APIError{
+			Err:        err,
+			Message:    fmt.Sprintf("Failed to find an active client with id=%q.", job.ClientID),
+			HTTPStatus: http.StatusInternalServerError,
+		}
+	}
+	if client == nil {
+		return errors2.APIError{
+			Message:    fmt.Sprintf("Active client with id=%q not found.", job.ClientID),
+			HTTPStatus: http.StatusNotFound,
+		}
+	}
+
+	err = comm.SendRequestAndGetResponse(client.Connection, comm.RequestTypeCancelJob, comm.CancelJobRequest{JID: job.JID}, nil)
+	if err != nil {
+		if _, ok := err.(*comm.ClientError); ok {
+			return errors2.APIError{
+				Err:        err,
+				HTTPStatus: http.StatusConflict,
+			}
+		}
+		return errors2.APIError{
+			Err:        err,
+			Message:    "Failed to cancel the job.",
+			HTTPStatus: http.StatusInternalServerError,
+		}
+	}
+
+	return nil
+}
+
 // TODO: refactor to reuse similar code for REST API and WebSocket to execute cmds if both will be supported
 // handlePostMultiClientCommand handles POST /commands
 func (al *APIListener) handlePostMultiClientCommand(w http.ResponseWriter, req *http.Request) {
@@ -311,7 +464,7 @@ func (al *APIListener) handlePostMultiClientCommand(w http.ResponseWriter, req *
 		al.jsonError(w, err)
 		return
 	}
-	err = al.clientService.CheckClientsAccess(reqBody.OrderedClients, curUser, clientGroups)
+	err = al.clientService.CheckClientsAccess(reqBody.OrderedClients, curUser, clientGroups, users.PermissionCommands)
 	if err != nil {
 		al.jsonError(w, err)
 		return
@@ -319,6 +472,16 @@ func (al *APIListener) handlePostMultiClientCommand(w http.ResponseWriter, req *
 
 	reqBody.Username = curUser.Username
 
+	approval, err := al.requestApprovalIfRequired(req, approvals.NewRequest(&reqBody), reqBody.OrderedClients, curUser.Username)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if approval != nil {
+		al.writeJSONResponse(w, http.StatusAccepted, api.NewSuccessPayload(approval))
+		return
+	}
+
 	multiJob, err := al.StartMultiClientJob(ctx, &reqBody)
 	if err != nil {
 		al.jsonError(w, err)
@@ -341,7 +504,8 @@ func (al *APIListener) handlePostMultiClientCommand(w http.ResponseWriter, req *
 	al.Debugf("Multi-client Job[id=%q] created to execute remote command on clients %s, groups %s, tags %s: %q.", multiJob.JID, reqBody.ClientIDs, reqBody.GroupIDs, reqBody.GetClientTags(), reqBody.Command)
 }
 
-func (al *APIListener) handleExecuteCommand(ctx context.Context, w http.ResponseWriter, executeInput *api.ExecuteInput) *newJobResponse {
+func (al *APIListener) handleExecuteCommand(req *http.Request, w http.ResponseWriter, executeInput *api.ExecuteInput) *newJobResponse {
+	ctx := req.Context()
 	if executeInput.Command == "" {
 		al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "Command cannot be empty.")
 		return nil
@@ -365,6 +529,25 @@ func (al *APIListener) handleExecuteCommand(ctx context.Context, w http.Response
 		return nil
 	}
 
+	approval, err := al.requestApprovalIfRequired(req, &approvals.Request{
+		ClientIDs:   []string{executeInput.ClientID},
+		Command:     executeInput.Command,
+		Script:      executeInput.Script,
+		IsScript:    executeInput.IsScript,
+		Cwd:         executeInput.Cwd,
+		IsSudo:      executeInput.IsSudo,
+		Interpreter: executeInput.Interpreter,
+		TimeoutSec:  executeInput.TimeoutSec,
+	}, []*clients.Client{client}, api.GetUser(ctx, al.Logger))
+	if err != nil {
+		al.jsonError(w, err)
+		return nil
+	}
+	if approval != nil {
+		al.writeJSONResponse(w, http.StatusAccepted, api.NewSuccessPayload(approval))
+		return nil
+	}
+
 	// send the command to the client
 	// Send a job with all possible info in order to get the full-populated job back (in client-listener) when it's done.
 	// Needed when server restarts to get all job data from client. Because on server restart job running info is lost.
@@ -373,6 +556,7 @@ func (al *APIListener) handleExecuteCommand(ctx context.Context, w http.Response
 		al.jsonError(w, err)
 		return nil
 	}
+	createdBy := api.GetUser(ctx, al.Logger)
 	curJob := models.Job{
 		JID:         jid,
 		FinishedAt:  nil,
@@ -380,12 +564,14 @@ func (al *APIListener) handleExecuteCommand(ctx context.Context, w http.Response
 		ClientName:  client.Name,
 		Command:     executeInput.Command,
 		Interpreter: executeInput.Interpreter,
-		CreatedBy:   api.GetUser(ctx, al.Logger),
+		CreatedBy:   createdBy,
 		TimeoutSec:  executeInput.TimeoutSec,
 		Result:      nil,
 		Cwd:         executeInput.Cwd,
 		IsSudo:      executeInput.IsSudo,
 		IsScript:    executeInput.IsScript,
+
+		CreatedByGroups: al.getUserGroups(createdBy),
 	}
 	sshResp := &comm.RunCmdResponse{}
 	err = comm.SendRequestAndGetResponse(client.Connection, comm.RequestTypeRunCmd, curJob, sshResp)
@@ -449,7 +635,7 @@ func (al *APIListener) handleCommandsWS(w http.ResponseWriter, req *http.Request
 
 	auditLogEntry := al.auditLog.Entry(auditlog.ApplicationClientCommand, auditlog.ActionExecuteStart).WithHTTPRequest(req)
 
-	al.handleCommandsExecutionWS(ctx, uiConnTS, inboundMsg, auditLogEntry)
+	al.handleCommandsExecutionWS(req, uiConnTS, inboundMsg, auditLogEntry)
 }
 
 // handleGetMultiClientCommand handles GET /commands/{job_id}
@@ -284,7 +284,8 @@ func TestHandlePostCommand(t *testing.T) {
 						},
 					},
 				},
-				Logger: testLog,
+				Logger:      testLog,
+				userService: users.NewAPIService(users.NewStaticProvider([]*users.User{{Username: testUser, Groups: []string{"ops"}}}), false, 0, -1),
 			}
 			al.initRouter()
 
@@ -322,6 +323,7 @@ func TestHandlePostCommand(t *testing.T) {
 				assert.Equal(t, &sshSuccessResp.Pid, gotRunningJob.PID)
 				assert.Equal(t, sshSuccessResp.StartedAt, gotRunningJob.StartedAt)
 				assert.Equal(t, testUser, gotRunningJob.CreatedBy)
+				assert.Equal(t, []string{"ops"}, gotRunningJob.CreatedByGroups)
 				assert.Equal(t, tc.wantTimeout, gotRunningJob.TimeoutSec)
 				assert.Nil(t, gotRunningJob.Result)
 			} else {
@@ -416,6 +418,180 @@ func TestHandleGetCommand(t *testing.T) {
 	}
 }
 
+func TestHandleCancelCommand(t *testing.T) {
+	connMock := test.NewConnMock()
+	c1 := clients.New(t).Connection(connMock).Build()
+	c2 := clients.New(t).DisconnectedDuration(5 * time.Minute).Build()
+
+	testCases := []struct {
+		name string
+
+		user            string
+		clientID        string
+		job             *models.Job
+		connReturnNotOk bool
+
+		wantStatusCode int
+		wantErrTitle   string
+		wantErrDetail  string
+	}{
+		{
+			name:           "running job",
+			job:            jb.New(t).ClientID(c1.ID).JID("jid-1").Status(models.JobStatusRunning).Build(),
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:           "finished job",
+			job:            jb.New(t).ClientID(c1.ID).JID("jid-1").Status(models.JobStatusSuccessful).Build(),
+			wantStatusCode: http.StatusConflict,
+			wantErrTitle:   `Job[id="jid-1"] is not running, status: successful.`,
+		},
+		{
+			name:           "disconnected client",
+			job:            jb.New(t).ClientID(c2.ID).JID("jid-1").Status(models.JobStatusRunning).Build(),
+			wantStatusCode: http.StatusNotFound,
+			wantErrTitle:   fmt.Sprintf("Active client with id=%q not found.", c2.ID),
+		},
+		{
+			name:            "job not running on client",
+			job:             jb.New(t).ClientID(c1.ID).JID("jid-1").Status(models.JobStatusRunning).Build(),
+			connReturnNotOk: true,
+			wantStatusCode:  http.StatusConflict,
+			wantErrTitle:    `client error: job "jid-1" is not running`,
+		},
+		{
+			name:           "running job of user",
+			user:           "test-user",
+			job:            jb.New(t).ClientID(c1.ID).JID("jid-1").Status(models.JobStatusRunning).Build(),
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			name:           "running job of other user",
+			user:           "other-user",
+			job:            jb.New(t).ClientID(c1.ID).JID("jid-1").Status(models.JobStatusRunning).Build(),
+			wantStatusCode: http.StatusForbidden,
+			wantErrTitle:   "forbidden",
+			wantErrDetail:  "you are not allowed to access items created by another user",
+		},
+		{
+			name:           "job of other client",
+			clientID:       c1.ID,
+			job:            jb.New(t).ClientID(c2.ID).JID("jid-1").Status(models.JobStatusRunning).Build(),
+			wantStatusCode: http.StatusNotFound,
+			wantErrTitle:   `Job[id="jid-1"] not found.`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			al := APIListener{
+				insecureForTests: true,
+				Logger:           testLog,
+				Server: &Server{
+					clientService: NewClientService(nil, nil, clients.NewClientRepository([]*clients.Client{c1, c2}, &hour, testLog), testLog),
+					config: &chconfig.Config{
+						Server: chconfig.ServerConfig{MaxRequestBytes: 1024 * 1024},
+					},
+				},
+				userService: users.NewAPIService(users.NewStaticProvider([]*users.User{
+					{Username: "admin", Groups: []string{users.Administrators}},
+					{Username: "test-user"},
+					{Username: "other-user"},
+				}), false, 0, -1),
+			}
+			al.initRouter()
+
+			jp := NewJobProviderMock()
+			jp.ReturnJob = tc.job
+			al.jobProvider = jp
+
+			connMock.ReturnOk = !tc.connReturnNotOk
+			connMock.ReturnResponsePayload = []byte(`job "jid-1" is not running`)
+
+			user := tc.user
+			if user == "" {
+				user = "admin"
+			}
+			clientID := tc.clientID
+			if clientID == "" {
+				clientID = tc.job.ClientID
+			}
+			req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/clients/%s/commands/%s", clientID, tc.job.JID), nil)
+			req = req.WithContext(api.WithUser(req.Context(), user))
+
+			// when
+			w := httptest.NewRecorder()
+			al.router.ServeHTTP(w, req)
+
+			// then
+			assert.Equal(t, tc.wantStatusCode, w.Code)
+			if tc.wantErrTitle == "" {
+				name, _, payload := connMock.InputSendRequest()
+				assert.Equal(t, comm.RequestTypeCancelJob, name)
+				assert.JSONEq(t, `{"JID": "jid-1"}`, string(payload))
+			} else {
+				wantResp := api.NewErrAPIPayloadFromMessage("", tc.wantErrTitle, tc.wantErrDetail)
+				wantRespBytes, err := json.Marshal(wantResp)
+				require.NoError(t, err)
+				assert.Equal(t, string(wantRespBytes), w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleCancelMultiClientCommand(t *testing.T) {
+	ctx := api.WithUser(context.Background(), "admin")
+	connMock := test.NewConnMock()
+	connMock.ReturnOk = true
+	c1 := clients.New(t).Connection(connMock).Build()
+	c2 := clients.New(t).Connection(test.NewConnMock()).Build()
+
+	jp := makeJobsProvider(t, DataSourceOptions, testLog)
+	defer jp.Close()
+	multiJob := &models.MultiJob{
+		MultiJobSummary: models.MultiJobSummary{JID: "multi-jid", CreatedBy: "admin", StartedAt: time.Now()},
+		ClientIDs:       []string{c1.ID, c2.ID},
+		Command:         "/bin/sleep 60",
+	}
+	require.NoError(t, jp.SaveMultiJob(multiJob))
+	require.NoError(t, jp.CreateJob(jb.New(t).ClientID(c1.ID).JID("jid-1").MultiJobID(multiJob.JID).Status(models.JobStatusRunning).Build()))
+	require.NoError(t, jp.CreateJob(jb.New(t).ClientID(c2.ID).JID("jid-2").MultiJobID(multiJob.JID).Status(models.JobStatusSuccessful).Build()))
+
+	al := APIListener{
+		insecureForTests: true,
+		Logger:           testLog,
+		Server: &Server{
+			clientService: NewClientService(nil, nil, clients.NewClientRepository([]*clients.Client{c1, c2}, &hour, testLog), testLog),
+			config: &chconfig.Config{
+				Server: chconfig.ServerConfig{MaxRequestBytes: 1024 * 1024},
+			},
+			jobProvider: jp,
+		},
+		userService: users.NewAPIService(users.NewStaticProvider([]*users.User{{Username: "admin", Groups: []string{users.Administrators}}}), false, 0, -1),
+	}
+	al.initRouter()
+	runningCtx, done := al.trackMultiJob(multiJob.JID)
+	defer done()
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/commands/multi-jid", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	al.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Error(t, runningCtx.Err(), "no more jobs should be started")
+	name, _, payload := connMock.InputSendRequest()
+	assert.Equal(t, comm.RequestTypeCancelJob, name)
+	assert.JSONEq(t, `{"JID": "jid-1"}`, string(payload))
+
+	// nothing is running anymore after the multi-client job has stopped and its jobs are finished
+	done()
+	require.NoError(t, jp.SaveJob(jb.New(t).ClientID(c1.ID).JID("jid-1").MultiJobID(multiJob.JID).Status(models.JobStatusCancelled).Build()))
+	w = httptest.NewRecorder()
+	al.router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/commands/multi-jid", nil).WithContext(ctx))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
 func TestHandleGetCommands(t *testing.T) {
 	ft := time.Date(2020, 10, 10, 10, 10, 10, 0, time.UTC)
 	testCID := "cid-1234"
@@ -0,0 +1,141 @@
+package chserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/auditlog"
+	"github.com/cloudradar-monitoring/rport/server/configprofiles"
+	"github.com/cloudradar-monitoring/rport/server/routes"
+)
+
+// handleGetConfigProfiles handles GET /config-profiles
+func (al *APIListener) handleGetConfigProfiles(w http.ResponseWriter, req *http.Request) {
+	profiles, err := al.configProfiles.GetAll(req.Context())
+	if err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, "Failed to get config profiles.", err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(profiles))
+}
+
+// handleGetConfigProfile handles GET /config-profiles/{profile_id}
+func (al *APIListener) handleGetConfigProfile(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[routes.ParamProfileID]
+
+	profile, err := al.configProfiles.Get(req.Context(), id)
+	if err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to find config profile[id=%q].", id), err)
+		return
+	}
+	if profile == nil {
+		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("Config profile[id=%q] not found.", id))
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(profile))
+}
+
+// handlePostConfigProfiles handles POST /config-profiles
+func (al *APIListener) handlePostConfigProfiles(w http.ResponseWriter, req *http.Request) {
+	var profile configprofiles.Profile
+	err := parseRequestBody(req.Body, &profile)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	if err := profile.Validate(); err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusBadRequest, "Invalid config profile.", err)
+		return
+	}
+
+	existing, err := al.configProfiles.Get(req.Context(), profile.ID)
+	if err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to find config profile[id=%q].", profile.ID), err)
+		return
+	}
+	if existing != nil {
+		al.jsonErrorResponseWithTitle(w, http.StatusConflict, fmt.Sprintf("Config profile[id=%q] already exists.", profile.ID))
+		return
+	}
+
+	if err := al.configProfiles.Save(req.Context(), &profile); err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, "Failed to persist a new config profile.", err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationConfigProfile, auditlog.ActionCreate).
+		WithHTTPRequest(req).
+		WithRequest(profile).
+		WithID(profile.ID).
+		Save()
+
+	go al.pushConfigProfilesToAll(context.Background())
+
+	w.WriteHeader(http.StatusCreated)
+	al.Debugf("Config profile [id=%q] created.", profile.ID)
+}
+
+// handlePutConfigProfile handles PUT /config-profiles/{profile_id}
+func (al *APIListener) handlePutConfigProfile(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[routes.ParamProfileID]
+
+	var profile configprofiles.Profile
+	err := parseRequestBody(req.Body, &profile)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	if id != profile.ID {
+		al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, fmt.Sprintf("%q route param doesn't not match profile ID from request body.", routes.ParamProfileID))
+		return
+	}
+
+	if err := profile.Validate(); err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusBadRequest, "Invalid config profile.", err)
+		return
+	}
+
+	if err := al.configProfiles.Save(req.Context(), &profile); err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, "Failed to persist config profile.", err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationConfigProfile, auditlog.ActionUpdate).
+		WithHTTPRequest(req).
+		WithRequest(profile).
+		WithID(id).
+		Save()
+
+	go al.pushConfigProfilesToAll(context.Background())
+
+	w.WriteHeader(http.StatusNoContent)
+	al.Debugf("Config profile [id=%q] updated.", id)
+}
+
+// handleDeleteConfigProfile handles DELETE /config-profiles/{profile_id}
+func (al *APIListener) handleDeleteConfigProfile(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[routes.ParamProfileID]
+
+	if err := al.configProfiles.Delete(req.Context(), id); err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to delete config profile[id=%q].", id), err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationConfigProfile, auditlog.ActionDelete).
+		WithHTTPRequest(req).
+		WithID(id).
+		Save()
+
+	go al.pushConfigProfilesToAll(context.Background())
+
+	w.WriteHeader(http.StatusNoContent)
+	al.Debugf("Config profile [id=%q] deleted.", id)
+}
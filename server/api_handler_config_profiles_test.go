@@ -0,0 +1,129 @@
+package chserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/cgroups"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/configprofiles"
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+func TestHandleConfigProfiles(t *testing.T) {
+	curUser := makeTestUser("admin")
+	al := makeAPIListener(curUser, clients.NewClientRepository(nil, &hour, testLog), 60, testLog)
+	profiles, err := configprofiles.NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	defer profiles.Close()
+	al.configProfiles = profiles
+	al.initRouter()
+
+	ctx := api.WithUser(context.Background(), curUser.Username)
+	send := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body)).WithContext(ctx)
+		w := httptest.NewRecorder()
+		al.router.ServeHTTP(w, req)
+		return w
+	}
+	profile := `{"id":"linux","description":"","client_group_ids":["linux"],"options":{"remote-commands.allow":["^/usr/bin/.*"]}}`
+
+	w := send(http.MethodPost, "/api/v1/config-profiles", profile)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = send(http.MethodPost, "/api/v1/config-profiles", profile)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = send(http.MethodPost, "/api/v1/config-profiles", `{"id":"invalid","options":{"client.server":"example.com"}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `option \"client.server\" can not be managed`)
+
+	w = send(http.MethodGet, "/api/v1/config-profiles/linux", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":`+profile+`}`, w.Body.String())
+
+	updated := `{"id":"linux","description":"linux clients","client_group_ids":["linux"],"options":{"monitoring.enabled":false}}`
+	w = send(http.MethodPut, "/api/v1/config-profiles/linux", updated)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = send(http.MethodGet, "/api/v1/config-profiles", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[`+updated+`]}`, w.Body.String())
+
+	w = send(http.MethodDelete, "/api/v1/config-profiles/linux", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = send(http.MethodGet, "/api/v1/config-profiles/linux", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPushConfigProfiles(t *testing.T) {
+	ctx := context.Background()
+	effective := &clientconfig.Config{
+		Client: clientconfig.ClientConfig{Tags: []string{"managed"}},
+		ManagedConfig: clientconfig.ManagedConfig{
+			Enabled:  true,
+			Profiles: []string{"linux"},
+			Applied:  []string{"client.tags"},
+		},
+	}
+	response, err := json.Marshal(effective)
+	require.NoError(t, err)
+	connMock1 := test.NewConnMock()
+	connMock1.ReturnOk = true
+	connMock1.ReturnResponsePayload = response
+	connMock2 := test.NewConnMock()
+	c1 := clients.New(t).ID("client-1").Connection(connMock1).Config(&clientconfig.Config{
+		ManagedConfig: clientconfig.ManagedConfig{Enabled: true},
+	}).Build()
+	c1.OSKernel = "linux"
+	// managed config is disabled on the client
+	c2 := clients.New(t).ID("client-2").Connection(connMock2).Build()
+	c2.OSKernel = "linux"
+
+	al := makeAPIListener(makeTestUser("admin"), clients.NewClientRepository([]*clients.Client{c1, c2}, &hour, testLog), 60, testLog)
+	gp := makeGroupsProvider(t, DataSourceOptions)
+	defer gp.Close()
+	require.NoError(t, gp.Create(ctx, &cgroups.ClientGroup{
+		ID:     "linux",
+		Params: &cgroups.ClientParams{OSKernel: &cgroups.ParamValues{"linux"}},
+	}))
+	al.clientGroupProvider = gp
+	profiles, err := configprofiles.NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	defer profiles.Close()
+	require.NoError(t, profiles.Save(ctx, &configprofiles.Profile{
+		ID:             "linux",
+		ClientGroupIDs: []string{"linux"},
+		Options:        clientconfig.ManagedOptions{"client.tags": []interface{}{"managed"}},
+	}))
+	require.NoError(t, profiles.Save(ctx, &configprofiles.Profile{
+		ID:             "windows",
+		ClientGroupIDs: []string{"windows"},
+		Options:        clientconfig.ManagedOptions{"monitoring.enabled": false},
+	}))
+	al.configProfiles = profiles
+	al.Server.Logger = testLog
+
+	al.pushConfigProfilesToAll(ctx)
+
+	name, _, payload := connMock1.InputSendRequest()
+	assert.Equal(t, comm.RequestTypePutManagedConfig, name)
+	assert.JSONEq(t, `{"Profiles":["linux"],"Options":{"client.tags":["managed"]}}`, string(payload))
+	assert.Equal(t, effective, c1.ClientConfiguration)
+	assert.Equal(t, []string{"managed"}, c1.Tags)
+
+	name, _, _ = connMock2.InputSendRequest()
+	assert.Empty(t, name)
+}
@@ -0,0 +1,79 @@
+package chserver
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/routes"
+	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+// handleGetClientInventory handles GET /clients/{client_id}/inventory
+func (al *APIListener) handleGetClientInventory(w http.ResponseWriter, req *http.Request) {
+	clientID := mux.Vars(req)[routes.ParamClientID]
+
+	inventory, err := al.inventoryService.Get(req.Context(), clientID)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(inventory))
+}
+
+// handleListClientInventoryChanges handles GET /clients/{client_id}/inventory/changes
+func (al *APIListener) handleListClientInventoryChanges(w http.ResponseWriter, req *http.Request) {
+	clientID := mux.Vars(req)[routes.ParamClientID]
+
+	options := query.GetListOptions(req)
+	options.Filters = append(options.Filters, query.FilterOption{
+		Column: []string{"client_id"},
+		Values: []string{clientID},
+	})
+
+	result, err := al.inventoryService.ListChanges(req.Context(), options)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// handleListInventory handles GET /inventory
+func (al *APIListener) handleListInventory(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	options := query.GetListOptions(req)
+
+	// non-admin users see only the inventory of the clients they have access to
+	hasClients, err := al.filterByUserClients(ctx, curUser, users.PermissionMonitoring, options)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if !hasClients {
+		al.writeJSONResponse(w, http.StatusOK, &api.SuccessPayload{
+			Data: []*models.InventoryItem{},
+			Meta: api.NewMeta(0),
+		})
+		return
+	}
+
+	result, err := al.inventoryService.ListItems(ctx, options)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, result)
+}
@@ -0,0 +1,111 @@
+package chserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/inventory"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+func TestHandleInventory(t *testing.T) {
+	ctx := context.Background()
+	c1 := clients.New(t).ID("client-1").Build()
+	c2 := clients.New(t).ID("client-2").Build()
+
+	provider, err := inventory.NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { provider.Close() })
+	service := inventory.NewService(provider)
+
+	refreshed := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, service.Save(ctx, c1.ID, &models.Inventory{
+		Refreshed: refreshed,
+		Packages:  []models.InventoryPackage{{Name: "openssl", Version: "1.1.1"}},
+	}))
+	require.NoError(t, service.Save(ctx, c1.ID, &models.Inventory{
+		Refreshed: refreshed.Add(time.Hour),
+		Packages:  []models.InventoryPackage{{Name: "openssl", Version: "3.0.2"}},
+	}))
+	require.NoError(t, service.Save(ctx, c2.ID, &models.Inventory{
+		Refreshed: refreshed,
+		Packages:  []models.InventoryPackage{{Name: "bash", Version: "5.1"}},
+	}))
+
+	al := APIListener{
+		insecureForTests: true,
+		Server: &Server{
+			config:           &chconfig.Config{},
+			clientService:    NewClientService(nil, nil, clients.NewClientRepository([]*clients.Client{c1, c2}, &hour, testLog), testLog),
+			inventoryService: service,
+		},
+		userService: users.NewAPIService(users.NewStaticProvider([]*users.User{{Username: "admin", Groups: []string{users.Administrators}}}), false, 0, -1),
+	}
+	al.initRouter()
+
+	serve := func(url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		al.router.ServeHTTP(w, req.WithContext(api.WithUser(req.Context(), "admin")))
+		return w
+	}
+
+	t.Run("client inventory", func(t *testing.T) {
+		w := serve("/api/v1/clients/client-1/inventory")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		result := struct {
+			Data *models.Inventory `json:"data"`
+		}{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, []models.InventoryPackage{{Name: "openssl", Version: "3.0.2"}}, result.Data.Packages)
+		assert.Equal(t, refreshed.Add(time.Hour), result.Data.Refreshed)
+	})
+
+	t.Run("client inventory changes", func(t *testing.T) {
+		w := serve("/api/v1/clients/client-1/inventory/changes?filter[package]=openssl")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		result := struct {
+			Data []*inventory.Change `json:"data"`
+		}{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		assert.Equal(t, []*inventory.Change{{
+			Timestamp: refreshed.Add(time.Hour),
+			ClientID:  c1.ID,
+			Category:  models.InventoryCategoryPackage,
+			Name:      "openssl",
+			Action:    inventory.ActionChanged,
+			OldValue:  "1.1.1",
+			NewValue:  "3.0.2",
+		}}, result.Data)
+	})
+
+	t.Run("inventory items", func(t *testing.T) {
+		w := serve("/api/v1/inventory?filter[package]=bash")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"data": [{"client_id": "client-2", "category": "package", "name": "bash", "value": "5.1"}],
+			"meta": {"count": 1}
+		}`, w.Body.String())
+	})
+
+	t.Run("unsupported filter", func(t *testing.T) {
+		w := serve("/api/v1/inventory?filter[version]=5.1")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -11,6 +11,7 @@ import (
 	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
 	"github.com/cloudradar-monitoring/rport/server/api/users"
 	"github.com/cloudradar-monitoring/rport/server/bearer"
+	"github.com/cloudradar-monitoring/rport/server/webhooks"
 
 	chshare "github.com/cloudradar-monitoring/rport/share"
 	"github.com/cloudradar-monitoring/rport/share/logger"
@@ -75,6 +76,7 @@ func (al *APIListener) handleLogin(username, pwd string, newpwd string, skipPass
 
 	if !authorized {
 		al.bannedUsers.Add(username)
+		al.onLoginFailed(req, username)
 		al.jsonErrorResponseWithTitle(w, http.StatusUnauthorized, "unauthorized")
 		return
 	}
@@ -202,6 +204,15 @@ func (al *APIListener) handleLogin(username, pwd string, newpwd string, skipPass
 	al.writeJSONResponse(w, http.StatusOK, response)
 }
 
+// onLoginFailed notifies the webhooks about a user that failed to log in with a username and password, either
+// via the login endpoint or via basic auth
+func (al *APIListener) onLoginFailed(req *http.Request, username string) {
+	al.webhooksService.Dispatch(webhooks.EventLoginFailed, webhooks.UserEvent{
+		Username: username,
+		RemoteIP: chshare.RemoteIP(req),
+	})
+}
+
 func (al *APIListener) sendJWTToken(username string, w http.ResponseWriter, req *http.Request) {
 	lifetime, err := parseTokenLifetime(req)
 	if err != nil {
@@ -176,7 +176,12 @@ func (al *APIListener) handleChangeMe(w http.ResponseWriter, req *http.Request)
 			return
 		}
 
-		if !verifyPassword(curUser.Password, r.OldPassword) {
+		passwordOk, err := al.verifyUserPassword(curUser, r.OldPassword)
+		if err != nil {
+			al.jsonError(w, err)
+			return
+		}
+		if !passwordOk {
 			al.jsonErrorResponseWithTitle(w, http.StatusForbidden, "Incorrect old password.")
 			return
 		}
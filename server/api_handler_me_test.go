@@ -56,6 +56,7 @@ func TestHandleMeStaticAuth(t *testing.T) {
 				"monitoring": true,
 				"scheduler": true,
 				"scripts": true,
+				"terminal": true,
 				"tunnels": true,
 				"uploads": true,
 				"vault": true
@@ -137,6 +138,7 @@ func TestHandleMeDBAuth(t *testing.T) {
 				"monitoring": true,
 				"scheduler": false,
 				"scripts": false,
+				"terminal": false,
 				"tunnels": false,
 				"uploads": false,
 				"vault": true
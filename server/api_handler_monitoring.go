@@ -3,10 +3,13 @@ package chserver
 import (
 	"database/sql"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/gorilla/mux"
 
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/cgroups"
 	"github.com/cloudradar-monitoring/rport/server/monitoring"
 	"github.com/cloudradar-monitoring/rport/server/routes"
 	"github.com/cloudradar-monitoring/rport/share/comm"
@@ -79,8 +82,12 @@ func (al *APIListener) handleGetClientGraphMetrics(w http.ResponseWriter, req *h
 	requestInfo := query.ParseRequestInfo(req)
 	netLan := client.ClientConfiguration.Monitoring.LanCard != nil
 	netWan := client.ClientConfiguration.Monitoring.WanCard != nil
+	checks := make([]string, 0, len(client.ClientConfiguration.Monitoring.Checks))
+	for _, check := range client.ClientConfiguration.Monitoring.Checks {
+		checks = append(checks, check.Name)
+	}
 
-	payload, err := al.monitoringService.ListClientGraphMetrics(req.Context(), clientID, queryOptions, requestInfo, netLan, netWan)
+	payload, err := al.monitoringService.ListClientGraphMetrics(req.Context(), clientID, queryOptions, requestInfo, netLan, netWan, checks)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("graph-metrics for client with id %q not found", clientID))
@@ -159,3 +166,91 @@ func (al *APIListener) handleGetClientMountpoints(w http.ResponseWriter, req *ht
 	}
 	al.writeJSONResponse(w, http.StatusOK, payload)
 }
+
+// handleGetClientChecks handles GET /clients/{client_id}/checks
+func (al *APIListener) handleGetClientChecks(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	clientID := vars[routes.ParamClientID]
+
+	queryOptions := query.NewOptions(req, monitoring.ClientChecksSortDefault, monitoring.ClientChecksFilterDefault, monitoring.ClientChecksFieldsDefault)
+
+	payload, err := al.monitoringService.ListClientChecks(req.Context(), clientID, queryOptions)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	al.writeJSONResponse(w, http.StatusOK, payload)
+}
+
+// handleExportClientMeasurements handles GET /clients/{client_id}/measurements/export
+func (al *APIListener) handleExportClientMeasurements(w http.ResponseWriter, req *http.Request) {
+	clientID := mux.Vars(req)[routes.ParamClientID]
+
+	al.exportMeasurements(w, req, []string{clientID}, clientID)
+}
+
+// handleExportClientGroupMeasurements handles GET /client-groups/{group_id}/measurements/export
+func (al *APIListener) handleExportClientGroupMeasurements(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	id := mux.Vars(req)[routes.ParamGroupID]
+
+	group, err := al.clientGroupProvider.Get(ctx, id)
+	if err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to find client group[id=%q].", id), err)
+		return
+	}
+	if group == nil {
+		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("Client Group[id=%q] not found.", id))
+		return
+	}
+
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	allGroups, err := al.clientGroupProvider.GetAll(ctx)
+	if err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, "Failed to get client groups.", err)
+		return
+	}
+
+	// only the clients of the group the user has access to and the monitoring permission for are exported
+	al.clientService.PopulateGroupsWithUserClients([]*cgroups.ClientGroup{group}, allGroups, curUser, users.PermissionMonitoring)
+
+	al.exportMeasurements(w, req, group.ClientIDs, "group-"+id)
+}
+
+func (al *APIListener) exportMeasurements(w http.ResponseWriter, req *http.Request, clientIDs []string, name string) {
+	format := monitoring.ExportFormat(req.URL.Query().Get("format"))
+	if format == "" {
+		format = monitoring.ExportFormatCSV
+	}
+
+	w.Header().Set("Content-Type", monitoring.ExportContentTypes[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "measurements-"+name+monitoring.ExportFileExtensions[format]))
+
+	cw := &countingWriter{w: w}
+	err := al.monitoringService.ExportMeasurements(req.Context(), cw, clientIDs, format, query.GetListOptions(req))
+	if err != nil {
+		if cw.n > 0 {
+			// the response is already on its way, it can only be cut off
+			al.Errorf("Failed to export measurements: %v", err)
+			return
+		}
+		w.Header().Del("Content-Disposition")
+		al.jsonError(w, err)
+	}
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
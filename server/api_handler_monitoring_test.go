@@ -1,18 +1,25 @@
 package chserver
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/cgroups"
 	"github.com/cloudradar-monitoring/rport/server/chconfig"
 	"github.com/cloudradar-monitoring/rport/server/clients"
 	"github.com/cloudradar-monitoring/rport/server/monitoring"
 	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/query"
 	"github.com/cloudradar-monitoring/rport/share/test"
 )
 
@@ -172,3 +179,71 @@ func TestListClientMetrics(t *testing.T) {
 		})
 	}
 }
+
+type exportMonitoringServiceMock struct {
+	monitoring.Service
+	clientIDs []string
+	format    monitoring.ExportFormat
+}
+
+func (m *exportMonitoringServiceMock) ExportMeasurements(ctx context.Context, w io.Writer, clientIDs []string, format monitoring.ExportFormat, options *query.ListOptions) error {
+	m.clientIDs = clientIDs
+	m.format = format
+	_, err := io.WriteString(w, "exported")
+	return err
+}
+
+type exportClientGroupProvider struct {
+	cgroups.ClientGroupProvider
+	group *cgroups.ClientGroup
+}
+
+func (p exportClientGroupProvider) Get(ctx context.Context, id string) (*cgroups.ClientGroup, error) {
+	if id != p.group.ID {
+		return nil, nil
+	}
+	group := *p.group
+	return &group, nil
+}
+
+func (p exportClientGroupProvider) GetAll(ctx context.Context) ([]*cgroups.ClientGroup, error) {
+	return []*cgroups.ClientGroup{p.group}, nil
+}
+
+func TestHandleExportClientGroupMeasurements(t *testing.T) {
+	c1 := clients.New(t).ID("client-1").AllowedUserGroups([]string{"ops"}).Build()
+	c2 := clients.New(t).ID("client-2").Build()
+	monitoringService := &exportMonitoringServiceMock{}
+	al := APIListener{
+		Server: &Server{
+			config:            &chconfig.Config{},
+			clientService:     NewClientService(nil, nil, clients.NewClientRepository([]*clients.Client{c1, c2}, &hour, testLog), testLog),
+			monitoringService: monitoringService,
+			clientGroupProvider: exportClientGroupProvider{group: &cgroups.ClientGroup{
+				ID:     "group-1",
+				Params: &cgroups.ClientParams{ClientID: &cgroups.ParamValues{"client-*"}},
+			}},
+		},
+		userService: users.NewAPIService(users.NewStaticProvider([]*users.User{{Username: "user", Groups: []string{"ops"}}}), false, 0, -1),
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/client-groups/{group_id}/measurements/export", al.handleExportClientGroupMeasurements)
+
+	req := httptest.NewRequest(http.MethodGet, "/client-groups/group-1/measurements/export?format=influx", nil)
+	req = req.WithContext(api.WithUser(req.Context(), "user"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "exported", w.Body.String())
+	assert.Equal(t, `attachment; filename="measurements-group-group-1.lp"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, []string{"client-1"}, monitoringService.clientIDs)
+	assert.Equal(t, monitoring.ExportFormatInflux, monitoringService.format)
+
+	req = httptest.NewRequest(http.MethodGet, "/client-groups/unknown/measurements/export", nil)
+	req = req.WithContext(api.WithUser(req.Context(), "user"))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,151 @@
+package chserver
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/metrics"
+	chshare "github.com/cloudradar-monitoring/rport/share"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+// handleGetPrometheusMetrics handles GET /metrics
+func (al *APIListener) handleGetPrometheusMetrics(w http.ResponseWriter, req *http.Request) {
+	families, err := al.collectPrometheusMetrics(req.Context())
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", metrics.ContentType)
+	w.WriteHeader(http.StatusOK)
+	if err := metrics.Write(w, families...); err != nil {
+		al.Errorf("Failed to write metrics: %v", err)
+	}
+}
+
+func (al *APIListener) collectPrometheusMetrics(ctx context.Context) ([]*metrics.Family, error) {
+	allClients, err := al.clientService.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(allClients, func(i, j int) bool {
+		return allClients[i].ID < allClients[j].ID
+	})
+
+	var (
+		clientsTotal       = metrics.NewGauge("rport_clients", "Number of clients by connection state.")
+		clientConnected    = metrics.NewGauge("rport_client_connected", "Whether the client is connected (1) or not (0).")
+		clientCPU          = metrics.NewGauge("rport_client_cpu_usage_percent", "CPU usage of the client from the latest measurement.")
+		clientMemory       = metrics.NewGauge("rport_client_memory_usage_percent", "Memory usage of the client from the latest measurement.")
+		clientIO           = metrics.NewGauge("rport_client_io_usage_percent", "IO usage of the client from the latest measurement.")
+		clientNet          = metrics.NewGauge("rport_client_net_bytes_per_second", "Network throughput of the client from the latest measurement.")
+		clientMeasuredAt   = metrics.NewGauge("rport_client_measurement_timestamp_seconds", "Unix time of the latest measurement of the client.")
+		clientTunnels      = metrics.NewGauge("rport_client_tunnels", "Number of active tunnels of the client.")
+		tunnelConnsOpen    = metrics.NewGauge("rport_tunnel_connections_open", "Number of open connections through the tunnel.")
+		tunnelConnsTotal   = metrics.NewCounter("rport_tunnel_connections_total", "Number of connections through the tunnel since it was created.")
+		tunnelSent         = metrics.NewCounter("rport_tunnel_sent_bytes_total", "Bytes sent to the client through the tunnel.")
+		tunnelReceived     = metrics.NewCounter("rport_tunnel_received_bytes_total", "Bytes received from the client through the tunnel.")
+		connected, offline int
+	)
+
+	for _, c := range allClients {
+		isConnected := c.CalculateConnectionState() == clients.Connected
+		if !isConnected {
+			offline++
+			clientConnected.Add(0, "client_id", c.ID, "client_name", c.Name)
+			continue
+		}
+		connected++
+		clientConnected.Add(1, "client_id", c.ID, "client_name", c.Name)
+
+		if m := al.monitoringService.LatestMeasurement(c.ID); m != nil {
+			clientCPU.Add(m.CPUUsagePercent, "client_id", c.ID, "client_name", c.Name)
+			clientMemory.Add(m.MemoryUsagePercent, "client_id", c.ID, "client_name", c.Name)
+			clientIO.Add(m.IoUsagePercent, "client_id", c.ID, "client_name", c.Name)
+			clientMeasuredAt.Add(float64(m.Timestamp.Unix()), "client_id", c.ID, "client_name", c.Name)
+			addNetBytes(clientNet, c, "lan", m.NetLan)
+			addNetBytes(clientNet, c, "wan", m.NetWan)
+		}
+
+		clientTunnels.Add(float64(len(c.Tunnels)), "client_id", c.ID, "client_name", c.Name)
+		for _, t := range c.Tunnels {
+			stats := t.Stats()
+			labels := []string{"client_id", c.ID, "tunnel_id", t.ID, "protocol", t.Protocol}
+			tunnelConnsOpen.Add(float64(stats.Open), labels...)
+			tunnelConnsTotal.Add(float64(stats.Total), labels...)
+			tunnelSent.Add(float64(stats.BytesSent), labels...)
+			tunnelReceived.Add(float64(stats.BytesReceived), labels...)
+		}
+	}
+	clientsTotal.Add(float64(connected), "state", string(clients.Connected))
+	clientsTotal.Add(float64(offline), "state", string(clients.Disconnected))
+
+	jobs := metrics.NewGauge("rport_jobs", "Number of stored jobs by status.")
+	jobCounts, err := al.jobProvider.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range []string{models.JobStatusRunning, models.JobStatusSuccessful, models.JobStatusFailed, models.JobStatusUnknown} {
+		jobs.Add(float64(jobCounts[status]), "status", status)
+	}
+
+	result := []*metrics.Family{
+		clientsTotal,
+		clientConnected,
+		clientCPU,
+		clientMemory,
+		clientIO,
+		clientNet,
+		clientMeasuredAt,
+		clientTunnels,
+		tunnelConnsOpen,
+		tunnelConnsTotal,
+		tunnelSent,
+		tunnelReceived,
+		jobs,
+	}
+	result = append(result, serverConnectionMetrics(al.clientListener.connStats.Snapshot())...)
+	result = append(result, runtimeMetrics(chshare.ReadRuntimeStats())...)
+
+	return result, nil
+}
+
+func addNetBytes(f *metrics.Family, c *clients.Client, iface string, netBytes *models.NetBytes) {
+	if netBytes == nil {
+		return
+	}
+	f.Add(float64(netBytes.In), "client_id", c.ID, "client_name", c.Name, "interface", iface, "direction", "in")
+	f.Add(float64(netBytes.Out), "client_id", c.ID, "client_name", c.Name, "interface", iface, "direction", "out")
+}
+
+// serverConnectionMetrics returns metrics of the connections opened by clients to the server side, e.g. reverse tunnels
+func serverConnectionMetrics(stats chshare.ConnStatsSnapshot) []*metrics.Family {
+	open := metrics.NewGauge("rport_server_connections_open", "Number of open connections initiated by clients.")
+	open.Add(float64(stats.Open))
+	total := metrics.NewCounter("rport_server_connections_total", "Number of connections initiated by clients since server start.")
+	total.Add(float64(stats.Total))
+	sent := metrics.NewCounter("rport_server_connections_sent_bytes_total", "Bytes sent by clients through connections initiated by clients.")
+	sent.Add(float64(stats.BytesSent))
+	received := metrics.NewCounter("rport_server_connections_received_bytes_total", "Bytes received by clients through connections initiated by clients.")
+	received.Add(float64(stats.BytesReceived))
+
+	return []*metrics.Family{open, total, sent, received}
+}
+
+func runtimeMetrics(stats chshare.RuntimeStats) []*metrics.Family {
+	goroutines := metrics.NewGauge("go_goroutines", "Number of goroutines that currently exist.")
+	goroutines.Add(float64(stats.Goroutines))
+	alloc := metrics.NewGauge("go_memstats_alloc_bytes", "Number of bytes allocated and still in use.")
+	alloc.Add(float64(stats.HeapAllocBytes))
+	sys := metrics.NewGauge("go_memstats_sys_bytes", "Number of bytes obtained from system.")
+	sys.Add(float64(stats.SysBytes))
+	gcCycles := metrics.NewCounter("go_gc_cycles_total", "Number of completed GC cycles.")
+	gcCycles.Add(float64(stats.NumGC))
+	gcPause := metrics.NewCounter("go_gc_pause_seconds_total", "Total time spent in GC pauses.")
+	gcPause.Add(stats.GCPauseTotal.Seconds())
+
+	return []*metrics.Family{goroutines, alloc, sys, gcCycles, gcPause}
+}
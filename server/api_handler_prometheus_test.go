@@ -0,0 +1,151 @@
+package chserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/migration/jobs"
+	"github.com/cloudradar-monitoring/rport/db/sqlite"
+	jobsprovider "github.com/cloudradar-monitoring/rport/server/api/jobs"
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/metrics"
+	"github.com/cloudradar-monitoring/rport/server/monitoring"
+	chshare "github.com/cloudradar-monitoring/rport/share"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+func TestHandleGetPrometheusMetrics(t *testing.T) {
+	c1 := clients.New(t).ID("client-1").Build()
+	c2 := clients.New(t).ID("client-2").DisconnectedDuration(5 * time.Minute).Build()
+	for _, tunnel := range c1.Tunnels {
+		tunnel.TunnelProtocol = &tunnelProtocolStub{stats: chshare.ConnStatsSnapshot{Open: 1, Total: 3, BytesSent: 1024, BytesReceived: 2048}}
+	}
+	clientService := NewClientService(nil, nil, clients.NewClientRepository([]*clients.Client{c1, c2}, &hour, testLog), testLog)
+
+	monitoringService := monitoring.NewService(&monitoring.DBProviderMock{})
+	err := monitoringService.SaveMeasurement(context.Background(), &models.Measurement{
+		ClientID:           c1.ID,
+		Timestamp:          time.Date(2021, time.September, 1, 0, 0, 0, 0, time.UTC),
+		CPUUsagePercent:    12.5,
+		MemoryUsagePercent: 40,
+		IoUsagePercent:     1,
+		NetLan:             &models.NetBytes{In: 100, Out: 200},
+	})
+	require.NoError(t, err)
+
+	jobsDB, err := sqlite.New(":memory:", jobs.AssetNames(), jobs.Asset, DataSourceOptions)
+	require.NoError(t, err)
+	jp := jobsprovider.NewSqliteProvider(jobsDB, testLog)
+	defer jp.Close()
+
+	al := APIListener{
+		Server: &Server{
+			config: &chconfig.Config{
+				API: chconfig.APIConfig{
+					MetricsToken: "metrics-secret",
+				},
+			},
+			clientService:     clientService,
+			monitoringService: monitoringService,
+			jobProvider:       jp,
+			clientListener:    &ClientListener{},
+		},
+		Logger: testLog,
+	}
+	al.initRouter()
+
+	testCases := []struct {
+		Name           string
+		Token          string
+		ExpectedStatus int
+	}{
+		{
+			Name:           "valid token",
+			Token:          "metrics-secret",
+			ExpectedStatus: http.StatusOK,
+		},
+		{
+			Name:           "invalid token",
+			Token:          "wrong",
+			ExpectedStatus: http.StatusUnauthorized,
+		},
+		{
+			Name:           "no token",
+			ExpectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/metrics", nil)
+			if tc.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.Token)
+			}
+
+			w := httptest.NewRecorder()
+			al.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.ExpectedStatus, w.Code)
+			if tc.ExpectedStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, metrics.ContentType, w.Header().Get("Content-Type"))
+			body := w.Body.String()
+			assert.Contains(t, body, `rport_clients{state="connected"} 1`)
+			assert.Contains(t, body, `rport_clients{state="disconnected"} 1`)
+			assert.Contains(t, body, `rport_client_connected{client_id="client-2",client_name="`+c2.Name+`"} 0`)
+			assert.Contains(t, body, `rport_client_cpu_usage_percent{client_id="client-1",client_name="`+c1.Name+`"} 12.5`)
+			assert.Contains(t, body, `rport_client_net_bytes_per_second{client_id="client-1",client_name="`+c1.Name+`",interface="lan",direction="out"} 200`)
+			assert.Contains(t, body, `rport_client_tunnels{client_id="client-1",client_name="`+c1.Name+`"} 2`)
+			assert.Contains(t, body, `rport_tunnel_sent_bytes_total{client_id="client-1",tunnel_id="1",protocol="tcp"} 1024`)
+			assert.Contains(t, body, `rport_tunnel_received_bytes_total{client_id="client-1",tunnel_id="2",protocol="tcp"} 2048`)
+			assert.Contains(t, body, `rport_jobs{status="running"} 0`)
+			assert.Contains(t, body, `# TYPE go_goroutines gauge`)
+		})
+	}
+}
+
+func TestPrometheusMetricsDisabledWithoutToken(t *testing.T) {
+	al := APIListener{
+		Server: &Server{
+			config: &chconfig.Config{},
+		},
+		Logger: testLog,
+	}
+	al.initRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/metrics", nil)
+	w := httptest.NewRecorder()
+	al.router.ServeHTTP(w, req)
+
+	assert.NotEqual(t, http.StatusOK, w.Code)
+}
+
+type tunnelProtocolStub struct {
+	stats chshare.ConnStatsSnapshot
+}
+
+func (s *tunnelProtocolStub) Start(context.Context) error {
+	return nil
+}
+
+func (s *tunnelProtocolStub) Terminate(bool) error {
+	return nil
+}
+
+func (s *tunnelProtocolStub) LastActive() time.Time {
+	return time.Time{}
+}
+
+func (s *tunnelProtocolStub) Stats() chshare.ConnStatsSnapshot {
+	return s.stats
+}
@@ -0,0 +1,148 @@
+package chserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/auditlog"
+	"github.com/cloudradar-monitoring/rport/server/recordings"
+	"github.com/cloudradar-monitoring/rport/server/routes"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+var recordingContentTypes = map[recordings.Format]string{
+	recordings.FormatAsciicast:  "application/x-asciicast",
+	recordings.FormatGuacamole:  "application/octet-stream",
+	recordings.FormatWebsockify: "application/javascript",
+	recordings.FormatHTTPLog:    "application/x-ndjson",
+}
+
+// handleListRecordings handles GET /recordings
+func (al *APIListener) handleListRecordings(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	options := query.GetListOptions(req)
+
+	// non-admin users see only recordings of the clients they have access to
+	hasClients, err := al.filterByUserClients(ctx, curUser, users.PermissionsAuditLog, options)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if !hasClients {
+		al.writeJSONResponse(w, http.StatusOK, &api.SuccessPayload{
+			Data: []*recordings.Recording{},
+			Meta: api.NewMeta(0),
+		})
+		return
+	}
+
+	result, err := al.recordingsService.ListRecordings(ctx, options)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// handleGetRecording handles GET /recordings/{recording_id}
+func (al *APIListener) handleGetRecording(w http.ResponseWriter, req *http.Request) {
+	recording, ok := al.getRecordingWithAccess(w, req)
+	if !ok {
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(recording))
+}
+
+// handleDownloadRecording handles GET /recordings/{recording_id}/download
+func (al *APIListener) handleDownloadRecording(w http.ResponseWriter, req *http.Request) {
+	recording, ok := al.getRecordingWithAccess(w, req)
+	if !ok {
+		return
+	}
+
+	file, err := al.recordingsService.Open(recording)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", recordingContentTypes[recording.Format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", recording.Filename))
+	http.ServeContent(w, req, recording.Filename, stat.ModTime(), file)
+}
+
+// handleDeleteRecording handles DELETE /recordings/{recording_id}
+func (al *APIListener) handleDeleteRecording(w http.ResponseWriter, req *http.Request) {
+	recordingID := mux.Vars(req)[routes.ParamRecordingID]
+
+	err := al.recordingsService.Delete(req.Context(), recordingID)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationRecording, auditlog.ActionDelete).
+		WithHTTPRequest(req).
+		WithID(recordingID).
+		Save()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getRecordingWithAccess writes an error response and returns false if the recording doesn't exist or
+// the current user has no access to the client of it.
+func (al *APIListener) getRecordingWithAccess(w http.ResponseWriter, req *http.Request) (*recordings.Recording, bool) {
+	ctx := req.Context()
+	recordingID := mux.Vars(req)[routes.ParamRecordingID]
+
+	recording, err := al.recordingsService.Get(ctx, recordingID)
+	if err != nil {
+		al.jsonError(w, err)
+		return nil, false
+	}
+
+	if al.insecureForTests {
+		return recording, true
+	}
+
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return nil, false
+	}
+	if curUser.HasAccessToAllClients() {
+		return recording, true
+	}
+
+	clientGroups, err := al.clientGroupProvider.GetAll(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return nil, false
+	}
+	err = al.clientService.CheckClientAccess(recording.ClientID, curUser, clientGroups, "")
+	if err != nil {
+		al.jsonError(w, err)
+		return nil, false
+	}
+
+	return recording, true
+}
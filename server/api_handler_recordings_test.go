@@ -0,0 +1,87 @@
+package chserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/server/recordings"
+)
+
+func newTestRecordingsService(t *testing.T) *recordings.Service {
+	provider, err := recordings.NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { provider.Close() })
+
+	service, err := recordings.NewService(provider, filepath.Join(t.TempDir(), "recordings"), testLog)
+	require.NoError(t, err)
+
+	return service
+}
+
+func TestHandleRecordings(t *testing.T) {
+	service := newTestRecordingsService(t)
+	session, err := service.Start(context.Background(), recordings.TypeRDP, "client-1", "1", "admin")
+	require.NoError(t, err)
+	_, err = session.Write([]byte("4.sync,1.0;"))
+	require.NoError(t, err)
+	require.NoError(t, session.Close())
+
+	al := APIListener{
+		insecureForTests: true,
+		Server: &Server{
+			config:            &chconfig.Config{},
+			recordingsService: service,
+		},
+		userService: users.NewAPIService(users.NewStaticProvider([]*users.User{{Username: "admin", Groups: []string{users.Administrators}}}), false, 0, -1),
+	}
+	al.initRouter()
+
+	t.Run("list", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/recordings", nil)
+		ctx := api.WithUser(req.Context(), "admin")
+		w := httptest.NewRecorder()
+		al.router.ServeHTTP(w, req.WithContext(ctx))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		result := struct {
+			Data []*recordings.Recording `json:"data"`
+		}{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+		require.Len(t, result.Data, 1)
+		assert.Equal(t, session.ID(), result.Data[0].ID)
+		assert.Equal(t, "1", result.Data[0].TunnelID)
+	})
+
+	t.Run("download", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/recordings/"+session.ID()+"/download", nil)
+		w := httptest.NewRecorder()
+		al.router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "4.sync,1.0;", w.Body.String())
+		assert.Equal(t, `attachment; filename="`+session.ID()+`.guac"`, w.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/recordings/"+session.ID(), nil)
+		w := httptest.NewRecorder()
+		al.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/recordings/"+session.ID(), nil)
+		w = httptest.NewRecorder()
+		al.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
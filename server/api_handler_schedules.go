@@ -12,6 +12,8 @@ import (
 	"github.com/cloudradar-monitoring/rport/server/api"
 	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
 	"github.com/cloudradar-monitoring/rport/server/api/jobs/schedule"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/approvals"
 	"github.com/cloudradar-monitoring/rport/server/auditlog"
 )
 
@@ -50,13 +52,36 @@ func (al *APIListener) prepareHandleSchedules(req *http.Request) (schedule.Sched
 	if err != nil {
 		return scheduleInput, username, orderedClients, err
 	}
-	err = al.clientService.CheckClientsAccess(orderedClients, curUser, clientGroups)
+	err = al.clientService.CheckClientsAccess(orderedClients, curUser, clientGroups, schedulePermission(&scheduleInput))
 	if err != nil {
 		return scheduleInput, username, orderedClients, err
 	}
 	return scheduleInput, username, orderedClients, nil
 }
 
+// schedulePermission returns the permission needed to run the jobs of a schedule
+func schedulePermission(s *schedule.Schedule) string {
+	if s.Type == schedule.TypeScript {
+		return users.PermissionScripts
+	}
+	return users.PermissionCommands
+}
+
+// requestScheduleApprovalIfRequired validates a schedule and stores it as pending if it targets clients of a client
+// group that requires approval. The schedule is saved once approved, nil is returned if it can be saved right away.
+func (al *APIListener) requestScheduleApprovalIfRequired(req *http.Request, s *schedule.Schedule, orderedClients []*clients.Client, username string) (*approvals.Approval, error) {
+	if al.approvalsService == nil {
+		return nil, nil
+	}
+
+	// an invalid schedule must not wait for approval
+	if err := al.scheduleManager.Validate(s); err != nil {
+		return nil, err
+	}
+
+	return al.requestApprovalIfRequired(req, approvals.NewScheduleRequest(s), orderedClients, username)
+}
+
 func (al *APIListener) handlePostSchedules(w http.ResponseWriter, req *http.Request) {
 	ctx := req.Context()
 	scheduleInput, username, orderedClients, err := al.prepareHandleSchedules(req)
@@ -65,6 +90,16 @@ func (al *APIListener) handlePostSchedules(w http.ResponseWriter, req *http.Requ
 		return
 	}
 
+	approval, err := al.requestScheduleApprovalIfRequired(req, &scheduleInput, orderedClients, username)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if approval != nil {
+		al.writeJSONResponse(w, http.StatusAccepted, api.NewSuccessPayload(approval))
+		return
+	}
+
 	storedValue, err := al.scheduleManager.Create(ctx, &scheduleInput, username)
 	if err != nil {
 		al.jsonError(w, err)
@@ -90,12 +125,34 @@ func (al *APIListener) handleUpdateSchedule(w http.ResponseWriter, req *http.Req
 		return
 	}
 
-	scheduleInput, _, orderedClients, err := al.prepareHandleSchedules(req)
+	scheduleInput, username, orderedClients, err := al.prepareHandleSchedules(req)
 	if err != nil {
 		al.jsonError(w, err)
 		return
 	}
 
+	if al.approvalsService != nil {
+		existing, err := al.scheduleManager.Get(ctx, idStr)
+		if err != nil {
+			al.jsonError(w, err)
+			return
+		}
+		if existing == nil {
+			al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("Cannot find a schedule by the provided id: %s", idStr))
+			return
+		}
+		scheduleInput.ID = idStr
+		approval, err := al.requestScheduleApprovalIfRequired(req, &scheduleInput, orderedClients, username)
+		if err != nil {
+			al.jsonError(w, err)
+			return
+		}
+		if approval != nil {
+			al.writeJSONResponse(w, http.StatusAccepted, api.NewSuccessPayload(approval))
+			return
+		}
+	}
+
 	storedValue, err := al.scheduleManager.Update(ctx, idStr, &scheduleInput)
 	if err != nil {
 		al.jsonError(w, err)
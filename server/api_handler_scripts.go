@@ -11,6 +11,8 @@ import (
 	"github.com/cloudradar-monitoring/rport/server/api"
 	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
 	"github.com/cloudradar-monitoring/rport/server/api/jobs"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/approvals"
 	"github.com/cloudradar-monitoring/rport/server/auditlog"
 	"github.com/cloudradar-monitoring/rport/server/routes"
 	"github.com/cloudradar-monitoring/rport/share/ws"
@@ -46,7 +48,7 @@ func (al *APIListener) handleExecuteScript(w http.ResponseWriter, req *http.Requ
 	execCmdInput.ClientID = cid
 	execCmdInput.IsScript = true
 
-	resp := al.handleExecuteCommand(req.Context(), w, execCmdInput)
+	resp := al.handleExecuteCommand(req, w, execCmdInput)
 
 	if resp != nil {
 		al.auditLog.Entry(auditlog.ApplicationClientScript, auditlog.ActionExecuteStart).
@@ -92,7 +94,7 @@ func (al *APIListener) handlePostMultiClientScript(w http.ResponseWriter, req *h
 	if err != nil {
 		al.jsonError(w, err)
 	}
-	err = al.clientService.CheckClientsAccess(inboundMsg.OrderedClients, curUser, clientGroups)
+	err = al.clientService.CheckClientsAccess(inboundMsg.OrderedClients, curUser, clientGroups, users.PermissionScripts)
 	if err != nil {
 		al.jsonError(w, err)
 		return
@@ -100,6 +102,16 @@ func (al *APIListener) handlePostMultiClientScript(w http.ResponseWriter, req *h
 
 	inboundMsg.Username = curUser.Username
 
+	approval, err := al.requestApprovalIfRequired(req, approvals.NewRequest(inboundMsg), inboundMsg.OrderedClients, curUser.Username)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if approval != nil {
+		al.writeJSONResponse(w, http.StatusAccepted, api.NewSuccessPayload(approval))
+		return
+	}
+
 	multiJob, err := al.StartMultiClientJob(ctx, inboundMsg)
 	if err != nil {
 		al.jsonError(w, err)
@@ -160,7 +172,7 @@ func (al *APIListener) handleScriptsWS(w http.ResponseWriter, req *http.Request)
 
 	auditLogEntry := al.auditLog.Entry(auditlog.ApplicationClientScript, auditlog.ActionExecuteStart).WithHTTPRequest(req)
 
-	al.handleCommandsExecutionWS(ctx, uiConnTS, inboundMsg, auditLogEntry)
+	al.handleCommandsExecutionWS(req, uiConnTS, inboundMsg, auditLogEntry)
 }
 
 func (al *APIListener) enrichScriptInput(
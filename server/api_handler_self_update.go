@@ -0,0 +1,221 @@
+package chserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/auditlog"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/routes"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+const (
+	ClientUpdateStatusStarted  = "started"
+	ClientUpdateStatusUpToDate = "up_to_date"
+	ClientUpdateStatusFailed   = "failed"
+)
+
+type clientUpdateRequest struct {
+	// Version to update to, the newest available version if empty
+	Version string `json:"version"`
+}
+
+type multiClientUpdateRequest struct {
+	clientUpdateRequest
+	ClientIDs  []string              `json:"client_ids"`
+	GroupIDs   []string              `json:"group_ids"`
+	ClientTags *models.JobClientTags `json:"tags"`
+}
+
+func (r *multiClientUpdateRequest) GetClientIDs() []string {
+	return r.ClientIDs
+}
+
+func (r *multiClientUpdateRequest) GetGroupIDs() []string {
+	return r.GroupIDs
+}
+
+func (r *multiClientUpdateRequest) GetClientTags() *models.JobClientTags {
+	return r.ClientTags
+}
+
+type clientUpdateResult struct {
+	ClientID string `json:"client_id"`
+	Status   string `json:"status"`
+	Version  string `json:"version,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// handleGetClientBinaries handles GET /client-binaries
+func (al *APIListener) handleGetClientBinaries(w http.ResponseWriter, req *http.Request) {
+	binaries, err := al.clientBinaries.List()
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(binaries))
+}
+
+// handlePostClientUpdate handles POST /clients/{client_id}/update
+func (al *APIListener) handlePostClientUpdate(w http.ResponseWriter, req *http.Request) {
+	cid := mux.Vars(req)[routes.ParamClientID]
+
+	var reqBody clientUpdateRequest
+	err := parseRequestBody(req.Body, &reqBody)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	client, err := al.clientService.GetActiveByID(cid)
+	if err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to find an active client with id=%q.", cid), err)
+		return
+	}
+	if client == nil {
+		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("Active client with id=%q not found.", cid))
+		return
+	}
+
+	result, err := al.updateClient(client, reqBody.Version)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationClientSelfUpdate, auditlog.ActionExecuteStart).
+		WithHTTPRequest(req).
+		WithClientID(cid).
+		WithRequest(reqBody).
+		WithResponse(result).
+		Save()
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(result))
+}
+
+// handlePostMultiClientUpdate handles POST /client-updates
+func (al *APIListener) handlePostMultiClientUpdate(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	var reqBody multiClientUpdateRequest
+	err := parseRequestBody(req.Body, &reqBody)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	targetClients, err := al.getClientsToUpdate(ctx, &reqBody)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	results := make([]*clientUpdateResult, 0, len(targetClients))
+	for _, client := range targetClients {
+		result, err := al.updateClient(client, reqBody.Version)
+		if err != nil {
+			result = &clientUpdateResult{
+				ClientID: client.ID,
+				Status:   ClientUpdateStatusFailed,
+				Error:    err.Error(),
+			}
+		}
+		results = append(results, result)
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationClientSelfUpdate, auditlog.ActionExecuteStart).
+		WithHTTPRequest(req).
+		WithRequest(reqBody).
+		WithResponse(results).
+		SaveForMultipleClients(targetClients)
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(results))
+}
+
+func (al *APIListener) getClientsToUpdate(ctx context.Context, reqBody *multiClientUpdateRequest) ([]*clients.Client, error) {
+	targetClients, _, err := al.getOrderedClientsWithValidation(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clientGroups, err := al.clientGroupProvider.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = al.clientService.CheckClientsAccess(targetClients, curUser, clientGroups, users.PermissionCommands)
+	if err != nil {
+		return nil, err
+	}
+
+	return targetClients, nil
+}
+
+// updateClient asks the client to update itself to the given version, the client replies as soon as the update
+// is started. It downloads the binary and restarts afterwards.
+func (al *APIListener) updateClient(client *clients.Client, version string) (*clientUpdateResult, error) {
+	binary, err := al.clientBinaries.Find(version, client.OSKernel, client.OSArch)
+	if err != nil {
+		return nil, err
+	}
+	if binary == nil {
+		v := version
+		if v == "" {
+			v = "any version"
+		}
+		return nil, errors2.APIError{
+			Message:    fmt.Sprintf("No client binary of %s found for %s/%s.", v, client.OSKernel, client.OSArch),
+			HTTPStatus: http.StatusNotFound,
+		}
+	}
+
+	result := &clientUpdateResult{
+		ClientID: client.ID,
+		Version:  binary.Version,
+	}
+	if binary.Version == client.Version {
+		result.Status = ClientUpdateStatusUpToDate
+		return result, nil
+	}
+
+	err = comm.SendRequestAndGetResponse(client.Connection, comm.RequestTypeSelfUpdate, &comm.SelfUpdateRequest{
+		Version:   binary.Version,
+		OS:        binary.OS,
+		Arch:      binary.Arch,
+		Path:      binary.Path,
+		Size:      binary.Size,
+		SHA256:    binary.SHA256,
+		Signature: binary.Signature,
+	}, nil)
+	if err != nil {
+		if _, ok := err.(*comm.ClientError); ok {
+			return nil, errors2.APIError{
+				Message:    err.Error(),
+				HTTPStatus: http.StatusConflict,
+			}
+		}
+		return nil, errors2.APIError{
+			Message:    "Failed to start client update.",
+			Err:        err,
+			HTTPStatus: http.StatusInternalServerError,
+		}
+	}
+
+	al.Infof("Client %s is updating from version %s to %s", client.ID, client.Version, binary.Version)
+	result.Status = ClientUpdateStatusStarted
+
+	return result, nil
+}
@@ -0,0 +1,124 @@
+package chserver
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/selfupdate"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+func TestHandlePostClientUpdate(t *testing.T) {
+	binariesDir := t.TempDir()
+	binaryPath := filepath.Join(binariesDir, "0.9.1", "linux_amd64", "rport")
+	require.NoError(t, os.MkdirAll(filepath.Dir(binaryPath), 0755))
+	require.NoError(t, os.WriteFile(binaryPath, []byte("binary"), 0755))
+	signature := make([]byte, ed25519.SignatureSize)
+	require.NoError(t, os.WriteFile(binaryPath+".sig", signature, 0644))
+
+	newClient := func(id, version string, connMock *test.ConnMock) *clients.Client {
+		c := clients.New(t).ID(id).Connection(connMock).Build()
+		c.OSKernel = "linux"
+		c.OSArch = "amd64"
+		c.Version = version
+		return c
+	}
+	connMock1 := test.NewConnMock()
+	connMock1.ReturnOk = true
+	connMock2 := test.NewConnMock()
+	connMock3 := test.NewConnMock()
+	connMock3.ReturnOk = false
+	connMock3.ReturnResponsePayload = []byte("client self-update is disabled")
+	c1 := newClient("client-1", "0.9.0", connMock1)
+	c2 := newClient("client-2", "0.9.1", connMock2)
+	c3 := newClient("client-3", "0.9.0", connMock3)
+	c4 := newClient("client-4", "0.9.0", test.NewConnMock())
+	c4.OSKernel = "windows"
+
+	curUser := makeTestUser("admin")
+	al := makeAPIListener(curUser, clients.NewClientRepository([]*clients.Client{c1, c2, c3, c4}, &hour, testLog), 60, testLog)
+	gp := makeGroupsProvider(t, DataSourceOptions)
+	defer gp.Close()
+	al.clientGroupProvider = gp
+	al.initRouter()
+
+	ctx := api.WithUser(context.Background(), curUser.Username)
+	send := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body)).WithContext(ctx)
+		w := httptest.NewRecorder()
+		al.router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodPost, "/api/v1/clients/client-1/update", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "client self-update is disabled")
+
+	al.clientBinaries = selfupdate.NewBinaries(binariesDir)
+
+	t.Run("single client", func(t *testing.T) {
+		w := send(http.MethodPost, "/api/v1/clients/client-1/update", `{}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"data":{"client_id":"client-1","status":"started","version":"0.9.1"}}`, w.Body.String())
+		name, _, payload := connMock1.InputSendRequest()
+		assert.Equal(t, comm.RequestTypeSelfUpdate, name)
+		var got comm.SelfUpdateRequest
+		require.NoError(t, json.Unmarshal(payload, &got))
+		assert.Equal(t, comm.SelfUpdateRequest{
+			Version:   "0.9.1",
+			OS:        "linux",
+			Arch:      "amd64",
+			Path:      binaryPath,
+			Size:      6,
+			SHA256:    "9a3a45d01531a20e89ac6ae10b0b0beb0492acd7216a368aa062d1a5fecaf9cd",
+			Signature: signature,
+		}, got)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		w := send(http.MethodPost, "/api/v1/clients/client-1/update", `{"version":"1.0.0"}`)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), "No client binary of 1.0.0 found for linux/amd64.")
+	})
+
+	t.Run("multiple clients", func(t *testing.T) {
+		w := send(http.MethodPost, "/api/v1/client-updates", `{"client_ids":["client-2","client-3","client-4"]}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"data":[
+			{"client_id":"client-2","status":"up_to_date","version":"0.9.1"},
+			{"client_id":"client-3","status":"failed","error":"client error: client self-update is disabled"},
+			{"client_id":"client-4","status":"failed","error":"No client binary of any version found for windows/amd64."}
+		]}`, w.Body.String())
+		name, _, _ := connMock2.InputSendRequest()
+		assert.Empty(t, name, "up to date client must not be updated")
+	})
+
+	t.Run("list binaries", func(t *testing.T) {
+		w := send(http.MethodGet, "/api/v1/client-binaries", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"data":[{
+			"version":"0.9.1",
+			"os":"linux",
+			"arch":"amd64",
+			"size":6,
+			"sha256":"9a3a45d01531a20e89ac6ae10b0b0beb0492acd7216a368aa062d1a5fecaf9cd"
+		}]}`, w.Body.String())
+	})
+}
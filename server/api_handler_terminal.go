@@ -0,0 +1,318 @@
+package chserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/server/auditlog"
+	"github.com/cloudradar-monitoring/rport/server/recordings"
+	"github.com/cloudradar-monitoring/rport/server/routes"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/random"
+)
+
+const (
+	defaultTerminalCols = 80
+	defaultTerminalRows = 24
+
+	terminalMessageResize = "resize"
+	terminalMessageExit   = "exit"
+
+	// terminalExitTimeout is how long to wait for the exit status after the terminal output ended
+	terminalExitTimeout = 5 * time.Second
+)
+
+// terminalControlMessage is exchanged as websocket text message. Binary messages carry the terminal input and output.
+type terminalControlMessage struct {
+	Type     string `json:"type"`
+	Cols     uint16 `json:"cols,omitempty"`
+	Rows     uint16 `json:"rows,omitempty"`
+	ExitCode *int   `json:"exit_code,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type terminalSessionResult struct {
+	SessionID   string    `json:"session_id"`
+	StartedAt   time.Time `json:"started_at"`
+	Duration    float64   `json:"duration_sec"`
+	ExitCode    *int      `json:"exit_code"`
+	Error       string    `json:"error,omitempty"`
+	RecordingID string    `json:"recording_id,omitempty"`
+}
+
+// handleTerminalWS handles GET /ws/clients/{client_id}/terminal
+func (al *APIListener) handleTerminalWS(w http.ResponseWriter, req *http.Request) {
+	clientID := mux.Vars(req)[routes.ParamClientID]
+	client, err := al.clientService.GetActiveByID(clientID)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if client == nil {
+		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("Active client with id=%q not found.", clientID))
+		return
+	}
+	if client.ClientConfiguration != nil && !client.ClientConfiguration.RemoteTerminal.Enabled {
+		al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "Remote terminal is disabled on the client, check [remote-terminal] enabled option.")
+		return
+	}
+
+	termReq, err := parseTerminalRequest(req)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	sessionID, err := random.UUID4()
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	uiConn, err := apiUpgrader.Upgrade(w, req, nil)
+	if err != nil {
+		al.Errorf("Failed to establish WS connection: %v", err)
+		return
+	}
+	defer uiConn.Close()
+
+	// the user is sent to the client only, it's part of the audit log entry anyway
+	username := api.GetUser(req.Context(), al.Logger)
+	clientReq := *termReq
+	clientReq.User = username
+	clientReq.UserGroups = al.getUserGroups(username)
+	payload, err := json.Marshal(clientReq)
+	if err != nil {
+		writeTerminalError(uiConn, err)
+		return
+	}
+	var recording *recordings.Session
+	var recorder *recordings.AsciicastWriter
+	if al.recordingsService != nil {
+		recording, recorder, err = al.startTerminalRecording(req, client.ID, termReq)
+		if err != nil {
+			al.Errorf("Failed to start terminal recording: %v", err)
+			writeTerminalError(uiConn, err)
+			return
+		}
+		defer func() {
+			if err := recording.Close(); err != nil {
+				al.Errorf("Failed to close terminal recording: %v", err)
+			}
+		}()
+	}
+
+	sshCh, reqs, err := client.Connection.OpenChannel(comm.ChannelTypeTerminal, payload)
+	if err != nil {
+		al.Errorf("Failed to open terminal on client %s: %v", client.ID, err)
+		writeTerminalError(uiConn, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationClientTerminal, auditlog.ActionExecuteStart).
+		WithHTTPRequest(req).
+		WithClient(client).
+		WithRequest(termReq).
+		WithID(sessionID).
+		Save()
+
+	result := &terminalSessionResult{
+		SessionID: sessionID,
+		StartedAt: time.Now(),
+	}
+	if recording != nil {
+		result.RecordingID = recording.ID()
+	}
+	exitReq := bridgeTerminal(al.Logger.Fork("terminal#%s", sessionID), uiConn, sshCh, reqs, recorder)
+	result.Duration = time.Since(result.StartedAt).Seconds()
+	if exitReq != nil {
+		result.ExitCode = &exitReq.ExitCode
+		result.Error = exitReq.ErrMsg
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationClientTerminal, auditlog.ActionExecuteDone).
+		WithHTTPRequest(req).
+		WithClient(client).
+		WithResponse(result).
+		WithID(sessionID).
+		Save()
+}
+
+func (al *APIListener) startTerminalRecording(req *http.Request, clientID string, termReq *comm.TerminalRequest) (*recordings.Session, *recordings.AsciicastWriter, error) {
+	username := api.GetUser(req.Context(), al.Logger)
+	recording, err := al.recordingsService.Start(req.Context(), recordings.TypeTerminal, clientID, "", username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recorder, err := recordings.NewAsciicastWriter(recording, termReq.Cols, termReq.Rows)
+	if err != nil {
+		_ = recording.Close()
+		return nil, nil, err
+	}
+
+	return recording, recorder, nil
+}
+
+func parseTerminalRequest(req *http.Request) (*comm.TerminalRequest, error) {
+	cols, err := parseTerminalSize(req, "cols", defaultTerminalCols)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := parseTerminalSize(req, "rows", defaultTerminalRows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &comm.TerminalRequest{
+		Cols: cols,
+		Rows: rows,
+		Term: req.URL.Query().Get("term"),
+	}, nil
+}
+
+func parseTerminalSize(req *http.Request, param string, defaultValue uint16) (uint16, error) {
+	value := req.URL.Query().Get(param)
+	if value == "" {
+		return defaultValue, nil
+	}
+	size, err := strconv.ParseUint(value, 10, 16)
+	if err != nil || size == 0 {
+		return 0, errors2.APIError{
+			Message:    fmt.Sprintf("Invalid %q query param: must be a positive number.", param),
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+	return uint16(size), nil
+}
+
+// bridgeTerminal copies the terminal output to the websocket and the input from the websocket until either side
+// ends the session. It returns the exit status sent by the client, nil if it's unknown. The session is written to the
+// recorder unless it's nil.
+func bridgeTerminal(log *logger.Logger, uiConn *websocket.Conn, sshCh ssh.Channel, reqs <-chan *ssh.Request, recorder *recordings.AsciicastWriter) *comm.TerminalExitRequest {
+	var exitReq *comm.TerminalExitRequest
+	reqsDone := make(chan struct{})
+	go func() {
+		defer close(reqsDone)
+		for r := range reqs {
+			if r.Type == comm.RequestTypeTerminalExit {
+				exitReq = &comm.TerminalExitRequest{}
+				if err := json.Unmarshal(r.Payload, exitReq); err != nil {
+					log.Errorf("Failed to decode terminal exit status: %v", err)
+				}
+			}
+			if r.WantReply {
+				_ = r.Reply(false, nil)
+			}
+		}
+	}()
+
+	go func() {
+		// closing the channel terminates the shell on the client
+		defer sshCh.Close()
+		for {
+			msgType, data, err := uiConn.ReadMessage()
+			if err != nil {
+				return
+			}
+			switch msgType {
+			case websocket.BinaryMessage:
+				if recorder != nil {
+					if err := recorder.Input(data); err != nil {
+						log.Errorf("Failed to record terminal input: %v", err)
+					}
+				}
+				if _, err := sshCh.Write(data); err != nil {
+					log.Debugf("Failed to write terminal input: %v", err)
+					return
+				}
+			case websocket.TextMessage:
+				handleTerminalControlMessage(log, sshCh, data, recorder)
+			}
+		}
+	}()
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := sshCh.Read(buf)
+		if n > 0 {
+			if recorder != nil {
+				if rerr := recorder.Output(buf[:n]); rerr != nil {
+					log.Errorf("Failed to record terminal output: %v", rerr)
+				}
+			}
+			if werr := uiConn.WriteMessage(websocket.BinaryMessage, buf[:n]); werr != nil {
+				log.Debugf("Failed to write terminal output: %v", werr)
+				break
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	sshCh.Close()
+
+	select {
+	case <-reqsDone:
+	case <-time.After(terminalExitTimeout):
+		log.Debugf("Timeout waiting for terminal exit status.")
+		return nil
+	}
+
+	exitMsg := terminalControlMessage{
+		Type: terminalMessageExit,
+	}
+	if exitReq != nil {
+		exitMsg.ExitCode = &exitReq.ExitCode
+		exitMsg.Error = exitReq.ErrMsg
+	}
+	_ = uiConn.WriteJSON(exitMsg)
+	_ = uiConn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+
+	return exitReq
+}
+
+func handleTerminalControlMessage(log *logger.Logger, sshCh ssh.Channel, data []byte, recorder *recordings.AsciicastWriter) {
+	msg := terminalControlMessage{}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		log.Debugf("Invalid terminal control message: %v", err)
+		return
+	}
+	if msg.Type != terminalMessageResize || msg.Cols == 0 || msg.Rows == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(comm.TerminalResizeRequest{
+		Cols: msg.Cols,
+		Rows: msg.Rows,
+	})
+	if err != nil {
+		return
+	}
+	if _, err := sshCh.SendRequest(comm.RequestTypeTerminalResize, false, payload); err != nil {
+		log.Debugf("Failed to resize terminal: %v", err)
+	}
+	if recorder != nil {
+		if err := recorder.Resize(msg.Cols, msg.Rows); err != nil {
+			log.Errorf("Failed to record terminal resize: %v", err)
+		}
+	}
+}
+
+func writeTerminalError(uiConn *websocket.Conn, err error) {
+	_ = uiConn.WriteJSON(terminalControlMessage{
+		Type:  terminalMessageExit,
+		Error: err.Error(),
+	})
+	_ = uiConn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
@@ -0,0 +1,221 @@
+package chserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/recordings"
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+// terminalConnMock opens a terminal channel, the client side of it is exposed to the test
+type terminalConnMock struct {
+	*test.ConnMock
+	extraData []byte
+	channel   *terminalChannelMock
+	reqs      chan *ssh.Request
+	// input written by the server, output to be read by the server
+	input  *io.PipeReader
+	output *io.PipeWriter
+}
+
+func newTerminalConnMock() *terminalConnMock {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	return &terminalConnMock{
+		ConnMock: test.NewConnMock(),
+		channel: &terminalChannelMock{
+			Reader:      outR,
+			WriteCloser: inW,
+		},
+		reqs:   make(chan *ssh.Request, 10),
+		input:  inR,
+		output: outW,
+	}
+}
+
+func (c *terminalConnMock) OpenChannel(name string, data []byte) (ssh.Channel, <-chan *ssh.Request, error) {
+	c.extraData = data
+	return c.channel, c.reqs, nil
+}
+
+type terminalChannelMock struct {
+	ssh.Channel
+	io.Reader
+	io.WriteCloser
+
+	resizes []string
+}
+
+func (c *terminalChannelMock) Read(data []byte) (int, error) {
+	return c.Reader.Read(data)
+}
+
+func (c *terminalChannelMock) Write(data []byte) (int, error) {
+	return c.WriteCloser.Write(data)
+}
+
+func (c *terminalChannelMock) Close() error {
+	return c.WriteCloser.Close()
+}
+
+func (c *terminalChannelMock) SendRequest(name string, wantReply bool, payload []byte) (bool, error) {
+	c.resizes = append(c.resizes, string(payload))
+	return true, nil
+}
+
+func TestHandleTerminalWS(t *testing.T) {
+	connMock := newTerminalConnMock()
+	c1 := clients.New(t).Connection(connMock).Build()
+	c1.ClientConfiguration = &clientconfig.Config{RemoteTerminal: clientconfig.TerminalConfig{Enabled: true}}
+	c2 := clients.New(t).Build()
+	c2.ClientConfiguration = &clientconfig.Config{}
+
+	al := APIListener{
+		Server: &Server{
+			config:        &chconfig.Config{},
+			clientService: NewClientService(nil, nil, clients.NewClientRepository([]*clients.Client{c1, c2}, &hour, testLog), testLog),
+		},
+		Logger: testLog,
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/ws/clients/{client_id}/terminal", al.handleTerminalWS)
+	s := httptest.NewServer(router)
+	defer s.Close()
+
+	t.Run("disabled on client", func(t *testing.T) {
+		_, resp, err := websocket.DefaultDialer.Dial(httpToWS(t, s.URL)+"/ws/clients/"+c2.ID+"/terminal", nil)
+		require.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		_, resp, err := websocket.DefaultDialer.Dial(httpToWS(t, s.URL)+"/ws/clients/"+c1.ID+"/terminal?cols=abc", nil)
+		require.Error(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("session", func(t *testing.T) {
+		ws, _, err := websocket.DefaultDialer.Dial(httpToWS(t, s.URL)+"/ws/clients/"+c1.ID+"/terminal?cols=120&rows=40", nil)
+		require.NoError(t, err)
+		defer ws.Close()
+
+		// resize is forwarded as channel request
+		err = ws.WriteMessage(websocket.TextMessage, []byte(`{"type":"resize","cols":100,"rows":30}`))
+		require.NoError(t, err)
+
+		// input is forwarded to the client
+		err = ws.WriteMessage(websocket.BinaryMessage, []byte("ls\n"))
+		require.NoError(t, err)
+		buf := make([]byte, 16)
+		n, err := connMock.input.Read(buf)
+		require.NoError(t, err)
+		assert.Equal(t, "ls\n", string(buf[:n]))
+
+		// output is forwarded to the websocket
+		go func() {
+			_, _ = connMock.output.Write([]byte("file.txt\n"))
+			connMock.reqs <- &ssh.Request{
+				Type:    comm.RequestTypeTerminalExit,
+				Payload: []byte(`{"ExitCode":2}`),
+			}
+			close(connMock.reqs)
+			connMock.output.Close()
+		}()
+
+		msgType, data, err := ws.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, websocket.BinaryMessage, msgType)
+		assert.Equal(t, "file.txt\n", string(data))
+
+		msgType, data, err = ws.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, websocket.TextMessage, msgType)
+		assert.JSONEq(t, `{"type":"exit","exit_code":2}`, string(data))
+
+		_, _, err = ws.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure))
+
+		termReq := comm.TerminalRequest{}
+		require.NoError(t, json.Unmarshal(connMock.extraData, &termReq))
+		assert.Equal(t, comm.TerminalRequest{Cols: 120, Rows: 40}, termReq)
+		// the resize was handled before the input had been forwarded
+		require.Len(t, connMock.channel.resizes, 1)
+		assert.JSONEq(t, `{"Cols":100,"Rows":30}`, connMock.channel.resizes[0])
+	})
+}
+
+func TestHandleTerminalWSRecording(t *testing.T) {
+	connMock := newTerminalConnMock()
+	c1 := clients.New(t).Connection(connMock).Build()
+	c1.ClientConfiguration = &clientconfig.Config{RemoteTerminal: clientconfig.TerminalConfig{Enabled: true}}
+
+	al := APIListener{
+		Server: &Server{
+			config:            &chconfig.Config{},
+			clientService:     NewClientService(nil, nil, clients.NewClientRepository([]*clients.Client{c1}, &hour, testLog), testLog),
+			recordingsService: newTestRecordingsService(t),
+		},
+		Logger: testLog,
+	}
+	router := mux.NewRouter()
+	router.HandleFunc("/ws/clients/{client_id}/terminal", al.handleTerminalWS)
+	s := httptest.NewServer(router)
+	defer s.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial(httpToWS(t, s.URL)+"/ws/clients/"+c1.ID+"/terminal", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+
+	err = ws.WriteMessage(websocket.BinaryMessage, []byte("ls\n"))
+	require.NoError(t, err)
+	buf := make([]byte, 16)
+	_, err = connMock.input.Read(buf)
+	require.NoError(t, err)
+
+	go func() {
+		_, _ = connMock.output.Write([]byte("file.txt\n"))
+		close(connMock.reqs)
+		connMock.output.Close()
+	}()
+	for {
+		if _, _, err := ws.ReadMessage(); err != nil {
+			break
+		}
+	}
+
+	var recorded []*recordings.Recording
+	require.Eventually(t, func() bool {
+		result, err := al.recordingsService.ListRecordings(context.Background(), &query.ListOptions{})
+		require.NoError(t, err)
+		recorded = result.Data.([]*recordings.Recording)
+		return len(recorded) == 1 && recorded[0].FinishedAt != nil
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, recordings.TypeTerminal, recorded[0].Type)
+	assert.Equal(t, c1.ID, recorded[0].ClientID)
+
+	file, err := al.recordingsService.Open(recorded[0])
+	require.NoError(t, err)
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"width":80,"height":24`)
+	assert.Contains(t, string(content), `"i","ls\n"]`)
+	assert.Contains(t, string(content), `"o","file.txt\n"]`)
+}
@@ -0,0 +1,138 @@
+package chserver
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/auditlog"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	"github.com/cloudradar-monitoring/rport/server/routes"
+	"github.com/cloudradar-monitoring/rport/server/tunnelshares"
+)
+
+// handleGetTunnelShares handles GET /clients/{client_id}/tunnels/{tunnel_id}/shares
+func (al *APIListener) handleGetTunnelShares(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	shares, err := al.tunnelShares.List(req.Context(), vars[routes.ParamClientID], vars["tunnel_id"])
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, &api.SuccessPayload{
+		Data: shares,
+		Meta: api.NewMeta(len(shares)),
+	})
+}
+
+// handlePostTunnelShare handles POST /clients/{client_id}/tunnels/{tunnel_id}/shares
+func (al *APIListener) handlePostTunnelShare(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	client, tunnel, ok := al.getClientTunnel(w, req)
+	if !ok {
+		return
+	}
+
+	shareReq := tunnelshares.ShareRequest{}
+	err := parseRequestBody(req.Body, &shareReq)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	share, token, err := al.tunnelShares.Create(ctx, client.ID, tunnel, shareReq, api.GetUser(ctx, al.Logger))
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationClientTunnelShare, auditlog.ActionCreate).
+		WithHTTPRequest(req).
+		WithClient(client).
+		WithRequest(share).
+		WithID(share.ID).
+		Save()
+
+	al.writeJSONResponse(w, http.StatusCreated, api.NewSuccessPayload(&tunnelshares.CreatedShare{
+		Share: share,
+		Token: token,
+		URL:   al.tunnelShareURL(req, tunnel, token),
+	}))
+}
+
+// handleDeleteTunnelShare handles DELETE /clients/{client_id}/tunnels/{tunnel_id}/shares/{share_id}
+func (al *APIListener) handleDeleteTunnelShare(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	vars := mux.Vars(req)
+	clientID := vars[routes.ParamClientID]
+	id := vars[routes.ParamShareID]
+
+	share, err := al.tunnelShares.Revoke(ctx, clientID, vars["tunnel_id"], id, api.GetUser(ctx, al.Logger))
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationClientTunnelShare, auditlog.ActionDelete).
+		WithHTTPRequest(req).
+		WithClientID(clientID).
+		WithRequest(share).
+		WithID(id).
+		Save()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getClientTunnel returns the active client and its tunnel of the request, it writes the error response if one is not found
+func (al *APIListener) getClientTunnel(w http.ResponseWriter, req *http.Request) (*clients.Client, *clienttunnel.Tunnel, bool) {
+	vars := mux.Vars(req)
+	clientID := vars[routes.ParamClientID]
+
+	client, err := al.clientService.GetActiveByID(clientID)
+	if err != nil {
+		al.jsonError(w, err)
+		return nil, nil, false
+	}
+	if client == nil {
+		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("client with id %s not found", clientID))
+		return nil, nil, false
+	}
+
+	tunnel := client.FindTunnel(vars["tunnel_id"])
+	if tunnel == nil {
+		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, "tunnel not found")
+		return nil, nil, false
+	}
+
+	return client, tunnel, true
+}
+
+// tunnelShareURL returns the link to the tunnel proxy with the share token. The host is the configured tunnel_host,
+// the host of the tunnel proxy or the host the API was requested with.
+func (al *APIListener) tunnelShareURL(req *http.Request, t *clienttunnel.Tunnel, token string) string {
+	host := al.config.Server.TunnelHost
+	if host == "" {
+		host = t.Proxy.Host
+		if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+			host = req.Host
+			if h, _, err := net.SplitHostPort(req.Host); err == nil {
+				host = h
+			}
+		}
+	}
+
+	u := url.URL{
+		Scheme:   "https",
+		Host:     net.JoinHostPort(host, t.Proxy.Port),
+		Path:     "/",
+		RawQuery: url.Values{clienttunnel.ShareTokenParam: {token}}.Encode(),
+	}
+	return u.String()
+}
@@ -0,0 +1,85 @@
+package chserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	"github.com/cloudradar-monitoring/rport/server/tunnelshares"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+func TestHandleTunnelShares(t *testing.T) {
+	curUser := makeTestUser("admin")
+	scheme := "https"
+	c1 := clients.New(t).ID("client-1").Build()
+	c1.Tunnels = []*clienttunnel.Tunnel{{
+		ID:        "1",
+		SessionID: "session-1",
+		Proxy:     &clienttunnel.TunnelProxy{Host: "0.0.0.0", Port: "20000"},
+		Remote: models.Remote{
+			Scheme:       &scheme,
+			HTTPProxy:    true,
+			AuthUser:     "user",
+			AuthPassword: "password",
+		},
+	}}
+	al := makeAPIListener(curUser, clients.NewClientRepository([]*clients.Client{c1}, &hour, testLog), 60, testLog)
+	al.initRouter()
+
+	ctx := api.WithUser(context.Background(), curUser.Username)
+	send := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body)).WithContext(ctx)
+		req.Host = "rport.example.com:3000"
+		w := httptest.NewRecorder()
+		al.router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(http.MethodGet, "/api/v1/clients/client-1/tunnels/1/shares", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "tunnel sharing is disabled")
+
+	provider, err := tunnelshares.NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	al.tunnelShares = tunnelshares.NewService(tunnelshares.Config{MaxLifetime: time.Hour, OTPLifetime: time.Minute}, provider, "secret", nil, testLog)
+	defer al.tunnelShares.Close()
+
+	w = send(http.MethodPost, "/api/v1/clients/client-1/tunnels/2/shares", `{"expires_in":"30m"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = send(http.MethodPost, "/api/v1/clients/client-1/tunnels/1/shares", `{"expires_in":"2h"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = send(http.MethodPost, "/api/v1/clients/client-1/tunnels/1/shares", `{"expires_in":"30m","description":"vendor"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	created := struct {
+		Data tunnelshares.CreatedShare `json:"data"`
+	}{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "vendor", created.Data.Description)
+	assert.Equal(t, "admin", created.Data.CreatedBy)
+	assert.Equal(t, "https://rport.example.com:20000/?rport-share="+created.Data.Token, created.Data.URL)
+
+	w = send(http.MethodGet, "/api/v1/clients/client-1/tunnels/1/shares", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"`+created.Data.ID+`"`)
+	assert.NotContains(t, w.Body.String(), created.Data.Token)
+
+	w = send(http.MethodDelete, "/api/v1/clients/client-1/tunnels/1/shares/"+created.Data.ID, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = send(http.MethodDelete, "/api/v1/clients/client-1/tunnels/1/shares/unknown", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -4,24 +4,34 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/gorilla/mux"
+
 	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
 	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	"github.com/cloudradar-monitoring/rport/server/routes"
 	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/query"
 )
 
 type TunnelPayload struct {
 	models.Remote
-	ID        string    `json:"id"`
-	ClientID  string    `json:"client_id"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            string    `json:"id"`
+	ClientID      string    `json:"client_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	BytesSent     int64     `json:"bytes_sent"`
+	BytesReceived int64     `json:"bytes_received"`
 }
 
 func convertToTunnelPayload(t *clienttunnel.Tunnel, clientID string) TunnelPayload {
+	sent, received := t.Traffic()
 	return TunnelPayload{
-		Remote:    t.Remote,
-		ID:        t.ID,
-		ClientID:  clientID,
-		CreatedAt: t.CreatedAt,
+		Remote:        t.Remote,
+		ID:            t.ID,
+		ClientID:      clientID,
+		CreatedAt:     t.CreatedAt,
+		BytesSent:     sent,
+		BytesReceived: received,
 	}
 }
 
@@ -44,7 +54,7 @@ func (al *APIListener) handleGetTunnels(w http.ResponseWriter, req *http.Request
 
 	tunnels := make([]TunnelPayload, 0)
 	for _, c := range clients {
-		if c.DisconnectedAt != nil {
+		if c.DisconnectedAt != nil || !c.UserHasPermission(curUser, users.PermissionTunnels, clientGroups) {
 			continue
 		}
 
@@ -55,3 +65,66 @@ func (al *APIListener) handleGetTunnels(w http.ResponseWriter, req *http.Request
 
 	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(tunnels))
 }
+
+// handleListTunnelHistory handles GET /tunnels/history
+func (al *APIListener) handleListTunnelHistory(w http.ResponseWriter, req *http.Request) {
+	clientIDs, err := al.tunnelHistoryClientIDs(req)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	result, err := al.tunnelHistory.ListSessions(req.Context(), query.GetListOptions(req), clientIDs)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// handleListTunnelHistoryConnections handles GET /tunnels/history/{session_id}/connections
+func (al *APIListener) handleListTunnelHistoryConnections(w http.ResponseWriter, req *http.Request) {
+	clientIDs, err := al.tunnelHistoryClientIDs(req)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	sessionID := mux.Vars(req)[routes.ParamSessionID]
+	result, err := al.tunnelHistory.ListConnections(req.Context(), sessionID, query.GetListOptions(req), clientIDs)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, result)
+}
+
+// tunnelHistoryClientIDs returns the clients whose tunnel history the current user can see, nil if the user sees all
+func (al *APIListener) tunnelHistoryClientIDs(req *http.Request) ([]string, error) {
+	curUser, err := al.getUserModelForAuth(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	if _, limited := curUser.GetPermissionScope(users.PermissionTunnels); curUser.HasAccessToAllClients() && !limited {
+		return nil, nil
+	}
+
+	clientGroups, err := al.clientGroupProvider.GetAll(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	clients, err := al.clientService.GetUserClients(clientGroups, curUser)
+	if err != nil {
+		return nil, err
+	}
+
+	clientIDs := make([]string, 0, len(clients))
+	for _, c := range clients {
+		if c.UserHasPermission(curUser, users.PermissionTunnels, clientGroups) {
+			clientIDs = append(clientIDs, c.ID)
+		}
+	}
+	return clientIDs, nil
+}
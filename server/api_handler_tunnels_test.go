@@ -0,0 +1,121 @@
+package chserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/apikeys"
+	"github.com/cloudradar-monitoring/rport/server/cgroups"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	"github.com/cloudradar-monitoring/rport/server/tunnelhistory"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+func TestHandleTunnelHistory(t *testing.T) {
+	curUser := makeTestUser("admin")
+	al := makeAPIListener(curUser, clients.NewClientRepository(nil, &hour, testLog), 60, testLog)
+	al.initRouter()
+
+	ctx := api.WithUser(context.Background(), curUser.Username)
+	send := func(url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		al.router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("/api/v1/tunnels/history")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "tunnel history is disabled")
+
+	provider, err := tunnelhistory.NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	al.tunnelHistory = tunnelhistory.NewService(provider, testLog)
+	defer al.tunnelHistory.Close()
+
+	tunnel := &clienttunnel.Tunnel{
+		ID:        "1",
+		SessionID: "session-1",
+		CreatedAt: time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC),
+		Remote: models.Remote{
+			LocalHost:  "0.0.0.0",
+			LocalPort:  "2222",
+			RemoteHost: "127.0.0.1",
+			RemotePort: "22",
+			Protocol:   models.ProtocolTCP,
+		},
+	}
+	al.tunnelHistory.TunnelStarted("client-1", tunnel)
+	al.tunnelHistory.ConnectionClosed(tunnel, clienttunnel.Connection{Protocol: "tcp", SourceIP: "192.0.2.1"})
+
+	w = send("/api/v1/tunnels/history?filter[client_id]=client-1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"session-1"`)
+	assert.Contains(t, w.Body.String(), `"meta":{"count":1}`)
+
+	w = send("/api/v1/tunnels/history?filter[unknown]=1")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = send("/api/v1/tunnels/history/session-1/connections")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"source_ip":"192.0.2.1"`)
+
+	w = send("/api/v1/tunnels/history/unknown/connections")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTunnelHistoryClientIDs(t *testing.T) {
+	ctx := context.Background()
+	gp := makeGroupsProvider(t, DataSourceOptions)
+	defer gp.Close()
+	require.NoError(t, gp.Create(ctx, &cgroups.ClientGroup{
+		ID:     "web",
+		Params: &cgroups.ClientParams{ClientID: &cgroups.ParamValues{"web-*"}},
+	}))
+
+	curUser := makeTestUser("admin")
+	c1 := clients.New(t).ID("web-1").Build()
+	c2 := clients.New(t).ID("db-1").Build()
+	al := makeAPIListener(curUser, clients.NewClientRepository([]*clients.Client{c1, c2}, &hour, testLog), 60, testLog)
+	al.clientGroupProvider = gp
+
+	testCases := []struct {
+		name          string
+		apiKey        *apikeys.APIKey
+		wantClientIDs []string
+	}{
+		{
+			name:          "admin",
+			wantClientIDs: nil,
+		},
+		{
+			name:          "admin with api key limited to client group",
+			apiKey:        &apikeys.APIKey{ID: "key-1", ClientGroupIDs: []string{"web"}},
+			wantClientIDs: []string{"web-1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqCtx := api.WithUser(ctx, curUser.Username)
+			if tc.apiKey != nil {
+				reqCtx = apikeys.WithAPIKey(reqCtx, tc.apiKey)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tunnels/history", nil).WithContext(reqCtx)
+
+			clientIDs, err := al.tunnelHistoryClientIDs(req)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantClientIDs, clientIDs)
+		})
+	}
+}
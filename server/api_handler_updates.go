@@ -0,0 +1,307 @@
+package chserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/jobs"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/approvals"
+	"github.com/cloudradar-monitoring/rport/server/auditlog"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/routes"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/ws"
+)
+
+type updatesInstallationRequest struct {
+	models.UpdatesInstallation
+	TimeoutSec int `json:"timeout_sec"`
+}
+
+type multiClientUpdatesInstallationRequest struct {
+	updatesInstallationRequest
+	ClientIDs           []string              `json:"client_ids"`
+	GroupIDs            []string              `json:"group_ids"`
+	ClientTags          *models.JobClientTags `json:"tags"`
+	ExecuteConcurrently bool                  `json:"execute_concurrently"`
+	AbortOnError        *bool                 `json:"abort_on_error"`
+}
+
+func (r *multiClientUpdatesInstallationRequest) toMultiJobRequest() *jobs.MultiJobRequest {
+	return &jobs.MultiJobRequest{
+		ClientIDs:           r.ClientIDs,
+		GroupIDs:            r.GroupIDs,
+		ClientTags:          r.ClientTags,
+		TimeoutSec:          r.TimeoutSec,
+		ExecuteConcurrently: r.ExecuteConcurrently,
+		AbortOnError:        r.AbortOnError,
+		UpdatesInstallation: &r.UpdatesInstallation,
+	}
+}
+
+// handlePostUpdatesInstallation handles POST /clients/{client_id}/updates-installation
+func (al *APIListener) handlePostUpdatesInstallation(w http.ResponseWriter, req *http.Request) {
+	cid := mux.Vars(req)[routes.ParamClientID]
+
+	var reqBody updatesInstallationRequest
+	err := parseRequestBody(req.Body, &reqBody)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if err := reqBody.Validate(); err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusBadRequest, "Invalid updates installation.", err)
+		return
+	}
+	if reqBody.TimeoutSec <= 0 {
+		reqBody.TimeoutSec = jobs.DefaultUpdatesInstallationTimeoutSec
+	}
+
+	client, err := al.clientService.GetActiveByID(cid)
+	if err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to find an active client with id=%q.", cid), err)
+		return
+	}
+	if client == nil {
+		al.jsonErrorResponseWithTitle(w, http.StatusNotFound, fmt.Sprintf("Active client with id=%q not found.", cid))
+		return
+	}
+
+	approval, err := al.requestApprovalIfRequired(req, &approvals.Request{
+		ClientIDs:           []string{cid},
+		TimeoutSec:          reqBody.TimeoutSec,
+		UpdatesInstallation: &reqBody.UpdatesInstallation,
+	}, []*clients.Client{client}, api.GetUser(req.Context(), al.Logger))
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if approval != nil {
+		al.writeJSONResponse(w, http.StatusAccepted, api.NewSuccessPayload(approval))
+		return
+	}
+
+	jid, err := generateNewJobID()
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	createdBy := api.GetUser(req.Context(), al.Logger)
+	curJob := models.Job{
+		JID:        jid,
+		ClientID:   cid,
+		ClientName: client.Name,
+		Command:    reqBody.UpdatesInstallation.String(),
+		CreatedBy:  createdBy,
+		TimeoutSec: reqBody.TimeoutSec,
+
+		UpdatesInstallation: &reqBody.UpdatesInstallation,
+		CreatedByGroups:     al.getUserGroups(createdBy),
+	}
+	sshResp := &comm.RunCmdResponse{}
+	err = comm.SendRequestAndGetResponse(client.Connection, comm.RequestTypeInstallUpdates, curJob, sshResp)
+	if err != nil {
+		if _, ok := err.(*comm.ClientError); ok {
+			al.jsonErrorResponseWithTitle(w, http.StatusConflict, err.Error())
+		} else {
+			al.jsonErrorResponseWithError(w, http.StatusInternalServerError, "Failed to start updates installation.", err)
+		}
+		return
+	}
+
+	curJob.PID = &sshResp.Pid
+	curJob.StartedAt = sshResp.StartedAt
+	curJob.Status = models.JobStatusRunning
+
+	if err := al.jobProvider.CreateJob(&curJob); err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, "Failed to persist a new job.", err)
+		return
+	}
+
+	resp := newJobResponse{
+		JID: curJob.JID,
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationClientUpdates, auditlog.ActionExecuteStart).
+		WithHTTPRequest(req).
+		WithClientID(cid).
+		WithRequest(reqBody).
+		WithResponse(resp).
+		WithID(resp.JID).
+		Save()
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(resp))
+
+	al.Debugf("Job[id=%q] created to %s on client with id=%q.", curJob.JID, curJob.Command, cid)
+}
+
+// handlePostMultiClientUpdatesInstallation handles POST /updates-installation
+func (al *APIListener) handlePostMultiClientUpdatesInstallation(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	var reqBody multiClientUpdatesInstallationRequest
+	err := parseRequestBody(req.Body, &reqBody)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if err := reqBody.Validate(); err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusBadRequest, "Invalid updates installation.", err)
+		return
+	}
+
+	multiJobRequest := reqBody.toMultiJobRequest()
+
+	orderedClients, _, responseErr := al.getOrderedClientsWithValidation(ctx, multiJobRequest)
+	if responseErr != nil {
+		al.jsonError(w, responseErr)
+		return
+	}
+	multiJobRequest.OrderedClients = orderedClients
+
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	clientGroups, err := al.clientGroupProvider.GetAll(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	err = al.clientService.CheckClientsAccess(orderedClients, curUser, clientGroups, users.PermissionCommands)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	multiJobRequest.Username = curUser.Username
+
+	approval, err := al.requestApprovalIfRequired(req, approvals.NewRequest(multiJobRequest), orderedClients, curUser.Username)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	if approval != nil {
+		al.writeJSONResponse(w, http.StatusAccepted, api.NewSuccessPayload(approval))
+		return
+	}
+
+	multiJob, err := al.StartMultiClientJob(ctx, multiJobRequest)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	resp := newJobResponse{
+		JID: multiJob.JID,
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationClientUpdates, auditlog.ActionExecuteStart).
+		WithHTTPRequest(req).
+		WithRequest(reqBody).
+		WithResponse(resp).
+		WithID(multiJob.JID).
+		SaveForMultipleClients(orderedClients)
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(resp))
+
+	al.Debugf("Multi-client Job[id=%q] created to %s on clients %s, groups %s, tags %s.", multiJob.JID, multiJob.Command, reqBody.ClientIDs, reqBody.GroupIDs, reqBody.ClientTags)
+}
+
+// handleUpdatesInstallationWS handles GET /ws/updates-installation, the output of the installation is streamed to the websocket
+func (al *APIListener) handleUpdatesInstallationWS(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	uiConn, err := apiUpgrader.Upgrade(w, req, nil)
+	if err != nil {
+		al.Errorf("Failed to establish WS connection: %v", err)
+		return
+	}
+	uiConnTS := ws.NewConcurrentWebSocket(uiConn, al.Logger)
+	inboundMsg := &multiClientUpdatesInstallationRequest{}
+	err = uiConnTS.ReadJSON(inboundMsg)
+	if err == io.EOF { // is handled separately to return an informative error message
+		uiConnTS.WriteError("Inbound message should contain non empty json object with updates installation data.", nil)
+		return
+	} else if err != nil {
+		uiConnTS.WriteError("Invalid JSON data.", err)
+		return
+	}
+	if err := inboundMsg.Validate(); err != nil {
+		uiConnTS.WriteError("Invalid updates installation.", err)
+		return
+	}
+
+	multiJobRequest := inboundMsg.toMultiJobRequest()
+	orderedClients, _, responseErr := al.getOrderedClientsWithValidation(ctx, multiJobRequest)
+	if responseErr != nil {
+		uiConnTS.WriteError("", responseErr)
+		return
+	}
+	multiJobRequest.OrderedClients = orderedClients
+
+	auditLogEntry := al.auditLog.Entry(auditlog.ApplicationClientUpdates, auditlog.ActionExecuteStart).WithHTTPRequest(req)
+
+	al.handleCommandsExecutionWS(req, uiConnTS, multiJobRequest, auditLogEntry)
+}
+
+var updatesReportSupportedFilters = map[string]bool{
+	"groups": true,
+	"tags":   true,
+}
+
+// handleGetUpdatesReport handles GET /updates-report
+func (al *APIListener) handleGetUpdatesReport(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	options := query.GetListOptions(req)
+	err := query.ValidateListOptions(options, nil, updatesReportSupportedFilters, nil, nil)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	format := req.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, fmt.Sprintf("Invalid format %q, expected 'json' or 'csv'.", format))
+		return
+	}
+
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	groups, err := al.clientGroupProvider.GetAll(ctx)
+	if err != nil {
+		al.jsonErrorResponseWithError(w, http.StatusInternalServerError, "Failed to get client groups.", err)
+		return
+	}
+
+	cls, err := al.clientService.GetFilteredUserClients(curUser, options.Filters, groups)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+	clients.SortByID(cls, false)
+
+	report := clients.NewUpdatesReport(cls)
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="updates-report.csv"`)
+		if err := report.WriteCSV(w); err != nil {
+			al.Errorf("Failed to write updates report: %v", err)
+		}
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(report))
+}
@@ -0,0 +1,133 @@
+package chserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/cgroups"
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+type updatesReportClientGroupProvider struct {
+	cgroups.ClientGroupProvider
+	groups []*cgroups.ClientGroup
+}
+
+func (p updatesReportClientGroupProvider) GetAll(ctx context.Context) ([]*cgroups.ClientGroup, error) {
+	return p.groups, nil
+}
+
+func TestHandleGetUpdatesReport(t *testing.T) {
+	curUser := &users.User{
+		Username: "admin",
+		Groups:   []string{users.Administrators},
+	}
+	refreshed := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	c1 := clients.New(t).ID("web-1").Build()
+	c1.Tags = []string{"prod"}
+	c1.UpdatesStatus = &models.UpdatesStatus{Refreshed: refreshed, UpdatesAvailable: 12, SecurityUpdatesAvailable: 2, RebootPending: true}
+	c2 := clients.New(t).ID("web-2").Build()
+	c2.Tags = []string{"staging"}
+	c2.UpdatesStatus = &models.UpdatesStatus{Refreshed: refreshed}
+	c3 := clients.New(t).ID("db-1").Build()
+	c3.Tags = []string{"prod"}
+	al := APIListener{
+		insecureForTests: true,
+		Server: &Server{
+			clientService: NewClientService(nil, nil, clients.NewClientRepository([]*clients.Client{c1, c2, c3}, &hour, testLog), testLog),
+			config:        &chconfig.Config{},
+			clientGroupProvider: updatesReportClientGroupProvider{groups: []*cgroups.ClientGroup{{
+				ID:     "web",
+				Params: &cgroups.ClientParams{ClientID: &cgroups.ParamValues{"web-*"}},
+			}}},
+		},
+		userService: users.NewAPIService(users.NewStaticProvider([]*users.User{curUser}), false, 0, -1),
+	}
+	al.initRouter()
+
+	testCases := []struct {
+		Name              string
+		URL               string
+		ExpectedStatus    int
+		ExpectedClientIDs []string
+	}{
+		{
+			Name:              "all clients",
+			URL:               "/api/v1/updates-report",
+			ExpectedStatus:    http.StatusOK,
+			ExpectedClientIDs: []string{"db-1", "web-1", "web-2"},
+		},
+		{
+			Name:              "group filter",
+			URL:               "/api/v1/updates-report?filter[groups]=web",
+			ExpectedStatus:    http.StatusOK,
+			ExpectedClientIDs: []string{"web-1", "web-2"},
+		},
+		{
+			Name:              "group and tag filter",
+			URL:               "/api/v1/updates-report?filter[groups]=web&filter[tags]=prod",
+			ExpectedStatus:    http.StatusOK,
+			ExpectedClientIDs: []string{"web-1"},
+		},
+		{
+			Name:           "unsupported filter",
+			URL:            "/api/v1/updates-report?filter[os]=linux",
+			ExpectedStatus: http.StatusBadRequest,
+		},
+		{
+			Name:           "invalid format",
+			URL:            "/api/v1/updates-report?format=xml",
+			ExpectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.URL, nil)
+			req = req.WithContext(api.WithUser(req.Context(), curUser.Username))
+			w := httptest.NewRecorder()
+			al.router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.ExpectedStatus, w.Code)
+			if tc.ExpectedStatus != http.StatusOK {
+				return
+			}
+			var resp struct {
+				Data *clients.UpdatesReport `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			var clientIDs []string
+			for _, e := range resp.Data.Clients {
+				clientIDs = append(clientIDs, e.ClientID)
+			}
+			assert.Equal(t, tc.ExpectedClientIDs, clientIDs)
+		})
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/updates-report?format=csv&filter[tags]=prod", nil)
+		req = req.WithContext(api.WithUser(req.Context(), curUser.Username))
+		w := httptest.NewRecorder()
+		al.router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="updates-report.csv"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, `client_id,name,connection_state,updates_available,security_updates_available,reboot_pending,refreshed,error
+db-1,`+c3.Name+`,connected,,,,,
+web-1,`+c1.Name+`,connected,12,2,true,2022-10-01T12:00:00Z,
+`, w.Body.String())
+	})
+}
@@ -110,6 +110,11 @@ func (al *APIListener) handleDeleteUser(w http.ResponseWriter, req *http.Request
 		return
 	}
 
+	if err := al.apiKeys.DeleteAllByUser(req.Context(), userID); err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
 	al.auditLog.Entry("user", auditlog.ActionDelete).
 		WithHTTPRequest(req).
 		WithID(userID).
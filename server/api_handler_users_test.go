@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/server/api"
 	"github.com/cloudradar-monitoring/rport/server/api/session"
 	"github.com/cloudradar-monitoring/rport/server/api/users"
@@ -204,7 +205,7 @@ type MockAPISessionStorageProvider struct {
 
 func newEmptyMockAPISessionStorageProvider() (m *MockAPISessionStorageProvider, err error) {
 	m = &MockAPISessionStorageProvider{}
-	m.SqliteProvider, err = session.NewSqliteProvider(":memory:", DataSourceOptions)
+	m.SqliteProvider, err = session.NewSqliteProvider(":memory:", database.Options{SQLite: DataSourceOptions})
 	return m, err
 }
 
@@ -12,6 +12,8 @@ import (
 	"github.com/cloudradar-monitoring/rport/server/auditlog"
 	"github.com/cloudradar-monitoring/rport/server/routes"
 	"github.com/cloudradar-monitoring/rport/server/vault"
+	"github.com/cloudradar-monitoring/rport/server/webhooks"
+	chshare "github.com/cloudradar-monitoring/rport/share"
 )
 
 func (al *APIListener) handleGetVaultStatus(w http.ResponseWriter, req *http.Request) {
@@ -42,6 +44,10 @@ func (al *APIListener) handleVaultUnlock(w http.ResponseWriter, req *http.Reques
 	al.auditLog.Entry(auditlog.ApplicationVault, "unlock").
 		WithHTTPRequest(req).
 		Save()
+	al.webhooksService.Dispatch(webhooks.EventVaultUnlocked, webhooks.UserEvent{
+		Username: api.GetUser(req.Context(), al.Logger),
+		RemoteIP: chshare.RemoteIP(req),
+	})
 
 	w.WriteHeader(http.StatusCreated)
 }
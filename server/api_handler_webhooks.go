@@ -0,0 +1,133 @@
+package chserver
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/auditlog"
+	"github.com/cloudradar-monitoring/rport/server/routes"
+	"github.com/cloudradar-monitoring/rport/server/webhooks"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+// handleGetWebhooks handles GET /webhooks
+func (al *APIListener) handleGetWebhooks(w http.ResponseWriter, req *http.Request) {
+	all, err := al.webhooksService.GetAll(req.Context())
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	result := make([]*webhooks.Webhook, 0, len(all))
+	for _, webhook := range all {
+		result = append(result, webhook.WithoutSecret())
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, &api.SuccessPayload{
+		Data: result,
+		Meta: api.NewMeta(len(result)),
+	})
+}
+
+// handleGetWebhook handles GET /webhooks/{webhook_id}
+func (al *APIListener) handleGetWebhook(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[routes.ParamWebhookID]
+
+	webhook, err := al.webhooksService.Get(req.Context(), id)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(webhook.WithoutSecret()))
+}
+
+// handlePostWebhook handles POST /webhooks
+func (al *APIListener) handlePostWebhook(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	curUser, err := al.getUserModelForAuth(ctx)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	webhook := &webhooks.Webhook{}
+	err = parseRequestBody(req.Body, webhook)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	result, err := al.webhooksService.Create(ctx, webhook, curUser.Username)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationWebhook, auditlog.ActionCreate).
+		WithHTTPRequest(req).
+		WithRequest(result.WithoutSecret()).
+		WithID(result.ID).
+		Save()
+
+	al.writeJSONResponse(w, http.StatusCreated, api.NewSuccessPayload(result.WithoutSecret()))
+}
+
+// handlePutWebhook handles PUT /webhooks/{webhook_id}
+func (al *APIListener) handlePutWebhook(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[routes.ParamWebhookID]
+
+	webhook := &webhooks.Webhook{}
+	err := parseRequestBody(req.Body, webhook)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	result, err := al.webhooksService.Update(req.Context(), id, webhook)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationWebhook, auditlog.ActionUpdate).
+		WithHTTPRequest(req).
+		WithRequest(result.WithoutSecret()).
+		WithID(id).
+		Save()
+
+	al.writeJSONResponse(w, http.StatusOK, api.NewSuccessPayload(result.WithoutSecret()))
+}
+
+// handleDeleteWebhook handles DELETE /webhooks/{webhook_id}
+func (al *APIListener) handleDeleteWebhook(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[routes.ParamWebhookID]
+
+	err := al.webhooksService.Delete(req.Context(), id)
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.auditLog.Entry(auditlog.ApplicationWebhook, auditlog.ActionDelete).
+		WithHTTPRequest(req).
+		WithID(id).
+		Save()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleListWebhookDeliveries handles GET /webhooks/{webhook_id}/deliveries
+func (al *APIListener) handleListWebhookDeliveries(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)[routes.ParamWebhookID]
+
+	result, err := al.webhooksService.ListDeliveries(req.Context(), id, query.GetListOptions(req))
+	if err != nil {
+		al.jsonError(w, err)
+		return
+	}
+
+	al.writeJSONResponse(w, http.StatusOK, result)
+}
@@ -0,0 +1,129 @@
+package chserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	"github.com/cloudradar-monitoring/rport/server/webhooks"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+func TestHandleWebhooks(t *testing.T) {
+	curUser := makeTestUser("admin")
+	al := makeAPIListener(curUser, clients.NewClientRepository(nil, &hour, testLog), 60, testLog)
+	provider, err := webhooks.NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	al.webhooksService = webhooks.NewService(webhooks.Config{MaxAttempts: 1, RetryInterval: time.Second, Timeout: time.Second}, provider, testLog)
+	defer al.webhooksService.Close()
+	al.initRouter()
+
+	ctx := api.WithUser(context.Background(), curUser.Username)
+	send := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body)).WithContext(ctx)
+		w := httptest.NewRecorder()
+		al.router.ServeHTTP(w, req)
+		return w
+	}
+	var created struct {
+		Data *webhooks.Webhook `json:"data"`
+	}
+
+	w := send(http.MethodPost, "/api/v1/webhooks", `{"url":"ftp://example.com"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "url must start with http:// or https://")
+
+	w = send(http.MethodPost, "/api/v1/webhooks", `{"url":"https://example.com/hook","secret":"top-secret","events":["job.failed"],"enabled":true}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "top-secret")
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.Equal(t, "admin", created.Data.CreatedBy)
+	assert.EqualValues(t, []string{webhooks.EventJobFailed}, created.Data.Events)
+
+	stored, err := al.webhooksService.Get(ctx, created.Data.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "top-secret", stored.Secret)
+
+	w = send(http.MethodPut, "/api/v1/webhooks/"+created.Data.ID, `{"url":"https://example.com/new","enabled":false}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "top-secret")
+
+	w = send(http.MethodGet, "/api/v1/webhooks", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"url":"https://example.com/new"`)
+	assert.NotContains(t, w.Body.String(), "top-secret")
+
+	w = send(http.MethodGet, "/api/v1/webhooks/"+created.Data.ID+"/deliveries", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[],"meta":{"count":0}}`, w.Body.String())
+
+	w = send(http.MethodDelete, "/api/v1/webhooks/"+created.Data.ID, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = send(http.MethodGet, "/api/v1/webhooks/"+created.Data.ID, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandleWebhooksDisabled(t *testing.T) {
+	curUser := makeTestUser("admin")
+	al := makeAPIListener(curUser, clients.NewClientRepository(nil, &hour, testLog), 60, testLog)
+	al.initRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/webhooks", nil)
+	req = req.WithContext(api.WithUser(req.Context(), curUser.Username))
+	w := httptest.NewRecorder()
+	al.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "webhooks are disabled")
+}
+
+func TestTunnelEventWithoutCredentials(t *testing.T) {
+	scheme := "http"
+	acl := "192.0.2.0/24"
+	tunnel := &clienttunnel.Tunnel{
+		ID: "1",
+		Remote: models.Remote{
+			Protocol:     models.ProtocolTCP,
+			LocalHost:    "0.0.0.0",
+			LocalPort:    "2000",
+			RemoteHost:   "127.0.0.1",
+			RemotePort:   "80",
+			Scheme:       &scheme,
+			ACL:          &acl,
+			HTTPProxy:    true,
+			AuthUser:     "proxy-user",
+			AuthPassword: "proxy-password",
+		},
+	}
+
+	event := tunnelEvent("client-1", "admin", tunnel, "")
+
+	assert.Equal(t, webhooks.TunnelEvent{
+		ClientID:   "client-1",
+		TunnelID:   "1",
+		Username:   "admin",
+		Protocol:   models.ProtocolTCP,
+		LocalHost:  "0.0.0.0",
+		LocalPort:  "2000",
+		RemoteHost: "127.0.0.1",
+		RemotePort: "80",
+		Scheme:     "http",
+		ACL:        "192.0.2.0/24",
+	}, event)
+	body, err := json.Marshal(event)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "proxy-user")
+	assert.NotContains(t, string(body), "proxy-password")
+}
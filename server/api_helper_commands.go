@@ -1,12 +1,14 @@
 package chserver
 
 import (
-	"context"
+	"net/http"
 	"time"
 
 	"github.com/gorilla/websocket"
 
 	"github.com/cloudradar-monitoring/rport/server/api/jobs"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/approvals"
 	"github.com/cloudradar-monitoring/rport/server/auditlog"
 	"github.com/cloudradar-monitoring/rport/server/validation"
 	"github.com/cloudradar-monitoring/rport/share/models"
@@ -14,11 +16,15 @@ import (
 )
 
 func (al *APIListener) handleCommandsExecutionWS(
-	ctx context.Context,
+	req *http.Request,
 	uiConnTS *ws.ConcurrentWebSocket,
 	inboundMsg *jobs.MultiJobRequest,
 	auditLogEntry *auditlog.Entry,
 ) {
+	ctx := req.Context()
+	if inboundMsg.UpdatesInstallation != nil {
+		inboundMsg.Command = inboundMsg.UpdatesInstallation.String()
+	}
 	if inboundMsg.Command == "" {
 		uiConnTS.WriteError("Command cannot be empty.", nil)
 		return
@@ -29,7 +35,11 @@ func (al *APIListener) handleCommandsExecutionWS(
 	}
 
 	if inboundMsg.TimeoutSec <= 0 {
-		inboundMsg.TimeoutSec = al.config.Server.RunRemoteCmdTimeoutSec
+		if inboundMsg.UpdatesInstallation != nil {
+			inboundMsg.TimeoutSec = jobs.DefaultUpdatesInstallationTimeoutSec
+		} else {
+			inboundMsg.TimeoutSec = al.config.Server.RunRemoteCmdTimeoutSec
+		}
 	}
 
 	curUser, err := al.getUserModelForAuth(ctx)
@@ -42,12 +52,27 @@ func (al *APIListener) handleCommandsExecutionWS(
 	if err != nil {
 		uiConnTS.WriteError("Could not get client groups", err)
 	}
-	err = al.clientService.CheckClientsAccess(inboundMsg.OrderedClients, curUser, clientGroups)
+	permission := users.PermissionCommands
+	if inboundMsg.IsScript {
+		permission = users.PermissionScripts
+	}
+	err = al.clientService.CheckClientsAccess(inboundMsg.OrderedClients, curUser, clientGroups, permission)
 	if err != nil {
 		uiConnTS.WriteError(err.Error(), nil)
 		return
 	}
 
+	approval, err := al.requestApprovalIfRequired(req, approvals.NewRequest(inboundMsg), inboundMsg.OrderedClients, curUser.Username)
+	if err != nil {
+		uiConnTS.WriteError("Could not request approval.", err)
+		return
+	}
+	if approval != nil {
+		// the job is started once approved, there are no results to stream
+		_ = uiConnTS.WriteJSON(approval)
+		return
+	}
+
 	jid, err := generateNewJobID()
 	if err != nil {
 		uiConnTS.WriteError("Could not generate job id.", err)
@@ -86,6 +111,8 @@ func (al *APIListener) handleCommandsExecutionWS(
 			AbortOnErr:  abortOnErr,
 			IsSudo:      inboundMsg.IsSudo,
 			IsScript:    inboundMsg.IsScript,
+
+			UpdatesInstallation: inboundMsg.UpdatesInstallation,
 		}
 		if err := al.jobProvider.SaveMultiJob(multiJob); err != nil {
 			uiConnTS.WriteError("Failed to persist a new multi-client job.", err)
@@ -96,6 +123,9 @@ func (al *APIListener) handleCommandsExecutionWS(
 
 		uiConnTS.SetWritesBeforeClose(len(inboundMsg.OrderedClients))
 
+		cancelCtx, done := al.trackMultiJob(multiJob.JID)
+		defer done()
+
 		// for sequential execution - create a channel to get the job result
 		var curJobDoneChannel chan *models.Job
 
@@ -109,6 +139,10 @@ func (al *APIListener) handleCommandsExecutionWS(
 		}
 
 		for _, client := range inboundMsg.OrderedClients {
+			if cancelCtx.Err() != nil {
+				uiConnTS.Close()
+				return
+			}
 			curJID, err := generateNewJobID()
 			if err != nil {
 				uiConnTS.WriteError("Could not generate job id.", err)
@@ -126,6 +160,7 @@ func (al *APIListener) handleCommandsExecutionWS(
 					multiJob.TimeoutSec,
 					multiJob.IsSudo,
 					multiJob.IsScript,
+					multiJob.UpdatesInstallation,
 					client,
 				)
 			} else {
@@ -140,6 +175,7 @@ func (al *APIListener) handleCommandsExecutionWS(
 					multiJob.TimeoutSec,
 					multiJob.IsSudo,
 					multiJob.IsScript,
+					multiJob.UpdatesInstallation,
 					client,
 				)
 
@@ -158,7 +194,7 @@ func (al *APIListener) handleCommandsExecutionWS(
 
 				// wait until command is finished
 				jobResult := <-curJobDoneChannel
-				if multiJob.AbortOnErr && jobResult.Status == models.JobStatusFailed {
+				if multiJob.AbortOnErr && jobResult.Status == models.JobStatusFailed || cancelCtx.Err() != nil {
 					uiConnTS.Close()
 					return
 				}
@@ -177,6 +213,7 @@ func (al *APIListener) handleCommandsExecutionWS(
 			inboundMsg.TimeoutSec,
 			inboundMsg.IsSudo,
 			inboundMsg.IsScript,
+			inboundMsg.UpdatesInstallation,
 			client,
 		)
 	}
@@ -24,6 +24,7 @@ type JobProvider interface {
 	GetByJID(clientID, jid string) (*models.Job, error)
 	List(ctx context.Context, options *query.ListOptions) ([]*models.Job, error)
 	Count(ctx context.Context, options *query.ListOptions) (int, error)
+	CountByStatus(ctx context.Context) (map[string]int, error)
 	// SaveJob creates or updates a job
 	SaveJob(job *models.Job) error
 	// CreateJob creates a new job. If already exist with a given JID - do nothing and return nil
@@ -42,6 +43,7 @@ func (al *APIListener) createAndRunJobWS(
 	jid, cmd, interpreter, createdBy, cwd string,
 	timeoutSec int,
 	isSudo, isScript bool,
+	updatesInstallation *models.UpdatesInstallation,
 	client *clients.Client,
 ) bool {
 	curJob := models.Job{
@@ -58,12 +60,15 @@ func (al *APIListener) createAndRunJobWS(
 		IsSudo:       isSudo,
 		IsScript:     isScript,
 		StreamResult: true,
+
+		UpdatesInstallation: updatesInstallation,
+		CreatedByGroups:     al.getUserGroups(createdBy),
 	}
 	logPrefix := curJob.LogPrefix()
 
 	// send the command to the client
 	sshResp := &comm.RunCmdResponse{}
-	err := comm.SendRequestAndGetResponse(client.Connection, comm.RequestTypeRunCmd, curJob, sshResp)
+	err := comm.SendRequestAndGetResponse(client.Connection, jobRequestType(&curJob), curJob, sshResp)
 	if err != nil {
 		al.Errorf("%s, Error on execute remote command: %v", logPrefix, err)
 
@@ -108,7 +113,11 @@ func (al *APIListener) StartMultiClientJob(ctx context.Context, multiJobRequest
 		abortOnErr = *multiJobRequest.AbortOnError
 	}
 	if multiJobRequest.TimeoutSec <= 0 {
-		multiJobRequest.TimeoutSec = al.config.Server.RunRemoteCmdTimeoutSec
+		if multiJobRequest.UpdatesInstallation != nil {
+			multiJobRequest.TimeoutSec = jobs.DefaultUpdatesInstallationTimeoutSec
+		} else {
+			multiJobRequest.TimeoutSec = al.config.Server.RunRemoteCmdTimeoutSec
+		}
 	}
 
 	if multiJobRequest.OrderedClients == nil {
@@ -131,7 +140,9 @@ func (al *APIListener) StartMultiClientJob(ctx context.Context, multiJobRequest
 	}
 
 	command := multiJobRequest.Command
-	if multiJobRequest.IsScript {
+	if multiJobRequest.UpdatesInstallation != nil {
+		command = multiJobRequest.UpdatesInstallation.String()
+	} else if multiJobRequest.IsScript {
 		decodedScriptBytes, err := base64.StdEncoding.DecodeString(multiJobRequest.Script)
 		if err != nil {
 			return nil, err
@@ -157,6 +168,8 @@ func (al *APIListener) StartMultiClientJob(ctx context.Context, multiJobRequest
 		TimeoutSec:  multiJobRequest.TimeoutSec,
 		Concurrent:  multiJobRequest.ExecuteConcurrently,
 		AbortOnErr:  abortOnErr,
+
+		UpdatesInstallation: multiJobRequest.UpdatesInstallation,
 	}
 	if err := al.jobProvider.SaveMultiJob(multiJob); err != nil {
 		return nil, err
@@ -167,10 +180,35 @@ func (al *APIListener) StartMultiClientJob(ctx context.Context, multiJobRequest
 	return multiJob, nil
 }
 
+// trackMultiJob makes a running multi-client job cancellable, the returned context is done when the job is cancelled.
+// The returned func must be called when no more jobs are started.
+func (al *APIListener) trackMultiJob(jid string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	al.runningMultiJobs.Store(jid, cancel)
+	return ctx, func() {
+		al.runningMultiJobs.Delete(jid)
+		cancel()
+	}
+}
+
+// stopMultiJob prevents a running multi-client job from starting jobs on further clients.
+// Returns false if the multi-client job is not starting jobs anymore.
+func (al *APIListener) stopMultiJob(jid string) bool {
+	cancel, ok := al.runningMultiJobs.Load(jid)
+	if !ok {
+		return false
+	}
+	cancel.(context.CancelFunc)()
+	return true
+}
+
 func (al *APIListener) executeMultiClientJob(
 	job *models.MultiJob,
 	orderedClients []*clients.Client,
 ) {
+	ctx, done := al.trackMultiJob(job.JID)
+	defer done()
+
 	// for sequential execution - create a channel to get the job result
 	var curJobDoneChannel chan *models.Job
 	if !job.Concurrent {
@@ -182,30 +220,14 @@ func (al *APIListener) executeMultiClientJob(
 		}()
 	}
 	for _, client := range orderedClients {
+		if ctx.Err() != nil {
+			al.Infof("Multi-client Job[id=%q] cancelled.", job.JID)
+			break
+		}
 		if job.Concurrent {
-			go al.createAndRunJob(
-				job.JID,
-				job.Command,
-				job.Interpreter,
-				job.CreatedBy,
-				job.Cwd,
-				job.TimeoutSec,
-				job.IsSudo,
-				job.IsScript,
-				client,
-			)
+			go al.createAndRunJob(job, client)
 		} else {
-			success := al.createAndRunJob(
-				job.JID,
-				job.Command,
-				job.Interpreter,
-				job.CreatedBy,
-				job.Cwd,
-				job.TimeoutSec,
-				job.IsSudo,
-				job.IsScript,
-				client,
-			)
+			success := al.createAndRunJob(job, client)
 			if !success {
 				if job.AbortOnErr {
 					break
@@ -224,6 +246,10 @@ func (al *APIListener) executeMultiClientJob(
 			if job.AbortOnErr && jobResult.Status == models.JobStatusFailed {
 				break
 			}
+			if ctx.Err() != nil {
+				al.Infof("Multi-client Job[id=%q] cancelled.", job.JID)
+				break
+			}
 		}
 	}
 	if al.testDone != nil {
@@ -231,15 +257,10 @@ func (al *APIListener) executeMultiClientJob(
 	}
 }
 
-func (al *APIListener) createAndRunJob(
-	multiJobID, cmd, interpreter, createdBy, cwd string,
-	timeoutSec int,
-	isSudo, isScript bool,
-	client *clients.Client,
-) bool {
+func (al *APIListener) createAndRunJob(multiJob *models.MultiJob, client *clients.Client) bool {
 	jid, err := generateNewJobID()
 	if err != nil {
-		al.Errorf("multi_client_id=%q, client_id=%q, Could not generate job id: %v", multiJobID, client.ID, err)
+		al.Errorf("multi_client_id=%q, client_id=%q, Could not generate job id: %v", multiJob.JID, client.ID, err)
 		return false
 	}
 	// send the command to the client
@@ -248,18 +269,21 @@ func (al *APIListener) createAndRunJob(
 		StartedAt:   time.Now(),
 		ClientID:    client.ID,
 		ClientName:  client.Name,
-		Command:     cmd,
-		Cwd:         cwd,
-		IsSudo:      isSudo,
-		IsScript:    isScript,
-		Interpreter: interpreter,
-		CreatedBy:   createdBy,
-		TimeoutSec:  timeoutSec,
-		MultiJobID:  &multiJobID,
+		Command:     multiJob.Command,
+		Cwd:         multiJob.Cwd,
+		IsSudo:      multiJob.IsSudo,
+		IsScript:    multiJob.IsScript,
+		Interpreter: multiJob.Interpreter,
+		CreatedBy:   multiJob.CreatedBy,
+		TimeoutSec:  multiJob.TimeoutSec,
+		MultiJobID:  &multiJob.JID,
+
+		UpdatesInstallation: multiJob.UpdatesInstallation,
+		CreatedByGroups:     al.getUserGroups(multiJob.CreatedBy),
 	}
 	sshResp := &comm.RunCmdResponse{}
 	if client.Connection != nil {
-		err = comm.SendRequestAndGetResponse(client.Connection, comm.RequestTypeRunCmd, curJob, sshResp)
+		err = comm.SendRequestAndGetResponse(client.Connection, jobRequestType(&curJob), curJob, sshResp)
 	} else {
 		err = errors.New("client is not connected")
 	}
@@ -285,3 +309,11 @@ func (al *APIListener) createAndRunJob(
 
 	return err == nil
 }
+
+// jobRequestType returns the type of the request that starts the job on a client
+func jobRequestType(job *models.Job) string {
+	if job.UpdatesInstallation != nil {
+		return comm.RequestTypeInstallUpdates
+	}
+	return comm.RequestTypeRunCmd
+}
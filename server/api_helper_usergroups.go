@@ -1,6 +1,7 @@
 package chserver
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -44,6 +45,18 @@ func (al *APIListener) handleUpdateUserGroup(w http.ResponseWriter, req *http.Re
 		return
 	}
 
+	for clientGroupID := range input.ClientGroupPermissions {
+		clientGroup, err := al.clientGroupProvider.Get(req.Context(), clientGroupID)
+		if err != nil {
+			al.jsonError(w, err)
+			return
+		}
+		if clientGroup == nil {
+			al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, fmt.Sprintf("Client group %q not found.", clientGroupID))
+			return
+		}
+	}
+
 	group, err := al.userService.UpdateGroup(name, input)
 	if err != nil {
 		al.jsonError(w, err)
@@ -5,8 +5,10 @@ import (
 	"net/http"
 
 	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/apikeys"
 	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
 	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/share/query"
 )
 
 // TODO: remove
@@ -16,12 +18,56 @@ func (al *APIListener) getUserModel(ctx context.Context) (*users.User, error) {
 		return nil, nil
 	}
 
-	user, err := al.userService.GetByUsername(curUsername)
+	return al.getUserModelByUsername(curUsername, apikeys.FromContext(ctx))
+}
+
+// getUserModelByUsername returns the user with the permission scopes of its groups, limited to the client groups of
+// the API key if given. It returns nil if the user doesn't exist.
+func (al *APIListener) getUserModelByUsername(username string, apiKey *apikeys.APIKey) (*users.User, error) {
+	user, err := al.userService.GetByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, nil
+	}
+
+	permissionScopes, err := al.userService.GetPermissionScopes(user)
 	if err != nil {
 		return nil, err
 	}
 
-	return user, err
+	if len(permissionScopes) > 0 || apiKey != nil && len(apiKey.ClientGroupIDs) > 0 {
+		// copy to not modify the user kept by the provider
+		limited := *user
+		limited.PermissionScopes = permissionScopes
+		if apiKey != nil && len(apiKey.ClientGroupIDs) > 0 {
+			limited.ClientGroupIDs = apiKey.ClientGroupIDs
+		}
+		return &limited, nil
+	}
+
+	return user, nil
+}
+
+// getUserGroups returns the groups of the given user, they are sent to clients to apply their user policy.
+// Errors are only logged, clients with a policy for groups reject the request then.
+func (al *APIListener) getUserGroups(username string) []string {
+	if username == "" {
+		return nil
+	}
+
+	user, err := al.userService.GetByUsername(username)
+	if err != nil {
+		al.Errorf("Failed to get groups of user %q: %v", username, err)
+		return nil
+	}
+	if user == nil {
+		return nil
+	}
+
+	return user.Groups
 }
 
 // TODO: move to userService
@@ -43,3 +89,36 @@ func (al *APIListener) getUserModelForAuth(ctx context.Context) (*users.User, er
 
 	return usr, nil
 }
+
+// filterByUserClients limits the list options to the clients a user has access to and the given permission for,
+// unless it's an unrestricted admin. It returns false if the user has no access to any client.
+func (al *APIListener) filterByUserClients(ctx context.Context, curUser *users.User, permission string, options *query.ListOptions) (bool, error) {
+	if _, limited := curUser.GetPermissionScope(permission); curUser.HasAccessToAllClients() && !limited {
+		return true, nil
+	}
+
+	clientGroups, err := al.clientGroupProvider.GetAll(ctx)
+	if err != nil {
+		return false, err
+	}
+	userClients, err := al.clientService.GetUserClients(clientGroups, curUser)
+	if err != nil {
+		return false, err
+	}
+
+	clientIDs := make([]string, 0, len(userClients))
+	for _, c := range userClients {
+		if c.UserHasPermission(curUser, permission, clientGroups) {
+			clientIDs = append(clientIDs, c.ID)
+		}
+	}
+	if len(clientIDs) == 0 {
+		return false, nil
+	}
+	options.Filters = append(options.Filters, query.FilterOption{
+		Column: []string{"client_id"},
+		Values: clientIDs,
+	})
+
+	return true, nil
+}
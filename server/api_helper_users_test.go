@@ -0,0 +1,98 @@
+package chserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/cgroups"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+func TestFilterByUserClients(t *testing.T) {
+	ctx := context.Background()
+	gp := makeGroupsProvider(t, DataSourceOptions)
+	defer gp.Close()
+	for id, pattern := range map[string]string{"web": "web-*", "db": "db-*"} {
+		require.NoError(t, gp.Create(ctx, &cgroups.ClientGroup{
+			ID:     id,
+			Params: &cgroups.ClientParams{ClientID: &cgroups.ParamValues{cgroups.Param(pattern)}},
+		}))
+	}
+
+	c1 := clients.New(t).ID("web-1").AllowedUserGroups([]string{"team"}).Build()
+	c2 := clients.New(t).ID("db-1").AllowedUserGroups([]string{"team"}).Build()
+	al := APIListener{
+		Logger: testLog,
+		Server: &Server{
+			clientService:       NewClientService(nil, nil, clients.NewClientRepository([]*clients.Client{c1, c2}, &hour, testLog), testLog),
+			clientGroupProvider: gp,
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		user          *users.User
+		wantHas       bool
+		wantClientIDs []string
+	}{
+		{
+			name:    "admin",
+			user:    &users.User{Groups: []string{users.Administrators}},
+			wantHas: true,
+		},
+		{
+			name: "admin with permission for one client group",
+			user: &users.User{
+				Groups:           []string{users.Administrators},
+				PermissionScopes: map[string][]string{users.PermissionMonitoring: {"db"}},
+			},
+			wantHas:       true,
+			wantClientIDs: []string{"db-1"},
+		},
+		{
+			name:          "user",
+			user:          &users.User{Groups: []string{"team"}},
+			wantHas:       true,
+			wantClientIDs: []string{"db-1", "web-1"},
+		},
+		{
+			name: "user with permission for one client group",
+			user: &users.User{
+				Groups:           []string{"team"},
+				PermissionScopes: map[string][]string{users.PermissionMonitoring: {"web"}},
+			},
+			wantHas:       true,
+			wantClientIDs: []string{"web-1"},
+		},
+		{
+			name: "user with permission for no client group",
+			user: &users.User{
+				Groups:           []string{"team"},
+				PermissionScopes: map[string][]string{users.PermissionMonitoring: {}},
+			},
+			wantHas: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := &query.ListOptions{}
+
+			has, err := al.filterByUserClients(ctx, tc.user, users.PermissionMonitoring, options)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantHas, has)
+			if tc.wantClientIDs == nil {
+				assert.Empty(t, options.Filters)
+				return
+			}
+			require.Len(t, options.Filters, 1)
+			assert.ElementsMatch(t, tc.wantClientIDs, options.Filters[0].Values)
+		})
+	}
+}
@@ -18,8 +18,9 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/jpillora/requestlog"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/migration/library"
-	"github.com/cloudradar-monitoring/rport/db/sqlite"
+	"github.com/cloudradar-monitoring/rport/db/postgres"
 
 	"github.com/cloudradar-monitoring/rport/server/api/session"
 	"github.com/cloudradar-monitoring/rport/server/chconfig"
@@ -27,6 +28,7 @@ import (
 	"github.com/cloudradar-monitoring/rport/server/script"
 
 	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/apikeys"
 	"github.com/cloudradar-monitoring/rport/server/api/command"
 	"github.com/cloudradar-monitoring/rport/server/api/message"
 	"github.com/cloudradar-monitoring/rport/server/api/users"
@@ -53,6 +55,7 @@ type APIListener struct {
 
 	fingerprint       string
 	apiSessions       *session.Cache
+	apiKeys           apikeys.Provider
 	router            *mux.Router
 	httpServer        *chshare.HTTPServer
 	requestLogOptions *requestlog.Options
@@ -85,6 +88,8 @@ type UserService interface {
 	CheckPermission(*users.User, string) error
 	SupportsGroupPermissions() bool
 	GetEffectiveUserPermissions(*users.User) (map[string]bool, error)
+	GetPermissionScopes(*users.User) (map[string][]string, error)
+	PasswordVerifier() users.PasswordVerifier
 }
 
 func NewAPIListener(
@@ -112,6 +117,17 @@ func NewAPIListener(
 				return nil, err
 			}
 		}
+	} else if config.LDAP.Enabled {
+		logger := logger.NewLogger("ldap", config.Logging.LogOutput, config.Logging.LogLevel)
+		ldapProvider, err := users.NewLDAPProvider(logger, config.LDAP)
+		if err != nil {
+			return nil, err
+		}
+		// the directory might be temporarily unavailable, the server is started anyway
+		if err := ldapProvider.Check(); err != nil {
+			logger.Errorf("LDAP server check failed: %v", err)
+		}
+		usersProvider = ldapProvider
 	} else if config.API.AuthFile != "" {
 		logger := logger.NewLogger("auth-file", config.Logging.LogOutput, config.Logging.LogLevel)
 		usersProvider, err = users.NewFileAdapter(logger, users.NewFileManager(config.API.AuthFile))
@@ -147,24 +163,25 @@ func NewAPIListener(
 		&vault.NotInitDbProvider{},
 	)
 
-	// init vault DB if it already exists
+	// init vault DB if it already exists, in PostgreSQL its tables are created along with the other stores
 	fs := files.NewFileSystem()
 	exist, err := fs.Exist(config.GetVaultDBPath())
 	if err != nil {
 		return nil, fmt.Errorf("failed to check if vault DB %q exists: %v", config.GetVaultDBPath(), err)
 	}
-	if exist {
+	if exist || config.Database.Driver == postgres.DriverName {
 		err := vaultDBProviderFactory.Init()
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	libraryDb, err := sqlite.New(
+	libraryDb, err := database.Open(
+		"library",
 		path.Join(config.Server.DataDir, "library.db"),
 		library.AssetNames(),
 		library.Asset,
-		config.Server.GetSQLiteDataSourceOptions(),
+		config.GetDatabaseOptions(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed init library DB instance: %w", err)
@@ -255,7 +272,7 @@ func NewAPIListener(
 		a.accessLogFile = accessLogFile
 	}
 
-	sessionDB, err := session.NewSqliteProvider(path.Join(config.Server.DataDir, "api_sessions.db"), config.Server.GetSQLiteDataSourceOptions())
+	sessionDB, err := session.NewSqliteProvider(path.Join(config.Server.DataDir, "api_sessions.db"), config.GetDatabaseOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -265,6 +282,11 @@ func NewAPIListener(
 		return nil, err
 	}
 
+	a.apiKeys, err = apikeys.NewSqliteProvider(path.Join(config.Server.DataDir, "api_keys.db"), config.GetDatabaseOptions())
+	if err != nil {
+		return nil, err
+	}
+
 	a.initRouter()
 
 	return a, nil
@@ -323,6 +345,9 @@ func (al *APIListener) Close() error {
 	if al.apiSessions != nil {
 		g.Go(al.apiSessions.Close)
 	}
+	if al.apiKeys != nil {
+		g.Go(al.apiKeys.Close)
+	}
 
 	return g.Wait()
 }
@@ -330,29 +355,35 @@ func (al *APIListener) Close() error {
 var ErrTooManyRequests = errors.New("too many requests, please try later")
 var ErrThatPasswordHasExpired = errors.New("password has expired, please change your password")
 
-// lookupUser is used to get the user on every request in auth middleware
-func (al *APIListener) lookupUser(r *http.Request, isBearerOnly bool) (authorized bool, username string, err error) {
+// lookupUser is used to get the user on every request in auth middleware, apiKey is set if the request is
+// authenticated with an API key
+func (al *APIListener) lookupUser(r *http.Request, isBearerOnly bool) (authorized bool, username string, apiKey *apikeys.APIKey, err error) {
 	if !isBearerOnly {
 		if basicUser, basicPwd, basicAuthProvided := r.BasicAuth(); basicAuthProvided {
-			return al.handleBasicAuth(basicUser, basicPwd)
+			authorized, username, err = al.handleBasicAuth(basicUser, basicPwd)
+			return authorized, username, nil, err
 		}
 	}
 
 	if bearerToken, bearerAuthProvided := bearer.GetBearerToken(r); bearerAuthProvided {
+		if apikeys.IsToken(bearerToken) {
+			return al.checkAPIKey(r.Context(), bearerToken, chshare.PeerIP(r))
+		}
+
 		isAuthorized, token, err := al.checkBearerToken(r.Context(), bearerToken, r.URL.Path, r.Method)
 		if err != nil {
-			return isAuthorized, "", err
+			return isAuthorized, "", nil, err
 		}
 
-		return isAuthorized, token.AppClaims.Username, nil
+		return isAuthorized, token.AppClaims.Username, nil, nil
 	}
 
 	// case when no auth method is provided
 	if al.bannedUsers.IsBanned("") {
-		return false, "", ErrTooManyRequests
+		return false, "", nil, ErrTooManyRequests
 	}
 
-	return false, "", nil
+	return false, "", nil, nil
 }
 
 // handleBasicAuth checks username and password against either user's password or token
@@ -379,7 +410,10 @@ func (al *APIListener) handleBasicAuth(username, password string) (authorized bo
 
 	// skip basic auth with password when 2fa is enabled
 	if !al.config.API.IsTwoFAOn() && !al.config.API.TotPEnabled {
-		passwordOk := verifyPassword(user.Password, password)
+		passwordOk, err := al.verifyUserPassword(user, password)
+		if err != nil {
+			return false, username, err
+		}
 		if passwordOk {
 			return true, username, nil
 		}
@@ -431,6 +465,35 @@ func (al *APIListener) checkBearerToken(ctx context.Context, bearerToken, uri, m
 	return authorized, tokenCtx, nil
 }
 
+// checkAPIKey looks up an API key by the hash of the token and checks its expiry and IP allow-list
+func (al *APIListener) checkAPIKey(ctx context.Context, token, remoteIP string) (bool, string, *apikeys.APIKey, error) {
+	apiKey, err := al.apiKeys.GetByHash(ctx, apikeys.Hash(token))
+	if err != nil {
+		return false, "", nil, err
+	}
+	if apiKey == nil {
+		al.Errorf("API key not found")
+		return false, "", nil, nil
+	}
+
+	if al.bannedUsers.IsBanned(apiKey.Username) {
+		al.Errorf("User %s is banned", apiKey.Username)
+		return false, "", nil, ErrTooManyRequests
+	}
+
+	if apiKey.IsExpired(time.Now()) {
+		al.Errorf("API key %s expired at %v", apiKey.ID, apiKey.ExpiresAt)
+		return false, apiKey.Username, nil, nil
+	}
+
+	if !apiKey.AllowsIP(remoteIP) {
+		al.Errorf("API key %s is not allowed to be used from %s", apiKey.ID, remoteIP)
+		return false, apiKey.Username, nil, nil
+	}
+
+	return true, apiKey.Username, apiKey, nil
+}
+
 const htpasswdBcryptPrefix = "$2y$"
 
 // validateCredentials returns true if given credentials belong to a user with access to the API.
@@ -468,7 +531,8 @@ func (al *APIListener) validateCredentials(username, password string, skipPasswo
 		return true, user, nil
 	}
 
-	return verifyPassword(user.Password, password), user, nil
+	passwordOk, err := al.verifyUserPassword(user, password)
+	return passwordOk, user, err
 }
 
 func (al *APIListener) shouldCreateMissingUser(user *users.User, skipPasswordValidation bool) bool {
@@ -488,6 +552,15 @@ func isOAuthPermittedUserList(cfg *chconfig.Config) (is bool) {
 	return cfg.PlusConfig.OAuthConfig.PermittedUserList
 }
 
+// verifyUserPassword verifies the password with the users provider if it's able to, e.g. LDAP, otherwise it's compared
+// with the saved one
+func (al *APIListener) verifyUserPassword(user *users.User, password string) (bool, error) {
+	if verifier := al.userService.PasswordVerifier(); verifier != nil {
+		return verifier.VerifyPassword(user.Username, password)
+	}
+	return verifyPassword(user.Password, password), nil
+}
+
 func verifyPassword(saved, provided string) bool {
 	// bcrypt hashed password
 	if strings.HasPrefix(saved, htpasswdBcryptPrefix) {
@@ -523,6 +596,7 @@ func (al *APIListener) wsAuth(f http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var authorized bool
 		var username string
+		var apiKey *apikeys.APIKey
 		var err error
 
 		tokenStr := r.URL.Query().Get(WebSocketAccessTokenQueryParam)
@@ -538,6 +612,8 @@ func (al *APIListener) wsAuth(f http.Handler) http.HandlerFunc {
 				al.jsonErrorResponse(w, http.StatusUnauthorized, errAccessTokenRequired)
 				return
 			}
+		} else if apikeys.IsToken(tokenStr) {
+			authorized, username, apiKey, err = al.checkAPIKey(r.Context(), tokenStr, chshare.PeerIP(r))
 		} else {
 			var token *bearer.TokenContext
 			authorized, token, err = al.checkBearerToken(r.Context(), tokenStr, r.URL.Path, r.Method)
@@ -561,11 +637,17 @@ func (al *APIListener) wsAuth(f http.Handler) http.HandlerFunc {
 
 		if !authorized || username == "" {
 			al.bannedUsers.Add(username)
+			if _, _, basicAuthProvided := r.BasicAuth(); basicAuthProvided && tokenStr == "" && username != "" {
+				al.onLoginFailed(r, username)
+			}
 			al.jsonErrorResponse(w, http.StatusUnauthorized, errUnauthorized)
 			return
 		}
 
 		newCtx := api.WithUser(r.Context(), username)
+		if apiKey != nil {
+			newCtx = apikeys.WithAPIKey(newCtx, apiKey)
+		}
 		f.ServeHTTP(w, r.WithContext(newCtx))
 	}
 }
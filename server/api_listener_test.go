@@ -132,3 +132,31 @@ func TestValidateCredentials(t *testing.T) {
 		assert.Equalf(t, gotRes, tc.wantRes, msg)
 	}
 }
+
+// passwordVerifierProvider is a users provider that verifies passwords itself like the LDAP one
+type passwordVerifierProvider struct {
+	*users.StaticProvider
+	passwords map[string]string
+}
+
+func (p *passwordVerifierProvider) VerifyPassword(username, password string) (bool, error) {
+	return p.passwords[username] == password, nil
+}
+
+func TestValidateCredentialsWithPasswordVerifier(t *testing.T) {
+	provider := &passwordVerifierProvider{
+		StaticProvider: users.NewStaticProvider([]*users.User{{Username: "admin"}}),
+		passwords:      map[string]string{"admin": "directory-pwd"},
+	}
+	al := &APIListener{
+		userService: users.NewAPIService(provider, false, 0, -1),
+	}
+
+	ok, _, err := al.validateCredentials("admin", "directory-pwd", false)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, _, err = al.validateCredentials("admin", "", false)
+	assert.NoError(t, err)
+	assert.False(t, ok, "the saved password must not be used")
+}
@@ -2,17 +2,21 @@ package chserver
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 
 	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/apikeys"
 	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
 	"github.com/cloudradar-monitoring/rport/server/api/users"
 	"github.com/cloudradar-monitoring/rport/server/bearer"
+	"github.com/cloudradar-monitoring/rport/server/cluster"
 	"github.com/cloudradar-monitoring/rport/server/routes"
 	"github.com/cloudradar-monitoring/rport/share/enums"
 	"github.com/cloudradar-monitoring/rport/share/logger"
@@ -44,19 +48,53 @@ func (al *APIListener) wrapAdminAccessMiddleware(next http.Handler) http.Handler
 			return
 		}
 
-		if user.IsAdmin() {
-			next.ServeHTTP(w, r)
+		err = al.checkAdminAccess(r.Context(), user)
+		if err != nil {
+			al.jsonError(w, err)
 			return
 		}
 
-		al.jsonError(w, errors2.APIError{
+		next.ServeHTTP(w, r)
+	})
+}
+
+// checkAdminAccess returns nil if the user is an admin and the request isn't authenticated by an API key
+// without admin access
+func (al *APIListener) checkAdminAccess(ctx context.Context, user *users.User) error {
+	if !user.IsAdmin() {
+		return errors2.APIError{
 			Message: fmt.Sprintf(
 				"current user should belong to %s group to access this resource",
 				users.Administrators,
 			),
 			HTTPStatus: http.StatusForbidden,
-		})
-	})
+		}
+	}
+
+	if apiKey := apikeys.FromContext(ctx); apiKey != nil && !apiKey.Admin {
+		return errors2.APIError{
+			Message:    fmt.Sprintf("API key %q has no admin access", apiKey.Name),
+			HTTPStatus: http.StatusForbidden,
+		}
+	}
+
+	return nil
+}
+
+// wrapNoAPIKeyMiddleware rejects requests authenticated by an API key, it protects the routes that issue or change
+// the credentials of the user
+func (al *APIListener) wrapNoAPIKeyMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if apiKey := apikeys.FromContext(r.Context()); apiKey != nil {
+			al.jsonError(w, errors2.APIError{
+				Message:    fmt.Sprintf("API key %q can't be used to manage credentials", apiKey.Name),
+				HTTPStatus: http.StatusForbidden,
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
 }
 
 func (al *APIListener) wrapTotPEnabledMiddleware(next http.HandlerFunc) http.HandlerFunc {
@@ -70,10 +108,177 @@ func (al *APIListener) wrapTotPEnabledMiddleware(next http.HandlerFunc) http.Han
 	}
 }
 
+// wrapMetricsTokenMiddleware checks the dedicated bearer token of the metrics endpoint
+func (al *APIListener) wrapMetricsTokenMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearer.GetBearerToken(r)
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(al.config.API.MetricsToken)) != 1 {
+			al.jsonErrorResponseWithTitle(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
+
+func (al *APIListener) wrapAlertsEnabledMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if al.alertsService == nil {
+			al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "alerting is disabled")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (al *APIListener) wrapRecordingsEnabledMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if al.recordingsService == nil {
+			al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "session recording is disabled")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (al *APIListener) wrapApprovalsEnabledMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if al.approvalsService == nil {
+			al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "approvals are disabled")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (al *APIListener) wrapWebhooksEnabledMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if al.webhooksService == nil {
+			al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "webhooks are disabled")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (al *APIListener) wrapTunnelHistoryEnabledMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if al.tunnelHistory == nil {
+			al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "tunnel history is disabled")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (al *APIListener) wrapTunnelSharesEnabledMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if al.tunnelShares == nil {
+			al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "tunnel sharing is disabled")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (al *APIListener) wrapSelfUpdateEnabledMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if al.clientBinaries == nil {
+			al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "client self-update is disabled")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// wrapClusterForwardMiddleware forwards requests for a client connected to another node of the cluster to that node
+func (al *APIListener) wrapClusterForwardMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if al.clusterService == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// forwarded requests are handled by this node in any case to avoid loops
+		if cluster.NodeIDFromContext(r.Context()) != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		client, err := al.clientService.GetActiveByID(mux.Vars(r)[routes.ParamClientID])
+		if err != nil {
+			al.jsonError(w, err)
+			return
+		}
+		if client == nil || client.Node == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		node := al.clusterService.Node(client.Node)
+		if node == nil {
+			al.jsonErrorResponseWithTitle(w, http.StatusBadGateway, fmt.Sprintf("Cluster node %q the client is connected to is gone.", client.Node))
+			return
+		}
+
+		al.clusterService.Forward(w, r, node, func(w http.ResponseWriter, err error) {
+			al.jsonErrorResponseWithError(w, http.StatusBadGateway, fmt.Sprintf("Cluster node %q the client is connected to is not reachable.", node.ID), err)
+		})
+	})
+}
+
+// wrapClusterNodeMiddleware allows only requests sent by other nodes of the cluster
+func (al *APIListener) wrapClusterNodeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if al.clusterService == nil {
+			al.jsonErrorResponseWithTitle(w, http.StatusBadRequest, "clustering is disabled")
+			return
+		}
+
+		if cluster.NodeIDFromContext(r.Context()) == "" {
+			al.jsonErrorResponseWithError(w, http.StatusUnauthorized, "Invalid cluster request.", errors.New("missing cluster node header"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// wrapClusterVerifyMiddleware verifies the requests sent by other nodes of the cluster before they are authenticated.
+// The address of a forwarded request is replaced by the address of its caller, so the caller is authenticated and
+// banned instead of the forwarding node.
+func (al *APIListener) wrapClusterVerifyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if al.clusterService == nil || !cluster.IsForwarded(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		nodeID, err := al.clusterService.Verify(r)
+		if err != nil {
+			al.jsonErrorResponseWithError(w, http.StatusUnauthorized, "Invalid cluster request.", err)
+			return
+		}
+
+		r = r.WithContext(cluster.WithNodeID(r.Context(), nodeID))
+		if peerAddr := r.Header.Get(cluster.HeaderPeerAddr); peerAddr != "" {
+			r.RemoteAddr = peerAddr
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (al *APIListener) wrapWithAuthMiddleware(isBearerOnly bool) mux.MiddlewareFunc {
 	return func(f http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			authorized, username, err := al.lookupUser(r, isBearerOnly)
+			authorized, username, apiKey, err := al.lookupUser(r, isBearerOnly)
 			if err != nil {
 				al.Logf(logger.LogLevelError, err.Error())
 				if errors.Is(err, ErrTooManyRequests) {
@@ -90,14 +295,26 @@ func (al *APIListener) wrapWithAuthMiddleware(isBearerOnly bool) mux.MiddlewareF
 
 			if !authorized || username == "" {
 				al.bannedUsers.Add(username)
+				if _, _, basicAuthProvided := r.BasicAuth(); basicAuthProvided && !isBearerOnly && username != "" {
+					al.onLoginFailed(r, username)
+				}
 				al.jsonErrorResponse(w, http.StatusUnauthorized, errors.New("unauthorized"))
 				return
 			}
 
 			newCtx := api.WithUser(r.Context(), username)
+			if apiKey != nil {
+				newCtx = apikeys.WithAPIKey(newCtx, apiKey)
+			}
 
 			token, hasBearerToken := bearer.GetBearerToken(r)
-			if hasBearerToken {
+			if apiKey != nil {
+				err = al.apiKeys.UpdateLastUsed(newCtx, apiKey.ID, time.Now())
+				if err != nil {
+					al.jsonError(w, err)
+					return
+				}
+			} else if hasBearerToken {
 				err = al.updateTokenAccess(newCtx, token, time.Now(), r.UserAgent(), r.RemoteAddr)
 				if err != nil {
 					al.jsonError(w, err)
@@ -134,7 +351,7 @@ func (al *APIListener) wrapClientAccessMiddleware(next http.Handler) http.Handle
 		if err != nil {
 			al.jsonError(w, err)
 		}
-		err = al.clientService.CheckClientAccess(clientID, curUser, clientGroups)
+		err = al.clientService.CheckClientAccess(clientID, curUser, clientGroups, "")
 		if err != nil {
 			al.jsonError(w, err)
 			return
@@ -145,6 +362,11 @@ func (al *APIListener) wrapClientAccessMiddleware(next http.Handler) http.Handle
 }
 
 func (al *APIListener) permissionsMiddleware(permission string) mux.MiddlewareFunc {
+	return al.anyPermissionMiddleware(permission)
+}
+
+// anyPermissionMiddleware lets pass requests of users having at least one of the given permissions
+func (al *APIListener) anyPermissionMiddleware(permissions ...string) mux.MiddlewareFunc {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if al.insecureForTests {
@@ -158,19 +380,73 @@ func (al *APIListener) permissionsMiddleware(permission string) mux.MiddlewareFu
 				return
 			}
 
-			if al.userService.SupportsGroupPermissions() {
-				// Check group permissions only if supported otherwise let pass.
-				err = al.userService.CheckPermission(currUser, permission)
-				if err != nil {
-					al.jsonError(w, err)
+			for _, permission := range permissions {
+				err = al.checkRoutePermission(r, currUser, permission)
+				if err == nil {
+					next.ServeHTTP(w, r)
 					return
 				}
 			}
 
-			next.ServeHTTP(w, r)
+			al.jsonError(w, err)
 		})
+	}
+}
 
+// apiKeyPermissionMiddleware lets pass requests of API keys having at least one of the given permissions. Requests
+// that are not authenticated by an API key are passed without checking the permissions of the user.
+func (al *APIListener) apiKeyPermissionMiddleware(permissions ...string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey := apikeys.FromContext(r.Context())
+			if apiKey == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			for _, permission := range permissions {
+				if apiKey.Permissions.Has(permission) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			al.jsonError(w, errors2.APIError{
+				Message:    fmt.Sprintf("API key %q has none of the permissions %s", apiKey.Name, strings.Join(permissions, ", ")),
+				HTTPStatus: http.StatusForbidden,
+			})
+		})
+	}
+}
+
+// checkRoutePermission returns nil if the user and the API key of the request have the permission. For routes of a
+// client the permission is checked for the client.
+func (al *APIListener) checkRoutePermission(r *http.Request, currUser *users.User, permission string) error {
+	if apiKey := apikeys.FromContext(r.Context()); apiKey != nil && !apiKey.Permissions.Has(permission) {
+		return errors2.APIError{
+			Message:    fmt.Sprintf("API key %q has no %s permission", apiKey.Name, permission),
+			HTTPStatus: http.StatusForbidden,
+		}
 	}
+
+	if al.userService.SupportsGroupPermissions() {
+		// Check group permissions only if supported otherwise let pass.
+		err := al.userService.CheckPermission(currUser, permission)
+		if err != nil {
+			return err
+		}
+	}
+
+	if clientID := mux.Vars(r)[routes.ParamClientID]; clientID != "" && users.IsClientPermission(permission) {
+		// the permission might be granted only for some client groups
+		clientGroups, err := al.clientGroupProvider.GetAll(r.Context())
+		if err != nil {
+			return err
+		}
+		return al.clientService.CheckClientAccess(clientID, currUser, clientGroups, permission)
+	}
+
+	return nil
 }
 
 func (al *APIListener) updateTokenAccess(ctx context.Context, token string, accessTime time.Time, userAgent string, remoteAddress string) (err error) {
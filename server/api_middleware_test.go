@@ -0,0 +1,183 @@
+package chserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/apikeys"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/cgroups"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+)
+
+func TestPermissionsMiddlewareWithClientGroupPermissions(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlx.Connect("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlExecs := []string{
+		`CREATE TABLE "users" ("username" TEXT PRIMARY KEY, "password" TEXT, "password_expired" BOOLEAN NOT NULL CHECK (password_expired IN (0, 1)) DEFAULT 0)`,
+		`INSERT INTO "users" VALUES("test-user","1", false)`,
+		`CREATE TABLE "groups" ("username" TEXT, "group" TEXT)`,
+		`INSERT INTO "groups" VALUES("test-user","web-team")`,
+		`CREATE TABLE "group_details" ("name" TEXT, "permissions" TEXT, "client_group_permissions" TEXT)`,
+		`CREATE UNIQUE INDEX "main"."username_group_name" ON "group_details" ("name" ASC)`,
+		`INSERT INTO "group_details" VALUES('web-team','{"vault":true}','{"web":{"commands":true,"tunnels":true},"db":{"tunnels":true}}')`,
+	}
+	for _, sqlExec := range sqlExecs {
+		_, err = db.Exec(sqlExec)
+		require.NoError(t, err)
+	}
+
+	userProvider, err := users.NewUserDatabase(db, "users", "groups", "group_details", false, false, testLog)
+	require.NoError(t, err)
+
+	gp := makeGroupsProvider(t, DataSourceOptions)
+	defer gp.Close()
+	for id, pattern := range map[string]string{"web": "web-*", "db": "db-*"} {
+		require.NoError(t, gp.Create(ctx, &cgroups.ClientGroup{
+			ID:                id,
+			AllowedUserGroups: []string{"web-team"},
+			Params:            &cgroups.ClientParams{ClientID: &cgroups.ParamValues{cgroups.Param(pattern)}},
+		}))
+	}
+
+	c1 := clients.New(t).ID("web-1").Build()
+	c2 := clients.New(t).ID("db-1").Build()
+	al := APIListener{
+		Logger:      testLog,
+		userService: users.NewAPIService(userProvider, false, 0, -1),
+		Server: &Server{
+			clientService:       NewClientService(nil, nil, clients.NewClientRepository([]*clients.Client{c1, c2}, &hour, testLog), testLog),
+			clientGroupProvider: gp,
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		permission string
+		clientID   string
+		wantStatus int
+	}{
+		{
+			name:       "permission for client group of client",
+			permission: users.PermissionCommands,
+			clientID:   "web-1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "permission for other client group only",
+			permission: users.PermissionCommands,
+			clientID:   "db-1",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "permission for both client groups",
+			permission: users.PermissionTunnels,
+			clientID:   "db-1",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "permission for no client group",
+			permission: users.PermissionScripts,
+			clientID:   "web-1",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.Handle("/clients/{client_id}", al.permissionsMiddleware(tc.permission)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/clients/"+tc.clientID, nil)
+			req = req.WithContext(api.WithUser(req.Context(), "test-user"))
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatus, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestAPIKeyPermissionMiddleware(t *testing.T) {
+	db, err := sqlx.Connect("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	sqlExecs := []string{
+		`CREATE TABLE "users" ("username" TEXT PRIMARY KEY, "password" TEXT, "password_expired" BOOLEAN NOT NULL CHECK (password_expired IN (0, 1)) DEFAULT 0)`,
+		`INSERT INTO "users" VALUES("test-user","1", false)`,
+		`CREATE TABLE "groups" ("username" TEXT, "group" TEXT)`,
+		`INSERT INTO "groups" VALUES("test-user","guests")`,
+		`CREATE TABLE "group_details" ("name" TEXT, "permissions" TEXT, "client_group_permissions" TEXT)`,
+		`CREATE UNIQUE INDEX "main"."username_group_name" ON "group_details" ("name" ASC)`,
+		`INSERT INTO "group_details" VALUES('guests','{}','{}')`,
+	}
+	for _, sqlExec := range sqlExecs {
+		_, err = db.Exec(sqlExec)
+		require.NoError(t, err)
+	}
+
+	userProvider, err := users.NewUserDatabase(db, "users", "groups", "group_details", false, false, testLog)
+	require.NoError(t, err)
+	al := APIListener{
+		Logger:      testLog,
+		userService: users.NewAPIService(userProvider, false, 0, -1),
+	}
+
+	testCases := []struct {
+		name       string
+		apiKey     *apikeys.APIKey
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "user without permissions",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "api key with one of the permissions",
+			apiKey:     &apikeys.APIKey{Name: "ci", Permissions: users.NewPermissions(users.PermissionScripts)},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "api key without the permissions",
+			apiKey:     &apikeys.APIKey{Name: "audit", Permissions: users.NewPermissions(users.PermissionsAuditLog)},
+			wantStatus: http.StatusForbidden,
+			wantError:  `API key \"audit\" has none of the permissions commands, scripts`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := al.apiKeyPermissionMiddleware(users.PermissionCommands, users.PermissionScripts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/client-groups", nil)
+			ctx := api.WithUser(req.Context(), "test-user")
+			if tc.apiKey != nil {
+				ctx = apikeys.WithAPIKey(ctx, tc.apiKey)
+			}
+			handler.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, tc.wantStatus, w.Code, w.Body.String())
+			if tc.wantError != "" {
+				assert.Contains(t, w.Body.String(), tc.wantError)
+			}
+		})
+	}
+}
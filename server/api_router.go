@@ -36,14 +36,20 @@ func (al *APIListener) initRouter() {
 		secureAPI.Use(al.wrapWithAuthMiddleware(false))
 	}
 	secureAPI.HandleFunc("/status", al.handleGetStatus).Methods(http.MethodGet)
-	secureAPI.HandleFunc("/me", al.handleGetMe).Methods(http.MethodGet)
-	secureAPI.HandleFunc("/me", al.handleChangeMe).Methods(http.MethodPut)
+	secureAPI.HandleFunc(routes.MeRoute, al.handleGetMe).Methods(http.MethodGet)
+	secureAPI.HandleFunc(routes.MeRoute, al.wrapNoAPIKeyMiddleware(al.handleChangeMe)).Methods(http.MethodPut)
 	secureAPI.HandleFunc("/me/ip", al.handleGetIP).Methods(http.MethodGet)
-	secureAPI.HandleFunc("/me/token", al.handlePostToken).Methods(http.MethodPost)
-	secureAPI.HandleFunc("/me/token", al.handleDeleteToken).Methods(http.MethodDelete)
+	secureAPI.HandleFunc(routes.MeTokenRoute, al.wrapNoAPIKeyMiddleware(al.handlePostToken)).Methods(http.MethodPost)
+	secureAPI.HandleFunc(routes.MeTokenRoute, al.wrapNoAPIKeyMiddleware(al.handleDeleteToken)).Methods(http.MethodDelete)
+	secureAPI.HandleFunc(routes.MeAPIKeysRoute, al.wrapNoAPIKeyMiddleware(al.handleGetAPIKeys)).Methods(http.MethodGet)
+	secureAPI.HandleFunc(routes.MeAPIKeysRoute, al.wrapNoAPIKeyMiddleware(al.handlePostAPIKey)).Methods(http.MethodPost)
+	secureAPI.HandleFunc(routes.MeAPIKeysRoute+"/{api_key_id}", al.wrapNoAPIKeyMiddleware(al.handleDeleteAPIKey)).Methods(http.MethodDelete)
 
 	secureAPI.HandleFunc("/clients", al.handleGetClients).Methods(http.MethodGet)
+	secureAPI.HandleFunc("/updates-report", al.handleGetUpdatesReport).Methods(http.MethodGet)
+
 	clientDetails := secureAPI.PathPrefix("/clients/{client_id}").Subrouter()
+	clientDetails.Use(al.wrapClusterForwardMiddleware)
 	clientDetails.Use(al.wrapClientAccessMiddleware)
 	clientDetails.HandleFunc("", al.handleGetClient).Methods(http.MethodGet)
 	clientDetails.HandleFunc("", al.handleDeleteClient).Methods(http.MethodDelete)
@@ -55,16 +61,29 @@ func (al *APIListener) initRouter() {
 	clientCommands.HandleFunc("", al.handlePostCommand).Methods(http.MethodPost)
 	clientCommands.HandleFunc("", al.handleGetCommands).Methods(http.MethodGet)
 	clientCommands.HandleFunc("/{job_id}", al.handleGetCommand).Methods(http.MethodGet)
+	clientCommands.HandleFunc("/{job_id}", al.handleCancelCommand).Methods(http.MethodDelete)
+	clientDetails.Handle("/updates-installation", al.permissionsMiddleware(users.PermissionCommands)(http.HandlerFunc(al.handlePostUpdatesInstallation))).Methods(http.MethodPost)
+	clientDetails.Handle("/update", al.wrapSelfUpdateEnabledMiddleware(al.permissionsMiddleware(users.PermissionCommands)(http.HandlerFunc(al.handlePostClientUpdate)))).Methods(http.MethodPost)
 
 	clientTunnels := clientDetails.NewRoute().Subrouter()
 	clientTunnels.Use(al.permissionsMiddleware(users.PermissionTunnels))
 	clientTunnels.HandleFunc("/tunnels", al.handlePutClientTunnel).Methods(http.MethodPut)
 	clientTunnels.HandleFunc("/tunnels/{tunnel_id}", al.handleDeleteClientTunnel).Methods(http.MethodDelete)
+	tunnelShares := clientTunnels.PathPrefix("/tunnels/{tunnel_id}/shares").Subrouter()
+	tunnelShares.Use(al.wrapTunnelSharesEnabledMiddleware)
+	tunnelShares.HandleFunc("", al.handleGetTunnelShares).Methods(http.MethodGet)
+	tunnelShares.HandleFunc("", al.handlePostTunnelShare).Methods(http.MethodPost)
+	tunnelShares.HandleFunc("/{"+routes.ParamShareID+"}", al.handleDeleteTunnelShare).Methods(http.MethodDelete)
 	clientTunnels.HandleFunc("/stored-tunnels", al.handleGetStoredTunnels).Methods(http.MethodGet)
 	clientTunnels.HandleFunc("/stored-tunnels", al.handlePostStoredTunnels).Methods(http.MethodPost)
 	clientTunnels.HandleFunc("/stored-tunnels/{tunnel_id}", al.handleDeleteStoredTunnel).Methods(http.MethodDelete)
 	clientTunnels.HandleFunc("/stored-tunnels/{tunnel_id}", al.handlePutStoredTunnel).Methods(http.MethodPut)
 
+	clientFiles := clientDetails.PathPrefix("/files").Subrouter()
+	clientFiles.Use(al.permissionsMiddleware(users.PermissionUploads))
+	clientFiles.HandleFunc("", al.handleGetClientFiles).Methods(http.MethodGet)
+	clientFiles.HandleFunc("/content", al.handleGetClientFileContent).Methods(http.MethodGet)
+
 	clientMonitoring := clientDetails.NewRoute().Subrouter()
 	clientMonitoring.Use(al.permissionsMiddleware(users.PermissionMonitoring))
 	clientMonitoring.HandleFunc("/updates-status", al.handleRefreshUpdatesStatus).Methods(http.MethodPost)
@@ -73,19 +92,36 @@ func (al *APIListener) initRouter() {
 	clientMonitoring.HandleFunc("/metrics", al.handleGetClientMetrics).Methods(http.MethodGet)
 	clientMonitoring.HandleFunc("/processes", al.handleGetClientProcesses).Methods(http.MethodGet)
 	clientMonitoring.HandleFunc("/mountpoints", al.handleGetClientMountpoints).Methods(http.MethodGet)
+	clientMonitoring.HandleFunc("/checks", al.handleGetClientChecks).Methods(http.MethodGet)
+	clientMonitoring.HandleFunc("/measurements/export", al.handleExportClientMeasurements).Methods(http.MethodGet)
+	clientMonitoring.HandleFunc("/inventory", al.handleGetClientInventory).Methods(http.MethodGet)
+	clientMonitoring.HandleFunc("/inventory/changes", al.handleListClientInventoryChanges).Methods(http.MethodGet)
 
 	secureAPI.Handle("/tunnels", al.permissionsMiddleware(users.PermissionTunnels)(http.HandlerFunc(al.handleGetTunnels))).Methods(http.MethodGet)
+	tunnelHistory := secureAPI.PathPrefix("/tunnels/history").Subrouter()
+	tunnelHistory.Use(al.permissionsMiddleware(users.PermissionTunnels), al.wrapTunnelHistoryEnabledMiddleware)
+	tunnelHistory.HandleFunc("", al.handleListTunnelHistory).Methods(http.MethodGet)
+	tunnelHistory.HandleFunc("/{"+routes.ParamSessionID+"}/connections", al.handleListTunnelHistoryConnections).Methods(http.MethodGet)
+	secureAPI.Handle("/inventory", al.permissionsMiddleware(users.PermissionMonitoring)(http.HandlerFunc(al.handleListInventory))).Methods(http.MethodGet)
 	secureAPI.Handle("/auditlog", al.permissionsMiddleware(users.PermissionsAuditLog)(http.HandlerFunc(al.handleListAuditLog))).Methods(http.MethodGet)
 	secureAPI.Handle("/files", al.permissionsMiddleware(users.PermissionUploads)(http.HandlerFunc(al.handleFileUploads))).Methods(http.MethodPost).Name(routes.FilesUploadRouteName)
 
-	secureAPI.HandleFunc("/client-groups", al.handleGetClientGroups).Methods(http.MethodGet)
-	secureAPI.HandleFunc("/client-groups/{group_id}", al.handleGetClientGroup).Methods(http.MethodGet)
+	clientGroups := secureAPI.NewRoute().Subrouter()
+	clientGroups.Use(al.apiKeyPermissionMiddleware(users.ClientPermissions...))
+	clientGroups.HandleFunc("/client-groups", al.handleGetClientGroups).Methods(http.MethodGet)
+	clientGroups.HandleFunc("/client-groups/{group_id}", al.handleGetClientGroup).Methods(http.MethodGet)
+	secureAPI.Handle("/client-groups/{group_id}/measurements/export", al.permissionsMiddleware(users.PermissionMonitoring)(http.HandlerFunc(al.handleExportClientGroupMeasurements))).Methods(http.MethodGet)
 
 	adminOnly := secureAPI.NewRoute().Subrouter()
 	adminOnly.Use(al.wrapAdminAccessMiddleware)
 	adminOnly.HandleFunc("/client-groups", al.handlePostClientGroups).Methods(http.MethodPost)
 	adminOnly.HandleFunc("/client-groups/{group_id}", al.handlePutClientGroup).Methods(http.MethodPut)
 	adminOnly.HandleFunc("/client-groups/{group_id}", al.handleDeleteClientGroup).Methods(http.MethodDelete)
+	adminOnly.HandleFunc("/config-profiles", al.handleGetConfigProfiles).Methods(http.MethodGet)
+	adminOnly.HandleFunc("/config-profiles", al.handlePostConfigProfiles).Methods(http.MethodPost)
+	adminOnly.HandleFunc("/config-profiles/{profile_id}", al.handleGetConfigProfile).Methods(http.MethodGet)
+	adminOnly.HandleFunc("/config-profiles/{profile_id}", al.handlePutConfigProfile).Methods(http.MethodPut)
+	adminOnly.HandleFunc("/config-profiles/{profile_id}", al.handleDeleteConfigProfile).Methods(http.MethodDelete)
 	adminOnly.HandleFunc("/users", al.wrapStaticPassModeMiddleware(al.handleGetUsers)).Methods(http.MethodGet)
 	adminOnly.HandleFunc("/users", al.wrapStaticPassModeMiddleware(al.handleChangeUser)).Methods(http.MethodPost)
 	adminOnly.HandleFunc("/users/{user_id}", al.wrapStaticPassModeMiddleware(al.handleChangeUser)).Methods(http.MethodPut)
@@ -112,7 +148,11 @@ func (al *APIListener) initRouter() {
 	commands.HandleFunc("/commands", al.handlePostMultiClientCommand).Methods(http.MethodPost)
 	commands.HandleFunc("/commands", al.handleGetMultiClientCommands).Methods(http.MethodGet)
 	commands.HandleFunc("/commands/{job_id}", al.handleGetMultiClientCommand).Methods(http.MethodGet)
+	commands.HandleFunc("/commands/{job_id}", al.handleCancelMultiClientCommand).Methods(http.MethodDelete)
 	commands.HandleFunc("/commands/{job_id}/jobs", al.handleGetMultiClientCommandJobs).Methods(http.MethodGet)
+	commands.HandleFunc("/updates-installation", al.handlePostMultiClientUpdatesInstallation).Methods(http.MethodPost)
+	commands.Handle("/client-updates", al.wrapSelfUpdateEnabledMiddleware(http.HandlerFunc(al.handlePostMultiClientUpdate))).Methods(http.MethodPost)
+	commands.Handle("/client-binaries", al.wrapSelfUpdateEnabledMiddleware(http.HandlerFunc(al.handleGetClientBinaries))).Methods(http.MethodGet)
 	commands.HandleFunc("/library/commands", al.handleListCommands).Methods(http.MethodGet)
 	commands.HandleFunc("/library/commands", al.handleCommandCreate).Methods(http.MethodPost)
 	commands.HandleFunc("/library/commands/{"+routes.ParamCommandValueID+"}", al.handleCommandUpdate).Methods(http.MethodPut)
@@ -148,9 +188,40 @@ func (al *APIListener) initRouter() {
 	schedules.HandleFunc("/{schedule_id}", al.handleUpdateSchedule).Methods(http.MethodPut)
 	schedules.HandleFunc("/{schedule_id}", al.handleDeleteSchedule).Methods(http.MethodDelete)
 
-	secureAPI.HandleFunc(routes.TotPRoutes, al.wrapTotPEnabledMiddleware(al.handleGetTotP)).Methods(http.MethodGet)
-	secureAPI.HandleFunc(routes.TotPRoutes, al.wrapTotPEnabledMiddleware(al.handlePostTotP)).Methods(http.MethodPost)
-	secureAPI.HandleFunc(routes.TotPRoutes, al.wrapTotPEnabledMiddleware(al.handleDeleteTotP)).Methods(http.MethodDelete)
+	alerts := secureAPI.PathPrefix("/alerts").Subrouter()
+	alerts.Use(al.permissionsMiddleware(users.PermissionMonitoring), al.wrapAlertsEnabledMiddleware)
+	alerts.HandleFunc("", al.handleListAlerts).Methods(http.MethodGet)
+	alerts.HandleFunc("/rules", al.handleListAlertRules).Methods(http.MethodGet)
+	alerts.Handle("/rules", al.wrapAdminAccessMiddleware(http.HandlerFunc(al.handlePostAlertRule))).Methods(http.MethodPost)
+	alerts.Handle("/rules/{"+routes.ParamAlertRuleID+"}", al.wrapAdminAccessMiddleware(http.HandlerFunc(al.handlePutAlertRule))).Methods(http.MethodPut)
+	alerts.Handle("/rules/{"+routes.ParamAlertRuleID+"}", al.wrapAdminAccessMiddleware(http.HandlerFunc(al.handleDeleteAlertRule))).Methods(http.MethodDelete)
+
+	recordings := secureAPI.PathPrefix("/recordings").Subrouter()
+	recordings.Use(al.permissionsMiddleware(users.PermissionsAuditLog), al.wrapRecordingsEnabledMiddleware)
+	recordings.HandleFunc("", al.handleListRecordings).Methods(http.MethodGet)
+	recordings.HandleFunc("/{"+routes.ParamRecordingID+"}", al.handleGetRecording).Methods(http.MethodGet)
+	recordings.HandleFunc("/{"+routes.ParamRecordingID+"}/download", al.handleDownloadRecording).Methods(http.MethodGet)
+	recordings.Handle("/{"+routes.ParamRecordingID+"}", al.wrapAdminAccessMiddleware(http.HandlerFunc(al.handleDeleteRecording))).Methods(http.MethodDelete)
+
+	approvals := secureAPI.PathPrefix("/approvals").Subrouter()
+	approvals.Use(al.apiKeyPermissionMiddleware(al.approvalsPermissions()...), al.wrapApprovalsEnabledMiddleware)
+	approvals.HandleFunc("", al.handleListApprovals).Methods(http.MethodGet)
+	approvals.HandleFunc("/{"+routes.ParamApprovalID+"}", al.handleGetApproval).Methods(http.MethodGet)
+	approvals.HandleFunc("/{"+routes.ParamApprovalID+"}/approve", al.handleApproveApproval).Methods(http.MethodPost)
+	approvals.HandleFunc("/{"+routes.ParamApprovalID+"}/reject", al.handleRejectApproval).Methods(http.MethodPost)
+
+	webhooksRouter := secureAPI.PathPrefix("/webhooks").Subrouter()
+	webhooksRouter.Use(al.wrapAdminAccessMiddleware, al.wrapWebhooksEnabledMiddleware)
+	webhooksRouter.HandleFunc("", al.handleGetWebhooks).Methods(http.MethodGet)
+	webhooksRouter.HandleFunc("", al.handlePostWebhook).Methods(http.MethodPost)
+	webhooksRouter.HandleFunc("/{"+routes.ParamWebhookID+"}", al.handleGetWebhook).Methods(http.MethodGet)
+	webhooksRouter.HandleFunc("/{"+routes.ParamWebhookID+"}", al.handlePutWebhook).Methods(http.MethodPut)
+	webhooksRouter.HandleFunc("/{"+routes.ParamWebhookID+"}", al.handleDeleteWebhook).Methods(http.MethodDelete)
+	webhooksRouter.HandleFunc("/{"+routes.ParamWebhookID+"}/deliveries", al.handleListWebhookDeliveries).Methods(http.MethodGet)
+
+	secureAPI.HandleFunc(routes.TotPRoutes, al.wrapNoAPIKeyMiddleware(al.wrapTotPEnabledMiddleware(al.handleGetTotP))).Methods(http.MethodGet)
+	secureAPI.HandleFunc(routes.TotPRoutes, al.wrapNoAPIKeyMiddleware(al.wrapTotPEnabledMiddleware(al.handlePostTotP))).Methods(http.MethodPost)
+	secureAPI.HandleFunc(routes.TotPRoutes, al.wrapNoAPIKeyMiddleware(al.wrapTotPEnabledMiddleware(al.handleDeleteTotP))).Methods(http.MethodDelete)
 
 	// all routes defined below do not have authorization middleware, auth is done in each handler separately
 	api.HandleFunc("/login", al.handleGetLogin).Methods(http.MethodGet)
@@ -158,18 +229,29 @@ func (al *APIListener) initRouter() {
 	api.HandleFunc("/logout", al.handleDeleteLogout).Methods(http.MethodDelete)
 	api.Handle(routes.Verify2FaRoute, al.wrapWithAuthMiddleware(true)(al.handlePostVerify2FAToken())).Methods(http.MethodPost)
 
+	// requests of other nodes of the cluster are authenticated by the shared secret
+	api.Handle(routes.ClusterClientRequestsRoute, al.wrapClusterNodeMiddleware(http.HandlerFunc(al.handlePostClusterClientRequest))).Methods(http.MethodPost)
+	api.Handle(routes.ClusterJobResultsRoute, al.wrapClusterNodeMiddleware(http.HandlerFunc(al.handlePostClusterJobResult))).Methods(http.MethodPost)
+
 	// web sockets
 	// common auth middleware is not used due to JS issue https://stackoverflow.com/questions/22383089/is-it-possible-to-use-bearer-authentication-for-websocket-upgrade-requests
 	api.HandleFunc("/ws/commands", al.wsAuth(al.permissionsMiddleware(users.PermissionCommands)(http.HandlerFunc(al.handleCommandsWS)))).Methods(http.MethodGet)
+	api.HandleFunc("/ws/updates-installation", al.wsAuth(al.permissionsMiddleware(users.PermissionCommands)(http.HandlerFunc(al.handleUpdatesInstallationWS)))).Methods(http.MethodGet)
 	api.HandleFunc("/ws/scripts", al.wsAuth(al.permissionsMiddleware(users.PermissionScripts)(http.HandlerFunc(al.handleScriptsWS)))).Methods(http.MethodGet)
 	api.HandleFunc("/ws/uploads", al.wsAuth(al.permissionsMiddleware(users.PermissionUploads)(http.HandlerFunc(al.handleUploadsWS)))).Methods(http.MethodGet)
+	api.HandleFunc("/ws/clients/{client_id}/terminal", al.wsAuth(al.wrapClusterForwardMiddleware(al.permissionsMiddleware(users.PermissionTerminal)(al.wrapClientAccessMiddleware(http.HandlerFunc(al.handleTerminalWS)))))).Methods(http.MethodGet)
 
 	if al.config.Server.EnableWsTestEndpoints {
 		api.HandleFunc("/test/commands/ui", al.wsCommands)
 		api.HandleFunc("/test/scripts/ui", al.wsScripts)
 		api.HandleFunc("/test/uploads/ui", al.wsUploads)
 	}
+	if al.config.API.MetricsToken != "" {
+		api.HandleFunc("/metrics", al.wrapMetricsTokenMiddleware(al.handleGetPrometheusMetrics)).Methods(http.MethodGet)
+	}
 
+	// cluster requests are verified first, the ban list applies to the caller of forwarded requests
+	api.Use(al.wrapClusterVerifyMiddleware)
 	if al.bannedIPs != nil {
 		api.Use(security.RejectBannedIPs(al.bannedIPs))
 	}
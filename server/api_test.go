@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/ssh"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/sqlite"
 	"github.com/cloudradar-monitoring/rport/server/api/session"
 	"github.com/cloudradar-monitoring/rport/server/bearer"
@@ -31,7 +32,7 @@ func (m *mockConnection) Close() error {
 }
 
 func newEmptyAPISessionCache(t *testing.T) *session.Cache {
-	storage, err := session.NewSqliteProvider(":memory:", DataSourceOptions)
+	storage, err := session.NewSqliteProvider(":memory:", database.Options{SQLite: DataSourceOptions})
 	require.NoError(t, err)
 	c, err := session.NewCache(context.Background(), bearer.DefaultTokenLifetime, cleanupAPISessionsInterval, storage, nil)
 	require.NoError(t, err)
@@ -0,0 +1,26 @@
+package chserver
+
+import (
+	"path"
+
+	"github.com/cloudradar-monitoring/rport/server/approvals"
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+func initApprovalsService(config *chconfig.Config, log *logger.Logger) (*approvals.Service, error) {
+	provider, err := approvals.NewSqliteProvider(
+		path.Join(config.Server.DataDir, "approvals.db"),
+		config.GetDatabaseOptions(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	msgSrv, err := newNotificationMessageService(config, config.Approvals.NotificationDelivery)
+	if err != nil {
+		return nil, err
+	}
+
+	return approvals.NewService(provider, config.Approvals.Expiration, msgSrv, config.Approvals.NotificationRecipients, log), nil
+}
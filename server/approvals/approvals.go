@@ -0,0 +1,174 @@
+package approvals
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/server/api/jobs"
+	"github.com/cloudradar-monitoring/rport/server/api/jobs/schedule"
+	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/types"
+)
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+	StatusExpired  Status = "expired"
+)
+
+// Approval is a command, script or updates installation on clients of client groups that require approval, or a
+// schedule targeting them. It's executed or saved only after a second user approved it.
+type Approval struct {
+	ID      string   `json:"id" db:"id"`
+	Status  Status   `json:"status" db:"status"`
+	Request *Request `json:"request" db:"request"`
+	// ClientGroupIDs are the client groups requiring approval the request is targeting
+	ClientGroupIDs types.StringSlice `json:"client_group_ids" db:"client_group_ids"`
+	RequestedBy    string            `json:"requested_by" db:"requested_by"`
+	RequestedAt    time.Time         `json:"requested_at" db:"requested_at"`
+	ExpiresAt      time.Time         `json:"expires_at" db:"expires_at"`
+	DecidedBy      *string           `json:"decided_by" db:"decided_by"`
+	DecidedAt      *time.Time        `json:"decided_at" db:"decided_at"`
+	Comment        string            `json:"comment" db:"comment"`
+	// JobID is the id of the multi-client job started after the approval, or the id of the saved schedule
+	JobID *string `json:"job_id" db:"job_id"`
+	// Error is set if the job couldn't be started after the approval
+	Error string `json:"error" db:"error"`
+}
+
+// Text returns a summary of the approval used in notifications
+func (a *Approval) Text() string {
+	text := fmt.Sprintf("Approval %s is %s for a %s on client groups %s requested by %s.\nRequested at: %s",
+		a.ID, a.Status, a.jobType(), strings.Join(a.ClientGroupIDs, ", "), a.RequestedBy, a.RequestedAt.UTC().Format(time.RFC3339))
+	if a.Status == StatusPending {
+		text += fmt.Sprintf("\nExpires at: %s", a.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	if a.DecidedBy != nil && a.DecidedAt != nil {
+		text += fmt.Sprintf("\nDecided by %s at: %s", *a.DecidedBy, a.DecidedAt.UTC().Format(time.RFC3339))
+	}
+	if a.Comment != "" {
+		text += fmt.Sprintf("\nComment: %s", a.Comment)
+	}
+	if a.Request == nil {
+		return text
+	}
+	if a.Request.Schedule != nil {
+		text += fmt.Sprintf("\nSchedule: %s (%s)", a.Request.Schedule.Name, a.Request.Schedule.Schedule)
+	} else if !a.Request.IsScript && a.Request.Command != "" {
+		text += fmt.Sprintf("\nCommand: %s", a.Request.Command)
+	}
+	return text
+}
+
+func (a *Approval) jobType() string {
+	switch {
+	case a.Request == nil:
+		return "command"
+	case a.Request.Schedule != nil:
+		return "schedule"
+	case a.Request.UpdatesInstallation != nil:
+		return "updates installation"
+	case a.Request.IsScript:
+		return "script"
+	}
+	return "command"
+}
+
+// Request holds the parameters of the job to start or of the schedule to save once approved.
+type Request struct {
+	// ClientIDs are the clients the job is started on. They are resolved from the client ids, group ids and tags
+	// when the approval is requested, GroupIDs and ClientTags are kept for information only.
+	ClientIDs           []string              `json:"client_ids"`
+	GroupIDs            []string              `json:"group_ids"`
+	ClientTags          *models.JobClientTags `json:"tags"`
+	Command             string                `json:"command"`
+	Script              string                `json:"script"`
+	IsScript            bool                  `json:"is_script"`
+	Cwd                 string                `json:"cwd"`
+	IsSudo              bool                  `json:"is_sudo"`
+	Interpreter         string                `json:"interpreter"`
+	TimeoutSec          int                   `json:"timeout_sec"`
+	ExecuteConcurrently bool                  `json:"execute_concurrently"`
+	AbortOnError        *bool                 `json:"abort_on_error"`
+
+	// UpdatesInstallation is set to install pending updates instead of running the command
+	UpdatesInstallation *models.UpdatesInstallation `json:"updates_installation,omitempty"`
+	// Schedule is set to save a schedule instead of starting a job. It's updated if it has an id, created otherwise.
+	Schedule *schedule.Schedule `json:"schedule,omitempty"`
+}
+
+// NewRequest returns the request to approve for a given multi-client job request.
+func NewRequest(multiJobRequest *jobs.MultiJobRequest) *Request {
+	return &Request{
+		ClientIDs:           multiJobRequest.ClientIDs,
+		GroupIDs:            multiJobRequest.GroupIDs,
+		ClientTags:          multiJobRequest.ClientTags,
+		Command:             multiJobRequest.Command,
+		Script:              multiJobRequest.Script,
+		IsScript:            multiJobRequest.IsScript,
+		Cwd:                 multiJobRequest.Cwd,
+		IsSudo:              multiJobRequest.IsSudo,
+		Interpreter:         multiJobRequest.Interpreter,
+		TimeoutSec:          multiJobRequest.TimeoutSec,
+		ExecuteConcurrently: multiJobRequest.ExecuteConcurrently,
+		AbortOnError:        multiJobRequest.AbortOnError,
+		UpdatesInstallation: multiJobRequest.UpdatesInstallation,
+	}
+}
+
+// NewScheduleRequest returns the request to approve for a given schedule.
+func NewScheduleRequest(s *schedule.Schedule) *Request {
+	return &Request{
+		Schedule: s,
+	}
+}
+
+// MultiJobRequest returns the multi-client job request to start the approved job on behalf of the requesting user.
+// The job is started only on the clients resolved when the approval was requested.
+func (r *Request) MultiJobRequest(username string) *jobs.MultiJobRequest {
+	return &jobs.MultiJobRequest{
+		ClientIDs:           r.ClientIDs,
+		Command:             r.Command,
+		Script:              r.Script,
+		IsScript:            r.IsScript,
+		Cwd:                 r.Cwd,
+		IsSudo:              r.IsSudo,
+		Interpreter:         r.Interpreter,
+		TimeoutSec:          r.TimeoutSec,
+		ExecuteConcurrently: r.ExecuteConcurrently,
+		AbortOnError:        r.AbortOnError,
+		UpdatesInstallation: r.UpdatesInstallation,
+		Username:            username,
+	}
+}
+
+func (r *Request) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("expected to have string, got %T", value)
+	}
+	err := json.Unmarshal(data, r)
+	if err != nil {
+		return fmt.Errorf("failed to decode approval request: %v", err)
+	}
+	return nil
+}
+
+func (r *Request) Value() (driver.Value, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode approval request: %v", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,40 @@
+package approvals
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+)
+
+const MinExpiration = time.Minute
+
+type Config struct {
+	Enabled bool `mapstructure:"enabled"`
+	// ApproverPermission is the permission a user needs to approve requests, if empty only administrators can approve.
+	ApproverPermission     string        `mapstructure:"approver_permission"`
+	Expiration             time.Duration `mapstructure:"expiration"`
+	NotificationDelivery   string        `mapstructure:"notification_delivery"`
+	NotificationRecipients []string      `mapstructure:"notification_recipients"`
+}
+
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.ApproverPermission != "" && !users.NewPermissions(users.AllPermissions...).Has(c.ApproverPermission) {
+		return fmt.Errorf("invalid approvals.approver_permission: unknown permission %q", c.ApproverPermission)
+	}
+
+	if c.Expiration < MinExpiration {
+		return fmt.Errorf("invalid approvals.expiration: must be at least %s, got %s", MinExpiration, c.Expiration)
+	}
+
+	if c.NotificationDelivery != "" && len(c.NotificationRecipients) == 0 {
+		return errors.New("approvals.notification_recipients must be set when approvals.notification_delivery is set")
+	}
+
+	return nil
+}
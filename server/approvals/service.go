@@ -0,0 +1,238 @@
+package approvals
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/server/api/message"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/random"
+)
+
+const notificationTimeout = time.Minute
+
+var (
+	supportedFilters = map[string]bool{
+		"id":           true,
+		"status":       true,
+		"requested_by": true,
+		"decided_by":   true,
+	}
+	supportedSorts = map[string]bool{
+		"requested_at": true,
+		"expires_at":   true,
+		"decided_at":   true,
+		"status":       true,
+	}
+	defaultSort = []query.SortOption{{Column: "requested_at", IsASC: false}}
+)
+
+// RunFunc starts the job of an approved request and returns the id of the started job
+type RunFunc func(ctx context.Context, approval *Approval) (string, error)
+
+// Service keeps the requests that wait for an approval.
+type Service struct {
+	provider   Provider
+	expiration time.Duration
+	msgSrv     message.Service
+	recipients []string
+	logger     *logger.Logger
+	now        func() time.Time
+}
+
+// NewService returns a new approvals service. msgSrv is optional, if nil - no notifications are sent.
+func NewService(provider Provider, expiration time.Duration, msgSrv message.Service, recipients []string, logger *logger.Logger) *Service {
+	return &Service{
+		provider:   provider,
+		expiration: expiration,
+		msgSrv:     msgSrv,
+		recipients: recipients,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Create stores a new pending request that targets the clients of the given client groups requiring approval.
+func (s *Service) Create(ctx context.Context, request *Request, clientGroupIDs []string, username string) (*Approval, error) {
+	id, err := random.UUID4()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC()
+	approval := &Approval{
+		ID:             id,
+		Status:         StatusPending,
+		Request:        request,
+		ClientGroupIDs: clientGroupIDs,
+		RequestedBy:    username,
+		RequestedAt:    now,
+		ExpiresAt:      now.Add(s.expiration),
+	}
+	if err := s.provider.Create(ctx, approval); err != nil {
+		return nil, err
+	}
+
+	s.notify(approval)
+
+	return approval, nil
+}
+
+// Get returns an approval, it returns nil if not found.
+func (s *Service) Get(ctx context.Context, id string) (*Approval, error) {
+	return s.provider.Get(ctx, id)
+}
+
+func (s *Service) List(ctx context.Context, options *query.ListOptions) (*api.SuccessPayload, error) {
+	err := query.ValidateListOptions(options, supportedSorts, supportedFilters, nil, &query.PaginationConfig{
+		DefaultLimit: 10,
+		MaxLimit:     100,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(options.Sorts) == 0 {
+		options.Sorts = defaultSort
+	}
+
+	entries, err := s.provider.List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.provider.Count(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.SuccessPayload{
+		Data: entries,
+		Meta: api.NewMeta(count),
+	}, nil
+}
+
+// Approve approves a pending request and starts its job using a given run func. An error starting the job is
+// stored on the approval instead of being returned.
+func (s *Service) Approve(ctx context.Context, id, username, comment string, run RunFunc) (*Approval, error) {
+	approval, err := s.decide(ctx, id, username, comment, StatusApproved)
+	if err != nil {
+		return nil, err
+	}
+
+	jobID, err := run(ctx, approval)
+	if err != nil {
+		s.logger.Errorf("Failed to start the job of approval %s: %v", approval.ID, err)
+		approval.Error = err.Error()
+	} else {
+		approval.JobID = &jobID
+	}
+	if err := s.provider.Update(ctx, approval); err != nil {
+		return nil, err
+	}
+
+	s.notify(approval)
+
+	return approval, nil
+}
+
+// Reject rejects a pending request, its job is never started.
+func (s *Service) Reject(ctx context.Context, id, username, comment string) (*Approval, error) {
+	approval, err := s.decide(ctx, id, username, comment, StatusRejected)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(approval)
+
+	return approval, nil
+}
+
+func (s *Service) decide(ctx context.Context, id, username, comment string, status Status) (*Approval, error) {
+	approval, err := s.provider.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if approval == nil {
+		return nil, errors2.APIError{
+			Message:    fmt.Sprintf("Approval with id %q not found.", id),
+			HTTPStatus: http.StatusNotFound,
+		}
+	}
+
+	if approval.RequestedBy == username {
+		return nil, errors2.APIError{
+			Message:    "An approval must be decided by a different user than the one who requested it.",
+			HTTPStatus: http.StatusForbidden,
+		}
+	}
+
+	now := s.now().UTC()
+	if approval.Status == StatusPending && !approval.ExpiresAt.After(now) {
+		if _, err := s.provider.ExpirePending(ctx, now); err != nil {
+			return nil, err
+		}
+		approval.Status = StatusExpired
+	}
+	if approval.Status != StatusPending {
+		return nil, errors2.APIError{
+			Message:    fmt.Sprintf("Approval with id %q is not pending, its status is %q.", id, approval.Status),
+			HTTPStatus: http.StatusConflict,
+		}
+	}
+
+	approval.Status = status
+	approval.DecidedBy = &username
+	approval.DecidedAt = &now
+	approval.Comment = comment
+	decided, err := s.provider.Decide(ctx, approval)
+	if err != nil {
+		return nil, err
+	}
+	if !decided {
+		return nil, errors2.APIError{
+			Message:    fmt.Sprintf("Approval with id %q has been decided already.", id),
+			HTTPStatus: http.StatusConflict,
+		}
+	}
+
+	return approval, nil
+}
+
+// ExpirePending marks pending approvals expired that haven't been decided in time.
+func (s *Service) ExpirePending(ctx context.Context) (int64, error) {
+	return s.provider.ExpirePending(ctx, s.now().UTC())
+}
+
+// notify sends a notification about a new or decided approval to all recipients in the background.
+func (s *Service) notify(approval *Approval) {
+	if s.msgSrv == nil {
+		return
+	}
+
+	data := message.Data{
+		Title:   fmt.Sprintf("[RPort approval %s] %s requested by %s", strings.ToUpper(string(approval.Status)), approval.jobType(), approval.RequestedBy),
+		Message: approval.Text(),
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+		defer cancel()
+
+		for _, recipient := range s.recipients {
+			data.SendTo = recipient
+			if err := s.msgSrv.Send(ctx, data); err != nil {
+				s.logger.Errorf("Failed to send approval %s notification to %s via %s: %v", approval.ID, recipient, s.msgSrv.DeliveryMethod(), err)
+			}
+		}
+	}()
+}
+
+func (s *Service) Close() error {
+	return s.provider.Close()
+}
@@ -0,0 +1,168 @@
+package approvals
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+var testLog = logger.NewLogger("approvals", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug)
+
+// testClock is the time seen by the service, approvals expire when it's moved forward
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// newService returns a service with approvals expiring after an hour
+func newService(t *testing.T, clock *testClock) *Service {
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { dbProvider.Close() })
+
+	service := NewService(dbProvider, time.Hour, nil, nil, testLog)
+	service.now = clock.Now
+
+	return service
+}
+
+func TestApprove(t *testing.T) {
+	ctx := context.Background()
+	clock := &testClock{now: time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)}
+	service := newService(t, clock)
+
+	created, err := service.Create(ctx, &Request{ClientIDs: []string{"client-1"}, Command: "reboot"}, []string{"production"}, "user1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, created.Status)
+	assert.Equal(t, clock.now.Add(time.Hour), created.ExpiresAt)
+
+	_, err = service.Approve(ctx, created.ID, "user1", "", nil)
+	assert.Equal(t, errors2.APIError{
+		Message:    "An approval must be decided by a different user than the one who requested it.",
+		HTTPStatus: http.StatusForbidden,
+	}, err)
+
+	var started *Approval
+	approved, err := service.Approve(ctx, created.ID, "user2", "ok", func(ctx context.Context, approval *Approval) (string, error) {
+		started = approval
+		return "job-1", nil
+	})
+	require.NoError(t, err)
+	require.NotNil(t, started)
+	assert.Equal(t, "reboot", started.Request.Command)
+	assert.Equal(t, StatusApproved, approved.Status)
+
+	got, err := service.Get(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusApproved, got.Status)
+	assert.Equal(t, "user2", *got.DecidedBy)
+	assert.Equal(t, "ok", got.Comment)
+	assert.Equal(t, "job-1", *got.JobID)
+	assert.Equal(t, []string{"client-1"}, got.Request.ClientIDs)
+	assert.EqualValues(t, []string{"production"}, got.ClientGroupIDs)
+
+	_, err = service.Reject(ctx, created.ID, "user2", "")
+	assert.Equal(t, errors2.APIError{
+		Message:    `Approval with id "` + created.ID + `" is not pending, its status is "approved".`,
+		HTTPStatus: http.StatusConflict,
+	}, err)
+}
+
+func TestApproveRunError(t *testing.T) {
+	ctx := context.Background()
+	service := newService(t, &testClock{now: time.Now()})
+
+	created, err := service.Create(ctx, &Request{GroupIDs: []string{"group-1"}, Script: "ZGF0ZQ==", IsScript: true}, []string{"production"}, "user1")
+	require.NoError(t, err)
+
+	approved, err := service.Approve(ctx, created.ID, "user2", "", func(ctx context.Context, approval *Approval) (string, error) {
+		return "", errors.New("no clients for execution")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, StatusApproved, approved.Status)
+	assert.Nil(t, approved.JobID)
+
+	got, err := service.Get(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "no clients for execution", got.Error)
+	assert.True(t, got.Request.IsScript)
+}
+
+func TestReject(t *testing.T) {
+	ctx := context.Background()
+	service := newService(t, &testClock{now: time.Now()})
+
+	created, err := service.Create(ctx, &Request{ClientIDs: []string{"client-1"}, Command: "reboot"}, []string{"production"}, "user1")
+	require.NoError(t, err)
+
+	rejected, err := service.Reject(ctx, created.ID, "user2", "not now")
+	require.NoError(t, err)
+	assert.Equal(t, StatusRejected, rejected.Status)
+
+	_, err = service.Approve(ctx, created.ID, "user3", "", func(ctx context.Context, approval *Approval) (string, error) {
+		t.Fatal("rejected job must not be started")
+		return "", nil
+	})
+	assert.Error(t, err)
+
+	_, err = service.Reject(ctx, "unknown", "user2", "")
+	assert.Equal(t, errors2.APIError{
+		Message:    `Approval with id "unknown" not found.`,
+		HTTPStatus: http.StatusNotFound,
+	}, err)
+}
+
+func TestExpirePending(t *testing.T) {
+	ctx := context.Background()
+	clock := &testClock{now: time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)}
+	service := newService(t, clock)
+
+	a1, err := service.Create(ctx, &Request{Command: "reboot"}, []string{"production"}, "user1")
+	require.NoError(t, err)
+	clock.Advance(30 * time.Minute)
+	a2, err := service.Create(ctx, &Request{Command: "reboot"}, []string{"production"}, "user1")
+	require.NoError(t, err)
+
+	clock.Advance(30 * time.Minute)
+	_, err = service.Approve(ctx, a1.ID, "user2", "", nil)
+	assert.Equal(t, errors2.APIError{
+		Message:    `Approval with id "` + a1.ID + `" is not pending, its status is "expired".`,
+		HTTPStatus: http.StatusConflict,
+	}, err)
+
+	expired, err := service.ExpirePending(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, expired, "already expired on approval")
+
+	clock.Advance(time.Hour)
+	expired, err = service.ExpirePending(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, expired)
+
+	result, err := service.List(ctx, &query.ListOptions{
+		Filters: []query.FilterOption{{Column: []string{"status"}, Values: []string{string(StatusExpired)}}},
+	})
+	require.NoError(t, err)
+	list := result.Data.([]*Approval)
+	require.Len(t, list, 2)
+	assert.Equal(t, a2.ID, list[0].ID)
+	assert.Equal(t, a1.ID, list[1].ID)
+}
@@ -0,0 +1,161 @@
+package approvals
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	approvalsmigration "github.com/cloudradar-monitoring/rport/db/migration/approvals"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+type Provider interface {
+	Create(ctx context.Context, approval *Approval) error
+	Get(ctx context.Context, id string) (*Approval, error)
+	List(ctx context.Context, options *query.ListOptions) ([]*Approval, error)
+	Count(ctx context.Context, options *query.ListOptions) (int, error)
+	Decide(ctx context.Context, approval *Approval) (bool, error)
+	Update(ctx context.Context, approval *Approval) error
+	ExpirePending(ctx context.Context, now time.Time) (int64, error)
+	Close() error
+}
+
+type SqliteProvider struct {
+	db        *sqlx.DB
+	converter *query.SQLConverter
+}
+
+func NewSqliteProvider(dbPath string, dbOptions database.Options) (*SqliteProvider, error) {
+	db, err := database.Open("approvals", dbPath, approvalsmigration.AssetNames(), approvalsmigration.Asset, dbOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create approvals DB instance: %v", err)
+	}
+
+	return &SqliteProvider{
+		db:        db,
+		converter: query.NewSQLConverter(db.DriverName()),
+	}, nil
+}
+
+func (p *SqliteProvider) Create(ctx context.Context, approval *Approval) error {
+	_, err := p.db.NamedExecContext(ctx,
+		`INSERT INTO approvals (
+			id,
+			status,
+			request,
+			client_group_ids,
+			requested_by,
+			requested_at,
+			expires_at
+		) VALUES (
+			:id,
+			:status,
+			:request,
+			:client_group_ids,
+			:requested_by,
+			:requested_at,
+			:expires_at
+		)`,
+		approval,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to create approval: %w", err)
+	}
+
+	return nil
+}
+
+func (p *SqliteProvider) Get(ctx context.Context, id string) (*Approval, error) {
+	approval := &Approval{}
+	err := p.db.GetContext(ctx, approval, p.db.Rebind("SELECT * FROM approvals WHERE id = ?"), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to get approval: %w", err)
+	}
+
+	return approval, nil
+}
+
+func (p *SqliteProvider) List(ctx context.Context, options *query.ListOptions) ([]*Approval, error) {
+	values := []*Approval{}
+
+	q, params := p.converter.ConvertListOptionsToQuery(options, "SELECT * FROM approvals")
+
+	err := p.db.SelectContext(ctx, &values, p.db.Rebind(q), params...)
+	return values, err
+}
+
+func (p *SqliteProvider) Count(ctx context.Context, options *query.ListOptions) (int, error) {
+	var result int
+
+	countOptions := *options
+	countOptions.Pagination = nil
+	countOptions.Sorts = nil
+	q, params := p.converter.ConvertListOptionsToQuery(&countOptions, "SELECT COUNT(*) FROM approvals")
+
+	err := p.db.GetContext(ctx, &result, p.db.Rebind(q), params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// Decide saves the decision about a pending approval, it returns false if the approval is not pending anymore.
+func (p *SqliteProvider) Decide(ctx context.Context, approval *Approval) (bool, error) {
+	res, err := p.db.NamedExecContext(ctx,
+		`UPDATE approvals SET
+			status = :status,
+			decided_by = :decided_by,
+			decided_at = :decided_at,
+			comment = :comment
+		WHERE id = :id AND status = 'pending'`,
+		approval,
+	)
+	if err != nil {
+		return false, fmt.Errorf("unable to update approval: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// Update saves the outcome of starting the job of an approval
+func (p *SqliteProvider) Update(ctx context.Context, approval *Approval) error {
+	_, err := p.db.NamedExecContext(ctx, "UPDATE approvals SET job_id = :job_id, error = :error WHERE id = :id", approval)
+	if err != nil {
+		return fmt.Errorf("unable to update approval: %w", err)
+	}
+
+	return nil
+}
+
+// ExpirePending marks all pending approvals expired before a given time as expired
+func (p *SqliteProvider) ExpirePending(ctx context.Context, now time.Time) (int64, error) {
+	res, err := p.db.ExecContext(
+		ctx,
+		p.db.Rebind("UPDATE approvals SET status = ? WHERE status = ? AND expires_at <= ?"),
+		StatusExpired,
+		StatusPending,
+		now,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("unable to expire approvals: %w", err)
+	}
+
+	return res.RowsAffected()
+}
+
+func (p *SqliteProvider) Close() error {
+	return p.db.Close()
+}
@@ -0,0 +1,103 @@
+package approvals
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+func TestSqliteProvider(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		p, err := NewSqliteProvider(":memory:", database.Options{PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		defer p.Close()
+
+		requestedAt := time.Date(2022, 10, 16, 12, 0, 0, 0, time.UTC)
+		command := &Approval{
+			ID:             "approval-1",
+			Status:         StatusPending,
+			Request:        &Request{ClientIDs: []string{"client-1"}, Command: "reboot"},
+			ClientGroupIDs: []string{"production"},
+			RequestedBy:    "user1",
+			RequestedAt:    requestedAt,
+			ExpiresAt:      requestedAt.Add(time.Hour),
+		}
+		script := &Approval{
+			ID:          "approval-2",
+			Status:      StatusPending,
+			Request:     &Request{ClientIDs: []string{"client-2"}, Script: "ZWNobyBoZWxsbw==", IsScript: true},
+			RequestedBy: "user2",
+			RequestedAt: requestedAt.Add(time.Minute),
+			ExpiresAt:   requestedAt.Add(2 * time.Hour),
+		}
+		require.NoError(t, p.Create(ctx, command))
+		require.NoError(t, p.Create(ctx, script))
+
+		got, err := p.Get(ctx, "approval-1")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, command.Request, got.Request)
+		assert.EqualValues(t, []string{"production"}, got.ClientGroupIDs)
+		assert.True(t, requestedAt.Equal(got.RequestedAt))
+		assert.Nil(t, got.DecidedBy)
+
+		decidedBy := "admin"
+		decidedAt := requestedAt.Add(10 * time.Minute)
+		command.Status = StatusApproved
+		command.DecidedBy = &decidedBy
+		command.DecidedAt = &decidedAt
+		command.Comment = "go ahead"
+		decided, err := p.Decide(ctx, command)
+		require.NoError(t, err)
+		assert.True(t, decided)
+		// a decided approval can't be decided again
+		command.Status = StatusRejected
+		decided, err = p.Decide(ctx, command)
+		require.NoError(t, err)
+		assert.False(t, decided)
+
+		jobID := "job-1"
+		command.JobID = &jobID
+		command.Error = "partially failed"
+		require.NoError(t, p.Update(ctx, command))
+
+		got, err = p.Get(ctx, "approval-1")
+		require.NoError(t, err)
+		assert.Equal(t, StatusApproved, got.Status)
+		assert.Equal(t, "go ahead", got.Comment)
+		require.NotNil(t, got.DecidedAt)
+		assert.True(t, decidedAt.Equal(*got.DecidedAt))
+		require.NotNil(t, got.JobID)
+		assert.Equal(t, "job-1", *got.JobID)
+		assert.Equal(t, "partially failed", got.Error)
+
+		expired, err := p.ExpirePending(ctx, requestedAt.Add(3*time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, expired)
+
+		options := &query.ListOptions{
+			Filters: []query.FilterOption{{Column: []string{"status"}, Values: []string{string(StatusExpired)}}},
+			Sorts:   []query.SortOption{{Column: "requested_at", IsASC: false}},
+		}
+		list, err := p.List(ctx, options)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "approval-2", list[0].ID)
+		assert.True(t, list[0].Request.IsScript)
+		count, err := p.Count(ctx, &query.ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		got, err = p.Get(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
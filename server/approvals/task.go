@@ -0,0 +1,30 @@
+package approvals
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+type ExpireTask struct {
+	log     *logger.Logger
+	service *Service
+}
+
+// NewExpireTask returns a task to expire pending approvals that haven't been decided in time
+func NewExpireTask(log *logger.Logger, service *Service) *ExpireTask {
+	return &ExpireTask{
+		log:     log,
+		service: service,
+	}
+}
+
+func (t *ExpireTask) Run(ctx context.Context) error {
+	expired, err := t.service.ExpirePending(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to expire approvals: %v", err)
+	}
+	t.log.Debugf("approvals.ExpireTask: %d approvals expired", expired)
+	return nil
+}
@@ -10,7 +10,7 @@ import (
 
 	"github.com/cloudradar-monitoring/rport/server/api/users"
 
-	"github.com/cloudradar-monitoring/rport/db/sqlite"
+	"github.com/cloudradar-monitoring/rport/db/database"
 
 	"github.com/cloudradar-monitoring/rport/server/api"
 	"github.com/cloudradar-monitoring/rport/server/clients"
@@ -70,19 +70,27 @@ func (e *NotAllowedError) Error() string {
 	return e.Msg
 }
 
-func New(l *logger.Logger, cg ClientGetter, dataDir string, cfg Config, dataSourceOptions sqlite.DataSourceOptions) (*AuditLog, error) {
+func New(l *logger.Logger, cg ClientGetter, dataDir string, cfg Config, dbOptions database.Options) (*AuditLog, error) {
 	a := &AuditLog{
 		logger:       l,
 		clientGetter: cg,
 		config:       cfg,
 	}
 
-	if cfg.Enable {
+	if cfg.Enable && dbOptions.PostgresDSN != "" {
+		// rotation renames the sqlite file, entries in PostgreSQL are kept in a single table
+		provider, err := newSQLiteProvider(dataDir, dbOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		a.provider = provider
+	} else if cfg.Enable {
 		rotation, err := newRotationProvider(
 			l,
 			cfg.rotationPeriod(),
 			dataDir,
-			dataSourceOptions,
+			dbOptions,
 		)
 		if err != nil {
 			return nil, err
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/migration/auditlog"
 	"github.com/cloudradar-monitoring/rport/db/sqlite"
 	"github.com/cloudradar-monitoring/rport/server/clients"
@@ -20,7 +21,7 @@ func TestNotEnabled(t *testing.T) {
 	req := httptest.NewRequest("GET", "/", nil)
 
 	mockProvider := &mockProvider{}
-	auditLog, err := New(nil, nil, "", Config{Enable: false}, DataSourceOptions)
+	auditLog, err := New(nil, nil, "", Config{Enable: false}, database.Options{SQLite: DataSourceOptions})
 	require.NoError(t, err)
 	auditLog.provider = mockProvider
 
@@ -1,33 +1,49 @@
 package auditlog
 
 const (
-	ActionCreate       = "create"
-	ActionDelete       = "delete"
-	ActionUpdate       = "update"
-	ActionExecuteStart = "execute.start"
-	ActionExecuteDone  = "execute.done"
-	ActionSuccess      = "success"
-	ActionFailed       = "failed"
+	ActionCreate        = "create"
+	ActionDelete        = "delete"
+	ActionUpdate        = "update"
+	ActionExecuteStart  = "execute.start"
+	ActionExecuteDone   = "execute.done"
+	ActionExecuteCancel = "execute.cancel"
+	ActionSuccess       = "success"
+	ActionFailed        = "failed"
+	ActionList          = "list"
+	ActionDownload      = "download"
+	ActionApprove       = "approve"
+	ActionReject        = "reject"
 )
 
 const (
-	ApplicationAuthUser        = "auth.user"
-	ApplicationAuthUserMe      = "auth.user.me"
-	ApplicationAuthUserMeToken = "auth.user.me.token" //nolint:gosec
-	ApplicationAuthUserTotP    = "auth.user.totp"
-	ApplicationAuthUserGroup   = "auth.user.group"
-	ApplicationAuthAPISession  = "auth.api.session"
-	ApplicationAuthAPISessions = "auth.api.sessions"
-	ApplicationClient          = "client"
-	ApplicationClientACL       = "client.acl"
-	ApplicationClientAuth      = "client.auth"
-	ApplicationClientGroup     = "client.group"
-	ApplicationClientTunnel    = "client.tunnel"
-	ApplicationClientCommand   = "client.command"
-	ApplicationClientScript    = "client.script"
-	ApplicationLibraryCommand  = "library.command"
-	ApplicationLibraryScript   = "library.script"
-	ApplicationVault           = "vault"
-	ApplicationSchedule        = "schedule"
-	ApplicationUploads         = "uploads"
+	ApplicationAuthUser          = "auth.user"
+	ApplicationAuthUserMe        = "auth.user.me"
+	ApplicationAuthUserMeToken   = "auth.user.me.token" //nolint:gosec
+	ApplicationAuthUserMeAPIKey  = "auth.user.me.apikey"
+	ApplicationAuthUserTotP      = "auth.user.totp"
+	ApplicationAuthUserGroup     = "auth.user.group"
+	ApplicationAuthAPISession    = "auth.api.session"
+	ApplicationAuthAPISessions   = "auth.api.sessions"
+	ApplicationClient            = "client"
+	ApplicationClientACL         = "client.acl"
+	ApplicationClientAuth        = "client.auth"
+	ApplicationClientGroup       = "client.group"
+	ApplicationClientTunnel      = "client.tunnel"
+	ApplicationClientTunnelShare = "client.tunnel.share"
+	ApplicationClientCommand     = "client.command"
+	ApplicationClientScript      = "client.script"
+	ApplicationClientTerminal    = "client.terminal"
+	ApplicationClientFiles       = "client.files"
+	ApplicationClientUpdates     = "client.updates"
+	ApplicationClientSelfUpdate  = "client.self-update"
+	ApplicationConfigProfile     = "client.config-profile"
+	ApplicationLibraryCommand    = "library.command"
+	ApplicationLibraryScript     = "library.script"
+	ApplicationVault             = "vault"
+	ApplicationSchedule          = "schedule"
+	ApplicationUploads           = "uploads"
+	ApplicationAlertRule         = "alert.rule"
+	ApplicationRecording         = "recording"
+	ApplicationApproval          = "approval"
+	ApplicationWebhook           = "webhook"
 )
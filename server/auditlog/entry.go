@@ -46,6 +46,16 @@ func (e *Entry) WithHTTPRequest(req *http.Request) *Entry {
 	return e
 }
 
+// WithRemoteIP sets the remote IP for entries of requests that are not API requests
+func (e *Entry) WithRemoteIP(ip string) *Entry {
+	if e == nil {
+		return e
+	}
+
+	e.RemoteIP = ip
+	return e
+}
+
 func (e *Entry) WithRequest(request interface{}) *Entry {
 	if e == nil {
 		return e
@@ -8,8 +8,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/cloudradar-monitoring/rport/db/sqlite"
-
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/share/logger"
 	"github.com/cloudradar-monitoring/rport/share/query"
 )
@@ -20,28 +19,29 @@ const (
 )
 
 type RotationProvider struct {
-	logger            *logger.Logger
-	period            time.Duration
-	ticker            *time.Ticker
-	dataDir           string
-	dataSourceOptions sqlite.DataSourceOptions
+	logger    *logger.Logger
+	period    time.Duration
+	ticker    *time.Ticker
+	dataDir   string
+	dbOptions database.Options
 
 	mtx    sync.RWMutex
 	sqlite *SQLiteProvider
 }
 
-func newRotationProvider(l *logger.Logger, period time.Duration, dataDir string, dataSourceOptions sqlite.DataSourceOptions) (*RotationProvider, error) {
-	sqlite, err := newSQLiteProvider(dataDir, dataSourceOptions)
+func newRotationProvider(l *logger.Logger, period time.Duration, dataDir string, dbOptions database.Options) (*RotationProvider, error) {
+	sqlite, err := newSQLiteProvider(dataDir, dbOptions)
 	if err != nil {
 		return nil, err
 	}
 
 	r := &RotationProvider{
-		logger:  l,
-		period:  period,
-		dataDir: dataDir,
-		sqlite:  sqlite,
-		ticker:  time.NewTicker(period),
+		logger:    l,
+		period:    period,
+		dataDir:   dataDir,
+		dbOptions: dbOptions,
+		sqlite:    sqlite,
+		ticker:    time.NewTicker(period),
 	}
 	err = r.rotateIfNeeded()
 	if err != nil {
@@ -78,7 +78,7 @@ func (r *RotationProvider) rotate() error {
 		return err
 	}
 
-	r.sqlite, err = newSQLiteProvider(r.dataDir, r.dataSourceOptions)
+	r.sqlite, err = newSQLiteProvider(r.dataDir, r.dbOptions)
 	if err != nil {
 		return err
 	}
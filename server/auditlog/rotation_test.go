@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/migration/auditlog"
 	"github.com/cloudradar-monitoring/rport/db/sqlite"
 	"github.com/cloudradar-monitoring/rport/share/query"
@@ -22,7 +23,7 @@ func TestRotation(t *testing.T) {
 	period := 300 * time.Millisecond
 
 	// Prepare sqlite with 1 entry
-	sqlite, err := newSQLiteProvider(dir, dso)
+	sqlite, err := newSQLiteProvider(dir, database.Options{SQLite: dso})
 	require.NoError(t, err)
 	err = sqlite.Save(&Entry{Timestamp: time.Now(), Username: "test1"})
 	require.NoError(t, err)
@@ -30,7 +31,7 @@ func TestRotation(t *testing.T) {
 	require.NoError(t, err)
 
 	// No rotation on init if entry is not older than period
-	rotation, err := newRotationProvider(nil, period, dir, dso)
+	rotation, err := newRotationProvider(nil, period, dir, database.Options{SQLite: dso})
 	require.NoError(t, err)
 	entries, err := rotation.List(ctx, &query.ListOptions{})
 	require.NoError(t, err)
@@ -42,7 +43,7 @@ func TestRotation(t *testing.T) {
 	time.Sleep(period)
 
 	// Should rotate on init
-	rotation, err = newRotationProvider(nil, period, dir, dso)
+	rotation, err = newRotationProvider(nil, period, dir, database.Options{SQLite: dso})
 	require.NoError(t, err)
 	entries, err = rotation.List(ctx, &query.ListOptions{})
 	require.NoError(t, err)
@@ -7,8 +7,8 @@ import (
 
 	"github.com/jmoiron/sqlx"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/migration/auditlog"
-	"github.com/cloudradar-monitoring/rport/db/sqlite"
 	"github.com/cloudradar-monitoring/rport/share/query"
 )
 
@@ -17,12 +17,13 @@ type SQLiteProvider struct {
 	converter *query.SQLConverter
 }
 
-func newSQLiteProvider(dataDir string, dataSourceOptions sqlite.DataSourceOptions) (*SQLiteProvider, error) {
-	db, err := sqlite.New(
+func newSQLiteProvider(dataDir string, dbOptions database.Options) (*SQLiteProvider, error) {
+	db, err := database.Open(
+		"auditlog",
 		path.Join(dataDir, sqliteFilename),
 		auditlog.AssetNames(),
 		auditlog.Asset,
-		dataSourceOptions,
+		dbOptions,
 	)
 	if err != nil {
 		return nil, err
@@ -67,11 +68,11 @@ func (p *SQLiteProvider) Save(e *Entry) error {
 func (p *SQLiteProvider) List(ctx context.Context, options *query.ListOptions) ([]*Entry, error) {
 	values := []*Entry{}
 
-	q := "SELECT * FROM `auditlog`"
+	q := "SELECT * FROM auditlog"
 
 	q, params := p.converter.ConvertListOptionsToQuery(options, q)
 
-	err := p.db.SelectContext(ctx, &values, q, params...)
+	err := p.db.SelectContext(ctx, &values, p.db.Rebind(q), params...)
 	if err != nil {
 		return values, err
 	}
@@ -82,12 +83,13 @@ func (p *SQLiteProvider) List(ctx context.Context, options *query.ListOptions) (
 func (p *SQLiteProvider) Count(ctx context.Context, options *query.ListOptions) (int, error) {
 	var result int
 
-	q := "SELECT COUNT(*) FROM `auditlog`"
+	q := "SELECT COUNT(*) FROM auditlog"
 	countOptions := *options
 	countOptions.Pagination = nil
+	countOptions.Sorts = nil
 	q, params := p.converter.ConvertListOptionsToQuery(&countOptions, q)
 
-	err := p.db.GetContext(ctx, &result, q, params...)
+	err := p.db.GetContext(ctx, &result, p.db.Rebind(q), params...)
 	if err != nil {
 		return 0, err
 	}
@@ -98,7 +100,7 @@ func (p *SQLiteProvider) Count(ctx context.Context, options *query.ListOptions)
 func (p *SQLiteProvider) OldestTimestamp(ctx context.Context) (time.Time, error) {
 	var ts time.Time
 	q := "SELECT timestamp FROM auditlog ORDER BY timestamp ASC LIMIT 1"
-	err := p.db.GetContext(ctx, &ts, q)
+	err := p.db.GetContext(ctx, &ts, p.db.Rebind(q))
 	if err != nil {
 		return ts, err
 	}
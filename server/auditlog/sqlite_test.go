@@ -1,13 +1,17 @@
 package auditlog
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/migration/auditlog"
 	"github.com/cloudradar-monitoring/rport/db/sqlite"
+	"github.com/cloudradar-monitoring/rport/share/query"
 	"github.com/cloudradar-monitoring/rport/share/test"
 )
 
@@ -51,3 +55,37 @@ func TestSqliteSave(t *testing.T) {
 	q := "SELECT * FROM auditlog"
 	test.AssertRowsEqual(t, db, expectedRows, q, []interface{}{})
 }
+
+func TestProviderDatabases(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		dbProv, err := newSQLiteProvider(t.TempDir(), database.Options{SQLite: DataSourceOptions, PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		defer dbProv.Close()
+
+		oldest := time.Date(2021, 10, 19, 13, 57, 58, 0, time.UTC)
+		require.NoError(t, dbProv.Save(&Entry{Timestamp: oldest.Add(time.Hour), Username: "admin", Application: ApplicationLibraryCommand, Action: ActionDelete, ID: "2"}))
+		require.NoError(t, dbProv.Save(&Entry{Timestamp: oldest, Username: "admin", Application: ApplicationLibraryCommand, Action: ActionCreate, ID: "1"}))
+		require.NoError(t, dbProv.Save(&Entry{Timestamp: oldest.Add(time.Minute), Username: "other", Application: ApplicationLibraryCommand, Action: ActionCreate, ID: "3"}))
+
+		options := &query.ListOptions{
+			Filters: []query.FilterOption{{Column: []string{"username"}, Values: []string{"admin"}}},
+			Sorts:   []query.SortOption{{Column: "timestamp", IsASC: true}},
+		}
+		entries, err := dbProv.List(ctx, options)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "1", entries[0].ID)
+		assert.Equal(t, ActionCreate, entries[0].Action)
+		assert.True(t, oldest.Equal(entries[0].Timestamp))
+		assert.Equal(t, "2", entries[1].ID)
+
+		count, err := dbProv.Count(ctx, options)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		ts, err := dbProv.OldestTimestamp(ctx)
+		require.NoError(t, err)
+		assert.True(t, oldest.Equal(ts))
+	})
+}
@@ -16,6 +16,8 @@ type ClientGroup struct {
 	Description       string            `json:"description" db:"description"`
 	Params            *ClientParams     `json:"params" db:"params"`
 	AllowedUserGroups types.StringSlice `json:"allowed_user_groups" db:"allowed_user_groups"`
+	// RequireApproval is set if commands and scripts on the clients of the group must be approved by a second user.
+	RequireApproval bool `json:"require_approval" db:"require_approval"`
 	// ClientIDs shows what clients belong to a given group. Note: it's populated separately.
 	ClientIDs []string `json:"client_ids" db:"-"`
 }
@@ -29,7 +29,7 @@ func (p *SqliteProvider) GetAll(ctx context.Context) ([]*ClientGroup, error) {
 	err := p.db.SelectContext(
 		ctx,
 		&res,
-		"SELECT * FROM client_groups ORDER BY id COLLATE NOCASE",
+		"SELECT * FROM client_groups ORDER BY LOWER(id)",
 	)
 	if err != nil {
 		return nil, err
@@ -39,7 +39,7 @@ func (p *SqliteProvider) GetAll(ctx context.Context) ([]*ClientGroup, error) {
 
 func (p *SqliteProvider) Get(ctx context.Context, id string) (*ClientGroup, error) {
 	res := &ClientGroup{}
-	err := p.db.GetContext(ctx, res, "SELECT * FROM client_groups WHERE id = ?", id)
+	err := p.db.GetContext(ctx, res, p.db.Rebind("SELECT * FROM client_groups WHERE id = ?"), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -52,7 +52,7 @@ func (p *SqliteProvider) Get(ctx context.Context, id string) (*ClientGroup, erro
 func (p *SqliteProvider) Create(ctx context.Context, group *ClientGroup) error {
 	_, err := p.db.NamedExecContext(
 		ctx,
-		"INSERT INTO client_groups (id, description, params, allowed_user_groups) VALUES (:id, :description, :params, :allowed_user_groups)",
+		"INSERT INTO client_groups (id, description, params, allowed_user_groups, require_approval) VALUES (:id, :description, :params, :allowed_user_groups, :require_approval)",
 		group,
 	)
 	return err
@@ -61,14 +61,16 @@ func (p *SqliteProvider) Create(ctx context.Context, group *ClientGroup) error {
 func (p *SqliteProvider) Update(ctx context.Context, group *ClientGroup) error {
 	_, err := p.db.NamedExecContext(
 		ctx,
-		"INSERT OR REPLACE INTO client_groups (id, description, params, allowed_user_groups) VALUES (:id, :description, :params, :allowed_user_groups)",
+		"INSERT INTO client_groups (id, description, params, allowed_user_groups, require_approval) VALUES (:id, :description, :params, :allowed_user_groups, :require_approval)"+
+			" ON CONFLICT(id) DO UPDATE SET description = excluded.description, params = excluded.params, allowed_user_groups = excluded.allowed_user_groups,"+
+			" require_approval = excluded.require_approval",
 		group,
 	)
 	return err
 }
 
 func (p *SqliteProvider) Delete(ctx context.Context, id string) error {
-	_, err := p.db.ExecContext(ctx, "DELETE FROM client_groups WHERE id = ?", id)
+	_, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM client_groups WHERE id = ?"), id)
 	return err
 }
 
@@ -0,0 +1,60 @@
+package cgroups
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/db/migration/client_groups"
+	"github.com/cloudradar-monitoring/rport/share/test"
+	"github.com/cloudradar-monitoring/rport/share/types"
+)
+
+func TestSqliteProvider(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		db, err := database.Open("client_groups", ":memory:", client_groups.AssetNames(), client_groups.Asset, database.Options{PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		p, err := NewSqliteProvider(db)
+		require.NoError(t, err)
+		defer p.Close()
+
+		group1 := &ClientGroup{
+			ID:                "group-1",
+			Description:       "Linux servers",
+			Params:            &ClientParams{OS: &ParamValues{"linux*"}},
+			AllowedUserGroups: types.StringSlice{"admins"},
+		}
+		group2 := &ClientGroup{ID: "Group-2", Params: &ClientParams{}, AllowedUserGroups: types.StringSlice{}}
+		require.NoError(t, p.Create(ctx, group1))
+		require.NoError(t, p.Create(ctx, group2))
+		assert.Error(t, p.Create(ctx, group1))
+
+		group1.Description = "Debian servers"
+		group1.Params = &ClientParams{OS: &ParamValues{"debian*"}}
+		group1.AllowedUserGroups = types.StringSlice{"admins", "operators"}
+		group1.RequireApproval = true
+		require.NoError(t, p.Update(ctx, group1))
+
+		got, err := p.Get(ctx, "group-1")
+		require.NoError(t, err)
+		assert.Equal(t, group1, got)
+		got, err = p.Get(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		all, err := p.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		assert.Equal(t, "group-1", all[0].ID)
+		assert.Equal(t, "Group-2", all[1].ID)
+
+		require.NoError(t, p.Delete(ctx, "group-1"))
+		all, err = p.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []*ClientGroup{group2}, all)
+	})
+}
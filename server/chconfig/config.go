@@ -17,6 +17,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/db/postgres"
 	"github.com/cloudradar-monitoring/rport/db/sqlite"
 	rportplus "github.com/cloudradar-monitoring/rport/plus"
 
@@ -28,10 +30,18 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/cloudradar-monitoring/rport/server/api/message"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/approvals"
 	"github.com/cloudradar-monitoring/rport/server/auditlog"
 	"github.com/cloudradar-monitoring/rport/server/bearer"
 	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	"github.com/cloudradar-monitoring/rport/server/cluster"
 	"github.com/cloudradar-monitoring/rport/server/ports"
+	"github.com/cloudradar-monitoring/rport/server/recordings"
+	"github.com/cloudradar-monitoring/rport/server/selfupdate"
+	"github.com/cloudradar-monitoring/rport/server/tunnelhistory"
+	"github.com/cloudradar-monitoring/rport/server/tunnelshares"
+	"github.com/cloudradar-monitoring/rport/server/webhooks"
 	chshare "github.com/cloudradar-monitoring/rport/share"
 	"github.com/cloudradar-monitoring/rport/share/email"
 	"github.com/cloudradar-monitoring/rport/share/logger"
@@ -59,6 +69,7 @@ type APIConfig struct {
 	MaxTokenLifeTimeHours  int     `mapstructure:"max_token_lifetime"`
 	PasswordMinLength      int     `mapstructure:"password_min_length"`
 	PasswordZxcvbnMinscore int     `mapstructure:"password_zxcvbn_minscore"`
+	MetricsToken           string  `mapstructure:"metrics_token"`
 
 	TwoFATokenDelivery       string                 `mapstructure:"two_fa_token_delivery"`
 	TwoFATokenTTLSeconds     int                    `mapstructure:"two_fa_token_ttl_seconds"`
@@ -99,6 +110,8 @@ const (
 	MinKeepDisconnectedClients = time.Second
 	MaxKeepDisconnectedClients = 7 * 24 * time.Hour
 	DefaultVaultDBName         = "vault.sqlite.db"
+	// DefaultClientBinariesDirName is the folder in the data dir hosting the client binaries for self-update
+	DefaultClientBinariesDirName = "client-binaries"
 
 	socketPrefix = "socket:"
 )
@@ -145,6 +158,8 @@ type ServerConfig struct {
 	EnableWsTestEndpoints            bool                           `mapstructure:"enable_ws_test_endpoints"`
 	TunnelProxyConfig                clienttunnel.TunnelProxyConfig `mapstructure:",squash"`
 	JobsMaxResults                   int                            `mapstructure:"jobs_max_results"`
+	ClientTunnelRateLimitUp          int64                          `mapstructure:"client_tunnel_rate_limit_up"`
+	ClientTunnelRateLimitDown        int64                          `mapstructure:"client_tunnel_rate_limit_down"`
 
 	allowedPorts mapset.Set
 	AuthID       string
@@ -157,6 +172,7 @@ type DatabaseConfig struct {
 	User     string `mapstructure:"db_user"`
 	Password string `mapstructure:"db_password"`
 	Name     string `mapstructure:"db_name"`
+	SSLMode  string `mapstructure:"db_ssl_mode"`
 
 	Driver string
 	Dsn    string
@@ -252,20 +268,44 @@ type MonitoringConfig struct {
 	DataStorageDays int64 `mapstructure:"data_storage_days"`
 }
 
+type InventoryConfig struct {
+	// HistoryStorageDays is the number of days the changes of the client inventories are kept
+	HistoryStorageDays int64 `mapstructure:"history_storage_days"`
+}
+
+type AlertsConfig struct {
+	Enabled                bool          `mapstructure:"enabled"`
+	CheckInterval          time.Duration `mapstructure:"check_interval"`
+	NotificationDelivery   string        `mapstructure:"notification_delivery"`
+	NotificationRecipients []string      `mapstructure:"notification_recipients"`
+	DataStorageDays        int64         `mapstructure:"data_storage_days"`
+}
+
 type Config struct {
-	Server     ServerConfig     `mapstructure:"server"`
-	Logging    LogConfig        `mapstructure:"logging"`
-	API        APIConfig        `mapstructure:"api"`
-	Database   DatabaseConfig   `mapstructure:"database"`
-	Pushover   PushoverConfig   `mapstructure:"pushover"`
-	SMTP       SMTPConfig       `mapstructure:"smtp"`
-	Monitoring MonitoringConfig `mapstructure:"monitoring"`
+	Server        ServerConfig         `mapstructure:"server"`
+	Logging       LogConfig            `mapstructure:"logging"`
+	API           APIConfig            `mapstructure:"api"`
+	Database      DatabaseConfig       `mapstructure:"database"`
+	Pushover      PushoverConfig       `mapstructure:"pushover"`
+	SMTP          SMTPConfig           `mapstructure:"smtp"`
+	Monitoring    MonitoringConfig     `mapstructure:"monitoring"`
+	Inventory     InventoryConfig      `mapstructure:"inventory"`
+	Alerts        AlertsConfig         `mapstructure:"alerts"`
+	Recordings    recordings.Config    `mapstructure:"recordings"`
+	Approvals     approvals.Config     `mapstructure:"approvals"`
+	Webhooks      webhooks.Config      `mapstructure:"webhooks"`
+	TunnelHistory tunnelhistory.Config `mapstructure:"tunnel_history"`
+	TunnelShares  tunnelshares.Config  `mapstructure:"tunnel_shares"`
+	LDAP          users.LDAPConfig     `mapstructure:"ldap"`
+	Cluster       cluster.Config       `mapstructure:"cluster"`
+	SelfUpdate    selfupdate.Config    `mapstructure:"client-self-update"`
 
 	PlusConfig rportplus.PlusConfig `mapstructure:",squash"`
 }
 
 var (
 	CheckClientsConnectionIntervalMinimum = time.Minute * 2
+	AlertsCheckIntervalMinimum            = 10 * time.Second
 )
 
 func (c *Config) GetVaultDBPath() string {
@@ -280,6 +320,24 @@ func (s *ServerConfig) GetSQLiteDataSourceOptions() sqlite.DataSourceOptions {
 	return sqlite.DataSourceOptions{WALEnabled: s.SqliteWAL}
 }
 
+// GetDatabaseOptions returns the options of the server stores, they are kept in PostgreSQL if it's the configured database
+func (c *Config) GetDatabaseOptions() database.Options {
+	opts := database.Options{
+		SQLite: c.Server.GetSQLiteDataSourceOptions(),
+	}
+	if c.Database.Driver == postgres.DriverName {
+		opts.PostgresDSN = c.Database.Dsn
+	}
+	return opts
+}
+
+// GetVaultDBOptions returns the options of the vault store, its sqlite file is opened without WAL
+func (c *Config) GetVaultDBOptions() database.Options {
+	opts := c.GetDatabaseOptions()
+	opts.SQLite = sqlite.DataSourceOptions{WALEnabled: false}
+	return opts
+}
+
 func (c *Config) InitRequestLogOptions() *requestlog.Options {
 	o := requestlog.DefaultOptions
 	o.Writer = c.Logging.LogOutput.File
@@ -320,6 +378,10 @@ func (c *Config) ParseAndValidate(mLog *logger.MemLogger) error {
 		return errors.New("'data directory path' cannot be empty")
 	}
 
+	if c.Server.ClientTunnelRateLimitUp < 0 || c.Server.ClientTunnelRateLimitDown < 0 {
+		return errors.New("'client_tunnel_rate_limit_up' and 'client_tunnel_rate_limit_down' cannot be negative")
+	}
+
 	if c.Server.PurgeDisconnectedClients && c.Server.KeepDisconnectedClients != 0 && (c.Server.KeepDisconnectedClients.Nanoseconds() < MinKeepDisconnectedClients.Nanoseconds() ||
 		c.Server.KeepDisconnectedClients.Nanoseconds() > MaxKeepDisconnectedClients.Nanoseconds()) {
 		return fmt.Errorf("expected 'Keep Lost Clients' can be in range [%v, %v], actual: %v", MinKeepDisconnectedClients, MaxKeepDisconnectedClients, c.Server.KeepDisconnectedClients)
@@ -345,9 +407,133 @@ func (c *Config) ParseAndValidate(mLog *logger.MemLogger) error {
 		mLog.Errorf("'check_clients_status_interval' too fast. Using the minimum possible of %s", CheckClientsConnectionIntervalMinimum)
 	}
 
+	if err := c.parseAndValidateAlerts(); err != nil {
+		return err
+	}
+
+	if err := c.Recordings.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.parseAndValidateApprovals(); err != nil {
+		return err
+	}
+
+	if err := c.Webhooks.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.TunnelHistory.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.parseAndValidateTunnelShares(); err != nil {
+		return err
+	}
+
+	if err := c.parseAndValidateCluster(); err != nil {
+		return err
+	}
+
+	if err := c.parseAndValidateSelfUpdate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Config) parseAndValidateAlerts() error {
+	if !c.Alerts.Enabled {
+		return nil
+	}
+
+	if c.Alerts.CheckInterval < AlertsCheckIntervalMinimum {
+		return fmt.Errorf("invalid alerts.check_interval: must be at least %s, got %s", AlertsCheckIntervalMinimum, c.Alerts.CheckInterval)
+	}
+
+	if c.Alerts.DataStorageDays < 1 {
+		return fmt.Errorf("invalid alerts.data_storage_days: must be at least 1, got %d", c.Alerts.DataStorageDays)
+	}
+
+	if c.Alerts.NotificationDelivery != "" && len(c.Alerts.NotificationRecipients) == 0 {
+		return errors.New("alerts.notification_recipients must be set when alerts.notification_delivery is set")
+	}
+
+	return c.validateNotificationDelivery("alerts", c.Alerts.NotificationDelivery)
+}
+
+func (c *Config) parseAndValidateApprovals() error {
+	if err := c.Approvals.Validate(); err != nil {
+		return err
+	}
+
+	if !c.Approvals.Enabled {
+		return nil
+	}
+
+	return c.validateNotificationDelivery("approvals", c.Approvals.NotificationDelivery)
+}
+
+func (c *Config) parseAndValidateTunnelShares() error {
+	if err := c.TunnelShares.Validate(); err != nil {
+		return err
+	}
+
+	// share links are served by the tunnel proxy
+	if c.TunnelShares.Enabled && !c.Server.TunnelProxyConfig.Enabled {
+		return errors.New("tunnel_shares require the tunnel proxy, set 'tunnel_proxy_cert_file' and 'tunnel_proxy_key_file' in the [server] section")
+	}
+
 	return nil
 }
 
+func (c *Config) parseAndValidateSelfUpdate() error {
+	if c.SelfUpdate.Enabled && c.SelfUpdate.BinariesDir == "" {
+		c.SelfUpdate.BinariesDir = filepath.Join(c.Server.DataDir, DefaultClientBinariesDirName)
+	}
+
+	return c.SelfUpdate.Validate()
+}
+
+func (c *Config) parseAndValidateCluster() error {
+	if err := c.Cluster.Validate(); err != nil {
+		return err
+	}
+
+	if !c.Cluster.Enabled {
+		return nil
+	}
+
+	// all nodes share the state, sqlite files in the data dir of each node can't be used
+	if c.Database.Driver != postgres.DriverName {
+		return errors.New("clustering requires 'db_type' = 'postgres' in the [database] section")
+	}
+
+	if c.API.Address == "" {
+		return errors.New("clustering requires the API to be enabled, nodes forward requests to each other via the API")
+	}
+
+	return nil
+}
+
+// validateNotificationDelivery validates the notification delivery method of a given config section, it's optional.
+func (c *Config) validateNotificationDelivery(section, delivery string) error {
+	switch delivery {
+	case "":
+		return nil
+	case "pushover":
+		return c.Pushover.Validate()
+	case "smtp":
+		return c.SMTP.Validate()
+	default:
+		if _, err := exec.LookPath(delivery); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown %s notification delivery method: %s", section, delivery)
+}
+
 func (c *Config) parseAndValidateClientAuth() error {
 	if c.Server.Auth == "" && c.Server.AuthFile == "" && c.Server.AuthTable == "" {
 		return errors.New("client authentication must be enabled: set either 'auth', 'auth_file' or 'auth_table'")
@@ -389,6 +575,10 @@ func (c *Config) parseAndValidateAPI() error {
 			return err
 		}
 		if c.API.JWTSecret == "" {
+			// tokens issued by one node of a cluster must be accepted by all nodes
+			if c.Cluster.Enabled {
+				return errors.New("'jwt_secret' must be set when clustering is enabled, it must be the same on all nodes")
+			}
 			c.API.JWTSecret, err = generateJWTSecret()
 			if err != nil {
 				return err
@@ -455,8 +645,12 @@ func (c *Config) parseAndValidate2FA() error {
 }
 
 func (c *Config) parseAndValidateAPIAuth() error {
+	if c.LDAP.Enabled {
+		return c.parseAndValidateLDAPAuth()
+	}
+
 	if c.API.AuthFile == "" && c.API.Auth == "" && c.API.AuthUserTable == "" {
-		return errors.New("authentication must be enabled: set either 'auth', 'auth_file' or 'auth_user_table'")
+		return errors.New("authentication must be enabled: set either 'auth', 'auth_file' or 'auth_user_table' or enable 'ldap'")
 	}
 
 	if c.API.AuthFile != "" && c.API.Auth != "" {
@@ -491,6 +685,23 @@ func (c *Config) parseAndValidateAPIAuth() error {
 	return nil
 }
 
+func (c *Config) parseAndValidateLDAPAuth() error {
+	if c.API.Auth != "" || c.API.AuthFile != "" || c.API.AuthUserTable != "" {
+		return errors.New("'ldap' is enabled: 'auth', 'auth_file' and 'auth_user_table' must not be set")
+	}
+
+	// the 2FA settings of the users can't be stored in the directory
+	if c.API.IsTwoFAOn() || c.API.TotPEnabled {
+		return errors.New("2FA is not available if you use ldap")
+	}
+
+	if c.API.AuthHeader != "" && c.API.UserHeader == "" {
+		return errors.New("'user_header' must be set when 'auth_header' is set")
+	}
+
+	return c.LDAP.Validate()
+}
+
 func (c *Config) parseAndValidateAPIHTTPSOptions() error {
 	if c.API.CertFile == "" && c.API.KeyFile == "" {
 		return nil
@@ -597,14 +808,45 @@ func (d *DatabaseConfig) ParseAndValidate() error {
 	case "sqlite":
 		d.Driver = "sqlite3"
 		d.Dsn = d.Name
+	case "postgres":
+		d.Driver = postgres.DriverName
+		dsn := url.URL{
+			Scheme: "postgres",
+			Path:   "/" + d.Name,
+		}
+		if d.User != "" {
+			if d.Password != "" {
+				dsn.User = url.UserPassword(d.User, d.Password)
+			} else {
+				dsn.User = url.User(d.User)
+			}
+		}
+		params := url.Values{}
+		if strings.HasPrefix(d.Host, socketPrefix) {
+			params.Set("host", strings.TrimPrefix(d.Host, socketPrefix))
+		} else {
+			dsn.Host = d.Host
+		}
+		if d.SSLMode != "" {
+			params.Set("sslmode", d.SSLMode)
+		}
+		dsn.RawQuery = params.Encode()
+		d.Dsn = dsn.String()
 	default:
-		return fmt.Errorf("invalid 'db_type', expected 'mysql' or 'sqlite', got %q", d.Type)
+		return fmt.Errorf("invalid 'db_type', expected 'mysql', 'sqlite' or 'postgres', got %q", d.Type)
 	}
 
 	return nil
 }
 
 func (d *DatabaseConfig) DsnForLogs() string {
+	if d.Driver == postgres.DriverName {
+		dsn, err := url.Parse(d.Dsn)
+		if err != nil {
+			return ""
+		}
+		return dsn.Redacted()
+	}
 	if d.Password != "" {
 		// hide the password
 		return strings.Replace(d.Dsn, ":"+d.Password, ":***", 1)
@@ -26,6 +26,7 @@ import (
 	"github.com/cloudradar-monitoring/rport/server/chconfig"
 	"github.com/cloudradar-monitoring/rport/server/clients"
 	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	"github.com/cloudradar-monitoring/rport/server/webhooks"
 	chshare "github.com/cloudradar-monitoring/rport/share"
 	"github.com/cloudradar-monitoring/rport/share/comm"
 	"github.com/cloudradar-monitoring/rport/share/logger"
@@ -266,9 +267,16 @@ func (cl *ClientListener) handleWebsocket(w http.ResponseWriter, req *http.Reque
 		return
 	}
 	clog.Debugf("client service started for %s", client.Name)
+	cl.claimClusterClient(ctx, client, clog)
+	cl.webhooksService.Dispatch(webhooks.EventClientConnected, clientEvent(client))
 
 	cl.replyConnectionSuccess(r, connRequest.Remotes)
 	cl.sendCapabilities(sshConn)
+	go func() {
+		if err := cl.Server.pushConfigProfiles(context.Background(), client); err != nil {
+			clog.Errorf("Failed to push config profiles: %v", err)
+		}
+	}()
 
 	clientBanner := client.Banner()
 	clog.Debugf("open %s", clientBanner)
@@ -279,10 +287,15 @@ func (cl *ClientListener) handleWebsocket(w http.ResponseWriter, req *http.Reque
 	}
 	clog.Debugf("close %s", clientBanner)
 
+	if !cl.releaseClusterClient(client, clog) {
+		return
+	}
+
 	err = cl.clientService.Terminate(client)
 	if err != nil {
 		cl.Errorf("could not terminate client: %s", err)
 	}
+	cl.webhooksService.Dispatch(webhooks.EventClientDisconnected, clientEvent(client))
 }
 
 // checkVersions print if client and server versions dont match.
@@ -428,16 +441,10 @@ func (cl *ClientListener) handleSSHRequests(clientLog *logger.Logger, clientID s
 				WithResponse(job).
 				WithClientID(clientID).
 				Save()
+			cl.webhooksService.Dispatch(jobEvent(job))
 
-			if job.MultiJobID != nil {
-				done := cl.jobsDoneChannel.Get(*job.MultiJobID)
-				if done != nil {
-					// to avoid blocking the exec - send job result in a new goroutine
-					go func(done2 chan *models.Job, job2 *models.Job) {
-						done2 <- job2
-					}(done, job)
-				}
-			}
+			cl.signalJobDone(job)
+			cl.relayJobResult(job, r.Payload)
 		case comm.RequestTypeUpdatesStatus:
 			updatesStatus := &models.UpdatesStatus{}
 			err := json.Unmarshal(r.Payload, updatesStatus)
@@ -450,6 +457,18 @@ func (cl *ClientListener) handleSSHRequests(clientLog *logger.Logger, clientID s
 				clientLog.Errorf("Failed to save updates status: %s", err)
 				continue
 			}
+		case comm.RequestTypeInventory:
+			inventory := &models.Inventory{}
+			err := json.Unmarshal(r.Payload, inventory)
+			if err != nil {
+				clientLog.Errorf("Failed to unmarshal inventory: %s", err)
+				continue
+			}
+			err = cl.inventoryService.Save(context.Background(), clientID, inventory)
+			if err != nil {
+				clientLog.Errorf("Failed to save inventory: %s", err)
+				continue
+			}
 		case comm.RequestTypeSaveMeasurement:
 			measurement := &models.Measurement{}
 			err := json.Unmarshal(r.Payload, measurement)
@@ -463,6 +482,13 @@ func (cl *ClientListener) handleSSHRequests(clientLog *logger.Logger, clientID s
 				clientLog.Errorf("Failed to save measurement for client %s: %s", clientID, err)
 				continue
 			}
+			if cl.alertsService != nil {
+				err = cl.alertsService.EvaluateMeasurement(context.Background(), measurement)
+				if err != nil {
+					clientLog.Errorf("Failed to evaluate alert rules for client %s: %s", clientID, err)
+					continue
+				}
+			}
 		default:
 			clientLog.Debugf("Unknown request: %s", r.Type)
 		}
@@ -476,29 +502,48 @@ func (cl *ClientListener) saveCmdResult(respBytes []byte) (*models.Job, error) {
 		return nil, fmt.Errorf("failed to decode cmd result request: %s", err)
 	}
 
+	cl.writeJobResultToUI(&resp, respBytes)
+
+	err = cl.jobProvider.SaveJob(&resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save job result: %s", err)
+	}
+
+	return &resp, nil
+}
+
+// writeJobResultToUI pushes a job result to the UI Web Socket listening for it, if any
+func (s *Server) writeJobResultToUI(job *models.Job, result []byte) {
 	var wsJID string
-	if resp.MultiJobID != nil {
-		wsJID = *resp.MultiJobID
+	if job.MultiJobID != nil {
+		wsJID = *job.MultiJobID
 	} else {
-		wsJID = resp.JID
+		wsJID = job.JID
 	}
-	ws := cl.Server.uiJobWebSockets.Get(wsJID)
+	ws := s.uiJobWebSockets.Get(wsJID)
 	if ws != nil {
-		err := ws.WriteMessage(websocket.TextMessage, respBytes)
+		err := ws.WriteMessage(websocket.TextMessage, result)
 		if err != nil {
-			cl.Errorf("%s, failed to write message to UI Web Socket: %v", resp.LogPrefix(), err)
+			s.Errorf("%s, failed to write message to UI Web Socket: %v", job.LogPrefix(), err)
 			// proceed further
 		}
 	} else {
-		cl.Debugf("%s, WS conn not found when saving command result. No active listeners connected", resp.LogPrefix())
+		s.Debugf("%s, WS conn not found when saving command result. No active listeners connected", job.LogPrefix())
 	}
+}
 
-	err = cl.jobProvider.SaveJob(&resp)
-	if err != nil {
-		return nil, fmt.Errorf("failed to save job result: %s", err)
+// signalJobDone notifies a sequential multi-client job waiting for a job result
+func (s *Server) signalJobDone(job *models.Job) {
+	if job.MultiJobID == nil {
+		return
+	}
+	done := s.jobsDoneChannel.Get(*job.MultiJobID)
+	if done != nil {
+		// to avoid blocking the exec - send job result in a new goroutine
+		go func(done2 chan *models.Job, job2 *models.Job) {
+			done2 <- job2
+		}(done, job)
 	}
-
-	return &resp, nil
 }
 
 func (cl *ClientListener) handleSSHChannels(clientLog *logger.Logger, chans <-chan ssh.NewChannel) {
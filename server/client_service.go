@@ -21,7 +21,12 @@ import (
 	"github.com/cloudradar-monitoring/rport/server/clients"
 	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
 	"github.com/cloudradar-monitoring/rport/server/ports"
+	"github.com/cloudradar-monitoring/rport/server/recordings"
+	"github.com/cloudradar-monitoring/rport/server/tunnelhistory"
+	"github.com/cloudradar-monitoring/rport/server/webhooks"
 	chshare "github.com/cloudradar-monitoring/rport/share"
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/comm"
 	"github.com/cloudradar-monitoring/rport/share/logger"
 	"github.com/cloudradar-monitoring/rport/share/models"
 	"github.com/cloudradar-monitoring/rport/share/query"
@@ -35,7 +40,7 @@ type ClientService interface {
 	GetActiveByID(id string) (*clients.Client, error)
 	GetActiveByGroups(groups []*cgroups.ClientGroup) []*clients.Client
 	GetClientsByTag(tags []string, operator string, allowDisconnected bool) (clients []*clients.Client, err error)
-	PopulateGroupsWithUserClients(groups []*cgroups.ClientGroup, user clients.User)
+	PopulateGroupsWithUserClients(groups, allGroups []*cgroups.ClientGroup, user clients.User, permission string)
 	GetAllByClientID(clientID string) []*clients.Client
 	GetAll() ([]*clients.Client, error)
 	GetUserClients(groups []*cgroups.ClientGroup, user clients.User) ([]*clients.Client, error)
@@ -50,9 +55,10 @@ type ClientService interface {
 	DeleteOffline(clientID string) error
 	SetACL(clientID string, allowedUserGroups []string) error
 	SetUpdatesStatus(clientID string, updatesStatus *models.UpdatesStatus) error
+	SetClientConfiguration(clientID string, config *clientconfig.Config) error
 	SetLastHeartbeat(clientID string, heartbeat time.Time) error
-	CheckClientAccess(clientID string, user clients.User, groups []*cgroups.ClientGroup) error
-	CheckClientsAccess(clients []*clients.Client, user clients.User, groups []*cgroups.ClientGroup) error
+	CheckClientAccess(clientID string, user clients.User, groups []*cgroups.ClientGroup, permission string) error
+	CheckClientsAccess(clients []*clients.Client, user clients.User, groups []*cgroups.ClientGroup, permission string) error
 	GetRepo() *clients.ClientRepository
 }
 
@@ -61,6 +67,15 @@ type ClientServiceProvider struct {
 	portDistributor   *ports.PortDistributor
 	tunnelProxyConfig *clienttunnel.TunnelProxyConfig
 	logger            *logger.Logger
+	// tunnelRateLimitUp and tunnelRateLimitDown are the bytes per second shared by all tunnels of a client
+	tunnelRateLimitUp   int64
+	tunnelRateLimitDown int64
+	// tunnelHistory records the tunnel sessions, it's nil if the tunnel history is disabled
+	tunnelHistory *tunnelhistory.Service
+	// recordings is used by tunnel proxies to record the sessions, it's nil if session recording is disabled
+	recordings *recordings.Service
+	// webhooks is notified about tunnels closed by the server, it's nil if webhooks are disabled
+	webhooks *webhooks.Service
 
 	mu sync.Mutex
 }
@@ -117,6 +132,7 @@ var clientsSupportedFields = map[string]map[string]bool{
 		"version":                  true,
 		"address":                  true,
 		"tunnels":                  true,
+		"tunnel_traffic":           true,
 		"disconnected_at":          true,
 		"last_heartbeat_at":        true,
 		"connection_state":         true,
@@ -168,9 +184,10 @@ func InitClientService(
 	portDistributor *ports.PortDistributor,
 	db *sqlx.DB,
 	keepDisconnectedClients *time.Duration,
+	remoteClientIDs map[string]bool,
 	logger *logger.Logger,
 ) (*ClientServiceProvider, error) {
-	repo, err := clients.InitClientRepository(ctx, db, keepDisconnectedClients, logger)
+	repo, err := clients.InitClientRepository(ctx, db, keepDisconnectedClients, remoteClientIDs, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to init Client Repository: %v", err)
 	}
@@ -178,6 +195,50 @@ func InitClientService(
 	return NewClientService(tunnelProxyConfig, portDistributor, repo, logger), nil
 }
 
+// SetTunnelRateLimits sets the limits in bytes per second applied to the sum of all tunnels of each client
+// connecting afterwards, 0 means unlimited.
+func (s *ClientServiceProvider) SetTunnelRateLimits(up, down int64) {
+	s.tunnelRateLimitUp = up
+	s.tunnelRateLimitDown = down
+}
+
+// SetTunnelHistory sets the service recording the sessions and connections of the tunnels
+func (s *ClientServiceProvider) SetTunnelHistory(tunnelHistory *tunnelhistory.Service) {
+	s.tunnelHistory = tunnelHistory
+}
+
+// SetRecordings sets the service recording the sessions going through tunnel proxies
+func (s *ClientServiceProvider) SetRecordings(recordingsService *recordings.Service) {
+	s.recordings = recordingsService
+}
+
+// SetWebhooks sets the service notifying the webhooks about tunnels closed without an API request
+func (s *ClientServiceProvider) SetWebhooks(webhooksService *webhooks.Service) {
+	s.webhooks = webhooksService
+}
+
+// TunnelStarted implements clients.TunnelObserver
+func (s *ClientServiceProvider) TunnelStarted(client *clients.Client, t *clienttunnel.Tunnel) {
+	s.tunnelHistory.TunnelStarted(client.ID, t)
+}
+
+// TunnelClosed persists the traffic of a closed tunnel and closes its session in the tunnel history
+func (s *ClientServiceProvider) TunnelClosed(client *clients.Client, t *clienttunnel.Tunnel, reason string) {
+	if err := s.repo.Save(client); err != nil {
+		s.logger.Errorf("failed to save traffic of tunnel %s of client %s: %v", t.ID, client.ID, err)
+	}
+	s.tunnelHistory.TunnelClosed(t, reason)
+	// tunnels deleted via the API are reported by the API handler along with the user deleting them
+	if reason != clienttunnel.CloseReasonDeleted {
+		s.webhooks.Dispatch(webhooks.EventTunnelDeleted, tunnelEvent(client.ID, "", t, reason))
+	}
+}
+
+// TunnelConnectionClosed implements clients.TunnelObserver
+func (s *ClientServiceProvider) TunnelConnectionClosed(client *clients.Client, t *clienttunnel.Tunnel, conn clienttunnel.Connection) {
+	s.tunnelHistory.ConnectionClosed(t, conn)
+}
+
 func (s *ClientServiceProvider) Count() (int, error) {
 	return s.repo.Count()
 }
@@ -216,9 +277,14 @@ func (s *ClientServiceProvider) GetClientsByTag(tags []string, operator string,
 	return s.repo.GetClientsByTag(tags, operator, allowDisconnected)
 }
 
-func (s *ClientServiceProvider) PopulateGroupsWithUserClients(groups []*cgroups.ClientGroup, user clients.User) {
-	all, _ := s.repo.GetUserClients(user, groups)
+// PopulateGroupsWithUserClients sets the clients of the given groups the user has access to and, unless it's empty,
+// the given permission for. allGroups are all client groups, they are needed to check the access.
+func (s *ClientServiceProvider) PopulateGroupsWithUserClients(groups, allGroups []*cgroups.ClientGroup, user clients.User, permission string) {
+	all, _ := s.repo.GetUserClients(user, allGroups)
 	for _, curClient := range all {
+		if permission != "" && !curClient.UserHasPermission(user, permission, allGroups) {
+			continue
+		}
 		for _, curGroup := range groups {
 			if curClient.BelongsTo(curGroup) {
 				curGroup.ClientIDs = append(curGroup.ClientIDs, curClient.ID)
@@ -268,7 +334,8 @@ func (s *ClientServiceProvider) StartClient(
 			sessionReUsed = true
 			clog.Debugf("resuming existing session %s for client %s [%s]", req.SessionID, client.Name, clientID)
 		}
-		if client.DisconnectedAt == nil && !sessionReUsed {
+		// a client connected to another node of the cluster is taking over if it reconnected to this node
+		if client.DisconnectedAt == nil && client.Node == "" && !sessionReUsed {
 			return nil, fmt.Errorf("client is already connected: %s [%s]", client.Name, clientID)
 		}
 
@@ -334,12 +401,20 @@ func (s *ClientServiceProvider) StartClient(
 	client.Version = req.Version
 	client.ClientConfiguration = req.ClientConfiguration
 	client.Address = clientHost
+	// the tunnels of the previous session are closed, the required ones are re-established with new counters
+	for _, t := range client.Tunnels {
+		client.AddTunnelTraffic(t)
+		s.closeTunnelSession(t)
+	}
 	client.Tunnels = make([]*clienttunnel.Tunnel, 0)
 	client.DisconnectedAt = nil
 	client.ClientAuthID = clientAuthID
 	client.Connection = sshConn
+	client.Node = ""
 	client.Context = ctx
 	client.Logger = clog
+	client.TunnelRateLimits = clienttunnel.NewRateLimits(s.tunnelRateLimitUp, s.tunnelRateLimitDown)
+	client.TunnelObserver = s
 
 	client.SetConnected()
 
@@ -406,7 +481,7 @@ func (s *ClientServiceProvider) startClientTunnels(client *clients.Client, remot
 		}
 
 		s.logger.Debugf("starting tunnnel: %s", remote)
-		t, err := client.StartTunnel(remote, acl, s.tunnelProxyConfig, s.portDistributor)
+		t, err := client.StartTunnel(remote, acl, s.tunnelProxyConfig, s.recordings, s.portDistributor)
 		if err != nil {
 			return nil, errors.APIError{
 				HTTPStatus: http.StatusConflict,
@@ -451,6 +526,10 @@ func (s *ClientServiceProvider) Terminate(client *clients.Client) error {
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	for _, t := range client.Tunnels {
+		s.closeTunnelSession(t)
+		s.webhooks.Dispatch(webhooks.EventTunnelDeleted, tunnelEvent(client.ID, "", t, clienttunnel.CloseReasonClientDisconnected))
+	}
 	if s.repo.KeepDisconnectedClients != nil && *s.repo.KeepDisconnectedClients == 0 {
 		return s.repo.Delete(client)
 	}
@@ -469,6 +548,15 @@ func (s *ClientServiceProvider) Terminate(client *clients.Client) error {
 	return s.repo.Save(client)
 }
 
+// closeTunnelSession closes the history session of a tunnel that stopped because its client disconnected.
+// Tunnels loaded from the db have no session, closing an already closed session is a no-op.
+func (s *ClientServiceProvider) closeTunnelSession(t *clienttunnel.Tunnel) {
+	if t.SessionID == "" {
+		return
+	}
+	s.tunnelHistory.TunnelClosed(t, clienttunnel.CloseReasonClientDisconnected)
+}
+
 // ForceDelete deletes client from repo regardless off KeepDisconnectedClients setting,
 // if client is active it will be closed
 func (s *ClientServiceProvider) ForceDelete(client *clients.Client) error {
@@ -534,6 +622,20 @@ func (s *ClientServiceProvider) SetUpdatesStatus(clientID string, updatesStatus
 	return s.repo.Save(existing)
 }
 
+// SetClientConfiguration stores the effective configuration reported by a client after configuration profiles
+// were applied, the tags of the client can be set by the profiles.
+func (s *ClientServiceProvider) SetClientConfiguration(clientID string, config *clientconfig.Config) error {
+	existing, err := s.getExistingByID(clientID)
+	if err != nil {
+		return err
+	}
+
+	existing.ClientConfiguration = config
+	existing.Tags = config.Client.Tags
+
+	return s.repo.Save(existing)
+}
+
 func (s *ClientServiceProvider) SetLastHeartbeat(clientID string, heartbeat time.Time) error {
 	existing, err := s.getExistingByID(clientID)
 	if err != nil {
@@ -543,31 +645,32 @@ func (s *ClientServiceProvider) SetLastHeartbeat(clientID string, heartbeat time
 	return nil
 }
 
-// CheckClientAccess returns nil if a given user has an access to a given client.
+// CheckClientAccess returns nil if a given user has an access to a given client and the given permission for it,
+// the permission is not checked if it's empty.
 // Otherwise, APIError with 403 is returned.
-func (s *ClientServiceProvider) CheckClientAccess(clientID string, user clients.User, groups []*cgroups.ClientGroup) error {
+func (s *ClientServiceProvider) CheckClientAccess(clientID string, user clients.User, groups []*cgroups.ClientGroup, permission string) error {
 	existing, err := s.getExistingByID(clientID)
 	if err != nil {
 		return err
 	}
 
-	return s.CheckClientsAccess([]*clients.Client{existing}, user, groups)
+	return s.CheckClientsAccess([]*clients.Client{existing}, user, groups, permission)
 }
 
-// CheckClientsAccess returns nil if a given user has an access to all of the given clients.
+// CheckClientsAccess returns nil if a given user has an access to all of the given clients and the given permission
+// for them, the permission is not checked if it's empty.
 // Otherwise, APIError with 403 is returned.
-func (s *ClientServiceProvider) CheckClientsAccess(clients []*clients.Client, user clients.User, clientGroups []*cgroups.ClientGroup) error {
-	if user.IsAdmin() {
-		return nil
-	}
-
+func (s *ClientServiceProvider) CheckClientsAccess(clients []*clients.Client, user clients.User, clientGroups []*cgroups.ClientGroup, permission string) error {
 	var clientsWithNoAccess []string
-	userGroups := user.GetGroups()
+	var clientsWithNoPermission []string
 	for _, curClient := range clients {
-		if curClient.HasAccessViaUserGroups(userGroups) || curClient.UserGroupHasAccessViaClientGroup(userGroups, clientGroups) {
+		if !curClient.UserHasAccess(user, clientGroups) {
+			clientsWithNoAccess = append(clientsWithNoAccess, curClient.ID)
 			continue
 		}
-		clientsWithNoAccess = append(clientsWithNoAccess, curClient.ID)
+		if permission != "" && !curClient.UserHasPermission(user, permission, clientGroups) {
+			clientsWithNoPermission = append(clientsWithNoPermission, curClient.ID)
+		}
 	}
 
 	if len(clientsWithNoAccess) > 0 {
@@ -577,6 +680,13 @@ func (s *ClientServiceProvider) CheckClientsAccess(clients []*clients.Client, us
 		}
 	}
 
+	if len(clientsWithNoPermission) > 0 {
+		return errors.APIError{
+			Message:    fmt.Sprintf("No %s permission for client(s) with ID(s): %v", permission, strings.Join(clientsWithNoPermission, ", ")),
+			HTTPStatus: http.StatusForbidden,
+		}
+	}
+
 	return nil
 }
 
@@ -611,7 +721,11 @@ func (s *ClientServiceProvider) GetRepo() *clients.ClientRepository {
 func ExcludeNotAllowedTunnels(clog *logger.Logger, tunnels []*models.Remote, conn ssh.Conn) ([]*models.Remote, error) {
 	filtered := make([]*models.Remote, 0, len(tunnels))
 	for _, t := range tunnels {
-		allowed, err := clienttunnel.IsAllowed(t.Remote(), conn)
+		allowed, err := clienttunnel.IsAllowed(&comm.CheckTunnelAllowedRequest{
+			Remote:     t.Remote(),
+			User:       t.Owner,
+			UserGroups: t.OwnerGroups,
+		}, conn)
 		if err != nil {
 			if strings.Contains(err.Error(), "unknown request") {
 				return tunnels, nil
@@ -2,6 +2,7 @@ package chserver
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -16,12 +17,14 @@ import (
 	mapset "github.com/deckarep/golang-set"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
 
 	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
 	"github.com/cloudradar-monitoring/rport/server/api/users"
 	"github.com/cloudradar-monitoring/rport/server/clients"
 	"github.com/cloudradar-monitoring/rport/server/ports"
 	chshare "github.com/cloudradar-monitoring/rport/share"
+	"github.com/cloudradar-monitoring/rport/share/comm"
 	"github.com/cloudradar-monitoring/rport/share/models"
 	"github.com/cloudradar-monitoring/rport/share/test"
 )
@@ -363,7 +366,7 @@ func TestCheckClientsAccess(t *testing.T) {
 			clientService := NewClientService(nil, nil, clients.NewClientRepository(allClients, nil, testLog), testLog)
 
 			// when
-			gotErr := clientService.CheckClientsAccess(tc.clients, tc.user, clientGroups)
+			gotErr := clientService.CheckClientsAccess(tc.clients, tc.user, clientGroups, "")
 
 			// then
 			if len(tc.wantClientIDsWithNoAccess) > 0 {
@@ -378,3 +381,156 @@ func TestCheckClientsAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckClientsAccessWithPermissionScopes(t *testing.T) {
+	web := clients.New(t).ID("web-1").AllowedUserGroups([]string{"team"}).Build()
+	db := clients.New(t).ID("db-1").AllowedUserGroups([]string{"team"}).Build()
+	allClients := []*clients.Client{web, db}
+	clientGroups := []*cgroups.ClientGroup{
+		{
+			ID:     "web",
+			Params: &cgroups.ClientParams{ClientID: &cgroups.ParamValues{"web-*"}},
+		},
+		{
+			ID:     "db",
+			Params: &cgroups.ClientParams{ClientID: &cgroups.ParamValues{"db-*"}},
+		},
+	}
+	user := &users.User{
+		Groups: []string{"team"},
+		PermissionScopes: map[string][]string{
+			users.PermissionCommands: {"web"},
+			users.PermissionTunnels:  {"web", "db"},
+			users.PermissionScripts:  {},
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		permission string
+		wantErr    error
+	}{
+		{
+			name:       "permission not checked",
+			permission: "",
+		},
+		{
+			name:       "permission not limited",
+			permission: users.PermissionUploads,
+		},
+		{
+			name:       "permission for all client groups",
+			permission: users.PermissionTunnels,
+		},
+		{
+			name:       "permission for one client group",
+			permission: users.PermissionCommands,
+			wantErr: errors2.APIError{
+				Message:    "No commands permission for client(s) with ID(s): db-1",
+				HTTPStatus: http.StatusForbidden,
+			},
+		},
+		{
+			name:       "permission for no client group",
+			permission: users.PermissionScripts,
+			wantErr: errors2.APIError{
+				Message:    "No scripts permission for client(s) with ID(s): web-1, db-1",
+				HTTPStatus: http.StatusForbidden,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clientService := NewClientService(nil, nil, clients.NewClientRepository(allClients, nil, testLog), testLog)
+
+			gotErr := clientService.CheckClientsAccess(allClients, user, clientGroups, tc.permission)
+
+			assert.Equal(t, tc.wantErr, gotErr)
+		})
+	}
+}
+
+func TestPopulateGroupsWithUserClientsWithPermissionScopes(t *testing.T) {
+	web := clients.New(t).ID("web-1").AllowedUserGroups([]string{"team"}).Build()
+	db := clients.New(t).ID("db-1").AllowedUserGroups([]string{"team"}).Build()
+	allClients := []*clients.Client{web, db}
+	user := &users.User{
+		Groups: []string{"team"},
+		PermissionScopes: map[string][]string{
+			users.PermissionMonitoring: {"web"},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		permission    string
+		wantClientIDs []string
+	}{
+		{
+			name:          "permission not checked",
+			permission:    "",
+			wantClientIDs: []string{"db-1", "web-1"},
+		},
+		{
+			name:          "permission not limited",
+			permission:    users.PermissionCommands,
+			wantClientIDs: []string{"db-1", "web-1"},
+		},
+		{
+			name:          "permission for one client group",
+			permission:    users.PermissionMonitoring,
+			wantClientIDs: []string{"web-1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			all := &cgroups.ClientGroup{
+				ID:     "all",
+				Params: &cgroups.ClientParams{ClientID: &cgroups.ParamValues{"*"}},
+			}
+			allGroups := []*cgroups.ClientGroup{
+				all,
+				{ID: "web", Params: &cgroups.ClientParams{ClientID: &cgroups.ParamValues{"web-*"}}},
+				{ID: "db", Params: &cgroups.ClientParams{ClientID: &cgroups.ParamValues{"db-*"}}},
+			}
+			clientService := NewClientService(nil, nil, clients.NewClientRepository(allClients, nil, testLog), testLog)
+
+			clientService.PopulateGroupsWithUserClients([]*cgroups.ClientGroup{all}, allGroups, user, tc.permission)
+
+			assert.Equal(t, tc.wantClientIDs, all.ClientIDs)
+		})
+	}
+}
+
+// tunnelPolicyConnMock rejects the tunnels of users in the denied group like a client with a user policy
+type tunnelPolicyConnMock struct {
+	ssh.Conn
+	deniedGroup string
+}
+
+func (c *tunnelPolicyConnMock) SendRequest(name string, wantReply bool, payload []byte) (bool, []byte, error) {
+	req := comm.CheckTunnelAllowedRequest{}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return false, nil, err
+	}
+	allowed := true
+	for _, group := range req.UserGroups {
+		if group == c.deniedGroup {
+			allowed = false
+		}
+	}
+	resp, err := json.Marshal(comm.CheckTunnelAllowedResponse{IsAllowed: allowed})
+	return true, resp, err
+}
+
+func TestExcludeNotAllowedTunnels(t *testing.T) {
+	allowed := &models.Remote{RemoteHost: "127.0.0.1", RemotePort: "22", Owner: "admin", OwnerGroups: []string{"ops"}}
+	denied := &models.Remote{RemoteHost: "127.0.0.1", RemotePort: "80", Owner: "intern", OwnerGroups: []string{"interns"}}
+
+	tunnels, err := ExcludeNotAllowedTunnels(testLog, []*models.Remote{allowed, denied}, &tunnelPolicyConnMock{deniedGroup: "interns"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*models.Remote{allowed}, tunnels)
+}
@@ -32,6 +32,10 @@ func (t *ClientsStatusCheckTask) Run(ctx context.Context) error {
 	var confirmedClients = 0
 	var now = time.Now()
 	for _, c := range t.cr.GetAllActive() {
+		if c.Node != "" {
+			// clients connected to other nodes of the cluster are checked by these nodes
+			continue
+		}
 		// Shorten the threshold aka make heartbeat older than it is because the ping response is stored after this check.
 		// Clients would get checked only every second time otherwise.
 		if c.LastHeartbeatAt != nil && now.Sub(*c.LastHeartbeatAt) < t.th-10*time.Second {
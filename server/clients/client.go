@@ -14,6 +14,7 @@ import (
 	"github.com/cloudradar-monitoring/rport/server/cgroups"
 	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
 	"github.com/cloudradar-monitoring/rport/server/ports"
+	"github.com/cloudradar-monitoring/rport/server/recordings"
 	"github.com/cloudradar-monitoring/rport/share/clientconfig"
 	"github.com/cloudradar-monitoring/rport/share/logger"
 	"github.com/cloudradar-monitoring/rport/share/models"
@@ -67,14 +68,36 @@ type Client struct {
 	AllowedUserGroups   []string              `json:"allowed_user_groups"`
 	UpdatesStatus       *models.UpdatesStatus `json:"updates_status"`
 	ClientConfiguration *clientconfig.Config  `json:"client_configuration"`
+	// TunnelTraffic is the sum of the traffic of all closed tunnels of the client
+	TunnelTraffic TunnelTraffic `json:"tunnel_traffic"`
 
 	Connection ssh.Conn        `json:"-"`
 	Context    context.Context `json:"-"`
 	Logger     *logger.Logger  `json:"-"`
+	// Node is the id of the cluster node the client is connected to, empty if it's connected to this node
+	Node string `json:"-"`
+	// TunnelRateLimits are shared by all tunnels of the client, nil if unlimited
+	TunnelRateLimits *clienttunnel.RateLimits `json:"-"`
+	// TunnelObserver is notified about the tunnels of the client, it can be nil
+	TunnelObserver TunnelObserver `json:"-"`
 
 	lock sync.Mutex
 }
 
+// TunnelObserver is notified when tunnels of a client are started and closed
+type TunnelObserver interface {
+	TunnelStarted(c *Client, t *clienttunnel.Tunnel)
+	// TunnelClosed is called after the traffic of the tunnel was added to the TunnelTraffic of the client
+	TunnelClosed(c *Client, t *clienttunnel.Tunnel, reason string)
+	TunnelConnectionClosed(c *Client, t *clienttunnel.Tunnel, conn clienttunnel.Connection)
+}
+
+// TunnelTraffic is the number of bytes sent to and received from the remotes of tunnels
+type TunnelTraffic struct {
+	BytesSent     int64 `json:"bytes_sent"`
+	BytesReceived int64 `json:"bytes_received"`
+}
+
 // CalculatedClient contains additional fields and is calculated on each request
 type CalculatedClient struct {
 	*Client
@@ -132,7 +155,7 @@ func (c *Client) FindTunnelByRemote(r *models.Remote) *clienttunnel.Tunnel {
 	return nil
 }
 
-func (c *Client) StartTunnel(r *models.Remote, acl *clienttunnel.TunnelACL, tunnelProxyConfig *clienttunnel.TunnelProxyConfig, portDistributor *ports.PortDistributor) (*clienttunnel.Tunnel, error) {
+func (c *Client) StartTunnel(r *models.Remote, acl *clienttunnel.TunnelACL, tunnelProxyConfig *clienttunnel.TunnelProxyConfig, recordingsService *recordings.Service, portDistributor *ports.PortDistributor) (*clienttunnel.Tunnel, error) {
 	t := c.FindTunnelByRemote(r)
 	if t != nil {
 		return t, nil
@@ -156,10 +179,11 @@ func (c *Client) StartTunnel(r *models.Remote, acl *clienttunnel.TunnelACL, tunn
 	}
 
 	tunnelID := strconv.FormatInt(c.generateNewTunnelID(), 10)
-	t, err := clienttunnel.NewTunnel(c.Logger, c.Connection, tunnelID, *r, acl)
+	t, err := clienttunnel.NewTunnel(c.Logger, c.Connection, tunnelID, *r, acl, c.TunnelRateLimits)
 	if err != nil {
 		return nil, err
 	}
+	t.OnConnectionClosed = c.tunnelConnectionClosed
 
 	ctx := c.Context
 	if r.AutoClose > 0 {
@@ -174,7 +198,7 @@ func (c *Client) StartTunnel(r *models.Remote, acl *clienttunnel.TunnelACL, tunn
 
 	// start tunnel proxy
 	if startTunnelProxy {
-		tProxy := clienttunnel.NewTunnelProxy(t, c.Logger, tunnelProxyConfig, proxyHost, proxyPort, proxyACL)
+		tProxy := clienttunnel.NewTunnelProxy(t, c.ID, c.Logger, tunnelProxyConfig, recordingsService, proxyHost, proxyPort, proxyACL)
 		if err := tProxy.Start(ctx); err != nil {
 			c.Logger.Debugf("tunnel proxy could not be started, tunnel must be terminated: %v", err)
 			if tErr := t.Terminate(true); tErr != nil {
@@ -195,7 +219,7 @@ func (c *Client) StartTunnel(r *models.Remote, acl *clienttunnel.TunnelACL, tunn
 			<-ctx.Done()
 			// DeadlineExceeded err is expected when tunnel AutoClose period is reached, otherwise skip cleanup
 			if ctx.Err() == context.DeadlineExceeded {
-				c.cleanupAfterAutoClose(t)
+				c.cleanupAfterAutoClose(t, clienttunnel.CloseReasonAutoClose)
 			}
 		}()
 	}
@@ -215,7 +239,7 @@ func (c *Client) StartTunnel(r *models.Remote, acl *clienttunnel.TunnelACL, tunn
 					if sinceLastActive > idleTimeout {
 						c.Logger.Infof("Terminating... inactivity period is reached: %d minute(s)", t.IdleTimeoutMinutes)
 						_ = t.Terminate(true)
-						c.cleanupAfterAutoClose(t)
+						c.cleanupAfterAutoClose(t, clienttunnel.CloseReasonIdleTimeout)
 						return
 					}
 					timer.Reset(idleTimeout - sinceLastActive)
@@ -224,11 +248,15 @@ func (c *Client) StartTunnel(r *models.Remote, acl *clienttunnel.TunnelACL, tunn
 		}()
 	}
 
+	if c.TunnelObserver != nil {
+		c.TunnelObserver.TunnelStarted(c, t)
+	}
+
 	c.Tunnels = append(c.Tunnels, t)
 	return t, nil
 }
 
-func (c *Client) cleanupAfterAutoClose(t *clienttunnel.Tunnel) {
+func (c *Client) cleanupAfterAutoClose(t *clienttunnel.Tunnel, reason string) {
 	c.Lock()
 	defer c.Unlock()
 
@@ -241,6 +269,7 @@ func (c *Client) cleanupAfterAutoClose(t *clienttunnel.Tunnel) {
 
 	c.removeTunnelByID(t.ID)
 	c.Logger.Debugf("tunnel with id=%s removed", t.ID)
+	c.tunnelClosed(t, reason)
 }
 
 func (c *Client) TerminateTunnel(t *clienttunnel.Tunnel, force bool) error {
@@ -255,9 +284,30 @@ func (c *Client) TerminateTunnel(t *clienttunnel.Tunnel, force bool) error {
 		}
 	}
 	c.removeTunnelByID(t.ID)
+	c.tunnelClosed(t, clienttunnel.CloseReasonDeleted)
 	return nil
 }
 
+// AddTunnelTraffic adds the traffic of a tunnel that is not running anymore to the traffic of the client
+func (c *Client) AddTunnelTraffic(t *clienttunnel.Tunnel) {
+	sent, received := t.Traffic()
+	c.TunnelTraffic.BytesSent += sent
+	c.TunnelTraffic.BytesReceived += received
+}
+
+func (c *Client) tunnelClosed(t *clienttunnel.Tunnel, reason string) {
+	c.AddTunnelTraffic(t)
+	if c.TunnelObserver != nil {
+		c.TunnelObserver.TunnelClosed(c, t, reason)
+	}
+}
+
+func (c *Client) tunnelConnectionClosed(t *clienttunnel.Tunnel, conn clienttunnel.Connection) {
+	if c.TunnelObserver != nil {
+		c.TunnelObserver.TunnelConnectionClosed(c, t, conn)
+	}
+}
+
 func (c *Client) FindTunnel(id string) *clienttunnel.Tunnel {
 	for _, curr := range c.Tunnels {
 		if curr.ID == id {
@@ -362,6 +412,41 @@ func (c *Client) CalculateConnectionState() ConnectionState {
 	return Disconnected
 }
 
+// UserHasAccess returns true if a given user has access to a current client either being an admin or via the user
+// groups. If the access of the user is limited to some client groups the client must belong to one of them.
+func (c *Client) UserHasAccess(user User, allClientGroups []*cgroups.ClientGroup) bool {
+	if limitIDs := user.GetClientGroupIDs(); len(limitIDs) > 0 {
+		if !c.BelongsToOneOf(filterClientGroupsByID(allClientGroups, limitIDs)) {
+			return false
+		}
+	}
+
+	userGroups := user.GetGroups()
+	return user.IsAdmin() || c.HasAccessViaUserGroups(userGroups) || c.UserGroupHasAccessViaClientGroup(userGroups, allClientGroups)
+}
+
+// UserHasPermission returns true if the given permission of the user is not limited to some client groups or the
+// current client is member of one of them.
+func (c *Client) UserHasPermission(user User, permission string, allClientGroups []*cgroups.ClientGroup) bool {
+	clientGroupIDs, limited := user.GetPermissionScope(permission)
+	if !limited {
+		return true
+	}
+	return c.BelongsToOneOf(filterClientGroupsByID(allClientGroups, clientGroupIDs))
+}
+
+func filterClientGroupsByID(allClientGroups []*cgroups.ClientGroup, ids []string) []*cgroups.ClientGroup {
+	var result []*cgroups.ClientGroup
+	for _, group := range allClientGroups {
+		for _, id := range ids {
+			if group.ID == id {
+				result = append(result, group)
+			}
+		}
+	}
+	return result
+}
+
 // HasAccessViaUserGroups returns true if at least one of given user groups has access to a current client.
 func (c *Client) HasAccessViaUserGroups(userGroups []string) bool {
 	for _, curUserGroup := range userGroups {
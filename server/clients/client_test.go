@@ -5,9 +5,12 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/cloudradar-monitoring/rport/server/api/users"
 	"github.com/cloudradar-monitoring/rport/server/cgroups"
+	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	chshare "github.com/cloudradar-monitoring/rport/share"
 )
 
 func TestClientBelongsToGroup(t *testing.T) {
@@ -325,6 +328,108 @@ func TestHasAccess(t *testing.T) {
 	}
 }
 
+func TestUserHasAccess(t *testing.T) {
+	group1 := &cgroups.ClientGroup{
+		ID:     "group-1",
+		Params: &cgroups.ClientParams{ClientID: &cgroups.ParamValues{"client-1"}},
+	}
+	group2 := &cgroups.ClientGroup{
+		ID:                "group-2",
+		Params:            &cgroups.ClientParams{ClientID: &cgroups.ParamValues{"client-2"}},
+		AllowedUserGroups: []string{"group2"},
+	}
+	allGroups := []*cgroups.ClientGroup{group1, group2}
+	client1 := &Client{ID: "client-1"}
+	client2 := &Client{ID: "client-2"}
+
+	testCases := []struct {
+		name string
+
+		client *Client
+		user   UserMock
+
+		wantRes bool
+	}{
+		{
+			name:    "admin",
+			client:  client2,
+			user:    UserMock{ReturnIsAdmin: true},
+			wantRes: true,
+		},
+		{
+			name:    "admin limited to other client group",
+			client:  client2,
+			user:    UserMock{ReturnIsAdmin: true, ReturnClientGroupIDs: []string{"group-1"}},
+			wantRes: false,
+		},
+		{
+			name:    "admin limited to client group of client",
+			client:  client1,
+			user:    UserMock{ReturnIsAdmin: true, ReturnClientGroupIDs: []string{"group-1"}},
+			wantRes: true,
+		},
+		{
+			name:    "user with access via client group",
+			client:  client2,
+			user:    UserMock{ReturnGroups: []string{"group2"}},
+			wantRes: true,
+		},
+		{
+			name:    "user with access via client group limited to client group",
+			client:  client2,
+			user:    UserMock{ReturnGroups: []string{"group2"}, ReturnClientGroupIDs: []string{"group-2"}},
+			wantRes: true,
+		},
+		{
+			name:    "user limited to client group without access",
+			client:  client1,
+			user:    UserMock{ReturnGroups: []string{"group2"}, ReturnClientGroupIDs: []string{"group-1"}},
+			wantRes: false,
+		},
+		{
+			name:    "limited to unknown client group",
+			client:  client1,
+			user:    UserMock{ReturnIsAdmin: true, ReturnClientGroupIDs: []string{"unknown"}},
+			wantRes: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			gotRes := tc.client.UserHasAccess(tc.user, allGroups)
+
+			// then
+			assert.Equal(t, tc.wantRes, gotRes)
+		})
+	}
+}
+
+func TestUserHasPermission(t *testing.T) {
+	web := &cgroups.ClientGroup{
+		ID:     "web",
+		Params: &cgroups.ClientParams{ClientID: &cgroups.ParamValues{"web-*"}},
+	}
+	db := &cgroups.ClientGroup{
+		ID:     "db",
+		Params: &cgroups.ClientParams{ClientID: &cgroups.ParamValues{"db-*"}},
+	}
+	allGroups := []*cgroups.ClientGroup{web, db}
+	webClient := &Client{ID: "web-1"}
+	dbClient := &Client{ID: "db-1"}
+	user := UserMock{ReturnPermissionScopes: map[string][]string{
+		"commands": {"web"},
+		"tunnels":  {"web", "db"},
+		"scripts":  {},
+	}}
+
+	assert.True(t, webClient.UserHasPermission(user, "commands", allGroups))
+	assert.False(t, dbClient.UserHasPermission(user, "commands", allGroups))
+	assert.True(t, dbClient.UserHasPermission(user, "tunnels", allGroups))
+	assert.False(t, webClient.UserHasPermission(user, "scripts", allGroups))
+	assert.True(t, dbClient.UserHasPermission(user, "uploads", allGroups), "not limited")
+}
+
 func TestToCalculatedForGroups(t *testing.T) {
 	client := &Client{
 		Name: "abc",
@@ -380,3 +485,51 @@ func TestToCalculatedWhenDisconnected(t *testing.T) {
 	assert.Equal(t, client, calculated.Client)
 	assert.Equal(t, "disconnected", string(calculated.ConnectionState))
 }
+
+type tunnelProtocolMock struct {
+	clienttunnel.TunnelProtocol
+	stats chshare.ConnStatsSnapshot
+}
+
+func (m *tunnelProtocolMock) Terminate(bool) error {
+	return nil
+}
+
+func (m *tunnelProtocolMock) Stats() chshare.ConnStatsSnapshot {
+	return m.stats
+}
+
+type tunnelObserverMock struct {
+	closed []string
+}
+
+func (m *tunnelObserverMock) TunnelStarted(*Client, *clienttunnel.Tunnel) {}
+
+func (m *tunnelObserverMock) TunnelClosed(c *Client, t *clienttunnel.Tunnel, reason string) {
+	m.closed = append(m.closed, t.ID+":"+reason)
+}
+
+func (m *tunnelObserverMock) TunnelConnectionClosed(*Client, *clienttunnel.Tunnel, clienttunnel.Connection) {
+}
+
+func TestTerminateTunnelAddsTraffic(t *testing.T) {
+	tunnel := &clienttunnel.Tunnel{
+		ID:             "1",
+		TunnelProtocol: &tunnelProtocolMock{stats: chshare.ConnStatsSnapshot{BytesSent: 10, BytesReceived: 20}},
+	}
+	c := &Client{
+		ID:            "client-1",
+		Logger:        testLog,
+		Tunnels:       []*clienttunnel.Tunnel{tunnel},
+		TunnelTraffic: TunnelTraffic{BytesSent: 1, BytesReceived: 2},
+	}
+	observer := &tunnelObserverMock{}
+	c.TunnelObserver = observer
+
+	err := c.TerminateTunnel(c.Tunnels[0], true)
+	require.NoError(t, err)
+
+	assert.Empty(t, c.Tunnels)
+	assert.Equal(t, []string{"1:deleted"}, observer.closed)
+	assert.Equal(t, TunnelTraffic{BytesSent: 11, BytesReceived: 22}, c.TunnelTraffic)
+}
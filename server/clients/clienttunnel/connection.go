@@ -0,0 +1,97 @@
+package clienttunnel
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// Reasons why a tunnel was closed
+const (
+	CloseReasonDeleted            = "deleted"
+	CloseReasonAutoClose          = "auto_close"
+	CloseReasonIdleTimeout        = "idle_timeout"
+	CloseReasonClientDisconnected = "client_disconnected"
+	CloseReasonServerStopped      = "server_stopped"
+)
+
+// udpPeerTimeout is the inactivity after which a udp peer is reported as a closed connection
+var udpPeerTimeout = 2 * time.Minute
+
+// Connection is a closed connection of a tunnel. For udp there are no connections,
+// it contains the datagrams exchanged with a peer until the peer was inactive for udpPeerTimeout.
+type Connection struct {
+	Protocol      string
+	SourceIP      string
+	StartedAt     time.Time
+	EndedAt       time.Time
+	BytesSent     int64
+	BytesReceived int64
+}
+
+// udpPeers tracks the traffic of the udp peers of a tunnel
+type udpPeers struct {
+	onClosed func(Connection)
+
+	mu    sync.Mutex
+	peers map[string]*udpPeer
+}
+
+type udpPeer struct {
+	Connection
+	lastActive time.Time
+}
+
+func newUDPPeers(onClosed func(Connection)) *udpPeers {
+	return &udpPeers{
+		onClosed: onClosed,
+		peers:    make(map[string]*udpPeer),
+	}
+}
+
+func (p *udpPeers) add(addr *net.UDPAddr, sent, received int) {
+	if p.onClosed == nil {
+		return
+	}
+	now := time.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	peer, ok := p.peers[addr.String()]
+	if !ok {
+		peer = &udpPeer{
+			Connection: Connection{
+				Protocol:  "udp",
+				SourceIP:  addr.IP.String(),
+				StartedAt: now,
+			},
+		}
+		p.peers[addr.String()] = peer
+	}
+	peer.lastActive = now
+	peer.BytesSent += int64(sent)
+	peer.BytesReceived += int64(received)
+}
+
+// closeInactive reports the peers inactive since before the given time, all peers if it's zero
+func (p *udpPeers) closeInactive(before time.Time) {
+	if p.onClosed == nil {
+		return
+	}
+
+	var closed []Connection
+	p.mu.Lock()
+	for addr, peer := range p.peers {
+		if before.IsZero() || peer.lastActive.Before(before) {
+			peer.EndedAt = peer.lastActive
+			closed = append(closed, peer.Connection)
+			delete(p.peers, addr)
+		}
+	}
+	p.mu.Unlock()
+
+	for _, c := range closed {
+		p.onClosed(c)
+	}
+}
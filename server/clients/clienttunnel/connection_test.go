@@ -0,0 +1,41 @@
+package clienttunnel
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUDPPeersCloseInactive(t *testing.T) {
+	var closed []Connection
+	peers := newUDPPeers(func(c Connection) {
+		closed = append(closed, c)
+	})
+	peer1 := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 1), Port: 5000}
+	peer2 := &net.UDPAddr{IP: net.IPv4(192, 0, 2, 2), Port: 5000}
+
+	peers.add(peer1, 10, 0)
+	peers.add(peer1, 0, 20)
+	peers.add(peer2, 5, 0)
+	peers.peers[peer2.String()].lastActive = time.Now().Add(-time.Hour)
+
+	peers.closeInactive(time.Now().Add(-time.Minute))
+	require.Len(t, closed, 1)
+	assert.Equal(t, "192.0.2.2", closed[0].SourceIP)
+	assert.Equal(t, int64(5), closed[0].BytesSent)
+
+	peers.closeInactive(time.Time{})
+	require.Len(t, closed, 2)
+	assert.Equal(t, Connection{
+		Protocol:      "udp",
+		SourceIP:      "192.0.2.1",
+		StartedAt:     closed[1].StartedAt,
+		EndedAt:       closed[1].EndedAt,
+		BytesSent:     10,
+		BytesReceived: 20,
+	}, closed[1])
+	assert.Empty(t, peers.peers)
+}
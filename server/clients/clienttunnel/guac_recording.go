@@ -0,0 +1,53 @@
+package clienttunnel
+
+import (
+	"github.com/wwt/guac"
+
+	"github.com/cloudradar-monitoring/rport/server/recordings"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+// recordingGuacTunnel writes all instructions sent by guacd to a recording. That's the same content guacd writes to
+// its own session recordings, so the standard Guacamole tools can be used to play it.
+type recordingGuacTunnel struct {
+	guac.Tunnel
+	recording *recordings.Session
+	logger    *logger.Logger
+}
+
+func newRecordingGuacTunnel(tunnel guac.Tunnel, recording *recordings.Session, logger *logger.Logger) *recordingGuacTunnel {
+	return &recordingGuacTunnel{
+		Tunnel:    tunnel,
+		recording: recording,
+		logger:    logger,
+	}
+}
+
+func (t *recordingGuacTunnel) AcquireReader() guac.InstructionReader {
+	return &recordingInstructionReader{
+		InstructionReader: t.Tunnel.AcquireReader(),
+		tunnel:            t,
+	}
+}
+
+func (t *recordingGuacTunnel) Close() error {
+	if err := t.recording.Close(); err != nil {
+		t.logger.Errorf("failed to close recording: %v", err)
+	}
+	return t.Tunnel.Close()
+}
+
+type recordingInstructionReader struct {
+	guac.InstructionReader
+	tunnel *recordingGuacTunnel
+}
+
+func (r *recordingInstructionReader) ReadSome() ([]byte, error) {
+	ins, err := r.InstructionReader.ReadSome()
+	if len(ins) > 0 {
+		if _, werr := r.tunnel.recording.Write(ins); werr != nil {
+			r.tunnel.logger.Errorf("failed to record guacamole instruction: %v", werr)
+		}
+	}
+	return ins, err
+}
@@ -0,0 +1,151 @@
+package clienttunnel
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	chshare "github.com/cloudradar-monitoring/rport/share"
+)
+
+// RateLimiter limits the number of bytes per second using a token bucket with a burst of one second.
+// A nil RateLimiter doesn't limit anything.
+type RateLimiter struct {
+	rate int64 // bytes per second
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a limiter for the given bytes per second or nil if bytesPerSec is not positive.
+func NewRateLimiter(bytesPerSec int64) *RateLimiter {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	return &RateLimiter{
+		rate:   bytesPerSec,
+		tokens: float64(bytesPerSec),
+		last:   time.Now(),
+	}
+}
+
+// WaitN takes n bytes from the bucket and blocks until the rate allows them or ctx is done.
+// n may exceed the burst, the bytes are then paid off by waiting longer.
+func (l *RateLimiter) WaitN(ctx context.Context, n int) error {
+	if l == nil || n <= 0 {
+		return nil
+	}
+
+	delay := l.reserve(n, time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes n bytes from the bucket and returns how long to wait until they are available
+func (l *RateLimiter) reserve(n int, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens += now.Sub(l.last).Seconds() * float64(l.rate)
+	if l.tokens > float64(l.rate) {
+		l.tokens = float64(l.rate)
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / float64(l.rate) * float64(time.Second))
+}
+
+// RateLimits limits both directions of a tunnel. Up limits the bytes sent to the remote,
+// Down the bytes received from the remote.
+type RateLimits struct {
+	Up   *RateLimiter
+	Down *RateLimiter
+}
+
+// NewRateLimits returns the limits for the given bytes per second, a value of 0 means unlimited.
+func NewRateLimits(up, down int64) *RateLimits {
+	if up <= 0 && down <= 0 {
+		return nil
+	}
+	return &RateLimits{
+		Up:   NewRateLimiter(up),
+		Down: NewRateLimiter(down),
+	}
+}
+
+// rateLimits are all limits applied to a tunnel, e.g. the limits of the tunnel and the limits shared by all tunnels of a client
+type rateLimits []*RateLimits
+
+func (rl rateLimits) waitUp(ctx context.Context, n int) error {
+	for _, l := range rl {
+		if l == nil {
+			continue
+		}
+		if err := l.Up.WaitN(ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rl rateLimits) waitDown(ctx context.Context, n int) error {
+	for _, l := range rl {
+		if l == nil {
+			continue
+		}
+		if err := l.Down.WaitN(ctx, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// meteredConn counts the bytes read from a connection as soon as they are read and throttles the reads
+type meteredConn struct {
+	io.ReadWriteCloser
+	ctx  context.Context
+	wait func(ctx context.Context, n int) error
+	add  func(n int64)
+}
+
+func (c *meteredConn) Read(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Read(p)
+	if n > 0 {
+		c.add(int64(n))
+		if waitErr := c.wait(c.ctx, n); waitErr != nil && err == nil {
+			err = waitErr
+		}
+	}
+	return n, err
+}
+
+// meter returns src and dst wrapped so the bytes sent from src to dst and received from dst are counted in stats
+// and limited by limits
+func (rl rateLimits) meter(ctx context.Context, stats *chshare.ConnStats, src, dst io.ReadWriteCloser) (io.ReadWriteCloser, io.ReadWriteCloser) {
+	return &meteredConn{
+		ReadWriteCloser: src,
+		ctx:             ctx,
+		wait:            rl.waitUp,
+		add:             func(n int64) { stats.AddBytes(n, 0) },
+	}, &meteredConn{
+		ReadWriteCloser: dst,
+		ctx:             ctx,
+		wait:            rl.waitDown,
+		add:             func(n int64) { stats.AddBytes(0, n) },
+	}
+}
@@ -0,0 +1,91 @@
+package clienttunnel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	chshare "github.com/cloudradar-monitoring/rport/share"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	l := NewRateLimiter(1000)
+	start := l.last
+
+	// the burst of one second is available immediately
+	assert.Equal(t, time.Duration(0), l.reserve(1000, start))
+	// further bytes have to wait until they are refilled
+	assert.Equal(t, 500*time.Millisecond, l.reserve(500, start))
+	// bytes exceeding the burst are paid off by waiting longer
+	assert.Equal(t, 2500*time.Millisecond, l.reserve(2000, start))
+	// refilled tokens reduce the debt
+	assert.Equal(t, 500*time.Millisecond, l.reserve(0, start.Add(2*time.Second)))
+	// the bucket is not filled beyond the burst
+	assert.Equal(t, time.Duration(0), l.reserve(1000, start.Add(time.Hour)))
+	assert.Equal(t, 100*time.Millisecond, l.reserve(100, start.Add(time.Hour)))
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	assert.Nil(t, NewRateLimiter(0))
+	assert.Nil(t, NewRateLimits(0, 0))
+
+	var l *RateLimiter
+	assert.NoError(t, l.WaitN(context.Background(), 1<<30))
+}
+
+func TestRateLimiterWaitCanceled(t *testing.T) {
+	l := NewRateLimiter(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.NoError(t, l.WaitN(ctx, 10))
+	assert.ErrorIs(t, l.WaitN(ctx, 10), context.Canceled)
+}
+
+type nopCloser struct {
+	io.ReadWriter
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func TestRateLimitsMeter(t *testing.T) {
+	limits := rateLimits{NewRateLimits(0, 100), nil}
+	stats := &chshare.ConnStats{}
+	src, dst := limits.meter(context.Background(), stats, nopCloser{bytes.NewBufferString("ping")}, nopCloser{bytes.NewBufferString("pong-pong")})
+
+	_, err := io.ReadAll(src)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), stats.Snapshot().BytesSent)
+
+	start := time.Now()
+	_, err = io.ReadAll(dst)
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), stats.Snapshot().BytesReceived)
+	// 9 bytes fit into the burst of 100 bytes per second
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestTunnelMarshalJSONTraffic(t *testing.T) {
+	tcp := newTunnelTCP(nil, nil, models.Remote{}, nil, nil, nil)
+	tcp.connStats.AddBytes(10, 20)
+	tunnel := &Tunnel{ID: "1", TunnelProtocol: tcp}
+
+	b, err := json.Marshal(tunnel)
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"bytes_sent":10,"bytes_received":20`)
+
+	stored := &Tunnel{}
+	require.NoError(t, json.Unmarshal(b, stored))
+	sent, received := stored.Traffic()
+	assert.Equal(t, int64(10), sent)
+	assert.Equal(t, int64(20), received)
+}
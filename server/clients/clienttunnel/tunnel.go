@@ -2,6 +2,7 @@ package clienttunnel
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"golang.org/x/crypto/ssh"
@@ -9,14 +10,17 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
+	chshare "github.com/cloudradar-monitoring/rport/share"
 	"github.com/cloudradar-monitoring/rport/share/logger"
 	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/random"
 )
 
 type TunnelProtocol interface {
 	Start(ctx context.Context) error
 	Terminate(force bool) error
 	LastActive() time.Time
+	Stats() chshare.ConnStatsSnapshot
 }
 
 type MultiTunnel struct {
@@ -55,6 +59,14 @@ func (mt *MultiTunnel) LastActive() time.Time {
 	return result
 }
 
+func (mt *MultiTunnel) Stats() chshare.ConnStatsSnapshot {
+	var result chshare.ConnStatsSnapshot
+	for _, tp := range mt.Protocols {
+		result = result.Add(tp.Stats())
+	}
+	return result
+}
+
 // TODO(m-terel): Refactor to use separate models for representation and business logic.
 // Tunnel represents active remote proxy connection
 type Tunnel struct {
@@ -64,32 +76,72 @@ type Tunnel struct {
 	TunnelProtocol `json:"-"`
 	Proxy          *TunnelProxy `json:"-"`
 	CreatedAt      time.Time    `json:"created_at"`
+	// BytesSent and BytesReceived are only read from the db, for running tunnels they are taken from the stats
+	BytesSent     int64 `json:"bytes_sent"`
+	BytesReceived int64 `json:"bytes_received"`
+	// SessionID is unique across all tunnels, contrary to ID it isn't reused by other tunnels of the client
+	SessionID string `json:"-"`
+	// OnConnectionClosed is called after a connection of the tunnel was closed, it has to be set before the tunnel is started
+	OnConnectionClosed func(t *Tunnel, conn Connection) `json:"-"`
 }
 
-func NewTunnel(logger *logger.Logger, ssh ssh.Conn, id string, remote models.Remote, acl *TunnelACL) (*Tunnel, error) {
+// NewTunnel returns a tunnel for the given remote. The rate limits of the remote are applied in addition to clientLimits
+// which are shared by all tunnels of a client.
+func NewTunnel(logger *logger.Logger, ssh ssh.Conn, id string, remote models.Remote, acl *TunnelACL, clientLimits *RateLimits) (*Tunnel, error) {
 	logger = logger.Fork("tunnel#%s:%s", id, remote)
+	limits := rateLimits{NewRateLimits(remote.RateLimitUp, remote.RateLimitDown), clientLimits}
+
+	sessionID, err := random.UUID4()
+	if err != nil {
+		return nil, err
+	}
+	t := &Tunnel{
+		Remote:    remote,
+		ID:        id,
+		SessionID: sessionID,
+		CreatedAt: time.Now(),
+	}
 
-	var tunnelProtocol TunnelProtocol
 	switch remote.Protocol {
 	case models.ProtocolUDP:
-		tunnelProtocol = newTunnelUDP(logger, ssh, remote, acl)
+		t.TunnelProtocol = newTunnelUDP(logger, ssh, remote, acl, limits, t.connectionClosed)
 	case models.ProtocolTCP:
-		tunnelProtocol = newTunnelTCP(logger, ssh, remote, acl)
+		t.TunnelProtocol = newTunnelTCP(logger, ssh, remote, acl, limits, t.connectionClosed)
+	case models.ProtocolSOCKS5:
+		t.TunnelProtocol = newTunnelSOCKS5(logger, ssh, remote, acl, limits, t.connectionClosed)
 	case models.ProtocolTCPUDP:
-		tunnelProtocol = &MultiTunnel{
+		t.TunnelProtocol = &MultiTunnel{
 			Protocols: []TunnelProtocol{
-				newTunnelTCP(logger, ssh, remote, acl),
-				newTunnelUDP(logger, ssh, remote, acl),
+				newTunnelTCP(logger, ssh, remote, acl, limits, t.connectionClosed),
+				newTunnelUDP(logger, ssh, remote, acl, limits, t.connectionClosed),
 			},
 		}
 	default:
 		return nil, errors.Errorf("unsupported protocol %q", remote.Protocol)
 	}
 
-	return &Tunnel{
-		Remote:         remote,
-		ID:             id,
-		TunnelProtocol: tunnelProtocol,
-		CreatedAt:      time.Now(),
-	}, nil
+	return t, nil
+}
+
+func (t *Tunnel) connectionClosed(conn Connection) {
+	if t.OnConnectionClosed != nil {
+		t.OnConnectionClosed(t, conn)
+	}
+}
+
+// Traffic returns the number of bytes sent to and received from the remote since the tunnel was started
+func (t *Tunnel) Traffic() (sent int64, received int64) {
+	if t.TunnelProtocol == nil {
+		return t.BytesSent, t.BytesReceived
+	}
+	stats := t.Stats()
+	return stats.BytesSent, stats.BytesReceived
+}
+
+// MarshalJSON adds the current byte counters of the tunnel
+func (t *Tunnel) MarshalJSON() ([]byte, error) {
+	type tunnel Tunnel
+	result := tunnel(*t)
+	result.BytesSent, result.BytesReceived = t.Traffic()
+	return json.Marshal(result)
 }
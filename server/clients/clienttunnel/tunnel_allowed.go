@@ -8,10 +8,8 @@ import (
 	"github.com/cloudradar-monitoring/rport/share/comm"
 )
 
-func IsAllowed(remote string, conn ssh.Conn) (bool, error) {
-	req := &comm.CheckTunnelAllowedRequest{
-		Remote: remote,
-	}
+// IsAllowed asks the client whether a tunnel to the given remote is allowed by its config for the user in req
+func IsAllowed(req *comm.CheckTunnelAllowedRequest, conn ssh.Conn) (bool, error) {
 	resp := &comm.CheckTunnelAllowedResponse{}
 	err := comm.SendRequestAndGetResponse(conn, comm.RequestTypeCheckTunnelAllowed, req, resp)
 	if err != nil {
@@ -8,12 +8,14 @@ import (
 	"errors"
 	"fmt"
 	"html/template"
+	"io"
 	"net"
 	"net/http"
 	"time"
 
 	"github.com/gorilla/mux"
 
+	"github.com/cloudradar-monitoring/rport/server/recordings"
 	chshare "github.com/cloudradar-monitoring/rport/share"
 	"github.com/cloudradar-monitoring/rport/share/logger"
 	"github.com/cloudradar-monitoring/rport/share/security"
@@ -28,6 +30,8 @@ type TunnelProxyConfig struct {
 	NovncRoot    string `mapstructure:"novnc_root"`
 	GuacdAddress string `mapstructure:"guacd_address"`
 	Enabled      bool
+	// Shares is set by the server if tunnel sharing is enabled
+	Shares TunnelShares `mapstructure:"-"`
 }
 
 func (c *TunnelProxyConfig) ParseAndValidate() error {
@@ -73,9 +77,12 @@ func (c *TunnelProxyConfig) validateGuacd(addr string) error {
 }
 
 type TunnelProxy struct {
-	Tunnel               *Tunnel
-	Logger               *logger.Logger
-	Config               *TunnelProxyConfig
+	Tunnel   *Tunnel
+	ClientID string
+	Logger   *logger.Logger
+	Config   *TunnelProxyConfig
+	// Recordings is nil if session recording is disabled
+	Recordings           *recordings.Service
 	Host                 string
 	Port                 string
 	TunnelHost           string
@@ -85,10 +92,12 @@ type TunnelProxy struct {
 	tunnelProxyConnector TunnelProxyConnector
 }
 
-func NewTunnelProxy(tunnel *Tunnel, logger *logger.Logger, config *TunnelProxyConfig, host string, port string, acl *TunnelACL) *TunnelProxy {
+func NewTunnelProxy(tunnel *Tunnel, clientID string, logger *logger.Logger, config *TunnelProxyConfig, recordingsService *recordings.Service, host string, port string, acl *TunnelACL) *TunnelProxy {
 	tp := &TunnelProxy{
 		Tunnel:     tunnel,
+		ClientID:   clientID,
 		Config:     config,
+		Recordings: recordingsService,
 		Host:       host,
 		Port:       port,
 		TunnelHost: tunnel.Remote.LocalHost,
@@ -103,6 +112,9 @@ func NewTunnelProxy(tunnel *Tunnel, logger *logger.Logger, config *TunnelProxyCo
 func (tp *TunnelProxy) Start(ctx context.Context) error {
 	router := mux.NewRouter()
 	router.Use(tp.handleACL)
+	if tp.Config.Shares != nil {
+		router.Use(tp.handleShare)
+	}
 
 	router.Handle("/css/tunnel-proxy.css", http.FileServer(http.FS(tunnelProxyCSS)))
 
@@ -138,9 +150,30 @@ func (tp *TunnelProxy) Stop(ctx context.Context) error {
 		tp.Logger.Infof("tunnel proxy shutdown failed:%+s", err)
 	}
 
+	if closer, ok := tp.tunnelProxyConnector.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			tp.Logger.Errorf("failed to close tunnel proxy connector: %v", err)
+		}
+	}
+
 	return nil
 }
 
+// startRecording returns nil if session recording is disabled
+func (tp *TunnelProxy) startRecording(recType recordings.Type) (*recordings.Session, error) {
+	if tp.Recordings == nil {
+		return nil, nil
+	}
+
+	session, err := tp.Recordings.Start(context.Background(), recType, tp.ClientID, tp.Tunnel.ID, tp.Tunnel.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session recording: %v", err)
+	}
+	tp.Logger.Infof("recording %s session to %s", recType, session.ID())
+
+	return session, nil
+}
+
 func (tp *TunnelProxy) Addr() string {
 	return net.JoinHostPort(tp.Host, tp.Port)
 }
@@ -1,18 +1,31 @@
 package clienttunnel
 
 import (
+	"bufio"
 	"crypto/tls"
+	"errors"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"sync"
+	"time"
 
 	"github.com/gorilla/mux"
+
+	"github.com/cloudradar-monitoring/rport/server/recordings"
+	chshare "github.com/cloudradar-monitoring/rport/share"
 )
 
 // TunnelProxyConnectorHTTP uses the standard ReverseProxy from package httputil to connect to HTTP/HTTPS server on tunnel endpoint
 type TunnelProxyConnectorHTTP struct {
 	tunnelProxy  *TunnelProxy
 	reverseProxy *httputil.ReverseProxy
+
+	// all requests of a tunnel are recorded to a single recording, it's started with the first request
+	recordingMu sync.Mutex
+	recording   *recordings.Session
+	httpLog     *recordings.HTTPLogWriter
 }
 
 func NewTunnelConnectorHTTP(tp *TunnelProxy) *TunnelProxyConnectorHTTP {
@@ -22,6 +35,10 @@ func NewTunnelConnectorHTTP(tp *TunnelProxy) *TunnelProxyConnectorHTTP {
 func (tc *TunnelProxyConnectorHTTP) InitRouter(router *mux.Router) *mux.Router {
 	router.PathPrefix("/").HandlerFunc(tc.serveHTTP)
 
+	if tc.tunnelProxy.Recordings != nil {
+		router.Use(tc.recordRequests)
+	}
+
 	if tc.tunnelProxy.Tunnel.Remote.HostHeader != "" {
 		router.Use(tc.addHostHeader)
 	}
@@ -49,7 +66,8 @@ func (tc *TunnelProxyConnectorHTTP) createReverseProxy() {
 }
 
 func (tc *TunnelProxyConnectorHTTP) serveHTTP(w http.ResponseWriter, r *http.Request) {
-	if tc.tunnelProxy.Tunnel.Remote.AuthUser != "" && tc.tunnelProxy.Tunnel.Remote.AuthPassword != "" {
+	// users of a share link access the tunnel without its credentials
+	if tc.tunnelProxy.Tunnel.Remote.AuthUser != "" && tc.tunnelProxy.Tunnel.Remote.AuthPassword != "" && !hasShareAccess(r.Context()) {
 		user, password, ok := r.BasicAuth()
 		if !ok || user != tc.tunnelProxy.Tunnel.Remote.AuthUser || password != tc.tunnelProxy.Tunnel.Remote.AuthPassword {
 			w.Header().Set("WWW-Authenticate", `Basic realm="restricted", charset="UTF-8"`)
@@ -68,3 +86,92 @@ func (tc *TunnelProxyConnectorHTTP) addHostHeader(next http.Handler) http.Handle
 		next.ServeHTTP(w, r)
 	})
 }
+
+func (tc *TunnelProxyConnectorHTTP) recordRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpLog, err := tc.getHTTPLog()
+		if err != nil {
+			tc.tunnelProxy.handleProxyError(w, r, err)
+			return
+		}
+
+		rw := &recordingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		started := time.Now()
+		next.ServeHTTP(rw, r)
+
+		err = httpLog.Log(&recordings.HTTPLogEntry{
+			Time:       started.UTC(),
+			RemoteAddr: chshare.RemoteIP(r),
+			Method:     r.Method,
+			URL:        r.URL.String(),
+			Status:     rw.status,
+			Bytes:      rw.bytes,
+			Duration:   time.Since(started).Seconds(),
+		})
+		if err != nil {
+			tc.tunnelProxy.Logger.Errorf("failed to record request: %v", err)
+		}
+	})
+}
+
+func (tc *TunnelProxyConnectorHTTP) getHTTPLog() (*recordings.HTTPLogWriter, error) {
+	tc.recordingMu.Lock()
+	defer tc.recordingMu.Unlock()
+
+	if tc.httpLog != nil {
+		return tc.httpLog, nil
+	}
+
+	recording, err := tc.tunnelProxy.startRecording(recordings.TypeHTTP)
+	if err != nil {
+		return nil, err
+	}
+	tc.recording = recording
+	tc.httpLog = recordings.NewHTTPLogWriter(recording)
+
+	return tc.httpLog, nil
+}
+
+// Close finishes the recording when the tunnel proxy is stopped
+func (tc *TunnelProxyConnectorHTTP) Close() error {
+	tc.recordingMu.Lock()
+	defer tc.recordingMu.Unlock()
+
+	if tc.recording == nil {
+		return nil
+	}
+	return tc.recording.Close()
+}
+
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *recordingResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingResponseWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *recordingResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack is needed to proxy websocket connections
+func (w *recordingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	w.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/wwt/guac"
+
+	"github.com/cloudradar-monitoring/rport/server/recordings"
 )
 
 const (
@@ -188,7 +190,19 @@ func (tc *TunnelProxyConnectorRDP) connectToGuacamole(r *http.Request) (guac.Tun
 		return nil, err
 	}
 	tc.tunnelProxy.Logger.Debugf("Socket configured")
-	return guac.NewSimpleTunnel(stream), nil
+	tunnel := guac.NewSimpleTunnel(stream)
+
+	recording, err := tc.tunnelProxy.startRecording(recordings.TypeRDP)
+	if err != nil {
+		tc.tunnelProxy.Logger.Errorf("%v", err)
+		_ = tunnel.Close()
+		return nil, err
+	}
+	if recording != nil {
+		return newRecordingGuacTunnel(tunnel, recording, tc.tunnelProxy.Logger), nil
+	}
+
+	return tunnel, nil
 }
 
 // handleFormValues middleware to handle parsing form values
@@ -2,6 +2,7 @@ package clienttunnel
 
 import (
 	_ "embed" //to embed novnc wrapper templates
+	"fmt"
 	"net"
 	"net/http"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/gorilla/websocket"
 
 	"github.com/cloudradar-monitoring/rport/server/api/middleware"
+	"github.com/cloudradar-monitoring/rport/server/recordings"
 )
 
 //go:embed novnc/index.html
@@ -82,8 +84,15 @@ func (tc *TunnelProxyConnectorVNC) serveVNC(w http.ResponseWriter, r *http.Reque
 			p := new(WebsocketTCPProxy)
 			p.Initialize(wsConn, tcpAddr, tc.tunnelProxy.Logger)
 
-			if err = p.Dial(); err != nil {
-				tc.tunnelProxy.Logger.Errorf("failed to dial tcp addr: %v", err)
+			if err = tc.record(p); err != nil {
+				tc.tunnelProxy.Logger.Errorf("%v", err)
+			}
+
+			if err == nil {
+				if err = p.Dial(); err != nil {
+					tc.tunnelProxy.Logger.Errorf("failed to dial tcp addr: %v", err)
+					p.StopRecording()
+				}
 			}
 
 			if err == nil {
@@ -94,3 +103,16 @@ func (tc *TunnelProxyConnectorVNC) serveVNC(w http.ResponseWriter, r *http.Reque
 	}
 	_ = wsConn.WriteMessage(websocket.CloseMessage, []byte("could not start websocket tcp proxy"))
 }
+
+func (tc *TunnelProxyConnectorVNC) record(p *WebsocketTCPProxy) error {
+	recording, err := tc.tunnelProxy.startRecording(recordings.TypeVNC)
+	if err != nil || recording == nil {
+		return err
+	}
+
+	if err := p.Record(recording); err != nil {
+		_ = recording.Close()
+		return fmt.Errorf("failed to start session recording: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package clienttunnel
+
+import (
+	"context"
+	_ "embed" //to embed html templates
+	"errors"
+	"net/http"
+	"strings"
+
+	chshare "github.com/cloudradar-monitoring/rport/share"
+)
+
+const (
+	// ShareTokenParam is the query parameter of a share link containing the token
+	ShareTokenParam = "rport-share"
+	shareCookieName = "rport_share"
+)
+
+var (
+	// ErrShareOTPRequired is returned by TunnelShares.Redeem if the share is bound to an email and a one-time password was sent
+	ErrShareOTPRequired = errors.New("one-time password required")
+	// ErrShareInvalidOTP is returned by TunnelShares.Redeem if the given one-time password is wrong or expired
+	ErrShareInvalidOTP = errors.New("invalid one-time password")
+)
+
+//go:embed share/otp.html
+var shareOTPHTML string
+
+// TunnelShares grants external users access to tunnels via share links
+type TunnelShares interface {
+	// Redeem validates the token of a share link for the tunnel with the given session id and returns
+	// an access key for the following requests
+	Redeem(ctx context.Context, token, tunnelSessionID, remoteIP, otp string) (accessKey string, err error)
+	// Authorize returns whether an access key grants access to the tunnel with the given session id
+	Authorize(ctx context.Context, accessKey, tunnelSessionID, remoteIP string) bool
+}
+
+type shareAccessKey struct{}
+
+// hasShareAccess returns whether the request was authorized by a share link
+func hasShareAccess(ctx context.Context) bool {
+	ok, _ := ctx.Value(shareAccessKey{}).(bool)
+	return ok
+}
+
+// handleShare middleware redeems share links and authorizes the requests of users who redeemed one.
+// Requests without a share link are passed on unchanged.
+func (tp *TunnelProxy) handleShare(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the ip a link is bound to is checked against the peer address, X-Forwarded-For can be set by anyone
+		remoteIP := chshare.PeerIP(r)
+
+		if token := r.URL.Query().Get(ShareTokenParam); token != "" {
+			accessKey, err := tp.Config.Shares.Redeem(r.Context(), token, tp.Tunnel.SessionID, remoteIP, r.PostFormValue("otp"))
+			switch {
+			case errors.Is(err, ErrShareOTPRequired):
+				tp.serveTemplate(w, r, shareOTPHTML, map[string]interface{}{})
+				return
+			case errors.Is(err, ErrShareInvalidOTP):
+				tp.serveTemplate(w, r, shareOTPHTML, map[string]interface{}{"error": "The access code is invalid or expired."})
+				return
+			case err != nil:
+				tp.Logger.Infof("Share link rejected. Remote addr: %s: %v", remoteIP, err)
+				tp.sendHTML(w, http.StatusForbidden, "Share link is invalid or expired")
+				return
+			}
+
+			http.SetCookie(w, &http.Cookie{
+				Name:     shareCookieName,
+				Value:    accessKey,
+				Path:     "/",
+				Secure:   true,
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+			// remove the token from the url, so it isn't kept in the browser history or passed to the tunnel
+			u := *r.URL
+			q := u.Query()
+			q.Del(ShareTokenParam)
+			u.RawQuery = q.Encode()
+			http.Redirect(w, r, u.RequestURI(), http.StatusSeeOther)
+			return
+		}
+
+		cookie, err := r.Cookie(shareCookieName)
+		if err == nil && tp.Config.Shares.Authorize(r.Context(), cookie.Value, tp.Tunnel.SessionID, remoteIP) {
+			removeCookie(r, shareCookieName)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), shareAccessKey{}, true)))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// removeCookie removes a cookie from the request, so it isn't passed to the tunnel
+func removeCookie(r *http.Request, name string) {
+	var cookies []string
+	for _, c := range r.Cookies() {
+		if c.Name != name {
+			cookies = append(cookies, c.String())
+		}
+	}
+	r.Header.Del("Cookie")
+	if len(cookies) > 0 {
+		r.Header.Set("Cookie", strings.Join(cookies, "; "))
+	}
+}
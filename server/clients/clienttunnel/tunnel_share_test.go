@@ -0,0 +1,129 @@
+package clienttunnel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+type tunnelSharesMock struct {
+	redeemErr error
+	remoteIP  string
+}
+
+func (m *tunnelSharesMock) Redeem(ctx context.Context, token, tunnelSessionID, remoteIP, otp string) (string, error) {
+	m.remoteIP = remoteIP
+	if m.redeemErr != nil {
+		return "", m.redeemErr
+	}
+	return "key-" + token, nil
+}
+
+func (m *tunnelSharesMock) Authorize(ctx context.Context, accessKey, tunnelSessionID, remoteIP string) bool {
+	m.remoteIP = remoteIP
+	return accessKey == "key-valid" && tunnelSessionID == "session-1"
+}
+
+func TestHandleShare(t *testing.T) {
+	testCases := []struct {
+		name           string
+		url            string
+		cookie         string
+		redeemErr      error
+		wantStatus     int
+		wantLocation   string
+		wantCookie     string
+		wantNext       bool
+		wantAccess     bool
+		wantNextCookie string
+	}{
+		{
+			name:         "redeem share link",
+			url:          "/path?a=1&rport-share=valid",
+			wantStatus:   http.StatusSeeOther,
+			wantLocation: "/path?a=1",
+			wantCookie:   "key-valid",
+		},
+		{
+			name:       "invalid share link",
+			url:        "/?rport-share=invalid",
+			redeemErr:  assert.AnError,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "otp required",
+			url:        "/?rport-share=valid",
+			redeemErr:  ErrShareOTPRequired,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:           "authorized by cookie",
+			url:            "/",
+			cookie:         "key-valid",
+			wantStatus:     http.StatusOK,
+			wantNext:       true,
+			wantAccess:     true,
+			wantNextCookie: "other=1",
+		},
+		{
+			name:           "invalid cookie",
+			url:            "/",
+			cookie:         "key-invalid",
+			wantStatus:     http.StatusOK,
+			wantNext:       true,
+			wantNextCookie: "other=1; rport_share=key-invalid",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			shares := &tunnelSharesMock{redeemErr: tc.redeemErr}
+			tp := &TunnelProxy{
+				Tunnel: &Tunnel{SessionID: "session-1"},
+				Logger: logger.NewLogger("tunnel-proxy", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug),
+				Config: &TunnelProxyConfig{Shares: shares},
+			}
+			var nextCalled, nextAccess bool
+			var nextCookie string
+			handler := tp.handleShare(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nextCalled = true
+				nextAccess = hasShareAccess(r.Context())
+				nextCookie = r.Header.Get("Cookie")
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			req.RemoteAddr = "192.0.2.10:1234"
+			req.Header.Set("X-Forwarded-For", "198.51.100.1")
+			req.AddCookie(&http.Cookie{Name: "other", Value: "1"})
+			if tc.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: shareCookieName, Value: tc.cookie})
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.wantStatus, w.Code)
+			assert.Equal(t, tc.wantLocation, w.Header().Get("Location"))
+			if tc.wantCookie != "" {
+				cookies := w.Result().Cookies()
+				require.Len(t, cookies, 1)
+				assert.Equal(t, tc.wantCookie, cookies[0].Value)
+				assert.True(t, cookies[0].HttpOnly)
+				assert.True(t, cookies[0].Secure)
+			}
+			if tc.cookie != "" || tc.wantCookie != "" {
+				assert.Equal(t, "192.0.2.10", shares.remoteIP)
+			}
+			assert.Equal(t, tc.wantNext, nextCalled)
+			assert.Equal(t, tc.wantAccess, nextAccess)
+			assert.Equal(t, tc.wantNextCookie, nextCookie)
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package clienttunnel
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+// SOCKS5 protocol values, see RFC 1928 and RFC 1929
+const (
+	socks5Version     = 0x05
+	socks5AuthVersion = 0x01
+
+	socks5MethodNoAuth       = 0x00
+	socks5MethodUserPassword = 0x02
+	socks5MethodNoAcceptable = 0xff
+
+	socks5CmdConnect = 0x01
+
+	socks5AddrIPv4   = 0x01
+	socks5AddrDomain = 0x03
+	socks5AddrIPv6   = 0x04
+
+	socks5ReplySucceeded        = 0x00
+	socks5ReplyGeneralFailure   = 0x01
+	socks5ReplyNotAllowed       = 0x02
+	socks5ReplyCmdNotSupported  = 0x07
+	socks5ReplyAddrNotSupported = 0x08
+
+	socks5AuthSucceeded = 0x00
+	socks5AuthFailed    = 0x01
+)
+
+// socks5HandshakeTimeout limits the time a SOCKS5 client has to authenticate and send its request
+var socks5HandshakeTimeout = 30 * time.Second
+
+// newTunnelSOCKS5 returns a tcp tunnel acting as a SOCKS5 proxy. The destination of each connection is requested by
+// the SOCKS5 client with the CONNECT command, it has to be allowed by the "tunnel_allowed" config of the client.
+// If Remote.AuthUser is set, SOCKS5 clients have to authenticate with Remote.AuthUser and Remote.AuthPassword.
+func newTunnelSOCKS5(logger *logger.Logger, ssh ssh.Conn, remote models.Remote, acl *TunnelACL, limits rateLimits, onConnClosed func(Connection)) *tunnelTCP {
+	t := newTunnelTCP(logger, ssh, remote, acl, limits, onConnClosed)
+	t.connect = t.connectSOCKS5
+	return t
+}
+
+func (t *tunnelTCP) connectSOCKS5(l *logger.Logger, src io.ReadWriteCloser) (io.ReadWriteCloser, error) {
+	if conn, ok := src.(net.Conn); ok {
+		_ = conn.SetDeadline(time.Now().Add(socks5HandshakeTimeout))
+		defer func() {
+			_ = conn.SetDeadline(time.Time{})
+		}()
+	}
+
+	if err := t.authenticateSOCKS5(src); err != nil {
+		return nil, err
+	}
+
+	dest, reply, err := readSOCKS5Request(src)
+	if err != nil {
+		_ = writeSOCKS5Reply(src, reply)
+		return nil, err
+	}
+
+	allowed, err := IsAllowed(&comm.CheckTunnelAllowedRequest{
+		Remote:     dest,
+		User:       t.Owner,
+		UserGroups: t.OwnerGroups,
+	}, t.sshConn)
+	if err != nil {
+		_ = writeSOCKS5Reply(src, socks5ReplyGeneralFailure)
+		return nil, err
+	}
+	if !allowed {
+		_ = writeSOCKS5Reply(src, socks5ReplyNotAllowed)
+		return nil, fmt.Errorf("destination %s is not allowed by client configuration", dest)
+	}
+
+	dst, err := openRemoteChannel(t.sshConn, dest)
+	if err != nil {
+		reply = socks5ReplyGeneralFailure
+		var openErr *ssh.OpenChannelError
+		if errors.As(err, &openErr) && openErr.Reason == ssh.Prohibited {
+			reply = socks5ReplyNotAllowed
+		}
+		_ = writeSOCKS5Reply(src, reply)
+		return nil, err
+	}
+
+	if err := writeSOCKS5Reply(src, socks5ReplySucceeded); err != nil {
+		dst.Close()
+		return nil, err
+	}
+
+	l.Debugf("Connected to %s", dest)
+	return dst, nil
+}
+
+// authenticateSOCKS5 negotiates the authentication method and checks the credentials if the tunnel requires them
+func (t *tunnelTCP) authenticateSOCKS5(rw io.ReadWriter) error {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(rw, header); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 greeting: %v", err)
+	}
+	if header[0] != socks5Version {
+		return fmt.Errorf("unsupported SOCKS version %d", header[0])
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(rw, methods); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 authentication methods: %v", err)
+	}
+
+	method := byte(socks5MethodNoAuth)
+	if t.AuthUser != "" {
+		method = socks5MethodUserPassword
+	}
+	if !bytes.Contains(methods, []byte{method}) {
+		_, _ = rw.Write([]byte{socks5Version, socks5MethodNoAcceptable})
+		return errors.New("no acceptable SOCKS5 authentication method")
+	}
+	if _, err := rw.Write([]byte{socks5Version, method}); err != nil {
+		return err
+	}
+	if method == socks5MethodNoAuth {
+		return nil
+	}
+
+	version := make([]byte, 1)
+	if _, err := io.ReadFull(rw, version); err != nil {
+		return fmt.Errorf("failed to read SOCKS5 credentials: %v", err)
+	}
+	if version[0] != socks5AuthVersion {
+		return fmt.Errorf("unsupported SOCKS5 authentication version %d", version[0])
+	}
+	user, err := readSOCKS5String(rw)
+	if err != nil {
+		return fmt.Errorf("failed to read SOCKS5 credentials: %v", err)
+	}
+	password, err := readSOCKS5String(rw)
+	if err != nil {
+		return fmt.Errorf("failed to read SOCKS5 credentials: %v", err)
+	}
+
+	userOK := subtle.ConstantTimeCompare(user, []byte(t.AuthUser)) == 1
+	passwordOK := subtle.ConstantTimeCompare(password, []byte(t.AuthPassword)) == 1
+	if !userOK || !passwordOK {
+		_, _ = rw.Write([]byte{socks5AuthVersion, socks5AuthFailed})
+		return errors.New("invalid SOCKS5 credentials")
+	}
+
+	_, err = rw.Write([]byte{socks5AuthVersion, socks5AuthSucceeded})
+	return err
+}
+
+// readSOCKS5Request returns the destination of a CONNECT request, on failure it returns the reply to send as well
+func readSOCKS5Request(r io.Reader) (string, byte, error) {
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return "", socks5ReplyGeneralFailure, fmt.Errorf("failed to read SOCKS5 request: %v", err)
+	}
+	if header[0] != socks5Version {
+		return "", socks5ReplyGeneralFailure, fmt.Errorf("unsupported SOCKS version %d", header[0])
+	}
+	if header[1] != socks5CmdConnect {
+		return "", socks5ReplyCmdNotSupported, fmt.Errorf("unsupported SOCKS5 command %d", header[1])
+	}
+
+	var host string
+	switch header[3] {
+	case socks5AddrIPv4, socks5AddrIPv6:
+		size := net.IPv4len
+		if header[3] == socks5AddrIPv6 {
+			size = net.IPv6len
+		}
+		ip := make([]byte, size)
+		if _, err := io.ReadFull(r, ip); err != nil {
+			return "", socks5ReplyGeneralFailure, fmt.Errorf("failed to read SOCKS5 destination: %v", err)
+		}
+		host = net.IP(ip).String()
+	case socks5AddrDomain:
+		domain, err := readSOCKS5String(r)
+		if err != nil {
+			return "", socks5ReplyGeneralFailure, fmt.Errorf("failed to read SOCKS5 destination: %v", err)
+		}
+		host = string(domain)
+	default:
+		return "", socks5ReplyAddrNotSupported, fmt.Errorf("unsupported SOCKS5 address type %d", header[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(r, port); err != nil {
+		return "", socks5ReplyGeneralFailure, fmt.Errorf("failed to read SOCKS5 destination: %v", err)
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), 0, nil
+}
+
+// readSOCKS5String reads a string prefixed with its length
+func readSOCKS5String(r io.Reader) ([]byte, error) {
+	size := make([]byte, 1)
+	if _, err := io.ReadFull(r, size); err != nil {
+		return nil, err
+	}
+	result := make([]byte, size[0])
+	if _, err := io.ReadFull(r, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// writeSOCKS5Reply writes a reply to a request, the bound address is not known so it's always 0.0.0.0:0
+func writeSOCKS5Reply(w io.Writer, reply byte) error {
+	_, err := w.Write([]byte{socks5Version, reply, 0x00, socks5AddrIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
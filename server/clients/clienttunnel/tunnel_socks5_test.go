@@ -0,0 +1,172 @@
+package clienttunnel
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/net/proxy"
+
+	"github.com/cloudradar-monitoring/rport/share/comm"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+// socks5ConnMock answers the tunnel allowed requests and echoes the data sent to opened channels
+type socks5ConnMock struct {
+	ssh.Conn
+	allowed bool
+
+	mu       sync.Mutex
+	checked  []comm.CheckTunnelAllowedRequest
+	channels []string
+}
+
+func (c *socks5ConnMock) SendRequest(name string, wantReply bool, payload []byte) (bool, []byte, error) {
+	req := comm.CheckTunnelAllowedRequest{}
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return false, nil, err
+	}
+	c.mu.Lock()
+	c.checked = append(c.checked, req)
+	c.mu.Unlock()
+
+	resp, err := json.Marshal(comm.CheckTunnelAllowedResponse{IsAllowed: c.allowed})
+	return true, resp, err
+}
+
+func (c *socks5ConnMock) OpenChannel(name string, data []byte) (ssh.Channel, <-chan *ssh.Request, error) {
+	c.mu.Lock()
+	c.channels = append(c.channels, string(data))
+	c.mu.Unlock()
+
+	local, remote := net.Pipe()
+	go func() {
+		_, _ = io.Copy(remote, remote)
+		remote.Close()
+	}()
+	reqs := make(chan *ssh.Request)
+	close(reqs)
+	return &pipeChannel{conn: local}, reqs, nil
+}
+
+type pipeChannel struct {
+	ssh.Channel
+	conn net.Conn
+}
+
+func (c *pipeChannel) Read(p []byte) (int, error) {
+	return c.conn.Read(p)
+}
+
+func (c *pipeChannel) Write(p []byte) (int, error) {
+	return c.conn.Write(p)
+}
+
+func (c *pipeChannel) Close() error {
+	return c.conn.Close()
+}
+
+func startTestSOCKS5Tunnel(t *testing.T, conn ssh.Conn, remote models.Remote) string {
+	remote.LocalHost = "127.0.0.1"
+	remote.LocalPort = "0"
+	log := logger.NewLogger("socks5-test", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug)
+	tunnel := newTunnelSOCKS5(log, conn, remote, nil, nil, nil)
+
+	l, err := net.Listen("tcp4", remote.Local())
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	tunnel.stopFn = cancel
+	tunnel.wg.Add(1)
+	go tunnel.listen(ctx, l)
+	t.Cleanup(func() {
+		_ = tunnel.Terminate(true)
+	})
+
+	return l.Addr().String()
+}
+
+func TestTunnelSOCKS5(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Allowed       bool
+		Remote        models.Remote
+		Auth          *proxy.Auth
+		ExpectedError string
+		ExpectedOpen  []string
+	}{
+		{
+			Name:         "no auth",
+			Allowed:      true,
+			ExpectedOpen: []string{"example.com:80"},
+		},
+		{
+			Name:         "valid credentials",
+			Allowed:      true,
+			Remote:       models.Remote{AuthUser: "user", AuthPassword: "secret"},
+			Auth:         &proxy.Auth{User: "user", Password: "secret"},
+			ExpectedOpen: []string{"example.com:80"},
+		},
+		{
+			Name:          "invalid credentials",
+			Allowed:       true,
+			Remote:        models.Remote{AuthUser: "user", AuthPassword: "secret"},
+			Auth:          &proxy.Auth{User: "user", Password: "wrong"},
+			ExpectedError: "username/password authentication failed",
+		},
+		{
+			Name:          "missing credentials",
+			Allowed:       true,
+			Remote:        models.Remote{AuthUser: "user", AuthPassword: "secret"},
+			ExpectedError: "no acceptable authentication methods",
+		},
+		{
+			Name:          "destination not allowed",
+			Allowed:       false,
+			ExpectedError: "connection not allowed by ruleset",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			conn := &socks5ConnMock{allowed: tc.Allowed}
+			tc.Remote.Protocol = models.ProtocolSOCKS5
+			tc.Remote.Owner = "admin"
+			tc.Remote.OwnerGroups = []string{"ops"}
+			addr := startTestSOCKS5Tunnel(t, conn, tc.Remote)
+
+			dialer, err := proxy.SOCKS5("tcp", addr, tc.Auth, proxy.Direct)
+			require.NoError(t, err)
+			c, err := dialer.Dial("tcp", "example.com:80")
+			if tc.ExpectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.ExpectedError)
+			} else {
+				require.NoError(t, err)
+				defer c.Close()
+
+				_, err = c.Write([]byte("ping"))
+				require.NoError(t, err)
+				buf := make([]byte, 4)
+				_, err = io.ReadFull(c, buf)
+				require.NoError(t, err)
+				assert.Equal(t, "ping", string(buf))
+			}
+
+			conn.mu.Lock()
+			defer conn.mu.Unlock()
+			assert.Equal(t, tc.ExpectedOpen, conn.channels)
+			if len(conn.checked) > 0 {
+				assert.Equal(t, comm.CheckTunnelAllowedRequest{Remote: "example.com:80", User: "admin", UserGroups: []string{"ops"}}, conn.checked[0])
+			}
+		})
+	}
+}
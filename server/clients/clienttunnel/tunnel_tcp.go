@@ -24,20 +24,30 @@ type tunnelTCP struct {
 	models.Remote
 	sshConn ssh.Conn
 	acl     *TunnelACL // parsed Remote.ACL field
+	limits  rateLimits
+	// onConnClosed is called after a connection was closed, it can be nil
+	onConnClosed func(Connection)
+	// connect opens the channel to the remote for an accepted connection
+	connect func(l *logger.Logger, src io.ReadWriteCloser) (io.ReadWriteCloser, error)
 
 	stopFn                    func()
 	connectionIDAutoIncrement int
 	connCount                 int32
+	connStats                 chshare.ConnStats
 	wg                        sync.WaitGroup // TODO: verify whether wait group is needed here
 }
 
-func newTunnelTCP(logger *logger.Logger, ssh ssh.Conn, remote models.Remote, acl *TunnelACL) *tunnelTCP {
-	return &tunnelTCP{
-		Logger:  logger,
-		Remote:  remote,
-		sshConn: ssh,
-		acl:     acl,
+func newTunnelTCP(logger *logger.Logger, ssh ssh.Conn, remote models.Remote, acl *TunnelACL, limits rateLimits, onConnClosed func(Connection)) *tunnelTCP {
+	t := &tunnelTCP{
+		Logger:       logger,
+		Remote:       remote,
+		sshConn:      ssh,
+		acl:          acl,
+		limits:       limits,
+		onConnClosed: onConnClosed,
 	}
+	t.connect = t.connectRemote
+	return t
 }
 
 func (t *tunnelTCP) Start(ctx context.Context) error {
@@ -131,6 +141,10 @@ func (t *tunnelTCP) LastActive() time.Time {
 	return time.Unix(atomic.LoadInt64(&t.lastConnClose), 0)
 }
 
+func (t *tunnelTCP) Stats() chshare.ConnStatsSnapshot {
+	return t.connStats.Snapshot()
+}
+
 func (t *tunnelTCP) accept(ctx context.Context, src io.ReadWriteCloser) {
 	defer src.Close()
 	t.connectionIDAutoIncrement++
@@ -158,15 +172,56 @@ func (t *tunnelTCP) accept(ctx context.Context, src io.ReadWriteCloser) {
 		l.Debugf("No remote connection")
 		return
 	}
-	//ssh request for tcp connection for this proxy's remote
-	dst, reqs, err := t.sshConn.OpenChannel("rport", []byte(t.Remote.Remote()))
+	dst, err := t.connect(l, src)
 	if err != nil {
 		l.Errorf("Could not establish TCP tunnel: %v", err)
 		return
 	}
-	go ssh.DiscardRequests(reqs)
 	//then pipe
-	s, r := chshare.Pipe(src, dst)
+	t.connStats.New()
+	t.connStats.Open()
+	startedAt := time.Now()
+	// the bytes are counted while they are transferred, so the stats of long-running connections are up to date
+	s, r := chshare.Pipe(t.limits.meter(ctx, &t.connStats, src, dst))
+	t.connStats.Close()
 	l.Debugf("Close (sent %s received %s)", sizestr.ToString(s), sizestr.ToString(r))
 	close(done)
+
+	if t.onConnClosed != nil {
+		t.onConnClosed(Connection{
+			Protocol:      "tcp",
+			SourceIP:      sourceIP(src),
+			StartedAt:     startedAt,
+			EndedAt:       time.Now(),
+			BytesSent:     s,
+			BytesReceived: r,
+		})
+	}
+}
+
+// sourceIP returns the ip of the peer of a connection accepted by the tunnel
+func sourceIP(conn io.ReadWriteCloser) string {
+	c, ok := conn.(net.Conn)
+	if !ok {
+		return ""
+	}
+	if addr, ok := c.RemoteAddr().(*net.TCPAddr); ok {
+		return addr.IP.String()
+	}
+	return c.RemoteAddr().String()
+}
+
+// connectRemote opens the channel to the fixed remote of the tunnel
+func (t *tunnelTCP) connectRemote(l *logger.Logger, src io.ReadWriteCloser) (io.ReadWriteCloser, error) {
+	return openRemoteChannel(t.sshConn, t.Remote.Remote())
+}
+
+// openRemoteChannel requests the client to open a tcp connection to the given remote
+func openRemoteChannel(sshConn ssh.Conn, remote string) (ssh.Channel, error) {
+	dst, reqs, err := sshConn.OpenChannel("rport", []byte(remote))
+	if err != nil {
+		return nil, err
+	}
+	go ssh.DiscardRequests(reqs)
+	return dst, nil
 }
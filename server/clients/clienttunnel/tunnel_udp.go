@@ -9,6 +9,7 @@ import (
 
 	"golang.org/x/crypto/ssh"
 
+	chshare "github.com/cloudradar-monitoring/rport/share"
 	"github.com/cloudradar-monitoring/rport/share/comm"
 	"github.com/cloudradar-monitoring/rport/share/logger"
 	"github.com/cloudradar-monitoring/rport/share/models"
@@ -21,6 +22,7 @@ type tunnelUDP struct {
 	models.Remote
 	sshConn     ssh.Conn
 	acl         *TunnelACL // parsed Remote.ACL field
+	limits      rateLimits
 	idleTimeout time.Duration
 
 	conn    *net.UDPConn
@@ -30,17 +32,22 @@ type tunnelUDP struct {
 
 	mtx        sync.Mutex
 	lastActive time.Time
+
+	connStats chshare.ConnStats
+	peers     *udpPeers
 }
 
-func newTunnelUDP(logger *logger.Logger, ssh ssh.Conn, remote models.Remote, acl *TunnelACL) *tunnelUDP {
+func newTunnelUDP(logger *logger.Logger, ssh ssh.Conn, remote models.Remote, acl *TunnelACL, limits rateLimits, onConnClosed func(Connection)) *tunnelUDP {
 	return &tunnelUDP{
 		Logger:      logger,
 		Remote:      remote,
 		sshConn:     ssh,
 		acl:         acl,
+		limits:      limits,
 		done:        make(chan struct{}),
 		lastActive:  time.Now(),
 		idleTimeout: time.Duration(remote.IdleTimeoutMinutes) * time.Minute,
+		peers:       newUDPPeers(onConnClosed),
 	}
 }
 
@@ -89,9 +96,11 @@ func (t *tunnelUDP) start(ctx context.Context, sshChan io.ReadWriter) error {
 func (t *tunnelUDP) runInbound(ctx context.Context) error {
 	defer t.conn.Close()
 	defer close(t.done)
+	defer t.peers.closeInactive(time.Time{})
 
 	const maxMTU = 9012
 	buff := make([]byte, maxMTU)
+	lastPeersCheck := time.Now()
 	for {
 		select {
 		case <-ctx.Done():
@@ -99,6 +108,11 @@ func (t *tunnelUDP) runInbound(ctx context.Context) error {
 		default:
 		}
 
+		if time.Since(lastPeersCheck) > udpPeerTimeout {
+			lastPeersCheck = time.Now()
+			t.peers.closeInactive(lastPeersCheck.Add(-udpPeerTimeout))
+		}
+
 		err := t.conn.SetReadDeadline(time.Now().Add(udpReadTimeout))
 		if err != nil {
 			return err
@@ -124,10 +138,16 @@ func (t *tunnelUDP) runInbound(ctx context.Context) error {
 			}
 		}
 
+		if err := t.limits.waitUp(ctx, n); err != nil {
+			return nil
+		}
+
 		err = t.channel.Encode(sourceAddr, buff[:n])
 		if err != nil {
 			return err
 		}
+		t.connStats.AddBytes(int64(n), 0)
+		t.peers.add(sourceAddr, n, 0)
 	}
 }
 
@@ -149,10 +169,16 @@ func (t *tunnelUDP) runOutbound(ctx context.Context) error {
 
 		t.setLastActive()
 
+		if err := t.limits.waitDown(ctx, len(data)); err != nil {
+			return nil
+		}
+
 		_, err = t.conn.WriteToUDP(data, addr)
 		if err != nil {
 			return err
 		}
+		t.connStats.AddBytes(0, int64(len(data)))
+		t.peers.add(addr, 0, len(data))
 	}
 }
 
@@ -170,6 +196,10 @@ func (t *tunnelUDP) LastActive() time.Time {
 	return t.lastActive
 }
 
+func (t *tunnelUDP) Stats() chshare.ConnStatsSnapshot {
+	return t.connStats.Snapshot()
+}
+
 func (t *tunnelUDP) setLastActive() {
 	t.mtx.Lock()
 	defer t.mtx.Unlock()
@@ -20,7 +20,7 @@ func TestTunnelUDP(t *testing.T) {
 	udpReadTimeout = time.Millisecond
 	remote := models.Remote{}
 	logger := logger.NewLogger("udp-handler-test", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug)
-	tunnel := newTunnelUDP(logger, nil, remote, nil)
+	tunnel := newTunnelUDP(logger, nil, remote, nil, nil, nil)
 	serverChannel, clientChannel := test.NewMockChannel()
 	channel := comm.NewUDPChannel(clientChannel)
 	err := tunnel.start(context.Background(), serverChannel)
@@ -50,6 +50,12 @@ func TestTunnelUDP(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.WithinDuration(t, time.Now(), tunnel.LastActive(), 10*time.Millisecond)
+
+	// bytes are counted once the write returned, which might be after the peer already got the data
+	assert.Eventually(t, func() bool {
+		stats := tunnel.Stats()
+		return stats.BytesSent == 3 && stats.BytesReceived == 3
+	}, time.Second, time.Millisecond)
 }
 
 func TestTunnelUDPWithACL(t *testing.T) {
@@ -58,7 +64,7 @@ func TestTunnelUDPWithACL(t *testing.T) {
 	logger := logger.NewLogger("udp-handler-test", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug)
 	acl, err := ParseTunnelACL("127.0.0.2")
 	require.NoError(t, err)
-	tunnel := newTunnelUDP(logger, nil, remote, acl)
+	tunnel := newTunnelUDP(logger, nil, remote, acl, nil, nil)
 	serverChannel, clientChannel := test.NewMockChannel()
 	channel := comm.NewUDPChannel(clientChannel)
 	local1, err := net.ResolveUDPAddr("udp", "127.0.0.1:0")
@@ -3,9 +3,11 @@ package clienttunnel
 import (
 	"net"
 	"net/http"
+	"sync"
 
 	"github.com/gorilla/websocket"
 
+	"github.com/cloudradar-monitoring/rport/server/recordings"
 	"github.com/cloudradar-monitoring/rport/share/logger"
 )
 
@@ -24,6 +26,10 @@ type WebsocketTCPProxy struct {
 	tcpAddr *net.TCPAddr
 	tcpConn *net.TCPConn
 	logger  *logger.Logger
+
+	recording *recordings.Session
+	recorder  *recordings.WebsockifyWriter
+	closeOnce sync.Once
 }
 
 // Initialize WebsocketTCPProxy
@@ -41,6 +47,18 @@ func (p *WebsocketTCPProxy) Start() {
 	go p.ReadTCP()
 }
 
+// Record writes all frames to the given recording, it's closed on teardown
+func (p *WebsocketTCPProxy) Record(recording *recordings.Session) error {
+	recorder, err := recordings.NewWebsockifyWriter(recording)
+	if err != nil {
+		return err
+	}
+
+	p.recording = recording
+	p.recorder = recorder
+	return nil
+}
+
 func (p *WebsocketTCPProxy) Dial() error {
 	tcpConn, err := net.DialTCP(p.tcpAddr.Network(), nil, p.tcpAddr)
 
@@ -66,6 +84,12 @@ func (p *WebsocketTCPProxy) ReadWebSocket() {
 			break
 		}
 
+		if p.recorder != nil {
+			if err := p.recorder.FromClient(data); err != nil {
+				p.logger.Errorf("failed to record websocket frame: %v", err)
+			}
+		}
+
 		_, err = p.tcpConn.Write(data)
 		if err != nil {
 			p.logger.Errorf(" error writing websocket buffer to tcp connection: %v", err)
@@ -86,6 +110,12 @@ func (p *WebsocketTCPProxy) ReadTCP() {
 			break
 		}
 
+		if p.recorder != nil {
+			if err := p.recorder.FromServer(buffer[:bytesRead]); err != nil {
+				p.logger.Errorf("failed to record tcp data: %v", err)
+			}
+		}
+
 		if err := p.wsConn.WriteMessage(websocket.BinaryMessage, buffer[:bytesRead]); err != nil {
 			p.logger.Errorf(" error writing tcp buffer to websocket: %v", err)
 			break
@@ -97,4 +127,21 @@ func (p *WebsocketTCPProxy) ReadTCP() {
 func (p *WebsocketTCPProxy) Teardown() {
 	p.tcpConn.Close()
 	p.wsConn.Close()
+	p.StopRecording()
+}
+
+// StopRecording finishes the recording if any, it's safe to be called multiple times.
+func (p *WebsocketTCPProxy) StopRecording() {
+	if p.recording != nil {
+		p.closeOnce.Do(p.finishRecording)
+	}
+}
+
+func (p *WebsocketTCPProxy) finishRecording() {
+	if err := p.recorder.Finish(); err != nil {
+		p.logger.Errorf("failed to finish recording: %v", err)
+	}
+	if err := p.recording.Close(); err != nil {
+		p.logger.Errorf("failed to close recording: %v", err)
+	}
 }
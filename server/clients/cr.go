@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"golang.org/x/crypto/ssh"
 
 	"github.com/cloudradar-monitoring/rport/server/cgroups"
 	"github.com/cloudradar-monitoring/rport/share/logger"
@@ -27,6 +28,8 @@ type ClientRepository struct {
 type User interface {
 	IsAdmin() bool
 	GetGroups() []string
+	GetClientGroupIDs() []string
+	GetPermissionScope(permission string) (clientGroupIDs []string, limited bool)
 }
 
 // NewClientRepository returns a new thread-safe in-memory cache to store client connections populated with given clients if any.
@@ -54,10 +57,11 @@ func InitClientRepository(
 	ctx context.Context,
 	db *sqlx.DB,
 	keepDisconnectedClients *time.Duration,
+	remoteClientIDs map[string]bool,
 	logger *logger.Logger,
 ) (*ClientRepository, error) {
 	provider := newSqliteProvider(db, keepDisconnectedClients)
-	initClients, err := GetInitState(ctx, provider)
+	initClients, err := GetInitState(ctx, provider, remoteClientIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -97,6 +101,70 @@ func (s *ClientRepository) Delete(client *Client) error {
 	return nil
 }
 
+// Forget removes a client from the cache only, it's used when a client reconnected to another node of the cluster.
+func (s *ClientRepository) Forget(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.clients, id)
+}
+
+// RemoteConnection is the connection of a client connected to another node of the cluster
+type RemoteConnection struct {
+	NodeID string
+	Conn   ssh.Conn
+}
+
+// SyncRemoteClients refreshes the cache with the clients handled by other nodes of the cluster. remoteConns are the
+// connections of the clients connected to other alive nodes by client id. Clients connected to this node are kept.
+func (s *ClientRepository) SyncRemoteClients(ctx context.Context, remoteConns map[string]RemoteConnection) error {
+	if s.provider == nil {
+		return nil
+	}
+
+	stored, err := s.provider.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get clients: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := now()
+	storedIDs := make(map[string]bool, len(stored))
+	for _, client := range stored {
+		storedIDs[client.ID] = true
+
+		cached := s.clients[client.ID]
+		if cached != nil && cached.Node == "" && cached.DisconnectedAt == nil {
+			continue
+		}
+
+		if remote, ok := remoteConns[client.ID]; ok {
+			client.Node = remote.NodeID
+			client.Connection = remote.Conn
+			client.Logger = s.logger
+			client.DisconnectedAt = nil
+		} else if client.DisconnectedAt == nil {
+			// the node the client was connected to is gone, it's expected to reconnect to one of the alive nodes
+			if cached != nil && cached.DisconnectedAt != nil {
+				client.DisconnectedAt = cached.DisconnectedAt
+			} else {
+				client.SetDisconnected(&now)
+			}
+		}
+		s.clients[client.ID] = client
+	}
+
+	// clients deleted by other nodes
+	for id, client := range s.clients {
+		if !storedIDs[id] && (client.Node != "" || client.DisconnectedAt != nil) {
+			delete(s.clients, id)
+		}
+	}
+
+	return nil
+}
+
 func (s *ClientRepository) GetClientsByTag(tags []string, operator string, allowDisconnected bool) (matchingClients []*Client, err error) {
 	var availableClients []*Client
 	if allowDisconnected {
@@ -317,13 +385,12 @@ func (s *ClientRepository) getNonObsolete() ([]*Client, error) {
 
 // getNonObsoleteByUser return connected clients the user has access to either by user group or by client group
 func (s *ClientRepository) getNonObsoleteByUser(user User, clientGroups []*cgroups.ClientGroup) ([]*Client, error) {
-	userGroups := user.GetGroups()
 	result := make([]*Client, 0, len(s.clients))
 	for _, client := range s.clients {
 		if client.Obsolete(s.KeepDisconnectedClients) {
 			continue
 		}
-		if user.IsAdmin() || client.HasAccessViaUserGroups(userGroups) || client.UserGroupHasAccessViaClientGroup(userGroups, clientGroups) {
+		if client.UserHasAccess(user, clientGroups) {
 			result = append(result, client)
 			continue
 		}
@@ -1,6 +1,7 @@
 package clients
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -14,8 +15,10 @@ import (
 )
 
 type UserMock struct {
-	ReturnIsAdmin bool
-	ReturnGroups  []string
+	ReturnIsAdmin          bool
+	ReturnGroups           []string
+	ReturnClientGroupIDs   []string
+	ReturnPermissionScopes map[string][]string
 }
 
 func (u UserMock) IsAdmin() bool {
@@ -26,6 +29,15 @@ func (u UserMock) GetGroups() []string {
 	return u.ReturnGroups
 }
 
+func (u UserMock) GetClientGroupIDs() []string {
+	return u.ReturnClientGroupIDs
+}
+
+func (u UserMock) GetPermissionScope(permission string) ([]string, bool) {
+	clientGroupIDs, limited := u.ReturnPermissionScopes[permission]
+	return clientGroupIDs, limited
+}
+
 var admin = UserMock{
 	ReturnIsAdmin: true,
 }
@@ -596,3 +608,47 @@ func TestGetClientByTag(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncRemoteClients(t *testing.T) {
+	now = nowMockF
+	ctx := context.Background()
+
+	local := New(t).ID("local").Build()
+	remote := New(t).ID("remote").Build()
+	gone := New(t).ID("gone").Build()
+	deleted := New(t).ID("deleted").DisconnectedDuration(time.Minute).Build()
+	p := NewFakeClientProvider(t, nil, local, remote, gone)
+	defer p.Close()
+	repo := NewClientRepositoryWithDB([]*Client{local, deleted}, nil, p, testLog)
+
+	err := repo.SyncRemoteClients(ctx, map[string]RemoteConnection{
+		"remote": {NodeID: "node-2"},
+	})
+	require.NoError(t, err)
+
+	gotLocal, err := repo.GetActiveByID("local")
+	require.NoError(t, err)
+	assert.Same(t, local, gotLocal, "clients connected to this node must be kept")
+
+	gotRemote, err := repo.GetActiveByID("remote")
+	require.NoError(t, err)
+	require.NotNil(t, gotRemote)
+	assert.Equal(t, "node-2", gotRemote.Node)
+
+	gotGone, err := repo.GetByID("gone")
+	require.NoError(t, err)
+	require.NotNil(t, gotGone)
+	assert.Equal(t, &nowMock, gotGone.DisconnectedAt, "client of a dead node must be disconnected")
+
+	gotDeleted, err := repo.GetByID("deleted")
+	require.NoError(t, err)
+	assert.Nil(t, gotDeleted)
+
+	err = repo.SyncRemoteClients(ctx, nil)
+	require.NoError(t, err)
+
+	gotRemote, err = repo.GetByID("remote")
+	require.NoError(t, err)
+	assert.Equal(t, "", gotRemote.Node)
+	assert.NotNil(t, gotRemote.DisconnectedAt)
+}
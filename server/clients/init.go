@@ -6,7 +6,8 @@ import (
 )
 
 // GetInitState returns an initial Client Repository state populated with clients from the internal storage.
-func GetInitState(ctx context.Context, p ClientProvider) ([]*Client, error) {
+// Clients connected to other nodes of the cluster are given by remoteClientIDs, they are left connected.
+func GetInitState(ctx context.Context, p ClientProvider, remoteClientIDs map[string]bool) ([]*Client, error) {
 	all, err := p.GetAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get clients: %v", err)
@@ -15,7 +16,7 @@ func GetInitState(ctx context.Context, p ClientProvider) ([]*Client, error) {
 	// mark previously connected clients as disconnected with current time
 	now := now()
 	for _, cur := range all {
-		if cur.DisconnectedAt == nil {
+		if cur.DisconnectedAt == nil && !remoteClientIDs[cur.ID] {
 			cur.SetDisconnected(&now)
 			err := p.Save(ctx, cur)
 			if err != nil {
@@ -15,13 +15,15 @@ func TestGetInitState(t *testing.T) {
 	wantC1.DisconnectedAt = &nowMock
 	c2 := New(t).DisconnectedDuration(5 * time.Minute).Build()
 	c3 := New(t).DisconnectedDuration(2 * time.Hour).Build()
+	c4 := New(t).Build()
 
 	testCases := []struct {
 		name string
 
-		dbClients  []*Client
-		expiration time.Duration
-		wantRes    []*Client
+		dbClients       []*Client
+		remoteClientIDs map[string]bool
+		expiration      time.Duration
+		wantRes         []*Client
 	}{
 		{
 			name:      "no clients",
@@ -40,6 +42,13 @@ func TestGetInitState(t *testing.T) {
 			wantRes:    []*Client{wantC1},
 			expiration: 0,
 		},
+		{
+			name:            "1 connected, 1 connected to another node",
+			dbClients:       []*Client{c1, c4},
+			remoteClientIDs: map[string]bool{c4.ID: true},
+			wantRes:         []*Client{wantC1, c4},
+			expiration:      hour,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -49,7 +58,7 @@ func TestGetInitState(t *testing.T) {
 			defer p.Close()
 
 			// when
-			gotClients, gotErr := GetInitState(ctx, p)
+			gotClients, gotErr := GetInitState(ctx, p, tc.remoteClientIDs)
 
 			// then
 			assert.NoError(t, gotErr)
@@ -14,6 +14,7 @@ import (
 	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
 	chshare "github.com/cloudradar-monitoring/rport/share/clientconfig"
 	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/query"
 )
 
 type ClientProvider interface {
@@ -26,11 +27,16 @@ type ClientProvider interface {
 
 type SqliteProvider struct {
 	db                      *sqlx.DB
+	converter               *query.SQLConverter
 	keepDisconnectedClients *time.Duration
 }
 
 func newSqliteProvider(db *sqlx.DB, keepDisconnectedClients *time.Duration) *SqliteProvider {
-	return &SqliteProvider{db: db, keepDisconnectedClients: keepDisconnectedClients}
+	return &SqliteProvider{
+		db:                      db,
+		converter:               query.NewSQLConverter(db.DriverName()),
+		keepDisconnectedClients: keepDisconnectedClients,
+	}
 }
 
 func (p *SqliteProvider) GetAll(ctx context.Context) ([]*Client, error) {
@@ -38,7 +44,11 @@ func (p *SqliteProvider) GetAll(ctx context.Context) ([]*Client, error) {
 	err := p.db.SelectContext(
 		ctx,
 		&res,
-		"SELECT * FROM clients WHERE disconnected_at IS NULL OR DATETIME(disconnected_at) >= DATETIME(?) OR ?",
+		p.db.Rebind(fmt.Sprintf(
+			"SELECT * FROM clients WHERE disconnected_at IS NULL OR %s >= %s OR ?",
+			p.converter.DateTime("disconnected_at"),
+			p.converter.DateTime("?"),
+		)),
 		p.keepDisconnectedClientsStart(),
 		p.keepDisconnectedClients == nil,
 	)
@@ -50,7 +60,7 @@ func (p *SqliteProvider) GetAll(ctx context.Context) ([]*Client, error) {
 
 func (p *SqliteProvider) get(ctx context.Context, id string) (*Client, error) {
 	res := &clientSqlite{}
-	err := p.db.GetContext(ctx, res, "SELECT * FROM clients WHERE id = ?", id)
+	err := p.db.GetContext(ctx, res, p.db.Rebind("SELECT * FROM clients WHERE id = ?"), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -63,7 +73,8 @@ func (p *SqliteProvider) get(ctx context.Context, id string) (*Client, error) {
 func (p *SqliteProvider) Save(ctx context.Context, client *Client) error {
 	_, err := p.db.NamedExecContext(
 		ctx,
-		"INSERT OR REPLACE INTO clients (id, client_auth_id, disconnected_at, details) VALUES (:id, :client_auth_id, :disconnected_at, :details)",
+		"INSERT INTO clients (id, client_auth_id, disconnected_at, details) VALUES (:id, :client_auth_id, :disconnected_at, :details)"+
+			" ON CONFLICT(id) DO UPDATE SET client_auth_id = excluded.client_auth_id, disconnected_at = excluded.disconnected_at, details = excluded.details",
 		convertToSqlite(client),
 	)
 	return err
@@ -72,7 +83,11 @@ func (p *SqliteProvider) Save(ctx context.Context, client *Client) error {
 func (p *SqliteProvider) DeleteObsolete(ctx context.Context) error {
 	_, err := p.db.ExecContext(
 		ctx,
-		"DELETE FROM clients WHERE disconnected_at IS NOT NULL AND DATETIME(disconnected_at) < DATETIME(?) AND ?",
+		p.db.Rebind(fmt.Sprintf(
+			"DELETE FROM clients WHERE disconnected_at IS NOT NULL AND %s < %s AND ?",
+			p.converter.DateTime("disconnected_at"),
+			p.converter.DateTime("?"),
+		)),
 		p.keepDisconnectedClientsStart(),
 		p.keepDisconnectedClients != nil,
 	)
@@ -80,7 +95,7 @@ func (p *SqliteProvider) DeleteObsolete(ctx context.Context) error {
 }
 
 func (p *SqliteProvider) Delete(ctx context.Context, id string) error {
-	_, err := p.db.ExecContext(ctx, "DELETE FROM clients WHERE id = ?", id)
+	_, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM clients WHERE id = ?"), id)
 	return err
 }
 
@@ -7,6 +7,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/db/migration/clients"
+	"github.com/cloudradar-monitoring/rport/share/test"
 )
 
 func TestClientsSqliteProvider(t *testing.T) {
@@ -68,3 +72,56 @@ func TestClientsSqliteProvider(t *testing.T) {
 	require.NoError(t, err)
 	assert.ElementsMatch(t, []*Client{c1, c2, c3, c4}, gotAll)
 }
+
+func TestClientsProviderDatabases(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		db, err := database.Open("clients", ":memory:", clients.AssetNames(), clients.Asset, database.Options{SQLite: DataSourceOptions, PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		keepLost := hour
+		p := newSqliteProvider(db, &keepLost)
+		defer p.Close()
+
+		active := New(t).Build()
+		disconnected := New(t).DisconnectedDuration(5 * time.Minute).Build()
+		obsolete := New(t).DisconnectedDuration(keepLost + time.Minute).Build()
+		for _, c := range []*Client{active, disconnected, obsolete} {
+			require.NoError(t, p.Save(ctx, c))
+		}
+
+		gotAll, err := p.GetAll(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{active.ID, disconnected.ID}, clientIDs(gotAll))
+
+		require.NoError(t, p.DeleteObsolete(ctx))
+		gotAll, err = newSqliteProvider(db, nil).GetAll(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{active.ID, disconnected.ID}, clientIDs(gotAll))
+
+		active.Name = "renamed"
+		active.Tags = []string{"linux"}
+		disconnectedAt := time.Date(2020, 11, 5, 12, 11, 20, 0, time.UTC)
+		active.DisconnectedAt = &disconnectedAt
+		require.NoError(t, p.Save(ctx, active))
+		got, err := p.get(ctx, active.ID)
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.Equal(t, "renamed", got.Name)
+		assert.Equal(t, []string{"linux"}, got.Tags)
+		require.NotNil(t, got.DisconnectedAt)
+		assert.True(t, disconnectedAt.Equal(*got.DisconnectedAt))
+
+		require.NoError(t, p.Delete(ctx, active.ID))
+		got, err = p.get(ctx, active.ID)
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func clientIDs(list []*Client) []string {
+	var ids []string
+	for _, c := range list {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
@@ -76,7 +76,7 @@ func (p *SQLiteProvider) List(ctx context.Context, clientID string, options *que
 
 	q, params = p.converter.AppendOptionsToQuery(options, q, params)
 
-	err := p.db.SelectContext(ctx, &values, q, params...)
+	err := p.db.SelectContext(ctx, &values, p.db.Rebind(q), params...)
 	if err != nil {
 		return values, err
 	}
@@ -92,9 +92,10 @@ func (p *SQLiteProvider) Count(ctx context.Context, clientID string, options *qu
 
 	countOptions := *options
 	countOptions.Pagination = nil
+	countOptions.Sorts = nil
 	q, params = p.converter.AppendOptionsToQuery(&countOptions, q, params)
 
-	err := p.db.GetContext(ctx, &result, q, params...)
+	err := p.db.GetContext(ctx, &result, p.db.Rebind(q), params...)
 	if err != nil {
 		return 0, err
 	}
@@ -103,6 +104,6 @@ func (p *SQLiteProvider) Count(ctx context.Context, clientID string, options *qu
 }
 
 func (p *SQLiteProvider) Delete(ctx context.Context, clientID, id string) error {
-	_, err := p.db.ExecContext(ctx, "DELETE FROM stored_tunnels WHERE client_id = ? AND id = ?", clientID, id)
+	_, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM stored_tunnels WHERE client_id = ? AND id = ?"), clientID, id)
 	return err
 }
@@ -0,0 +1,215 @@
+package clients
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	UpdatesReportGroupUnknown = "unknown"
+
+	UpdatesReportCountNone      = "0"
+	UpdatesReportCountFew       = "1-9"
+	UpdatesReportCountSome      = "10-49"
+	UpdatesReportCountMany      = "50+"
+	UpdatesReportRebootPending  = "reboot_pending"
+	UpdatesReportNoReboot       = "no_reboot_pending"
+	UpdatesReportRefreshedDay   = "less_than_1d"
+	UpdatesReportRefreshedWeek  = "1d_to_7d"
+	UpdatesReportRefreshedMonth = "7d_to_30d"
+	UpdatesReportRefreshedOlder = "more_than_30d"
+)
+
+var (
+	updatesReportCountGroups   = []string{UpdatesReportCountNone, UpdatesReportCountFew, UpdatesReportCountSome, UpdatesReportCountMany, UpdatesReportGroupUnknown}
+	updatesReportRebootGroups  = []string{UpdatesReportRebootPending, UpdatesReportNoReboot, UpdatesReportGroupUnknown}
+	updatesReportRefreshGroups = []string{UpdatesReportRefreshedDay, UpdatesReportRefreshedWeek, UpdatesReportRefreshedMonth, UpdatesReportRefreshedOlder, UpdatesReportGroupUnknown}
+)
+
+// UpdatesReport is the patch compliance of a set of clients based on the updates status reported by each of them
+type UpdatesReport struct {
+	TotalClients               int                   `json:"total_clients"`
+	ByUpdatesAvailable         []*UpdatesReportGroup `json:"by_updates_available"`
+	BySecurityUpdatesAvailable []*UpdatesReportGroup `json:"by_security_updates_available"`
+	ByRebootPending            []*UpdatesReportGroup `json:"by_reboot_pending"`
+	ByRefreshAge               []*UpdatesReportGroup `json:"by_refresh_age"`
+	Clients                    []*UpdatesReportEntry `json:"clients"`
+}
+
+type UpdatesReportGroup struct {
+	Name      string   `json:"name"`
+	Count     int      `json:"count"`
+	ClientIDs []string `json:"client_ids"`
+}
+
+// UpdatesReportEntry is the updates status of a single client, the status fields are nil if the client has not reported a valid status yet
+type UpdatesReportEntry struct {
+	ClientID                 string          `json:"client_id"`
+	Name                     string          `json:"name"`
+	ConnectionState          ConnectionState `json:"connection_state"`
+	UpdatesAvailable         *int            `json:"updates_available"`
+	SecurityUpdatesAvailable *int            `json:"security_updates_available"`
+	RebootPending            *bool           `json:"reboot_pending"`
+	Refreshed                *time.Time      `json:"refreshed"`
+	Error                    string          `json:"error,omitempty"`
+}
+
+func NewUpdatesReport(clients []*CalculatedClient) *UpdatesReport {
+	report := &UpdatesReport{
+		TotalClients:               len(clients),
+		ByUpdatesAvailable:         newUpdatesReportGroups(updatesReportCountGroups),
+		BySecurityUpdatesAvailable: newUpdatesReportGroups(updatesReportCountGroups),
+		ByRebootPending:            newUpdatesReportGroups(updatesReportRebootGroups),
+		ByRefreshAge:               newUpdatesReportGroups(updatesReportRefreshGroups),
+		Clients:                    make([]*UpdatesReportEntry, 0, len(clients)),
+	}
+
+	reportNow := now()
+	for _, c := range clients {
+		entry := &UpdatesReportEntry{
+			ClientID:        c.ID,
+			Name:            c.Name,
+			ConnectionState: c.ConnectionState,
+		}
+		report.Clients = append(report.Clients, entry)
+
+		status := c.UpdatesStatus
+		if status == nil {
+			addToUpdatesReportGroup(report.ByUpdatesAvailable, UpdatesReportGroupUnknown, c.ID)
+			addToUpdatesReportGroup(report.BySecurityUpdatesAvailable, UpdatesReportGroupUnknown, c.ID)
+			addToUpdatesReportGroup(report.ByRebootPending, UpdatesReportGroupUnknown, c.ID)
+			addToUpdatesReportGroup(report.ByRefreshAge, UpdatesReportGroupUnknown, c.ID)
+			continue
+		}
+
+		// the refresh age is known even if the package manager failed to list the updates
+		refreshed := status.Refreshed
+		entry.Refreshed = &refreshed
+		addToUpdatesReportGroup(report.ByRefreshAge, refreshAgeGroup(reportNow.Sub(refreshed)), c.ID)
+
+		if status.Error != "" {
+			entry.Error = status.Error
+			addToUpdatesReportGroup(report.ByUpdatesAvailable, UpdatesReportGroupUnknown, c.ID)
+			addToUpdatesReportGroup(report.BySecurityUpdatesAvailable, UpdatesReportGroupUnknown, c.ID)
+			addToUpdatesReportGroup(report.ByRebootPending, UpdatesReportGroupUnknown, c.ID)
+			continue
+		}
+
+		updatesAvailable := status.UpdatesAvailable
+		securityUpdatesAvailable := status.SecurityUpdatesAvailable
+		rebootPending := status.RebootPending
+		entry.UpdatesAvailable = &updatesAvailable
+		entry.SecurityUpdatesAvailable = &securityUpdatesAvailable
+		entry.RebootPending = &rebootPending
+
+		addToUpdatesReportGroup(report.ByUpdatesAvailable, countGroup(updatesAvailable), c.ID)
+		addToUpdatesReportGroup(report.BySecurityUpdatesAvailable, countGroup(securityUpdatesAvailable), c.ID)
+		if rebootPending {
+			addToUpdatesReportGroup(report.ByRebootPending, UpdatesReportRebootPending, c.ID)
+		} else {
+			addToUpdatesReportGroup(report.ByRebootPending, UpdatesReportNoReboot, c.ID)
+		}
+	}
+
+	return report
+}
+
+func newUpdatesReportGroups(names []string) []*UpdatesReportGroup {
+	groups := make([]*UpdatesReportGroup, 0, len(names))
+	for _, name := range names {
+		groups = append(groups, &UpdatesReportGroup{
+			Name:      name,
+			ClientIDs: []string{},
+		})
+	}
+	return groups
+}
+
+func addToUpdatesReportGroup(groups []*UpdatesReportGroup, name, clientID string) {
+	for _, g := range groups {
+		if g.Name == name {
+			g.Count++
+			g.ClientIDs = append(g.ClientIDs, clientID)
+			return
+		}
+	}
+}
+
+func countGroup(count int) string {
+	switch {
+	case count <= 0:
+		return UpdatesReportCountNone
+	case count < 10:
+		return UpdatesReportCountFew
+	case count < 50:
+		return UpdatesReportCountSome
+	default:
+		return UpdatesReportCountMany
+	}
+}
+
+func refreshAgeGroup(age time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case age < day:
+		return UpdatesReportRefreshedDay
+	case age < 7*day:
+		return UpdatesReportRefreshedWeek
+	case age < 30*day:
+		return UpdatesReportRefreshedMonth
+	default:
+		return UpdatesReportRefreshedOlder
+	}
+}
+
+var updatesReportCSVHeader = []string{
+	"client_id", "name", "connection_state", "updates_available", "security_updates_available", "reboot_pending", "refreshed", "error",
+}
+
+// WriteCSV writes a line per client, unknown values are left empty
+func (r *UpdatesReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(updatesReportCSVHeader); err != nil {
+		return err
+	}
+	for _, e := range r.Clients {
+		err := cw.Write([]string{
+			e.ClientID,
+			e.Name,
+			string(e.ConnectionState),
+			formatOptionalInt(e.UpdatesAvailable),
+			formatOptionalInt(e.SecurityUpdatesAvailable),
+			formatOptionalBool(e.RebootPending),
+			formatOptionalTime(e.Refreshed),
+			e.Error,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatOptionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func formatOptionalBool(v *bool) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatBool(*v)
+}
+
+func formatOptionalTime(v *time.Time) string {
+	if v == nil {
+		return ""
+	}
+	return v.UTC().Format(time.RFC3339)
+}
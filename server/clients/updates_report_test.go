@@ -0,0 +1,99 @@
+package clients
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+func TestNewUpdatesReport(t *testing.T) {
+	now = nowMockF
+
+	clients := []*CalculatedClient{
+		{
+			Client: &Client{
+				ID:   "patched",
+				Name: "patched client",
+				UpdatesStatus: &models.UpdatesStatus{
+					Refreshed: clientsNow.Add(-time.Hour),
+				},
+			},
+			ConnectionState: Connected,
+		},
+		{
+			Client: &Client{
+				ID:   "outdated",
+				Name: "outdated client",
+				UpdatesStatus: &models.UpdatesStatus{
+					Refreshed:                clientsNow.Add(-48 * time.Hour),
+					UpdatesAvailable:         57,
+					SecurityUpdatesAvailable: 3,
+					RebootPending:            true,
+				},
+			},
+			ConnectionState: Connected,
+		},
+		{
+			Client: &Client{
+				ID:   "failed",
+				Name: "failed client",
+				UpdatesStatus: &models.UpdatesStatus{
+					Refreshed: clientsNow.Add(-40 * 24 * time.Hour),
+					Error:     "apt-get not found",
+				},
+			},
+			ConnectionState: Disconnected,
+		},
+		{
+			Client: &Client{
+				ID:   "unknown",
+				Name: "unknown client",
+			},
+			ConnectionState: Connected,
+		},
+	}
+
+	report := NewUpdatesReport(clients)
+
+	assert.Equal(t, 4, report.TotalClients)
+	assert.Equal(t, []*UpdatesReportGroup{
+		{Name: UpdatesReportCountNone, Count: 1, ClientIDs: []string{"patched"}},
+		{Name: UpdatesReportCountFew, Count: 0, ClientIDs: []string{}},
+		{Name: UpdatesReportCountSome, Count: 0, ClientIDs: []string{}},
+		{Name: UpdatesReportCountMany, Count: 1, ClientIDs: []string{"outdated"}},
+		{Name: UpdatesReportGroupUnknown, Count: 2, ClientIDs: []string{"failed", "unknown"}},
+	}, report.ByUpdatesAvailable)
+	assert.Equal(t, []*UpdatesReportGroup{
+		{Name: UpdatesReportCountNone, Count: 1, ClientIDs: []string{"patched"}},
+		{Name: UpdatesReportCountFew, Count: 1, ClientIDs: []string{"outdated"}},
+		{Name: UpdatesReportCountSome, Count: 0, ClientIDs: []string{}},
+		{Name: UpdatesReportCountMany, Count: 0, ClientIDs: []string{}},
+		{Name: UpdatesReportGroupUnknown, Count: 2, ClientIDs: []string{"failed", "unknown"}},
+	}, report.BySecurityUpdatesAvailable)
+	assert.Equal(t, []*UpdatesReportGroup{
+		{Name: UpdatesReportRebootPending, Count: 1, ClientIDs: []string{"outdated"}},
+		{Name: UpdatesReportNoReboot, Count: 1, ClientIDs: []string{"patched"}},
+		{Name: UpdatesReportGroupUnknown, Count: 2, ClientIDs: []string{"failed", "unknown"}},
+	}, report.ByRebootPending)
+	assert.Equal(t, []*UpdatesReportGroup{
+		{Name: UpdatesReportRefreshedDay, Count: 1, ClientIDs: []string{"patched"}},
+		{Name: UpdatesReportRefreshedWeek, Count: 1, ClientIDs: []string{"outdated"}},
+		{Name: UpdatesReportRefreshedMonth, Count: 0, ClientIDs: []string{}},
+		{Name: UpdatesReportRefreshedOlder, Count: 1, ClientIDs: []string{"failed"}},
+		{Name: UpdatesReportGroupUnknown, Count: 1, ClientIDs: []string{"unknown"}},
+	}, report.ByRefreshAge)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteCSV(&buf))
+	assert.Equal(t, `client_id,name,connection_state,updates_available,security_updates_available,reboot_pending,refreshed,error
+patched,patched client,connected,0,0,false,2020-08-19T09:09:23Z,
+outdated,outdated client,connected,57,3,true,2020-08-17T10:09:23Z,
+failed,failed client,disconnected,,,,2020-07-10T10:09:23Z,apt-get not found
+unknown,unknown client,connected,,,,,
+`, buf.String())
+}
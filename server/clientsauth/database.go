@@ -8,6 +8,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/mattn/go-sqlite3"
 
+	"github.com/cloudradar-monitoring/rport/db/postgres"
 	"github.com/cloudradar-monitoring/rport/share/query"
 
 	"github.com/cloudradar-monitoring/rport/share/enums"
@@ -39,17 +40,17 @@ func (c *DatabaseProvider) GetFiltered(filter *query.ListOptions) ([]*ClientAuth
 	filter.Sorts = nil
 	cQuery, cParams := c.converter.ConvertListOptionsToQuery(filter, fmt.Sprintf("SELECT COUNT(id) FROM %s", c.tableName))
 	var count = 0
-	if err := c.db.Get(&count, cQuery, cParams...); err != nil {
+	if err := c.db.Get(&count, c.db.Rebind(cQuery), cParams...); err != nil {
 		return nil, 0, err
 	}
 	var result = []*ClientAuth{}
-	err := c.db.Select(&result, rQuery, rParams...)
+	err := c.db.Select(&result, c.db.Rebind(rQuery), rParams...)
 	return result, count, err
 }
 
 func (c *DatabaseProvider) Get(id string) (*ClientAuth, error) {
 	result := &ClientAuth{}
-	err := c.db.Get(result, fmt.Sprintf("SELECT id, password FROM %s WHERE id = ?", c.tableName), id)
+	err := c.db.Get(result, c.db.Rebind(fmt.Sprintf("SELECT id, password FROM %s WHERE id = ?", c.tableName)), id)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -60,6 +61,9 @@ func (c *DatabaseProvider) Add(client *ClientAuth) (bool, error) {
 	_, err := c.db.NamedExec(fmt.Sprintf("INSERT INTO %s (id, password) VALUES (:id, :password)", c.tableName), client)
 	if err != nil {
 		// Check for client already exists error
+		if postgres.IsUniqueViolation(err) {
+			return false, nil
+		}
 		switch typeErr := err.(type) {
 		case sqlite3.Error:
 			if typeErr.Code == sqlite3.ErrConstraint {
@@ -76,7 +80,7 @@ func (c *DatabaseProvider) Add(client *ClientAuth) (bool, error) {
 }
 
 func (c *DatabaseProvider) Delete(id string) error {
-	_, err := c.db.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ?", c.tableName), id)
+	_, err := c.db.Exec(c.db.Rebind(fmt.Sprintf("DELETE FROM %s WHERE id = ?", c.tableName)), id)
 	return err
 }
 
@@ -0,0 +1,126 @@
+package chserver
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/cluster"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+func initClusterService(ctx context.Context, config *chconfig.Config, log *logger.Logger) (*cluster.Service, error) {
+	provider, err := cluster.NewSqliteProvider(
+		path.Join(config.Server.DataDir, "cluster.db"),
+		config.GetDatabaseOptions(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	service := cluster.NewService(config.Cluster, provider, log.Fork("cluster"))
+	err = service.Heartbeat(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to join cluster: %v", err)
+	}
+
+	return service, nil
+}
+
+// remoteClientIDs returns the ids of the clients connected to other nodes of the cluster
+func (s *Server) remoteClientIDs() map[string]bool {
+	if s.clusterService == nil {
+		return nil
+	}
+
+	result := make(map[string]bool)
+	for clientID := range s.clusterService.RemoteClients() {
+		result[clientID] = true
+	}
+	return result
+}
+
+// syncCluster applies the state of the other nodes of the cluster refreshed by the last heartbeat
+func (s *Server) syncCluster(ctx context.Context) error {
+	remoteConns := make(map[string]clients.RemoteConnection)
+	for clientID, node := range s.clusterService.RemoteClients() {
+		remoteConns[clientID] = clients.RemoteConnection{
+			NodeID: node.ID,
+			Conn:   s.clusterService.NewRemoteConn(node, clientID),
+		}
+	}
+
+	err := s.clientService.GetRepo().SyncRemoteClients(ctx, remoteConns)
+	if err != nil {
+		return err
+	}
+
+	// schedules are created on any node, but run only on the leader
+	if s.scheduleManager != nil {
+		err = s.scheduleManager.Reload(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	// alert rules are created on any node, measurements are evaluated on the node the client is connected to
+	if s.alertsService != nil {
+		return s.alertsService.ReloadRules(ctx)
+	}
+	return nil
+}
+
+// claimClusterClient stores that a client is connected to this node, so the other nodes forward requests to it
+func (s *Server) claimClusterClient(ctx context.Context, client *clients.Client, clog *logger.Logger) {
+	if s.clusterService == nil {
+		return
+	}
+
+	err := s.clusterService.ClaimClient(ctx, client.ID)
+	if err != nil {
+		clog.Errorf("Failed to claim client %s in cluster: %v", client.ID, err)
+	}
+}
+
+// releaseClusterClient removes the ownership of a disconnected client. It returns false if the client reconnected to
+// another node of the cluster in the meantime, it must not be terminated then.
+func (s *Server) releaseClusterClient(client *clients.Client, clog *logger.Logger) bool {
+	if s.clusterService == nil {
+		return true
+	}
+
+	released, err := s.clusterService.ReleaseClient(context.Background(), client.ID)
+	if err != nil {
+		clog.Errorf("Failed to release client %s in cluster: %v", client.ID, err)
+		return true
+	}
+	if !released {
+		clog.Infof("Client %s reconnected to another cluster node.", client.ID)
+		s.clientService.GetRepo().Forget(client.ID)
+		return false
+	}
+
+	return true
+}
+
+// relayJobResult sends the result of a job started by another node of the cluster to that node
+func (s *Server) relayJobResult(job *models.Job, result []byte) {
+	if s.clusterService == nil {
+		return
+	}
+
+	nodeID, ok := s.clusterService.RelayedJobOrigin(job.JID)
+	if !ok {
+		return
+	}
+
+	go func() {
+		err := s.clusterService.SendJobResult(context.Background(), nodeID, result)
+		if err != nil {
+			s.Errorf("%s, failed to send job result to cluster node %q: %v", job.LogPrefix(), nodeID, err)
+		}
+	}()
+}
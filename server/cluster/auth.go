@@ -0,0 +1,172 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/share/security"
+)
+
+const (
+	HeaderNode      = "X-Rport-Cluster-Node"
+	HeaderTarget    = "X-Rport-Cluster-Target"
+	HeaderTimestamp = "X-Rport-Cluster-Timestamp"
+	HeaderNonce     = "X-Rport-Cluster-Nonce"
+	HeaderSignature = "X-Rport-Cluster-Signature"
+	// HeaderPeerAddr is the address of the caller of a forwarded request, the receiving node authenticates the caller
+	// with it instead of the address of the forwarding node
+	HeaderPeerAddr = "X-Rport-Cluster-Peer-Addr"
+
+	// MaxClockSkew is the max age of a signed request, the clocks of all nodes must be in sync
+	MaxClockSkew = time.Minute
+
+	nonceLength = 32
+)
+
+type ctxKeyType string
+
+const ctxKey ctxKeyType = "cluster-node"
+
+// WithNodeID returns a copy of a given context that contains the id of the node a verified request was sent by.
+func WithNodeID(ctx context.Context, nodeID string) context.Context {
+	return context.WithValue(ctx, ctxKey, nodeID)
+}
+
+// NodeIDFromContext returns the id of the node a request was sent by, empty if it isn't a verified cluster request.
+func NodeIDFromContext(ctx context.Context) string {
+	nodeID, _ := ctx.Value(ctxKey).(string)
+	return nodeID
+}
+
+// IsForwarded returns true if a request was sent by another node of the cluster
+func IsForwarded(req *http.Request) bool {
+	return req.Header.Get(HeaderNode) != ""
+}
+
+// Sign authenticates a request sent to the given node with the shared secret. The body is read to sign it and
+// replaced by a copy.
+func (s *Service) Sign(req *http.Request, targetID string) error {
+	bodyHash, err := hashBody(req)
+	if err != nil {
+		return err
+	}
+	nonce, err := security.NewRandomToken(nonceLength)
+	if err != nil {
+		return err
+	}
+
+	// the request is sent by this node on its own, there is no caller
+	req.Header.Del(HeaderPeerAddr)
+	s.sign(req, targetID, bodyHash, nonce)
+	return nil
+}
+
+func (s *Service) sign(req *http.Request, targetID, bodyHash, nonce string) {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set(HeaderNode, s.config.NodeID)
+	req.Header.Set(HeaderTarget, targetID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, s.signature(s.config.NodeID, targetID, req.Method, req.URL.RequestURI(), timestamp, nonce, req.Header.Get(HeaderPeerAddr), bodyHash))
+}
+
+// Verify checks the signature of a request sent by another node and returns the id of that node. Each request is
+// accepted only once and only by the node it was signed for, replayed requests are rejected.
+func (s *Service) Verify(req *http.Request) (string, error) {
+	nodeID := req.Header.Get(HeaderNode)
+	if nodeID == "" {
+		return "", errors.New("missing cluster node header")
+	}
+
+	// the target is signed, so a request can't be replayed on another node
+	if target := req.Header.Get(HeaderTarget); target != s.config.NodeID {
+		return "", fmt.Errorf("cluster request of node %q is meant for node %q", nodeID, target)
+	}
+
+	timestamp := req.Header.Get(HeaderTimestamp)
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid cluster timestamp %q", timestamp)
+	}
+	signedAt := time.Unix(unix, 0)
+	skew := s.now().Sub(signedAt)
+	if skew > MaxClockSkew || skew < -MaxClockSkew {
+		return "", fmt.Errorf("cluster request of node %q is expired", nodeID)
+	}
+
+	nonce := req.Header.Get(HeaderNonce)
+	if nonce == "" {
+		return "", fmt.Errorf("missing nonce of cluster request of node %q", nodeID)
+	}
+
+	bodyHash, err := hashBody(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read cluster request of node %q: %v", nodeID, err)
+	}
+
+	expected := s.signature(nodeID, s.config.NodeID, req.Method, req.URL.RequestURI(), timestamp, nonce, req.Header.Get(HeaderPeerAddr), bodyHash)
+	if !hmac.Equal([]byte(expected), []byte(req.Header.Get(HeaderSignature))) {
+		return "", fmt.Errorf("invalid signature of cluster request of node %q", nodeID)
+	}
+
+	// the request is rejected as expired once the nonce can be forgotten
+	if !s.useNonce(nodeID+"\n"+nonce, signedAt.Add(MaxClockSkew)) {
+		return "", fmt.Errorf("cluster request of node %q has been replayed", nodeID)
+	}
+
+	return nodeID, nil
+}
+
+// useNonce returns false if the nonce has been used before, otherwise it's remembered until the given time
+func (s *Service) useNonce(nonce string, until time.Time) bool {
+	s.noncesMu.Lock()
+	defer s.noncesMu.Unlock()
+
+	now := s.now()
+	for n, expiresAt := range s.nonces {
+		if now.After(expiresAt) {
+			delete(s.nonces, n)
+		}
+	}
+
+	if _, ok := s.nonces[nonce]; ok {
+		return false
+	}
+	s.nonces[nonce] = until
+	return true
+}
+
+func (s *Service) signature(nodeID, targetID, method, uri, timestamp, nonce, peerAddr, bodyHash string) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Secret))
+	mac.Write([]byte(nodeID + "\n" + targetID + "\n" + method + "\n" + uri + "\n" + timestamp + "\n" + nonce + "\n" + peerAddr + "\n" + bodyHash))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// hashBody returns the hex encoded sha256 of the request body, the body is replaced by a copy to be read again
+func hashBody(req *http.Request) (string, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return "", err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
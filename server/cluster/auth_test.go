@@ -0,0 +1,98 @@
+package cluster
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	node1 := newNode(t, ":memory:", "node-1", now)
+	node2 := newNode(t, ":memory:", "node-2", now.Add(30*time.Second))
+
+	req := httptest.NewRequest(http.MethodGet, "http://node-2:3000/api/v1/clients/client-1?fields=id", nil)
+	assert.False(t, IsForwarded(req))
+	require.NoError(t, node1.Sign(req, "node-2"))
+	assert.True(t, IsForwarded(req))
+
+	tampered := req.Clone(req.Context())
+	tampered.URL.Path = "/api/v1/clients/client-2"
+	_, err := node2.Verify(tampered)
+	assert.EqualError(t, err, `invalid signature of cluster request of node "node-1"`)
+
+	spoofed := req.Clone(req.Context())
+	spoofed.Header.Set(HeaderPeerAddr, "192.0.2.10:40000")
+	_, err = node2.Verify(spoofed)
+	assert.EqualError(t, err, `invalid signature of cluster request of node "node-1"`)
+
+	node3 := newNode(t, ":memory:", "node-3", now)
+	_, err = node3.Verify(req)
+	assert.EqualError(t, err, `cluster request of node "node-1" is meant for node "node-2"`)
+
+	retargeted := req.Clone(req.Context())
+	retargeted.Header.Set(HeaderTarget, "node-3")
+	_, err = node3.Verify(retargeted)
+	assert.EqualError(t, err, `invalid signature of cluster request of node "node-1"`)
+
+	node2.config.Secret = "another-secret-another-secret-another"
+	_, err = node2.Verify(req)
+	assert.EqualError(t, err, `invalid signature of cluster request of node "node-1"`)
+	node2.config.Secret = node1.config.Secret
+
+	nodeID, err := node2.Verify(req)
+	require.NoError(t, err)
+	assert.Equal(t, "node-1", nodeID)
+
+	_, err = node2.Verify(req)
+	assert.EqualError(t, err, `cluster request of node "node-1" has been replayed`)
+
+	node2.now = func() time.Time { return now.Add(2 * time.Minute) }
+	_, err = node2.Verify(req)
+	assert.EqualError(t, err, `cluster request of node "node-1" is expired`)
+
+	node1.now = node2.now
+	later := httptest.NewRequest(http.MethodGet, "http://node-2:3000/api/v1/clients/client-1", nil)
+	require.NoError(t, node1.Sign(later, "node-2"))
+	_, err = node2.Verify(later)
+	require.NoError(t, err)
+	assert.Len(t, node2.nonces, 1, "expired nonces must be forgotten")
+
+	noNonce := later.Clone(later.Context())
+	noNonce.Header.Del(HeaderNonce)
+	_, err = node2.Verify(noNonce)
+	assert.EqualError(t, err, `missing nonce of cluster request of node "node-1"`)
+
+	_, err = node2.Verify(httptest.NewRequest(http.MethodGet, "/api/v1/clients", nil))
+	assert.EqualError(t, err, "missing cluster node header")
+}
+
+func TestSignAndVerifyBody(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	node1 := newNode(t, ":memory:", "node-1", now)
+	node2 := newNode(t, ":memory:", "node-2", now)
+
+	newSigned := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "http://node-2:3000/api/v1/cluster/jobs", strings.NewReader(`{"command":"date"}`))
+		require.NoError(t, node1.Sign(req, "node-2"))
+		return req
+	}
+
+	req := newSigned()
+	_, err := node2.Verify(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"command":"date"}`, string(body), "body must be readable after verification")
+
+	tampered := newSigned()
+	tampered.Body = io.NopCloser(strings.NewReader(`{"command":"reboot"}`))
+	_, err = node2.Verify(tampered)
+	assert.EqualError(t, err, `invalid signature of cluster request of node "node-1"`)
+}
@@ -0,0 +1,211 @@
+package cluster
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+// Node is an rportd instance of the cluster
+type Node struct {
+	ID  string `json:"id" db:"id"`
+	URL string `json:"url" db:"url"`
+	// HeartbeatAt is the last time the node announced it's alive
+	HeartbeatAt time.Time `json:"heartbeat_at" db:"heartbeat_at"`
+}
+
+// ClientOwner is the node a client is connected to
+type ClientOwner struct {
+	ClientID    string    `db:"client_id"`
+	NodeID      string    `db:"node_id"`
+	ConnectedAt time.Time `db:"connected_at"`
+}
+
+// Service keeps track of the nodes of the cluster and the clients connected to them. All nodes share the database,
+// the state of the other nodes is refreshed on each heartbeat.
+type Service struct {
+	config     Config
+	provider   Provider
+	logger     *logger.Logger
+	httpClient *http.Client
+	now        func() time.Time
+
+	mu sync.RWMutex
+	// nodes are the alive nodes including this one
+	nodes map[string]*Node
+	// remoteClients are the ids of the clients connected to other alive nodes mapped to the node ids
+	remoteClients map[string]string
+
+	noncesMu sync.Mutex
+	// nonces are the nonces of the verified requests of other nodes mapped to the time they expire
+	nonces map[string]time.Time
+
+	relayedJobsMu sync.Mutex
+	// relayedJobs maps the ids of jobs started by other nodes on local clients to the ids of these nodes
+	relayedJobs map[string]string
+}
+
+func NewService(config Config, provider Provider, logger *logger.Logger) *Service {
+	return &Service{
+		config:        config,
+		provider:      provider,
+		logger:        logger,
+		httpClient:    &http.Client{Timeout: relayTimeout},
+		now:           time.Now,
+		nodes:         make(map[string]*Node),
+		remoteClients: make(map[string]string),
+		relayedJobs:   make(map[string]string),
+		nonces:        make(map[string]time.Time),
+	}
+}
+
+// NodeID returns the id of this node
+func (s *Service) NodeID() string {
+	return s.config.NodeID
+}
+
+// Heartbeat announces this node is alive and refreshes the state of the other nodes
+func (s *Service) Heartbeat(ctx context.Context) error {
+	now := s.now()
+	err := s.provider.SaveNode(ctx, &Node{
+		ID:          s.config.NodeID,
+		URL:         s.config.NodeURL,
+		HeartbeatAt: now,
+	})
+	if err != nil {
+		return err
+	}
+
+	nodes, err := s.provider.GetNodes(ctx)
+	if err != nil {
+		return err
+	}
+	owners, err := s.provider.GetClientOwners(ctx)
+	if err != nil {
+		return err
+	}
+
+	aliveNodes := make(map[string]*Node)
+	for _, node := range nodes {
+		if now.Sub(node.HeartbeatAt) <= s.config.NodeTimeout {
+			aliveNodes[node.ID] = node
+		}
+	}
+
+	remoteClients := make(map[string]string)
+	for _, owner := range owners {
+		// clients of dead nodes are expected to reconnect to one of the alive nodes
+		if owner.NodeID == s.config.NodeID || aliveNodes[owner.NodeID] == nil {
+			continue
+		}
+		remoteClients[owner.ClientID] = owner.NodeID
+	}
+
+	s.mu.Lock()
+	for id := range s.nodes {
+		if aliveNodes[id] == nil {
+			s.logger.Infof("Cluster node %q is gone.", id)
+		}
+	}
+	for id := range aliveNodes {
+		if s.nodes[id] == nil && id != s.config.NodeID {
+			s.logger.Infof("Cluster node %q joined.", id)
+		}
+	}
+	s.nodes = aliveNodes
+	s.remoteClients = remoteClients
+	s.mu.Unlock()
+
+	return nil
+}
+
+// ClaimClient stores that a client is connected to this node
+func (s *Service) ClaimClient(ctx context.Context, clientID string) error {
+	err := s.provider.SetClientOwner(ctx, &ClientOwner{
+		ClientID:    clientID,
+		NodeID:      s.config.NodeID,
+		ConnectedAt: s.now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	delete(s.remoteClients, clientID)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// ReleaseClient removes the ownership of a disconnected client. It returns false if the client is owned by a
+// different node meanwhile, so it must not be marked as disconnected.
+func (s *Service) ReleaseClient(ctx context.Context, clientID string) (bool, error) {
+	owner, err := s.provider.GetClientOwner(ctx, clientID)
+	if err != nil {
+		return false, err
+	}
+	if owner != nil && owner.NodeID != s.config.NodeID {
+		return false, nil
+	}
+
+	return true, s.provider.DeleteClientOwner(ctx, clientID, s.config.NodeID)
+}
+
+// RemoteClients returns the ids of the clients connected to other alive nodes mapped to these nodes
+func (s *Service) RemoteClients() map[string]*Node {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string]*Node, len(s.remoteClients))
+	for clientID, nodeID := range s.remoteClients {
+		result[clientID] = s.nodes[nodeID]
+	}
+	return result
+}
+
+// Node returns an alive node by id or nil if not found
+func (s *Service) Node(id string) *Node {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.nodes[id]
+}
+
+// IsLeader returns true if this node is the alive node with the lowest id. The leader runs the tasks that must be
+// executed only once per cluster, like scheduled jobs.
+func (s *Service) IsLeader() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for id := range s.nodes {
+		if id < s.config.NodeID {
+			return false
+		}
+	}
+	return true
+}
+
+// TrackRelayedJob stores the node that started a job on a local client to send the result to it
+func (s *Service) TrackRelayedJob(jid, nodeID string) {
+	s.relayedJobsMu.Lock()
+	defer s.relayedJobsMu.Unlock()
+
+	s.relayedJobs[jid] = nodeID
+}
+
+// RelayedJobOrigin returns and forgets the node that started a given job, it returns false for local jobs
+func (s *Service) RelayedJobOrigin(jid string) (string, bool) {
+	s.relayedJobsMu.Lock()
+	defer s.relayedJobsMu.Unlock()
+
+	nodeID, ok := s.relayedJobs[jid]
+	delete(s.relayedJobs, jid)
+	return nodeID, ok
+}
+
+func (s *Service) Close() error {
+	return s.provider.Close()
+}
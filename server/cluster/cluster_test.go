@@ -0,0 +1,104 @@
+package cluster
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+var testLog = logger.NewLogger("cluster", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug)
+
+// newNode returns the service of a node, nodes given the same dbPath share the database like the nodes of a cluster
+func newNode(t *testing.T, dbPath, nodeID string, now time.Time) *Service {
+	provider, err := NewSqliteProvider(dbPath, database.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { provider.Close() })
+
+	service := NewService(Config{
+		Enabled:           true,
+		NodeID:            nodeID,
+		NodeURL:           "http://" + nodeID + ":3000",
+		Secret:            "0123456789abcdef0123456789abcdef",
+		HeartbeatInterval: 5 * time.Second,
+		NodeTimeout:       30 * time.Second,
+	}, provider, testLog)
+	service.now = func() time.Time { return now }
+
+	return service
+}
+
+func TestHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	dbPath := filepath.Join(t.TempDir(), "cluster.db")
+	node1 := newNode(t, dbPath, "node-1", now)
+	node2 := newNode(t, dbPath, "node-2", now)
+	node3 := newNode(t, dbPath, "node-3", now.Add(-time.Minute))
+
+	require.NoError(t, node3.Heartbeat(ctx))
+	require.NoError(t, node3.ClaimClient(ctx, "client-3"))
+	require.NoError(t, node2.Heartbeat(ctx))
+	require.NoError(t, node2.ClaimClient(ctx, "client-2"))
+	require.NoError(t, node1.ClaimClient(ctx, "client-1"))
+	require.NoError(t, node1.Heartbeat(ctx))
+
+	assert.NotNil(t, node1.Node("node-2"))
+	assert.Nil(t, node1.Node("node-3"), "node without recent heartbeat must be dead")
+	assert.Equal(t, map[string]*Node{"client-2": node1.Node("node-2")}, node1.RemoteClients())
+	assert.Equal(t, "http://node-2:3000", node1.Node("node-2").URL)
+
+	assert.True(t, node1.IsLeader())
+	require.NoError(t, node2.Heartbeat(ctx))
+	assert.False(t, node2.IsLeader())
+}
+
+func TestReleaseClient(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	dbPath := filepath.Join(t.TempDir(), "cluster.db")
+	node1 := newNode(t, dbPath, "node-1", now)
+	node2 := newNode(t, dbPath, "node-2", now)
+
+	require.NoError(t, node1.ClaimClient(ctx, "client-1"))
+	require.NoError(t, node2.ClaimClient(ctx, "client-1"))
+
+	released, err := node1.ReleaseClient(ctx, "client-1")
+	require.NoError(t, err)
+	assert.False(t, released, "client reconnected to another node")
+
+	released, err = node2.ReleaseClient(ctx, "client-1")
+	require.NoError(t, err)
+	assert.True(t, released)
+
+	owner, err := node1.provider.GetClientOwner(ctx, "client-1")
+	require.NoError(t, err)
+	assert.Nil(t, owner)
+
+	released, err = node1.ReleaseClient(ctx, "client-1")
+	require.NoError(t, err)
+	assert.True(t, released, "client without owner must be released")
+}
+
+func TestRelayedJobs(t *testing.T) {
+	service := newNode(t, ":memory:", "node-1", time.Now())
+
+	service.TrackRelayedJob("job-1", "node-2")
+
+	_, ok := service.RelayedJobOrigin("job-2")
+	assert.False(t, ok)
+
+	nodeID, ok := service.RelayedJobOrigin("job-1")
+	assert.True(t, ok)
+	assert.Equal(t, "node-2", nodeID)
+
+	_, ok = service.RelayedJobOrigin("job-1")
+	assert.False(t, ok, "result is sent only once")
+}
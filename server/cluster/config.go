@@ -0,0 +1,61 @@
+package cluster
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	MinSecretLength      = 32
+	MinHeartbeatInterval = time.Second
+)
+
+type Config struct {
+	Enabled bool `mapstructure:"enabled"`
+	// NodeID identifies this node, it must be unique within the cluster.
+	NodeID string `mapstructure:"node_id"`
+	// NodeURL is the base URL other nodes use to reach the API of this node.
+	NodeURL string `mapstructure:"node_url"`
+	// Secret is shared by all nodes to authenticate the requests they send to each other.
+	Secret            string        `mapstructure:"secret"`
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
+	// NodeTimeout is the time after which a node that didn't send a heartbeat is considered dead.
+	NodeTimeout time.Duration `mapstructure:"node_timeout"`
+}
+
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.NodeID == "" {
+		return errors.New("cluster.node_id must be set when clustering is enabled")
+	}
+
+	if c.NodeURL == "" {
+		return errors.New("cluster.node_url must be set when clustering is enabled")
+	}
+	u, err := url.Parse(c.NodeURL)
+	if err != nil {
+		return fmt.Errorf("invalid cluster.node_url: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid cluster.node_url: expected an http or https URL, got %q", c.NodeURL)
+	}
+
+	if len(c.Secret) < MinSecretLength {
+		return fmt.Errorf("invalid cluster.secret: must be at least %d characters long", MinSecretLength)
+	}
+
+	if c.HeartbeatInterval < MinHeartbeatInterval {
+		return fmt.Errorf("invalid cluster.heartbeat_interval: must be at least %s, got %s", MinHeartbeatInterval, c.HeartbeatInterval)
+	}
+
+	if c.NodeTimeout <= c.HeartbeatInterval {
+		return fmt.Errorf("invalid cluster.node_timeout: must be greater than cluster.heartbeat_interval, got %s", c.NodeTimeout)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package cluster
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"path"
+
+	"github.com/cloudradar-monitoring/rport/share/security"
+)
+
+// Forward proxies an API request to the node a client is connected to. The credentials and the address of the caller
+// are kept, the node authenticates the user on its own. Websocket connections are supported.
+func (s *Service) Forward(w http.ResponseWriter, req *http.Request, node *Node, onError func(http.ResponseWriter, error)) {
+	target, err := url.Parse(node.URL)
+	if err != nil {
+		onError(w, err)
+		return
+	}
+
+	// the body and nonce are prepared in advance, the director can't fail
+	bodyHash, err := hashBody(req)
+	if err != nil {
+		onError(w, err)
+		return
+	}
+	nonce, err := security.NewRandomToken(nonceLength)
+	if err != nil {
+		onError(w, err)
+		return
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Director: func(out *http.Request) {
+			out.URL.Scheme = target.Scheme
+			out.URL.Host = target.Host
+			out.URL.Path = path.Join("/", target.Path, out.URL.Path)
+			out.URL.RawPath = ""
+			out.Host = target.Host
+			out.Header.Set(HeaderPeerAddr, req.RemoteAddr)
+			s.sign(out, node.ID, bodyHash, nonce)
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			onError(w, err)
+		},
+	}
+
+	s.logger.Debugf("Forwarding %s %s to cluster node %q.", req.Method, req.URL.Path, node.ID)
+	proxy.ServeHTTP(w, req)
+}
@@ -0,0 +1,169 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/cloudradar-monitoring/rport/server/routes"
+)
+
+const relayTimeout = 2 * time.Minute
+
+// ClientRequest is an ssh request relayed to a client connected to another node
+type ClientRequest struct {
+	ClientID  string `json:"client_id"`
+	Type      string `json:"type"`
+	WantReply bool   `json:"want_reply"`
+	Payload   []byte `json:"payload"`
+}
+
+// ClientResponse is the reply of a client to a relayed ssh request
+type ClientResponse struct {
+	OK      bool   `json:"ok"`
+	Payload []byte `json:"payload"`
+}
+
+// RemoteConn is the connection of a client connected to another node of the cluster. Requests are relayed to the
+// node, channels can't be opened, so API requests using them must be forwarded to the node instead.
+type RemoteConn struct {
+	service  *Service
+	node     *Node
+	clientID string
+}
+
+var _ ssh.Conn = &RemoteConn{}
+
+func (s *Service) NewRemoteConn(node *Node, clientID string) *RemoteConn {
+	return &RemoteConn{
+		service:  s,
+		node:     node,
+		clientID: clientID,
+	}
+}
+
+func (c *RemoteConn) SendRequest(name string, wantReply bool, payload []byte) (bool, []byte, error) {
+	resp := &ClientResponse{}
+	err := c.service.post(context.Background(), c.node, routes.ClusterClientRequestsRoute, &ClientRequest{
+		ClientID:  c.clientID,
+		Type:      name,
+		WantReply: wantReply,
+		Payload:   payload,
+	}, resp)
+	if err != nil {
+		return false, nil, err
+	}
+	return resp.OK, resp.Payload, nil
+}
+
+func (c *RemoteConn) OpenChannel(name string, data []byte) (ssh.Channel, <-chan *ssh.Request, error) {
+	return nil, nil, fmt.Errorf("client %q is connected to cluster node %q, channels can't be relayed", c.clientID, c.node.ID)
+}
+
+// Close does nothing, the connection is closed by the node the client is connected to
+func (c *RemoteConn) Close() error {
+	return nil
+}
+
+func (c *RemoteConn) Wait() error {
+	return nil
+}
+
+func (c *RemoteConn) User() string {
+	return ""
+}
+
+func (c *RemoteConn) SessionID() []byte {
+	return nil
+}
+
+func (c *RemoteConn) ClientVersion() []byte {
+	return nil
+}
+
+func (c *RemoteConn) ServerVersion() []byte {
+	return nil
+}
+
+func (c *RemoteConn) RemoteAddr() net.Addr {
+	return nodeAddr(c.node.URL)
+}
+
+func (c *RemoteConn) LocalAddr() net.Addr {
+	return nodeAddr(c.service.config.NodeURL)
+}
+
+type nodeAddr string
+
+func (a nodeAddr) Network() string {
+	return "cluster"
+}
+
+func (a nodeAddr) String() string {
+	return string(a)
+}
+
+// SendJobResult sends the result of a job to the node that started it
+func (s *Service) SendJobResult(ctx context.Context, nodeID string, result json.RawMessage) error {
+	node := s.Node(nodeID)
+	if node == nil {
+		return fmt.Errorf("cluster node %q is gone", nodeID)
+	}
+
+	return s.post(ctx, node, routes.ClusterJobResultsRoute, result, nil)
+}
+
+func (s *Service) post(ctx context.Context, node *Node, route string, body, dest interface{}) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	u := strings.TrimSuffix(node.URL, "/") + routes.AllRoutesPrefix + route
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := s.Sign(req, node.ID); err != nil {
+		return err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to cluster node %q: %v", node.ID, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response of cluster node %q: %v", node.ID, err)
+	}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("cluster node %q responded with status %d: %s", node.ID, resp.StatusCode, respBody)
+	}
+
+	if dest == nil {
+		return nil
+	}
+
+	// the API wraps all responses in a data field
+	payload := struct {
+		Data interface{} `json:"data"`
+	}{
+		Data: dest,
+	}
+	if err := json.Unmarshal(respBody, &payload); err != nil {
+		return fmt.Errorf("invalid response of cluster node %q: %v", node.ID, err)
+	}
+
+	return nil
+}
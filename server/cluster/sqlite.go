@@ -0,0 +1,111 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	clustermigration "github.com/cloudradar-monitoring/rport/db/migration/cluster"
+)
+
+type Provider interface {
+	SaveNode(ctx context.Context, node *Node) error
+	GetNodes(ctx context.Context) ([]*Node, error)
+	SetClientOwner(ctx context.Context, owner *ClientOwner) error
+	DeleteClientOwner(ctx context.Context, clientID, nodeID string) error
+	GetClientOwner(ctx context.Context, clientID string) (*ClientOwner, error)
+	GetClientOwners(ctx context.Context) ([]*ClientOwner, error)
+	Close() error
+}
+
+type SqliteProvider struct {
+	db *sqlx.DB
+}
+
+func NewSqliteProvider(dbPath string, dbOptions database.Options) (*SqliteProvider, error) {
+	db, err := database.Open("cluster", dbPath, clustermigration.AssetNames(), clustermigration.Asset, dbOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cluster DB instance: %v", err)
+	}
+
+	return &SqliteProvider{
+		db: db,
+	}, nil
+}
+
+func (p *SqliteProvider) SaveNode(ctx context.Context, node *Node) error {
+	_, err := p.db.NamedExecContext(ctx,
+		`INSERT INTO cluster_nodes (id, url, heartbeat_at) VALUES (:id, :url, :heartbeat_at)
+		ON CONFLICT (id) DO UPDATE SET url = excluded.url, heartbeat_at = excluded.heartbeat_at`,
+		node,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to save cluster node: %w", err)
+	}
+
+	return nil
+}
+
+func (p *SqliteProvider) GetNodes(ctx context.Context) ([]*Node, error) {
+	values := []*Node{}
+	err := p.db.SelectContext(ctx, &values, "SELECT * FROM cluster_nodes ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("unable to get cluster nodes: %w", err)
+	}
+
+	return values, nil
+}
+
+// SetClientOwner stores the node a client is connected to, it replaces the previous owner
+func (p *SqliteProvider) SetClientOwner(ctx context.Context, owner *ClientOwner) error {
+	_, err := p.db.NamedExecContext(ctx,
+		`INSERT INTO cluster_clients (client_id, node_id, connected_at) VALUES (:client_id, :node_id, :connected_at)
+		ON CONFLICT (client_id) DO UPDATE SET node_id = excluded.node_id, connected_at = excluded.connected_at`,
+		owner,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to save cluster client owner: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteClientOwner removes the owner of a client only if it's still the given node
+func (p *SqliteProvider) DeleteClientOwner(ctx context.Context, clientID, nodeID string) error {
+	_, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM cluster_clients WHERE client_id = ? AND node_id = ?"), clientID, nodeID)
+	if err != nil {
+		return fmt.Errorf("unable to delete cluster client owner: %w", err)
+	}
+
+	return nil
+}
+
+func (p *SqliteProvider) GetClientOwner(ctx context.Context, clientID string) (*ClientOwner, error) {
+	owner := &ClientOwner{}
+	err := p.db.GetContext(ctx, owner, p.db.Rebind("SELECT * FROM cluster_clients WHERE client_id = ?"), clientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to get cluster client owner: %w", err)
+	}
+
+	return owner, nil
+}
+
+func (p *SqliteProvider) GetClientOwners(ctx context.Context) ([]*ClientOwner, error) {
+	values := []*ClientOwner{}
+	err := p.db.SelectContext(ctx, &values, "SELECT * FROM cluster_clients")
+	if err != nil {
+		return nil, fmt.Errorf("unable to get cluster client owners: %w", err)
+	}
+
+	return values, nil
+}
+
+func (p *SqliteProvider) Close() error {
+	return p.db.Close()
+}
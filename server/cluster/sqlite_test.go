@@ -0,0 +1,55 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+func TestSqliteProvider(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		p, err := NewSqliteProvider(":memory:", database.Options{PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		defer p.Close()
+
+		heartbeat := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, p.SaveNode(ctx, &Node{ID: "node-2", URL: "http://node-2:3000", HeartbeatAt: heartbeat}))
+		require.NoError(t, p.SaveNode(ctx, &Node{ID: "node-1", URL: "http://node-1:3000", HeartbeatAt: heartbeat}))
+		require.NoError(t, p.SaveNode(ctx, &Node{ID: "node-1", URL: "http://node-1:4000", HeartbeatAt: heartbeat.Add(time.Minute)}))
+
+		nodes, err := p.GetNodes(ctx)
+		require.NoError(t, err)
+		require.Len(t, nodes, 2)
+		assert.Equal(t, "node-1", nodes[0].ID)
+		assert.Equal(t, "http://node-1:4000", nodes[0].URL)
+		assert.True(t, heartbeat.Add(time.Minute).Equal(nodes[0].HeartbeatAt))
+		assert.Equal(t, "node-2", nodes[1].ID)
+
+		require.NoError(t, p.SetClientOwner(ctx, &ClientOwner{ClientID: "client-1", NodeID: "node-1", ConnectedAt: heartbeat}))
+		require.NoError(t, p.SetClientOwner(ctx, &ClientOwner{ClientID: "client-1", NodeID: "node-2", ConnectedAt: heartbeat.Add(time.Hour)}))
+
+		owner, err := p.GetClientOwner(ctx, "client-1")
+		require.NoError(t, err)
+		require.NotNil(t, owner)
+		assert.Equal(t, "node-2", owner.NodeID)
+		assert.True(t, heartbeat.Add(time.Hour).Equal(owner.ConnectedAt))
+
+		// the previous owner must not remove the client of the new one
+		require.NoError(t, p.DeleteClientOwner(ctx, "client-1", "node-1"))
+		owners, err := p.GetClientOwners(ctx)
+		require.NoError(t, err)
+		assert.Len(t, owners, 1)
+
+		require.NoError(t, p.DeleteClientOwner(ctx, "client-1", "node-2"))
+		owner, err = p.GetClientOwner(ctx, "client-1")
+		require.NoError(t, err)
+		assert.Nil(t, owner)
+	})
+}
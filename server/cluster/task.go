@@ -0,0 +1,37 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+type SyncTask struct {
+	log     *logger.Logger
+	service *Service
+	onSync  func(ctx context.Context) error
+}
+
+// NewSyncTask returns a task that sends the heartbeat of this node and then calls onSync to apply the refreshed
+// state of the other nodes
+func NewSyncTask(log *logger.Logger, service *Service, onSync func(ctx context.Context) error) *SyncTask {
+	return &SyncTask{
+		log:     log,
+		service: service,
+		onSync:  onSync,
+	}
+}
+
+func (t *SyncTask) Run(ctx context.Context) error {
+	if err := t.service.Heartbeat(ctx); err != nil {
+		return fmt.Errorf("failed to send cluster heartbeat: %v", err)
+	}
+
+	if err := t.onSync(ctx); err != nil {
+		return fmt.Errorf("failed to sync cluster state: %v", err)
+	}
+
+	t.log.Debugf("cluster.SyncTask: %d clients connected to other nodes", len(t.service.RemoteClients()))
+	return nil
+}
@@ -0,0 +1,97 @@
+package chserver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudradar-monitoring/rport/server/cgroups"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/configprofiles"
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/comm"
+)
+
+// pushConfigProfiles sends the configuration profiles assigned to the client groups of a client to the client
+func (s *Server) pushConfigProfiles(ctx context.Context, client *clients.Client) error {
+	if !acceptsConfigProfiles(client) {
+		return nil
+	}
+
+	groups, err := s.clientGroupProvider.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get client groups: %v", err)
+	}
+	profiles, err := s.configProfiles.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get config profiles: %v", err)
+	}
+
+	return s.pushConfigProfilesToClient(client, groups, profiles)
+}
+
+// pushConfigProfilesToAll sends the configuration profiles to all active clients, it's called after profiles
+// or client groups changed
+func (s *Server) pushConfigProfilesToAll(ctx context.Context) {
+	var targets []*clients.Client
+	for _, client := range s.clientService.GetRepo().GetAllActive() {
+		if acceptsConfigProfiles(client) {
+			targets = append(targets, client)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	groups, err := s.clientGroupProvider.GetAll(ctx)
+	if err != nil {
+		s.Errorf("Failed to push config profiles: failed to get client groups: %v", err)
+		return
+	}
+	profiles, err := s.configProfiles.GetAll(ctx)
+	if err != nil {
+		s.Errorf("Failed to push config profiles: failed to get config profiles: %v", err)
+		return
+	}
+
+	for _, client := range targets {
+		if err := s.pushConfigProfilesToClient(client, groups, profiles); err != nil {
+			s.Errorf("Failed to push config profiles to client %s: %v", client.ID, err)
+		}
+	}
+}
+
+// pushConfigProfilesToClient sends the merged options of the profiles matching the client to the client, it replies
+// with its effective configuration. Clients not accepting managed config are skipped, as well as clients without
+// profiles before and after the push.
+func (s *Server) pushConfigProfilesToClient(client *clients.Client, groups []*cgroups.ClientGroup, profiles []*configprofiles.Profile) error {
+	if !acceptsConfigProfiles(client) {
+		return nil
+	}
+
+	var groupIDs []string
+	for _, group := range groups {
+		if client.BelongsTo(group) {
+			groupIDs = append(groupIDs, group.ID)
+		}
+	}
+	profileIDs, options := configprofiles.Merge(profiles, groupIDs)
+	if len(profileIDs) == 0 && len(client.ClientConfiguration.ManagedConfig.Profiles) == 0 {
+		return nil
+	}
+
+	effective := &clientconfig.Config{}
+	err := comm.SendRequestAndGetResponse(client.Connection, comm.RequestTypePutManagedConfig, &comm.ManagedConfigRequest{
+		Profiles: profileIDs,
+		Options:  options,
+	}, effective)
+	if err != nil {
+		return err
+	}
+
+	s.Debugf("Config profiles %v pushed to client %s.", profileIDs, client.ID)
+	return s.clientService.SetClientConfiguration(client.ID, effective)
+}
+
+func acceptsConfigProfiles(client *clients.Client) bool {
+	return client.ClientConfiguration != nil && client.ClientConfiguration.ManagedConfig.Enabled
+}
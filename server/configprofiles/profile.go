@@ -0,0 +1,56 @@
+package configprofiles
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/types"
+)
+
+// Profile is a set of client options the server pushes to the clients of the assigned client groups.
+type Profile struct {
+	ID          string `json:"id" db:"id"`
+	Description string `json:"description" db:"description"`
+	// ClientGroupIDs are the client groups the profile is assigned to
+	ClientGroupIDs types.StringSlice           `json:"client_group_ids" db:"client_group_ids"`
+	Options        clientconfig.ManagedOptions `json:"options" db:"options"`
+}
+
+func (p *Profile) Validate() error {
+	if p.ID == "" {
+		return errors.New("profile ID cannot be empty")
+	}
+	return p.Options.Validate()
+}
+
+// Merge returns the ids and the merged options of the profiles assigned to one of the given client groups.
+// Profiles are merged ordered by id, options of later profiles override earlier ones.
+func Merge(profiles []*Profile, clientGroupIDs []string) ([]string, clientconfig.ManagedOptions) {
+	groups := make(map[string]bool, len(clientGroupIDs))
+	for _, id := range clientGroupIDs {
+		groups[id] = true
+	}
+
+	var matching []*Profile
+	for _, p := range profiles {
+		for _, groupID := range p.ClientGroupIDs {
+			if groups[groupID] {
+				matching = append(matching, p)
+				break
+			}
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].ID < matching[j].ID
+	})
+
+	ids := make([]string, 0, len(matching))
+	options := make([]clientconfig.ManagedOptions, 0, len(matching))
+	for _, p := range matching {
+		ids = append(ids, p.ID)
+		options = append(options, p.Options)
+	}
+
+	return ids, clientconfig.MergeManagedOptions(options...)
+}
@@ -0,0 +1,77 @@
+package configprofiles
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+func TestMerge(t *testing.T) {
+	profiles := []*Profile{
+		{
+			ID:             "b-linux",
+			ClientGroupIDs: []string{"linux"},
+			Options:        clientconfig.ManagedOptions{"monitoring.enabled": false},
+		},
+		{
+			ID:             "a-default",
+			ClientGroupIDs: []string{"all", "linux"},
+			Options:        clientconfig.ManagedOptions{"monitoring.enabled": true, "remote-scripts.enabled": true},
+		},
+		{
+			ID:             "c-windows",
+			ClientGroupIDs: []string{"windows"},
+			Options:        clientconfig.ManagedOptions{"remote-terminal.enabled": true},
+		},
+	}
+
+	ids, options := Merge(profiles, []string{"all", "linux"})
+
+	assert.Equal(t, []string{"a-default", "b-linux"}, ids)
+	assert.Equal(t, clientconfig.ManagedOptions{"monitoring.enabled": false, "remote-scripts.enabled": true}, options)
+
+	ids, options = Merge(profiles, nil)
+
+	assert.Empty(t, ids)
+	assert.Empty(t, options)
+}
+
+func TestSqliteProvider(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		p, err := NewSqliteProvider(":memory:", database.Options{PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		defer p.Close()
+
+		profile := &Profile{
+			ID:             "default",
+			Description:    "default profile",
+			ClientGroupIDs: []string{"all"},
+			Options:        clientconfig.ManagedOptions{"client.tags": []interface{}{"managed"}},
+		}
+		require.NoError(t, p.Save(ctx, profile))
+
+		got, err := p.Get(ctx, "default")
+		require.NoError(t, err)
+		assert.Equal(t, profile, got)
+
+		profile.Options = clientconfig.ManagedOptions{"monitoring.enabled": true}
+		require.NoError(t, p.Save(ctx, profile))
+
+		all, err := p.GetAll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []*Profile{profile}, all)
+
+		require.NoError(t, p.Delete(ctx, "default"))
+
+		got, err = p.Get(ctx, "default")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
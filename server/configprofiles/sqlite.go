@@ -0,0 +1,74 @@
+package configprofiles
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	configprofilesmigration "github.com/cloudradar-monitoring/rport/db/migration/config_profiles"
+)
+
+type Provider interface {
+	Get(ctx context.Context, id string) (*Profile, error)
+	GetAll(ctx context.Context) ([]*Profile, error)
+	Save(ctx context.Context, profile *Profile) error
+	Delete(ctx context.Context, id string) error
+	Close() error
+}
+
+type SqliteProvider struct {
+	db *sqlx.DB
+}
+
+func NewSqliteProvider(dbPath string, dbOptions database.Options) (*SqliteProvider, error) {
+	db, err := database.Open("config_profiles", dbPath, configprofilesmigration.AssetNames(), configprofilesmigration.Asset, dbOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config_profiles DB instance: %v", err)
+	}
+
+	return &SqliteProvider{db: db}, nil
+}
+
+func (p *SqliteProvider) Get(ctx context.Context, id string) (*Profile, error) {
+	res := &Profile{}
+	err := p.db.GetContext(ctx, res, p.db.Rebind("SELECT * FROM config_profiles WHERE id = ?"), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *SqliteProvider) GetAll(ctx context.Context) ([]*Profile, error) {
+	res := []*Profile{}
+	err := p.db.SelectContext(ctx, &res, "SELECT * FROM config_profiles ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Save creates the profile or replaces an existing one with the same id
+func (p *SqliteProvider) Save(ctx context.Context, profile *Profile) error {
+	_, err := p.db.NamedExecContext(
+		ctx,
+		"INSERT INTO config_profiles (id, description, client_group_ids, options) VALUES (:id, :description, :client_group_ids, :options)"+
+			" ON CONFLICT(id) DO UPDATE SET description = excluded.description, client_group_ids = excluded.client_group_ids, options = excluded.options",
+		profile,
+	)
+	return err
+}
+
+func (p *SqliteProvider) Delete(ctx context.Context, id string) error {
+	_, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM config_profiles WHERE id = ?"), id)
+	return err
+}
+
+func (p *SqliteProvider) Close() error {
+	return p.db.Close()
+}
@@ -0,0 +1,33 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+type CleanupTask struct {
+	log     *logger.Logger
+	service *Service
+	period  time.Duration
+}
+
+// NewCleanupTask returns a task to delete the inventory changes after configured period
+func NewCleanupTask(log *logger.Logger, service *Service, period time.Duration) *CleanupTask {
+	return &CleanupTask{
+		log:     log,
+		service: service,
+		period:  period,
+	}
+}
+
+func (t *CleanupTask) Run(ctx context.Context) error {
+	deleted, err := t.service.DeleteChangesOlderThan(ctx, t.period)
+	if err != nil {
+		return fmt.Errorf("failed to cleanup inventory changes: %v", err)
+	}
+	t.log.Debugf("inventory.CleanupTask: %d inventory changes deleted", deleted)
+	return nil
+}
@@ -0,0 +1,109 @@
+package inventory
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+const (
+	ActionAdded   = "added"
+	ActionRemoved = "removed"
+	ActionChanged = "changed"
+)
+
+// Change is a difference between two consecutive inventories of a client
+type Change struct {
+	Timestamp time.Time `json:"timestamp" db:"timestamp"`
+	ClientID  string    `json:"client_id" db:"client_id"`
+	Category  string    `json:"category" db:"category"`
+	Name      string    `json:"name" db:"name"`
+	Action    string    `json:"action" db:"action"`
+	OldValue  string    `json:"old_value" db:"old_value"`
+	NewValue  string    `json:"new_value" db:"new_value"`
+}
+
+type itemKey struct {
+	category string
+	name     string
+}
+
+// Diff compares the items of two inventories, items are identified by category and name.
+// Items with the same key, e.g. a package installed in several versions, are compared by all their values.
+func Diff(oldItems, newItems []models.InventoryItem) []*Change {
+	oldValues := groupValues(oldItems)
+	newValues := groupValues(newItems)
+
+	var changes []*Change
+	for key, newValue := range newValues {
+		oldValue, ok := oldValues[key]
+		switch {
+		case !ok:
+			changes = append(changes, newChange(key, ActionAdded, "", newValue))
+		case oldValue != newValue:
+			changes = append(changes, newChange(key, ActionChanged, oldValue, newValue))
+		}
+	}
+	for key, oldValue := range oldValues {
+		if _, ok := newValues[key]; !ok {
+			changes = append(changes, newChange(key, ActionRemoved, oldValue, ""))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Category != changes[j].Category {
+			return changes[i].Category < changes[j].Category
+		}
+		return changes[i].Name < changes[j].Name
+	})
+
+	return changes
+}
+
+// keepFailedCategories replaces the items of the categories the client failed to collect by the previous ones,
+// otherwise a temporary failure would be recorded as all items of the category being removed.
+func keepFailedCategories(oldItems, newItems []models.InventoryItem, failed map[string]bool) []models.InventoryItem {
+	if len(failed) == 0 {
+		return newItems
+	}
+
+	items := make([]models.InventoryItem, 0, len(newItems))
+	for _, item := range newItems {
+		if !failed[item.Category] {
+			items = append(items, item)
+		}
+	}
+	for _, item := range oldItems {
+		if failed[item.Category] {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func groupValues(items []models.InventoryItem) map[itemKey]string {
+	grouped := make(map[itemKey][]string)
+	for _, item := range items {
+		key := itemKey{category: item.Category, name: item.Name}
+		grouped[key] = append(grouped[key], item.Value)
+	}
+
+	values := make(map[itemKey]string, len(grouped))
+	for key, v := range grouped {
+		sort.Strings(v)
+		values[key] = strings.Join(v, ", ")
+	}
+	return values
+}
+
+func newChange(key itemKey, action, oldValue, newValue string) *Change {
+	return &Change{
+		Category: key.category,
+		Name:     key.name,
+		Action:   action,
+		OldValue: oldValue,
+		NewValue: newValue,
+	}
+}
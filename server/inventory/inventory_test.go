@@ -0,0 +1,42 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+func TestDiff(t *testing.T) {
+	oldItems := []models.InventoryItem{
+		{Category: "package", Name: "openssl", Value: "1.1.1"},
+		{Category: "package", Name: "bash", Value: "5.0"},
+		{Category: "package", Name: "linux-image", Value: "5.15.0-1"},
+		{Category: "package", Name: "linux-image", Value: "5.15.0-2"},
+		{Category: "service", Name: "cron", Value: "running"},
+	}
+	newItems := []models.InventoryItem{
+		{Category: "package", Name: "openssl", Value: "3.0.2"},
+		{Category: "package", Name: "bash", Value: "5.0"},
+		{Category: "package", Name: "linux-image", Value: "5.15.0-2"},
+		{Category: "package", Name: "linux-image", Value: "5.15.0-1"},
+		{Category: "service", Name: "ssh", Value: "running"},
+	}
+
+	changes := Diff(oldItems, newItems)
+
+	assert.Equal(t, []*Change{
+		{Category: "package", Name: "openssl", Action: ActionChanged, OldValue: "1.1.1", NewValue: "3.0.2"},
+		{Category: "service", Name: "cron", Action: ActionRemoved, OldValue: "running"},
+		{Category: "service", Name: "ssh", Action: ActionAdded, NewValue: "running"},
+	}, changes)
+}
+
+func TestDiffUnchanged(t *testing.T) {
+	items := []models.InventoryItem{
+		{Category: "nic", Name: "eth0", Value: "00:0c:29:aa:bb:cc"},
+	}
+
+	assert.Empty(t, Diff(items, items))
+}
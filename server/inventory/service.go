@@ -0,0 +1,205 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+var (
+	supportedItemFilters = map[string]bool{
+		"client_id": true,
+		"category":  true,
+		"name":      true,
+		"value":     true,
+	}
+	supportedItemSorts = map[string]bool{
+		"client_id": true,
+		"category":  true,
+		"name":      true,
+		"value":     true,
+	}
+	defaultItemSort = []query.SortOption{
+		{Column: "client_id", IsASC: true},
+		{Column: "category", IsASC: true},
+		{Column: "name", IsASC: true},
+	}
+
+	supportedChangeFilters = map[string]bool{
+		"client_id":        true,
+		"category":         true,
+		"name":             true,
+		"action":           true,
+		"old_value":        true,
+		"new_value":        true,
+		"timestamp[gt]":    true,
+		"timestamp[lt]":    true,
+		"timestamp[since]": true,
+		"timestamp[until]": true,
+	}
+	supportedChangeSorts = map[string]bool{
+		"timestamp": true,
+		"client_id": true,
+		"category":  true,
+		"name":      true,
+	}
+	defaultChangeSort = []query.SortOption{{Column: "timestamp", IsASC: false}}
+
+	paginationConfig = &query.PaginationConfig{
+		DefaultLimit: 50,
+		MaxLimit:     1000,
+	}
+)
+
+// shortcutFilters are filters by the name of an item of a category, e.g. filter[package]=openssl
+var shortcutFilters = map[string]string{
+	"package":  models.InventoryCategoryPackage,
+	"service":  models.InventoryCategoryService,
+	"disk":     models.InventoryCategoryDisk,
+	"nic":      models.InventoryCategoryNIC,
+	"user":     models.InventoryCategoryUser,
+	"hardware": models.InventoryCategoryHardware,
+}
+
+type Service struct {
+	provider Provider
+	now      func() time.Time
+}
+
+func NewService(provider Provider) *Service {
+	return &Service{
+		provider: provider,
+		now:      time.Now,
+	}
+}
+
+// Save stores the inventory sent by a client, the refresh time is set by the server when the client didn't send it
+func (s *Service) Save(ctx context.Context, clientID string, inventory *models.Inventory) error {
+	if inventory.Refreshed.IsZero() {
+		inventory.Refreshed = s.now()
+	}
+	inventory.Refreshed = inventory.Refreshed.UTC()
+
+	return s.provider.Save(ctx, clientID, inventory)
+}
+
+func (s *Service) Get(ctx context.Context, clientID string) (*models.Inventory, error) {
+	inventory, err := s.provider.Get(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if inventory == nil {
+		return nil, errors2.APIError{
+			Message:    fmt.Sprintf("No inventory received from client %q yet.", clientID),
+			HTTPStatus: http.StatusNotFound,
+		}
+	}
+	return inventory, nil
+}
+
+// ListItems lists the inventory items of all clients matching the filters
+func (s *Service) ListItems(ctx context.Context, options *query.ListOptions) (*api.SuccessPayload, error) {
+	options.Filters = expandShortcutFilters(options.Filters, []string{"value"})
+	err := query.ValidateListOptions(options, supportedItemSorts, supportedItemFilters, nil, paginationConfig)
+	if err != nil {
+		return nil, err
+	}
+	if len(options.Sorts) == 0 {
+		options.Sorts = defaultItemSort
+	}
+
+	items, err := s.provider.ListItems(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.provider.CountItems(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.SuccessPayload{
+		Data: items,
+		Meta: api.NewMeta(count),
+	}, nil
+}
+
+// ListChanges lists the recorded inventory changes matching the filters, the latest first
+func (s *Service) ListChanges(ctx context.Context, options *query.ListOptions) (*api.SuccessPayload, error) {
+	options.Filters = expandShortcutFilters(options.Filters, []string{"old_value", "new_value"})
+	err := query.ValidateListOptions(options, supportedChangeSorts, supportedChangeFilters, nil, paginationConfig)
+	if err != nil {
+		return nil, err
+	}
+	if len(options.Sorts) == 0 {
+		options.Sorts = defaultChangeSort
+	}
+
+	changes, err := s.provider.ListChanges(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.provider.CountChanges(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.SuccessPayload{
+		Data: changes,
+		Meta: api.NewMeta(count),
+	}, nil
+}
+
+// DeleteChangesOlderThan deletes the changes recorded before the given period, the current inventories are kept
+func (s *Service) DeleteChangesOlderThan(ctx context.Context, period time.Duration) (int64, error) {
+	return s.provider.DeleteChangesBefore(ctx, s.now().Add(-period).UTC())
+}
+
+func (s *Service) Close() error {
+	return s.provider.Close()
+}
+
+// expandShortcutFilters replaces the shortcut filters by filters on the item columns:
+//   - filter[package]=openssl becomes filter[category]=package&filter[name]=openssl, same for the other categories
+//   - filter[mac]=00:0c:29:* matches the value of nic items
+//   - filter[serial]=ABC123 matches the value of the serial number hardware item
+//
+// valueColumns are the columns holding the item values.
+func expandShortcutFilters(filters []query.FilterOption, valueColumns []string) []query.FilterOption {
+	expanded := make([]query.FilterOption, 0, len(filters))
+	for _, f := range filters {
+		if len(f.Column) != 1 || f.Operator != "" {
+			expanded = append(expanded, f)
+			continue
+		}
+
+		column := f.Column[0]
+		if category, ok := shortcutFilters[column]; ok {
+			expanded = append(expanded, categoryFilter(category), query.FilterOption{Column: []string{"name"}, Values: f.Values})
+			continue
+		}
+
+		switch column {
+		case "mac":
+			expanded = append(expanded, categoryFilter(models.InventoryCategoryNIC), query.FilterOption{Column: valueColumns, Values: f.Values})
+		case "serial":
+			expanded = append(expanded,
+				categoryFilter(models.InventoryCategoryHardware),
+				query.FilterOption{Column: []string{"name"}, Values: []string{"serial_number"}},
+				query.FilterOption{Column: valueColumns, Values: f.Values},
+			)
+		default:
+			expanded = append(expanded, f)
+		}
+	}
+	return expanded
+}
+
+func categoryFilter(category string) query.FilterOption {
+	return query.FilterOption{Column: []string{"category"}, Values: []string{category}}
+}
@@ -0,0 +1,200 @@
+package inventory
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+var testInventory = &models.Inventory{
+	Packages: []models.InventoryPackage{
+		{Name: "bash", Version: "5.1"},
+		{Name: "openssl", Version: "1.1.1"},
+	},
+	Services: []models.InventoryService{
+		{Name: "cron", State: "running"},
+	},
+	NICs: []models.InventoryNIC{
+		{Name: "eth0", MAC: "00:0c:29:aa:bb:cc", Addresses: []string{"192.168.1.10/24"}},
+	},
+	Hardware: models.InventoryHardware{
+		Manufacturer: "ACME",
+		SerialNumber: "ABC123",
+	},
+}
+
+func listOptions(filters ...query.FilterOption) *query.ListOptions {
+	return &query.ListOptions{Filters: filters}
+}
+
+func filter(column string, values ...string) query.FilterOption {
+	return query.FilterOption{Column: []string{column}, Values: values}
+}
+
+func TestSaveAndGet(t *testing.T) {
+	ctx := context.Background()
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	defer dbProvider.Close()
+	service := NewService(dbProvider)
+	service.now = func() time.Time { return time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	_, err = service.Get(ctx, "client-1")
+	assert.Equal(t, errors2.APIError{
+		Message:    `No inventory received from client "client-1" yet.`,
+		HTTPStatus: http.StatusNotFound,
+	}, err)
+
+	saved := *testInventory
+	require.NoError(t, service.Save(ctx, "client-1", &saved))
+
+	inventory, err := service.Get(ctx, "client-1")
+	require.NoError(t, err)
+	expected := *testInventory
+	expected.Refreshed = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Equal(t, &expected, inventory)
+
+	// the first inventory is no change
+	result, err := service.ListChanges(ctx, listOptions())
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Meta.Count)
+}
+
+func TestListItems(t *testing.T) {
+	ctx := context.Background()
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	defer dbProvider.Close()
+	service := NewService(dbProvider)
+	inventory := *testInventory
+	require.NoError(t, service.Save(ctx, "client-1", &inventory))
+	require.NoError(t, service.Save(ctx, "client-2", &models.Inventory{
+		Packages: []models.InventoryPackage{{Name: "openssl", Version: "3.0.2"}},
+	}))
+
+	testCases := []struct {
+		Name     string
+		Filters  []query.FilterOption
+		Expected []*models.InventoryItem
+	}{
+		{
+			Name:    "package",
+			Filters: []query.FilterOption{filter("package", "openssl")},
+			Expected: []*models.InventoryItem{
+				{ClientID: "client-1", Category: "package", Name: "openssl", Value: "1.1.1"},
+				{ClientID: "client-2", Category: "package", Name: "openssl", Value: "3.0.2"},
+			},
+		},
+		{
+			Name:    "package with wildcard and client",
+			Filters: []query.FilterOption{filter("package", "open*"), filter("client_id", "client-2")},
+			Expected: []*models.InventoryItem{
+				{ClientID: "client-2", Category: "package", Name: "openssl", Value: "3.0.2"},
+			},
+		},
+		{
+			Name:    "mac",
+			Filters: []query.FilterOption{filter("mac", "00:0c:29:*")},
+			Expected: []*models.InventoryItem{
+				{ClientID: "client-1", Category: "nic", Name: "eth0", Value: "00:0c:29:aa:bb:cc"},
+			},
+		},
+		{
+			Name:    "serial",
+			Filters: []query.FilterOption{filter("serial", "ABC123")},
+			Expected: []*models.InventoryItem{
+				{ClientID: "client-1", Category: "hardware", Name: "serial_number", Value: "ABC123"},
+			},
+		},
+		{
+			Name:     "no match",
+			Filters:  []query.FilterOption{filter("service", "nginx")},
+			Expected: []*models.InventoryItem{},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := service.ListItems(ctx, listOptions(tc.Filters...))
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.Expected, result.Data)
+			assert.Equal(t, len(tc.Expected), result.Meta.Count)
+		})
+	}
+}
+
+func TestListItemsUnsupportedFilter(t *testing.T) {
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	defer dbProvider.Close()
+	service := NewService(dbProvider)
+
+	_, err = service.ListItems(context.Background(), listOptions(filter("version", "1.0")))
+
+	assert.EqualError(t, err, "unsupported filter field 'filter[version]'")
+}
+
+func TestListChanges(t *testing.T) {
+	ctx := context.Background()
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	defer dbProvider.Close()
+	service := NewService(dbProvider)
+	first := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := first.Add(4 * time.Hour)
+
+	inventory := *testInventory
+	inventory.Refreshed = first
+	require.NoError(t, service.Save(ctx, "client-1", &inventory))
+
+	inventory.Refreshed = second
+	inventory.Packages = []models.InventoryPackage{
+		{Name: "bash", Version: "5.1"},
+		{Name: "openssl", Version: "3.0.2"},
+		{Name: "curl", Version: "7.81"},
+	}
+	inventory.Services = nil
+	require.NoError(t, service.Save(ctx, "client-1", &inventory))
+
+	result, err := service.ListChanges(ctx, listOptions(filter("client_id", "client-1")))
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Meta.Count)
+	assert.ElementsMatch(t, []*Change{
+		{Timestamp: second, ClientID: "client-1", Category: "package", Name: "curl", Action: ActionAdded, NewValue: "7.81"},
+		{Timestamp: second, ClientID: "client-1", Category: "package", Name: "openssl", Action: ActionChanged, OldValue: "1.1.1", NewValue: "3.0.2"},
+		{Timestamp: second, ClientID: "client-1", Category: "service", Name: "cron", Action: ActionRemoved, OldValue: "running"},
+	}, result.Data)
+
+	result, err = service.ListChanges(ctx, listOptions(filter("package", "openssl")))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Meta.Count)
+
+	items, err := service.ListItems(ctx, listOptions(filter("client_id", "client-1"), filter("category", "package")))
+	require.NoError(t, err)
+	assert.Equal(t, 3, items.Meta.Count)
+
+	// packages failed to be collected, they are not recorded as removed
+	inventory.Refreshed = second.Add(4 * time.Hour)
+	inventory.Packages = nil
+	inventory.Errors = []string{"package: no supported package manager found"}
+	require.NoError(t, service.Save(ctx, "client-1", &inventory))
+
+	result, err = service.ListChanges(ctx, listOptions())
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Meta.Count)
+
+	items, err = service.ListItems(ctx, listOptions(filter("client_id", "client-1"), filter("category", "package")))
+	require.NoError(t, err)
+	assert.Equal(t, 3, items.Meta.Count)
+}
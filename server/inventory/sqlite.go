@@ -0,0 +1,190 @@
+package inventory
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	inventorymigration "github.com/cloudradar-monitoring/rport/db/migration/inventory"
+	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+type Provider interface {
+	Get(ctx context.Context, clientID string) (*models.Inventory, error)
+	Save(ctx context.Context, clientID string, inventory *models.Inventory) error
+	ListItems(ctx context.Context, options *query.ListOptions) ([]*models.InventoryItem, error)
+	CountItems(ctx context.Context, options *query.ListOptions) (int, error)
+	ListChanges(ctx context.Context, options *query.ListOptions) ([]*Change, error)
+	CountChanges(ctx context.Context, options *query.ListOptions) (int, error)
+	DeleteChangesBefore(ctx context.Context, before time.Time) (int64, error)
+	Close() error
+}
+
+type SqliteProvider struct {
+	db        *sqlx.DB
+	converter *query.SQLConverter
+}
+
+func NewSqliteProvider(dbPath string, dbOptions database.Options) (*SqliteProvider, error) {
+	db, err := database.Open("inventory", dbPath, inventorymigration.AssetNames(), inventorymigration.Asset, dbOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inventory DB instance: %v", err)
+	}
+
+	return &SqliteProvider{
+		db:        db,
+		converter: query.NewSQLConverter(db.DriverName()),
+	}, nil
+}
+
+func (p *SqliteProvider) Get(ctx context.Context, clientID string) (*models.Inventory, error) {
+	var data string
+	err := p.db.GetContext(ctx, &data, p.db.Rebind("SELECT inventory FROM inventories WHERE client_id = ?"), clientID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	inventory := &models.Inventory{}
+	if err := json.Unmarshal([]byte(data), inventory); err != nil {
+		return nil, fmt.Errorf("failed to decode inventory of client %q: %v", clientID, err)
+	}
+	return inventory, nil
+}
+
+// Save replaces the inventory of a client and records the changes to the previous one.
+// No changes are recorded for the first inventory of a client.
+func (p *SqliteProvider) Save(ctx context.Context, clientID string, inventory *models.Inventory) error {
+	data, err := json.Marshal(inventory)
+	if err != nil {
+		return err
+	}
+
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := p.save(ctx, tx, clientID, inventory, string(data)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (p *SqliteProvider) save(ctx context.Context, tx *sqlx.Tx, clientID string, inventory *models.Inventory, data string) error {
+	var count int
+	err := tx.GetContext(ctx, &count, tx.Rebind("SELECT COUNT(*) FROM inventories WHERE client_id = ?"), clientID)
+	if err != nil {
+		return err
+	}
+
+	newItems := inventory.Items()
+	if count > 0 {
+		oldItems := []models.InventoryItem{}
+		err := tx.SelectContext(ctx, &oldItems, tx.Rebind("SELECT * FROM inventory_items WHERE client_id = ?"), clientID)
+		if err != nil {
+			return err
+		}
+		newItems = keepFailedCategories(oldItems, newItems, inventory.FailedCategories())
+
+		for _, change := range Diff(oldItems, newItems) {
+			change.Timestamp = inventory.Refreshed
+			change.ClientID = clientID
+			_, err := tx.NamedExecContext(ctx,
+				`INSERT INTO inventory_changes (timestamp, client_id, category, name, action, old_value, new_value)
+				VALUES (:timestamp, :client_id, :category, :name, :action, :old_value, :new_value)`,
+				change,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM inventory_items WHERE client_id = ?"), clientID); err != nil {
+		return err
+	}
+	for _, item := range newItems {
+		item.ClientID = clientID
+		_, err := tx.NamedExecContext(ctx,
+			"INSERT INTO inventory_items (client_id, category, name, value) VALUES (:client_id, :category, :name, :value)",
+			item,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		tx.Rebind("INSERT INTO inventories (client_id, refreshed, inventory) VALUES (?, ?, ?)"+
+			" ON CONFLICT(client_id) DO UPDATE SET refreshed = excluded.refreshed, inventory = excluded.inventory"),
+		clientID, inventory.Refreshed, data,
+	)
+	return err
+}
+
+func (p *SqliteProvider) ListItems(ctx context.Context, options *query.ListOptions) ([]*models.InventoryItem, error) {
+	items := []*models.InventoryItem{}
+	q, params := p.converter.ConvertListOptionsToQuery(options, "SELECT * FROM inventory_items")
+	err := p.db.SelectContext(ctx, &items, p.db.Rebind(q), params...)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (p *SqliteProvider) CountItems(ctx context.Context, options *query.ListOptions) (int, error) {
+	return p.count(ctx, "inventory_items", options)
+}
+
+func (p *SqliteProvider) ListChanges(ctx context.Context, options *query.ListOptions) ([]*Change, error) {
+	changes := []*Change{}
+	q, params := p.converter.ConvertListOptionsToQuery(options, "SELECT * FROM inventory_changes")
+	err := p.db.SelectContext(ctx, &changes, p.db.Rebind(q), params...)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (p *SqliteProvider) CountChanges(ctx context.Context, options *query.ListOptions) (int, error) {
+	return p.count(ctx, "inventory_changes", options)
+}
+
+func (p *SqliteProvider) DeleteChangesBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM inventory_changes WHERE timestamp < ?"), before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (p *SqliteProvider) count(ctx context.Context, table string, options *query.ListOptions) (int, error) {
+	var result int
+
+	countOptions := *options
+	countOptions.Pagination = nil
+	countOptions.Sorts = nil
+	q, params := p.converter.ConvertListOptionsToQuery(&countOptions, "SELECT COUNT(*) FROM "+table)
+
+	err := p.db.GetContext(ctx, &result, p.db.Rebind(q), params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (p *SqliteProvider) Close() error {
+	return p.db.Close()
+}
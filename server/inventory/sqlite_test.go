@@ -0,0 +1,84 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+func TestSqliteProvider(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		p, err := NewSqliteProvider(":memory:", database.Options{PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		defer p.Close()
+
+		inventory, err := p.Get(ctx, "client-1")
+		require.NoError(t, err)
+		assert.Nil(t, inventory)
+
+		first := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, p.Save(ctx, "client-1", &models.Inventory{
+			Refreshed: first,
+			Packages:  []models.InventoryPackage{{Name: "bash", Version: "5.1"}, {Name: "openssl", Version: "1.1.1"}},
+		}))
+		second := first.Add(time.Hour)
+		require.NoError(t, p.Save(ctx, "client-1", &models.Inventory{
+			Refreshed: second,
+			Packages:  []models.InventoryPackage{{Name: "bash", Version: "5.1"}, {Name: "openssl", Version: "3.0.2"}},
+		}))
+
+		inventory, err = p.Get(ctx, "client-1")
+		require.NoError(t, err)
+		require.NotNil(t, inventory)
+		assert.True(t, second.Equal(inventory.Refreshed))
+		assert.Equal(t, []models.InventoryPackage{{Name: "bash", Version: "5.1"}, {Name: "openssl", Version: "3.0.2"}}, inventory.Packages)
+
+		options := &query.ListOptions{
+			Filters: []query.FilterOption{{Column: []string{"client_id"}, Values: []string{"client-1"}}},
+			Sorts:   []query.SortOption{{Column: "name", IsASC: true}},
+		}
+		items, err := p.ListItems(ctx, options)
+		require.NoError(t, err)
+		assert.Equal(t, []*models.InventoryItem{
+			{ClientID: "client-1", Category: "package", Name: "bash", Value: "5.1"},
+			{ClientID: "client-1", Category: "package", Name: "openssl", Value: "3.0.2"},
+		}, items)
+		count, err := p.CountItems(ctx, options)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		changes, err := p.ListChanges(ctx, options)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.True(t, second.Equal(changes[0].Timestamp))
+		assert.Equal(t, "openssl", changes[0].Name)
+		assert.Equal(t, ActionChanged, changes[0].Action)
+		assert.Equal(t, "1.1.1", changes[0].OldValue)
+		assert.Equal(t, "3.0.2", changes[0].NewValue)
+		count, err = p.CountChanges(ctx, options)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		deleted, err := p.DeleteChangesBefore(ctx, second)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, deleted)
+		deleted, err = p.DeleteChangesBefore(ctx, second.Add(time.Minute))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+		count, err = p.CountChanges(ctx, options)
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+		count, err = p.CountItems(ctx, options)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	clients := NewGauge("rport_clients", "Number of clients by connection state")
+	clients.Add(2, "state", "connected")
+	clients.Add(1, "state", "disconnected")
+
+	cpu := NewGauge("rport_client_cpu_usage_percent", "CPU usage")
+	cpu.Add(12.5, "client_id", "client-1", "client_name", `my "test"\client`)
+	cpu.Add(math.NaN(), "client_id", "client-2", "client_name", "line\nbreak")
+
+	sent := NewCounter("rport_tunnel_sent_bytes_total", "Bytes sent\nvia tunnels")
+	sent.Add(1024)
+
+	empty := NewGauge("rport_empty", "Metric without samples")
+
+	buf := &bytes.Buffer{}
+	err := Write(buf, clients, cpu, sent, empty)
+	require.NoError(t, err)
+
+	expected := `# HELP rport_clients Number of clients by connection state
+# TYPE rport_clients gauge
+rport_clients{state="connected"} 2
+rport_clients{state="disconnected"} 1
+# HELP rport_client_cpu_usage_percent CPU usage
+# TYPE rport_client_cpu_usage_percent gauge
+rport_client_cpu_usage_percent{client_id="client-1",client_name="my \"test\"\\client"} 12.5
+rport_client_cpu_usage_percent{client_id="client-2",client_name="line\nbreak"} NaN
+# HELP rport_tunnel_sent_bytes_total Bytes sent\nvia tunnels
+# TYPE rport_tunnel_sent_bytes_total counter
+rport_tunnel_sent_bytes_total 1024
+`
+	assert.Equal(t, expected, buf.String())
+}
@@ -16,6 +16,9 @@ type DBProviderMock struct {
 	MetricsListPayload           []*ClientMetricsPayload
 	ProcessesListPayload         []*ClientProcessesPayload
 	MountpointsListPayload       []*ClientMountpointsPayload
+	ExportedMeasurements         []*ExportedMeasurement
+	CheckResultsListPayload      []*ClientCheckResultPayload
+	CheckResults                 []*models.CheckResult
 }
 
 func (p *DBProviderMock) CountByClientID(ctx context.Context, clientID string, fo *query.ListOptions) (int, error) {
@@ -42,6 +45,32 @@ func (p *DBProviderMock) ListGraphMetricsByClientID(ctx context.Context, clientI
 	return p.GraphMetricsListPayload, nil
 }
 
+func (p *DBProviderMock) StreamMeasurements(ctx context.Context, clientIDs []string, lo *query.ListOptions, fn func(*ExportedMeasurement) error) error {
+	for _, m := range p.ExportedMeasurements {
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *DBProviderMock) CreateCheckResults(ctx context.Context, clientID string, results []*models.CheckResult) error {
+	p.CheckResults = append(p.CheckResults, results...)
+	return nil
+}
+
+func (p *DBProviderMock) ListChecksByClientID(ctx context.Context, clientID string, o *query.ListOptions) ([]*ClientCheckResultPayload, error) {
+	return p.CheckResultsListPayload, nil
+}
+
+func (p *DBProviderMock) CountChecksByClientID(ctx context.Context, clientID string, o *query.ListOptions) (int, error) {
+	return len(p.CheckResultsListPayload), nil
+}
+
+func (p *DBProviderMock) ListCheckGraphByClientID(ctx context.Context, clientID string, name string, hours float64, o *query.ListOptions) ([]*ClientGraphMetricsGraphPayload, error) {
+	return p.GraphMetricsGraphListPayload, nil
+}
+
 func (p *DBProviderMock) CreateMeasurement(ctx context.Context, measurement *models.Measurement) error {
 	return nil
 }
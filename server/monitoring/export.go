@@ -0,0 +1,220 @@
+package monitoring
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/server/metrics"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV         ExportFormat = "csv"
+	ExportFormatInflux      ExportFormat = "influx"
+	ExportFormatOpenMetrics ExportFormat = "openmetrics"
+)
+
+var ExportContentTypes = map[ExportFormat]string{
+	ExportFormatCSV:         "text/csv; charset=utf-8",
+	ExportFormatInflux:      "text/plain; charset=utf-8",
+	ExportFormatOpenMetrics: metrics.OpenMetricsContentType,
+}
+
+var ExportFileExtensions = map[ExportFormat]string{
+	ExportFormatCSV:         ".csv",
+	ExportFormatInflux:      ".lp",
+	ExportFormatOpenMetrics: ".txt",
+}
+
+var ExportSortFields = map[string]bool{}
+
+const influxMeasurementName = "rport_client"
+
+var exportCSVHeader = []string{
+	"client_id", "timestamp", "cpu_usage_percent", "memory_usage_percent", "io_usage_percent",
+	"net_lan_in", "net_lan_out", "net_wan_in", "net_wan_out",
+}
+
+func exportCSV(ctx context.Context, w *bufio.Writer, provider DBProvider, clientIDs []string, lo *query.ListOptions) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return err
+	}
+
+	err := provider.StreamMeasurements(ctx, clientIDs, lo, func(m *ExportedMeasurement) error {
+		return cw.Write([]string{
+			m.ClientID,
+			m.Timestamp.UTC().Format(time.RFC3339),
+			formatFloat(m.CPUUsagePercent),
+			formatFloat(m.MemoryUsagePercent),
+			formatFloat(m.IOUsagePercent),
+			formatOptionalInt(m.NetLanIn),
+			formatOptionalInt(m.NetLanOut),
+			formatOptionalInt(m.NetWanIn),
+			formatOptionalInt(m.NetWanOut),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+var influxTagReplacer = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+
+// exportInflux writes a line per measurement in the InfluxDB line protocol, see
+// https://docs.influxdata.com/influxdb/v2.0/reference/syntax/line-protocol/
+func exportInflux(ctx context.Context, w *bufio.Writer, provider DBProvider, clientIDs []string, lo *query.ListOptions) error {
+	return provider.StreamMeasurements(ctx, clientIDs, lo, func(m *ExportedMeasurement) error {
+		w.WriteString(influxMeasurementName)
+		w.WriteString(",client_id=")
+		w.WriteString(influxTagReplacer.Replace(m.ClientID))
+		w.WriteString(" cpu_usage_percent=")
+		w.WriteString(formatFloat(m.CPUUsagePercent))
+		w.WriteString(",memory_usage_percent=")
+		w.WriteString(formatFloat(m.MemoryUsagePercent))
+		w.WriteString(",io_usage_percent=")
+		w.WriteString(formatFloat(m.IOUsagePercent))
+		writeInfluxIntField(w, "net_lan_in", m.NetLanIn)
+		writeInfluxIntField(w, "net_lan_out", m.NetLanOut)
+		writeInfluxIntField(w, "net_wan_in", m.NetWanIn)
+		writeInfluxIntField(w, "net_wan_out", m.NetWanOut)
+		w.WriteByte(' ')
+		w.WriteString(strconv.FormatInt(m.Timestamp.UnixNano(), 10))
+		_, err := w.WriteString("\n")
+		return err
+	})
+}
+
+func writeInfluxIntField(w *bufio.Writer, name string, value *int64) {
+	if value == nil {
+		return
+	}
+	w.WriteByte(',')
+	w.WriteString(name)
+	w.WriteByte('=')
+	w.WriteString(strconv.FormatInt(*value, 10))
+	w.WriteByte('i')
+}
+
+// openMetricsSeries is a time series of a single value of the measurements
+type openMetricsSeries struct {
+	labels []string
+	value  func(*ExportedMeasurement) *float64
+}
+
+type openMetricsFamily struct {
+	*metrics.Family
+	series []openMetricsSeries
+}
+
+func floatValue(get func(*ExportedMeasurement) float64) func(*ExportedMeasurement) *float64 {
+	return func(m *ExportedMeasurement) *float64 {
+		v := get(m)
+		return &v
+	}
+}
+
+func optionalIntValue(get func(*ExportedMeasurement) *int64) func(*ExportedMeasurement) *float64 {
+	return func(m *ExportedMeasurement) *float64 {
+		v := get(m)
+		if v == nil {
+			return nil
+		}
+		f := float64(*v)
+		return &f
+	}
+}
+
+var openMetricsFamilies = []openMetricsFamily{
+	{
+		Family: metrics.NewGauge("rport_client_cpu_usage_percent", "CPU usage of the client."),
+		series: []openMetricsSeries{
+			{value: floatValue(func(m *ExportedMeasurement) float64 { return m.CPUUsagePercent })},
+		},
+	},
+	{
+		Family: metrics.NewGauge("rport_client_memory_usage_percent", "Memory usage of the client."),
+		series: []openMetricsSeries{
+			{value: floatValue(func(m *ExportedMeasurement) float64 { return m.MemoryUsagePercent })},
+		},
+	},
+	{
+		Family: metrics.NewGauge("rport_client_io_usage_percent", "IO usage of the client."),
+		series: []openMetricsSeries{
+			{value: floatValue(func(m *ExportedMeasurement) float64 { return m.IOUsagePercent })},
+		},
+	},
+	{
+		Family: metrics.NewGauge("rport_client_net_bytes_per_second", "Network throughput of the client."),
+		series: []openMetricsSeries{
+			{
+				labels: []string{"interface", "lan", "direction", "in"},
+				value:  optionalIntValue(func(m *ExportedMeasurement) *int64 { return m.NetLanIn }),
+			},
+			{
+				labels: []string{"interface", "lan", "direction", "out"},
+				value:  optionalIntValue(func(m *ExportedMeasurement) *int64 { return m.NetLanOut }),
+			},
+			{
+				labels: []string{"interface", "wan", "direction", "in"},
+				value:  optionalIntValue(func(m *ExportedMeasurement) *int64 { return m.NetWanIn }),
+			},
+			{
+				labels: []string{"interface", "wan", "direction", "out"},
+				value:  optionalIntValue(func(m *ExportedMeasurement) *int64 { return m.NetWanOut }),
+			},
+		},
+	},
+}
+
+// exportOpenMetrics writes the measurements in the OpenMetrics text format, see
+// https://github.com/OpenObservability/OpenMetrics/blob/main/specification/OpenMetrics.md
+// The format requires samples of a metric to be grouped, so the measurements are read once per time series.
+func exportOpenMetrics(ctx context.Context, w *bufio.Writer, provider DBProvider, clientIDs []string, lo *query.ListOptions) error {
+	for _, family := range openMetricsFamilies {
+		metrics.WriteFamilyHeader(w, family.Family)
+		for _, series := range family.series {
+			err := provider.StreamMeasurements(ctx, clientIDs, lo, func(m *ExportedMeasurement) error {
+				value := series.value(m)
+				if value == nil {
+					return nil
+				}
+				sample := metrics.Sample{
+					Labels: []metrics.Label{{Name: "client_id", Value: m.ClientID}},
+					Value:  *value,
+				}
+				for i := 0; i+1 < len(series.labels); i += 2 {
+					sample.Labels = append(sample.Labels, metrics.Label{Name: series.labels[i], Value: series.labels[i+1]})
+				}
+				metrics.WriteSampleAt(w, family.Name, sample, m.Timestamp)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := w.WriteString("# EOF\n")
+	return err
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatOptionalInt(v *int64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatInt(*v, 10)
+}
@@ -15,6 +15,8 @@ const (
 	LinkNetBPSLan     = "net_usage_bps_lan"
 	LinkNetPercentWan = "net_usage_percent_wan"
 	LinkNetBPSWan     = "net_usage_bps_wan"
+	// LinkCheckPrefix is followed by the name of a custom check
+	LinkCheckPrefix = "check_"
 )
 
 type CPUUsagePercent struct {
@@ -71,6 +73,13 @@ type NetUsageBPSWan struct {
 	OutMax *float64 `json:"out_max,omitempty" db:"net_usage_bps_wan_out_max"`
 }
 
+type CheckValue struct {
+	Avg       *float64 `json:"avg,omitempty" db:"check_value_avg"`
+	Min       *float64 `json:"min,omitempty" db:"check_value_min"`
+	Max       *float64 `json:"max,omitempty" db:"check_value_max"`
+	StatusMax *int     `json:"status_max,omitempty" db:"check_status_max"`
+}
+
 type ClientGraphMetricsPayload struct {
 	Timestamp          time.Time `json:"timestamp,omitempty" db:"timestamp"`
 	CPUUsagePercent    `json:"cpu_usage_percent,omitempty"`
@@ -87,6 +96,7 @@ type ClientGraphMetricsGraphPayload struct {
 	*NetUsagePercentWan `json:"net_usage_percent_wan,omitempty"`
 	*NetUsageBPSLan     `json:"net_usage_bps_lan,omitempty"`
 	*NetUsageBPSWan     `json:"net_usage_bps_wan,omitempty"`
+	*CheckValue         `json:"check,omitempty"`
 }
 
 var ClientGraphNameToField = map[string]string{
@@ -116,6 +126,19 @@ type ClientMetricsPayload struct {
 	IOUsagePercent     float64   `json:"io_usage_percent" db:"io_usage_percent"`
 }
 
+// ExportedMeasurement is a single measurement as it's exported, net values are nil if not measured
+type ExportedMeasurement struct {
+	ClientID           string    `db:"client_id"`
+	Timestamp          time.Time `db:"timestamp"`
+	CPUUsagePercent    float64   `db:"cpu_usage_percent"`
+	MemoryUsagePercent float64   `db:"memory_usage_percent"`
+	IOUsagePercent     float64   `db:"io_usage_percent"`
+	NetLanIn           *int64    `db:"net_lan_in"`
+	NetLanOut          *int64    `db:"net_lan_out"`
+	NetWanIn           *int64    `db:"net_wan_in"`
+	NetWanOut          *int64    `db:"net_wan_out"`
+}
+
 type ClientProcessesPayload struct {
 	Timestamp time.Time        `json:"timestamp" db:"timestamp"`
 	Processes types.JSONString `json:"processes" db:"processes"`
@@ -134,6 +157,8 @@ type GraphMetricsLinksPayload struct {
 	NetWanUsagePercent *string `json:"net_usage_percent_wan,omitempty"`
 	NetLanUsageBPS     *string `json:"net_usage_bps_lan,omitempty"`
 	NetWanUsageBPS     *string `json:"net_usage_bps_wan,omitempty"`
+	// Checks contains a link per custom check, keyed by the check name
+	Checks map[string]*string `json:"checks,omitempty"`
 }
 
 func NewGraphMetricsLink(requestInfo *query.RequestInfo, target string) *string {
@@ -141,6 +166,14 @@ func NewGraphMetricsLink(requestInfo *query.RequestInfo, target string) *string
 	return &link
 }
 
+type ClientCheckResultPayload struct {
+	Name      string    `json:"name" db:"name"`
+	Timestamp time.Time `json:"timestamp" db:"timestamp"`
+	Value     *float64  `json:"value" db:"value"`
+	Status    int       `json:"status" db:"status"`
+	Output    string    `json:"output" db:"output"`
+}
+
 var ClientGraphMetricsSortFields = map[string]bool{
 	"timestamp": true,
 }
@@ -157,6 +190,12 @@ var ClientMountpointsSortFields = map[string]bool{
 	"timestamp": true,
 }
 
+var ClientChecksSortFields = map[string]bool{
+	"timestamp": true,
+	"name":      true,
+	"status":    true,
+}
+
 var ClientMetricsFilterFields = map[string]bool{
 	"timestamp[gt]":    true,
 	"timestamp[lt]":    true,
@@ -185,6 +224,15 @@ var ClientMountpointsFilterFields = map[string]bool{
 	"timestamp[until]": true,
 }
 
+var ClientChecksFilterFields = map[string]bool{
+	"name":             true,
+	"status":           true,
+	"timestamp[gt]":    true,
+	"timestamp[lt]":    true,
+	"timestamp[since]": true,
+	"timestamp[until]": true,
+}
+
 var ClientGraphMetricsFields = map[string]map[string]bool{
 	"graph-metrics": map[string]bool{
 		"timestamp":            true,
@@ -216,6 +264,16 @@ var ClientMountpointsFields = map[string]map[string]bool{
 	},
 }
 
+var ClientChecksFields = map[string]map[string]bool{
+	"checks": map[string]bool{
+		"name":      true,
+		"timestamp": true,
+		"value":     true,
+		"status":    true,
+		"output":    true,
+	},
+}
+
 var ClientGraphMetricsSortDefault = map[string][]string{"sort": {"-timestamp"}}
 var ClientGraphMetricsFilterDefault = map[string][]string{}
 var ClientGraphMetricsFieldsDefault = map[string][]string{}
@@ -231,3 +289,7 @@ var ClientProcessesFieldsDefault = map[string][]string{"fields[processes]": {"ti
 var ClientMountpointsSortDefault = map[string][]string{"sort": {"-timestamp"}}
 var ClientMountpointsFilterDefault = map[string][]string{}
 var ClientMountpointsFieldsDefault = map[string][]string{"fields[mountpoints]": {"timestamp", "mountpoints"}}
+
+var ClientChecksSortDefault = map[string][]string{"sort": {"-timestamp"}}
+var ClientChecksFilterDefault = map[string][]string{}
+var ClientChecksFieldsDefault = map[string][]string{"fields[checks]": {"name", "timestamp", "value", "status", "output"}}
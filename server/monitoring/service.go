@@ -1,11 +1,14 @@
 package monitoring
 
 import (
+	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cloudradar-monitoring/rport/server/api"
@@ -19,9 +22,15 @@ type Service interface {
 	DeleteMeasurementsOlderThan(ctx context.Context, period time.Duration) (int64, error)
 	ListClientMetrics(context.Context, string, *query.ListOptions) (*api.SuccessPayload, error)
 	ListClientGraph(context.Context, string, *query.ListOptions, string, *models.NetworkCard, *models.NetworkCard) (*api.SuccessPayload, error)
-	ListClientGraphMetrics(context.Context, string, *query.ListOptions, *query.RequestInfo, bool, bool) (*api.SuccessPayload, error)
+	ListClientGraphMetrics(context.Context, string, *query.ListOptions, *query.RequestInfo, bool, bool, []string) (*api.SuccessPayload, error)
 	ListClientMountpoints(context.Context, string, *query.ListOptions) (*api.SuccessPayload, error)
 	ListClientProcesses(context.Context, string, *query.ListOptions) (*api.SuccessPayload, error)
+	ListClientChecks(context.Context, string, *query.ListOptions) (*api.SuccessPayload, error)
+	// ExportMeasurements writes all measurements of given clients matching the timestamp filters in the given format.
+	// Invalid options are reported before anything is written.
+	ExportMeasurements(ctx context.Context, w io.Writer, clientIDs []string, format ExportFormat, options *query.ListOptions) error
+	// LatestMeasurement returns the last measurement saved since server start or nil.
+	LatestMeasurement(clientID string) *models.Measurement
 }
 
 const layoutAPI = time.RFC3339
@@ -32,6 +41,8 @@ const defaultLimitMountpoints = 1
 const maxLimitMountpoints = 100
 const defaultLimitProcesses = 1
 const maxLimitProcesses = 10
+const defaultLimitChecks = 20
+const maxLimitChecks = 500
 const minDownsamplingHours = 2
 const minDownsamplingDuration = time.Duration(minDownsamplingHours) * time.Hour
 const maxDownsamplingHours = 48
@@ -40,14 +51,43 @@ const oneMBitBytes = 125000.0 // for converting MBits to Bytes
 
 type monitoringService struct {
 	DBProvider DBProvider
+
+	latestMu sync.RWMutex
+	latest   map[string]*models.Measurement
 }
 
 func NewService(dbProvider DBProvider) Service {
-	return &monitoringService{DBProvider: dbProvider}
+	return &monitoringService{
+		DBProvider: dbProvider,
+		latest:     make(map[string]*models.Measurement),
+	}
 }
 func (s *monitoringService) SaveMeasurement(ctx context.Context, measurement *models.Measurement) error {
 	measurement.Timestamp = time.Now().UTC()
-	return s.DBProvider.CreateMeasurement(ctx, measurement)
+	if err := s.DBProvider.CreateMeasurement(ctx, measurement); err != nil {
+		return err
+	}
+
+	if len(measurement.Checks) > 0 {
+		for _, result := range measurement.Checks {
+			result.Timestamp = result.Timestamp.UTC()
+		}
+		if err := s.DBProvider.CreateCheckResults(ctx, measurement.ClientID, measurement.Checks); err != nil {
+			return err
+		}
+	}
+
+	s.latestMu.Lock()
+	defer s.latestMu.Unlock()
+	s.latest[measurement.ClientID] = measurement
+
+	return nil
+}
+
+func (s *monitoringService) LatestMeasurement(clientID string) *models.Measurement {
+	s.latestMu.RLock()
+	defer s.latestMu.RUnlock()
+	return s.latest[clientID]
 }
 
 func (s *monitoringService) DeleteMeasurementsOlderThan(ctx context.Context, period time.Duration) (int64, error) {
@@ -55,7 +95,7 @@ func (s *monitoringService) DeleteMeasurementsOlderThan(ctx context.Context, per
 	return s.DBProvider.DeleteMeasurementsBefore(ctx, compare)
 }
 
-func (s *monitoringService) ListClientGraphMetrics(ctx context.Context, clientID string, lo *query.ListOptions, ri *query.RequestInfo, netLan bool, netWan bool) (*api.SuccessPayload, error) {
+func (s *monitoringService) ListClientGraphMetrics(ctx context.Context, clientID string, lo *query.ListOptions, ri *query.RequestInfo, netLan bool, netWan bool, checks []string) (*api.SuccessPayload, error) {
 	span, err := s.validateAndParseGraphOptions(lo)
 	if err != nil {
 		return nil, err
@@ -79,6 +119,12 @@ func (s *monitoringService) ListClientGraphMetrics(ctx context.Context, clientID
 		links.NetWanUsagePercent = NewGraphMetricsLink(ri, LinkNetPercentWan)
 		links.NetWanUsageBPS = NewGraphMetricsLink(ri, LinkNetBPSWan)
 	}
+	if len(checks) > 0 {
+		links.Checks = make(map[string]*string, len(checks))
+		for _, name := range checks {
+			links.Checks[name] = NewGraphMetricsLink(ri, LinkCheckPrefix+name)
+		}
+	}
 
 	return &api.SuccessPayload{
 		Data:  entries,
@@ -87,6 +133,10 @@ func (s *monitoringService) ListClientGraphMetrics(ctx context.Context, clientID
 }
 
 func (s *monitoringService) ListClientGraph(ctx context.Context, clientID string, lo *query.ListOptions, graph string, lanCard *models.NetworkCard, wanCard *models.NetworkCard) (*api.SuccessPayload, error) {
+	if strings.HasPrefix(graph, LinkCheckPrefix) {
+		return s.listClientCheckGraph(ctx, clientID, lo, strings.TrimPrefix(graph, LinkCheckPrefix))
+	}
+
 	if strings.HasSuffix(graph, "_lan") && lanCard == nil ||
 		strings.HasSuffix(graph, "_wan") && wanCard == nil {
 		return nil, errors.APIError{
@@ -113,6 +163,22 @@ func (s *monitoringService) ListClientGraph(ctx context.Context, clientID string
 	}, nil
 }
 
+func (s *monitoringService) listClientCheckGraph(ctx context.Context, clientID string, lo *query.ListOptions, name string) (*api.SuccessPayload, error) {
+	span, err := s.validateAndParseGraphOptions(lo)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.DBProvider.ListCheckGraphByClientID(ctx, clientID, name, span.Hours(), lo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.SuccessPayload{
+		Data: entries,
+	}, nil
+}
+
 func calculatePercentValues(entries *[]*ClientGraphMetricsGraphPayload, lanCard *models.NetworkCard, wanCard *models.NetworkCard) {
 	if entries == nil {
 		return
@@ -320,6 +386,64 @@ func (s *monitoringService) ListClientProcesses(ctx context.Context, clientID st
 	}, nil
 }
 
+func (s *monitoringService) ListClientChecks(ctx context.Context, clientID string, options *query.ListOptions) (*api.SuccessPayload, error) {
+	err := query.ValidateListOptions(options, ClientChecksSortFields, ClientChecksFilterFields, ClientChecksFields, &query.PaginationConfig{
+		DefaultLimit: defaultLimitChecks,
+		MaxLimit:     maxLimitChecks,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := parseAndConvertFilterValues(options.Filters); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.DBProvider.ListChecksByClientID(ctx, clientID, options)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.DBProvider.CountChecksByClientID(ctx, clientID, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.SuccessPayload{
+		Data: entries,
+		Meta: api.NewMeta(count),
+	}, nil
+}
+
+func (s *monitoringService) ExportMeasurements(ctx context.Context, w io.Writer, clientIDs []string, format ExportFormat, options *query.ListOptions) error {
+	var export func(context.Context, *bufio.Writer, DBProvider, []string, *query.ListOptions) error
+	switch format {
+	case ExportFormatCSV:
+		export = exportCSV
+	case ExportFormatInflux:
+		export = exportInflux
+	case ExportFormatOpenMetrics:
+		export = exportOpenMetrics
+	default:
+		return errors.APIError{
+			Message:    fmt.Sprintf("Invalid format %q, expected one of %q, %q or %q.", format, ExportFormatCSV, ExportFormatInflux, ExportFormatOpenMetrics),
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	err := query.ValidateListOptions(options, ExportSortFields, ClientMetricsFilterFields, nil, nil)
+	if err != nil {
+		return err
+	}
+	if err := parseAndConvertFilterValues(options.Filters); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := export(ctx, bw, s.DBProvider, clientIDs, options); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
 func parseAndConvertFilterValues(filters []query.FilterOption) error {
 	for _, fo := range filters {
 		if (fo.Operator == query.FilterOperatorTypeGT) || (fo.Operator == query.FilterOperatorTypeLT) {
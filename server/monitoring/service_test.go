@@ -1,10 +1,12 @@
 package monitoring
 
 import (
+	"bytes"
 	"context"
 	"testing"
 	"time"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/sqlite"
 
 	"github.com/stretchr/testify/require"
@@ -13,7 +15,7 @@ import (
 	"github.com/cloudradar-monitoring/rport/share/query"
 )
 
-var DataSourceOptions = sqlite.DataSourceOptions{WALEnabled: false}
+var DataSourceOptions = database.Options{SQLite: sqlite.DataSourceOptions{WALEnabled: false}}
 
 func TestMonitoringService_SaveMeasurement(t *testing.T) {
 	dbProvider, err := NewSqliteProvider(":memory:", DataSourceOptions, testLog)
@@ -31,6 +33,8 @@ func TestMonitoringService_SaveMeasurement(t *testing.T) {
 	require.NoError(t, err)
 	gap := m.Timestamp.Sub(mClient)
 	require.True(t, gap >= minGap, "monitoring.service must set timestamp")
+	require.Equal(t, m, service.LatestMeasurement("test1"))
+	require.Nil(t, service.LatestMeasurement("test2"))
 }
 
 func TestMonitoringService_ListClientMetrics(t *testing.T) {
@@ -173,7 +177,7 @@ func TestMonitoringService_ListClientGraphMetrics(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
-			payload, err := service.ListClientGraphMetrics(ctx, "test_client", tc.Options, tc.RequestInfo, tc.NetLan, tc.NetWan)
+			payload, err := service.ListClientGraphMetrics(ctx, "test_client", tc.Options, tc.RequestInfo, tc.NetLan, tc.NetWan, nil)
 			require.NoError(t, err)
 			require.NotNil(t, payload.Data)
 			require.Nil(t, payload.Meta)
@@ -193,3 +197,171 @@ func TestMonitoringService_ListClientGraphMetrics(t *testing.T) {
 	}
 
 }
+
+func TestMonitoringService_ClientChecks(t *testing.T) {
+	dbProvider, err := NewSqliteProvider(":memory:", DataSourceOptions, testLog)
+	require.NoError(t, err)
+	defer dbProvider.Close()
+
+	service := NewService(dbProvider)
+	ctx := context.Background()
+
+	value1, value2 := 3.0, 7.0
+	err = service.SaveMeasurement(ctx, &models.Measurement{
+		ClientID: "test_client",
+		Checks: []*models.CheckResult{
+			{Name: "queue", Timestamp: measurement1, Value: &value1, Status: models.CheckStatusOK, Output: "3"},
+			{Name: "load", Timestamp: measurement1, Status: models.CheckStatusCritical, Output: "LOAD CRITICAL"},
+			{Name: "queue", Timestamp: measurement2, Value: &value2, Status: models.CheckStatusOK, Output: "7"},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("list", func(t *testing.T) {
+		options := &query.ListOptions{
+			Sorts:   query.ParseSortOptions(ClientChecksSortDefault),
+			Filters: []query.FilterOption{{Column: []string{"name"}, Values: []string{"queue"}}},
+			Fields:  query.ParseFieldsOptions(ClientChecksFieldsDefault),
+		}
+		payload, err := service.ListClientChecks(ctx, "test_client", options)
+		require.NoError(t, err)
+		require.Equal(t, 2, payload.Meta.Count)
+		require.Equal(t, []*ClientCheckResultPayload{
+			{Name: "queue", Timestamp: measurement2, Value: &value2, Status: models.CheckStatusOK, Output: "7"},
+			{Name: "queue", Timestamp: measurement1, Value: &value1, Status: models.CheckStatusOK, Output: "3"},
+		}, payload.Data)
+	})
+
+	t.Run("filter by status", func(t *testing.T) {
+		options := &query.ListOptions{
+			Filters: []query.FilterOption{{Column: []string{"status"}, Values: []string{"2"}}},
+			Fields:  query.ParseFieldsOptions(ClientChecksFieldsDefault),
+		}
+		payload, err := service.ListClientChecks(ctx, "test_client", options)
+		require.NoError(t, err)
+		require.Equal(t, 1, payload.Meta.Count)
+		checks := payload.Data.([]*ClientCheckResultPayload)
+		require.Len(t, checks, 1)
+		require.Equal(t, "load", checks[0].Name)
+		require.Nil(t, checks[0].Value)
+	})
+
+	t.Run("graph", func(t *testing.T) {
+		options := createGraphMetricsDefaultOptions(measurement1, 48, layoutAPI)
+		payload, err := service.ListClientGraph(ctx, "test_client", options, LinkCheckPrefix+"queue", nil, nil)
+		require.NoError(t, err)
+		entries := payload.Data.([]*ClientGraphMetricsGraphPayload)
+		require.Len(t, entries, 1)
+		require.NotNil(t, entries[0].CheckValue)
+		require.Equal(t, 5.0, *entries[0].CheckValue.Avg)
+		require.Equal(t, 3.0, *entries[0].CheckValue.Min)
+		require.Equal(t, 7.0, *entries[0].CheckValue.Max)
+		require.Equal(t, models.CheckStatusOK, *entries[0].CheckValue.StatusMax)
+		require.Nil(t, entries[0].CPUUsagePercent)
+	})
+
+	t.Run("graph links", func(t *testing.T) {
+		options := createGraphMetricsDefaultOptions(measurement1, 48, layoutAPI)
+		payload, err := service.ListClientGraphMetrics(ctx, "test_client", options, &query.RequestInfo{URL: "url"}, false, false, []string{"queue"})
+		require.NoError(t, err)
+		links := payload.Links.(*GraphMetricsLinksPayload)
+		link := "url/check_queue"
+		require.Equal(t, map[string]*string{"queue": &link}, links.Checks)
+	})
+}
+
+func TestMonitoringService_ExportMeasurements(t *testing.T) {
+	netIn, netOut := int64(3000), int64(2000)
+	dbProvider := &DBProviderMock{
+		ExportedMeasurements: []*ExportedMeasurement{
+			{
+				ClientID:           "client 1",
+				Timestamp:          measurement1,
+				CPUUsagePercent:    10.5,
+				MemoryUsagePercent: 30,
+				IOUsagePercent:     2,
+				NetLanIn:           &netIn,
+				NetLanOut:          &netOut,
+			},
+			{
+				ClientID:           "client 1",
+				Timestamp:          measurement2,
+				CPUUsagePercent:    15,
+				MemoryUsagePercent: 35,
+				IOUsagePercent:     3,
+			},
+		},
+	}
+	service := NewService(dbProvider)
+
+	testCases := []struct {
+		Name     string
+		Format   ExportFormat
+		Expected string
+	}{
+		{
+			Name:   "csv",
+			Format: ExportFormatCSV,
+			Expected: `client_id,timestamp,cpu_usage_percent,memory_usage_percent,io_usage_percent,net_lan_in,net_lan_out,net_wan_in,net_wan_out
+client 1,2021-09-01T00:00:00Z,10.5,30,2,3000,2000,,
+client 1,2021-09-01T00:01:00Z,15,35,3,,,,
+`,
+		},
+		{
+			Name:   "influx",
+			Format: ExportFormatInflux,
+			Expected: `rport_client,client_id=client\ 1 cpu_usage_percent=10.5,memory_usage_percent=30,io_usage_percent=2,net_lan_in=3000i,net_lan_out=2000i 1630454400000000000
+rport_client,client_id=client\ 1 cpu_usage_percent=15,memory_usage_percent=35,io_usage_percent=3 1630454460000000000
+`,
+		},
+		{
+			Name:   "openmetrics",
+			Format: ExportFormatOpenMetrics,
+			Expected: `# HELP rport_client_cpu_usage_percent CPU usage of the client.
+# TYPE rport_client_cpu_usage_percent gauge
+rport_client_cpu_usage_percent{client_id="client 1"} 10.5 1630454400
+rport_client_cpu_usage_percent{client_id="client 1"} 15 1630454460
+# HELP rport_client_memory_usage_percent Memory usage of the client.
+# TYPE rport_client_memory_usage_percent gauge
+rport_client_memory_usage_percent{client_id="client 1"} 30 1630454400
+rport_client_memory_usage_percent{client_id="client 1"} 35 1630454460
+# HELP rport_client_io_usage_percent IO usage of the client.
+# TYPE rport_client_io_usage_percent gauge
+rport_client_io_usage_percent{client_id="client 1"} 2 1630454400
+rport_client_io_usage_percent{client_id="client 1"} 3 1630454460
+# HELP rport_client_net_bytes_per_second Network throughput of the client.
+# TYPE rport_client_net_bytes_per_second gauge
+rport_client_net_bytes_per_second{client_id="client 1",interface="lan",direction="in"} 3000 1630454400
+rport_client_net_bytes_per_second{client_id="client 1",interface="lan",direction="out"} 2000 1630454400
+# EOF
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := service.ExportMeasurements(context.Background(), buf, []string{"client 1"}, tc.Format, &query.ListOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tc.Expected, buf.String())
+		})
+	}
+
+	t.Run("invalid format", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := service.ExportMeasurements(context.Background(), buf, []string{"client 1"}, "json", &query.ListOptions{})
+		require.EqualError(t, err, `Invalid format "json", expected one of "csv", "influx" or "openmetrics".`)
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		options := &query.ListOptions{
+			Filters: []query.FilterOption{{Column: []string{"cpu_usage_percent"}, Values: []string{"10"}}},
+		}
+		err := service.ExportMeasurements(context.Background(), buf, []string{"client 1"}, ExportFormatCSV, options)
+		require.Error(t, err)
+		require.Empty(t, buf.String())
+	})
+}
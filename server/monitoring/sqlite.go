@@ -10,6 +10,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	monitoring "github.com/cloudradar-monitoring/rport/db/migration/monitoring"
 	"github.com/cloudradar-monitoring/rport/db/sqlite"
 	"github.com/cloudradar-monitoring/rport/share/logger"
@@ -26,17 +27,25 @@ type DBProvider interface {
 	ListMountpointsByClientID(context.Context, string, *query.ListOptions) ([]*ClientMountpointsPayload, error)
 	ListProcessesByClientID(context.Context, string, *query.ListOptions) ([]*ClientProcessesPayload, error)
 	CountByClientID(context.Context, string, *query.ListOptions) (int, error)
+	// StreamMeasurements calls fn for each measurement of given clients ordered by client id and timestamp
+	StreamMeasurements(ctx context.Context, clientIDs []string, lo *query.ListOptions, fn func(*ExportedMeasurement) error) error
+	CreateCheckResults(ctx context.Context, clientID string, results []*models.CheckResult) error
+	ListChecksByClientID(context.Context, string, *query.ListOptions) ([]*ClientCheckResultPayload, error)
+	CountChecksByClientID(context.Context, string, *query.ListOptions) (int, error)
+	ListCheckGraphByClientID(ctx context.Context, clientID string, name string, hours float64, lo *query.ListOptions) ([]*ClientGraphMetricsGraphPayload, error)
 	Close() error
 }
 
+const maxStreamClientIDs = 500
+
 type SqliteProvider struct {
 	db        *sqlx.DB
 	logger    *logger.Logger
 	converter *query.SQLConverter
 }
 
-func NewSqliteProvider(dbPath string, dataSourceOptions sqlite.DataSourceOptions, logger *logger.Logger) (DBProvider, error) {
-	db, err := sqlite.New(dbPath, monitoring.AssetNames(), monitoring.Asset, dataSourceOptions)
+func NewSqliteProvider(dbPath string, dbOptions database.Options, logger *logger.Logger) (DBProvider, error) {
+	db, err := database.Open("monitoring", dbPath, monitoring.AssetNames(), monitoring.Asset, dbOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create monitoring DB instance: %v", err)
 	}
@@ -51,50 +60,51 @@ func NewSqliteProvider(dbPath string, dataSourceOptions sqlite.DataSourceOptions
 }
 
 func (p *SqliteProvider) ListMountpointsByClientID(ctx context.Context, clientID string, o *query.ListOptions) ([]*ClientMountpointsPayload, error) {
-	q := "SELECT * FROM `measurements` as `mountpoints` WHERE `client_id` = ? "
+	q := "SELECT * FROM measurements AS mountpoints WHERE client_id = ? "
 	params := []interface{}{}
 	params = append(params, clientID)
 	q, params = p.converter.AppendOptionsToQuery(o, q, params)
 
 	val := []*ClientMountpointsPayload{}
-	err := p.db.SelectContext(ctx, &val, q, params...)
+	err := p.db.SelectContext(ctx, &val, p.db.Rebind(q), params...)
 	return val, err
 }
 
 func (p *SqliteProvider) ListProcessesByClientID(ctx context.Context, clientID string, o *query.ListOptions) ([]*ClientProcessesPayload, error) {
-	q := "SELECT * FROM `measurements` as `processes` WHERE `client_id` = ? "
+	q := "SELECT * FROM measurements AS processes WHERE client_id = ? "
 	params := []interface{}{}
 	params = append(params, clientID)
 	q, params = p.converter.AppendOptionsToQuery(o, q, params)
 
 	val := []*ClientProcessesPayload{}
-	err := p.db.SelectContext(ctx, &val, q, params...)
+	err := p.db.SelectContext(ctx, &val, p.db.Rebind(q), params...)
 	return val, err
 }
 
 func (p *SqliteProvider) ListMetricsByClientID(ctx context.Context, clientID string, o *query.ListOptions) ([]*ClientMetricsPayload, error) {
-	q := "SELECT * FROM `measurements` as `metrics` WHERE `client_id` = ? "
+	q := "SELECT * FROM measurements AS metrics WHERE client_id = ? "
 	params := []interface{}{}
 	params = append(params, clientID)
 	q, params = p.converter.AppendOptionsToQuery(o, q, params)
 
 	val := []*ClientMetricsPayload{}
-	err := p.db.SelectContext(ctx, &val, q, params...)
+	err := p.db.SelectContext(ctx, &val, p.db.Rebind(q), params...)
 	return val, err
 }
 
 func (p *SqliteProvider) CountByClientID(ctx context.Context, clientID string, options *query.ListOptions) (int, error) {
 	var result int
 
-	q := "SELECT COUNT(*) FROM `measurements` WHERE `client_id` = ? "
+	q := "SELECT COUNT(*) FROM measurements WHERE client_id = ? "
 	countOptions := *options
 	countOptions.Pagination = nil
+	countOptions.Sorts = nil
 
 	params := []interface{}{}
 	params = append(params, clientID)
 	q, params = p.converter.AppendOptionsToQuery(&countOptions, q, params)
 
-	err := p.db.GetContext(ctx, &result, q, params...)
+	err := p.db.GetContext(ctx, &result, p.db.Rebind(q), params...)
 	if err != nil {
 		return 0, err
 	}
@@ -107,7 +117,7 @@ func (p *SqliteProvider) ListGraphMetricsByClientID(ctx context.Context, clientI
 	params = append(params, clientID)
 
 	q := `SELECT
-		timestamp,
+		` + p.groupTimestamp() + `,
 		round(avg(cpu_usage_percent),2) as cpu_usage_percent_avg,
 		min(cpu_usage_percent) as cpu_usage_percent_min,
 		max(cpu_usage_percent) as cpu_usage_percent_max,
@@ -124,14 +134,14 @@ func (p *SqliteProvider) ListGraphMetricsByClientID(ctx context.Context, clientI
 	/*This is the part of "downsampling graph data" (group together graph points, so that you don't get too much points in one request).
 	The value of "29" comes from Thorsten. He did some research and found out that "29" would be the best fit.
 	*/
-	q = q + ` GROUP BY round((strftime('%s',timestamp)/(?)),0)`
+	q = q + p.groupByInterval()
 	divisor := (math.Round(hours*100) / 100) * 29
 	params = append(params, divisor)
 
 	q = p.converter.AddOrderBy(lo.Sorts, q)
 
 	val := []*ClientGraphMetricsPayload{}
-	err := p.db.SelectContext(ctx, &val, q, params...)
+	err := p.db.SelectContext(ctx, &val, p.db.Rebind(q), params...)
 	return val, err
 }
 
@@ -144,7 +154,7 @@ func (p *SqliteProvider) ListGraphByClientID(ctx context.Context, clientID strin
 		return nil, fmt.Errorf("unknown graph: %s", graph)
 	}
 
-	q := `SELECT timestamp, `
+	q := `SELECT ` + p.groupTimestamp() + `, `
 	q = q + ` 
 		round(avg(` + field + `),2) as ` + alias + `_avg,
 		min(` + field + `) as ` + alias + `_min,
@@ -163,17 +173,59 @@ func (p *SqliteProvider) ListGraphByClientID(ctx context.Context, clientID strin
 
 	q, params = p.converter.AddWhere(lo.Filters, q, params)
 
-	q = q + ` GROUP BY round((strftime('%s',timestamp)/(?)),0)`
+	q = q + p.groupByInterval()
 	divisor := (math.Round(hours*100) / 100) * 29
 	params = append(params, divisor)
 
 	query := p.converter.AddOrderBy(lo.Sorts, q)
 
 	val := []*ClientGraphMetricsGraphPayload{}
-	err := p.db.SelectContext(ctx, &val, query, params...)
+	err := p.db.SelectContext(ctx, &val, p.db.Rebind(query), params...)
 	return val, err
 }
 
+func (p *SqliteProvider) StreamMeasurements(ctx context.Context, clientIDs []string, lo *query.ListOptions, fn func(*ExportedMeasurement) error) error {
+	// stay below the sqlite limit of query params
+	for start := 0; start < len(clientIDs); start += maxStreamClientIDs {
+		end := start + maxStreamClientIDs
+		if end > len(clientIDs) {
+			end = len(clientIDs)
+		}
+		if err := p.streamMeasurements(ctx, clientIDs[start:end], lo, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *SqliteProvider) streamMeasurements(ctx context.Context, clientIDs []string, lo *query.ListOptions, fn func(*ExportedMeasurement) error) error {
+	q, params, err := sqlx.In(`SELECT client_id, timestamp, cpu_usage_percent, memory_usage_percent, io_usage_percent,
+		net_lan_in, net_lan_out, net_wan_in, net_wan_out
+	FROM measurements WHERE client_id IN (?)`, clientIDs)
+	if err != nil {
+		return err
+	}
+	q, params = p.converter.AddWhere(lo.Filters, q, params)
+	q += " ORDER BY client_id, timestamp"
+
+	rows, err := p.db.QueryxContext(ctx, p.db.Rebind(q), params...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m := &ExportedMeasurement{}
+		if err := rows.StructScan(m); err != nil {
+			return err
+		}
+		if err := fn(m); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
 func (p *SqliteProvider) CreateMeasurement(ctx context.Context, measurement *models.Measurement) error {
 	q := `INSERT INTO measurements (client_id, timestamp, cpu_usage_percent, memory_usage_percent, io_usage_percent, processes, mountpoints, net_lan_in, net_lan_out, net_wan_in, net_wan_out) 
 		VALUES (:client_id, :timestamp, :cpu_usage_percent, :memory_usage_percent, :io_usage_percent, :processes, :mountpoints, `
@@ -198,13 +250,108 @@ func (p *SqliteProvider) CreateMeasurement(ctx context.Context, measurement *mod
 }
 
 func (p *SqliteProvider) DeleteMeasurementsBefore(ctx context.Context, compare time.Time) (int64, error) {
-	result, err := p.db.ExecContext(ctx, "DELETE FROM measurements WHERE  timestamp < ?", compare)
+	if _, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM check_results WHERE timestamp < ?"), compare); err != nil {
+		return 0, err
+	}
+
+	result, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM measurements WHERE  timestamp < ?"), compare)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected()
 }
 
+func (p *SqliteProvider) CreateCheckResults(ctx context.Context, clientID string, results []*models.CheckResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	values := make([]string, 0, len(results))
+	params := make([]interface{}, 0, len(results)*6)
+	for _, r := range results {
+		values = append(values, "(?, ?, ?, ?, ?, ?)")
+		params = append(params, clientID, r.Name, r.Timestamp, r.Value, r.Status, r.Output)
+	}
+	// results are sent again if the previous measurement was lost, ignore the duplicates
+	q := "INSERT INTO check_results (client_id, name, timestamp, value, status, output) VALUES " + strings.Join(values, ", ") +
+		" ON CONFLICT DO NOTHING"
+
+	_, err := sqlite.WithRetryWhenBusy(func() (result sql.Result, err error) {
+		return p.db.ExecContext(ctx, p.db.Rebind(q), params...)
+	}, "createcheckresults", p.logger)
+
+	return err
+}
+
+func (p *SqliteProvider) ListChecksByClientID(ctx context.Context, clientID string, o *query.ListOptions) ([]*ClientCheckResultPayload, error) {
+	q := "SELECT * FROM check_results AS checks WHERE client_id = ? "
+	params := []interface{}{}
+	params = append(params, clientID)
+	q, params = p.converter.AppendOptionsToQuery(o, q, params)
+
+	val := []*ClientCheckResultPayload{}
+	err := p.db.SelectContext(ctx, &val, p.db.Rebind(q), params...)
+	return val, err
+}
+
+func (p *SqliteProvider) CountChecksByClientID(ctx context.Context, clientID string, options *query.ListOptions) (int, error) {
+	var result int
+
+	q := "SELECT COUNT(*) FROM check_results WHERE client_id = ? "
+	params := []interface{}{}
+	params = append(params, clientID)
+	q, params = p.converter.AddWhere(options.Filters, q, params)
+
+	err := p.db.GetContext(ctx, &result, p.db.Rebind(q), params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (p *SqliteProvider) ListCheckGraphByClientID(ctx context.Context, clientID string, name string, hours float64, lo *query.ListOptions) ([]*ClientGraphMetricsGraphPayload, error) {
+	params := []interface{}{}
+	params = append(params, clientID, name)
+
+	q := `SELECT
+		` + p.groupTimestamp() + `,
+		round(avg(value),2) as check_value_avg,
+		min(value) as check_value_min,
+		max(value) as check_value_max,
+		max(status) as check_status_max
+	FROM check_results WHERE client_id = ? AND name = ?`
+
+	q, params = p.converter.AddWhere(lo.Filters, q, params)
+
+	q = q + p.groupByInterval()
+	divisor := (math.Round(hours*100) / 100) * 29
+	params = append(params, divisor)
+
+	q = p.converter.AddOrderBy(lo.Sorts, q)
+
+	val := []*ClientGraphMetricsGraphPayload{}
+	err := p.db.SelectContext(ctx, &val, p.db.Rebind(q), params...)
+	return val, err
+}
+
+// groupTimestamp returns the timestamp of a group of downsampled measurements.
+// PostgreSQL doesn't allow to select columns that are neither grouped nor aggregated.
+func (p *SqliteProvider) groupTimestamp() string {
+	if p.db.DriverName() == query.DriverPostgres {
+		return "MIN(timestamp) AS timestamp"
+	}
+	return "timestamp"
+}
+
+// groupByInterval groups the measurements by intervals, the length of an interval in seconds is given as the query param
+func (p *SqliteProvider) groupByInterval() string {
+	if p.db.DriverName() == query.DriverPostgres {
+		return fmt.Sprintf(" GROUP BY round(CAST(%s/(?) AS NUMERIC), 0)", p.converter.UnixTime("timestamp"))
+	}
+	return fmt.Sprintf(" GROUP BY round((%s/(?)),0)", p.converter.UnixTime("timestamp"))
+}
+
 func (p *SqliteProvider) Close() error {
 	return p.db.Close()
 }
@@ -9,9 +9,11 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	chshare "github.com/cloudradar-monitoring/rport/share/logger"
 	"github.com/cloudradar-monitoring/rport/share/models"
 	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/test"
 	"github.com/cloudradar-monitoring/rport/share/types"
 )
 
@@ -67,6 +69,12 @@ var testData = []models.Measurement{
 	},
 }
 
+func dataSourceOptions(postgresDSN string) database.Options {
+	options := DataSourceOptions
+	options.PostgresDSN = postgresDSN
+	return options
+}
+
 func TestSqliteProvider_CreateMeasurement(t *testing.T) {
 	dbProvider, err := NewSqliteProvider(":memory:", DataSourceOptions, testLog)
 	require.NoError(t, err)
@@ -97,35 +105,68 @@ func TestSqliteProvider_CreateMeasurement(t *testing.T) {
 }
 
 func TestSqliteProvider_DeleteMeasurementsBefore(t *testing.T) {
-	dbProvider, err := NewSqliteProvider(":memory:", DataSourceOptions, testLog)
-	require.NoError(t, err)
-	defer dbProvider.Close()
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		dbProvider, err := NewSqliteProvider(":memory:", dataSourceOptions(postgresDSN), testLog)
+		require.NoError(t, err)
+		defer dbProvider.Close()
 
-	ctx := context.Background()
+		ctx := context.Background()
 
-	err = createTestData(ctx, dbProvider)
-	require.NoError(t, err)
+		err = createTestData(ctx, dbProvider)
+		require.NoError(t, err)
 
-	deleted, err := dbProvider.DeleteMeasurementsBefore(ctx, measurement3)
-	require.NoError(t, err)
-	require.Equal(t, int64(2), deleted)
+		deleted, err := dbProvider.DeleteMeasurementsBefore(ctx, measurement3)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+	})
+}
+
+func TestSqliteProvider_CreateCheckResults(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		dbProvider, err := NewSqliteProvider(":memory:", dataSourceOptions(postgresDSN), testLog)
+		require.NoError(t, err)
+		defer dbProvider.Close()
+
+		ctx := context.Background()
+
+		results := []*models.CheckResult{
+			{Name: "queue", Timestamp: measurement1, Status: models.CheckStatusOK},
+			{Name: "queue", Timestamp: measurement3, Status: models.CheckStatusWarning},
+		}
+		require.NoError(t, dbProvider.CreateCheckResults(ctx, "test_client_1", results))
+		// duplicates are ignored
+		require.NoError(t, dbProvider.CreateCheckResults(ctx, "test_client_1", results[1:]))
+
+		count, err := dbProvider.CountChecksByClientID(ctx, "test_client_1", &query.ListOptions{})
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+
+		_, err = dbProvider.DeleteMeasurementsBefore(ctx, measurement3)
+		require.NoError(t, err)
+
+		count, err = dbProvider.CountChecksByClientID(ctx, "test_client_1", &query.ListOptions{})
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	})
 }
 
 func TestSqliteProvider_CountByClientID(t *testing.T) {
-	dbProvider, err := NewSqliteProvider(":memory:", DataSourceOptions, testLog)
-	require.NoError(t, err)
-	defer dbProvider.Close()
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		dbProvider, err := NewSqliteProvider(":memory:", dataSourceOptions(postgresDSN), testLog)
+		require.NoError(t, err)
+		defer dbProvider.Close()
 
-	ctx := context.Background()
+		ctx := context.Background()
 
-	err = createTestData(ctx, dbProvider)
-	require.NoError(t, err)
+		err = createTestData(ctx, dbProvider)
+		require.NoError(t, err)
 
-	// get the latest metrics measurement of client
-	options := createMetricsDefaultOptions()
-	count, err := dbProvider.CountByClientID(ctx, "test_client_1", options)
-	require.NoError(t, err)
-	require.Equal(t, 3, count)
+		// get the latest metrics measurement of client
+		options := createMetricsDefaultOptions()
+		count, err := dbProvider.CountByClientID(ctx, "test_client_1", options)
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+	})
 }
 
 func TestSqliteProvider_ListMetricsLatestByClientID(t *testing.T) {
@@ -168,29 +209,31 @@ func TestSqliteProvider_ListMetricsNextByClientID(t *testing.T) {
 }
 
 func TestSqliteProvider_ListGraphMetricsByClientID(t *testing.T) {
-	dbProvider, err := NewSqliteProvider(":memory:", DataSourceOptions, testLog)
-	require.NoError(t, err)
-	defer dbProvider.Close()
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		dbProvider, err := NewSqliteProvider(":memory:", dataSourceOptions(postgresDSN), testLog)
+		require.NoError(t, err)
+		defer dbProvider.Close()
 
-	ctx := context.Background()
+		ctx := context.Background()
 
-	err = createDownsamplingData(ctx, dbProvider)
-	require.NoError(t, err)
+		err = createDownsamplingData(ctx, dbProvider)
+		require.NoError(t, err)
 
-	hours := 48.0
-	options := createGraphMetricsDefaultOptions(measurement1, hours, layoutDb)
+		hours := 48.0
+		options := createGraphMetricsDefaultOptions(measurement1, hours, layoutDb)
 
-	mList, err := dbProvider.ListGraphMetricsByClientID(ctx, "test_client", hours, options)
-	require.NoError(t, err)
-	require.NotNil(t, mList)
-	require.Equal(t, 126, len(mList))
+		mList, err := dbProvider.ListGraphMetricsByClientID(ctx, "test_client", hours, options)
+		require.NoError(t, err)
+		require.NotNil(t, mList)
+		require.Equal(t, 126, len(mList))
 
-	options.Filters = createGTLTFilter(measurement1, hours)
+		options.Filters = createGTLTFilter(measurement1, hours)
 
-	mList, err = dbProvider.ListGraphMetricsByClientID(ctx, "test_client", hours, options)
-	require.NoError(t, err)
-	require.NotNil(t, mList)
-	require.Equal(t, 126, len(mList))
+		mList, err = dbProvider.ListGraphMetricsByClientID(ctx, "test_client", hours, options)
+		require.NoError(t, err)
+		require.NotNil(t, mList)
+		require.Equal(t, 126, len(mList))
+	})
 }
 
 func TestSqliteProvider_ListGraphMetricsGraphByClientID(t *testing.T) {
@@ -467,3 +510,49 @@ func createMountpointsDefaultOptions() *query.ListOptions {
 
 	return qOptions
 }
+
+func TestSqliteProvider_StreamMeasurements(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		dbProvider, err := NewSqliteProvider(":memory:", dataSourceOptions(postgresDSN), testLog)
+		require.NoError(t, err)
+		defer dbProvider.Close()
+
+		ctx := context.Background()
+		for _, m := range []*models.Measurement{
+			{ClientID: "client_2", Timestamp: measurement1, CPUUsagePercent: 50},
+			{ClientID: "client_1", Timestamp: measurement2, CPUUsagePercent: 20, NetLan: &models.NetBytes{In: 3000, Out: 2000}},
+			{ClientID: "client_1", Timestamp: measurement1, CPUUsagePercent: 10},
+			{ClientID: "client_3", Timestamp: measurement1, CPUUsagePercent: 90},
+			{ClientID: "client_1", Timestamp: measurement3, CPUUsagePercent: 30},
+		} {
+			require.NoError(t, dbProvider.CreateMeasurement(ctx, m))
+		}
+
+		options := &query.ListOptions{
+			Filters: []query.FilterOption{
+				{
+					Column:   []string{"timestamp"},
+					Operator: query.FilterOperatorTypeLT,
+					Values:   []string{measurement3.Format(layoutDb)},
+				},
+			},
+		}
+		var result []*ExportedMeasurement
+		err = dbProvider.StreamMeasurements(ctx, []string{"client_1", "client_2"}, options, func(m *ExportedMeasurement) error {
+			result = append(result, m)
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.Len(t, result, 3)
+		require.Equal(t, "client_1", result[0].ClientID)
+		require.Equal(t, measurement1, result[0].Timestamp.UTC())
+		require.Nil(t, result[0].NetLanIn)
+		require.Equal(t, "client_1", result[1].ClientID)
+		require.Equal(t, 20.0, result[1].CPUUsagePercent)
+		require.Equal(t, int64(3000), *result[1].NetLanIn)
+		require.Equal(t, int64(2000), *result[1].NetLanOut)
+		require.Nil(t, result[1].NetWanIn)
+		require.Equal(t, "client_2", result[2].ClientID)
+	})
+}
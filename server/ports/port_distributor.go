@@ -34,6 +34,7 @@ func NewPortDistributorForTests(allowedPorts, tcpPortsPool, udpPortsPool mapset.
 }
 
 func (d *PortDistributor) GetRandomPort(protocol string) (int, error) {
+	protocol = listenProtocol(protocol)
 	subProtocols := []string{protocol}
 	if protocol == models.ProtocolTCPUDP {
 		subProtocols = []string{models.ProtocolTCP, models.ProtocolUDP}
@@ -65,7 +66,7 @@ func (d *PortDistributor) IsPortAllowed(port int) bool {
 }
 
 func (d *PortDistributor) IsPortBusy(protocol string, port int) bool {
-	return !d.getPool(protocol).Contains(port)
+	return !d.getPool(listenProtocol(protocol)).Contains(port)
 }
 
 func (d *PortDistributor) getPool(protocol string) mapset.Set {
@@ -100,7 +101,7 @@ func (d *PortDistributor) refresh(protocol string) error {
 
 func ListBusyPorts(protocol string) (mapset.Set, error) {
 	result := mapset.NewThreadUnsafeSet()
-	connections, err := net.Connections(protocol)
+	connections, err := net.Connections(listenProtocol(protocol))
 	if err != nil {
 		return nil, err
 	}
@@ -114,3 +115,11 @@ func ListBusyPorts(protocol string) (mapset.Set, error) {
 
 	return result, nil
 }
+
+// listenProtocol returns the protocol of the port a tunnel listens on, socks5 tunnels listen on a tcp port
+func listenProtocol(protocol string) string {
+	if protocol == models.ProtocolSOCKS5 {
+		return models.ProtocolTCP
+	}
+	return protocol
+}
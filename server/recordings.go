@@ -0,0 +1,21 @@
+package chserver
+
+import (
+	"path"
+
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/server/recordings"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+func initRecordingsService(config *chconfig.Config, log *logger.Logger) (*recordings.Service, error) {
+	provider, err := recordings.NewSqliteProvider(
+		path.Join(config.Server.DataDir, "recordings.db"),
+		config.GetDatabaseOptions(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return recordings.NewService(provider, path.Join(config.Server.DataDir, "recordings"), log)
+}
@@ -0,0 +1,185 @@
+package recordings
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// AsciicastWriter writes terminal sessions in asciicast v2 format, see
+// https://github.com/asciinema/asciinema/blob/develop/doc/asciicast-v2.md
+type AsciicastWriter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+
+	// incomplete utf-8 sequences at the end of the last chunk, keyed by event type
+	pending map[string][]byte
+}
+
+type asciicastHeader struct {
+	Version   int   `json:"version"`
+	Width     int   `json:"width"`
+	Height    int   `json:"height"`
+	Timestamp int64 `json:"timestamp"`
+}
+
+func NewAsciicastWriter(w io.Writer, cols, rows uint16) (*AsciicastWriter, error) {
+	a := &AsciicastWriter{
+		w:       w,
+		start:   time.Now(),
+		pending: make(map[string][]byte),
+	}
+	err := a.writeLine(asciicastHeader{
+		Version:   2,
+		Width:     int(cols),
+		Height:    int(rows),
+		Timestamp: a.start.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Output records data written to the terminal.
+func (a *AsciicastWriter) Output(data []byte) error {
+	return a.writeEvent("o", data)
+}
+
+// Input records data typed by the user.
+func (a *AsciicastWriter) Input(data []byte) error {
+	return a.writeEvent("i", data)
+}
+
+func (a *AsciicastWriter) Resize(cols, rows uint16) error {
+	return a.writeEvent("r", []byte(fmt.Sprintf("%dx%d", cols, rows)))
+}
+
+func (a *AsciicastWriter) writeEvent(eventType string, data []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// a multi-byte character might be split between two chunks, keep the start of it for the next event
+	data = append(a.pending[eventType], data...)
+	complete := completeUTF8Len(data)
+	a.pending[eventType] = append([]byte(nil), data[complete:]...)
+	if complete == 0 {
+		return nil
+	}
+
+	elapsed := time.Since(a.start).Seconds()
+	return a.writeLine([]interface{}{elapsed, eventType, string(data[:complete])})
+}
+
+func (a *AsciicastWriter) writeLine(v interface{}) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = a.w.Write(append(line, '\n'))
+	return err
+}
+
+// completeUTF8Len returns the length of data without an incomplete utf-8 sequence at the end.
+func completeUTF8Len(data []byte) int {
+	for i := 1; i <= utf8.UTFMax-1 && i <= len(data); i++ {
+		start := len(data) - i
+		if !utf8.RuneStart(data[start]) {
+			continue
+		}
+		if !utf8.FullRune(data[start:]) {
+			return start
+		}
+		break
+	}
+	return len(data)
+}
+
+// WebsockifyWriter writes VNC sessions in the record format of websockify, see
+// https://github.com/novnc/websockify/wiki/Recording-and-Playback
+type WebsockifyWriter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+}
+
+func NewWebsockifyWriter(w io.Writer) (*WebsockifyWriter, error) {
+	if _, err := io.WriteString(w, "var VNC_frame_data = [\n"); err != nil {
+		return nil, err
+	}
+
+	return &WebsockifyWriter{
+		w:     w,
+		start: time.Now(),
+	}, nil
+}
+
+// FromServer records a frame sent by the VNC server.
+func (ww *WebsockifyWriter) FromServer(data []byte) error {
+	return ww.writeFrame('{', data)
+}
+
+// FromClient records a frame sent by the browser.
+func (ww *WebsockifyWriter) FromClient(data []byte) error {
+	return ww.writeFrame('}', data)
+}
+
+func (ww *WebsockifyWriter) writeFrame(direction byte, data []byte) error {
+	ww.mu.Lock()
+	defer ww.mu.Unlock()
+
+	elapsed := time.Since(ww.start).Milliseconds()
+	_, err := fmt.Fprintf(ww.w, "'%c%d%c%s',\n", direction, elapsed, direction, base64.StdEncoding.EncodeToString(data))
+	return err
+}
+
+// Finish writes the end of the record, no frames must be written afterwards.
+func (ww *WebsockifyWriter) Finish() error {
+	ww.mu.Lock()
+	defer ww.mu.Unlock()
+
+	_, err := io.WriteString(ww.w, "'EOF'];\n")
+	return err
+}
+
+// HTTPLogEntry describes a request that went through the HTTP tunnel proxy.
+type HTTPLogEntry struct {
+	Time       time.Time `json:"time"`
+	RemoteAddr string    `json:"remote_addr"`
+	Method     string    `json:"method"`
+	URL        string    `json:"url"`
+	Status     int       `json:"status"`
+	Bytes      int64     `json:"bytes"`
+	Duration   float64   `json:"duration_sec"`
+}
+
+// HTTPLogWriter writes a JSON object per line for each request.
+type HTTPLogWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewHTTPLogWriter(w io.Writer) *HTTPLogWriter {
+	return &HTTPLogWriter{
+		w: w,
+	}
+}
+
+func (hw *HTTPLogWriter) Log(entry *HTTPLogEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	hw.mu.Lock()
+	defer hw.mu.Unlock()
+
+	_, err = hw.w.Write(append(line, '\n'))
+	return err
+}
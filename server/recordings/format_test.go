@@ -0,0 +1,85 @@
+package recordings
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsciicastWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewAsciicastWriter(buf, 80, 24)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Input([]byte("ls\n")))
+	// "ä" is split between two chunks
+	require.NoError(t, w.Output([]byte{'a', 0xc3}))
+	require.NoError(t, w.Output([]byte{0xa4, '\n'}))
+	require.NoError(t, w.Resize(100, 30))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 5)
+
+	header := asciicastHeader{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &header))
+	assert.Equal(t, 2, header.Version)
+	assert.Equal(t, 80, header.Width)
+	assert.Equal(t, 24, header.Height)
+
+	var events [][]interface{}
+	for _, line := range lines[1:] {
+		var event []interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+		require.Len(t, event, 3)
+		events = append(events, event[1:])
+	}
+	assert.Equal(t, [][]interface{}{
+		{"i", "ls\n"},
+		{"o", "a"},
+		{"o", "ä\n"},
+		{"r", "100x30"},
+	}, events)
+}
+
+func TestWebsockifyWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewWebsockifyWriter(buf)
+	require.NoError(t, err)
+
+	require.NoError(t, w.FromServer([]byte("RFB 003.008\n")))
+	require.NoError(t, w.FromClient([]byte{1}))
+	require.NoError(t, w.Finish())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "var VNC_frame_data = [", lines[0])
+	assert.Regexp(t, `^'\{\d+\{UkZCIDAwMy4wMDgK',$`, lines[1])
+	assert.Regexp(t, `^'\}\d+\}AQ==',$`, lines[2])
+	assert.Equal(t, "'EOF'];", lines[3])
+}
+
+func TestCompleteUTF8Len(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     []byte
+		expected int
+	}{
+		{name: "empty", data: []byte{}, expected: 0},
+		{name: "ascii", data: []byte("abc"), expected: 3},
+		{name: "complete multi-byte", data: []byte("a€"), expected: 4},
+		{name: "incomplete 2 of 3 bytes", data: []byte("a€")[:3], expected: 1},
+		{name: "incomplete 1 of 3 bytes", data: []byte("a€")[:2], expected: 1},
+		{name: "invalid byte", data: []byte{'a', 0xff}, expected: 2},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, completeUTF8Len(tc.data))
+		})
+	}
+}
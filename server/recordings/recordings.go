@@ -0,0 +1,61 @@
+package recordings
+
+import (
+	"time"
+)
+
+// Type is the kind of session that was recorded.
+type Type string
+
+const (
+	TypeTerminal Type = "terminal"
+	TypeHTTP     Type = "http"
+	TypeVNC      Type = "vnc"
+	TypeRDP      Type = "rdp"
+)
+
+// Format is the file format of a recording.
+type Format string
+
+const (
+	// FormatAsciicast is asciicast v2, it can be played with asciinema.
+	FormatAsciicast Format = "asciicast"
+	// FormatGuacamole is a dump of the Guacamole protocol instructions sent to the browser,
+	// it can be played with guacenc or the Guacamole session recording player.
+	FormatGuacamole Format = "guacamole"
+	// FormatWebsockify is the record format of websockify, it can be played with the noVNC playback utility.
+	FormatWebsockify Format = "websockify"
+	// FormatHTTPLog contains a JSON object per line for each proxied HTTP request.
+	FormatHTTPLog Format = "http-log"
+)
+
+var formatsByType = map[Type]Format{
+	TypeTerminal: FormatAsciicast,
+	TypeHTTP:     FormatHTTPLog,
+	TypeVNC:      FormatWebsockify,
+	TypeRDP:      FormatGuacamole,
+}
+
+var fileExtensions = map[Format]string{
+	FormatAsciicast:  ".cast",
+	FormatGuacamole:  ".guac",
+	FormatWebsockify: ".websockify",
+	FormatHTTPLog:    ".jsonl",
+}
+
+// Recording describes a recorded session, the content is stored in a file in the recordings dir.
+type Recording struct {
+	ID     string `json:"id" db:"id"`
+	Type   Type   `json:"type" db:"type"`
+	Format Format `json:"format" db:"format"`
+	// ClientID is the client the session was established to.
+	ClientID string `json:"client_id" db:"client_id"`
+	// TunnelID is empty for sessions that don't go through a tunnel, like terminal sessions.
+	TunnelID string `json:"tunnel_id" db:"tunnel_id"`
+	// Username is the user who started the session, for tunnels it's the user who created the tunnel.
+	Username   string     `json:"username" db:"username"`
+	Filename   string     `json:"-" db:"filename"`
+	Size       int64      `json:"size" db:"size"`
+	StartedAt  time.Time  `json:"started_at" db:"started_at"`
+	FinishedAt *time.Time `json:"finished_at" db:"finished_at"`
+}
@@ -0,0 +1,249 @@
+package recordings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/random"
+)
+
+var (
+	supportedFilters = map[string]bool{
+		"id":        true,
+		"type":      true,
+		"client_id": true,
+		"tunnel_id": true,
+		"username":  true,
+	}
+	supportedSorts = map[string]bool{
+		"started_at":  true,
+		"finished_at": true,
+		"client_id":   true,
+		"username":    true,
+		"size":        true,
+	}
+	defaultSort = []query.SortOption{{Column: "started_at", IsASC: false}}
+)
+
+// Service keeps the index of the recorded sessions and stores the recordings as files in a dir.
+type Service struct {
+	provider Provider
+	dir      string
+	logger   *logger.Logger
+	now      func() time.Time
+}
+
+func NewService(provider Provider, dir string, logger *logger.Logger) (*Service, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create recordings dir %q: %v", dir, err)
+	}
+
+	return &Service{
+		provider: provider,
+		dir:      dir,
+		logger:   logger,
+		now:      time.Now,
+	}, nil
+}
+
+// Start creates a new recording. The recorded data is written to the returned session, it must be closed once the
+// recorded session ended.
+func (s *Service) Start(ctx context.Context, recType Type, clientID, tunnelID, username string) (*Session, error) {
+	format, ok := formatsByType[recType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported recording type %q", recType)
+	}
+
+	id, err := random.UUID4()
+	if err != nil {
+		return nil, err
+	}
+
+	recording := &Recording{
+		ID:        id,
+		Type:      recType,
+		Format:    format,
+		ClientID:  clientID,
+		TunnelID:  tunnelID,
+		Username:  username,
+		Filename:  id + fileExtensions[format],
+		StartedAt: s.now().UTC(),
+	}
+
+	file, err := os.OpenFile(s.path(recording), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording file: %v", err)
+	}
+
+	if err := s.provider.Save(ctx, recording); err != nil {
+		file.Close()
+		_ = os.Remove(file.Name())
+		return nil, fmt.Errorf("failed to save recording: %v", err)
+	}
+
+	return &Session{
+		service:   s,
+		recording: recording,
+		file:      file,
+	}, nil
+}
+
+func (s *Service) ListRecordings(ctx context.Context, options *query.ListOptions) (*api.SuccessPayload, error) {
+	err := query.ValidateListOptions(options, supportedSorts, supportedFilters, nil, &query.PaginationConfig{
+		DefaultLimit: 10,
+		MaxLimit:     100,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(options.Sorts) == 0 {
+		options.Sorts = defaultSort
+	}
+
+	entries, err := s.provider.List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.provider.Count(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.SuccessPayload{
+		Data: entries,
+		Meta: api.NewMeta(count),
+	}, nil
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*Recording, error) {
+	recording, err := s.provider.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if recording == nil {
+		return nil, errors2.APIError{
+			Message:    fmt.Sprintf("Recording with id %q not found.", id),
+			HTTPStatus: http.StatusNotFound,
+		}
+	}
+
+	return recording, nil
+}
+
+// Open returns the file of a recording, it must be closed by the caller.
+func (s *Service) Open(recording *Recording) (*os.File, error) {
+	file, err := os.Open(s.path(recording))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errors2.APIError{
+				Message:    fmt.Sprintf("File of recording with id %q not found.", recording.ID),
+				HTTPStatus: http.StatusNotFound,
+			}
+		}
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	recording, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.delete(ctx, recording)
+}
+
+// DeleteOlderThan deletes recordings of sessions that finished before the given period.
+func (s *Service) DeleteOlderThan(ctx context.Context, period time.Duration) (int, error) {
+	recordings, err := s.provider.ListFinishedBefore(ctx, s.now().Add(-period))
+	if err != nil {
+		return 0, err
+	}
+
+	for i, recording := range recordings {
+		if err := s.delete(ctx, recording); err != nil {
+			return i, err
+		}
+	}
+
+	return len(recordings), nil
+}
+
+func (s *Service) delete(ctx context.Context, recording *Recording) error {
+	err := os.Remove(s.path(recording))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete recording file: %v", err)
+	}
+
+	return s.provider.Delete(ctx, recording.ID)
+}
+
+func (s *Service) path(recording *Recording) string {
+	return filepath.Join(s.dir, recording.Filename)
+}
+
+func (s *Service) Close() error {
+	return s.provider.Close()
+}
+
+// Session is a recording in progress. It's safe for concurrent use.
+type Session struct {
+	service   *Service
+	recording *Recording
+
+	mu     sync.Mutex
+	file   *os.File
+	closed bool
+}
+
+// ID returns the id of the recording.
+func (s *Session) ID() string {
+	return s.recording.ID
+}
+
+func (s *Session) Write(data []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, os.ErrClosed
+	}
+
+	n, err := s.file.Write(data)
+	s.recording.Size += int64(n)
+	return n, err
+}
+
+// Close finishes the recording, it's a no-op if the session has been already closed.
+func (s *Session) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	err := s.file.Close()
+
+	finishedAt := s.service.now().UTC()
+	s.recording.FinishedAt = &finishedAt
+	if saveErr := s.service.provider.Save(context.Background(), s.recording); saveErr != nil {
+		return fmt.Errorf("failed to save recording: %v", saveErr)
+	}
+
+	return err
+}
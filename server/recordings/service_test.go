@@ -0,0 +1,134 @@
+package recordings
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+var testLog = logger.NewLogger("recordings", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug)
+
+func TestRecordSession(t *testing.T) {
+	ctx := context.Background()
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	defer dbProvider.Close()
+	service, err := NewService(dbProvider, filepath.Join(t.TempDir(), "recordings"), testLog)
+	require.NoError(t, err)
+
+	session, err := service.Start(ctx, TypeRDP, "client-1", "3", "admin")
+	require.NoError(t, err)
+
+	started, err := service.Get(ctx, session.ID())
+	require.NoError(t, err)
+	assert.Equal(t, TypeRDP, started.Type)
+	assert.Equal(t, FormatGuacamole, started.Format)
+	assert.Equal(t, "client-1", started.ClientID)
+	assert.Equal(t, "3", started.TunnelID)
+	assert.Equal(t, "admin", started.Username)
+	assert.Nil(t, started.FinishedAt)
+
+	_, err = session.Write([]byte("4.sync,1.0;"))
+	require.NoError(t, err)
+	require.NoError(t, session.Close())
+	// closing again is a no-op
+	require.NoError(t, session.Close())
+	_, err = session.Write([]byte("more"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+
+	recording, err := service.Get(ctx, session.ID())
+	require.NoError(t, err)
+	assert.NotNil(t, recording.FinishedAt)
+	assert.EqualValues(t, 11, recording.Size)
+
+	file, err := service.Open(recording)
+	require.NoError(t, err)
+	defer file.Close()
+	content, err := os.ReadFile(file.Name())
+	require.NoError(t, err)
+	assert.Equal(t, "4.sync,1.0;", string(content))
+}
+
+func TestStartUnsupportedType(t *testing.T) {
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	defer dbProvider.Close()
+	service, err := NewService(dbProvider, filepath.Join(t.TempDir(), "recordings"), testLog)
+	require.NoError(t, err)
+
+	_, err = service.Start(context.Background(), Type("ssh"), "client-1", "", "admin")
+	assert.EqualError(t, err, `unsupported recording type "ssh"`)
+}
+
+func TestDeleteRecording(t *testing.T) {
+	ctx := context.Background()
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	defer dbProvider.Close()
+	service, err := NewService(dbProvider, filepath.Join(t.TempDir(), "recordings"), testLog)
+	require.NoError(t, err)
+
+	session, err := service.Start(ctx, TypeTerminal, "client-1", "", "admin")
+	require.NoError(t, err)
+	require.NoError(t, session.Close())
+	recording, err := service.Get(ctx, session.ID())
+	require.NoError(t, err)
+
+	require.NoError(t, service.Delete(ctx, session.ID()))
+
+	result, err := service.ListRecordings(ctx, &query.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Meta.Count)
+	_, err = os.Stat(service.path(recording))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	err = service.Delete(ctx, session.ID())
+	assert.Equal(t, errors2.APIError{
+		Message:    `Recording with id "` + session.ID() + `" not found.`,
+		HTTPStatus: http.StatusNotFound,
+	}, err)
+}
+
+func TestDeleteOlderThan(t *testing.T) {
+	ctx := context.Background()
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	defer dbProvider.Close()
+	service, err := NewService(dbProvider, filepath.Join(t.TempDir(), "recordings"), testLog)
+	require.NoError(t, err)
+
+	now := time.Date(2022, 1, 10, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now.Add(-48 * time.Hour) }
+	old, err := service.Start(ctx, TypeVNC, "client-1", "1", "admin")
+	require.NoError(t, err)
+	require.NoError(t, old.Close())
+	// a long running session started at the same time is still recorded
+	running, err := service.Start(ctx, TypeVNC, "client-1", "2", "admin")
+	require.NoError(t, err)
+	defer running.Close()
+
+	service.now = func() time.Time { return now }
+	recent, err := service.Start(ctx, TypeVNC, "client-1", "1", "admin")
+	require.NoError(t, err)
+	require.NoError(t, recent.Close())
+
+	deleted, err := service.DeleteOlderThan(ctx, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	recorded, err := dbProvider.List(ctx, &query.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, recorded, 2)
+	assert.ElementsMatch(t, []string{running.ID(), recent.ID()}, []string{recorded[0].ID, recorded[1].ID})
+}
@@ -0,0 +1,127 @@
+package recordings
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	recordingsmigration "github.com/cloudradar-monitoring/rport/db/migration/recordings"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+type Provider interface {
+	Save(ctx context.Context, recording *Recording) error
+	Get(ctx context.Context, id string) (*Recording, error)
+	List(ctx context.Context, options *query.ListOptions) ([]*Recording, error)
+	Count(ctx context.Context, options *query.ListOptions) (int, error)
+	ListFinishedBefore(ctx context.Context, before time.Time) ([]*Recording, error)
+	Delete(ctx context.Context, id string) error
+	Close() error
+}
+
+type SqliteProvider struct {
+	db        *sqlx.DB
+	converter *query.SQLConverter
+}
+
+func NewSqliteProvider(dbPath string, dbOptions database.Options) (*SqliteProvider, error) {
+	db, err := database.Open("recordings", dbPath, recordingsmigration.AssetNames(), recordingsmigration.Asset, dbOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recordings DB instance: %v", err)
+	}
+
+	return &SqliteProvider{
+		db:        db,
+		converter: query.NewSQLConverter(db.DriverName()),
+	}, nil
+}
+
+func (p *SqliteProvider) Save(ctx context.Context, recording *Recording) error {
+	_, err := p.db.NamedExecContext(ctx,
+		`INSERT INTO recordings (
+			id,
+			type,
+			format,
+			client_id,
+			tunnel_id,
+			username,
+			filename,
+			size,
+			started_at,
+			finished_at
+		) VALUES (
+			:id,
+			:type,
+			:format,
+			:client_id,
+			:tunnel_id,
+			:username,
+			:filename,
+			:size,
+			:started_at,
+			:finished_at
+		) ON CONFLICT(id) DO UPDATE SET
+			size = :size,
+			finished_at = :finished_at`,
+		recording,
+	)
+	return err
+}
+
+func (p *SqliteProvider) Get(ctx context.Context, id string) (*Recording, error) {
+	recording := &Recording{}
+	err := p.db.GetContext(ctx, recording, p.db.Rebind("SELECT * FROM recordings WHERE id = ?"), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return recording, nil
+}
+
+func (p *SqliteProvider) List(ctx context.Context, options *query.ListOptions) ([]*Recording, error) {
+	values := []*Recording{}
+
+	q, params := p.converter.ConvertListOptionsToQuery(options, "SELECT * FROM recordings")
+
+	err := p.db.SelectContext(ctx, &values, p.db.Rebind(q), params...)
+	return values, err
+}
+
+func (p *SqliteProvider) Count(ctx context.Context, options *query.ListOptions) (int, error) {
+	var result int
+
+	countOptions := *options
+	countOptions.Pagination = nil
+	countOptions.Sorts = nil
+	q, params := p.converter.ConvertListOptionsToQuery(&countOptions, "SELECT COUNT(*) FROM recordings")
+
+	err := p.db.GetContext(ctx, &result, p.db.Rebind(q), params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// ListFinishedBefore returns the recordings finished before the given time, recordings in progress are never returned
+func (p *SqliteProvider) ListFinishedBefore(ctx context.Context, before time.Time) ([]*Recording, error) {
+	values := []*Recording{}
+	err := p.db.SelectContext(ctx, &values, p.db.Rebind("SELECT * FROM recordings WHERE finished_at IS NOT NULL AND finished_at < ?"), before)
+	return values, err
+}
+
+func (p *SqliteProvider) Delete(ctx context.Context, id string) error {
+	_, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM recordings WHERE id = ?"), id)
+	return err
+}
+
+func (p *SqliteProvider) Close() error {
+	return p.db.Close()
+}
@@ -0,0 +1,72 @@
+package recordings
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+func TestSqliteProvider(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		provider, err := NewSqliteProvider(":memory:", database.Options{PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		defer provider.Close()
+
+		startedAt := time.Date(2022, 10, 16, 12, 0, 0, 0, time.UTC)
+		terminal := &Recording{ID: "rec-1", Type: TypeTerminal, Format: FormatAsciicast, ClientID: "client-1", Username: "admin", Filename: "rec-1.cast", StartedAt: startedAt}
+		tunnel := &Recording{ID: "rec-2", Type: TypeHTTP, Format: FormatHTTPLog, ClientID: "client-2", TunnelID: "1", Username: "admin", Filename: "rec-2.log", StartedAt: startedAt.Add(time.Minute)}
+		require.NoError(t, provider.Save(ctx, terminal))
+		require.NoError(t, provider.Save(ctx, tunnel))
+
+		// finishing a recording updates its size and end
+		finishedAt := startedAt.Add(time.Hour)
+		terminal.Size = 1024
+		terminal.FinishedAt = &finishedAt
+		require.NoError(t, provider.Save(ctx, terminal))
+
+		got, err := provider.Get(ctx, "rec-1")
+		require.NoError(t, err)
+		require.NotNil(t, got)
+		assert.EqualValues(t, 1024, got.Size)
+		assert.Equal(t, "rec-1.cast", got.Filename)
+		assert.True(t, startedAt.Equal(got.StartedAt))
+		require.NotNil(t, got.FinishedAt)
+		assert.True(t, finishedAt.Equal(*got.FinishedAt))
+
+		got, err = provider.Get(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+
+		options := &query.ListOptions{
+			Filters:    []query.FilterOption{{Column: []string{"username"}, Values: []string{"admin"}}},
+			Sorts:      []query.SortOption{{Column: "started_at", IsASC: false}},
+			Pagination: query.NewPagination(1, 0),
+		}
+		list, err := provider.List(ctx, options)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, "rec-2", list[0].ID)
+		assert.Equal(t, "1", list[0].TunnelID)
+		count, err := provider.Count(ctx, options)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		finished, err := provider.ListFinishedBefore(ctx, finishedAt.Add(time.Second))
+		require.NoError(t, err)
+		require.Len(t, finished, 1)
+		assert.Equal(t, "rec-1", finished[0].ID)
+
+		require.NoError(t, provider.Delete(ctx, "rec-1"))
+		got, err = provider.Get(ctx, "rec-1")
+		require.NoError(t, err)
+		assert.Nil(t, got)
+	})
+}
@@ -0,0 +1,33 @@
+package recordings
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+type CleanupTask struct {
+	log     *logger.Logger
+	service *Service
+	period  time.Duration
+}
+
+// NewCleanupTask returns a task to delete recordings after configured period
+func NewCleanupTask(log *logger.Logger, service *Service, period time.Duration) *CleanupTask {
+	return &CleanupTask{
+		log:     log,
+		service: service,
+		period:  period,
+	}
+}
+
+func (t *CleanupTask) Run(ctx context.Context) error {
+	deleted, err := t.service.DeleteOlderThan(ctx, t.period)
+	if err != nil {
+		return fmt.Errorf("failed to cleanup recordings: %v", err)
+	}
+	t.log.Debugf("recordings.CleanupTask: %d recordings deleted", deleted)
+	return nil
+}
@@ -11,6 +11,13 @@ const (
 	ParamScriptValueID  = "script_value_id"
 	ParamCommandValueID = "command_value_id"
 	ParamGraphName      = "graph_name"
+	ParamAlertRuleID    = "rule_id"
+	ParamRecordingID    = "recording_id"
+	ParamAPIKeyID       = "api_key_id"
+	ParamApprovalID     = "approval_id"
+	ParamProfileID      = "profile_id"
+	ParamWebhookID      = "webhook_id"
+	ParamShareID        = "share_id"
 
 	AllRoutesPrefix         = "/api/v1"
 	AuthRoutesPrefix        = "/auth"
@@ -18,6 +25,13 @@ const (
 	AuthSettingsRoute       = "/ext/settings"
 	AuthDeviceSettingsRoute = "/ext/settings/device"
 	TotPRoutes              = "/me/totp-secret"
+	MeRoute                 = "/me"
+	MeTokenRoute            = "/me/token"
+	MeAPIKeysRoute          = "/me/api-keys"
 	Verify2FaRoute          = "/verify-2fa"
 	FilesUploadRouteName    = "files"
+
+	ClusterRoutesPrefix        = "/cluster"
+	ClusterClientRequestsRoute = ClusterRoutesPrefix + "/client-requests"
+	ClusterJobResultsRoute     = ClusterRoutesPrefix + "/job-results"
 )
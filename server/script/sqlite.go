@@ -38,11 +38,11 @@ func (p *SqliteProvider) Close() error {
 }
 
 func (p *SqliteProvider) GetByID(ctx context.Context, id string, ro *query.RetrieveOptions) (val *Script, found bool, err error) {
-	q := "SELECT * FROM `scripts` WHERE `id` = ? LIMIT 1"
+	q := "SELECT * FROM scripts WHERE id = ? LIMIT 1"
 	q = p.converter.ConvertRetrieveOptionsToQuery(ro, q)
 
 	val = new(Script)
-	err = p.db.GetContext(ctx, val, q, id)
+	err = p.db.GetContext(ctx, val, p.db.Rebind(q), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return val, false, nil
@@ -57,11 +57,11 @@ func (p *SqliteProvider) GetByID(ctx context.Context, id string, ro *query.Retri
 func (p *SqliteProvider) List(ctx context.Context, lo *query.ListOptions) ([]Script, error) {
 	values := []Script{}
 
-	q := "SELECT * FROM `scripts`"
+	q := "SELECT * FROM scripts"
 
 	q, params := p.converter.ConvertListOptionsToQuery(lo, q)
 
-	err := p.db.SelectContext(ctx, &values, q, params...)
+	err := p.db.SelectContext(ctx, &values, p.db.Rebind(q), params...)
 	if err != nil {
 		return values, err
 	}
@@ -79,8 +79,8 @@ func (p *SqliteProvider) Save(ctx context.Context, s *Script, nowDate time.Time)
 
 		_, err = p.db.NamedExecContext(
 			ctx,
-			"INSERT INTO `scripts`"+
-				" (`id`, `name`, `created_at`, `created_by`, `interpreter`, `is_sudo`, `cwd`, `script`, `updated_at`, `updated_by`, `tags`, `timeout_sec`)"+
+			"INSERT INTO scripts"+
+				" (id, name, created_at, created_by, interpreter, is_sudo, cwd, script, updated_at, updated_by, tags, timeout_sec)"+
 				" VALUES "+
 				"(:id, :name, :created_at, :created_by, :interpreter, :is_sudo, :cwd, :script, :updated_at, :updated_by, :tags, :timeout_sec)",
 			s,
@@ -89,16 +89,16 @@ func (p *SqliteProvider) Save(ctx context.Context, s *Script, nowDate time.Time)
 		return scriptID, err
 	}
 
-	q := "UPDATE `scripts` SET " +
-		"`name` = :name, " +
-		"`interpreter` = :interpreter, " +
-		"`is_sudo` = :is_sudo, " +
-		"`cwd` = :cwd, " +
-		"`script` = :script, " +
-		"`updated_at` = :updated_at, " +
-		"`updated_by` = :updated_by, " +
-		"`tags` = :tags, " +
-		"`timeout_sec` = :timeout_sec" +
+	q := "UPDATE scripts SET " +
+		"name = :name, " +
+		"interpreter = :interpreter, " +
+		"is_sudo = :is_sudo, " +
+		"cwd = :cwd, " +
+		"script = :script, " +
+		"updated_at = :updated_at, " +
+		"updated_by = :updated_by, " +
+		"tags = :tags, " +
+		"timeout_sec = :timeout_sec" +
 		" WHERE id = :id "
 
 	_, err := p.db.NamedExecContext(ctx, q, s)
@@ -107,7 +107,7 @@ func (p *SqliteProvider) Save(ctx context.Context, s *Script, nowDate time.Time)
 }
 
 func (p *SqliteProvider) Delete(ctx context.Context, id string) error {
-	res, err := p.db.ExecContext(ctx, "DELETE FROM `scripts` WHERE `id` = ?", id)
+	res, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM scripts WHERE id = ?"), id)
 
 	if err != nil {
 		return err
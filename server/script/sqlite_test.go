@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/migration/library"
 	"github.com/cloudradar-monitoring/rport/db/sqlite"
 	"github.com/cloudradar-monitoring/rport/share/ptr"
@@ -354,3 +355,49 @@ func addDemoData(db *sqlx.DB) error {
 
 	return nil
 }
+
+func TestProviderDatabases(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		db, err := database.Open("library", ":memory:", library.AssetNames(), library.Asset, database.Options{SQLite: DataSourceOptions, PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		dbProv := NewSqliteProvider(db)
+		defer dbProv.Close()
+		ctx := context.Background()
+
+		for i := range demoData {
+			item := demoData[i]
+			item.ID = ""
+			_, err := dbProv.Save(ctx, &item, *item.CreatedAt)
+			require.NoError(t, err)
+		}
+		scripts, err := dbProv.List(ctx, &query.ListOptions{Sorts: []query.SortOption{{Column: "name", IsASC: true}}})
+		require.NoError(t, err)
+		require.Len(t, scripts, 2)
+		id := scripts[1].ID
+		assert.Equal(t, "some name", scripts[1].Name)
+
+		item := demoData[0]
+		item.ID = id
+		item.Script = "awk"
+		item.Tags = ptr.StringSlice("tag3")
+		_, err = dbProv.Save(ctx, &item, time.Date(2012, 1, 1, 1, 0, 0, 0, time.UTC))
+		require.NoError(t, err)
+
+		val, found, err := dbProv.GetByID(ctx, id, &query.RetrieveOptions{})
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, "awk", val.Script)
+		assert.Equal(t, ptr.StringSlice("tag3"), val.Tags)
+		assert.False(t, *val.IsSudo)
+		assert.Equal(t, timeoutSec, *val.TimoutSec)
+		assert.True(t, demoData[0].CreatedAt.Equal(*val.CreatedAt))
+
+		scripts, err = dbProv.List(ctx, &query.ListOptions{Filters: []query.FilterOption{{Column: []string{"is_sudo"}, Values: []string{"0"}}}})
+		require.NoError(t, err)
+		require.Len(t, scripts, 1)
+		assert.Equal(t, id, scripts[0].ID)
+
+		require.NoError(t, dbProv.Delete(ctx, id))
+		assert.EqualError(t, dbProv.Delete(ctx, id), "cannot find entry by id "+id)
+	})
+}
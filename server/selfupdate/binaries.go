@@ -0,0 +1,235 @@
+package selfupdate
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-version"
+)
+
+const (
+	binaryName    = "rport"
+	signatureExt  = ".sig"
+	platformSplit = "_"
+)
+
+// Binary is a signed client binary of a version built for an os and architecture
+type Binary struct {
+	Version string `json:"version"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+	Size    int64  `json:"size"`
+	SHA256  string `json:"sha256"`
+
+	Path      string `json:"-"`
+	Signature []byte `json:"-"`
+}
+
+type checksum struct {
+	size    int64
+	modTime time.Time
+	sha256  string
+}
+
+// Binaries are the client binaries hosted in a folder. Each version has its own sub folder, containing
+// a <os>_<arch> sub folder per platform, e.g. 0.9.0/linux_amd64/rport and 0.9.0/windows_amd64/rport.exe.
+// Each binary must be accompanied by a file with the .sig extension, holding the ed25519 signature of its manifest
+// as returned by comm.SelfUpdateRequest.Manifest.
+type Binaries struct {
+	dir string
+
+	mu        sync.Mutex
+	checksums map[string]checksum
+}
+
+func NewBinaries(dir string) *Binaries {
+	return &Binaries{
+		dir:       dir,
+		checksums: make(map[string]checksum),
+	}
+}
+
+// List returns all binaries ordered by version, newest first. Binaries without a signature are skipped.
+func (b *Binaries) List() ([]*Binary, error) {
+	versionDirs, err := os.ReadDir(b.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read client binaries dir: %w", err)
+	}
+
+	var result []*Binary
+	for _, versionDir := range versionDirs {
+		if !versionDir.IsDir() {
+			continue
+		}
+		platformDirs, err := os.ReadDir(filepath.Join(b.dir, versionDir.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client binaries dir: %w", err)
+		}
+		for _, platformDir := range platformDirs {
+			osName, arch, ok := strings.Cut(platformDir.Name(), platformSplit)
+			if !platformDir.IsDir() || !ok {
+				continue
+			}
+			bin, err := b.get(versionDir.Name(), osName, arch)
+			if err != nil {
+				return nil, err
+			}
+			if bin != nil {
+				result = append(result, bin)
+			}
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return compareVersions(result[i].Version, result[j].Version) > 0
+	})
+
+	return result, nil
+}
+
+// Find returns the binary of the given version for the given platform, the newest one if the version is empty.
+// Returns nil if there is no such binary.
+func (b *Binaries) Find(v, osName, arch string) (*Binary, error) {
+	if v != "" {
+		return b.get(v, osName, arch)
+	}
+
+	all, err := b.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, bin := range all {
+		if bin.OS == osName && bin.Arch == arch {
+			return bin, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (b *Binaries) get(v, osName, arch string) (*Binary, error) {
+	// the version is given by API users, it must not escape the binaries dir
+	for _, part := range []string{v, osName, arch} {
+		if part == "" || part != filepath.Base(part) || part == ".." {
+			return nil, nil
+		}
+	}
+
+	name := binaryName
+	if osName == "windows" {
+		name += ".exe"
+	}
+	path := filepath.Join(b.dir, v, osName+platformSplit+arch, name)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read client binary: %w", err)
+	}
+
+	signature, err := readSignature(path + signatureExt)
+	if err != nil {
+		return nil, err
+	}
+	if signature == nil {
+		return nil, nil
+	}
+
+	sum, err := b.checksum(path, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Binary{
+		Version:   v,
+		OS:        osName,
+		Arch:      arch,
+		Size:      info.Size(),
+		SHA256:    sum,
+		Path:      path,
+		Signature: signature,
+	}, nil
+}
+
+func (b *Binaries) checksum(path string, info os.FileInfo) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cached, ok := b.checksums[path]
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.sha256, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read client binary: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read client binary: %w", err)
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	b.checksums[path] = checksum{
+		size:    info.Size(),
+		modTime: info.ModTime(),
+		sha256:  sum,
+	}
+
+	return sum, nil
+}
+
+// readSignature reads a raw or base64 encoded signature, returns nil if the file does not exist
+func readSignature(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read client binary signature: %w", err)
+	}
+
+	if len(data) == ed25519.SignatureSize {
+		return data, nil
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("invalid client binary signature in %s", path)
+	}
+
+	return signature, nil
+}
+
+// compareVersions compares semantic versions, versions that can't be parsed are considered older
+func compareVersions(a, b string) int {
+	va, errA := version.NewVersion(a)
+	vb, errB := version.NewVersion(b)
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+
+	return va.Compare(vb)
+}
@@ -0,0 +1,89 @@
+package selfupdate
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeBinary(t *testing.T, dir, v, platform, name string, signature []byte) string {
+	path := filepath.Join(dir, v, platform, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(v+" "+platform), 0755))
+	if signature != nil {
+		require.NoError(t, os.WriteFile(path+signatureExt, signature, 0644))
+	}
+	return path
+}
+
+func TestBinaries(t *testing.T) {
+	dir := t.TempDir()
+	signature := make([]byte, ed25519.SignatureSize)
+	signature[0] = 1
+
+	writeBinary(t, dir, "0.9.0", "linux_amd64", "rport", signature)
+	writeBinary(t, dir, "0.10.0", "linux_amd64", "rport", []byte(base64.StdEncoding.EncodeToString(signature)+"\n"))
+	writeBinary(t, dir, "0.10.0", "windows_amd64", "rport.exe", signature)
+	writeBinary(t, dir, "0.10.1", "linux_amd64", "rport", nil)
+	writeBinary(t, dir, "0.9.5", "linux_arm64", "rport", signature)
+
+	binaries := NewBinaries(dir)
+
+	all, err := binaries.List()
+	require.NoError(t, err)
+	var got []string
+	for _, bin := range all {
+		got = append(got, bin.Version+"/"+bin.OS+"_"+bin.Arch)
+	}
+	assert.Equal(t, []string{"0.10.0/linux_amd64", "0.10.0/windows_amd64", "0.9.5/linux_arm64", "0.9.0/linux_amd64"}, got)
+
+	latest, err := binaries.Find("", "linux", "amd64")
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	sum := sha256.Sum256([]byte("0.10.0 linux_amd64"))
+	assert.Equal(t, &Binary{
+		Version:   "0.10.0",
+		OS:        "linux",
+		Arch:      "amd64",
+		Size:      18,
+		SHA256:    hex.EncodeToString(sum[:]),
+		Path:      filepath.Join(dir, "0.10.0", "linux_amd64", "rport"),
+		Signature: signature,
+	}, latest)
+
+	bin, err := binaries.Find("0.9.0", "linux", "amd64")
+	require.NoError(t, err)
+	require.NotNil(t, bin)
+	assert.Equal(t, "0.9.0", bin.Version)
+
+	bin, err = binaries.Find("0.10.1", "linux", "amd64")
+	require.NoError(t, err)
+	assert.Nil(t, bin, "binary without signature")
+
+	bin, err = binaries.Find("", "darwin", "amd64")
+	require.NoError(t, err)
+	assert.Nil(t, bin)
+
+	bin, err = binaries.Find("..", "linux", "amd64")
+	require.NoError(t, err)
+	assert.Nil(t, bin)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0.9.0", "linux_amd64", "rport.sig"), []byte("invalid"), 0644))
+	_, err = binaries.Find("0.9.0", "linux", "amd64")
+	assert.EqualError(t, err, "invalid client binary signature in "+filepath.Join(dir, "0.9.0", "linux_amd64", "rport.sig"))
+}
+
+func TestBinariesMissingDir(t *testing.T) {
+	binaries := NewBinaries(filepath.Join(t.TempDir(), "missing"))
+
+	all, err := binaries.List()
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
@@ -0,0 +1,23 @@
+package selfupdate
+
+import (
+	"errors"
+)
+
+type Config struct {
+	Enabled bool `mapstructure:"enabled"`
+	// BinariesDir contains the client binaries in <version>/<os>_<arch>/ sub folders
+	BinariesDir string `mapstructure:"binaries_dir"`
+}
+
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.BinariesDir == "" {
+		return errors.New("client-self-update.binaries_dir cannot be empty")
+	}
+
+	return nil
+}
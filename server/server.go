@@ -19,22 +19,32 @@ import (
 
 	"github.com/patrickmn/go-cache"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/migration/client_groups"
 	clientsmigration "github.com/cloudradar-monitoring/rport/db/migration/clients"
 	jobsmigration "github.com/cloudradar-monitoring/rport/db/migration/jobs"
-	"github.com/cloudradar-monitoring/rport/db/sqlite"
 	rportplus "github.com/cloudradar-monitoring/rport/plus"
+	"github.com/cloudradar-monitoring/rport/server/alerts"
 	"github.com/cloudradar-monitoring/rport/server/api/jobs"
 	"github.com/cloudradar-monitoring/rport/server/api/jobs/schedule"
 	"github.com/cloudradar-monitoring/rport/server/api/session"
+	"github.com/cloudradar-monitoring/rport/server/approvals"
 	"github.com/cloudradar-monitoring/rport/server/auditlog"
 	"github.com/cloudradar-monitoring/rport/server/cgroups"
 	"github.com/cloudradar-monitoring/rport/server/chconfig"
 	"github.com/cloudradar-monitoring/rport/server/clients"
 	"github.com/cloudradar-monitoring/rport/server/clientsauth"
+	"github.com/cloudradar-monitoring/rport/server/cluster"
+	"github.com/cloudradar-monitoring/rport/server/configprofiles"
+	"github.com/cloudradar-monitoring/rport/server/inventory"
 	"github.com/cloudradar-monitoring/rport/server/monitoring"
 	"github.com/cloudradar-monitoring/rport/server/ports"
+	"github.com/cloudradar-monitoring/rport/server/recordings"
 	"github.com/cloudradar-monitoring/rport/server/scheduler"
+	"github.com/cloudradar-monitoring/rport/server/selfupdate"
+	"github.com/cloudradar-monitoring/rport/server/tunnelhistory"
+	"github.com/cloudradar-monitoring/rport/server/tunnelshares"
+	"github.com/cloudradar-monitoring/rport/server/webhooks"
 	chshare "github.com/cloudradar-monitoring/rport/share"
 	"github.com/cloudradar-monitoring/rport/share/capabilities"
 	"github.com/cloudradar-monitoring/rport/share/files"
@@ -44,10 +54,17 @@ import (
 )
 
 const (
-	cleanupMeasurementsInterval = time.Minute * 2
-	cleanupAPISessionsInterval  = time.Hour
-	cleanupJobsInterval         = time.Hour
-	LogNumGoRoutinesInterval    = time.Minute * 2
+	cleanupMeasurementsInterval  = time.Minute * 2
+	cleanupInventoryInterval     = time.Hour
+	cleanupAPISessionsInterval   = time.Hour
+	cleanupJobsInterval          = time.Hour
+	cleanupAlertsInterval        = time.Hour
+	cleanupRecordingsInterval    = time.Hour
+	cleanupWebhooksInterval      = time.Hour
+	cleanupTunnelHistoryInterval = time.Hour
+	cleanupTunnelSharesInterval  = time.Hour
+	expireApprovalsInterval      = time.Minute
+	LogNumGoRoutinesInterval     = time.Minute * 2
 )
 
 // Server represents a rport service
@@ -61,11 +78,22 @@ type Server struct {
 	clientAuthProvider  clientsauth.Provider
 	jobProvider         JobProvider
 	clientGroupProvider cgroups.ClientGroupProvider
+	configProfiles      configprofiles.Provider
 	monitoringService   monitoring.Service
+	inventoryService    *inventory.Service
+	alertsService       *alerts.Service        // nil when alerting is disabled
+	recordingsService   *recordings.Service    // nil when session recording is disabled
+	approvalsService    *approvals.Service     // nil when approvals are disabled
+	webhooksService     *webhooks.Service      // nil when webhooks are disabled
+	tunnelHistory       *tunnelhistory.Service // nil when the tunnel history is disabled
+	tunnelShares        *tunnelshares.Service  // nil when tunnel sharing is disabled
+	clusterService      *cluster.Service       // nil when clustering is disabled
+	clientBinaries      *selfupdate.Binaries   // nil when client self-update is disabled
 	authDB              *sqlx.DB
 	uiJobWebSockets     ws.WebSocketCache // used to push job result to UI
 	uploadWebSockets    sync.Map
 	jobsDoneChannel     jobResultChanMap // used for sequential command execution to know when command is finished
+	runningMultiJobs    sync.Map         // cancel funcs of multi-client jobs that are still starting jobs on clients
 	auditLog            *auditlog.AuditLog
 	capabilities        *models.Capabilities
 	scheduleManager     *schedule.Manager
@@ -117,11 +145,12 @@ func NewServer(ctx context.Context, config *chconfig.Config, opts *ServerOpts) (
 		s.Errorf("Failed to store fingerprint %q in file %q: %v", fingerprint, fingerprintFile, err)
 	}
 
-	jobsDB, err := sqlite.New(
+	jobsDB, err := database.Open(
+		"jobs",
 		path.Join(config.Server.DataDir, "jobs.db"),
 		jobsmigration.AssetNames(),
 		jobsmigration.Asset,
-		config.Server.GetSQLiteDataSourceOptions(),
+		config.GetDatabaseOptions(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create jobs DB instance: %v", err)
@@ -129,11 +158,12 @@ func NewServer(ctx context.Context, config *chconfig.Config, opts *ServerOpts) (
 
 	s.jobProvider = jobs.NewSqliteProvider(jobsDB, s.Logger)
 
-	groupsDB, err := sqlite.New(
+	groupsDB, err := database.Open(
+		"client_groups",
 		path.Join(config.Server.DataDir, "client_groups.db"),
 		client_groups.AssetNames(),
 		client_groups.Asset,
-		config.Server.GetSQLiteDataSourceOptions(),
+		config.GetDatabaseOptions(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create client_groups DB instance: %v", err)
@@ -144,10 +174,18 @@ func NewServer(ctx context.Context, config *chconfig.Config, opts *ServerOpts) (
 		return nil, err
 	}
 
+	s.configProfiles, err = configprofiles.NewSqliteProvider(
+		path.Join(config.Server.DataDir, "config_profiles.db"),
+		config.GetDatabaseOptions(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	// create monitoringProvider and monitoringService
 	monitoringProvider, err := monitoring.NewSqliteProvider(
 		path.Join(config.Server.DataDir, "monitoring.db"),
-		config.Server.GetSQLiteDataSourceOptions(),
+		config.GetDatabaseOptions(),
 		s.Logger,
 	)
 	if err != nil {
@@ -155,11 +193,84 @@ func NewServer(ctx context.Context, config *chconfig.Config, opts *ServerOpts) (
 	}
 	s.monitoringService = monitoring.NewService(monitoringProvider)
 
-	s.clientDB, err = sqlite.New(
+	inventoryProvider, err := inventory.NewSqliteProvider(
+		path.Join(config.Server.DataDir, "inventory.db"),
+		config.GetDatabaseOptions(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.inventoryService = inventory.NewService(inventoryProvider)
+
+	if config.Alerts.Enabled {
+		s.alertsService, err = initAlertsService(ctx, config, s.Logger)
+		if err != nil {
+			return nil, err
+		}
+		s.Infof("Alerting is enabled")
+	}
+
+	if config.Recordings.Enabled {
+		s.recordingsService, err = initRecordingsService(config, s.Logger)
+		if err != nil {
+			return nil, err
+		}
+		s.Infof("Session recording is enabled")
+	}
+
+	if config.Approvals.Enabled {
+		s.approvalsService, err = initApprovalsService(config, s.Logger)
+		if err != nil {
+			return nil, err
+		}
+		s.Infof("Approvals are enabled")
+	}
+
+	if config.Webhooks.Enabled {
+		s.webhooksService, err = initWebhooksService(config, s.Logger)
+		if err != nil {
+			return nil, err
+		}
+		s.Infof("Webhooks are enabled")
+	}
+
+	if config.TunnelHistory.Enabled {
+		s.tunnelHistory, err = initTunnelHistoryService(config, s.Logger)
+		if err != nil {
+			return nil, err
+		}
+		s.Infof("Tunnel history is enabled")
+	}
+
+	if config.TunnelShares.Enabled {
+		s.tunnelShares, err = initTunnelSharesService(config, s.Logger)
+		if err != nil {
+			return nil, err
+		}
+		// tunnel proxies use the service to authorize the users of share links
+		s.config.Server.TunnelProxyConfig.Shares = s.tunnelShares
+		s.Infof("Tunnel sharing is enabled")
+	}
+
+	if config.Cluster.Enabled {
+		s.clusterService, err = initClusterService(ctx, config, s.Logger)
+		if err != nil {
+			return nil, err
+		}
+		s.Infof("Clustering is enabled, node id: %s", config.Cluster.NodeID)
+	}
+
+	if config.SelfUpdate.Enabled {
+		s.clientBinaries = selfupdate.NewBinaries(config.SelfUpdate.BinariesDir)
+		s.Infof("Client self-update is enabled, client binaries are hosted in %s", config.SelfUpdate.BinariesDir)
+	}
+
+	s.clientDB, err = database.Open(
+		"clients",
 		path.Join(config.Server.DataDir, "clients.db"),
 		clientsmigration.AssetNames(),
 		clientsmigration.Asset,
-		config.Server.GetSQLiteDataSourceOptions(),
+		config.GetDatabaseOptions(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create clients DB instance: %v", err)
@@ -171,28 +282,38 @@ func NewServer(ctx context.Context, config *chconfig.Config, opts *ServerOpts) (
 		keepDisconnectedClients = &config.Server.KeepDisconnectedClients
 	}
 
-	s.clientService, err = InitClientService(
+	clientService, err := InitClientService(
 		ctx,
 		&s.config.Server.TunnelProxyConfig,
 		ports.NewPortDistributor(config.AllowedPorts()),
 		s.clientDB,
 		keepDisconnectedClients,
+		s.remoteClientIDs(),
 		s.Logger,
 	)
 	if err != nil {
 		return nil, err
 	}
+	clientService.SetTunnelRateLimits(config.Server.ClientTunnelRateLimitUp, config.Server.ClientTunnelRateLimitDown)
+	clientService.SetTunnelHistory(s.tunnelHistory)
+	clientService.SetWebhooks(s.webhooksService)
+	// tunnel proxies use the service to record the sessions going through them
+	clientService.SetRecordings(s.recordingsService)
+	s.clientService = clientService
 
 	s.auditLog, err = auditlog.New(
 		logger.NewLogger("auditlog", config.Logging.LogOutput, config.Logging.LogLevel),
 		s.clientService,
 		s.config.Server.DataDir,
 		s.config.API.AuditLog,
-		s.config.Server.GetSQLiteDataSourceOptions(),
+		s.config.GetDatabaseOptions(),
 	)
 	if err != nil {
 		return nil, err
 	}
+	if s.tunnelShares != nil {
+		s.tunnelShares.SetAccessListener(s.onTunnelShareAccess)
+	}
 
 	if config.Database.Driver != "" {
 		s.authDB, err = sqlx.Connect(config.Database.Driver, config.Database.Dsn)
@@ -225,6 +346,20 @@ func NewServer(ctx context.Context, config *chconfig.Config, opts *ServerOpts) (
 	if err != nil {
 		return nil, err
 	}
+	if s.webhooksService != nil {
+		s.scheduleManager.SetRunListener(s.onScheduleRun)
+	}
+
+	if s.clusterService != nil {
+		s.scheduleManager.SetRunCondition(s.clusterService.IsLeader)
+		if s.alertsService != nil {
+			s.alertsService.SetRunCondition(s.clusterService.IsLeader)
+		}
+		err = s.syncCluster(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return s, nil
 }
@@ -289,6 +424,52 @@ func (s *Server) Run() error {
 	go scheduler.Run(ctx, s.Logger, monitoring.NewCleanupTask(s.Logger, s.monitoringService, cleaningPeriod), cleanupMeasurementsInterval)
 	s.Infof("Task to cleanup measurements will run with interval %v", cleanupMeasurementsInterval)
 
+	inventoryCleaningPeriod := time.Hour * 24 * time.Duration(s.config.Inventory.HistoryStorageDays)
+	go scheduler.Run(ctx, s.Logger, inventory.NewCleanupTask(s.Logger, s.inventoryService, inventoryCleaningPeriod), cleanupInventoryInterval)
+	s.Infof("Task to cleanup inventory changes will run with interval %v", cleanupInventoryInterval)
+
+	if s.alertsService != nil {
+		go scheduler.Run(ctx, s.Logger, alerts.NewCheckTask(s.Logger, s.alertsService, s.clientService), s.config.Alerts.CheckInterval)
+		s.Infof("Task to check alert rules will run with interval %v", s.config.Alerts.CheckInterval)
+
+		alertsCleaningPeriod := time.Hour * 24 * time.Duration(s.config.Alerts.DataStorageDays)
+		go scheduler.Run(ctx, s.Logger, alerts.NewCleanupTask(s.Logger, s.alertsService, alertsCleaningPeriod), cleanupAlertsInterval)
+		s.Infof("Task to cleanup resolved alerts will run with interval %v", cleanupAlertsInterval)
+	}
+
+	if s.recordingsService != nil {
+		recordingsCleaningPeriod := time.Hour * 24 * time.Duration(s.config.Recordings.DataStorageDays)
+		go scheduler.Run(ctx, s.Logger, recordings.NewCleanupTask(s.Logger, s.recordingsService, recordingsCleaningPeriod), cleanupRecordingsInterval)
+		s.Infof("Task to cleanup recordings will run with interval %v", cleanupRecordingsInterval)
+	}
+
+	if s.approvalsService != nil {
+		go scheduler.Run(ctx, s.Logger, approvals.NewExpireTask(s.Logger, s.approvalsService), expireApprovalsInterval)
+		s.Infof("Task to expire approvals will run with interval %v", expireApprovalsInterval)
+	}
+
+	if s.webhooksService != nil {
+		webhooksCleaningPeriod := time.Hour * 24 * time.Duration(s.config.Webhooks.DataStorageDays)
+		go scheduler.Run(ctx, s.Logger, webhooks.NewCleanupTask(s.Logger, s.webhooksService, webhooksCleaningPeriod), cleanupWebhooksInterval)
+		s.Infof("Task to cleanup webhook deliveries will run with interval %v", cleanupWebhooksInterval)
+	}
+
+	if s.tunnelHistory != nil {
+		tunnelHistoryCleaningPeriod := time.Hour * 24 * time.Duration(s.config.TunnelHistory.DataStorageDays)
+		go scheduler.Run(ctx, s.Logger, tunnelhistory.NewCleanupTask(s.Logger, s.tunnelHistory, tunnelHistoryCleaningPeriod), cleanupTunnelHistoryInterval)
+		s.Infof("Task to cleanup tunnel history will run with interval %v", cleanupTunnelHistoryInterval)
+	}
+
+	if s.tunnelShares != nil {
+		go scheduler.Run(ctx, s.Logger, tunnelshares.NewCleanupTask(s.Logger, s.tunnelShares), cleanupTunnelSharesInterval)
+		s.Infof("Task to cleanup expired tunnel shares will run with interval %v", cleanupTunnelSharesInterval)
+	}
+
+	if s.clusterService != nil {
+		go scheduler.Run(ctx, s.Logger, cluster.NewSyncTask(s.Logger, s.clusterService, s.syncCluster), s.config.Cluster.HeartbeatInterval)
+		s.Infof("Task to sync the cluster state will run with interval %v", s.config.Cluster.HeartbeatInterval)
+	}
+
 	go scheduler.Run(ctx, s.Logger, session.NewCleanupTask(s.apiListener.apiSessions), cleanupAPISessionsInterval)
 	s.Infof("Task to cleanup expired api sessions will run with interval %v", cleanupAPISessionsInterval)
 
@@ -339,10 +520,33 @@ func (s *Server) Close() error {
 	wg.Go(s.clientDB.Close)
 	wg.Go(s.jobProvider.Close)
 	wg.Go(s.clientGroupProvider.Close)
+	wg.Go(s.configProfiles.Close)
+	wg.Go(s.inventoryService.Close)
 	wg.Go(s.uiJobWebSockets.CloseConnections)
 	if s.auditLog != nil {
 		wg.Go(s.auditLog.Close)
 	}
+	if s.alertsService != nil {
+		wg.Go(s.alertsService.Close)
+	}
+	if s.recordingsService != nil {
+		wg.Go(s.recordingsService.Close)
+	}
+	if s.approvalsService != nil {
+		wg.Go(s.approvalsService.Close)
+	}
+	if s.webhooksService != nil {
+		wg.Go(s.webhooksService.Close)
+	}
+	if s.tunnelHistory != nil {
+		wg.Go(s.tunnelHistory.Close)
+	}
+	if s.tunnelShares != nil {
+		wg.Go(s.tunnelShares.Close)
+	}
+	if s.clusterService != nil {
+		wg.Go(s.clusterService.Close)
+	}
 
 	s.uploadWebSockets.Range(func(key, value interface{}) bool {
 		if wsConn, ok := value.(*ws.ConcurrentWebSocket); ok {
@@ -0,0 +1,21 @@
+package chserver
+
+import (
+	"path"
+
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/server/tunnelhistory"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+func initTunnelHistoryService(config *chconfig.Config, log *logger.Logger) (*tunnelhistory.Service, error) {
+	provider, err := tunnelhistory.NewSqliteProvider(
+		path.Join(config.Server.DataDir, "tunnel_history.db"),
+		config.GetDatabaseOptions(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return tunnelhistory.NewService(provider, log), nil
+}
@@ -0,0 +1,49 @@
+package chserver
+
+import (
+	"path"
+
+	"github.com/cloudradar-monitoring/rport/server/api/message"
+	"github.com/cloudradar-monitoring/rport/server/auditlog"
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/server/tunnelshares"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+func initTunnelSharesService(config *chconfig.Config, log *logger.Logger) (*tunnelshares.Service, error) {
+	provider, err := tunnelshares.NewSqliteProvider(
+		path.Join(config.Server.DataDir, "tunnel_shares.db"),
+		config.GetDatabaseOptions(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// access codes are sent by email if smtp is configured
+	var mailer message.Service
+	if config.SMTP.Server != "" {
+		mailer, err = newNotificationMessageService(config, "smtp")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tunnelshares.NewService(config.TunnelShares, provider, config.API.JWTSecret, mailer, log), nil
+}
+
+// onTunnelShareAccess records the attempts to redeem share links in the audit log
+func (s *Server) onTunnelShareAccess(share *tunnelshares.Share, remoteIP string, err error) {
+	action := auditlog.ActionSuccess
+	request := map[string]interface{}{}
+	if err != nil {
+		action = auditlog.ActionFailed
+		request["error"] = err.Error()
+	}
+
+	entry := s.auditLog.Entry(auditlog.ApplicationClientTunnelShare, action).WithRemoteIP(remoteIP)
+	if share != nil {
+		request["tunnel_id"] = share.TunnelID
+		entry = entry.WithID(share.ID).WithClientID(share.ClientID)
+	}
+	entry.WithRequest(request).Save()
+}
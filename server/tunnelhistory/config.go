@@ -0,0 +1,20 @@
+package tunnelhistory
+
+import "fmt"
+
+type Config struct {
+	Enabled         bool  `mapstructure:"enabled"`
+	DataStorageDays int64 `mapstructure:"data_storage_days"`
+}
+
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.DataStorageDays < 1 {
+		return fmt.Errorf("invalid tunnel_history.data_storage_days: must be at least 1, got %d", c.DataStorageDays)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package tunnelhistory
+
+import "time"
+
+// Session is the lifetime of a tunnel, from its start until it was closed
+type Session struct {
+	ID        string `json:"id" db:"id"`
+	ClientID  string `json:"client_id" db:"client_id"`
+	TunnelID  string `json:"tunnel_id" db:"tunnel_id"`
+	Name      string `json:"name" db:"name"`
+	Protocol  string `json:"protocol" db:"protocol"`
+	Local     string `json:"local" db:"local"`
+	Remote    string `json:"remote" db:"remote"`
+	Scheme    string `json:"scheme" db:"scheme"`
+	HTTPProxy bool   `json:"http_proxy" db:"http_proxy"`
+	// CreatedBy is the user who created the tunnel, empty for tunnels requested by the client
+	CreatedBy string     `json:"created_by" db:"created_by"`
+	StartedAt time.Time  `json:"started_at" db:"started_at"`
+	ClosedAt  *time.Time `json:"closed_at" db:"closed_at"`
+	// CloseReason is one of the clienttunnel.CloseReason* values, empty while the tunnel is running
+	CloseReason   string `json:"close_reason" db:"close_reason"`
+	Connections   int    `json:"connections" db:"connections"`
+	BytesSent     int64  `json:"bytes_sent" db:"bytes_sent"`
+	BytesReceived int64  `json:"bytes_received" db:"bytes_received"`
+}
+
+// Connection is an inbound tcp connection of a tunnel or the datagrams exchanged with an udp peer
+type Connection struct {
+	SessionID     string    `json:"session_id" db:"session_id"`
+	Protocol      string    `json:"protocol" db:"protocol"`
+	SourceIP      string    `json:"source_ip" db:"source_ip"`
+	StartedAt     time.Time `json:"started_at" db:"started_at"`
+	EndedAt       time.Time `json:"ended_at" db:"ended_at"`
+	BytesSent     int64     `json:"bytes_sent" db:"bytes_sent"`
+	BytesReceived int64     `json:"bytes_received" db:"bytes_received"`
+}
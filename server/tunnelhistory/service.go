@@ -0,0 +1,281 @@
+package tunnelhistory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+var (
+	supportedSessionFilters = map[string]bool{
+		"id":                true,
+		"client_id":         true,
+		"tunnel_id":         true,
+		"name":              true,
+		"protocol":          true,
+		"local":             true,
+		"remote":            true,
+		"created_by":        true,
+		"close_reason":      true,
+		"started_at[gt]":    true,
+		"started_at[lt]":    true,
+		"started_at[since]": true,
+		"started_at[until]": true,
+		"closed_at[gt]":     true,
+		"closed_at[lt]":     true,
+		"closed_at[since]":  true,
+		"closed_at[until]":  true,
+	}
+	supportedSessionSorts = map[string]bool{
+		"started_at":     true,
+		"closed_at":      true,
+		"client_id":      true,
+		"connections":    true,
+		"bytes_sent":     true,
+		"bytes_received": true,
+	}
+	defaultSessionSort = []query.SortOption{{Column: "started_at", IsASC: false}}
+
+	supportedConnectionFilters = map[string]bool{
+		"protocol":          true,
+		"source_ip":         true,
+		"started_at[gt]":    true,
+		"started_at[lt]":    true,
+		"started_at[since]": true,
+		"started_at[until]": true,
+	}
+	supportedConnectionSorts = map[string]bool{
+		"started_at":     true,
+		"ended_at":       true,
+		"bytes_sent":     true,
+		"bytes_received": true,
+	}
+	defaultConnectionSort = []query.SortOption{{Column: "started_at", IsASC: false}}
+
+	paginationConfig = &query.PaginationConfig{
+		DefaultLimit: 10,
+		MaxLimit:     100,
+	}
+)
+
+// Service records the sessions of the tunnels started by this server and their connections
+type Service struct {
+	provider Provider
+	logger   *logger.Logger
+
+	mu sync.Mutex
+	// running are the tunnels with an open session by their session id
+	running map[string]*clienttunnel.Tunnel
+	now     func() time.Time
+}
+
+func NewService(provider Provider, logger *logger.Logger) *Service {
+	return &Service{
+		provider: provider,
+		logger:   logger,
+		running:  make(map[string]*clienttunnel.Tunnel),
+		now:      time.Now,
+	}
+}
+
+// TunnelStarted opens a session for a started tunnel. It's a no-op if the history is disabled,
+// the same applies to TunnelClosed and ConnectionClosed.
+func (s *Service) TunnelStarted(clientID string, t *clienttunnel.Tunnel) {
+	if s == nil {
+		return
+	}
+
+	session := &Session{
+		ID:        t.SessionID,
+		ClientID:  clientID,
+		TunnelID:  t.ID,
+		Name:      t.Name,
+		Protocol:  t.Protocol,
+		Local:     t.Local(),
+		Remote:    t.Remote.Remote(),
+		HTTPProxy: t.HTTPProxy,
+		CreatedBy: t.Owner,
+		StartedAt: t.CreatedAt.UTC(),
+	}
+	if t.Scheme != nil {
+		session.Scheme = *t.Scheme
+	}
+
+	if err := s.provider.CreateSession(context.Background(), session); err != nil {
+		s.logger.Errorf("Failed to save session of tunnel %s of client %s: %v", t.ID, clientID, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.running[t.SessionID] = t
+	s.mu.Unlock()
+}
+
+// TunnelClosed closes the session of a tunnel with the final traffic of the tunnel
+func (s *Service) TunnelClosed(t *clienttunnel.Tunnel, reason string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	delete(s.running, t.SessionID)
+	s.mu.Unlock()
+
+	s.closeSession(t, reason)
+}
+
+func (s *Service) closeSession(t *clienttunnel.Tunnel, reason string) {
+	sent, received := t.Traffic()
+	err := s.provider.CloseSession(context.Background(), t.SessionID, s.now().UTC(), reason, sent, received)
+	if err != nil {
+		s.logger.Errorf("Failed to close session %s of tunnel %s: %v", t.SessionID, t.ID, err)
+	}
+}
+
+// ConnectionClosed adds a closed connection to the session of the tunnel
+func (s *Service) ConnectionClosed(t *clienttunnel.Tunnel, conn clienttunnel.Connection) {
+	if s == nil {
+		return
+	}
+
+	err := s.provider.AddConnection(context.Background(), &Connection{
+		SessionID:     t.SessionID,
+		Protocol:      conn.Protocol,
+		SourceIP:      conn.SourceIP,
+		StartedAt:     conn.StartedAt.UTC(),
+		EndedAt:       conn.EndedAt.UTC(),
+		BytesSent:     conn.BytesSent,
+		BytesReceived: conn.BytesReceived,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed to save connection of tunnel session %s: %v", t.SessionID, err)
+	}
+}
+
+// ListSessions returns the sessions of the given clients, of all clients if clientIDs is nil
+func (s *Service) ListSessions(ctx context.Context, options *query.ListOptions, clientIDs []string) (*api.SuccessPayload, error) {
+	err := query.ValidateListOptions(options, supportedSessionSorts, supportedSessionFilters, nil, paginationConfig)
+	if err != nil {
+		return nil, err
+	}
+	if clientIDs != nil && len(clientIDs) == 0 {
+		return &api.SuccessPayload{
+			Data: []*Session{},
+			Meta: api.NewMeta(0),
+		}, nil
+	}
+	if len(options.Sorts) == 0 {
+		options.Sorts = defaultSessionSort
+	}
+	if clientIDs != nil {
+		options.Filters = append(options.Filters, query.FilterOption{
+			Column: []string{"client_id"},
+			Values: clientIDs,
+		})
+	}
+
+	sessions, err := s.provider.ListSessions(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.provider.CountSessions(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// the traffic of running tunnels is only stored when they are closed
+	s.mu.Lock()
+	for _, session := range sessions {
+		if t, ok := s.running[session.ID]; ok && session.ClosedAt == nil {
+			session.BytesSent, session.BytesReceived = t.Traffic()
+		}
+	}
+	s.mu.Unlock()
+
+	return &api.SuccessPayload{
+		Data: sessions,
+		Meta: api.NewMeta(count),
+	}, nil
+}
+
+// ListConnections returns the connections of a session, clientIDs limits the access like for ListSessions
+func (s *Service) ListConnections(ctx context.Context, sessionID string, options *query.ListOptions, clientIDs []string) (*api.SuccessPayload, error) {
+	session, err := s.provider.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, errors2.APIError{
+			Message:    fmt.Sprintf("Tunnel session with id %q not found.", sessionID),
+			HTTPStatus: http.StatusNotFound,
+		}
+	}
+	if clientIDs != nil && !contains(clientIDs, session.ClientID) {
+		return nil, errors2.APIError{
+			Message:    "Access denied to the tunnel session.",
+			HTTPStatus: http.StatusForbidden,
+		}
+	}
+
+	err = query.ValidateListOptions(options, supportedConnectionSorts, supportedConnectionFilters, nil, paginationConfig)
+	if err != nil {
+		return nil, err
+	}
+	if len(options.Sorts) == 0 {
+		options.Sorts = defaultConnectionSort
+	}
+	options.Filters = append(options.Filters, query.FilterOption{
+		Column: []string{"session_id"},
+		Values: []string{sessionID},
+	})
+
+	connections, err := s.provider.ListConnections(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.provider.CountConnections(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.SuccessPayload{
+		Data: connections,
+		Meta: api.NewMeta(count),
+	}, nil
+}
+
+// DeleteOlderThan deletes the sessions closed before the given period along with their connections
+func (s *Service) DeleteOlderThan(ctx context.Context, period time.Duration) (int64, error) {
+	return s.provider.DeleteClosedBefore(ctx, s.now().Add(-period).UTC())
+}
+
+// Close closes the sessions of the tunnels that are still running
+func (s *Service) Close() error {
+	s.mu.Lock()
+	running := s.running
+	s.running = make(map[string]*clienttunnel.Tunnel)
+	s.mu.Unlock()
+
+	for _, t := range running {
+		s.closeSession(t, clienttunnel.CloseReasonServerStopped)
+	}
+
+	return s.provider.Close()
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package tunnelhistory
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+var testLog = logger.NewLogger("tunnel-history", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug)
+
+// historyTest is the service under test, it's backed by an in-memory database
+type historyTest struct {
+	*Service
+	t *testing.T
+}
+
+func newHistoryTest(t *testing.T) *historyTest {
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { dbProvider.Close() })
+
+	return &historyTest{Service: NewService(dbProvider, testLog), t: t}
+}
+
+// sessions lists the sessions seen by a user with access to the given clients, nil means all clients
+func (h *historyTest) sessions(clientIDs []string) []*Session {
+	result, err := h.ListSessions(context.Background(), &query.ListOptions{}, clientIDs)
+	require.NoError(h.t, err)
+	return result.Data.([]*Session)
+}
+
+func newTestTunnel(id string) *clienttunnel.Tunnel {
+	return &clienttunnel.Tunnel{
+		ID:        id,
+		SessionID: "session-" + id,
+		CreatedAt: time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC),
+		Remote: models.Remote{
+			LocalHost:  "0.0.0.0",
+			LocalPort:  "2222",
+			RemoteHost: "127.0.0.1",
+			RemotePort: "22",
+			Protocol:   models.ProtocolTCP,
+			Owner:      "admin",
+		},
+	}
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	ctx := context.Background()
+	service := newHistoryTest(t)
+	service.now = func() time.Time { return time.Date(2022, 5, 1, 11, 0, 0, 0, time.UTC) }
+	tunnel := newTestTunnel("1")
+
+	service.TunnelStarted("client-1", tunnel)
+	service.ConnectionClosed(tunnel, clienttunnel.Connection{
+		Protocol:      "tcp",
+		SourceIP:      "192.0.2.1",
+		StartedAt:     time.Date(2022, 5, 1, 10, 5, 0, 0, time.UTC),
+		EndedAt:       time.Date(2022, 5, 1, 10, 6, 0, 0, time.UTC),
+		BytesSent:     10,
+		BytesReceived: 20,
+	})
+	tunnel.BytesSent, tunnel.BytesReceived = 10, 20
+
+	sessions := service.sessions(nil)
+	require.Len(t, sessions, 1)
+	assert.Nil(t, sessions[0].ClosedAt)
+	assert.Equal(t, "0.0.0.0:2222", sessions[0].Local)
+	assert.Equal(t, "127.0.0.1:22", sessions[0].Remote)
+	assert.Equal(t, "admin", sessions[0].CreatedBy)
+	assert.Equal(t, 1, sessions[0].Connections)
+	// the traffic of a running tunnel is taken from the tunnel
+	assert.Equal(t, int64(10), sessions[0].BytesSent)
+	assert.Equal(t, int64(20), sessions[0].BytesReceived)
+
+	service.TunnelClosed(tunnel, clienttunnel.CloseReasonIdleTimeout)
+	// closing twice keeps the first close reason
+	service.TunnelClosed(tunnel, clienttunnel.CloseReasonClientDisconnected)
+
+	sessions = service.sessions(nil)
+	require.Len(t, sessions, 1)
+	require.NotNil(t, sessions[0].ClosedAt)
+	assert.Equal(t, service.now(), sessions[0].ClosedAt.UTC())
+	assert.Equal(t, clienttunnel.CloseReasonIdleTimeout, sessions[0].CloseReason)
+	assert.Equal(t, int64(10), sessions[0].BytesSent)
+
+	result, err := service.ListConnections(ctx, tunnel.SessionID, &query.ListOptions{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Meta.Count)
+	connections := result.Data.([]*Connection)
+	assert.Equal(t, "192.0.2.1", connections[0].SourceIP)
+	assert.Equal(t, int64(20), connections[0].BytesReceived)
+}
+
+func TestListSessionsAccess(t *testing.T) {
+	ctx := context.Background()
+	service := newHistoryTest(t)
+	service.TunnelStarted("client-1", newTestTunnel("1"))
+	service.TunnelStarted("client-2", newTestTunnel("2"))
+
+	assert.Len(t, service.sessions(nil), 2)
+	assert.Empty(t, service.sessions([]string{}))
+	sessions := service.sessions([]string{"client-2"})
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "2", sessions[0].TunnelID)
+
+	_, err := service.ListConnections(ctx, "session-1", &query.ListOptions{}, []string{"client-2"})
+	assert.Equal(t, http.StatusForbidden, err.(errors2.APIError).HTTPStatus)
+
+	_, err = service.ListConnections(ctx, "unknown", &query.ListOptions{}, nil)
+	assert.Equal(t, http.StatusNotFound, err.(errors2.APIError).HTTPStatus)
+}
+
+func TestDeleteOlderThan(t *testing.T) {
+	ctx := context.Background()
+	service := newHistoryTest(t)
+	now := time.Date(2022, 5, 1, 11, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	closed := newTestTunnel("1")
+	service.TunnelStarted("client-1", closed)
+	service.ConnectionClosed(closed, clienttunnel.Connection{Protocol: "tcp"})
+	service.TunnelClosed(closed, clienttunnel.CloseReasonDeleted)
+	service.TunnelStarted("client-1", newTestTunnel("2"))
+
+	now = now.Add(48 * time.Hour)
+	deleted, err := service.DeleteOlderThan(ctx, 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	sessions := service.sessions(nil)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "2", sessions[0].TunnelID)
+	count, err := service.provider.CountConnections(ctx, &query.ListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
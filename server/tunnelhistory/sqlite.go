@@ -0,0 +1,212 @@
+package tunnelhistory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	tunnelhistorymigration "github.com/cloudradar-monitoring/rport/db/migration/tunnel_history"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+type Provider interface {
+	CreateSession(ctx context.Context, session *Session) error
+	CloseSession(ctx context.Context, id string, closedAt time.Time, reason string, bytesSent, bytesReceived int64) error
+	GetSession(ctx context.Context, id string) (*Session, error)
+	ListSessions(ctx context.Context, options *query.ListOptions) ([]*Session, error)
+	CountSessions(ctx context.Context, options *query.ListOptions) (int, error)
+	AddConnection(ctx context.Context, conn *Connection) error
+	ListConnections(ctx context.Context, options *query.ListOptions) ([]*Connection, error)
+	CountConnections(ctx context.Context, options *query.ListOptions) (int, error)
+	DeleteClosedBefore(ctx context.Context, before time.Time) (int64, error)
+	Close() error
+}
+
+type SqliteProvider struct {
+	db        *sqlx.DB
+	converter *query.SQLConverter
+}
+
+func NewSqliteProvider(dbPath string, dbOptions database.Options) (*SqliteProvider, error) {
+	db, err := database.Open("tunnel_history", dbPath, tunnelhistorymigration.AssetNames(), tunnelhistorymigration.Asset, dbOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tunnel history DB instance: %v", err)
+	}
+
+	return &SqliteProvider{
+		db:        db,
+		converter: query.NewSQLConverter(db.DriverName()),
+	}, nil
+}
+
+func (p *SqliteProvider) CreateSession(ctx context.Context, session *Session) error {
+	_, err := p.db.NamedExecContext(ctx,
+		`INSERT INTO tunnel_sessions (
+			id,
+			client_id,
+			tunnel_id,
+			name,
+			protocol,
+			local,
+			remote,
+			scheme,
+			http_proxy,
+			created_by,
+			started_at
+		) VALUES (
+			:id,
+			:client_id,
+			:tunnel_id,
+			:name,
+			:protocol,
+			:local,
+			:remote,
+			:scheme,
+			:http_proxy,
+			:created_by,
+			:started_at
+		)`,
+		session,
+	)
+	return err
+}
+
+// CloseSession sets the end of a session, it's a no-op if the session is already closed
+func (p *SqliteProvider) CloseSession(ctx context.Context, id string, closedAt time.Time, reason string, bytesSent, bytesReceived int64) error {
+	_, err := p.db.ExecContext(ctx, p.db.Rebind(
+		`UPDATE tunnel_sessions SET closed_at = ?, close_reason = ?, bytes_sent = ?, bytes_received = ? WHERE id = ? AND closed_at IS NULL`),
+		closedAt, reason, bytesSent, bytesReceived, id,
+	)
+	return err
+}
+
+func (p *SqliteProvider) GetSession(ctx context.Context, id string) (*Session, error) {
+	session := &Session{}
+	err := p.db.GetContext(ctx, session, p.db.Rebind("SELECT * FROM tunnel_sessions WHERE id = ?"), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return session, nil
+}
+
+func (p *SqliteProvider) ListSessions(ctx context.Context, options *query.ListOptions) ([]*Session, error) {
+	sessions := []*Session{}
+	q, params := p.converter.ConvertListOptionsToQuery(options, "SELECT * FROM tunnel_sessions")
+	err := p.db.SelectContext(ctx, &sessions, p.db.Rebind(q), params...)
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (p *SqliteProvider) CountSessions(ctx context.Context, options *query.ListOptions) (int, error) {
+	return p.count(ctx, options, "SELECT COUNT(*) FROM tunnel_sessions")
+}
+
+// AddConnection stores a closed connection and increments the connections of its session
+func (p *SqliteProvider) AddConnection(ctx context.Context, conn *Connection) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.NamedExecContext(ctx,
+		`INSERT INTO tunnel_connections (
+			session_id,
+			protocol,
+			source_ip,
+			started_at,
+			ended_at,
+			bytes_sent,
+			bytes_received
+		) VALUES (
+			:session_id,
+			:protocol,
+			:source_ip,
+			:started_at,
+			:ended_at,
+			:bytes_sent,
+			:bytes_received
+		)`,
+		conn,
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	_, err = tx.ExecContext(ctx, tx.Rebind("UPDATE tunnel_sessions SET connections = connections + 1 WHERE id = ?"), conn.SessionID)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (p *SqliteProvider) ListConnections(ctx context.Context, options *query.ListOptions) ([]*Connection, error) {
+	connections := []*Connection{}
+	q, params := p.converter.ConvertListOptionsToQuery(options, "SELECT * FROM tunnel_connections")
+	err := p.db.SelectContext(ctx, &connections, p.db.Rebind(q), params...)
+	if err != nil {
+		return nil, err
+	}
+	return connections, nil
+}
+
+func (p *SqliteProvider) CountConnections(ctx context.Context, options *query.ListOptions) (int, error) {
+	return p.count(ctx, options, "SELECT COUNT(*) FROM tunnel_connections")
+}
+
+func (p *SqliteProvider) count(ctx context.Context, options *query.ListOptions, q string) (int, error) {
+	var result int
+
+	countOptions := *options
+	countOptions.Pagination = nil
+	countOptions.Sorts = nil
+	q, params := p.converter.ConvertListOptionsToQuery(&countOptions, q)
+
+	err := p.db.GetContext(ctx, &result, p.db.Rebind(q), params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// DeleteClosedBefore deletes the sessions closed before the given time along with their connections
+func (p *SqliteProvider) DeleteClosedBefore(ctx context.Context, before time.Time) (int64, error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(
+		"DELETE FROM tunnel_connections WHERE session_id IN (SELECT id FROM tunnel_sessions WHERE closed_at < ?)"), before)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	res, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM tunnel_sessions WHERE closed_at < ?"), before)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+
+	return deleted, tx.Commit()
+}
+
+func (p *SqliteProvider) Close() error {
+	return p.db.Close()
+}
@@ -0,0 +1,86 @@
+package tunnelhistory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+func TestSqliteProvider(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		p, err := NewSqliteProvider(":memory:", database.Options{PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		defer p.Close()
+
+		startedAt := time.Date(2022, 10, 16, 12, 0, 0, 0, time.UTC)
+		require.NoError(t, p.CreateSession(ctx, &Session{ID: "s1", ClientID: "client-1", TunnelID: "1", Protocol: "tcp", Local: "0.0.0.0:2222", Remote: "127.0.0.1:22", HTTPProxy: true, CreatedBy: "admin", StartedAt: startedAt}))
+		require.NoError(t, p.CreateSession(ctx, &Session{ID: "s2", ClientID: "client-2", TunnelID: "1", Protocol: "udp", Local: "0.0.0.0:5353", Remote: "127.0.0.1:53", StartedAt: startedAt.Add(time.Minute)}))
+
+		require.NoError(t, p.AddConnection(ctx, &Connection{SessionID: "s1", Protocol: "tcp", SourceIP: "192.0.2.1", StartedAt: startedAt, EndedAt: startedAt.Add(time.Second), BytesSent: 10, BytesReceived: 20}))
+		require.NoError(t, p.AddConnection(ctx, &Connection{SessionID: "s1", Protocol: "tcp", SourceIP: "192.0.2.2", StartedAt: startedAt.Add(time.Second), EndedAt: startedAt.Add(2 * time.Second)}))
+
+		closedAt := startedAt.Add(time.Hour)
+		require.NoError(t, p.CloseSession(ctx, "s1", closedAt, "deleted", 100, 200))
+		// a closed session is never closed again
+		require.NoError(t, p.CloseSession(ctx, "s1", closedAt.Add(time.Hour), "idle", 0, 0))
+
+		session, err := p.GetSession(ctx, "s1")
+		require.NoError(t, err)
+		require.NotNil(t, session)
+		assert.True(t, session.HTTPProxy)
+		assert.Equal(t, "deleted", session.CloseReason)
+		assert.Equal(t, 2, session.Connections)
+		assert.EqualValues(t, 100, session.BytesSent)
+		assert.EqualValues(t, 200, session.BytesReceived)
+		require.NotNil(t, session.ClosedAt)
+		assert.True(t, closedAt.Equal(*session.ClosedAt))
+
+		session, err = p.GetSession(ctx, "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, session)
+
+		sessionOptions := &query.ListOptions{
+			Filters: []query.FilterOption{{Column: []string{"tunnel_id"}, Values: []string{"1"}}},
+			Sorts:   []query.SortOption{{Column: "started_at", IsASC: false}},
+		}
+		sessions, err := p.ListSessions(ctx, sessionOptions)
+		require.NoError(t, err)
+		require.Len(t, sessions, 2)
+		assert.Equal(t, "s2", sessions[0].ID)
+		assert.Nil(t, sessions[0].ClosedAt)
+		count, err := p.CountSessions(ctx, sessionOptions)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		connOptions := &query.ListOptions{
+			Filters:    []query.FilterOption{{Column: []string{"session_id"}, Values: []string{"s1"}}},
+			Sorts:      []query.SortOption{{Column: "started_at", IsASC: true}},
+			Pagination: query.NewPagination(1, 1),
+		}
+		connections, err := p.ListConnections(ctx, connOptions)
+		require.NoError(t, err)
+		require.Len(t, connections, 1)
+		assert.Equal(t, "192.0.2.2", connections[0].SourceIP)
+		count, err = p.CountConnections(ctx, connOptions)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		deleted, err := p.DeleteClosedBefore(ctx, closedAt.Add(time.Second))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+		count, err = p.CountConnections(ctx, &query.ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+		count, err = p.CountSessions(ctx, &query.ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, count, "running sessions must be kept")
+	})
+}
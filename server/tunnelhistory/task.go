@@ -0,0 +1,33 @@
+package tunnelhistory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+type CleanupTask struct {
+	log     *logger.Logger
+	service *Service
+	period  time.Duration
+}
+
+// NewCleanupTask returns a task to delete the tunnel sessions closed longer ago than the configured period
+func NewCleanupTask(log *logger.Logger, service *Service, period time.Duration) *CleanupTask {
+	return &CleanupTask{
+		log:     log,
+		service: service,
+		period:  period,
+	}
+}
+
+func (t *CleanupTask) Run(ctx context.Context) error {
+	deleted, err := t.service.DeleteOlderThan(ctx, t.period)
+	if err != nil {
+		return fmt.Errorf("failed to cleanup tunnel history: %v", err)
+	}
+	t.log.Debugf("tunnelhistory.CleanupTask: %d tunnel sessions deleted", deleted)
+	return nil
+}
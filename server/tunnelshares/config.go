@@ -0,0 +1,32 @@
+package tunnelshares
+
+import (
+	"fmt"
+	"time"
+)
+
+const minLifetime = time.Minute
+
+type Config struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxLifetime is the longest period a share link can be valid
+	MaxLifetime time.Duration `mapstructure:"max_lifetime"`
+	// OTPLifetime is the period an access code sent by email can be used
+	OTPLifetime time.Duration `mapstructure:"otp_lifetime"`
+}
+
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.MaxLifetime < minLifetime {
+		return fmt.Errorf("invalid tunnel_shares.max_lifetime: must be at least %s, got %s", minLifetime, c.MaxLifetime)
+	}
+
+	if c.OTPLifetime < minLifetime {
+		return fmt.Errorf("invalid tunnel_shares.otp_lifetime: must be at least %s, got %s", minLifetime, c.OTPLifetime)
+	}
+
+	return nil
+}
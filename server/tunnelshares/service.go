@@ -0,0 +1,415 @@
+package tunnelshares
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/server/api/message"
+	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/random"
+	"github.com/cloudradar-monitoring/rport/share/security"
+)
+
+const (
+	accessKeyLength = 32
+	otpLength       = 6
+	maxOTPAttempts  = 5
+	// accessRecheckInterval is how often an access key is checked against the stored share,
+	// so revocations done on another cluster node take effect
+	accessRecheckInterval = 30 * time.Second
+)
+
+var (
+	errInvalidToken = errors.New("invalid share token")
+	errExpired      = errors.New("share link expired")
+	errRevoked      = errors.New("share link revoked")
+	errUsed         = errors.New("one-time share link was already used")
+	errWrongTunnel  = errors.New("share link is for another tunnel")
+	errWrongIP      = errors.New("share link is bound to another IP")
+)
+
+// AccessListener is notified about each attempt to redeem a share link, share is nil if the token is invalid
+type AccessListener func(share *Share, remoteIP string, err error)
+
+// access is the access granted to a browser that redeemed a share link
+type access struct {
+	shareID         string
+	tunnelSessionID string
+	boundIP         string
+	expiresAt       time.Time
+	checkedAt       time.Time
+}
+
+type otp struct {
+	code      string
+	expiresAt time.Time
+	attempts  int
+}
+
+// Service manages the share links of tunnels and authorizes the users of the tunnel proxy who redeemed them
+type Service struct {
+	config   Config
+	provider Provider
+	// secret signs the tokens
+	secret []byte
+	// mailer sends access codes, it's nil if smtp isn't configured
+	mailer         message.Service
+	logger         *logger.Logger
+	accessListener AccessListener
+
+	mu       sync.Mutex
+	accesses map[string]*access
+	otps     map[string]*otp
+	now      func() time.Time
+}
+
+func NewService(config Config, provider Provider, secret string, mailer message.Service, logger *logger.Logger) *Service {
+	return &Service{
+		config:   config,
+		provider: provider,
+		secret:   []byte(secret),
+		mailer:   mailer,
+		logger:   logger,
+		accesses: make(map[string]*access),
+		otps:     make(map[string]*otp),
+		now:      time.Now,
+	}
+}
+
+// SetAccessListener sets the listener notified about the attempts to redeem share links
+func (s *Service) SetAccessListener(listener AccessListener) {
+	s.accessListener = listener
+}
+
+// Create shares a running tunnel and returns the share with its token
+func (s *Service) Create(ctx context.Context, clientID string, t *clienttunnel.Tunnel, req ShareRequest, createdBy string) (*Share, string, error) {
+	if t.Proxy == nil || t.Scheme == nil || (*t.Scheme != "http" && *t.Scheme != "https") {
+		return nil, "", errors2.APIError{
+			Message:    "Only http and https tunnels with http_proxy can be shared.",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+	if t.AuthUser == "" {
+		return nil, "", errors2.APIError{
+			Message:    "The tunnel must be protected with auth_user and auth_password to be shared.",
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	expiresIn, err := time.ParseDuration(req.ExpiresIn)
+	if err != nil || expiresIn <= 0 {
+		return nil, "", errors2.APIError{
+			Message:    fmt.Sprintf("Invalid expires_in %q: must be a positive duration like 30m or 8h.", req.ExpiresIn),
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+	if expiresIn > s.config.MaxLifetime {
+		return nil, "", errors2.APIError{
+			Message:    fmt.Sprintf("Invalid expires_in %q: must not exceed %s.", req.ExpiresIn, s.config.MaxLifetime),
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	boundIP := ""
+	if req.BoundIP != "" {
+		ip := net.ParseIP(req.BoundIP)
+		if ip == nil {
+			return nil, "", errors2.APIError{
+				Message:    fmt.Sprintf("Invalid bound_ip %q.", req.BoundIP),
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+		boundIP = ip.String()
+	}
+
+	if req.Email != "" {
+		if !govalidator.IsEmail(req.Email) {
+			return nil, "", errors2.APIError{
+				Message:    fmt.Sprintf("Invalid email %q.", req.Email),
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+		if s.mailer == nil {
+			return nil, "", errors2.APIError{
+				Message:    "Access codes by email require smtp to be configured.",
+				HTTPStatus: http.StatusBadRequest,
+			}
+		}
+	}
+
+	id, err := random.UUID4()
+	if err != nil {
+		return nil, "", err
+	}
+	now := s.now().UTC()
+	share := &Share{
+		ID:              id,
+		ClientID:        clientID,
+		TunnelID:        t.ID,
+		TunnelSessionID: t.SessionID,
+		Description:     req.Description,
+		CreatedBy:       createdBy,
+		CreatedAt:       now,
+		ExpiresAt:       now.Add(expiresIn),
+		OneTime:         req.OneTime,
+		BoundIP:         boundIP,
+		Email:           req.Email,
+	}
+	if err := s.provider.Create(ctx, share); err != nil {
+		return nil, "", err
+	}
+
+	return share, s.token(share), nil
+}
+
+// List returns the shares of a tunnel, including revoked and expired shares until they are deleted
+func (s *Service) List(ctx context.Context, clientID, tunnelID string) ([]*Share, error) {
+	return s.provider.ListByTunnel(ctx, clientID, tunnelID)
+}
+
+// Revoke revokes a share of a tunnel, browsers that already redeemed the share lose the access
+func (s *Service) Revoke(ctx context.Context, clientID, tunnelID, id, revokedBy string) (*Share, error) {
+	share, err := s.provider.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if share == nil || share.ClientID != clientID || share.TunnelID != tunnelID {
+		return nil, errors2.APIError{
+			Message:    fmt.Sprintf("Tunnel share with id %q not found.", id),
+			HTTPStatus: http.StatusNotFound,
+		}
+	}
+
+	if err := s.provider.Revoke(ctx, id, s.now().UTC(), revokedBy); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	for key, a := range s.accesses {
+		if a.shareID == id {
+			delete(s.accesses, key)
+		}
+	}
+	delete(s.otps, id)
+	s.mu.Unlock()
+
+	return s.provider.Get(ctx, id)
+}
+
+// Redeem implements clienttunnel.TunnelShares
+func (s *Service) Redeem(ctx context.Context, token, tunnelSessionID, remoteIP, otp string) (string, error) {
+	share, err := s.validateToken(ctx, token, tunnelSessionID, remoteIP)
+	if err != nil {
+		s.notifyAccess(share, remoteIP, err)
+		return "", err
+	}
+
+	if share.Email != "" {
+		if err := s.checkOTP(ctx, share, otp); err != nil {
+			if !errors.Is(err, clienttunnel.ErrShareOTPRequired) {
+				s.notifyAccess(share, remoteIP, err)
+			}
+			return "", err
+		}
+	}
+
+	ok, err := s.provider.AddUse(ctx, share.ID, s.now().UTC(), remoteIP)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		s.notifyAccess(share, remoteIP, errUsed)
+		return "", errUsed
+	}
+
+	accessKey, err := security.NewRandomToken(accessKeyLength)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	s.accesses[accessKey] = &access{
+		shareID:         share.ID,
+		tunnelSessionID: share.TunnelSessionID,
+		boundIP:         share.BoundIP,
+		expiresAt:       share.ExpiresAt,
+		checkedAt:       s.now(),
+	}
+	s.mu.Unlock()
+
+	s.notifyAccess(share, remoteIP, nil)
+	return accessKey, nil
+}
+
+// Authorize implements clienttunnel.TunnelShares
+func (s *Service) Authorize(ctx context.Context, accessKey, tunnelSessionID, remoteIP string) bool {
+	now := s.now()
+
+	s.mu.Lock()
+	a, ok := s.accesses[accessKey]
+	if ok && now.After(a.expiresAt) {
+		delete(s.accesses, accessKey)
+		ok = false
+	}
+	s.mu.Unlock()
+	if !ok || a.tunnelSessionID != tunnelSessionID || (a.boundIP != "" && a.boundIP != remoteIP) {
+		return false
+	}
+
+	if now.Sub(a.checkedAt) < accessRecheckInterval {
+		return true
+	}
+	share, err := s.provider.Get(ctx, a.shareID)
+	if err != nil {
+		s.logger.Errorf("Failed to get tunnel share %s: %v", a.shareID, err)
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if share == nil || share.RevokedAt != nil {
+		delete(s.accesses, accessKey)
+		return false
+	}
+	a.checkedAt = now
+	return true
+}
+
+// DeleteExpired deletes the expired shares and forgets the access granted by them
+func (s *Service) DeleteExpired(ctx context.Context) (int64, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	for key, a := range s.accesses {
+		if now.After(a.expiresAt) {
+			delete(s.accesses, key)
+		}
+	}
+	for id, o := range s.otps {
+		if now.After(o.expiresAt) {
+			delete(s.otps, id)
+		}
+	}
+	s.mu.Unlock()
+
+	return s.provider.DeleteExpired(ctx, now.UTC())
+}
+
+func (s *Service) Close() error {
+	return s.provider.Close()
+}
+
+func (s *Service) validateToken(ctx context.Context, token, tunnelSessionID, remoteIP string) (*Share, error) {
+	id, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, errInvalidToken
+	}
+	share, err := s.provider.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if share == nil {
+		return nil, errInvalidToken
+	}
+	if subtle.ConstantTimeCompare([]byte(signature), []byte(s.signature(share))) != 1 {
+		return nil, errInvalidToken
+	}
+
+	switch {
+	case share.TunnelSessionID != tunnelSessionID:
+		return share, errWrongTunnel
+	case share.RevokedAt != nil:
+		return share, errRevoked
+	case s.now().After(share.ExpiresAt):
+		return share, errExpired
+	case share.BoundIP != "" && share.BoundIP != remoteIP:
+		return share, errWrongIP
+	}
+	return share, nil
+}
+
+// checkOTP sends an access code to the email of the share if otp is empty, otherwise it checks the given code
+func (s *Service) checkOTP(ctx context.Context, share *Share, code string) error {
+	now := s.now()
+
+	s.mu.Lock()
+	o, ok := s.otps[share.ID]
+	if ok && now.After(o.expiresAt) {
+		delete(s.otps, share.ID)
+		ok = false
+	}
+
+	if code == "" {
+		if ok {
+			// a code was already sent and is still valid
+			s.mu.Unlock()
+			return clienttunnel.ErrShareOTPRequired
+		}
+		newCode, err := security.NewRandomToken(otpLength)
+		if err != nil {
+			s.mu.Unlock()
+			return err
+		}
+		s.otps[share.ID] = &otp{
+			code:      newCode,
+			expiresAt: now.Add(s.config.OTPLifetime),
+		}
+		s.mu.Unlock()
+
+		err = s.mailer.Send(ctx, message.Data{
+			Title:  "Access code for a shared tunnel",
+			SendTo: share.Email,
+			Message: fmt.Sprintf(`A shared tunnel was opened with your email address.
+The access code is: %s
+
+The code is valid for %s.`, newCode, s.config.OTPLifetime),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to send access code: %v", err)
+		}
+		return clienttunnel.ErrShareOTPRequired
+	}
+	defer s.mu.Unlock()
+
+	if !ok {
+		return clienttunnel.ErrShareInvalidOTP
+	}
+	o.attempts++
+	if subtle.ConstantTimeCompare([]byte(code), []byte(o.code)) != 1 {
+		if o.attempts >= maxOTPAttempts {
+			delete(s.otps, share.ID)
+		}
+		return clienttunnel.ErrShareInvalidOTP
+	}
+	delete(s.otps, share.ID)
+	return nil
+}
+
+func (s *Service) notifyAccess(share *Share, remoteIP string, err error) {
+	if s.accessListener != nil {
+		s.accessListener(share, remoteIP, err)
+	}
+}
+
+func (s *Service) token(share *Share) string {
+	return share.ID + "." + s.signature(share)
+}
+
+// signature signs the fields of a share that must not change for the token to be valid
+func (s *Service) signature(share *Share) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "tunnel-share\n%s\n%s\n%s\n%d", share.ID, share.ClientID, share.TunnelSessionID, share.ExpiresAt.Unix())
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,253 @@
+package tunnelshares
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/server/api/message"
+	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+var testLog = logger.NewLogger("tunnel-shares", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug)
+
+type mailerMock struct {
+	message.ServiceMock
+	sent []message.Data
+}
+
+func (m *mailerMock) Send(ctx context.Context, data message.Data) error {
+	m.sent = append(m.sent, data)
+	return nil
+}
+
+type accessRecord struct {
+	share *Share
+	err   error
+}
+
+// accessLog records the attempts to redeem share links, it's set as access listener
+type accessLog []accessRecord
+
+func (l *accessLog) record(share *Share, remoteIP string, err error) {
+	*l = append(*l, accessRecord{share: share, err: err})
+}
+
+// newSharesService returns a service allowing shares of up to a day, the mailer is nil if smtp is not configured
+func newSharesService(t *testing.T, mailer message.Service) *Service {
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { dbProvider.Close() })
+
+	return NewService(Config{MaxLifetime: 24 * time.Hour, OTPLifetime: 10 * time.Minute}, dbProvider, "secret", mailer, testLog)
+}
+
+func newTestTunnel() *clienttunnel.Tunnel {
+	scheme := "https"
+	return &clienttunnel.Tunnel{
+		ID:        "1",
+		SessionID: "session-1",
+		Proxy:     &clienttunnel.TunnelProxy{Host: "0.0.0.0", Port: "20000"},
+		Remote: models.Remote{
+			Scheme:       &scheme,
+			HTTPProxy:    true,
+			AuthUser:     "user",
+			AuthPassword: "password",
+		},
+	}
+}
+
+func assertStatus(t *testing.T, status int, err error) {
+	t.Helper()
+	require.Error(t, err)
+	apiErr, ok := err.(errors2.APIError)
+	require.True(t, ok, err)
+	assert.Equal(t, status, apiErr.HTTPStatus)
+}
+
+func TestCreateValidation(t *testing.T) {
+	ctx := context.Background()
+	service := newSharesService(t, nil)
+
+	noAuth := newTestTunnel()
+	noAuth.AuthUser = ""
+	noProxy := newTestTunnel()
+	noProxy.Proxy = nil
+
+	testCases := []struct {
+		name    string
+		tunnel  *clienttunnel.Tunnel
+		request ShareRequest
+		message string
+	}{
+		{
+			name:    "no tunnel proxy",
+			tunnel:  noProxy,
+			request: ShareRequest{ExpiresIn: "1h"},
+			message: "Only http and https tunnels with http_proxy can be shared.",
+		},
+		{
+			name:    "no auth user",
+			tunnel:  noAuth,
+			request: ShareRequest{ExpiresIn: "1h"},
+			message: "The tunnel must be protected with auth_user and auth_password to be shared.",
+		},
+		{
+			name:    "missing expires_in",
+			tunnel:  newTestTunnel(),
+			request: ShareRequest{},
+			message: `Invalid expires_in "": must be a positive duration like 30m or 8h.`,
+		},
+		{
+			name:    "expires_in exceeds max lifetime",
+			tunnel:  newTestTunnel(),
+			request: ShareRequest{ExpiresIn: "25h"},
+			message: `Invalid expires_in "25h": must not exceed 24h0m0s.`,
+		},
+		{
+			name:    "invalid ip",
+			tunnel:  newTestTunnel(),
+			request: ShareRequest{ExpiresIn: "1h", BoundIP: "1.2.3"},
+			message: `Invalid bound_ip "1.2.3".`,
+		},
+		{
+			name:    "email without smtp",
+			tunnel:  newTestTunnel(),
+			request: ShareRequest{ExpiresIn: "1h", Email: "vendor@example.com"},
+			message: "Access codes by email require smtp to be configured.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := service.Create(ctx, "client-1", tc.tunnel, tc.request, "admin")
+			assertStatus(t, http.StatusBadRequest, err)
+			assert.EqualError(t, err, tc.message)
+		})
+	}
+}
+
+func TestRedeem(t *testing.T) {
+	ctx := context.Background()
+	service := newSharesService(t, nil)
+	var accesses accessLog
+	service.SetAccessListener(accesses.record)
+	tunnel := newTestTunnel()
+
+	share, token, err := service.Create(ctx, "client-1", tunnel, ShareRequest{ExpiresIn: "1h", Description: "vendor"}, "admin")
+	require.NoError(t, err)
+
+	_, err = service.Redeem(ctx, token+"x", "session-1", "192.0.2.1", "")
+	assert.ErrorIs(t, err, errInvalidToken)
+	_, err = service.Redeem(ctx, token, "session-2", "192.0.2.1", "")
+	assert.ErrorIs(t, err, errWrongTunnel)
+
+	key, err := service.Redeem(ctx, token, "session-1", "192.0.2.1", "")
+	require.NoError(t, err)
+	assert.True(t, service.Authorize(ctx, key, "session-1", "192.0.2.1"))
+	assert.False(t, service.Authorize(ctx, key, "session-2", "192.0.2.1"))
+	assert.False(t, service.Authorize(ctx, "unknown", "session-1", "192.0.2.1"))
+
+	// the share can be used multiple times
+	_, err = service.Redeem(ctx, token, "session-1", "192.0.2.2", "")
+	require.NoError(t, err)
+	shares, err := service.List(ctx, "client-1", "1")
+	require.NoError(t, err)
+	require.Len(t, shares, 1)
+	assert.Equal(t, 2, shares[0].Uses)
+	assert.Equal(t, "192.0.2.2", shares[0].LastUsedIP)
+
+	revoked, err := service.Revoke(ctx, "client-1", "1", share.ID, "admin")
+	require.NoError(t, err)
+	assert.NotNil(t, revoked.RevokedAt)
+	assert.False(t, service.Authorize(ctx, key, "session-1", "192.0.2.1"))
+	_, err = service.Redeem(ctx, token, "session-1", "192.0.2.1", "")
+	assert.ErrorIs(t, err, errRevoked)
+
+	_, err = service.Revoke(ctx, "client-2", "1", share.ID, "admin")
+	assertStatus(t, http.StatusNotFound, err)
+
+	require.Len(t, accesses, 5)
+	assert.Nil(t, accesses[0].share)
+	assert.NoError(t, accesses[2].err)
+	assert.ErrorIs(t, accesses[4].err, errRevoked)
+}
+
+func TestRedeemOneTimeBoundIP(t *testing.T) {
+	ctx := context.Background()
+	service := newSharesService(t, nil)
+
+	_, token, err := service.Create(ctx, "client-1", newTestTunnel(), ShareRequest{ExpiresIn: "1h", OneTime: true, BoundIP: "192.0.2.1"}, "admin")
+	require.NoError(t, err)
+
+	_, err = service.Redeem(ctx, token, "session-1", "192.0.2.2", "")
+	assert.ErrorIs(t, err, errWrongIP)
+
+	key, err := service.Redeem(ctx, token, "session-1", "192.0.2.1", "")
+	require.NoError(t, err)
+	assert.True(t, service.Authorize(ctx, key, "session-1", "192.0.2.1"))
+	assert.False(t, service.Authorize(ctx, key, "session-1", "192.0.2.2"))
+
+	_, err = service.Redeem(ctx, token, "session-1", "192.0.2.1", "")
+	assert.ErrorIs(t, err, errUsed)
+}
+
+func TestRedeemExpired(t *testing.T) {
+	ctx := context.Background()
+	service := newSharesService(t, nil)
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	_, token, err := service.Create(ctx, "client-1", newTestTunnel(), ShareRequest{ExpiresIn: "1h"}, "admin")
+	require.NoError(t, err)
+	key, err := service.Redeem(ctx, token, "session-1", "192.0.2.1", "")
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Hour)
+	_, err = service.Redeem(ctx, token, "session-1", "192.0.2.1", "")
+	assert.ErrorIs(t, err, errExpired)
+	assert.False(t, service.Authorize(ctx, key, "session-1", "192.0.2.1"))
+
+	deleted, err := service.DeleteExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+}
+
+func TestRedeemOTP(t *testing.T) {
+	ctx := context.Background()
+	mailer := &mailerMock{}
+	service := newSharesService(t, mailer)
+
+	_, token, err := service.Create(ctx, "client-1", newTestTunnel(), ShareRequest{ExpiresIn: "1h", Email: "vendor@example.com"}, "admin")
+	require.NoError(t, err)
+
+	_, err = service.Redeem(ctx, token, "session-1", "192.0.2.1", "")
+	assert.ErrorIs(t, err, clienttunnel.ErrShareOTPRequired)
+	// no new code is sent while the first one is valid
+	_, err = service.Redeem(ctx, token, "session-1", "192.0.2.1", "")
+	assert.ErrorIs(t, err, clienttunnel.ErrShareOTPRequired)
+	require.Len(t, mailer.sent, 1)
+	assert.Equal(t, "vendor@example.com", mailer.sent[0].SendTo)
+	code := regexp.MustCompile(`access code is: (\w+)`).FindStringSubmatch(mailer.sent[0].Message)[1]
+
+	_, err = service.Redeem(ctx, token, "session-1", "192.0.2.1", "wrong")
+	assert.ErrorIs(t, err, clienttunnel.ErrShareInvalidOTP)
+
+	key, err := service.Redeem(ctx, token, "session-1", "192.0.2.1", code)
+	require.NoError(t, err)
+	assert.True(t, service.Authorize(ctx, key, "session-1", "192.0.2.1"))
+
+	// the code can't be used twice
+	_, err = service.Redeem(ctx, token, "session-1", "192.0.2.1", code)
+	assert.ErrorIs(t, err, clienttunnel.ErrShareInvalidOTP)
+}
@@ -0,0 +1,44 @@
+package tunnelshares
+
+import "time"
+
+// Share grants external users access to a tunnel via a link until it expires or is revoked
+type Share struct {
+	ID       string `json:"id" db:"id"`
+	ClientID string `json:"client_id" db:"client_id"`
+	TunnelID string `json:"tunnel_id" db:"tunnel_id"`
+	// TunnelSessionID binds the share to the running tunnel, a new tunnel reusing the tunnel id isn't shared
+	TunnelSessionID string    `json:"tunnel_session_id" db:"tunnel_session_id"`
+	Description     string    `json:"description" db:"description"`
+	CreatedBy       string    `json:"created_by" db:"created_by"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt       time.Time `json:"expires_at" db:"expires_at"`
+	// OneTime shares can be redeemed only once, the browser that redeemed it keeps the access until the share expires
+	OneTime bool `json:"one_time" db:"one_time"`
+	// BoundIP is the only IP the share can be used from, empty for any IP
+	BoundIP string `json:"bound_ip" db:"bound_ip"`
+	// Email receives an access code that has to be entered to redeem the share, empty if no code is required
+	Email      string     `json:"email" db:"email"`
+	Uses       int        `json:"uses" db:"uses"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	LastUsedIP string     `json:"last_used_ip" db:"last_used_ip"`
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+	RevokedBy  string     `json:"revoked_by" db:"revoked_by"`
+}
+
+// ShareRequest is the request to share a tunnel
+type ShareRequest struct {
+	// ExpiresIn is a duration like "30m" or "8h"
+	ExpiresIn   string `json:"expires_in"`
+	OneTime     bool   `json:"one_time"`
+	BoundIP     string `json:"bound_ip"`
+	Email       string `json:"email"`
+	Description string `json:"description"`
+}
+
+// CreatedShare is returned when a share is created, it's the only time the token is returned
+type CreatedShare struct {
+	*Share
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
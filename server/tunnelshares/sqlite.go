@@ -0,0 +1,131 @@
+package tunnelshares
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	tunnelsharesmigration "github.com/cloudradar-monitoring/rport/db/migration/tunnel_shares"
+)
+
+type Provider interface {
+	Create(ctx context.Context, share *Share) error
+	Get(ctx context.Context, id string) (*Share, error)
+	ListByTunnel(ctx context.Context, clientID, tunnelID string) ([]*Share, error)
+	AddUse(ctx context.Context, id string, usedAt time.Time, remoteIP string) (bool, error)
+	Revoke(ctx context.Context, id string, revokedAt time.Time, revokedBy string) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+	Close() error
+}
+
+type SqliteProvider struct {
+	db *sqlx.DB
+}
+
+func NewSqliteProvider(dbPath string, dbOptions database.Options) (*SqliteProvider, error) {
+	db, err := database.Open("tunnel_shares", dbPath, tunnelsharesmigration.AssetNames(), tunnelsharesmigration.Asset, dbOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tunnel shares DB instance: %v", err)
+	}
+
+	return &SqliteProvider{
+		db: db,
+	}, nil
+}
+
+func (p *SqliteProvider) Create(ctx context.Context, share *Share) error {
+	_, err := p.db.NamedExecContext(ctx,
+		`INSERT INTO tunnel_shares (
+			id,
+			client_id,
+			tunnel_id,
+			tunnel_session_id,
+			description,
+			created_by,
+			created_at,
+			expires_at,
+			one_time,
+			bound_ip,
+			email
+		) VALUES (
+			:id,
+			:client_id,
+			:tunnel_id,
+			:tunnel_session_id,
+			:description,
+			:created_by,
+			:created_at,
+			:expires_at,
+			:one_time,
+			:bound_ip,
+			:email
+		)`,
+		share,
+	)
+	return err
+}
+
+func (p *SqliteProvider) Get(ctx context.Context, id string) (*Share, error) {
+	share := &Share{}
+	err := p.db.GetContext(ctx, share, p.db.Rebind("SELECT * FROM tunnel_shares WHERE id = ?"), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return share, nil
+}
+
+func (p *SqliteProvider) ListByTunnel(ctx context.Context, clientID, tunnelID string) ([]*Share, error) {
+	shares := []*Share{}
+	err := p.db.SelectContext(ctx, &shares, p.db.Rebind(
+		"SELECT * FROM tunnel_shares WHERE client_id = ? AND tunnel_id = ? ORDER BY created_at DESC"),
+		clientID, tunnelID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// AddUse counts a use of a share, it returns false if the share is one-time and was already used
+func (p *SqliteProvider) AddUse(ctx context.Context, id string, usedAt time.Time, remoteIP string) (bool, error) {
+	res, err := p.db.ExecContext(ctx, p.db.Rebind(
+		`UPDATE tunnel_shares SET uses = uses + 1, last_used_at = ?, last_used_ip = ? WHERE id = ? AND (NOT one_time OR uses = 0)`),
+		usedAt, remoteIP, id,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Revoke revokes a share, it's a no-op if the share is already revoked
+func (p *SqliteProvider) Revoke(ctx context.Context, id string, revokedAt time.Time, revokedBy string) error {
+	_, err := p.db.ExecContext(ctx, p.db.Rebind(
+		"UPDATE tunnel_shares SET revoked_at = ?, revoked_by = ? WHERE id = ? AND revoked_at IS NULL"),
+		revokedAt, revokedBy, id,
+	)
+	return err
+}
+
+func (p *SqliteProvider) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	res, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM tunnel_shares WHERE expires_at < ?"), before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (p *SqliteProvider) Close() error {
+	return p.db.Close()
+}
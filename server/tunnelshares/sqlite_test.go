@@ -0,0 +1,76 @@
+package tunnelshares
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/share/test"
+)
+
+func TestSqliteProvider(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		p, err := NewSqliteProvider(":memory:", database.Options{PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		defer p.Close()
+
+		createdAt := time.Date(2022, 10, 16, 12, 0, 0, 0, time.UTC)
+		require.NoError(t, p.Create(ctx, &Share{ID: "share-1", ClientID: "client-1", TunnelID: "1", TunnelSessionID: "session-1", CreatedBy: "admin", CreatedAt: createdAt, ExpiresAt: createdAt.Add(time.Hour), OneTime: true}))
+		require.NoError(t, p.Create(ctx, &Share{ID: "share-2", ClientID: "client-1", TunnelID: "1", TunnelSessionID: "session-1", CreatedBy: "admin", CreatedAt: createdAt.Add(time.Minute), ExpiresAt: createdAt.Add(24 * time.Hour), BoundIP: "192.0.2.1", Email: "guest@example.com"}))
+
+		shares, err := p.ListByTunnel(ctx, "client-1", "1")
+		require.NoError(t, err)
+		require.Len(t, shares, 2)
+		assert.Equal(t, "share-2", shares[0].ID)
+		assert.Equal(t, "guest@example.com", shares[0].Email)
+		shares, err = p.ListByTunnel(ctx, "client-2", "1")
+		require.NoError(t, err)
+		assert.Empty(t, shares)
+
+		usedAt := createdAt.Add(5 * time.Minute)
+		used, err := p.AddUse(ctx, "share-1", usedAt, "192.0.2.10")
+		require.NoError(t, err)
+		assert.True(t, used)
+		used, err = p.AddUse(ctx, "share-1", usedAt, "192.0.2.11")
+		require.NoError(t, err)
+		assert.False(t, used, "one-time share must be used only once")
+		for i := 0; i < 2; i++ {
+			used, err = p.AddUse(ctx, "share-2", usedAt, "192.0.2.1")
+			require.NoError(t, err)
+			assert.True(t, used)
+		}
+
+		share, err := p.Get(ctx, "share-1")
+		require.NoError(t, err)
+		require.NotNil(t, share)
+		assert.True(t, share.OneTime)
+		assert.Equal(t, 1, share.Uses)
+		assert.Equal(t, "192.0.2.10", share.LastUsedIP)
+		require.NotNil(t, share.LastUsedAt)
+		assert.True(t, usedAt.Equal(*share.LastUsedAt))
+		share, err = p.Get(ctx, "share-2")
+		require.NoError(t, err)
+		assert.Equal(t, 2, share.Uses)
+
+		revokedAt := createdAt.Add(10 * time.Minute)
+		require.NoError(t, p.Revoke(ctx, "share-2", revokedAt, "admin"))
+		require.NoError(t, p.Revoke(ctx, "share-2", revokedAt.Add(time.Minute), "other"))
+		share, err = p.Get(ctx, "share-2")
+		require.NoError(t, err)
+		assert.Equal(t, "admin", share.RevokedBy)
+		require.NotNil(t, share.RevokedAt)
+		assert.True(t, revokedAt.Equal(*share.RevokedAt))
+
+		deleted, err := p.DeleteExpired(ctx, createdAt.Add(2*time.Hour))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+		share, err = p.Get(ctx, "share-1")
+		require.NoError(t, err)
+		assert.Nil(t, share)
+	})
+}
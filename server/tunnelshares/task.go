@@ -0,0 +1,30 @@
+package tunnelshares
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudradar-monitoring/rport/share/logger"
+)
+
+type CleanupTask struct {
+	log     *logger.Logger
+	service *Service
+}
+
+// NewCleanupTask returns a task to delete the expired tunnel shares
+func NewCleanupTask(log *logger.Logger, service *Service) *CleanupTask {
+	return &CleanupTask{
+		log:     log,
+		service: service,
+	}
+}
+
+func (t *CleanupTask) Run(ctx context.Context) error {
+	deleted, err := t.service.DeleteExpired(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to cleanup tunnel shares: %v", err)
+	}
+	t.log.Debugf("tunnelshares.CleanupTask: %d expired tunnel shares deleted", deleted)
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	errors3 "github.com/cloudradar-monitoring/rport/share/errors"
 
 	"github.com/cloudradar-monitoring/rport/server/api"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
 
 	"github.com/cloudradar-monitoring/rport/server/auditlog"
 	"github.com/cloudradar-monitoring/rport/server/clients"
@@ -66,6 +67,9 @@ func (al *APIListener) handleFileUploads(w http.ResponseWriter, req *http.Reques
 	}
 	defer uploadRequest.File.Close()
 
+	uploadRequest.CreatedBy = curUser.Username
+	uploadRequest.CreatedByGroups = curUser.Groups
+
 	wasCreated, err := al.filesAPI.CreateDirIfNotExists(al.config.GetUploadDir(), files.DefaultMode)
 	if err != nil {
 		al.jsonError(w, err)
@@ -93,7 +97,7 @@ func (al *APIListener) handleFileUploads(w http.ResponseWriter, req *http.Reques
 	if err != nil {
 		al.jsonError(w, err)
 	}
-	if err := cr.CheckClientsAccess(uploadRequest.Clients, curUser, clientGroups); err != nil {
+	if err := cr.CheckClientsAccess(uploadRequest.Clients, curUser, clientGroups, users.PermissionUploads); err != nil {
 		al.jsonErrorResponseWithDetail(w, http.StatusForbidden, "ACCESS_CONTROL_VIOLATION", "upload forbidden", err.Error())
 		return
 	}
@@ -122,6 +122,8 @@ func TestHandleFileUploads(t *testing.T) {
 				ForceWrite:           true,
 				Sync:                 true,
 				Md5Checksum:          test.Md5Hash("some content"),
+				CreatedBy:            "admin",
+				CreatedByGroups:      []string{users.Administrators},
 			},
 		},
 		{
@@ -178,6 +180,8 @@ func TestHandleFileUploads(t *testing.T) {
 				ForceWrite:           true,
 				Sync:                 true,
 				Md5Checksum:          test.Md5Hash("some content"),
+				CreatedBy:            "admin",
+				CreatedByGroups:      []string{users.Administrators},
 			},
 		},
 		{
@@ -90,3 +90,28 @@ func ResolveTunnelAutoCloseValue(durationStr string) (time.Duration, error) {
 
 	return dur, nil
 }
+
+// ResolveTunnelRateLimitValue parses a rate limit in bytes per second, an empty value means unlimited
+func ResolveTunnelRateLimitValue(name, bytesPerSecStr string) (int64, error) {
+	if bytesPerSecStr == "" {
+		return 0, nil
+	}
+
+	bytesPerSec, err := strconv.ParseInt(bytesPerSecStr, 10, 64)
+	if err != nil {
+		return 0, errors2.APIError{
+			Message:    fmt.Sprintf("invalid %s param", name),
+			Err:        err,
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	if bytesPerSec < 0 {
+		return 0, errors2.APIError{
+			Message:    fmt.Sprintf("%s param should not be negative", name),
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	return bytesPerSec, nil
+}
@@ -33,6 +33,11 @@ func (dpf *StatefulDbProviderFactory) Init() error {
 	dpf.providerLock.Lock()
 	defer dpf.providerLock.Unlock()
 
+	// the DB is already open when it's shared with other stores, e.g. PostgreSQL
+	if dpf.initDBProvider != nil {
+		return nil
+	}
+
 	initDBProvider, err := dpf.initDBBuilder()
 	if err != nil {
 		return err
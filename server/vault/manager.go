@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/share/logger"
 	"github.com/cloudradar-monitoring/rport/share/query"
 
@@ -32,6 +33,7 @@ var WrongPasswordError = errors2.APIError{
 
 type Config interface {
 	GetVaultDBPath() string
+	GetVaultDBOptions() database.Options
 }
 
 type UserDataProvider interface {
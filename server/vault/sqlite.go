@@ -15,16 +15,14 @@ import (
 
 	"github.com/jmoiron/sqlx"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	"github.com/cloudradar-monitoring/rport/db/migration/vaults"
-
-	"github.com/cloudradar-monitoring/rport/db/sqlite"
 )
 
 var ErrDatabaseNotInitialised = errors2.APIError{
 	Err:        errors.New("vault is not initialized yet"),
 	HTTPStatus: http.StatusConflict,
 }
-var DataSourceOptions = sqlite.DataSourceOptions{WALEnabled: false}
 
 type SqliteProvider struct {
 	db        *sqlx.DB
@@ -35,7 +33,7 @@ type SqliteProvider struct {
 func NewSqliteProvider(c Config, logger *logger.Logger) (*SqliteProvider, error) {
 	dbPath := c.GetVaultDBPath()
 
-	db, err := sqlite.New(dbPath, vaults.AssetNames(), vaults.Asset, DataSourceOptions)
+	db, err := database.Open("vaults", dbPath, vaults.AssetNames(), vaults.Asset, c.GetVaultDBOptions())
 	if err != nil {
 		return nil, fmt.Errorf("failed init vault DB instance: %w", err)
 	}
@@ -59,7 +57,7 @@ func (p *SqliteProvider) Close() error {
 
 func (p *SqliteProvider) GetStatus(ctx context.Context) (DbStatus, error) {
 	res := DbStatus{}
-	err := p.db.GetContext(ctx, &res, "SELECT * FROM `status` LIMIT 1")
+	err := p.db.GetContext(ctx, &res, "SELECT * FROM status LIMIT 1")
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return res, nil
@@ -77,7 +75,7 @@ func (p *SqliteProvider) SetStatus(ctx context.Context, newStatus DbStatus) erro
 	}
 
 	var idToUpdate int
-	err = tx.GetContext(ctx, &idToUpdate, "SELECT id FROM `status` LIMIT 1")
+	err = tx.GetContext(ctx, &idToUpdate, "SELECT id FROM status LIMIT 1")
 	if err != nil {
 		if err == sql.ErrNoRows {
 			idToUpdate = 0
@@ -90,7 +88,7 @@ func (p *SqliteProvider) SetStatus(ctx context.Context, newStatus DbStatus) erro
 	if idToUpdate == 0 {
 		_, err = tx.ExecContext(
 			ctx,
-			"INSERT INTO `status` (`db_status`, `enc_check`, `dec_check`) VALUES (?, ?, ?)",
+			tx.Rebind("INSERT INTO status (db_status, enc_check, dec_check) VALUES (?, ?, ?)"),
 			newStatus.StatusName,
 			newStatus.EncCheckValue,
 			newStatus.DecCheckValue,
@@ -101,14 +99,14 @@ func (p *SqliteProvider) SetStatus(ctx context.Context, newStatus DbStatus) erro
 			return err
 		}
 	} else {
-		q := "UPDATE `status` SET db_status=?, enc_check = ?, dec_check = ? WHERE id = ?"
+		q := "UPDATE status SET db_status=?, enc_check = ?, dec_check = ? WHERE id = ?"
 		params := []interface{}{
 			newStatus.StatusName,
 			newStatus.EncCheckValue,
 			newStatus.DecCheckValue,
 			idToUpdate,
 		}
-		_, err = tx.ExecContext(ctx, q, params...)
+		_, err = tx.ExecContext(ctx, tx.Rebind(q), params...)
 		if err != nil {
 			p.handleRollback(tx)
 			return err
@@ -124,7 +122,7 @@ func (p *SqliteProvider) SetStatus(ctx context.Context, newStatus DbStatus) erro
 }
 
 func (p *SqliteProvider) GetByID(ctx context.Context, id int) (val StoredValue, found bool, err error) {
-	err = p.db.GetContext(ctx, &val, "SELECT * FROM `values` WHERE `id` = ? LIMIT 1", id)
+	err = p.db.GetContext(ctx, &val, p.db.Rebind(`SELECT * FROM "values" WHERE id = ? LIMIT 1`), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return val, false, nil
@@ -139,11 +137,11 @@ func (p *SqliteProvider) GetByID(ctx context.Context, id int) (val StoredValue,
 func (p *SqliteProvider) List(ctx context.Context, lo *query.ListOptions) ([]ValueKey, error) {
 	values := []ValueKey{}
 
-	q := "SELECT `id`, `client_id`, `created_by`, `created_at`, `key` FROM `values`"
+	q := `SELECT id, client_id, created_by, created_at, key FROM "values"`
 
 	q, params := p.converter.ConvertListOptionsToQuery(lo, q)
 
-	err := p.db.SelectContext(ctx, &values, q, params...)
+	err := p.db.SelectContext(ctx, &values, p.db.Rebind(q), params...)
 	if err != nil {
 		return values, err
 	}
@@ -152,7 +150,7 @@ func (p *SqliteProvider) List(ctx context.Context, lo *query.ListOptions) ([]Val
 }
 
 func (p *SqliteProvider) FindByKeyAndClientID(ctx context.Context, key, clientID string) (val StoredValue, found bool, err error) {
-	err = p.db.GetContext(ctx, &val, "SELECT * FROM `values` WHERE `key` = ? and `client_id` = ? LIMIT 1", key, clientID)
+	err = p.db.GetContext(ctx, &val, p.db.Rebind(`SELECT * FROM "values" WHERE key = ? and client_id = ? LIMIT 1`), key, clientID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return val, false, nil
@@ -166,9 +164,12 @@ func (p *SqliteProvider) FindByKeyAndClientID(ctx context.Context, key, clientID
 
 func (p *SqliteProvider) Save(ctx context.Context, user string, idToUpdate int64, val *InputValue, nowDate time.Time) (int64, error) {
 	if idToUpdate == 0 {
-		res, err := p.db.ExecContext(
+		var err error
+		idToUpdate, err = database.InsertReturningID(
 			ctx,
-			"INSERT INTO `values` (`client_id`, `required_group`, `created_at`, `created_by`, `updated_at`, `updated_by`, `key`, `value`, `type`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			p.db,
+			`INSERT INTO "values" (client_id, required_group, created_at, created_by, updated_at, updated_by, key, value, type) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			"id",
 			val.ClientID,
 			val.RequiredGroup,
 			nowDate.Format(time.RFC3339),
@@ -179,16 +180,11 @@ func (p *SqliteProvider) Save(ctx context.Context, user string, idToUpdate int64
 			val.Value,
 			val.Type,
 		)
-
-		if err != nil {
-			return 0, err
-		}
-		idToUpdate, err = res.LastInsertId()
 		if err != nil {
 			return 0, err
 		}
 	} else {
-		q := "UPDATE `values` SET `client_id` = ?, `required_group` = ?, `updated_at` = ?, `updated_by` = ?, `key` = ?, `value` = ?, `type` = ? WHERE id = ?"
+		q := `UPDATE "values" SET client_id = ?, required_group = ?, updated_at = ?, updated_by = ?, key = ?, value = ?, type = ? WHERE id = ?`
 		params := []interface{}{
 			val.ClientID,
 			val.RequiredGroup,
@@ -199,7 +195,7 @@ func (p *SqliteProvider) Save(ctx context.Context, user string, idToUpdate int64
 			val.Type,
 			idToUpdate,
 		}
-		_, err := p.db.ExecContext(ctx, q, params...)
+		_, err := p.db.ExecContext(ctx, p.db.Rebind(q), params...)
 		if err != nil {
 			return 0, err
 		}
@@ -209,7 +205,7 @@ func (p *SqliteProvider) Save(ctx context.Context, user string, idToUpdate int64
 }
 
 func (p *SqliteProvider) Delete(ctx context.Context, id int) error {
-	res, err := p.db.ExecContext(ctx, "DELETE FROM `values` WHERE `id` = ?", id)
+	res, err := p.db.ExecContext(ctx, p.db.Rebind(`DELETE FROM "values" WHERE id = ?`), id)
 
 	if err != nil {
 		return err
@@ -2,10 +2,12 @@ package vault
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/cloudradar-monitoring/rport/db/database"
 	chshare "github.com/cloudradar-monitoring/rport/share/logger"
 	"github.com/cloudradar-monitoring/rport/share/query"
 
@@ -20,12 +22,17 @@ import (
 var testLog = chshare.NewLogger("vault", chshare.LogOutput{File: os.Stdout}, chshare.LogLevelDebug)
 
 type configMock struct {
+	postgresDSN string
 }
 
 func (cm configMock) GetVaultDBPath() string {
 	return ":memory:"
 }
 
+func (cm configMock) GetVaultDBOptions() database.Options {
+	return database.Options{PostgresDSN: cm.postgresDSN}
+}
+
 func TestSetStatus(t *testing.T) {
 	dbProv, err := NewSqliteProvider(configMock{}, testLog)
 	require.NoError(t, err)
@@ -546,3 +553,61 @@ func addDemoData(db *sqlx.DB) error {
 
 	return nil
 }
+
+func TestProviderDatabases(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		dbProv, err := NewSqliteProvider(configMock{postgresDSN: postgresDSN}, testLog)
+		require.NoError(t, err)
+		defer dbProv.Close()
+
+		status, err := dbProv.GetStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, DbStatus{}, status)
+		require.NoError(t, dbProv.SetStatus(ctx, DbStatus{StatusName: DbStatusInit, EncCheckValue: "enc", DecCheckValue: "dec"}))
+		require.NoError(t, dbProv.SetStatus(ctx, DbStatus{StatusName: DbStatusInit, EncCheckValue: "enc2", DecCheckValue: "dec2"}))
+		status, err = dbProv.GetStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "enc2", status.EncCheckValue)
+		assert.Equal(t, "dec2", status.DecCheckValue)
+
+		createdAt := time.Date(2022, 10, 16, 12, 0, 0, 0, time.UTC)
+		id1, err := dbProv.Save(ctx, "admin", 0, &InputValue{ClientID: "client-1", Key: "password", Value: "secret", Type: SecretType}, createdAt)
+		require.NoError(t, err)
+		id2, err := dbProv.Save(ctx, "admin", 0, &InputValue{Key: "notes", Value: "text", Type: TextType}, createdAt)
+		require.NoError(t, err)
+		assert.NotEqual(t, id1, id2)
+
+		_, err = dbProv.Save(ctx, "other", id1, &InputValue{ClientID: "client-1", Key: "password", Value: "changed", Type: SecretType}, createdAt.Add(time.Hour))
+		require.NoError(t, err)
+		val, found, err := dbProv.GetByID(ctx, int(id1))
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, "changed", val.Value)
+		assert.Equal(t, "admin", val.CreatedBy)
+		require.NotNil(t, val.UpdatedBy)
+		assert.Equal(t, "other", *val.UpdatedBy)
+		assert.True(t, createdAt.Equal(val.CreatedAt))
+		assert.True(t, createdAt.Add(time.Hour).Equal(val.UpdatedAt))
+
+		val, found, err = dbProv.FindByKeyAndClientID(ctx, "password", "client-1")
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.EqualValues(t, id1, val.ID)
+		_, found, err = dbProv.FindByKeyAndClientID(ctx, "password", "client-2")
+		require.NoError(t, err)
+		assert.False(t, found)
+
+		keys, err := dbProv.List(ctx, &query.ListOptions{
+			Sorts:   []query.SortOption{{Column: "key", IsASC: true}},
+			Filters: []query.FilterOption{{Column: []string{"created_by"}, Values: []string{"admin"}}},
+		})
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, "notes", keys[0].Key)
+		assert.Equal(t, "password", keys[1].Key)
+
+		require.NoError(t, dbProv.Delete(ctx, int(id2)))
+		assert.EqualError(t, dbProv.Delete(ctx, int(id2)), fmt.Sprintf("cannot find entry by id %d", id2))
+	})
+}
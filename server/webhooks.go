@@ -0,0 +1,88 @@
+package chserver
+
+import (
+	"path"
+
+	"github.com/cloudradar-monitoring/rport/server/api/jobs/schedule"
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	"github.com/cloudradar-monitoring/rport/server/webhooks"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+func initWebhooksService(config *chconfig.Config, log *logger.Logger) (*webhooks.Service, error) {
+	provider, err := webhooks.NewSqliteProvider(
+		path.Join(config.Server.DataDir, "webhooks.db"),
+		config.GetDatabaseOptions(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks.NewService(config.Webhooks, provider, log), nil
+}
+
+func clientEvent(c *clients.Client) webhooks.ClientEvent {
+	return webhooks.ClientEvent{
+		ClientID: c.ID,
+		Name:     c.Name,
+		Address:  c.Address,
+	}
+}
+
+// tunnelEvent returns the data of a tunnel event, the reason is set for deleted tunnels only
+func tunnelEvent(clientID, username string, t *clienttunnel.Tunnel, reason string) webhooks.TunnelEvent {
+	event := webhooks.TunnelEvent{
+		ClientID:   clientID,
+		TunnelID:   t.ID,
+		Username:   username,
+		Protocol:   t.Protocol,
+		LocalHost:  t.LocalHost,
+		LocalPort:  t.LocalPort,
+		RemoteHost: t.RemoteHost,
+		RemotePort: t.RemotePort,
+		Reason:     reason,
+	}
+	if t.Scheme != nil {
+		event.Scheme = *t.Scheme
+	}
+	if t.ACL != nil {
+		event.ACL = *t.ACL
+	}
+	return event
+}
+
+// onScheduleRun notifies the webhooks about a schedule that was run
+func (s *Server) onScheduleRun(sch *schedule.Schedule, multiJob *models.MultiJob, err error) {
+	event := webhooks.ScheduleEvent{
+		ScheduleID: sch.ID,
+		Name:       sch.Name,
+	}
+	if multiJob != nil {
+		event.JobID = multiJob.JID
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	s.webhooksService.Dispatch(webhooks.EventScheduleRun, event)
+}
+
+// jobEvent returns the webhook event and its data of a finished job
+func jobEvent(job *models.Job) (string, webhooks.JobEvent) {
+	event := webhooks.EventJobFinished
+	if job.Status == models.JobStatusFailed {
+		event = webhooks.EventJobFailed
+	}
+	return event, webhooks.JobEvent{
+		JID:        job.JID,
+		MultiJobID: job.MultiJobID,
+		ClientID:   job.ClientID,
+		Status:     job.Status,
+		CreatedBy:  job.CreatedBy,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+		Error:      job.Error,
+	}
+}
@@ -0,0 +1,38 @@
+package webhooks
+
+import (
+	"fmt"
+	"time"
+)
+
+type Config struct {
+	Enabled         bool          `mapstructure:"enabled"`
+	MaxAttempts     int           `mapstructure:"max_attempts"`
+	RetryInterval   time.Duration `mapstructure:"retry_interval"`
+	Timeout         time.Duration `mapstructure:"timeout"`
+	DataStorageDays int64         `mapstructure:"data_storage_days"`
+}
+
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.MaxAttempts < 1 {
+		return fmt.Errorf("invalid webhooks.max_attempts: must be at least 1, got %d", c.MaxAttempts)
+	}
+
+	if c.RetryInterval < time.Second {
+		return fmt.Errorf("invalid webhooks.retry_interval: must be at least 1s, got %s", c.RetryInterval)
+	}
+
+	if c.Timeout < time.Second {
+		return fmt.Errorf("invalid webhooks.timeout: must be at least 1s, got %s", c.Timeout)
+	}
+
+	if c.DataStorageDays < 1 {
+		return fmt.Errorf("invalid webhooks.data_storage_days: must be at least 1, got %d", c.DataStorageDays)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package webhooks
+
+import "time"
+
+// ClientEvent is the data of the client.connected and client.disconnected events
+type ClientEvent struct {
+	ClientID string `json:"client_id"`
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+}
+
+// TunnelEvent is the data of the tunnel.created and tunnel.deleted events. It describes the tunnel without its
+// credentials, the payload is sent to third parties.
+type TunnelEvent struct {
+	ClientID   string `json:"client_id"`
+	TunnelID   string `json:"tunnel_id"`
+	Username   string `json:"username"`
+	Protocol   string `json:"protocol"`
+	LocalHost  string `json:"lhost"`
+	LocalPort  string `json:"lport"`
+	RemoteHost string `json:"rhost"`
+	RemotePort string `json:"rport"`
+	Scheme     string `json:"scheme,omitempty"`
+	ACL        string `json:"acl,omitempty"`
+	// Reason is why a tunnel was deleted, e.g. idle_timeout, empty for tunnel.created
+	Reason string `json:"reason,omitempty"`
+}
+
+// ScheduleEvent is the data of the schedule.run event
+type ScheduleEvent struct {
+	ScheduleID string `json:"schedule_id"`
+	Name       string `json:"name"`
+	JobID      string `json:"job_id,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// UserEvent is the data of the vault.unlocked and login.failed events
+type UserEvent struct {
+	Username string `json:"username"`
+	RemoteIP string `json:"remote_ip"`
+}
+
+// JobEvent is the data of the job.finished and job.failed events. It leaves out the command and its result, the
+// payload is sent to third parties.
+type JobEvent struct {
+	JID        string     `json:"jid"`
+	MultiJobID *string    `json:"multi_job_id"`
+	ClientID   string     `json:"client_id"`
+	Status     string     `json:"status"`
+	CreatedBy  string     `json:"created_by"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	Error      string     `json:"error,omitempty"`
+}
@@ -0,0 +1,417 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/server/api"
+	errors2 "github.com/cloudradar-monitoring/rport/server/api/errors"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/random"
+)
+
+const (
+	HeaderEvent     = "X-Rport-Event"
+	HeaderDelivery  = "X-Rport-Delivery"
+	HeaderSignature = "X-Rport-Signature"
+
+	// maxErrorBodyLen limits the response body stored as error of a failed delivery
+	maxErrorBodyLen = 1024
+	// queueSize limits the events and retries waiting for a worker, further ones are dropped
+	queueSize = 1000
+	// workers is the number of events processed concurrently
+	workers = 4
+)
+
+var (
+	supportedDeliveryFilters = map[string]bool{
+		"event":             true,
+		"status":            true,
+		"created_at[gt]":    true,
+		"created_at[lt]":    true,
+		"created_at[since]": true,
+		"created_at[until]": true,
+	}
+	supportedDeliverySorts = map[string]bool{
+		"created_at": true,
+		"updated_at": true,
+		"event":      true,
+		"status":     true,
+	}
+	defaultDeliverySort = []query.SortOption{{Column: "created_at", IsASC: false}}
+)
+
+// Service posts server events to the configured webhooks. Events are queued and delivered by a fixed number of
+// workers, failed deliveries are queued again with an exponential backoff. The result of each delivery is kept in
+// the delivery log.
+type Service struct {
+	config     Config
+	provider   Provider
+	logger     *logger.Logger
+	httpClient *http.Client
+
+	queue chan *task
+	// pending counts the queued tasks and the retries waiting for their backoff
+	pending sync.WaitGroup
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	now func() time.Time
+}
+
+// task is either an event to dispatch to the subscribed webhooks or the retry of a failed delivery
+type task struct {
+	event string
+	data  json.RawMessage
+
+	webhook  *Webhook
+	delivery *Delivery
+	body     []byte
+	delay    time.Duration
+}
+
+func NewService(config Config, provider Provider, logger *logger.Logger) *Service {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Service{
+		config:     config,
+		provider:   provider,
+		logger:     logger,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		queue:      make(chan *task, queueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+		now:        time.Now,
+	}
+
+	s.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go s.work()
+	}
+
+	return s
+}
+
+func (s *Service) Get(ctx context.Context, id string) (*Webhook, error) {
+	webhook, err := s.provider.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, errors2.APIError{
+			Message:    fmt.Sprintf("Webhook with id %q not found.", id),
+			HTTPStatus: http.StatusNotFound,
+		}
+	}
+	return webhook, nil
+}
+
+func (s *Service) GetAll(ctx context.Context) ([]*Webhook, error) {
+	return s.provider.GetAll(ctx)
+}
+
+func (s *Service) Create(ctx context.Context, webhook *Webhook, username string) (*Webhook, error) {
+	if err := webhook.Validate(); err != nil {
+		return nil, errors2.APIError{
+			Err:        err,
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	id, err := random.UUID4()
+	if err != nil {
+		return nil, err
+	}
+	webhook.ID = id
+	webhook.CreatedBy = username
+	webhook.CreatedAt = s.now().UTC()
+
+	if err := s.provider.Save(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// Update changes an existing webhook, the secret is kept if the given one is empty
+func (s *Service) Update(ctx context.Context, id string, webhook *Webhook) (*Webhook, error) {
+	existing, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := webhook.Validate(); err != nil {
+		return nil, errors2.APIError{
+			Err:        err,
+			HTTPStatus: http.StatusBadRequest,
+		}
+	}
+
+	webhook.ID = existing.ID
+	webhook.CreatedBy = existing.CreatedBy
+	webhook.CreatedAt = existing.CreatedAt
+	if webhook.Secret == "" {
+		webhook.Secret = existing.Secret
+	}
+
+	if err := s.provider.Save(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	return s.provider.Delete(ctx, id)
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, webhookID string, options *query.ListOptions) (*api.SuccessPayload, error) {
+	if _, err := s.Get(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	err := query.ValidateListOptions(options, supportedDeliverySorts, supportedDeliveryFilters, nil, &query.PaginationConfig{
+		DefaultLimit: 10,
+		MaxLimit:     100,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(options.Sorts) == 0 {
+		options.Sorts = defaultDeliverySort
+	}
+	options.Filters = append(options.Filters, query.FilterOption{
+		Column: []string{"webhook_id"},
+		Values: []string{webhookID},
+	})
+
+	deliveries, err := s.provider.ListDeliveries(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.provider.CountDeliveries(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.SuccessPayload{
+		Data: deliveries,
+		Meta: api.NewMeta(count),
+	}, nil
+}
+
+// DeleteDeliveriesOlderThan deletes the delivery log entries older than a given period
+func (s *Service) DeleteDeliveriesOlderThan(ctx context.Context, period time.Duration) (int64, error) {
+	return s.provider.DeleteDeliveriesBefore(ctx, s.now().Add(-period).UTC())
+}
+
+// Dispatch queues an event for all webhooks subscribed to it. It returns immediately without touching the
+// database, the event is dropped if the queue is full. It's a no-op if webhooks are disabled so callers don't need
+// to check it.
+func (s *Service) Dispatch(event string, data interface{}) {
+	if s == nil {
+		return
+	}
+
+	// the data is encoded right away, callers may change it once Dispatch returned
+	raw, err := json.Marshal(data)
+	if err != nil {
+		s.logger.Errorf("Failed to encode data of event %s: %v", event, err)
+		return
+	}
+
+	if !s.enqueue(&task{event: event, data: raw}) {
+		s.logger.Errorf("Webhooks queue is full, event %s dropped", event)
+	}
+}
+
+// enqueue adds a task to the queue without blocking, it returns false if the queue is full
+func (s *Service) enqueue(t *task) bool {
+	s.pending.Add(1)
+	select {
+	case s.queue <- t:
+		return true
+	default:
+		s.pending.Done()
+		return false
+	}
+}
+
+func (s *Service) work() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case t := <-s.queue:
+			s.run(t)
+			s.pending.Done()
+		}
+	}
+}
+
+func (s *Service) run(t *task) {
+	if t.delivery != nil {
+		s.deliver(t.webhook, t.delivery, t.body, t.delay)
+		return
+	}
+
+	webhooks, err := s.provider.GetAll(s.ctx)
+	if err != nil {
+		s.logger.Errorf("Failed to get webhooks for event %s: %v", t.event, err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		if !webhook.Subscribed(t.event) {
+			continue
+		}
+
+		delivery, body, err := s.newDelivery(webhook, t.event, t.data)
+		if err != nil {
+			s.logger.Errorf("Failed to create delivery of event %s to webhook %s: %v", t.event, webhook.ID, err)
+			continue
+		}
+
+		s.deliver(webhook, delivery, body, s.config.RetryInterval)
+	}
+}
+
+func (s *Service) newDelivery(webhook *Webhook, event string, data json.RawMessage) (*Delivery, []byte, error) {
+	id, err := random.UUID4()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := s.now().UTC()
+	body, err := json.Marshal(Payload{
+		ID:        id,
+		Event:     event,
+		Timestamp: now,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	delivery := &Delivery{
+		ID:        id,
+		WebhookID: webhook.ID,
+		Event:     event,
+		Payload:   string(body),
+		Status:    DeliveryStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.provider.SaveDelivery(s.ctx, delivery); err != nil {
+		return nil, nil, err
+	}
+
+	return delivery, body, nil
+}
+
+// deliver posts the payload once. If it failed and the max attempts aren't reached yet, the delivery is queued again
+// after the given delay, which doubles with each attempt.
+func (s *Service) deliver(webhook *Webhook, delivery *Delivery, body []byte, delay time.Duration) {
+	delivery.Attempts++
+	delivery.StatusCode, delivery.Error = s.post(webhook, delivery, body)
+	delivery.UpdatedAt = s.now().UTC()
+
+	retry := delivery.Error != "" && delivery.Attempts < s.config.MaxAttempts
+	switch {
+	case delivery.Error == "":
+		delivery.Status = DeliveryStatusDelivered
+	case !retry:
+		delivery.Status = DeliveryStatusFailed
+		s.logger.Errorf("Failed to deliver event %s to webhook %s after %d attempts: %s", delivery.Event, webhook.ID, delivery.Attempts, delivery.Error)
+	}
+
+	// the delivery log is written even if the service is closing
+	s.saveDelivery(delivery)
+	if !retry || s.ctx.Err() != nil {
+		return
+	}
+
+	s.pending.Add(1)
+	time.AfterFunc(delay, func() {
+		defer s.pending.Done()
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		retryTask := &task{
+			webhook:  webhook,
+			delivery: delivery,
+			body:     body,
+			delay:    delay * 2,
+		}
+		if !s.enqueue(retryTask) {
+			delivery.Status = DeliveryStatusFailed
+			delivery.Error = "webhooks queue is full, retry dropped"
+			delivery.UpdatedAt = s.now().UTC()
+			s.logger.Errorf("Webhooks queue is full, retry of delivery %s dropped", delivery.ID)
+			s.saveDelivery(delivery)
+		}
+	})
+}
+
+func (s *Service) saveDelivery(delivery *Delivery) {
+	if err := s.provider.SaveDelivery(context.Background(), delivery); err != nil {
+		s.logger.Errorf("Failed to save delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// post sends the payload once, it returns the status code of the response and an error message if it failed
+func (s *Service) post(webhook *Webhook, delivery *Delivery, body []byte) (int, string) {
+	req, err := http.NewRequestWithContext(s.ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.Event)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	if webhook.Secret != "" {
+		req.Header.Set(HeaderSignature, Signature(webhook.Secret, body))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLen))
+		return resp.StatusCode, fmt.Sprintf("unexpected status %s: %s", resp.Status, respBody)
+	}
+	// drain the body to reuse the connection
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, ""
+}
+
+// Signature returns the value of the signature header, the hex encoded HMAC-SHA256 of the body prefixed with "sha256="
+func Signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Close stops the workers and waits for the running deliveries to finish, queued events and retries are dropped
+func (s *Service) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return s.provider.Close()
+}
@@ -0,0 +1,258 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/share/logger"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+var testLog = logger.NewLogger("webhooks", logger.LogOutput{File: os.Stdout}, logger.LogLevelDebug)
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// testReceiver records the posted requests, it responds with the given status codes in order and 200 afterwards
+type testReceiver struct {
+	mu       sync.Mutex
+	requests []receivedRequest
+	statuses []int
+}
+
+func (r *testReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, receivedRequest{header: req.Header, body: body})
+	if len(r.statuses) > 0 {
+		w.WriteHeader(r.statuses[0])
+		r.statuses = r.statuses[1:]
+	}
+}
+
+// webhookTest is a service delivering to a test receiver at url
+type webhookTest struct {
+	service  *Service
+	receiver *testReceiver
+	url      string
+}
+
+// newWebhookTest starts the service and a receiver responding with the given statuses, both stop when the test ends
+func newWebhookTest(t *testing.T, maxAttempts int, statuses ...int) *webhookTest {
+	receiver := &testReceiver{statuses: statuses}
+	srv := httptest.NewServer(receiver)
+	t.Cleanup(srv.Close)
+
+	dbProvider, err := NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	service := NewService(Config{
+		MaxAttempts:   maxAttempts,
+		RetryInterval: time.Millisecond,
+		Timeout:       time.Second,
+	}, dbProvider, testLog)
+	t.Cleanup(func() { service.Close() })
+
+	return &webhookTest{service: service, receiver: receiver, url: srv.URL}
+}
+
+func (w *webhookTest) deliveries(t *testing.T, webhookID string) []*Delivery {
+	result, err := w.service.ListDeliveries(context.Background(), webhookID, &query.ListOptions{})
+	require.NoError(t, err)
+	return result.Data.([]*Delivery)
+}
+
+func TestDispatch(t *testing.T) {
+	ctx := context.Background()
+	wt := newWebhookTest(t, 3)
+	service, receiver := wt.service, wt.receiver
+
+	all, err := service.Create(ctx, &Webhook{URL: wt.url + "/all", Secret: "secret", Enabled: true}, "admin")
+	require.NoError(t, err)
+	jobsOnly, err := service.Create(ctx, &Webhook{URL: wt.url + "/jobs", Events: []string{EventJobFinished}, Enabled: true}, "admin")
+	require.NoError(t, err)
+	disabled, err := service.Create(ctx, &Webhook{URL: wt.url + "/disabled", Enabled: false}, "admin")
+	require.NoError(t, err)
+
+	service.Dispatch(EventClientConnected, ClientEvent{ClientID: "client-1", Name: "my client", Address: "192.0.2.1:1234"})
+	service.pending.Wait()
+
+	require.Len(t, receiver.requests, 1)
+	received := receiver.requests[0]
+	assert.Equal(t, "application/json", received.header.Get("Content-Type"))
+	assert.Equal(t, EventClientConnected, received.header.Get(HeaderEvent))
+	assert.Equal(t, Signature("secret", received.body), received.header.Get(HeaderSignature))
+
+	payload := struct {
+		ID    string      `json:"id"`
+		Event string      `json:"event"`
+		Data  ClientEvent `json:"data"`
+	}{}
+	require.NoError(t, json.Unmarshal(received.body, &payload))
+	assert.Equal(t, received.header.Get(HeaderDelivery), payload.ID)
+	assert.Equal(t, EventClientConnected, payload.Event)
+	assert.Equal(t, ClientEvent{ClientID: "client-1", Name: "my client", Address: "192.0.2.1:1234"}, payload.Data)
+
+	deliveries := wt.deliveries(t, all.ID)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, payload.ID, deliveries[0].ID)
+	assert.Equal(t, DeliveryStatusDelivered, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusOK, deliveries[0].StatusCode)
+	assert.Equal(t, string(received.body), deliveries[0].Payload)
+
+	assert.Empty(t, wt.deliveries(t, jobsOnly.ID))
+	assert.Empty(t, wt.deliveries(t, disabled.ID))
+}
+
+func TestDispatchRetry(t *testing.T) {
+	testCases := []struct {
+		Name               string
+		MaxAttempts        int
+		ExpectedStatus     DeliveryStatus
+		ExpectedAttempts   int
+		ExpectedStatusCode int
+		ExpectedError      string
+	}{
+		{
+			Name:               "delivered after retries",
+			MaxAttempts:        3,
+			ExpectedStatus:     DeliveryStatusDelivered,
+			ExpectedAttempts:   3,
+			ExpectedStatusCode: http.StatusOK,
+		},
+		{
+			Name:               "max attempts reached",
+			MaxAttempts:        2,
+			ExpectedStatus:     DeliveryStatusFailed,
+			ExpectedAttempts:   2,
+			ExpectedStatusCode: http.StatusServiceUnavailable,
+			ExpectedError:      "unexpected status 503 Service Unavailable: ",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			wt := newWebhookTest(t, tc.MaxAttempts, http.StatusInternalServerError, http.StatusServiceUnavailable)
+
+			webhook, err := wt.service.Create(context.Background(), &Webhook{URL: wt.url, Enabled: true}, "admin")
+			require.NoError(t, err)
+
+			wt.service.Dispatch(EventVaultUnlocked, UserEvent{Username: "admin"})
+			wt.service.pending.Wait()
+
+			assert.Len(t, wt.receiver.requests, tc.ExpectedAttempts)
+			deliveries := wt.deliveries(t, webhook.ID)
+			require.Len(t, deliveries, 1)
+			assert.Equal(t, tc.ExpectedStatus, deliveries[0].Status)
+			assert.Equal(t, tc.ExpectedAttempts, deliveries[0].Attempts)
+			assert.Equal(t, tc.ExpectedStatusCode, deliveries[0].StatusCode)
+			assert.Equal(t, tc.ExpectedError, deliveries[0].Error)
+		})
+	}
+}
+
+func TestDispatchQueueFull(t *testing.T) {
+	wt := newWebhookTest(t, 1)
+	service := wt.service
+	_, err := service.Create(context.Background(), &Webhook{URL: wt.url, Enabled: true}, "admin")
+	require.NoError(t, err)
+
+	// stop the workers, the queue isn't drained anymore
+	service.cancel()
+	service.wg.Wait()
+
+	for i := 0; i < queueSize+1; i++ {
+		service.Dispatch(EventLoginFailed, UserEvent{Username: "admin"})
+	}
+
+	assert.Len(t, service.queue, queueSize)
+	assert.Empty(t, wt.receiver.requests)
+}
+
+func TestDispatchDisabled(t *testing.T) {
+	var service *Service
+
+	assert.NotPanics(t, func() {
+		service.Dispatch(EventLoginFailed, UserEvent{Username: "admin"})
+	})
+}
+
+func TestUpdateKeepsSecret(t *testing.T) {
+	ctx := context.Background()
+	service := newWebhookTest(t, 1).service
+
+	webhook, err := service.Create(ctx, &Webhook{URL: "https://example.com/hook", Secret: "secret", Enabled: true}, "admin")
+	require.NoError(t, err)
+
+	updated, err := service.Update(ctx, webhook.ID, &Webhook{URL: "https://example.com/new", Events: []string{EventJobFailed}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "secret", updated.Secret)
+	assert.Equal(t, "admin", updated.CreatedBy)
+	assert.False(t, updated.Enabled)
+
+	stored, err := service.Get(ctx, webhook.ID)
+	require.NoError(t, err)
+	assert.Equal(t, updated, stored)
+}
+
+func TestValidateWebhook(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		Webhook       Webhook
+		ExpectedError string
+	}{
+		{
+			Name:    "valid",
+			Webhook: Webhook{URL: "https://example.com/hook", Events: []string{EventTunnelCreated, EventTunnelDeleted}},
+		},
+		{
+			Name:          "empty url",
+			Webhook:       Webhook{},
+			ExpectedError: "url must start with http:// or https://",
+		},
+		{
+			Name:          "unsupported scheme",
+			Webhook:       Webhook{URL: "ftp://example.com"},
+			ExpectedError: "url must start with http:// or https://",
+		},
+		{
+			Name:          "no host",
+			Webhook:       Webhook{URL: "https:///hook"},
+			ExpectedError: "url must contain a host",
+		},
+		{
+			Name:          "unknown event",
+			Webhook:       Webhook{URL: "https://example.com/hook", Events: []string{"client.deleted"}},
+			ExpectedError: `unknown event "client.deleted"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			err := tc.Webhook.Validate()
+			if tc.ExpectedError != "" {
+				assert.EqualError(t, err, tc.ExpectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	webhooksmigration "github.com/cloudradar-monitoring/rport/db/migration/webhooks"
+	"github.com/cloudradar-monitoring/rport/share/query"
+)
+
+type Provider interface {
+	Get(ctx context.Context, id string) (*Webhook, error)
+	GetAll(ctx context.Context) ([]*Webhook, error)
+	Save(ctx context.Context, webhook *Webhook) error
+	Delete(ctx context.Context, id string) error
+	SaveDelivery(ctx context.Context, delivery *Delivery) error
+	ListDeliveries(ctx context.Context, options *query.ListOptions) ([]*Delivery, error)
+	CountDeliveries(ctx context.Context, options *query.ListOptions) (int, error)
+	DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+	Close() error
+}
+
+type SqliteProvider struct {
+	db        *sqlx.DB
+	converter *query.SQLConverter
+}
+
+func NewSqliteProvider(dbPath string, dbOptions database.Options) (*SqliteProvider, error) {
+	db, err := database.Open("webhooks", dbPath, webhooksmigration.AssetNames(), webhooksmigration.Asset, dbOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhooks DB instance: %v", err)
+	}
+
+	return &SqliteProvider{
+		db:        db,
+		converter: query.NewSQLConverter(db.DriverName()),
+	}, nil
+}
+
+func (p *SqliteProvider) Get(ctx context.Context, id string) (*Webhook, error) {
+	webhook := &Webhook{}
+	err := p.db.GetContext(ctx, webhook, p.db.Rebind("SELECT * FROM webhooks WHERE id = ?"), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (p *SqliteProvider) GetAll(ctx context.Context) ([]*Webhook, error) {
+	webhooks := []*Webhook{}
+	err := p.db.SelectContext(ctx, &webhooks, "SELECT * FROM webhooks ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// Save creates the webhook or updates an existing one with the same id
+func (p *SqliteProvider) Save(ctx context.Context, webhook *Webhook) error {
+	_, err := p.db.NamedExecContext(ctx,
+		`INSERT INTO webhooks (
+			id,
+			url,
+			secret,
+			events,
+			description,
+			enabled,
+			created_by,
+			created_at
+		) VALUES (
+			:id,
+			:url,
+			:secret,
+			:events,
+			:description,
+			:enabled,
+			:created_by,
+			:created_at
+		) ON CONFLICT(id) DO UPDATE SET
+			url = :url,
+			secret = :secret,
+			events = :events,
+			description = :description,
+			enabled = :enabled`,
+		webhook,
+	)
+	return err
+}
+
+// Delete deletes the webhook along with its delivery log
+func (p *SqliteProvider) Delete(ctx context.Context, id string) error {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM deliveries WHERE webhook_id = ?"), id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind("DELETE FROM webhooks WHERE id = ?"), id); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (p *SqliteProvider) SaveDelivery(ctx context.Context, delivery *Delivery) error {
+	_, err := p.db.NamedExecContext(ctx,
+		`INSERT INTO deliveries (
+			id,
+			webhook_id,
+			event,
+			payload,
+			status,
+			attempts,
+			status_code,
+			error,
+			created_at,
+			updated_at
+		) VALUES (
+			:id,
+			:webhook_id,
+			:event,
+			:payload,
+			:status,
+			:attempts,
+			:status_code,
+			:error,
+			:created_at,
+			:updated_at
+		) ON CONFLICT(id) DO UPDATE SET
+			status = :status,
+			attempts = :attempts,
+			status_code = :status_code,
+			error = :error,
+			updated_at = :updated_at`,
+		delivery,
+	)
+	return err
+}
+
+func (p *SqliteProvider) ListDeliveries(ctx context.Context, options *query.ListOptions) ([]*Delivery, error) {
+	deliveries := []*Delivery{}
+	q, params := p.converter.ConvertListOptionsToQuery(options, "SELECT * FROM deliveries")
+	err := p.db.SelectContext(ctx, &deliveries, p.db.Rebind(q), params...)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (p *SqliteProvider) CountDeliveries(ctx context.Context, options *query.ListOptions) (int, error) {
+	var result int
+
+	countOptions := *options
+	countOptions.Pagination = nil
+	countOptions.Sorts = nil
+	q, params := p.converter.ConvertListOptionsToQuery(&countOptions, "SELECT COUNT(*) FROM deliveries")
+
+	err := p.db.GetContext(ctx, &result, p.db.Rebind(q), params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (p *SqliteProvider) DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := p.db.ExecContext(ctx, p.db.Rebind("DELETE FROM deliveries WHERE created_at < ?"), before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (p *SqliteProvider) Close() error {
+	return p.db.Close()
+}
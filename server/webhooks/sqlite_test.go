@@ -0,0 +1,100 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/share/query"
+	"github.com/cloudradar-monitoring/rport/share/test"
+	"github.com/cloudradar-monitoring/rport/share/types"
+)
+
+func TestSqliteProvider(t *testing.T) {
+	test.ForEachDatabase(t, func(t *testing.T, postgresDSN string) {
+		ctx := context.Background()
+		p, err := NewSqliteProvider(":memory:", database.Options{PostgresDSN: postgresDSN})
+		require.NoError(t, err)
+		defer p.Close()
+
+		createdAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		webhook := &Webhook{
+			ID:        "webhook-1",
+			URL:       "https://example.com/hook",
+			Secret:    "secret",
+			Events:    types.StringSlice{EventClientConnected},
+			Enabled:   true,
+			CreatedBy: "admin",
+			CreatedAt: createdAt,
+		}
+		require.NoError(t, p.Save(ctx, webhook))
+		require.NoError(t, p.Save(ctx, &Webhook{ID: "webhook-2", URL: "https://example.com/other", Events: types.StringSlice{}, CreatedAt: createdAt.Add(time.Minute)}))
+
+		webhook.URL = "https://example.com/changed"
+		webhook.Events = types.StringSlice{EventJobFinished, EventJobFailed}
+		webhook.Enabled = false
+		require.NoError(t, p.Save(ctx, webhook))
+
+		found, err := p.Get(ctx, "webhook-1")
+		require.NoError(t, err)
+		require.NotNil(t, found)
+		assert.Equal(t, "https://example.com/changed", found.URL)
+		assert.Equal(t, "secret", found.Secret)
+		assert.Equal(t, types.StringSlice{EventJobFinished, EventJobFailed}, found.Events)
+		assert.False(t, found.Enabled)
+		assert.True(t, createdAt.Equal(found.CreatedAt))
+
+		all, err := p.GetAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		assert.Equal(t, "webhook-1", all[0].ID)
+		assert.Equal(t, "webhook-2", all[1].ID)
+
+		delivery := &Delivery{
+			ID:        "delivery-1",
+			WebhookID: "webhook-1",
+			Event:     EventJobFinished,
+			Payload:   `{"id":"delivery-1"}`,
+			Status:    DeliveryStatusPending,
+			CreatedAt: createdAt,
+			UpdatedAt: createdAt,
+		}
+		require.NoError(t, p.SaveDelivery(ctx, delivery))
+		delivery.Status = DeliveryStatusFailed
+		delivery.Attempts = 2
+		delivery.StatusCode = 500
+		delivery.Error = "unexpected status code"
+		delivery.UpdatedAt = createdAt.Add(time.Minute)
+		require.NoError(t, p.SaveDelivery(ctx, delivery))
+		require.NoError(t, p.SaveDelivery(ctx, &Delivery{ID: "delivery-2", WebhookID: "webhook-2", Event: EventJobFailed, Status: DeliveryStatusDelivered, CreatedAt: createdAt.Add(time.Hour), UpdatedAt: createdAt.Add(time.Hour)}))
+
+		options := &query.ListOptions{Filters: []query.FilterOption{{Column: []string{"webhook_id"}, Values: []string{"webhook-1"}}}}
+		deliveries, err := p.ListDeliveries(ctx, options)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, DeliveryStatusFailed, deliveries[0].Status)
+		assert.Equal(t, 2, deliveries[0].Attempts)
+		assert.Equal(t, 500, deliveries[0].StatusCode)
+		assert.Equal(t, "unexpected status code", deliveries[0].Error)
+		assert.True(t, createdAt.Add(time.Minute).Equal(deliveries[0].UpdatedAt))
+		count, err := p.CountDeliveries(ctx, &query.ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		deleted, err := p.DeleteDeliveriesBefore(ctx, createdAt.Add(time.Minute))
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+
+		require.NoError(t, p.Delete(ctx, "webhook-2"))
+		found, err = p.Get(ctx, "webhook-2")
+		require.NoError(t, err)
+		assert.Nil(t, found)
+		count, err = p.CountDeliveries(ctx, &query.ListOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
@@ -0,0 +1,132 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/cloudradar-monitoring/rport/share/types"
+)
+
+const (
+	EventClientConnected    = "client.connected"
+	EventClientDisconnected = "client.disconnected"
+	EventTunnelCreated      = "tunnel.created"
+	EventTunnelDeleted      = "tunnel.deleted"
+	EventJobFinished        = "job.finished"
+	EventJobFailed          = "job.failed"
+	EventScheduleRun        = "schedule.run"
+	EventVaultUnlocked      = "vault.unlocked"
+	EventLoginFailed        = "login.failed"
+)
+
+var AllEvents = []string{
+	EventClientConnected,
+	EventClientDisconnected,
+	EventTunnelCreated,
+	EventTunnelDeleted,
+	EventJobFinished,
+	EventJobFailed,
+	EventScheduleRun,
+	EventVaultUnlocked,
+	EventLoginFailed,
+}
+
+// Webhook is a URL the server posts events to
+type Webhook struct {
+	ID  string `json:"id" db:"id"`
+	URL string `json:"url" db:"url"`
+	// Secret is the key of the HMAC signature of the payloads, it's never returned by the API
+	Secret string `json:"secret,omitempty" db:"secret"`
+	// Events the webhook is subscribed to, all events if empty
+	Events      types.StringSlice `json:"events" db:"events"`
+	Description string            `json:"description" db:"description"`
+	Enabled     bool              `json:"enabled" db:"enabled"`
+	CreatedBy   string            `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+}
+
+func (w *Webhook) Validate() error {
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("url must start with http:// or https://")
+	}
+	if u.Host == "" {
+		return errors.New("url must contain a host")
+	}
+
+	for _, e := range w.Events {
+		if !isKnownEvent(e) {
+			return fmt.Errorf("unknown event %q", e)
+		}
+	}
+
+	return nil
+}
+
+// Subscribed returns true if the webhook is enabled and subscribed to the event
+func (w *Webhook) Subscribed(event string) bool {
+	if !w.Enabled {
+		return false
+	}
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutSecret returns a copy of the webhook that is safe to be returned by the API or written to the audit log
+func (w *Webhook) WithoutSecret() *Webhook {
+	result := *w
+	result.Secret = ""
+	return &result
+}
+
+func isKnownEvent(event string) bool {
+	for _, e := range AllEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	DeliveryStatusFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is the log of posting an event to a webhook, it's updated after each attempt
+type Delivery struct {
+	ID        string         `json:"id" db:"id"`
+	WebhookID string         `json:"webhook_id" db:"webhook_id"`
+	Event     string         `json:"event" db:"event"`
+	Payload   string         `json:"payload" db:"payload"`
+	Status    DeliveryStatus `json:"status" db:"status"`
+	Attempts  int            `json:"attempts" db:"attempts"`
+	// StatusCode is the HTTP status code of the last attempt, 0 if no response was received
+	StatusCode int       `json:"status_code" db:"status_code"`
+	Error      string    `json:"error" db:"error"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Payload is the JSON body posted to the webhooks
+type Payload struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
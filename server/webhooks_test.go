@@ -0,0 +1,163 @@
+package chserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/db/database"
+	"github.com/cloudradar-monitoring/rport/server/api/users"
+	"github.com/cloudradar-monitoring/rport/server/chconfig"
+	"github.com/cloudradar-monitoring/rport/server/clients"
+	"github.com/cloudradar-monitoring/rport/server/clients/clienttunnel"
+	"github.com/cloudradar-monitoring/rport/server/webhooks"
+	"github.com/cloudradar-monitoring/rport/share/models"
+	"github.com/cloudradar-monitoring/rport/share/security"
+)
+
+// newTestWebhooksService returns a webhooks service with a webhook subscribed to all events, the bodies of the
+// delivered events are sent to the returned channel
+func newTestWebhooksService(t *testing.T) (*webhooks.Service, <-chan []byte) {
+	bodies := make(chan []byte, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		bodies <- body
+	}))
+	t.Cleanup(receiver.Close)
+
+	provider, err := webhooks.NewSqliteProvider(":memory:", database.Options{})
+	require.NoError(t, err)
+	service := webhooks.NewService(webhooks.Config{MaxAttempts: 1, RetryInterval: time.Second, Timeout: time.Second}, provider, testLog)
+	t.Cleanup(func() { service.Close() })
+	_, err = service.Create(context.Background(), &webhooks.Webhook{URL: receiver.URL, Enabled: true}, "admin")
+	require.NoError(t, err)
+
+	return service, bodies
+}
+
+type testWebhookPayload struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+func receiveWebhook(t *testing.T, bodies <-chan []byte) ([]byte, testWebhookPayload) {
+	payload := testWebhookPayload{}
+	select {
+	case body := <-bodies:
+		require.NoError(t, json.Unmarshal(body, &payload))
+		return body, payload
+	case <-time.After(time.Second):
+		require.FailNow(t, "event not delivered")
+		return nil, payload
+	}
+}
+
+func TestJobEventPayload(t *testing.T) {
+	service, bodies := newTestWebhooksService(t)
+
+	multiJobID := "multi-job-1"
+	finishedAt := time.Date(2022, 10, 16, 12, 0, 5, 0, time.UTC)
+	job := &models.Job{
+		JID:         "job-1",
+		MultiJobID:  &multiJobID,
+		ClientID:    "client-1",
+		Status:      models.JobStatusFailed,
+		CreatedBy:   "admin",
+		StartedAt:   time.Date(2022, 10, 16, 12, 0, 0, 0, time.UTC),
+		FinishedAt:  &finishedAt,
+		Command:     "mysql -p secret",
+		Cwd:         "/root",
+		Interpreter: "/bin/bash",
+		Error:       "exit status 1",
+		Result: &models.JobResult{
+			StdOut: "some output",
+			StdErr: "access denied",
+		},
+	}
+
+	service.Dispatch(jobEvent(job))
+
+	body, payload := receiveWebhook(t, bodies)
+	assert.Equal(t, webhooks.EventJobFailed, payload.Event)
+	assert.JSONEq(t, `{
+		"jid": "job-1",
+		"multi_job_id": "multi-job-1",
+		"client_id": "client-1",
+		"status": "failed",
+		"created_by": "admin",
+		"started_at": "2022-10-16T12:00:00Z",
+		"finished_at": "2022-10-16T12:00:05Z",
+		"error": "exit status 1"
+	}`, string(payload.Data))
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(payload.Data, &fields))
+	for _, field := range []string{"command", "cwd", "interpreter", "result"} {
+		assert.NotContains(t, fields, field)
+	}
+	assert.NotContains(t, string(body), "mysql -p secret")
+	assert.NotContains(t, string(body), "access denied")
+}
+
+func TestTunnelDeletedEventOfClosedTunnels(t *testing.T) {
+	service, bodies := newTestWebhooksService(t)
+	client := &clients.Client{ID: "client-1"}
+	cs := &ClientServiceProvider{
+		repo:     clients.NewClientRepository([]*clients.Client{client}, nil, testLog),
+		logger:   testLog,
+		webhooks: service,
+	}
+	tunnel := &clienttunnel.Tunnel{
+		ID:     "1",
+		Remote: models.Remote{Protocol: models.ProtocolTCP, LocalPort: "2000", RemotePort: "22"},
+	}
+
+	cs.TunnelClosed(client, tunnel, clienttunnel.CloseReasonIdleTimeout)
+
+	_, payload := receiveWebhook(t, bodies)
+	assert.Equal(t, webhooks.EventTunnelDeleted, payload.Event)
+	assert.Contains(t, string(payload.Data), `"tunnel_id":"1"`)
+	assert.Contains(t, string(payload.Data), `"reason":"idle_timeout"`)
+
+	client.Tunnels = []*clienttunnel.Tunnel{tunnel}
+	require.NoError(t, cs.Terminate(client))
+
+	_, payload = receiveWebhook(t, bodies)
+	assert.Equal(t, webhooks.EventTunnelDeleted, payload.Event)
+	assert.Contains(t, string(payload.Data), `"tunnel_id":"1"`)
+	assert.Contains(t, string(payload.Data), `"reason":"client_disconnected"`)
+}
+
+func TestLoginFailedEventOfBasicAuth(t *testing.T) {
+	service, bodies := newTestWebhooksService(t)
+	al := APIListener{
+		apiSessions: newEmptyAPISessionCache(t),
+		bannedUsers: security.NewBanList(0),
+		userService: users.NewAPIService(users.NewStaticProvider([]*users.User{{
+			Username: "user1",
+			Password: "$2y$05$ep2DdPDeLDDhwRrED9q/vuVEzRpZtB5WHCFT7YbcmH9r9oNmlsZOm",
+		}}), false, 0, -1),
+		Server: &Server{
+			config:          &chconfig.Config{},
+			webhooksService: service,
+		},
+	}
+	handler := al.wrapWithAuthMiddleware(false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/some-endpoint", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.SetBasicAuth("user1", "wrong")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+
+	_, payload := receiveWebhook(t, bodies)
+	assert.Equal(t, webhooks.EventLoginFailed, payload.Event)
+	assert.JSONEq(t, `{"username":"user1","remote_ip":"192.0.2.1"}`, string(payload.Data))
+}
@@ -1,6 +1,7 @@
 package clientconfig
 
 import (
+	"crypto/ed25519"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -16,10 +17,15 @@ type Config struct {
 	Logging             LogConfig           `json:"logging" mapstructure:"logging"`
 	RemoteCommands      CommandsConfig      `json:"remote_commands" mapstructure:"remote-commands"`
 	RemoteScripts       ScriptsConfig       `json:"remote_scripts" mapstructure:"remote-scripts"`
+	RemoteTerminal      TerminalConfig      `json:"remote_terminal" mapstructure:"remote-terminal"`
 	Monitoring          MonitoringConfig    `json:"monitoring" mapstructure:"monitoring"`
 	Tunnels             TunnelsConfig       `json:"-"`
 	InterpreterAliases  map[string]string   `json:"interpreter_aliases" mapstructure:"interpreter-aliases"`
 	FileReceptionConfig FileReceptionConfig `json:"file_reception" mapstructure:"file-reception"`
+	FileBrowser         FileBrowserConfig   `json:"file_browser" mapstructure:"file-browser"`
+	SelfUpdate          SelfUpdateConfig    `json:"self_update" mapstructure:"self-update"`
+	ManagedConfig       ManagedConfig       `json:"managed_config" mapstructure:"managed-config"`
+	UserPolicy          UserPolicyConfig    `json:"user_policy" mapstructure:"user-policy"`
 }
 
 type ClientConfig struct {
@@ -38,6 +44,8 @@ type ClientConfig struct {
 	TunnelAllowed            []string      `json:"tunnel_allowed" mapstructure:"tunnel_allowed"`
 	AllowRoot                bool          `json:"allow_root" mapstructure:"allow_root"`
 	UpdatesInterval          time.Duration `json:"updates_interval" mapstructure:"updates_interval"`
+	AllowUpdatesInstallation bool          `json:"allow_updates_installation" mapstructure:"allow_updates_installation"`
+	InventoryInterval        time.Duration `json:"inventory_interval" mapstructure:"inventory_interval"`
 	DataDir                  string        `json:"data_dir" mapstructure:"data_dir"`
 	BindInterface            string        `json:"bind_interface" mapstructure:"bind_interface"`
 
@@ -76,6 +84,9 @@ type CommandsConfig struct {
 	Allow         []string  `json:"allow" mapstructure:"allow"`
 	Deny          []string  `json:"deny" mapstructure:"deny"`
 	Order         [2]string `json:"order" mapstructure:"order"`
+	// CancelSignals are sent one by one to a cancelled job until it exits, waiting CancelSignalsInterval in between
+	CancelSignals         []string      `json:"cancel_signals" mapstructure:"cancel_signals"`
+	CancelSignalsInterval time.Duration `json:"cancel_signals_interval" mapstructure:"cancel_signals_interval"`
 
 	AllowRegexp []*regexp.Regexp `json:"allow_regexp"`
 	DenyRegexp  []*regexp.Regexp `json:"deny_regexp"`
@@ -85,6 +96,11 @@ type ScriptsConfig struct {
 	Enabled bool `json:"enabled" mapstructure:"enabled"`
 }
 
+type TerminalConfig struct {
+	Enabled bool   `json:"enabled" mapstructure:"enabled"`
+	Shell   string `json:"shell" mapstructure:"shell"`
+}
+
 type MonitoringConfig struct {
 	Enabled                       bool          `json:"enabled" mapstructure:"enabled"`
 	Interval                      time.Duration `json:"interval" mapstructure:"interval"`
@@ -97,12 +113,159 @@ type MonitoringConfig struct {
 	PMMaxNumberProcesses          uint          `json:"pm_max_number_processes" mapstructure:"pm_max_number_processes"`
 	NetLan                        []string      `json:"net_lan" mapstructure:"net_lan"`
 	NetWan                        []string      `json:"net_wan" mapstructure:"net_wan"`
+	Checks                        []CheckConfig `json:"checks" mapstructure:"checks"`
 
 	LanCard *models.NetworkCard `json:"lan_card"`
 	WanCard *models.NetworkCard `json:"wan_card"`
 }
 
+const (
+	CheckTypeValue  = "value"
+	CheckTypeNagios = "nagios"
+)
+
+// CheckConfig describes a custom check executed periodically by the monitoring
+type CheckConfig struct {
+	Name        string        `json:"name" mapstructure:"name"`
+	Command     string        `json:"command" mapstructure:"command"`
+	Interpreter string        `json:"interpreter" mapstructure:"interpreter"`
+	Type        string        `json:"type" mapstructure:"type"`
+	Interval    time.Duration `json:"interval" mapstructure:"interval"`
+	Timeout     time.Duration `json:"timeout" mapstructure:"timeout"`
+}
+
 type FileReceptionConfig struct {
 	Protected []string `json:"protected" mapstructure:"protected"`
 	Enabled   bool     `json:"enabled" mapstructure:"enabled"`
 }
+
+type FileBrowserConfig struct {
+	Enabled bool     `json:"enabled" mapstructure:"enabled"`
+	Allow   []string `json:"allow" mapstructure:"allow"`
+	Deny    []string `json:"deny" mapstructure:"deny"`
+}
+
+type SelfUpdateConfig struct {
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+	// PublicKeyFile is a PEM file with the ed25519 public key the client binaries are signed with
+	PublicKeyFile string `json:"public_key_file" mapstructure:"public_key_file"`
+	// RollbackTimeout is the time an updated client has to connect to the server, otherwise the update is rolled back
+	RollbackTimeout time.Duration `json:"rollback_timeout" mapstructure:"rollback_timeout"`
+
+	PublicKey ed25519.PublicKey `json:"-"`
+}
+
+type ManagedConfig struct {
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+	// Locked are options or whole sections keeping their local value, e.g. "remote-commands.allow" or "monitoring"
+	Locked []string `json:"locked" mapstructure:"locked"`
+
+	// Profiles are the ids of the configuration profiles pushed by the server
+	Profiles []string `json:"profiles"`
+	// Applied are the keys of the options set by the configuration profiles
+	Applied []string `json:"applied"`
+}
+
+// UserPolicyConfig limits the users of the server API that are allowed to run commands, upload files, create
+// tunnels, open terminals and browse files on the client. Deny takes precedence over allow.
+type UserPolicyConfig struct {
+	AllowUsers  []string `json:"allow_users" mapstructure:"allow_users"`
+	DenyUsers   []string `json:"deny_users" mapstructure:"deny_users"`
+	AllowGroups []string `json:"allow_groups" mapstructure:"allow_groups"`
+	DenyGroups  []string `json:"deny_groups" mapstructure:"deny_groups"`
+}
+
+// Clone returns a deep copy of the config, changes to the copy don't affect the original
+func (c *Config) Clone() *Config {
+	clone := *c
+
+	clone.Client.FallbackServers = cloneSlice(c.Client.FallbackServers)
+	clone.Client.Tags = cloneSlice(c.Client.Tags)
+	clone.Client.Remotes = cloneSlice(c.Client.Remotes)
+	clone.Client.TunnelAllowed = cloneSlice(c.Client.TunnelAllowed)
+	if c.Client.ProxyURL != nil {
+		proxyURL := *c.Client.ProxyURL
+		if c.Client.ProxyURL.User != nil {
+			userinfo := *c.Client.ProxyURL.User
+			proxyURL.User = &userinfo
+		}
+		clone.Client.ProxyURL = &proxyURL
+	}
+	if c.Client.Tunnels != nil {
+		clone.Client.Tunnels = make([]*models.Remote, len(c.Client.Tunnels))
+		for i, tunnel := range c.Client.Tunnels {
+			clone.Client.Tunnels[i] = cloneRemote(tunnel)
+		}
+	}
+
+	clone.Connection.HeadersRaw = cloneSlice(c.Connection.HeadersRaw)
+	clone.Connection.HTTPHeaders = c.Connection.HTTPHeaders.Clone()
+
+	clone.RemoteCommands.Allow = cloneSlice(c.RemoteCommands.Allow)
+	clone.RemoteCommands.Deny = cloneSlice(c.RemoteCommands.Deny)
+	clone.RemoteCommands.CancelSignals = cloneSlice(c.RemoteCommands.CancelSignals)
+	// compiled regexps are safe for concurrent use, only the slices are copied
+	clone.RemoteCommands.AllowRegexp = cloneSlice(c.RemoteCommands.AllowRegexp)
+	clone.RemoteCommands.DenyRegexp = cloneSlice(c.RemoteCommands.DenyRegexp)
+
+	clone.Monitoring.FSTypeInclude = cloneSlice(c.Monitoring.FSTypeInclude)
+	clone.Monitoring.FSPathExclude = cloneSlice(c.Monitoring.FSPathExclude)
+	clone.Monitoring.NetLan = cloneSlice(c.Monitoring.NetLan)
+	clone.Monitoring.NetWan = cloneSlice(c.Monitoring.NetWan)
+	clone.Monitoring.Checks = cloneSlice(c.Monitoring.Checks)
+	if c.Monitoring.LanCard != nil {
+		lanCard := *c.Monitoring.LanCard
+		clone.Monitoring.LanCard = &lanCard
+	}
+	if c.Monitoring.WanCard != nil {
+		wanCard := *c.Monitoring.WanCard
+		clone.Monitoring.WanCard = &wanCard
+	}
+
+	if c.InterpreterAliases != nil {
+		clone.InterpreterAliases = make(map[string]string, len(c.InterpreterAliases))
+		for alias, interpreter := range c.InterpreterAliases {
+			clone.InterpreterAliases[alias] = interpreter
+		}
+	}
+
+	clone.FileReceptionConfig.Protected = cloneSlice(c.FileReceptionConfig.Protected)
+	clone.FileBrowser.Allow = cloneSlice(c.FileBrowser.Allow)
+	clone.FileBrowser.Deny = cloneSlice(c.FileBrowser.Deny)
+	clone.SelfUpdate.PublicKey = cloneSlice(c.SelfUpdate.PublicKey)
+
+	clone.ManagedConfig.Locked = cloneSlice(c.ManagedConfig.Locked)
+	clone.ManagedConfig.Profiles = cloneSlice(c.ManagedConfig.Profiles)
+	clone.ManagedConfig.Applied = cloneSlice(c.ManagedConfig.Applied)
+
+	clone.UserPolicy.AllowUsers = cloneSlice(c.UserPolicy.AllowUsers)
+	clone.UserPolicy.DenyUsers = cloneSlice(c.UserPolicy.DenyUsers)
+	clone.UserPolicy.AllowGroups = cloneSlice(c.UserPolicy.AllowGroups)
+	clone.UserPolicy.DenyGroups = cloneSlice(c.UserPolicy.DenyGroups)
+
+	return &clone
+}
+
+// cloneSlice returns a copy of s, nil stays nil
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
+func cloneRemote(r *models.Remote) *models.Remote {
+	if r == nil {
+		return nil
+	}
+	clone := *r
+	if r.Scheme != nil {
+		scheme := *r.Scheme
+		clone.Scheme = &scheme
+	}
+	if r.ACL != nil {
+		acl := *r.ACL
+		clone.ACL = &acl
+	}
+	return &clone
+}
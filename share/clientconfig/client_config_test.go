@@ -0,0 +1,53 @@
+package clientconfig
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudradar-monitoring/rport/share/models"
+)
+
+func TestConfigClone(t *testing.T) {
+	scheme := "http"
+	cfg := &Config{}
+	cfg.Client.Tags = []string{"local"}
+	cfg.Client.ProxyURL = &url.URL{Scheme: "http", Host: "proxy:3128", User: url.UserPassword("user", "pass")}
+	cfg.Client.Tunnels = []*models.Remote{{RemotePort: "80", Scheme: &scheme}}
+	cfg.Connection.HTTPHeaders = http.Header{"X-Test": []string{"a"}}
+	cfg.RemoteCommands.Allow = []string{".*"}
+	cfg.RemoteCommands.Order = [2]string{"allow", "deny"}
+	cfg.Monitoring.Checks = []CheckConfig{{Name: "check"}}
+	cfg.Monitoring.LanCard = &models.NetworkCard{Name: "eth0"}
+	cfg.InterpreterAliases = map[string]string{"pwsh": "powershell"}
+	cfg.UserPolicy.AllowGroups = []string{"ops"}
+
+	clone := cfg.Clone()
+	assert.Equal(t, cfg, clone)
+
+	clone.Client.Tags[0] = "changed"
+	clone.Client.ProxyURL.Host = "changed"
+	clone.Client.Tunnels[0].RemotePort = "changed"
+	*clone.Client.Tunnels[0].Scheme = "changed"
+	clone.Connection.HTTPHeaders.Set("X-Test", "changed")
+	clone.RemoteCommands.Allow[0] = "changed"
+	clone.RemoteCommands.Order[0] = "changed"
+	clone.Monitoring.Checks[0].Name = "changed"
+	clone.Monitoring.LanCard.Name = "changed"
+	clone.InterpreterAliases["pwsh"] = "changed"
+	clone.UserPolicy.AllowGroups[0] = "changed"
+
+	assert.Equal(t, []string{"local"}, cfg.Client.Tags)
+	assert.Equal(t, "proxy:3128", cfg.Client.ProxyURL.Host)
+	assert.Equal(t, "80", cfg.Client.Tunnels[0].RemotePort)
+	assert.Equal(t, "http", *cfg.Client.Tunnels[0].Scheme)
+	assert.Equal(t, "a", cfg.Connection.HTTPHeaders.Get("X-Test"))
+	assert.Equal(t, []string{".*"}, cfg.RemoteCommands.Allow)
+	assert.Equal(t, [2]string{"allow", "deny"}, cfg.RemoteCommands.Order)
+	assert.Equal(t, "check", cfg.Monitoring.Checks[0].Name)
+	assert.Equal(t, "eth0", cfg.Monitoring.LanCard.Name)
+	assert.Equal(t, "powershell", cfg.InterpreterAliases["pwsh"])
+	assert.Equal(t, []string{"ops"}, cfg.UserPolicy.AllowGroups)
+}
@@ -0,0 +1,200 @@
+package clientconfig
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ManagedOptions are client options set by the server. Keys are the options of rport.conf prefixed by their section,
+// e.g. "remote-commands.allow". Values are decoded from JSON, durations are given as strings like "5m".
+type ManagedOptions map[string]interface{}
+
+// managedOptionFields maps the options that can be managed by the server to a func returning a pointer to their field
+var managedOptionFields = map[string]func(c *Config) interface{}{
+	"client.tags":                func(c *Config) interface{} { return &c.Client.Tags },
+	"client.tunnel_allowed":      func(c *Config) interface{} { return &c.Client.TunnelAllowed },
+	"remote-commands.enabled":    func(c *Config) interface{} { return &c.RemoteCommands.Enabled },
+	"remote-commands.allow":      func(c *Config) interface{} { return &c.RemoteCommands.Allow },
+	"remote-commands.deny":       func(c *Config) interface{} { return &c.RemoteCommands.Deny },
+	"remote-commands.order":      func(c *Config) interface{} { return &c.RemoteCommands.Order },
+	"remote-scripts.enabled":     func(c *Config) interface{} { return &c.RemoteScripts.Enabled },
+	"remote-terminal.enabled":    func(c *Config) interface{} { return &c.RemoteTerminal.Enabled },
+	"monitoring.enabled":         func(c *Config) interface{} { return &c.Monitoring.Enabled },
+	"monitoring.interval":        func(c *Config) interface{} { return &c.Monitoring.Interval },
+	"monitoring.fs_type_include": func(c *Config) interface{} { return &c.Monitoring.FSTypeInclude },
+	"monitoring.fs_path_exclude": func(c *Config) interface{} { return &c.Monitoring.FSPathExclude },
+	"monitoring.pm_enabled":      func(c *Config) interface{} { return &c.Monitoring.PMEnabled },
+	"file-reception.enabled":     func(c *Config) interface{} { return &c.FileReceptionConfig.Enabled },
+	"file-reception.protected":   func(c *Config) interface{} { return &c.FileReceptionConfig.Protected },
+	"file-browser.enabled":       func(c *Config) interface{} { return &c.FileBrowser.Enabled },
+}
+
+// ManagedOptionKeys returns the sorted keys of all options that can be managed by the server
+func ManagedOptionKeys() []string {
+	keys := make([]string, 0, len(managedOptionFields))
+	for key := range managedOptionFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// IsManagedOptionKey returns true if key is an option that can be managed by the server or a section of one
+func IsManagedOptionKey(key string) bool {
+	for option := range managedOptionFields {
+		if matchesOptionKey(option, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesOptionKey returns true if key is the option itself or its section
+func matchesOptionKey(option, key string) bool {
+	return option == key || strings.HasPrefix(option, key+".")
+}
+
+// Validate checks that all options can be managed and their values have the expected type
+func (o ManagedOptions) Validate() error {
+	_, err := o.ApplyTo(&Config{}, nil)
+	return err
+}
+
+// ApplyTo overrides the options of the given config, except the locked ones. A locked key is an option or a whole
+// section. It returns the sorted keys of the options that were applied.
+func (o ManagedOptions) ApplyTo(c *Config, locked []string) ([]string, error) {
+	keys := make([]string, 0, len(o))
+	for key := range o {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	applied := make([]string, 0, len(keys))
+	for _, key := range keys {
+		field, ok := managedOptionFields[key]
+		if !ok {
+			return nil, fmt.Errorf("option %q can not be managed", key)
+		}
+		if isLocked(key, locked) {
+			continue
+		}
+		if err := setOption(field(c), o[key]); err != nil {
+			return nil, fmt.Errorf("option %q: %v", key, err)
+		}
+		applied = append(applied, key)
+	}
+
+	return applied, nil
+}
+
+func isLocked(key string, locked []string) bool {
+	for _, l := range locked {
+		if matchesOptionKey(key, l) {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeManagedOptions returns the options of all given ones, options of later ones override earlier ones
+func MergeManagedOptions(options ...ManagedOptions) ManagedOptions {
+	merged := ManagedOptions{}
+	for _, o := range options {
+		for key, value := range o {
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+func setOption(field interface{}, value interface{}) error {
+	switch f := field.(type) {
+	case *bool:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected a boolean, got %T", value)
+		}
+		*f = v
+	case *time.Duration:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a duration string, got %T", value)
+		}
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*f = v
+	case *[]string:
+		v, err := toStrings(value)
+		if err != nil {
+			return err
+		}
+		*f = v
+	case *[2]string:
+		v, err := toStrings(value)
+		if err != nil {
+			return err
+		}
+		if len(v) != 2 {
+			return fmt.Errorf("expected a list of 2 strings, got %d", len(v))
+		}
+		copy(f[:], v)
+	default:
+		return fmt.Errorf("unsupported option type %T", field)
+	}
+	return nil
+}
+
+func toStrings(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case []string:
+		return v, nil
+	case []interface{}:
+		res := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a list of strings, got %T item", item)
+			}
+			res = append(res, s)
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("expected a list of strings, got %T", value)
+	}
+}
+
+func (o *ManagedOptions) Scan(value interface{}) error {
+	if o == nil {
+		return fmt.Errorf("'ManagedOptions' cannot be nil")
+	}
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("expected to have string, got %T", value)
+	}
+	if err := json.Unmarshal(data, o); err != nil {
+		return fmt.Errorf("failed to decode managed options: %v", err)
+	}
+	return nil
+}
+
+func (o ManagedOptions) Value() (driver.Value, error) {
+	if o == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(o)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode managed options: %v", err)
+	}
+	return string(b), nil
+}
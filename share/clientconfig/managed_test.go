@@ -0,0 +1,99 @@
+package clientconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagedOptionsApplyTo(t *testing.T) {
+	cfg := &Config{}
+	cfg.Client.Tags = []string{"local"}
+	cfg.RemoteCommands.Enabled = true
+
+	applied, err := ManagedOptions{
+		"client.tags":             []interface{}{"a", "b"},
+		"remote-commands.enabled": false,
+		"remote-commands.order":   []interface{}{"deny", "allow"},
+		"monitoring.interval":     "5m",
+	}.ApplyTo(cfg, []string{"remote-commands.enabled"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"client.tags", "monitoring.interval", "remote-commands.order"}, applied)
+	assert.Equal(t, []string{"a", "b"}, cfg.Client.Tags)
+	assert.True(t, cfg.RemoteCommands.Enabled)
+	assert.Equal(t, [2]string{"deny", "allow"}, cfg.RemoteCommands.Order)
+	assert.Equal(t, 5*time.Minute, cfg.Monitoring.Interval)
+}
+
+func TestManagedOptionsValidate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		options     ManagedOptions
+		expectedErr string
+	}{
+		{
+			name: "valid",
+			options: ManagedOptions{
+				"file-reception.protected": []interface{}{"/etc/*"},
+				"file-browser.enabled":     true,
+			},
+		},
+		{
+			name:        "unknown option",
+			options:     ManagedOptions{"client.server": "example.com"},
+			expectedErr: `option "client.server" can not be managed`,
+		},
+		{
+			name:        "invalid bool",
+			options:     ManagedOptions{"monitoring.enabled": "yes"},
+			expectedErr: `option "monitoring.enabled": expected a boolean, got string`,
+		},
+		{
+			name:        "invalid list",
+			options:     ManagedOptions{"client.tags": []interface{}{"a", 1.0}},
+			expectedErr: `option "client.tags": expected a list of strings, got float64 item`,
+		},
+		{
+			name:        "invalid order",
+			options:     ManagedOptions{"remote-commands.order": []interface{}{"allow"}},
+			expectedErr: `option "remote-commands.order": expected a list of 2 strings, got 1`,
+		},
+		{
+			name:        "invalid duration",
+			options:     ManagedOptions{"monitoring.interval": 60.0},
+			expectedErr: `option "monitoring.interval": expected a duration string, got float64`,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.options.Validate()
+
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func TestMergeManagedOptions(t *testing.T) {
+	merged := MergeManagedOptions(
+		ManagedOptions{"client.tags": []interface{}{"a"}, "monitoring.enabled": true},
+		nil,
+		ManagedOptions{"client.tags": []interface{}{"b"}},
+	)
+
+	assert.Equal(t, ManagedOptions{"client.tags": []interface{}{"b"}, "monitoring.enabled": true}, merged)
+}
+
+func TestIsManagedOptionKey(t *testing.T) {
+	assert.True(t, IsManagedOptionKey("remote-commands.allow"))
+	assert.True(t, IsManagedOptionKey("remote-commands"))
+	assert.False(t, IsManagedOptionKey("remote-command"))
+	assert.False(t, IsManagedOptionKey("client.server"))
+}
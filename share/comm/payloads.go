@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/cloudradar-monitoring/rport/share/clientconfig"
 )
 
 const (
@@ -13,15 +15,28 @@ const (
 	RequestTypeRefreshUpdatesStatus = "refresh_updates_status"
 	RequestTypePutCapabilities      = "put_capabilities"
 	RequestTypeCheckTunnelAllowed   = "check_tunnel_allowed"
+	RequestTypeInstallUpdates       = "install_updates"
+	RequestTypeCancelJob            = "cancel_job"
+	RequestTypeSelfUpdate           = "self_update"
+	RequestTypePutManagedConfig     = "put_managed_config"
 
 	// request types sent by clients to server
 	RequestTypeCmdResult       = "cmd_result"
 	RequestTypeUpdatesStatus   = "updates_status"
 	RequestTypeSaveMeasurement = "save_measurement"
+	RequestTypeInventory       = "inventory"
 	RequestTypeUpload          = "upload"
 
 	// request types understood on both sides, client and server
 	RequestTypePing = "ping"
+
+	// channel types opened by server on clients
+	ChannelTypeTerminal = "terminal"
+	ChannelTypeSFTP     = "sftp"
+
+	// request types sent on a terminal channel, resize by server and exit status by client
+	RequestTypeTerminalResize = "terminal_resize"
+	RequestTypeTerminalExit   = "terminal_exit"
 )
 
 type CheckPortRequest struct {
@@ -47,10 +62,70 @@ type RunCmdResponse struct {
 	StartedAt time.Time
 }
 
+// CancelJobRequest asks a client to terminate the process of a running job
+type CancelJobRequest struct {
+	JID string
+}
+
+// SelfUpdateRequest asks a client to replace its binary by the given one, hosted on the server and fetched via sftp
+type SelfUpdateRequest struct {
+	Version   string
+	OS        string
+	Arch      string
+	Path      string
+	Size      int64
+	SHA256    string
+	Signature []byte
+}
+
+// Manifest returns the data covered by the signature of the binary. It includes the version and the platform,
+// so a signed binary can't be installed as another version or on another platform.
+func (r *SelfUpdateRequest) Manifest() []byte {
+	return []byte(fmt.Sprintf("version=%s\nos=%s\narch=%s\nsha256=%s\n", r.Version, r.OS, r.Arch, r.SHA256))
+}
+
+// ManagedConfigRequest holds the merged options of the configuration profiles assigned to a client.
+// The client replies with its effective configuration.
+type ManagedConfigRequest struct {
+	Profiles []string
+	Options  clientconfig.ManagedOptions
+}
+
 type CheckTunnelAllowedRequest struct {
 	Remote string
+	// User and UserGroups identify the user creating the tunnel, the client applies its user policy to them
+	User       string
+	UserGroups []string
 }
 
 type CheckTunnelAllowedResponse struct {
 	IsAllowed bool
 }
+
+// TerminalRequest is sent as extra data when opening a terminal channel.
+type TerminalRequest struct {
+	Cols uint16
+	Rows uint16
+	// Term is the value of the TERM env var, optional
+	Term string
+	// User and UserGroups identify the user starting the session, the client applies its user policy to them
+	User       string
+	UserGroups []string
+}
+
+// SFTPRequest is sent as extra data when opening an sftp channel.
+type SFTPRequest struct {
+	// User and UserGroups identify the user browsing the files, the client applies its user policy to them
+	User       string
+	UserGroups []string
+}
+
+type TerminalResizeRequest struct {
+	Cols uint16
+	Rows uint16
+}
+
+type TerminalExitRequest struct {
+	ExitCode int
+	ErrMsg   string
+}
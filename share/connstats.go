@@ -6,8 +6,18 @@ import (
 )
 
 type ConnStats struct {
-	count int32
-	open  int32
+	sent     atomic.Int64
+	received atomic.Int64
+	count    int32
+	open     int32
+}
+
+// ConnStatsSnapshot is a point in time copy of ConnStats counters.
+type ConnStatsSnapshot struct {
+	Open          int32
+	Total         int32
+	BytesSent     int64
+	BytesReceived int64
 }
 
 func (c *ConnStats) New() int32 {
@@ -22,6 +32,30 @@ func (c *ConnStats) Close() {
 	atomic.AddInt32(&c.open, -1)
 }
 
+// AddBytes adds the number of bytes transferred by a connection.
+func (c *ConnStats) AddBytes(sent, received int64) {
+	c.sent.Add(sent)
+	c.received.Add(received)
+}
+
+func (c *ConnStats) Snapshot() ConnStatsSnapshot {
+	return ConnStatsSnapshot{
+		Open:          atomic.LoadInt32(&c.open),
+		Total:         atomic.LoadInt32(&c.count),
+		BytesSent:     c.sent.Load(),
+		BytesReceived: c.received.Load(),
+	}
+}
+
 func (c *ConnStats) String() string {
 	return fmt.Sprintf("[%d/%d]", atomic.LoadInt32(&c.open), atomic.LoadInt32(&c.count))
 }
+
+func (s ConnStatsSnapshot) Add(other ConnStatsSnapshot) ConnStatsSnapshot {
+	return ConnStatsSnapshot{
+		Open:          s.Open + other.Open,
+		Total:         s.Total + other.Total,
+		BytesSent:     s.BytesSent + other.BytesSent,
+		BytesReceived: s.BytesReceived + other.BytesReceived,
+	}
+}
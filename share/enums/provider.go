@@ -6,5 +6,6 @@ const (
 	ProviderSourceStatic ProviderSource = "Static Credentials"
 	ProviderSourceFile   ProviderSource = "File"
 	ProviderSourceDB     ProviderSource = "DB"
+	ProviderSourceLDAP   ProviderSource = "LDAP"
 	ProviderSourceMock   ProviderSource = "Mock"
 )
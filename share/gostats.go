@@ -11,6 +11,28 @@ import (
 	"github.com/jpillora/sizestr"
 )
 
+// RuntimeStats holds basic statistics of the go runtime
+type RuntimeStats struct {
+	Goroutines     int
+	HeapAllocBytes uint64
+	SysBytes       uint64
+	NumGC          uint32
+	GCPauseTotal   time.Duration
+}
+
+// ReadRuntimeStats returns current statistics of the go runtime
+func ReadRuntimeStats() RuntimeStats {
+	memStats := runtime.MemStats{}
+	runtime.ReadMemStats(&memStats)
+	return RuntimeStats{
+		Goroutines:     runtime.NumGoroutine(),
+		HeapAllocBytes: memStats.Alloc,
+		SysBytes:       memStats.Sys,
+		NumGC:          memStats.NumGC,
+		GCPauseTotal:   time.Duration(memStats.PauseTotalNs),
+	}
+}
+
 // GoStats prints statistics to
 // stdout on SIGUSR2 (posix-only)
 func GoStats() {
@@ -20,10 +42,9 @@ func GoStats() {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, SIGUSR2)
 	for range c {
-		memStats := runtime.MemStats{}
-		runtime.ReadMemStats(&memStats)
+		stats := ReadRuntimeStats()
 		log.Printf("received SIGUSR2, go-routines: %d, go-memory-usage: %s",
-			runtime.NumGoroutine(),
-			sizestr.ToString(int64(memStats.Alloc)))
+			stats.Goroutines,
+			sizestr.ToString(int64(stats.HeapAllocBytes)))
 	}
 }
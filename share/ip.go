@@ -29,6 +29,16 @@ func RemoteIP(r *http.Request) string {
 	return ips[0]
 }
 
+// PeerIP returns the IP of the peer of the connection. Unlike RemoteIP it ignores the X-Forwarded-For header, which
+// can be set by anyone, so it's used where the IP is part of the authorization.
+func PeerIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
 func firstValidIP(ips []string, allowPrivate bool) (string, bool) {
 	for _, ipStr := range ips {
 		ip := net.ParseIP(strings.TrimSpace(ipStr))
@@ -55,3 +55,15 @@ func TestRemoteIP(t *testing.T) {
 		})
 	}
 }
+
+func TestPeerIP(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "192.168.0.13:1234"
+	req.Header.Set("X-Forwarded-For", "8.8.8.8")
+
+	assert.Equal(t, "192.168.0.13", PeerIP(req))
+
+	req.RemoteAddr = "192.168.0.13"
+
+	assert.Equal(t, "192.168.0.13", PeerIP(req))
+}
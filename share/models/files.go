@@ -34,6 +34,9 @@ type UploadedFile struct {
 	ForceWrite           bool
 	Sync                 bool
 	Md5Checksum          []byte
+	// CreatedBy and CreatedByGroups identify the user uploading the file, the client applies its user policy to them
+	CreatedBy       string   `json:",omitempty"`
+	CreatedByGroups []string `json:",omitempty"`
 }
 
 func (uf UploadedFile) Validate() error {
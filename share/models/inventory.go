@@ -0,0 +1,123 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	InventoryCategoryPackage  = "package"
+	InventoryCategoryService  = "service"
+	InventoryCategoryDisk     = "disk"
+	InventoryCategoryNIC      = "nic"
+	InventoryCategoryUser     = "user"
+	InventoryCategoryHardware = "hardware"
+)
+
+// Inventory holds the installed software and the hardware of a client, it's collected periodically by the client
+type Inventory struct {
+	Refreshed time.Time          `json:"refreshed"`
+	Packages  []InventoryPackage `json:"packages"`
+	Services  []InventoryService `json:"services"`
+	Disks     []InventoryDisk    `json:"disks"`
+	NICs      []InventoryNIC     `json:"nics"`
+	Hardware  InventoryHardware  `json:"hardware"`
+	Users     []InventoryUser    `json:"users"`
+	// Errors are the errors of the categories that failed to be collected, prefixed with the category, e.g. "package: ..".
+	// The inventory is sent anyway.
+	Errors []string `json:"errors,omitempty"`
+}
+
+type InventoryPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type InventoryService struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+type InventoryDisk struct {
+	Device     string `json:"device"`
+	Mountpoint string `json:"mountpoint"`
+	FSType     string `json:"fs_type"`
+	TotalBytes uint64 `json:"total_bytes"`
+}
+
+type InventoryNIC struct {
+	Name      string   `json:"name"`
+	MAC       string   `json:"mac"`
+	Addresses []string `json:"addresses"`
+}
+
+type InventoryHardware struct {
+	Manufacturer string `json:"manufacturer"`
+	Model        string `json:"model"`
+	SerialNumber string `json:"serial_number"`
+	BIOSVendor   string `json:"bios_vendor"`
+	BIOSVersion  string `json:"bios_version"`
+}
+
+type InventoryUser struct {
+	Name     string `json:"name"`
+	Terminal string `json:"terminal"`
+	Host     string `json:"host"`
+}
+
+// InventoryItem is a single entry of an inventory, items are used to query and compare inventories.
+// Name identifies the item within its category, Value holds what is tracked for changes.
+type InventoryItem struct {
+	ClientID string `json:"client_id" db:"client_id"`
+	Category string `json:"category" db:"category"`
+	Name     string `json:"name" db:"name"`
+	Value    string `json:"value" db:"value"`
+}
+
+// Items returns the entries of the inventory, the client id is not set
+func (i *Inventory) Items() []InventoryItem {
+	var items []InventoryItem
+	add := func(category, name, value string) {
+		items = append(items, InventoryItem{Category: category, Name: name, Value: value})
+	}
+
+	for _, p := range i.Packages {
+		add(InventoryCategoryPackage, p.Name, p.Version)
+	}
+	for _, s := range i.Services {
+		add(InventoryCategoryService, s.Name, s.State)
+	}
+	for _, d := range i.Disks {
+		add(InventoryCategoryDisk, d.Mountpoint, d.Device)
+	}
+	for _, n := range i.NICs {
+		add(InventoryCategoryNIC, n.Name, n.MAC)
+	}
+	for _, u := range i.Users {
+		add(InventoryCategoryUser, u.Name, strings.TrimSpace(u.Terminal+" "+u.Host))
+	}
+	for _, h := range [][2]string{
+		{"manufacturer", i.Hardware.Manufacturer},
+		{"model", i.Hardware.Model},
+		{"serial_number", i.Hardware.SerialNumber},
+		{"bios_vendor", i.Hardware.BIOSVendor},
+		{"bios_version", i.Hardware.BIOSVersion},
+	} {
+		if h[1] != "" {
+			add(InventoryCategoryHardware, h[0], h[1])
+		}
+	}
+
+	return items
+}
+
+// FailedCategories returns the categories that failed to be collected
+func (i *Inventory) FailedCategories() map[string]bool {
+	failed := make(map[string]bool)
+	for _, e := range i.Errors {
+		if category, _, ok := strings.Cut(e, ": "); ok {
+			failed[category] = true
+		}
+	}
+	return failed
+}
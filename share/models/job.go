@@ -11,6 +11,7 @@ const (
 	JobStatusRunning    = "running"
 	JobStatusFailed     = "failed"
 	JobStatusUnknown    = "unknown"
+	JobStatusCancelled  = "cancelled"
 
 	ChannelStdout = "stdout"
 	ChannelStderr = "stderr"
@@ -36,6 +37,10 @@ type Job struct {
 	IsSudo       bool       `json:"is_sudo"`
 	IsScript     bool       `json:"is_script"`
 	StreamResult bool       `json:"stream_result"`
+	// UpdatesInstallation is set for jobs installing pending updates instead of running the command
+	UpdatesInstallation *UpdatesInstallation `json:"updates_installation,omitempty"`
+	// CreatedByGroups are the user groups of CreatedBy, the client applies its user policy to them
+	CreatedByGroups []string `json:"created_by_groups,omitempty"`
 }
 
 type JobResult struct {
@@ -64,6 +69,8 @@ type MultiJob struct {
 	Jobs        []*Job         `json:"jobs"`
 	IsSudo      bool           `json:"is_sudo"`
 	IsScript    bool           `json:"is_script"`
+
+	UpdatesInstallation *UpdatesInstallation `json:"updates_installation,omitempty"`
 }
 
 type MultiJobSummary struct {
@@ -19,4 +19,23 @@ type Measurement struct {
 	Mountpoints        string    `json:"mountpoints" db:"mountpoints"`
 	NetLan             *NetBytes `json:"net_lan" db:"net_lan"`
 	NetWan             *NetBytes `json:"net_wan" db:"net_wan"`
+
+	// Checks holds the results of custom checks finished since the previous measurement
+	Checks []*CheckResult `json:"checks,omitempty" db:"-"`
+}
+
+// Status codes of check results, they follow the exit codes of nagios plugins
+const (
+	CheckStatusOK       = 0
+	CheckStatusWarning  = 1
+	CheckStatusCritical = 2
+	CheckStatusUnknown  = 3
+)
+
+type CheckResult struct {
+	Name      string    `json:"name" db:"name"`
+	Timestamp time.Time `json:"timestamp" db:"timestamp"`
+	Value     *float64  `json:"value" db:"value"`
+	Status    int       `json:"status" db:"status"`
+	Output    string    `json:"output" db:"output"`
 }
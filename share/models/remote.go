@@ -23,6 +23,9 @@ import (
 //     local  192.168.0.1:3000
 //     remote google.com:80
 //   .../udp ->  udp protocol
+//   3000/socks5 ->
+//     local  0.0.0.0:3000
+//     remote given by the SOCKS5 clients
 
 const (
 	ZeroHost       = "0.0.0.0"
@@ -30,9 +33,10 @@ const (
 	ProtocolTCP    = "tcp"
 	ProtocolUDP    = "udp"
 	ProtocolTCPUDP = "tcp+udp"
+	ProtocolSOCKS5 = "socks5"
 )
 
-var protocolRe = regexp.MustCompile(`(.*)\/(tcp|udp|tcp\+udp)$`)
+var protocolRe = regexp.MustCompile(`(.*)\/(tcp|udp|tcp\+udp|socks5)$`)
 
 // TODO(m-terel): Remote should be only used for parsing command args and URL query params. Current Remote is kind of a Tunnel model. Refactor to use separate models for representation and business logic.
 type Remote struct {
@@ -51,6 +55,13 @@ type Remote struct {
 	HostHeader         string        `json:"host_header"`
 	AuthUser           string        `json:"auth_user"`
 	AuthPassword       string        `json:"auth_password"`
+	// RateLimitUp and RateLimitDown limit the bytes per second sent to and received from the remote, 0 is unlimited.
+	RateLimitUp   int64 `json:"rate_limit_up"`
+	RateLimitDown int64 `json:"rate_limit_down"`
+	// Owner is the user who created the tunnel, empty for tunnels requested by the client.
+	Owner string `json:"owner"`
+	// OwnerGroups are the user groups of the owner, the client applies its user policy to them when the tunnel is restored.
+	OwnerGroups []string `json:"owner_groups"`
 }
 
 func DecodeRemote(s string) (*Remote, error) {
@@ -61,6 +72,10 @@ func DecodeRemote(s string) (*Remote, error) {
 		protocol = matches[2]
 	}
 
+	if protocol == ProtocolSOCKS5 {
+		return decodeSOCKS5Remote(s)
+	}
+
 	parts := strings.Split(s, ":")
 	if len(parts) <= 0 || len(parts) >= 5 {
 		return nil, errors.New("Invalid remote")
@@ -100,6 +115,35 @@ func DecodeRemote(s string) (*Remote, error) {
 	return r, nil
 }
 
+// decodeSOCKS5Remote decodes the optional local address of a socks5 tunnel, it has no fixed remote
+func decodeSOCKS5Remote(s string) (*Remote, error) {
+	r := &Remote{
+		Protocol: ProtocolSOCKS5,
+	}
+	if s == "" {
+		return r, nil
+	}
+
+	parts := strings.Split(s, ":")
+	switch len(parts) {
+	case 1:
+		r.LocalHost = ZeroHost
+		r.LocalPort = parts[0]
+	case 2:
+		if !isHost(parts[0]) {
+			return nil, errors.New("Invalid host")
+		}
+		r.LocalHost = parts[0]
+		r.LocalPort = parts[1]
+	default:
+		return nil, errors.New("Invalid local address, socks5 tunnels don't have a remote")
+	}
+	if !isPort(r.LocalPort) {
+		return nil, errors.New("Invalid port")
+	}
+	return r, nil
+}
+
 var isPortRegExp = regexp.MustCompile(`^\d+$`)
 
 func isPort(s string) bool {
@@ -113,7 +157,10 @@ func isHost(s string) bool {
 
 // implement Stringer
 func (r Remote) String() string {
-	s := r.LocalHost + ":" + r.LocalPort + ":" + r.Remote()
+	s := r.LocalHost + ":" + r.LocalPort
+	if r.Protocol != ProtocolSOCKS5 {
+		s += ":" + r.Remote()
+	}
 	if r.Protocol != ProtocolTCP {
 		s += "/" + r.Protocol
 	}
@@ -123,7 +170,11 @@ func (r Remote) String() string {
 	return s
 }
 
+// Remote returns the destination of the tunnel, it's empty for socks5 tunnels
 func (r *Remote) Remote() string {
+	if r.Protocol == ProtocolSOCKS5 {
+		return ""
+	}
 	return net.JoinHostPort(r.RemoteHost, r.RemotePort)
 }
 
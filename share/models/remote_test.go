@@ -101,6 +101,22 @@ func TestDecodeRemote(t *testing.T) {
 			WantRemoteHost: "google.com",
 			WantRemotePort: "80",
 		},
+		{
+			Input:        "/socks5",
+			WantProtocol: ProtocolSOCKS5,
+		},
+		{
+			Input:         "3000/socks5",
+			WantProtocol:  ProtocolSOCKS5,
+			WantLocalHost: ZeroHost,
+			WantLocalPort: "3000",
+		},
+		{
+			Input:         "127.0.0.1:3000/socks5",
+			WantProtocol:  ProtocolSOCKS5,
+			WantLocalHost: LocalHost,
+			WantLocalPort: "3000",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -119,6 +135,13 @@ func TestDecodeRemote(t *testing.T) {
 	}
 }
 
+func TestDecodeRemoteInvalidSOCKS5(t *testing.T) {
+	for _, input := range []string{"3000:google.com:80/socks5", "localhost:http/socks5"} {
+		_, err := DecodeRemote(input)
+		assert.Error(t, err, input)
+	}
+}
+
 func TestIsProtocol(t *testing.T) {
 	testCases := []struct {
 		Protocol      string
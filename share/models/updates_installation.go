@@ -0,0 +1,63 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	UpdatesInstallationModeAll      = "all"
+	UpdatesInstallationModeSecurity = "security"
+	UpdatesInstallationModePackages = "packages"
+)
+
+// packageNameRegex matches package names of the supported package managers and KB article IDs of windows updates.
+// Package names are added to the installation scripts, so only safe characters are allowed.
+var packageNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._+:~-]*$`)
+
+// UpdatesInstallation describes which of the pending updates a client should install
+type UpdatesInstallation struct {
+	Mode     string   `json:"mode"`
+	Packages []string `json:"packages,omitempty"`
+	Reboot   bool     `json:"reboot"`
+}
+
+func (i UpdatesInstallation) Validate() error {
+	switch i.Mode {
+	case UpdatesInstallationModeAll, UpdatesInstallationModeSecurity:
+		if len(i.Packages) > 0 {
+			return fmt.Errorf("packages can only be used with mode %q", UpdatesInstallationModePackages)
+		}
+	case UpdatesInstallationModePackages:
+		if len(i.Packages) == 0 {
+			return errors.New("packages cannot be empty")
+		}
+		for _, p := range i.Packages {
+			if !packageNameRegex.MatchString(p) {
+				return fmt.Errorf("invalid package name %q", p)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid mode %q, expected one of %q, %q, %q", i.Mode, UpdatesInstallationModeAll, UpdatesInstallationModeSecurity, UpdatesInstallationModePackages)
+	}
+	return nil
+}
+
+// String returns a short description used as the command of the installation job
+func (i UpdatesInstallation) String() string {
+	var str string
+	switch i.Mode {
+	case UpdatesInstallationModeSecurity:
+		str = "install security updates"
+	case UpdatesInstallationModePackages:
+		str = "install updates of " + strings.Join(i.Packages, ", ")
+	default:
+		str = "install all updates"
+	}
+	if i.Reboot {
+		str += " and reboot"
+	}
+	return str
+}
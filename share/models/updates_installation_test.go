@@ -0,0 +1,71 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdatesInstallationValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		installation UpdatesInstallation
+		wantErr      string
+	}{
+		{
+			name:         "all",
+			installation: UpdatesInstallation{Mode: UpdatesInstallationModeAll, Reboot: true},
+		},
+		{
+			name:         "security",
+			installation: UpdatesInstallation{Mode: UpdatesInstallationModeSecurity},
+		},
+		{
+			name:         "packages",
+			installation: UpdatesInstallation{Mode: UpdatesInstallationModePackages, Packages: []string{"openssl", "libc6:amd64", "g++-12", "KB5018410"}},
+		},
+		{
+			name:         "invalid mode",
+			installation: UpdatesInstallation{Mode: "critical"},
+			wantErr:      `invalid mode "critical", expected one of "all", "security", "packages"`,
+		},
+		{
+			name:         "packages with mode all",
+			installation: UpdatesInstallation{Mode: UpdatesInstallationModeAll, Packages: []string{"openssl"}},
+			wantErr:      `packages can only be used with mode "packages"`,
+		},
+		{
+			name:         "no packages",
+			installation: UpdatesInstallation{Mode: UpdatesInstallationModePackages},
+			wantErr:      "packages cannot be empty",
+		},
+		{
+			name:         "option as package",
+			installation: UpdatesInstallation{Mode: UpdatesInstallationModePackages, Packages: []string{"-y"}},
+			wantErr:      `invalid package name "-y"`,
+		},
+		{
+			name:         "shell in package",
+			installation: UpdatesInstallation{Mode: UpdatesInstallationModePackages, Packages: []string{"curl;reboot"}},
+			wantErr:      `invalid package name "curl;reboot"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.installation.Validate()
+			if tc.wantErr != "" {
+				assert.EqualError(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUpdatesInstallationString(t *testing.T) {
+	assert.Equal(t, "install all updates", UpdatesInstallation{Mode: UpdatesInstallationModeAll}.String())
+	assert.Equal(t, "install security updates and reboot", UpdatesInstallation{Mode: UpdatesInstallationModeSecurity, Reboot: true}.String())
+	assert.Equal(t, "install updates of openssl, curl", UpdatesInstallation{Mode: UpdatesInstallationModePackages, Packages: []string{"openssl", "curl"}}.String())
+}
@@ -5,6 +5,13 @@ import (
 	"strings"
 )
 
+const (
+	DriverSQLite   = "sqlite3"
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+)
+
+// SQLConverter builds queries with ? placeholders, use sqlx Rebind before executing them on PostgreSQL
 type SQLConverter struct {
 	dbDriverName string
 }
@@ -53,10 +60,14 @@ func (c *SQLConverter) AddWhere(filterOptions []FilterOption, q string, params [
 					part = fmt.Sprintf("(%s OR %s IS NULL)", part, col)
 				} else if strings.Contains(val, "*") && filterOptions[i].Operator.Code() == "=" {
 					// Implement a SQL LIKE search triggered by a wildcard
-					if c.dbDriverName == "mysql" {
+					switch c.dbDriverName {
+					case DriverMySQL:
 						//MySQL needs the backslash escaped, that means double-backslash;  WHERE LOWER(id) LIKE 'op\%' escape "\\";
 						part = fmt.Sprintf("LOWER(%s) LIKE ? ESCAPE '\\\\'", col)
-					} else {
+					case DriverPostgres:
+						//PostgreSQL has no implicit cast to text for LOWER, e.g. on date columns
+						part = fmt.Sprintf("LOWER(CAST(%s AS TEXT)) LIKE ? ESCAPE '\\'", col)
+					default:
 						//SQLite needs a single backslash
 						part = fmt.Sprintf("LOWER(%s) LIKE ? ESCAPE '\\'", col)
 					}
@@ -135,3 +146,39 @@ func (c *SQLConverter) addLimitOffset(pagination *Pagination, q string, params [
 
 	return q, params
 }
+
+// DateTime returns an expression to compare or sort the date column or parameter by time.
+// SQLite stores dates as text, the formats may differ so they are normalized first.
+func (c *SQLConverter) DateTime(expr string) string {
+	switch c.dbDriverName {
+	case DriverMySQL, DriverPostgres:
+		return expr
+	default:
+		return fmt.Sprintf("DATETIME(%s)", expr)
+	}
+}
+
+// UnixTime returns an expression converting the date column to seconds since the epoch.
+func (c *SQLConverter) UnixTime(expr string) string {
+	switch c.dbDriverName {
+	case DriverMySQL:
+		return fmt.Sprintf("UNIX_TIMESTAMP(%s)", expr)
+	case DriverPostgres:
+		return fmt.Sprintf("EXTRACT(EPOCH FROM %s)", expr)
+	default:
+		return fmt.Sprintf("strftime('%%s', %s)", expr)
+	}
+}
+
+// Offset returns the clause to skip the given number of rows without limiting the remaining ones.
+func (c *SQLConverter) Offset() string {
+	switch c.dbDriverName {
+	case DriverMySQL:
+		// MySQL requires a limit, the docs recommend the largest possible value
+		return "LIMIT 18446744073709551615 OFFSET ?"
+	case DriverPostgres:
+		return "OFFSET ?"
+	default:
+		return "LIMIT -1 OFFSET ?"
+	}
+}
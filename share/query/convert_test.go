@@ -149,3 +149,50 @@ func TestConvertListOptionsToQuery(t *testing.T) {
 	}
 
 }
+
+func TestSQLConverterDialects(t *testing.T) {
+	testCases := []struct {
+		DbDriverName     string
+		ExpectedDateTime string
+		ExpectedUnixTime string
+		ExpectedOffset   string
+		ExpectedWildcard string
+	}{
+		{
+			DbDriverName:     query.DriverSQLite,
+			ExpectedDateTime: "DATETIME(started_at)",
+			ExpectedUnixTime: "strftime('%s', started_at)",
+			ExpectedOffset:   "LIMIT -1 OFFSET ?",
+			ExpectedWildcard: `SELECT * FROM res1 WHERE LOWER(started_at) LIKE ? ESCAPE '\'`,
+		},
+		{
+			DbDriverName:     query.DriverMySQL,
+			ExpectedDateTime: "started_at",
+			ExpectedUnixTime: "UNIX_TIMESTAMP(started_at)",
+			ExpectedOffset:   "LIMIT 18446744073709551615 OFFSET ?",
+			ExpectedWildcard: `SELECT * FROM res1 WHERE LOWER(started_at) LIKE ? ESCAPE '\\'`,
+		},
+		{
+			DbDriverName:     query.DriverPostgres,
+			ExpectedDateTime: "started_at",
+			ExpectedUnixTime: "EXTRACT(EPOCH FROM started_at)",
+			ExpectedOffset:   "OFFSET ?",
+			ExpectedWildcard: `SELECT * FROM res1 WHERE LOWER(CAST(started_at AS TEXT)) LIKE ? ESCAPE '\'`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.DbDriverName, func(t *testing.T) {
+			converter := query.NewSQLConverter(tc.DbDriverName)
+
+			assert.Equal(t, tc.ExpectedDateTime, converter.DateTime("started_at"))
+			assert.Equal(t, tc.ExpectedUnixTime, converter.UnixTime("started_at"))
+			assert.Equal(t, tc.ExpectedOffset, converter.Offset())
+
+			q, params := converter.AddWhere([]query.FilterOption{{Column: []string{"started_at"}, Values: []string{"2022-10*"}}}, "SELECT * FROM res1", nil)
+			assert.Equal(t, tc.ExpectedWildcard, q)
+			assert.Equal(t, []interface{}{"2022-10%"}, params)
+		})
+	}
+}
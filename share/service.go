@@ -40,6 +40,11 @@ func HandleServiceCommand(svc service.Service, command string) error {
 			return err
 		}
 		fmt.Println("Service stopped")
+	case "restart":
+		if err := svc.Restart(); err != nil {
+			return err
+		}
+		fmt.Println("Service restarted")
 	default:
 		return errors.New("Unknown service command")
 	}
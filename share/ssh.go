@@ -55,6 +55,7 @@ func HandleTCPStream(l *logger.Logger, connStats *ConnStats, src io.ReadWriteClo
 	connStats.Open()
 	l.Debugf("%s: Open", connStats)
 	s, r := Pipe(src, dst)
+	connStats.AddBytes(s, r)
 	connStats.Close()
 	l.Debugf("%s: Close (sent %s received %s)", connStats, sizestr.ToString(s), sizestr.ToString(r))
 }
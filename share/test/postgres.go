@@ -0,0 +1,59 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudradar-monitoring/rport/share/random"
+)
+
+// PostgresDSNEnv is the env var with the DSN of the PostgreSQL database used by integration tests
+const PostgresDSNEnv = "RPORT_TEST_POSTGRES_DSN"
+
+// PostgresDSN returns the DSN of the PostgreSQL database given by PostgresDSNEnv and skips the test if it's not set.
+// Each test gets its own schema that is dropped once the test finished, so tests of different packages can run
+// against the same database at the same time.
+func PostgresDSN(t *testing.T) string {
+	t.Helper()
+	dsn := os.Getenv(PostgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", PostgresDSNEnv)
+	}
+
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		var err error
+		dsn, err = pq.ParseURL(dsn)
+		require.NoError(t, err)
+	}
+
+	db, err := sqlx.Connect("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	schema := "rport_test_" + random.AlphaNum(16)
+	_, err = db.Exec(fmt.Sprintf("CREATE SCHEMA %s", schema))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := db.Exec(fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+		require.NoError(t, err)
+	})
+
+	return fmt.Sprintf("%s search_path=%s", dsn, schema)
+}
+
+// ForEachDatabase runs f for each supported database, the DSN is empty for sqlite. PostgreSQL is skipped if
+// PostgresDSNEnv is not set.
+func ForEachDatabase(t *testing.T, f func(t *testing.T, postgresDSN string)) {
+	t.Run("sqlite", func(t *testing.T) {
+		f(t, "")
+	})
+	t.Run("postgres", func(t *testing.T) {
+		f(t, PostgresDSN(t))
+	})
+}